@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var auditCmdName = "audit"
+
+// AuditCmd command
+var AuditCmd = cli.Command{
+	Name:  "audit",
+	Usage: "audit COMMAND",
+	Subcommands: []cli.Command{
+		auditList,
+	},
+}
+
+var auditList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List the mutating operations recorded on the current tenant",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "Lists only the operations done since a duration (for example 12h) or a date (2006-01-02 or RFC3339 format)",
+		},
+		cli.StringFlag{
+			Name:  "resource",
+			Usage: "Lists only the operations done on the resource with this name or id",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", auditCmdName, c.Command.Name, c.Args())
+
+		var since time.Time
+		if value := c.String("since"); value != "" {
+			var err error
+			since, err = parseSince(value)
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+			}
+		}
+		records, err := client.New().Audit.List(since, c.String("resource"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of audit records", false).Error())))
+		}
+		return clitools.SuccessResponse(records.GetRecords())
+	},
+}
+
+// parseSince converts a duration or a date to the time from which records are wanted
+func parseSince(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid value '%s' for --since: expecting a duration or a date", value)
+}
@@ -27,31 +27,25 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
-	"github.com/CS-SI/SafeScale/lib/server/cluster"
-	"github.com/CS-SI/SafeScale/lib/server/cluster/api"
-	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
-	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
-	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
-	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
 	"github.com/CS-SI/SafeScale/lib/server/install"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
-	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
-	"github.com/CS-SI/SafeScale/lib/utils/data"
-	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
 var (
 	clusterName string
 	// clusterServiceName *string
-	clusterInstance api.Cluster
+	clusterInstance *pb.Cluster
 )
 
 var clusterCommandName = "cluster"
@@ -76,6 +70,7 @@ var ClusterCommand = cli.Command{
 		clusterStopCommand,
 		clusterExpandCommand,
 		clusterShrinkCommand,
+		clusterTagCommand,
 		clusterDcosCommand,
 		clusterKubectlCommand,
 		clusterHelmCommand,
@@ -99,14 +94,14 @@ func extractClusterArgument(c *cli.Context) error {
 		}
 
 		var err error
-		clusterInstance, err = cluster.Load(concurrency.RootTask(), clusterName)
+		clusterInstance, err = client.New().Cluster.Inspect(clusterName, temporal.GetExecutionTimeout())
 		if err != nil {
-			if _, ok := err.(scerr.ErrNotFound); ok {
+			if status.Code(err) == codes.NotFound {
 				if !c.Command.HasName("create") {
 					return clitools.ExitOnErrorWithMessage(exitcode.NotFound, fmt.Sprintf("Cluster '%s' not found.\n", clusterName))
 				}
 			} else {
-				msg := fmt.Sprintf("failed to query for cluster '%s': %s\n", clusterName, err.Error())
+				msg := fmt.Sprintf("failed to query for cluster '%s': %s\n", clusterName, client.DecorateError(err, "inspection of cluster", false).Error())
 				return clitools.ExitOnRPC(msg)
 			}
 		} else {
@@ -125,19 +120,22 @@ var clusterListCommand = cli.Command{
 	Aliases: []string{"ls"},
 	Usage:   "List available clusters",
 
+	Flags: []cli.Flag{
+		selectorFlag,
+	},
+
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", clusterCommandName, c.Command.Name, c.Args())
-		list, err := cluster.List()
+		list, err := client.New().Cluster.List(c.String("selector"), temporal.GetExecutionTimeout())
 		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnRPC(fmt.Sprintf("failed to get cluster list: %v", err)))
+			return clitools.FailureResponse(clitools.ExitOnRPC(fmt.Sprintf("failed to get cluster list: %v", client.DecorateError(err, "list of clusters", false))))
 		}
 
 		var formatted []interface{}
-		for _, value := range list {
-			c := value.(api.Cluster)
+		for _, c := range list.GetClusters() {
 			converted, err := convertToMap(c)
 			if err != nil {
-				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, fmt.Sprintf("failed to extract data about cluster '%s'", c.GetIdentity(concurrency.RootTask()).Name)))
+				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, fmt.Sprintf("failed to extract data about cluster '%s'", c.GetIdentity().GetName())))
 			}
 			formatted = append(formatted, formatClusterConfig(converted, false))
 		}
@@ -162,12 +160,21 @@ func formatClusterConfig(value interface{}, detailed bool) map[string]interface{
 	return core
 }
 
+// clusterTagCommand handles 'safescale cluster tag CLUSTERNAME key=value... [--unset key]'
+var clusterTagCommand = tagCommand(clusterCommandName, "cluster", func(clt client.Client) tagSetter { return clt.Cluster })
+
 // clusterInspectCmd handles 'deploy cluster <clustername> inspect'
 var clusterInspectCommand = cli.Command{
 	Name:      "inspect",
 	Aliases:   []string{"show", "get"},
-	Usage:     "inspect CLUSTERNAME",
+	Usage:     "inspect [--show-password] CLUSTERNAME",
 	ArgsUsage: "CLUSTERNAME",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "show-password",
+			Usage: "Displays also the password of the administrator account (needs the permission on ClusterService/GetAdminPassword)",
+		},
+	},
 	// 	Help: &cli.HelpContent{
 	// 		Usage: `
 	// Usage: {{.ProgName}} [options] cluster <clustername> inspect`,
@@ -185,6 +192,14 @@ var clusterInspectCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
 		}
+		if c.Bool("show-password") {
+			adminPassword, err := client.New().Cluster.GetAdminPassword(clusterName, temporal.GetExecutionTimeout())
+			if err != nil {
+				msg := fmt.Sprintf("failed to get admin password of cluster '%s': %s", clusterName, client.DecorateError(err, "admin password of cluster", false).Error())
+				return clitools.FailureResponse(clitools.ExitOnRPC(msg))
+			}
+			clusterConfig["admin_password"] = adminPassword.GetAdminPassword()
+		}
 		return clitools.SuccessResponse(clusterConfig)
 	},
 }
@@ -202,123 +217,75 @@ func outputClusterConfig() (map[string]interface{}, error) {
 
 // convertToMap converts clusterInstance to its equivalent in map[string]interface{},
 // with fields converted to string and used as keys
-func convertToMap(c api.Cluster) (map[string]interface{}, error) {
-	identity := c.GetIdentity(concurrency.RootTask())
+func convertToMap(c *pb.Cluster) (map[string]interface{}, error) {
+	identity := c.GetIdentity()
 
 	result := map[string]interface{}{
-		"name":             identity.Name,
-		"flavor":           identity.Flavor,
-		"flavor_label":     identity.Flavor.String(),
-		"complexity":       identity.Complexity,
-		"complexity_label": identity.Complexity.String(),
-		"admin_login":      "cladm",
-		"admin_password":   identity.AdminPassword,
-		"keypair":          identity.Keypair,
+		"name":             identity.GetName(),
+		"flavor":           identity.GetFlavor(),
+		"flavor_label":     identity.GetFlavorLabel(),
+		"complexity":       identity.GetComplexity(),
+		"complexity_label": identity.GetComplexityLabel(),
+		"admin_login":      identity.GetAdminLogin(),
+		"tenant":           c.GetTenant(),
 	}
 
-	properties := c.GetProperties(concurrency.RootTask())
-	err := properties.LockForRead(property.CompositeV1).ThenUse(func(clonable data.Clonable) error {
-		result["tenant"] = clonable.(*clusterpropsv1.Composite).Tenants[0]
-		return nil
-	})
-	if err != nil {
-		return nil, err
+	netCfg := c.GetNetwork()
+	result["network_id"] = netCfg.GetNetworkId()
+	result["cidr"] = netCfg.GetCidr()
+	result["default_route_ip"] = netCfg.GetDefaultRouteIp()
+	result["gateway_ip"] = netCfg.GetDefaultRouteIp() // legacy ...
+	result["primary_gateway_ip"] = netCfg.GetGatewayIp()
+	result["endpoint_ip"] = netCfg.GetEndpointIp()
+	result["primary_public_ip"] = netCfg.GetEndpointIp()
+	if netCfg.GetSecondaryGatewayIp() != "" {
+		result["secondary_gateway_ip"] = netCfg.GetSecondaryGatewayIp()
+		result["secondary_public_ip"] = netCfg.GetSecondaryPublicIp()
+		result["public_ip"] = netCfg.GetEndpointIp() // legacy ...
 	}
 
-	netCfg, err := c.GetNetworkConfig(concurrency.RootTask())
-	if err != nil {
-		return nil, err
-	}
-	result["network_id"] = netCfg.NetworkID
-	result["cidr"] = netCfg.CIDR
-	result["default_route_ip"] = netCfg.DefaultRouteIP
-	result["gateway_ip"] = netCfg.DefaultRouteIP // legacy ...
-	result["primary_gateway_ip"] = netCfg.GatewayIP
-	result["endpoint_ip"] = netCfg.EndpointIP
-	result["primary_public_ip"] = netCfg.EndpointIP
-	if netCfg.SecondaryGatewayIP != "" {
-		result["secondary_gateway_ip"] = netCfg.SecondaryGatewayIP
-		result["secondary_public_ip"] = netCfg.SecondaryPublicIP
-		result["public_ip"] = netCfg.EndpointIP // legacy ...
-	}
-	if !properties.Lookup(property.DefaultsV2) {
-		err = properties.LockForRead(property.DefaultsV1).ThenUse(func(clonable data.Clonable) error {
-			defaultsV1 := clonable.(*clusterpropsv1.Defaults)
-			result["defaults"] = map[string]interface{}{
-				"image":  defaultsV1.Image,
-				"master": defaultsV1.MasterSizing,
-				"node":   defaultsV1.NodeSizing,
-			}
-			return nil
-		})
-	} else {
-		err = properties.LockForRead(property.DefaultsV2).ThenUse(func(clonable data.Clonable) error {
-			defaultsV2 := clonable.(*clusterpropsv2.Defaults)
-			result["defaults"] = map[string]interface{}{
-				"image":   defaultsV2.Image,
-				"gateway": defaultsV2.GatewaySizing,
-				"master":  defaultsV2.MasterSizing,
-				"node":    defaultsV2.NodeSizing,
-			}
-			return nil
-		})
+	defaults := c.GetDefaults()
+	result["defaults"] = map[string]interface{}{
+		"image":   defaults.GetImage(),
+		"gateway": defaults.GetGatewaySizing(),
+		"master":  defaults.GetMasterSizing(),
+		"node":    defaults.GetNodeSizing(),
 	}
-	if err != nil {
-		return nil, err
+
+	result["nodes"] = map[string]interface{}{
+		"masters": c.GetMasters(),
+		"nodes":   c.GetNodes(),
 	}
 
-	err = properties.LockForRead(property.NodesV1).ThenUse(func(clonable data.Clonable) error {
-		nodesV1 := clonable.(*clusterpropsv1.Nodes)
-		result["nodes"] = map[string]interface{}{
-			"masters": nodesV1.Masters,
-			"nodes":   nodesV1.PrivateNodes,
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
+	disabled := map[string]struct{}{}
+	for _, v := range c.GetDisabledFeatures() {
+		disabled[v] = struct{}{}
 	}
-	err = properties.LockForRead(property.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
-		result["features"] = clonable.(*clusterpropsv1.Features)
-		return nil
-	})
-	if err != nil {
-		return nil, err
+	result["features"] = map[string]interface{}{
+		"installed": c.GetInstalledFeatures(),
+		"disabled":  disabled,
 	}
 
-	err = properties.LockForRead(property.StateV1).ThenUse(func(clonable data.Clonable) error {
-		state := clonable.(*clusterpropsv1.State).State
-		result["last_state"] = state
-		result["last_state_label"] = state.String()
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-	result["admin_login"] = "cladm"
+	result["last_state"] = c.GetState()
+	result["last_state_label"] = c.GetStateLabel()
 
 	// Add information not directly in cluster GetConfig()
 	//TODO: replace use of !Disabled["remotedesktop"] with use of Installed["remotedesktop"] (not yet implemented)
-	if _, ok := result["features"].(*clusterpropsv1.Features).Disabled["remotedesktop"]; !ok {
+	if _, ok := disabled["remotedesktop"]; !ok {
 		remoteDesktops := map[string][]string{}
-		clientHost := client.New().Host
-		for _, id := range c.ListMasterIDs(concurrency.RootTask()) {
-			host, err := clientHost.Inspect(id, temporal.GetExecutionTimeout())
-			if err != nil {
-				return nil, err
-			}
+		for _, master := range c.GetMasters() {
 			urlFmt := "https://%s/_platform/remotedesktop/%s/"
-			urls := []string{fmt.Sprintf(urlFmt, netCfg.EndpointIP, host.Name)}
-			if netCfg.SecondaryPublicIP != "" {
+			urls := []string{fmt.Sprintf(urlFmt, netCfg.GetEndpointIp(), master.GetName())}
+			if netCfg.GetSecondaryPublicIp() != "" {
 				// VPL: no public VIP IP yet, so don't repeat primary gateway public IP
 				// urls = append(urls, fmt.Sprintf(+urlFmt, netCfg.PrimaryPublicIP, host.Name))
-				urls = append(urls, fmt.Sprintf(urlFmt, netCfg.SecondaryPublicIP, host.Name))
+				urls = append(urls, fmt.Sprintf(urlFmt, netCfg.GetSecondaryPublicIp(), master.GetName()))
 			}
-			remoteDesktops[host.Name] = urls
+			remoteDesktops[master.GetName()] = urls
 		}
 		result["remote_desktop"] = remoteDesktops
 	} else {
-		result["remote_desktop"] = fmt.Sprintf("Remote Desktop not installed. To install it, execute 'safescale deploy platform add-feature %s remotedesktop'.", clusterName)
+		result["remote_desktop"] = fmt.Sprintf("Remote Desktop not installed. To install it, execute 'safescale deploy platform add-feature %s remotedesktop'.", identity.GetName())
 	}
 
 	return result, nil
@@ -332,6 +299,9 @@ var clusterCreateCommand = cli.Command{
 	ArgsUsage: "CLUSTERNAME",
 
 	Flags: []cli.Flag{
+		progressFlag,
+		asyncFlag,
+		tagFlag,
 		cli.StringFlag{
 			Name:  "complexity, C",
 			Value: "Small",
@@ -419,6 +389,9 @@ var clusterCreateCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
+		if err := checkAsyncOptions(c); err != nil {
+			return clitools.FailureResponse(err)
+		}
 
 		complexityStr := c.String("complexity")
 		clusterComplexity, err := complexity.Parse(complexityStr)
@@ -439,10 +412,6 @@ var clusterCreateCommand = cli.Command{
 		cidr := c.String("cidr")
 
 		disable := c.StringSlice("disable")
-		disableFeatures := map[string]struct{}{}
-		for _, v := range disable {
-			disableFeatures[strings.ToLower(v)] = struct{}{}
-		}
 
 		los := c.String("os")
 		if clusterFlavor == flavor.DCOS {
@@ -505,27 +474,35 @@ var clusterCreateCommand = cli.Command{
 				mastersDef = gatewaysDef         // ... nor for masters
 			}
 		}
-		clusterInstance, err := cluster.Create(concurrency.RootTask(), control.Request{
-			Name:                    clusterName,
-			Complexity:              clusterComplexity,
-			CIDR:                    cidr,
-			Flavor:                  clusterFlavor,
-			KeepOnFailure:           keep,
-			GatewaysDef:             gatewaysDef,
-			MastersDef:              mastersDef,
-			NodesDef:                nodesDef,
-			DisabledDefaultFeatures: disableFeatures,
-		})
+		tags, err := tagsFromCLI(c)
 		if err != nil {
-			if clusterInstance != nil {
-				cluDel := clusterInstance.Delete(concurrency.RootTask())
-				if cluDel != nil {
-					logrus.Warnf("Error deleting cluster instance: %s", cluDel)
-				}
-			}
-			msg := fmt.Sprintf("failed to create cluster: %s", err.Error())
+			return clitools.FailureResponse(err)
+		}
+		clusterDef := pb.ClusterDefinition{
+			Name:             clusterName,
+			Complexity:       int32(clusterComplexity),
+			Cidr:             cidr,
+			Flavor:           int32(clusterFlavor),
+			KeepOnFailure:    keep,
+			DisabledFeatures: disable,
+			Gateways:         gatewaysDef,
+			Masters:          mastersDef,
+			Nodes:            nodesDef,
+			Tags:             tags,
+		}
+		var clusterInstance *pb.Cluster
+		if c.Bool("progress") {
+			clusterInstance, err = client.New().Cluster.CreateWithProgress(clusterDef, temporal.GetLongOperationTimeout(), printProgress)
+		} else {
+			clusterInstance, err = clientFor(c).Cluster.Create(clusterDef, temporal.GetLongOperationTimeout())
+		}
+		if err != nil {
+			msg := fmt.Sprintf("failed to create cluster: %s", client.DecorateError(err, "creation of cluster", true).Error())
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 		}
+		if c.Bool("async") {
+			return jobSubmittedResponse()
+		}
 		if clusterInstance == nil {
 			msg := "failed to create cluster: unknown reason"
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
@@ -569,6 +546,7 @@ var clusterDeleteCommand = cli.Command{
 		cli.BoolFlag{
 			Name: "force, f",
 		},
+		asyncFlag,
 	},
 
 	Action: func(c *cli.Context) error {
@@ -587,9 +565,12 @@ var clusterDeleteCommand = cli.Command{
 			logrus.Println("'-f,--force' does nothing yet")
 		}
 
-		err = clusterInstance.Delete(concurrency.RootTask())
+		err = clientFor(c).Cluster.Delete(clusterName, temporal.GetLongOperationTimeout())
 		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "deletion of cluster", true).Error()))
+		}
+		if c.Bool("async") {
+			return jobSubmittedResponse()
 		}
 		return clitools.SuccessResponse(nil)
 	},
@@ -615,9 +596,9 @@ var clusterStopCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		err = clusterInstance.Stop(concurrency.RootTask())
+		err = client.New().Cluster.Stop(clusterName, temporal.GetLongOperationTimeout())
 		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "stop of cluster", true).Error()))
 		}
 		return clitools.SuccessResponse(nil)
 	},
@@ -644,9 +625,9 @@ var clusterStartCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		err = clusterInstance.Start(concurrency.RootTask())
+		err = client.New().Cluster.Start(clusterName, temporal.GetLongOperationTimeout())
 		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "start of cluster", true).Error()))
 		}
 		return clitools.SuccessResponse(nil)
 	},
@@ -670,15 +651,15 @@ var clusterStateCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		state, err := clusterInstance.GetState(concurrency.RootTask())
+		state, err := client.New().Cluster.State(clusterName, temporal.GetExecutionTimeout())
 		if err != nil {
-			msg := fmt.Sprintf("failed to get cluster state: %s", err.Error())
+			msg := fmt.Sprintf("failed to get cluster state: %s", client.DecorateError(err, "state of cluster", false).Error())
 			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 		}
 		return clitools.SuccessResponse(map[string]interface{}{
 			"Name":       clusterName,
-			"State":      state,
-			"StateLabel": state.String(),
+			"State":      state.GetState(),
+			"StateLabel": state.GetStateLabel(),
 		})
 	},
 }
@@ -709,6 +690,7 @@ var clusterExpandCommand = cli.Command{
 	// 	},
 
 	Flags: []cli.Flag{
+		asyncFlag,
 		cli.UintFlag{
 			Name:  "count, n",
 			Usage: "Define the number of nodes wanted (default: 1)",
@@ -790,9 +772,16 @@ var clusterExpandCommand = cli.Command{
 			}
 		}
 
-		hosts, err := clusterInstance.AddNodes(concurrency.RootTask(), count, nodesDef)
+		nodes, err := clientFor(c).Cluster.Expand(clusterName, count, nodesDef, temporal.GetLongOperationTimeout())
 		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "expansion of cluster", true).Error()))
+		}
+		if c.Bool("async") {
+			return jobSubmittedResponse()
+		}
+		var hosts []string
+		for _, n := range nodes.GetNodes() {
+			hosts = append(hosts, n.GetId())
 		}
 		return clitools.SuccessResponse(hosts)
 	},
@@ -826,6 +815,7 @@ var clusterShrinkCommand = cli.Command{
 			Name:  "assume-yes, yes, y",
 			Usage: "Don't ask deletion confirmation",
 		},
+		asyncFlag,
 	},
 
 	Action: func(c *cli.Context) error {
@@ -842,10 +832,7 @@ var clusterShrinkCommand = cli.Command{
 		if count > 1 {
 			countS = "s"
 		}
-		present, err := clusterInstance.CountNodes(concurrency.RootTask())
-		if err != nil {
-			return clitools.FailureResponse(err)
-		}
+		present := uint(len(clusterInstance.GetNodes()))
 		if count > present {
 			msg := fmt.Sprintf("cannot delete %d node%s, the cluster contains only %d of them", count, countS, present)
 			return clitools.FailureResponse(clitools.ExitOnInvalidOption(msg))
@@ -859,19 +846,12 @@ var clusterShrinkCommand = cli.Command{
 		}
 
 		// fmt.Printf("Deleting %d node%s from Cluster '%s' (this may take a while)...\n", count, countS, clusterName)
-		var msgs []string
-		availableMaster, err := clusterInstance.FindAvailableMaster(concurrency.RootTask())
+		_, err = clientFor(c).Cluster.Shrink(clusterName, int(count), temporal.GetLongOperationTimeout())
 		if err != nil {
-			return clitools.FailureResponse(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "shrink of cluster", true).Error()))
 		}
-		for i := uint(0); i < count; i++ {
-			err := clusterInstance.DeleteLastNode(concurrency.RootTask(), availableMaster)
-			if err != nil {
-				msgs = append(msgs, fmt.Sprintf("failed to delete node #%d: %s", i+1, err.Error()))
-			}
-		}
-		if len(msgs) > 0 {
-			return clitools.FailureResponse(clitools.ExitOnRPC(strings.Join(msgs, "\n")))
+		if c.Bool("async") {
+			return jobSubmittedResponse()
 		}
 		return clitools.SuccessResponse(nil)
 	},
@@ -898,9 +878,9 @@ var clusterDcosCommand = cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		identity := clusterInstance.GetIdentity(concurrency.RootTask())
-		if identity.Flavor != flavor.DCOS {
-			msg := fmt.Sprintf("Can't call dcos on this cluster, its flavor isn't DCOS (%s).\n", identity.Flavor.String())
+		identity := clusterInstance.GetIdentity()
+		if flavor.Enum(identity.GetFlavor()) != flavor.DCOS {
+			msg := fmt.Sprintf("Can't call dcos on this cluster, its flavor isn't DCOS (%s).\n", identity.GetFlavorLabel())
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotApplicable, msg))
 		}
 
@@ -1123,9 +1103,9 @@ var clusterRunCommand = cli.Command{
 
 func executeCommand(command string, files *RemoteFilesHandler, outs outputs.Enum) error {
 	logrus.Debugf("command=[%s]", command)
-	master, err := clusterInstance.FindAvailableMaster(concurrency.RootTask())
+	master, err := findAvailableMaster()
 	if err != nil {
-		msg := fmt.Sprintf("No masters found available for the cluster '%s': %v", clusterInstance.GetIdentity(concurrency.RootTask()).Name, err.Error())
+		msg := fmt.Sprintf("No masters found available for the cluster '%s': %v", clusterName, err.Error())
 		return clitools.ExitOnErrorWithMessage(exitcode.RPC, msg)
 	}
 
@@ -1158,6 +1138,26 @@ func executeCommand(command string, files *RemoteFilesHandler, outs outputs.Enum
 	return nil
 }
 
+// findAvailableMaster returns the ID of the first started master of the cluster
+func findAvailableMaster() (string, error) {
+	masters, err := client.New().Cluster.ListMasters(clusterName, temporal.GetExecutionTimeout())
+	if err != nil {
+		return "", client.DecorateError(err, "list of cluster masters", false)
+	}
+	hostClt := client.New().Host
+	for _, master := range masters.GetNodes() {
+		hostStatus, err := hostClt.Status(master.GetId(), temporal.GetExecutionTimeout())
+		if err != nil {
+			logrus.Warnf("failed to get status of master '%s': %v", master.GetName(), err)
+			continue
+		}
+		if hostStatus.GetStatus() == pb.HostState_STARTED.String() {
+			return master.GetId(), nil
+		}
+	}
+	return "", fmt.Errorf("no master started")
+}
+
 // clusterCheckFeaturesCommand handles 'safescale cluster <cluster name or id> list-features'
 var clusterListFeaturesCommand = cli.Command{
 	Name:      "list-features",
@@ -1211,16 +1211,7 @@ var clusterAddFeatureCommand = cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		feature, err := install.NewFeature(concurrency.RootTask(), featureName)
-		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
-		}
-		if feature == nil {
-			msg := fmt.Sprintf("failed to find a feature named '%s'.\n", featureName)
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotFound, msg))
-		}
-
-		values := install.Variables{}
+		values := map[string]string{}
 		params := c.StringSlice("param")
 		for _, k := range params {
 			res := strings.Split(k, "=")
@@ -1229,22 +1220,15 @@ var clusterAddFeatureCommand = cli.Command{
 			}
 		}
 
-		settings := install.Settings{}
-		settings.SkipProxy = c.Bool("skip-proxy")
-
-		target, err := install.NewClusterTarget(concurrency.RootTask(), clusterInstance)
-		if err != nil {
-			return clitools.FailureResponse(err)
-		}
-		results, err := feature.Add(target, values, settings)
+		results, err := client.New().Cluster.AddFeature(clusterName, featureName, values, c.Bool("skip-proxy"), temporal.GetLongOperationTimeout())
 		if err != nil {
-			msg := fmt.Sprintf("error installing feature '%s' on cluster '%s': %s\n", featureName, clusterName, err.Error())
+			msg := fmt.Sprintf("error installing feature '%s' on cluster '%s': %s\n", featureName, clusterName, client.DecorateError(err, "installation of feature", true).Error())
 			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 		}
-		if !results.Successful() {
+		if !results.GetSuccess() {
 			msg := fmt.Sprintf("failed to install feature '%s' on cluster '%s'", featureName, clusterName)
 			if Debug || Verbose {
-				msg += fmt.Sprintf(":\n%s", results.AllErrorMessages())
+				msg += fmt.Sprintf(":\n%s", results.GetOutput())
 			}
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 		}
@@ -1274,17 +1258,7 @@ var clusterCheckFeatureCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		feature, err := install.NewFeature(concurrency.RootTask(), featureName)
-		if err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err.Error())
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
-		}
-		if feature == nil {
-			msg := fmt.Sprintf("failed to find a feature named '%s'.\n", featureName)
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotFound, msg))
-		}
-
-		values := install.Variables{}
+		values := map[string]string{}
 		params := c.StringSlice("param")
 		for _, k := range params {
 			res := strings.Split(k, "=")
@@ -1293,22 +1267,16 @@ var clusterCheckFeatureCommand = cli.Command{
 			}
 		}
 
-		settings := install.Settings{}
-
-		target, err := install.NewClusterTarget(concurrency.RootTask(), clusterInstance)
-		if err != nil {
-			return clitools.FailureResponse(err)
-		}
-		results, err := feature.Check(target, values, settings)
+		results, err := client.New().Cluster.CheckFeature(clusterName, featureName, values, temporal.GetExecutionTimeout())
 		if err != nil {
-			msg := fmt.Sprintf("error checking if feature '%s' is installed on '%s': %s\n", featureName, clusterName, err.Error())
+			msg := fmt.Sprintf("error checking if feature '%s' is installed on '%s': %s\n", featureName, clusterName, client.DecorateError(err, "check of feature", false).Error())
 			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 		}
 
-		if !results.Successful() {
+		if !results.GetSuccess() {
 			msg := fmt.Sprintf("Feature '%s' not found on cluster '%s'", featureName, clusterName)
 			if Verbose || Debug {
-				msg += fmt.Sprintf(":\n%s", results.AllErrorMessages())
+				msg += fmt.Sprintf(":\n%s", results.GetOutput())
 			}
 			return clitools.FailureResponse(clitools.ExitOnNotFound(msg))
 		}
@@ -1339,16 +1307,7 @@ var clusterDeleteFeatureCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		feature, err := install.NewFeature(concurrency.RootTask(), featureName)
-		if err != nil {
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
-		}
-		if feature == nil {
-			msg := fmt.Sprintf("failed to find a feature named '%s'.\n", featureName)
-			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.NotFound, msg))
-		}
-
-		values := install.Variables{}
+		values := map[string]string{}
 		params := c.StringSlice("param")
 		for _, k := range params {
 			res := strings.Split(k, "=")
@@ -1357,24 +1316,15 @@ var clusterDeleteFeatureCommand = cli.Command{
 			}
 		}
 
-		settings := install.Settings{}
-		// TODO: Reverse proxy rules are not yet purged when feature is removed, but current code
-		// will try to apply them... Quick fix: Setting SkipProxy to true prevent this
-		settings.SkipProxy = true
-
-		target, err := install.NewClusterTarget(concurrency.RootTask(), clusterInstance)
+		results, err := client.New().Cluster.DeleteFeature(clusterName, featureName, values, temporal.GetLongOperationTimeout())
 		if err != nil {
-			return clitools.FailureResponse(err)
-		}
-		results, err := feature.Remove(target, values, settings)
-		if err != nil {
-			msg := fmt.Sprintf("error uninstalling feature '%s' on '%s': %s\n", featureName, clusterName, err.Error())
+			msg := fmt.Sprintf("error uninstalling feature '%s' on '%s': %s\n", featureName, clusterName, client.DecorateError(err, "removal of feature", true).Error())
 			return clitools.FailureResponse(clitools.ExitOnRPC(msg))
 		}
-		if !results.Successful() {
+		if !results.GetSuccess() {
 			msg := fmt.Sprintf("failed to delete feature '%s' from cluster '%s'", featureName, clusterName)
 			if Verbose || Debug {
-				msg += fmt.Sprintf(":\n%s\n", results.AllErrorMessages())
+				msg += fmt.Sprintf(":\n%s\n", results.GetOutput())
 			}
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
 		}
@@ -1434,20 +1384,15 @@ var clusterNodeListCommand = cli.Command{
 		if err != nil {
 			return clitools.FailureResponse(err)
 		}
-		hostClt := client.New().Host
-		var formatted []map[string]interface{}
+		list, err := client.New().Cluster.ListNodes(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "list of cluster nodes", false).Error()))
+		}
 
-		list := clusterInstance.ListNodeIDs(concurrency.RootTask())
-		for _, i := range list {
-			host, err := hostClt.Inspect(i, temporal.GetExecutionTimeout())
-			if err != nil {
-				msg := fmt.Sprintf("failed to get data for node '%s': %s. Ignoring.", i, err.Error())
-				//fmt.Println(msg)
-				logrus.Warnln(msg)
-				continue
-			}
+		var formatted []map[string]interface{}
+		for _, node := range list.GetNodes() {
 			formatted = append(formatted, map[string]interface{}{
-				"name": host.Name,
+				"name": node.GetName(),
 			})
 		}
 		return clitools.SuccessResponse(formatted)
@@ -1649,21 +1594,16 @@ var clusterMasterListCommand = cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		hostClt := client.New().Host
-		var formatted []map[string]interface{}
+		list, err := client.New().Cluster.ListMasters(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "list of cluster masters", false).Error()))
+		}
 
-		list := clusterInstance.ListMasterIDs(concurrency.RootTask())
-		for _, i := range list {
-			host, err := hostClt.Inspect(i, temporal.GetExecutionTimeout())
-			if err != nil {
-				msg := fmt.Sprintf("failed to get data for master '%s': %s. Ignoring.", i, err.Error())
-				fmt.Println(msg)
-				logrus.Warnln(msg)
-				continue
-			}
+		var formatted []map[string]interface{}
+		for _, master := range list.GetNodes() {
 			formatted = append(formatted, map[string]interface{}{
-				"name": host.Name,
-				"id":   host.Id,
+				"name": master.GetName(),
+				"id":   master.GetId(),
 			})
 		}
 		return clitools.SuccessResponse(formatted)
@@ -18,11 +18,17 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
@@ -70,3 +76,124 @@ func extractHostArgument(c *cli.Context, hostnamePos int) error {
 
 	return nil
 }
+
+// progressFlag asks to display the progress of a creation on stderr
+var progressFlag = cli.BoolFlag{
+	Name:  "progress",
+	Usage: "Displays the progress of the creation on stderr",
+}
+
+// asyncFlag asks safescaled to run the operation as a background job
+var asyncFlag = cli.BoolFlag{
+	Name:  "async",
+	Usage: "Runs the operation as a background job of safescaled and displays at once the uuid of the job (see 'safescale job wait')",
+}
+
+// clientFor returns the safescale client to use, running the mutating requests as background jobs if --async is set
+func clientFor(c *cli.Context) client.Client {
+	if c.Bool("async") {
+		return client.Async(client.New())
+	}
+	return client.New()
+}
+
+// jobSubmittedResponse displays the uuid of the job of the last request run asynchronously
+func jobSubmittedResponse() error {
+	return clitools.SuccessResponse(map[string]string{"job": srvutils.GetUUID()})
+}
+
+// checkAsyncOptions refuses --progress with --async, the progress of a background job being not streamed
+func checkAsyncOptions(c *cli.Context) error {
+	if c.Bool("async") && c.Bool("progress") {
+		return clitools.ExitOnInvalidOption("--progress cannot be used with --async")
+	}
+	return nil
+}
+
+// printProgress displays a progress event sent by safescaled on stderr, keeping stdout for the JSON response
+func printProgress(event *pb.ProgressEvent) {
+	when := event.GetTime()
+	if t, err := time.Parse(time.RFC3339Nano, when); err == nil {
+		when = t.Local().Format("15:04:05")
+	}
+	var line string
+	switch event.GetKind() {
+	case srvutils.ProgressPhaseStarted:
+		line = fmt.Sprintf("%s: %s started", event.GetTarget(), event.GetPhase())
+	case srvutils.ProgressPhaseFinished:
+		line = fmt.Sprintf("%s: %s done", event.GetTarget(), event.GetPhase())
+	case srvutils.ProgressPhaseFailed:
+		line = fmt.Sprintf("%s: %s failed: %s", event.GetTarget(), event.GetPhase(), event.GetMessage())
+	case srvutils.ProgressRetry:
+		line = fmt.Sprintf("%s: %s, attempt #%d failed, retrying: %s", event.GetTarget(), event.GetPhase(), event.GetAttempt(), event.GetMessage())
+	case srvutils.ProgressSSHReady:
+		line = fmt.Sprintf("%s: SSH ready after %s", event.GetTarget(), event.GetPhase())
+	case srvutils.ProgressStepResult:
+		line = fmt.Sprintf("%s: step %s: %s", event.GetTarget(), event.GetPhase(), event.GetMessage())
+	default:
+		line = fmt.Sprintf("%s: %s %s %s", event.GetTarget(), event.GetKind(), event.GetPhase(), event.GetMessage())
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "[%s] %s\n", when, line)
+}
+
+// tagFlag defines the tags of the resource to create
+var tagFlag = cli.StringSliceFlag{
+	Name:  "tag",
+	Usage: "Tags the resource with key=value (can be used several times)",
+}
+
+// selectorFlag filters the resources listed on their tags
+var selectorFlag = cli.StringFlag{
+	Name:  "selector, l",
+	Usage: "Lists only the resources whose tags match the selector, like 'env=prod,team=geo' (also 'key!=value', 'key' and '!key')",
+}
+
+// tagsFromCLI returns the tags defined with --tag
+func tagsFromCLI(c *cli.Context) (map[string]string, error) {
+	tags, err := resources.ParseTags(c.StringSlice("tag"))
+	if err != nil {
+		return nil, clitools.ExitOnInvalidOption(err.Error())
+	}
+	return tags, nil
+}
+
+// tagSetter is satisfied by the parts of the safescale client handling resources with tags
+type tagSetter interface {
+	SetTags(name string, set map[string]string, unset []string, timeout time.Duration) (*pb.Tags, error)
+}
+
+// tagCommand returns the command 'tag' of the commands of kind, setting and removing tags of a resource
+// with the part of the client returned by target
+func tagCommand(cmdName string, kind string, target func(client.Client) tagSetter) cli.Command {
+	return cli.Command{
+		Name:      "tag",
+		Usage:     fmt.Sprintf("Sets and removes tags of a %s", kind),
+		ArgsUsage: fmt.Sprintf("<%s_name> [key=value...]", utils.Capitalize(kind)),
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "unset",
+				Usage: "Removes the tag key (can be used several times)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", cmdName, c.Command.Name, c.Args())
+			if c.NArg() < 1 {
+				_ = cli.ShowSubcommandHelp(c)
+				return clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("Missing mandatory argument <%s_name>.", utils.Capitalize(kind))))
+			}
+			set, err := resources.ParseTags(c.Args().Tail())
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+			}
+			if len(set) == 0 && len(c.StringSlice("unset")) == 0 {
+				_ = cli.ShowSubcommandHelp(c)
+				return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing tags to set or to unset."))
+			}
+			tags, err := target(client.New()).SetTags(c.Args().First(), set, c.StringSlice("unset"), temporal.GetExecutionTimeout())
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "tagging of "+kind, false).Error())))
+			}
+			return clitools.SuccessResponse(tags.GetTags())
+		},
+	}
+}
@@ -19,6 +19,7 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 	"strings"
 
@@ -53,6 +54,8 @@ var HostCmd = cli.Command{
 		hostReboot,
 		hostStart,
 		hostStop,
+		hostTag,
+		hostAdopt,
 		hostCheckFeatureCommand,
 		hostAddFeatureCommand,
 		hostDeleteFeatureCommand,
@@ -60,6 +63,8 @@ var HostCmd = cli.Command{
 	},
 }
 
+var hostTag = tagCommand(hostCmdName, "host", func(clt client.Client) tagSetter { return clt.Host })
+
 var hostStart = cli.Command{
 	Name:      "start",
 	Usage:     "start Host",
@@ -126,10 +131,12 @@ var hostList = cli.Command{
 		cli.BoolFlag{
 			Name:  "all",
 			Usage: "List all hosts on tenant (not only those created by SafeScale)",
-		}},
+		},
+		selectorFlag,
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", hostCmdName, c.Command.Name, c.Args())
-		hosts, err := client.New().Host.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		hosts, err := client.New().Host.List(c.Bool("all"), c.String("selector"), temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of hosts", false).Error())))
 		}
@@ -167,6 +174,47 @@ var hostInspect = cli.Command{
 	},
 }
 
+var hostAdopt = cli.Command{
+	Name:      "adopt",
+	Usage:     "Manage with SafeScale a host of the provider created outside of SafeScale",
+	ArgsUsage: "<Host_ID at the provider>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "key-file",
+			Usage: "File containing the private key of the host; if given, the SSH access to the host with this key is checked before adopting it",
+		},
+		cli.StringFlag{
+			Name:  "user",
+			Usage: "User used to access the host through SSH (default: the operator user of the tenant)",
+		},
+		cli.IntFlag{
+			Name:  "port",
+			Usage: "Port of the SSH server of the host (default: 22)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", hostCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_ID>."))
+		}
+
+		var privateKey string
+		if keyFile := c.String("key-file"); keyFile != "" {
+			content, err := ioutil.ReadFile(utils.AbsPathify(keyFile))
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("failed to read key file '%s': %v", keyFile, err)))
+			}
+			privateKey = string(content)
+		}
+		resp, err := client.New().Host.Adopt(c.Args().First(), c.String("user"), c.Int("port"), privateKey, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "adoption of host", false).Error())))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
 var hostStatus = cli.Command{
 	Name:      "status",
 	Usage:     "status Host",
@@ -191,6 +239,9 @@ var hostCreate = cli.Command{
 	Usage:     "create a new host",
 	ArgsUsage: "<Host_name>",
 	Flags: []cli.Flag{
+		progressFlag,
+		asyncFlag,
+		tagFlag,
 		cli.StringFlag{
 			Name:  "net,network",
 			Value: "",
@@ -272,14 +323,29 @@ var hostCreate = cli.Command{
 			}
 		}
 
+		if err := checkAsyncOptions(c); err != nil {
+			return clitools.FailureResponse(err)
+		}
 		def, err := constructPBHostDefinitionFromCLI(c, "sizing")
 		if err != nil {
 			return err
 		}
-		resp, err := client.New().Host.Create(*def, temporal.GetExecutionTimeout())
+		def.Tags, err = tagsFromCLI(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		var resp *pb.Host
+		if c.Bool("progress") {
+			resp, err = client.New().Host.CreateWithProgress(*def, temporal.GetExecutionTimeout(), printProgress)
+		} else {
+			resp, err = clientFor(c).Host.Create(*def, temporal.GetExecutionTimeout())
+		}
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "creation of host", true).Error())))
 		}
+		if c.Bool("async") {
+			return jobSubmittedResponse()
+		}
 		return clitools.SuccessResponse(resp)
 	},
 }
@@ -34,6 +34,8 @@ var ImageCmd = cli.Command{
 	Usage: "image COMMAND",
 	Subcommands: []cli.Command{
 		imageList,
+		imageCreate,
+		imageDelete,
 	},
 }
 
@@ -55,3 +57,47 @@ var imageList = cli.Command{
 		return clitools.SuccessResponse(images.GetImages())
 	},
 }
+
+var imageCreate = cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Create an image from a host; the features installed on the host are remembered",
+	ArgsUsage: "<Host_name|Host_ID> <Image_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name> and/or <Image_name>."))
+		}
+
+		image, err := client.New().Image.Create(c.Args().Get(0), c.Args().Get(1), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "creation of image", true).Error())))
+		}
+		return clitools.SuccessResponse(image)
+	},
+}
+
+var imageDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Delete an image created from a host",
+	ArgsUsage: "<Image_name|Image_ID> [<Image_name|Image_ID>...]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Image_name|Image_ID>."))
+		}
+
+		var imageList []string
+		imageList = append(imageList, c.Args().First())
+		imageList = append(imageList, c.Args().Tail()...)
+
+		err := client.New().Image.Delete(imageList, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "deletion of image", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var jobCmdName = "job"
+
+// JobCmd command
+var JobCmd = cli.Command{
+	Name:  "job",
+	Usage: "job COMMAND",
+	Subcommands: []cli.Command{
+		jobList,
+		jobInspect,
+		jobStop,
+		jobWait,
+	},
+}
+
+var jobList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List the running jobs",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "Lists also the finished jobs kept in history by safescaled",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+
+		jobs, err := client.New().JobManager.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of jobs", false).Error())))
+		}
+		return clitools.SuccessResponse(jobs.GetList())
+	},
+}
+
+var jobInspect = cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Inspect a job, running or finished",
+	ArgsUsage: "<job_uuid>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <job_uuid>."))
+		}
+
+		job, err := client.New().JobManager.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "inspection of job", false).Error())))
+		}
+		return clitools.SuccessResponse(job)
+	},
+}
+
+var jobStop = cli.Command{
+	Name:      "stop",
+	Aliases:   []string{"kill"},
+	Usage:     "Stop a running job",
+	ArgsUsage: "<job_uuid>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <job_uuid>."))
+		}
+
+		err := client.New().JobManager.Stop(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "stop of job", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var jobWait = cli.Command{
+	Name:      "wait",
+	Usage:     "Wait the end of a job, displaying the changes of its status on stderr",
+	ArgsUsage: "<job_uuid>",
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Maximum duration of the wait (default: no limit)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <job_uuid>."))
+		}
+
+		changed := func(job *pb.JobDefinition) {
+			_, _ = fmt.Fprintf(os.Stderr, "job %s: %s\n", job.GetUuid(), job.GetStatus())
+		}
+		job, err := client.New().JobManager.Wait(c.Args().First(), c.Duration("timeout"), changed)
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "wait of job", false).Error())))
+		}
+		if job.GetStatus() != srvutils.JobSucceeded {
+			msg := fmt.Sprintf("job '%s' %s", job.GetUuid(), job.GetStatus())
+			if job.GetError() != "" {
+				msg += ": " + job.GetError()
+			}
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
+		}
+		return clitools.SuccessResponse(job)
+	},
+}
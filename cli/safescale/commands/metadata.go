@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var metadataCmdName = "metadata"
+
+// MetadataCmd command
+var MetadataCmd = cli.Command{
+	Name:  "metadata",
+	Usage: "metadata COMMAND",
+	Subcommands: []cli.Command{
+		metadataHistory,
+		metadataRestore,
+	},
+}
+
+var metadataHistory = cli.Command{
+	Name:      "history",
+	Usage:     "List the revisions kept in the history of the metadata of a resource",
+	ArgsUsage: "<host|network|share|volume|cluster> <name_or_id>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", metadataCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory arguments <kind> and <name_or_id>."))
+		}
+
+		revisions, err := client.New().Metadata.ListRevisions(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of metadata revisions", false).Error())))
+		}
+		return clitools.SuccessResponse(revisions.GetRevisions())
+	},
+}
+
+var metadataRestore = cli.Command{
+	Name:      "restore",
+	Usage:     "Roll the metadata of a resource back to a revision listed by 'metadata history'",
+	ArgsUsage: "<host|network|share|volume|cluster> <name_or_id> <revision>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", metadataCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 3 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory arguments <kind>, <name_or_id> and <revision>."))
+		}
+
+		err := client.New().Metadata.Restore(c.Args().Get(0), c.Args().Get(1), c.Args().Get(2), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "restoration of metadata", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
@@ -41,9 +41,32 @@ var NetworkCmd = cli.Command{
 		networkDelete,
 		networkInspect,
 		networkList,
+		networkTag,
+		networkAdopt,
 	},
 }
 
+var networkAdopt = cli.Command{
+	Name:      "adopt",
+	Usage:     "Manage with SafeScale a network of the provider created outside of SafeScale",
+	ArgsUsage: "<Network_ID at the provider>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Network_ID>."))
+		}
+
+		resp, err := client.New().Network.Adopt(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "adoption of network", false).Error())))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+var networkTag = tagCommand(networkCmdName, "network", func(clt client.Client) tagSetter { return clt.Network })
+
 var networkList = cli.Command{
 	Name:    "list",
 	Aliases: []string{"ls"},
@@ -52,10 +75,12 @@ var networkList = cli.Command{
 		cli.BoolFlag{
 			Name:  "all",
 			Usage: "List all Networks on tenant (not only those created by SafeScale)",
-		}},
+		},
+		selectorFlag,
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
-		networks, err := client.New().Network.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		networks, err := client.New().Network.List(c.Bool("all"), c.String("selector"), temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of networks", false).Error())))
 		}
@@ -163,6 +188,9 @@ var networkCreate = cli.Command{
 	Usage:     "create a network",
 	ArgsUsage: "<network_name>",
 	Flags: []cli.Flag{
+		progressFlag,
+		asyncFlag,
+		tagFlag,
 		cli.StringFlag{
 			Name:  "cidr",
 			Value: "192.168.0.0/24",
@@ -225,10 +253,17 @@ var networkCreate = cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>."))
 		}
 
+		if err := checkAsyncOptions(c); err != nil {
+			return clitools.FailureResponse(err)
+		}
 		def, err := constructPBHostDefinitionFromCLI(c, "sizing")
 		if err != nil {
 			return err
 		}
+		tags, err := tagsFromCLI(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
 		netdef := pb.NetworkDefinition{
 			Cidr:     c.String("cidr"),
 			Name:     c.Args().Get(0),
@@ -238,11 +273,20 @@ var networkCreate = cli.Command{
 				Name:    c.String("gwname"),
 				Sizing:  def.Sizing,
 			},
+			Tags: tags,
+		}
+		var network *pb.Network
+		if c.Bool("progress") {
+			network, err = client.New().Network.CreateWithProgress(netdef, temporal.GetExecutionTimeout(), printProgress)
+		} else {
+			network, err = clientFor(c).Network.Create(netdef, temporal.GetExecutionTimeout())
 		}
-		network, err := client.New().Network.Create(netdef, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "creation of network", true).Error())))
 		}
+		if c.Bool("async") {
+			return jobSubmittedResponse()
+		}
 		return clitools.SuccessResponse(network)
 	},
 }
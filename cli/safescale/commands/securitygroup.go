@@ -0,0 +1,353 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var securityGroupCmdName = "security-group"
+
+// SecurityGroupCmd security-group command
+var SecurityGroupCmd = cli.Command{
+	Name:    "security-group",
+	Aliases: []string{"sg"},
+	Usage:   "security-group COMMAND",
+	Subcommands: []cli.Command{
+		securityGroupList,
+		securityGroupInspect,
+		securityGroupCreate,
+		securityGroupDelete,
+		securityGroupRule,
+		securityGroupBind,
+		securityGroupUnbind,
+	},
+}
+
+var securityGroupList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List security groups",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "List all security groups on tenant (not only those created by SafeScale)",
+		}},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		list, err := client.New().SecurityGroup.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of security groups", false).Error())))
+		}
+		var result []*securityGroupDisplayable
+		for _, sg := range list.GetSecurityGroups() {
+			result = append(result, toDisplayableSecurityGroup(sg))
+		}
+		return clitools.SuccessResponse(result)
+	},
+}
+
+var securityGroupInspect = cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Inspect security group",
+	ArgsUsage: "<SecurityGroup_name|SecurityGroup_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <SecurityGroup_name|SecurityGroup_ID>."))
+		}
+
+		sg, err := client.New().SecurityGroup.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "inspection of security group", false).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableSecurityGroup(sg))
+	},
+}
+
+var securityGroupCreate = cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Create a security group, without rules",
+	ArgsUsage: "<SecurityGroup_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "network, net",
+			Usage: "Network the security group is created for (needed by AWS, where security groups belong to a VPC)",
+		},
+		cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the security group",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <SecurityGroup_name>."))
+		}
+
+		def := pb.SecurityGroupDefinition{
+			Name:        c.Args().First(),
+			Description: c.String("description"),
+		}
+		if network := c.String("network"); network != "" {
+			def.Network = &pb.Reference{Name: network}
+		}
+		sg, err := client.New().SecurityGroup.Create(def, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "creation of security group", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableSecurityGroup(sg))
+	},
+}
+
+var securityGroupDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Delete security group",
+	ArgsUsage: "<SecurityGroup_name|SecurityGroup_ID> [<SecurityGroup_name|SecurityGroup_ID>...]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <SecurityGroup_name|SecurityGroup_ID>."))
+		}
+
+		var names []string
+		names = append(names, c.Args().First())
+		names = append(names, c.Args().Tail()...)
+
+		err := client.New().SecurityGroup.Delete(names, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "deletion of security group", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var securityGroupRule = cli.Command{
+	Name:  "rule",
+	Usage: "rule COMMAND",
+	Subcommands: []cli.Command{
+		securityGroupRuleAdd,
+		securityGroupRuleDelete,
+	},
+}
+
+var securityGroupRuleAdd = cli.Command{
+	Name:      "add",
+	Usage:     "Add a rule to a security group",
+	ArgsUsage: "<SecurityGroup_name|SecurityGroup_ID>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "direction",
+			Value: "ingress",
+			Usage: "Direction of the traffic allowed: ingress or egress",
+		},
+		cli.StringFlag{
+			Name:  "protocol",
+			Usage: "Protocol allowed: tcp, udp, icmp; all the protocols if not set",
+		},
+		cli.StringFlag{
+			Name:  "port",
+			Usage: "Port or range of ports allowed (tcp and udp only), as <port> or <from>-<to>; all the ports if not set",
+		},
+		cli.StringFlag{
+			Name:  "cidr",
+			Usage: "Range of addresses the traffic comes from (ingress) or goes to (egress); 0.0.0.0/0 if not set",
+		},
+		cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the rule",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <SecurityGroup_name>."))
+		}
+
+		rule := pb.SecurityGroupRule{
+			Description: c.String("description"),
+			Protocol:    strings.ToLower(c.String("protocol")),
+			Cidr:        c.String("cidr"),
+		}
+		direction, ok := pb.RuleDirection_value[strings.ToUpper(c.String("direction"))]
+		if !ok {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid direction '%s', must be ingress or egress", c.String("direction"))))
+		}
+		rule.Direction = pb.RuleDirection(direction)
+		rule.EtherType = 4
+		if strings.Contains(rule.Cidr, ":") {
+			rule.EtherType = 6
+		}
+		if port := c.String("port"); port != "" {
+			from, to, err := parsePortRange(port)
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+			}
+			rule.PortFrom, rule.PortTo = from, to
+		}
+
+		sg, err := client.New().SecurityGroup.AddRule(c.Args().First(), rule, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "addition of rule to security group", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableSecurityGroup(sg))
+	},
+}
+
+var securityGroupRuleDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Delete a rule from a security group",
+	ArgsUsage: "<SecurityGroup_name|SecurityGroup_ID> <Rule_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <SecurityGroup_name> and/or <Rule_ID>."))
+		}
+
+		sg, err := client.New().SecurityGroup.DeleteRule(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "deletion of rule from security group", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableSecurityGroup(sg))
+	},
+}
+
+var securityGroupBind = cli.Command{
+	Name:      "bind",
+	Usage:     "Apply the rules of a security group to a host",
+	ArgsUsage: "<SecurityGroup_name|SecurityGroup_ID> <Host_name|Host_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <SecurityGroup_name> and/or <Host_name>."))
+		}
+
+		err := client.New().SecurityGroup.Bind(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "binding of security group", true).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var securityGroupUnbind = cli.Command{
+	Name:      "unbind",
+	Usage:     "Remove the rules of a security group from a host",
+	ArgsUsage: "<SecurityGroup_name|SecurityGroup_ID> <Host_name|Host_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", securityGroupCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <SecurityGroup_name> and/or <Host_name>."))
+		}
+
+		err := client.New().SecurityGroup.Unbind(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "unbinding of security group", true).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+// parsePortRange parses a port or a range of ports in the form <from>-<to>
+func parsePortRange(value string) (int32, int32, error) {
+	parts := strings.SplitN(value, "-", 2)
+	from, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port '%s'", value)
+	}
+	to := from
+	if len(parts) == 2 {
+		to, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid port range '%s'", value)
+		}
+	}
+	return int32(from), int32(to), nil
+}
+
+type securityGroupRuleDisplayable struct {
+	ID          string
+	Description string `json:",omitempty"`
+	Direction   string
+	Protocol    string
+	Ports       string `json:",omitempty"`
+	CIDR        string
+}
+
+type securityGroupDisplayable struct {
+	ID          string
+	Name        string
+	Description string `json:",omitempty"`
+	NetworkID   string `json:",omitempty"`
+	Rules       []securityGroupRuleDisplayable
+	Hosts       []string `json:",omitempty"`
+}
+
+func toDisplayableSecurityGroup(sg *pb.SecurityGroup) *securityGroupDisplayable {
+	out := &securityGroupDisplayable{
+		ID:          sg.GetId(),
+		Name:        sg.GetName(),
+		Description: sg.GetDescription(),
+		NetworkID:   sg.GetNetworkId(),
+		Rules:       []securityGroupRuleDisplayable{},
+	}
+	for _, r := range sg.GetRules() {
+		rule := securityGroupRuleDisplayable{
+			ID:          r.GetId(),
+			Description: r.GetDescription(),
+			Direction:   strings.ToLower(r.GetDirection().String()),
+			Protocol:    r.GetProtocol(),
+			CIDR:        r.GetCidr(),
+		}
+		if rule.Protocol == "" {
+			rule.Protocol = "all"
+		}
+		if r.GetPortFrom() > 0 {
+			rule.Ports = strconv.Itoa(int(r.GetPortFrom()))
+			if r.GetPortTo() > r.GetPortFrom() {
+				rule.Ports += "-" + strconv.Itoa(int(r.GetPortTo()))
+			}
+		}
+		out.Rules = append(out.Rules, rule)
+	}
+	for _, h := range sg.GetHosts() {
+		out.Hosts = append(out.Hosts, h.GetName())
+	}
+	return out
+}
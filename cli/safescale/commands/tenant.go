@@ -36,6 +36,9 @@ var TenantCmd = cli.Command{
 		tenantList,
 		tenantGet,
 		tenantSet,
+		tenantInspect,
+		tenantRefreshCache,
+		tenantFsck,
 		// tenantStorageList,
 		// tenantStorageGet,
 		// tenantStorageSet,
@@ -69,6 +72,55 @@ var tenantGet = cli.Command{
 	},
 }
 
+var tenantInspect = cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Show the provider of a tenant, the rate limits of its calls and the state of its circuit breaker",
+	ArgsUsage: "[<tenant_name>]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		tenant, err := client.New().Tenant.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "inspection of tenant", false).Error())))
+		}
+		return clitools.SuccessResponse(tenant)
+	},
+}
+
+var tenantRefreshCache = cli.Command{
+	Name:      "refresh-cache",
+	Usage:     "Empty the cache of the images, templates, availability zones and regions of the provider of a tenant",
+	ArgsUsage: "[<tenant_name>]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		err := client.New().Tenant.RefreshCache(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "refresh of the cache of tenant", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var tenantFsck = cli.Command{
+	Name:      "fsck",
+	Usage:     "Cross-check the metadata of a tenant with the hosts, networks and volumes of its provider",
+	ArgsUsage: "[<tenant_name>]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "repair",
+			Usage: "Removes the metadata of the resources that don't exist anymore and the references to them, and adopts the networks and volumes without metadata that follow the naming of SafeScale",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		report, err := client.New().Tenant.Fsck(c.Args().First(), c.Bool("repair"), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "check of tenant", false).Error())))
+		}
+		return clitools.SuccessResponse(report)
+	},
+}
+
 var tenantSet = cli.Command{
 	Name:  "set",
 	Usage: "Set tenant to work with",
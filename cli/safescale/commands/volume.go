@@ -44,9 +44,34 @@ var VolumeCmd = cli.Command{
 		volumeCreate,
 		volumeAttach,
 		volumeDetach,
+		volumeResize,
+		volumeTag,
+		volumeAdopt,
+		volumeSnapshotCmd,
 	},
 }
 
+var volumeAdopt = cli.Command{
+	Name:      "adopt",
+	Usage:     "Manage with SafeScale a volume of the provider created outside of SafeScale",
+	ArgsUsage: "<Volume_ID at the provider>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_ID>."))
+		}
+
+		resp, err := client.New().Volume.Adopt(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "adoption of volume", false).Error())))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+var volumeTag = tagCommand(volumeCmdName, "volume", func(clt client.Client) tagSetter { return clt.Volume })
+
 var volumeList = cli.Command{
 	Name:    "list",
 	Aliases: []string{"ls"},
@@ -55,10 +80,12 @@ var volumeList = cli.Command{
 		cli.BoolFlag{
 			Name:  "all",
 			Usage: "List all Volumes on tenant (not only those created by SafeScale)",
-		}},
+		},
+		selectorFlag,
+	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
-		volumes, err := client.New().Volume.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		volumes, err := client.New().Volume.List(c.Bool("all"), c.String("selector"), temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of volumes", false).Error())))
 		}
@@ -126,6 +153,7 @@ var volumeCreate = cli.Command{
 			Value: "HDD",
 			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
 		},
+		tagFlag,
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
@@ -143,10 +171,15 @@ var volumeCreate = cli.Command{
 		if volSize <= 0 {
 			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d', should be at least 1", volSize)))
 		}
+		tags, err := tagsFromCLI(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
 		def := pb.VolumeDefinition{
 			Name:  c.Args().First(),
 			Size:  volSize,
 			Speed: pb.VolumeSpeed(volSpeed),
+			Tags:  tags,
 		}
 
 		volume, err := client.New().Volume.Create(def, temporal.GetExecutionTimeout())
@@ -217,6 +250,35 @@ var volumeDetach = cli.Command{
 	},
 }
 
+var volumeResize = cli.Command{
+	Name:      "resize",
+	Usage:     "Extend a volume, and grow its filesystem if the volume is attached to an host",
+	ArgsUsage: "<Volume_name|Volume_ID>",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "size",
+			Usage: "New size of the volume (in Go), greater than the current one",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name>."))
+		}
+		volSize := int32(c.Int("size"))
+		if volSize <= 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d', should be at least 1", volSize)))
+		}
+
+		volume, err := client.New().Volume.Resize(c.Args().First(), volSize, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "resize of volume", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplaybleVolume(volume))
+	},
+}
+
 type volumeInfoDisplayable struct {
 	ID        string
 	Name      string
@@ -226,6 +288,7 @@ type volumeInfoDisplayable struct {
 	MountPath string
 	Format    string
 	Device    string
+	Tags      map[string]string `json:",omitempty"`
 }
 
 type volumeDisplayable struct {
@@ -233,6 +296,7 @@ type volumeDisplayable struct {
 	Name  string
 	Speed string
 	Size  int32
+	Tags  map[string]string `json:",omitempty"`
 }
 
 func toDisplaybleVolumeInfo(volumeInfo *pb.VolumeInfo) *volumeInfoDisplayable {
@@ -245,6 +309,7 @@ func toDisplaybleVolumeInfo(volumeInfo *pb.VolumeInfo) *volumeInfoDisplayable {
 		volumeInfo.GetMountPath(),
 		volumeInfo.GetFormat(),
 		volumeInfo.GetDevice(),
+		volumeInfo.GetTags(),
 	}
 }
 
@@ -254,6 +319,7 @@ func toDisplaybleVolume(volumeInfo *pb.Volume) *volumeDisplayable {
 		volumeInfo.GetName(),
 		pb.VolumeSpeed_name[int32(volumeInfo.GetSpeed())],
 		volumeInfo.GetSize(),
+		volumeInfo.GetTags(),
 	}
 }
 
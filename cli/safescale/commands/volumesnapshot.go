@@ -0,0 +1,200 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var volumeSnapshotCmdName = "volume snapshot"
+
+// volumeSnapshotCmd volume snapshot command
+var volumeSnapshotCmd = cli.Command{
+	Name:  "snapshot",
+	Usage: "snapshot COMMAND",
+	Subcommands: []cli.Command{
+		volumeSnapshotCreate,
+		volumeSnapshotList,
+		volumeSnapshotDelete,
+		volumeSnapshotRestore,
+	},
+}
+
+var volumeSnapshotCreate = cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Create a snapshot of a volume (may be attached to a host)",
+	ArgsUsage: "<Volume_name|Volume_ID> <Snapshot_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the snapshot",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name> and/or <Snapshot_name>."))
+		}
+		def := pb.VolumeSnapshotDefinition{
+			Volume:      &pb.Reference{Name: c.Args().Get(0)},
+			Name:        c.Args().Get(1),
+			Description: c.String("description"),
+		}
+
+		snapshot, err := client.New().Volume.CreateSnapshot(def, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "creation of volume snapshot", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableVolumeSnapshot(snapshot))
+	},
+}
+
+var volumeSnapshotList = cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls"},
+	Usage:     "List the volume snapshots, of a volume if one is given",
+	ArgsUsage: "[<Volume_name|Volume_ID>]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "List all the snapshots on tenant (not only those created by SafeScale)",
+		}},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() > 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Too many arguments."))
+		}
+
+		list, err := client.New().Volume.ListSnapshots(c.Args().First(), c.Bool("all"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of volume snapshots", false).Error())))
+		}
+		var result []*volumeSnapshotDisplayable
+		for _, snapshot := range list.GetSnapshots() {
+			result = append(result, toDisplayableVolumeSnapshot(snapshot))
+		}
+		return clitools.SuccessResponse(result)
+	},
+}
+
+var volumeSnapshotDelete = cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Delete volume snapshot",
+	ArgsUsage: "<Snapshot_name|Snapshot_ID> [<Snapshot_name|Snapshot_ID>...]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Snapshot_name|Snapshot_ID>."))
+		}
+
+		var snapshotList []string
+		snapshotList = append(snapshotList, c.Args().First())
+		snapshotList = append(snapshotList, c.Args().Tail()...)
+
+		err := client.New().Volume.DeleteSnapshot(snapshotList, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "deletion of volume snapshot", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var volumeSnapshotRestore = cli.Command{
+	Name:      "restore",
+	Usage:     "Create a new volume from a volume snapshot",
+	ArgsUsage: "<Snapshot_name|Snapshot_ID> <Volume_name>",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "size",
+			Usage: "Size of the volume (in Go), the size of the snapshot if not set",
+		},
+		cli.StringFlag{
+			Name:  "speed",
+			Value: "HDD",
+			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeSnapshotCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Snapshot_name> and/or <Volume_name>."))
+		}
+
+		speed := c.String("speed")
+		volSpeed, ok := pb.VolumeSpeed_value[speed]
+		if !ok {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid speed '%s'", speed)))
+		}
+		volSize := int32(c.Int("size"))
+		if volSize < 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d'", volSize)))
+		}
+		def := pb.VolumeSnapshotRestoreRequest{
+			Snapshot: &pb.Reference{Name: c.Args().Get(0)},
+			Name:     c.Args().Get(1),
+			Size:     volSize,
+			Speed:    pb.VolumeSpeed(volSpeed),
+		}
+
+		volume, err := client.New().Volume.RestoreSnapshot(def, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "restoration of volume snapshot", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplaybleVolume(volume))
+	},
+}
+
+type volumeSnapshotDisplayable struct {
+	ID          string
+	Name        string
+	Description string `json:",omitempty"`
+	Volume      string
+	Size        int32
+	State       string
+	CreatedAt   string `json:",omitempty"`
+}
+
+func toDisplayableVolumeSnapshot(snapshot *pb.VolumeSnapshot) *volumeSnapshotDisplayable {
+	volume := snapshot.GetVolume().GetName()
+	if volume == "" {
+		volume = snapshot.GetVolume().GetId()
+	}
+	return &volumeSnapshotDisplayable{
+		ID:          snapshot.GetId(),
+		Name:        snapshot.GetName(),
+		Description: snapshot.GetDescription(),
+		Volume:      volume,
+		Size:        snapshot.GetSize(),
+		State:       snapshot.GetState(),
+		CreatedAt:   snapshot.GetCreatedAt(),
+	}
+}
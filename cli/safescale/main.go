@@ -102,6 +102,11 @@ func main() {
 			Name:  "debug, d",
 			Usage: "Show debug information",
 		},
+		cli.StringFlag{
+			Name:   "tenant",
+			Usage:  "Tenant targeted by the command, overriding the one set with 'safescale tenant set'",
+			EnvVar: "SAFESCALE_TENANT",
+		},
 		cli.StringFlag{
 			Name:  "profile",
 			Usage: "Profiles binary; can contain 'cpu', 'ram', 'web' and a combination of them (ie 'cpu,ram')",
@@ -123,6 +128,13 @@ func main() {
 		// 	logrus.Errorf(err.Error())
 		// }
 
+		// Selects the tenant targeted by all the calls to safescaled
+		if tenant := c.String("tenant"); tenant != "" {
+			if err := os.Setenv("SAFESCALE_TENANT", tenant); err != nil {
+				return err
+			}
+		}
+
 		// Sets profiling
 		if c.IsSet("profile") {
 			what := c.String("profile")
@@ -169,6 +181,9 @@ func main() {
 	app.Commands = append(app.Commands, commands.VolumeCmd)
 	sort.Sort(cli.CommandsByName(commands.VolumeCmd.Subcommands))
 
+	app.Commands = append(app.Commands, commands.SecurityGroupCmd)
+	sort.Sort(cli.CommandsByName(commands.SecurityGroupCmd.Subcommands))
+
 	app.Commands = append(app.Commands, commands.SSHCmd)
 	sort.Sort(cli.CommandsByName(commands.SSHCmd.Subcommands))
 
@@ -187,6 +202,15 @@ func main() {
 	app.Commands = append(app.Commands, commands.ClusterCommand)
 	sort.Sort(cli.CommandsByName(commands.ClusterCommand.Subcommands))
 
+	app.Commands = append(app.Commands, commands.AuditCmd)
+	sort.Sort(cli.CommandsByName(commands.AuditCmd.Subcommands))
+
+	app.Commands = append(app.Commands, commands.MetadataCmd)
+	sort.Sort(cli.CommandsByName(commands.MetadataCmd.Subcommands))
+
+	app.Commands = append(app.Commands, commands.JobCmd)
+	sort.Sort(cli.CommandsByName(commands.JobCmd.Subcommands))
+
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	// err := app.Run(os.Args)
@@ -17,8 +17,10 @@
 package main
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path"
@@ -26,6 +28,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -33,10 +36,15 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/jobs"
 	"github.com/CS-SI/SafeScale/lib/server/listeners"
+	"github.com/CS-SI/SafeScale/lib/server/rest"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
+	libutils "github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 
 	_ "github.com/CS-SI/SafeScale/lib/server"
 )
@@ -50,11 +58,11 @@ func cleanup(onAbort bool) {
 }
 
 // *** MAIN ***
-func work() {
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+func work(c *cli.Context) {
+	signals := make(chan os.Signal)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
 	go func() {
-		<-c
+		<-signals
 		cleanup(true)
 	}()
 
@@ -93,21 +101,118 @@ func work() {
 	if err != nil {
 		logrus.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	security, err := getServerSecurity(c)
+	if err != nil {
+		logrus.Fatalf("failed to set up security: %v", err)
+	}
+	loopback, err := security.LoopbackClientSecurity()
+	if err != nil {
+		logrus.Fatalf("failed to set up security of internal calls: %v", err)
+	}
+	utils.SetClientSecurity(loopback)
+
+	var serverOptions []grpc.ServerOption
+	creds, err := security.TransportCredentials()
+	if err != nil {
+		logrus.Fatalf("failed to set up TLS: %v", err)
+	}
+	if creds != nil {
+		serverOptions = append(serverOptions, grpc.Creds(creds))
+		if security.ClientCAFile != "" {
+			logrus.Infoln("Using mutual TLS")
+		} else {
+			logrus.Infoln("Using TLS")
+		}
+	} else {
+		logrus.Warnln("TLS is disabled, communications with safescaled are not encrypted")
+	}
+	authUnary, authStream := security.Interceptors()
+	if security.AuthEnabled() {
+		logrus.Infoln("Using token authentication")
+	} else {
+		logrus.Warnln("Authentication is disabled, anyone reaching safescaled can use it")
+	}
+	tenantUnary, tenantStream := listeners.TenantInterceptors()
+	recorder, err := getAuditRecorder(c)
+	if err != nil {
+		logrus.Fatalf("failed to set up audit: %v", err)
+	}
+	auditUnary, auditStream := recorder.Interceptors()
+	if recorder == nil {
+		logrus.Infoln("Audit is disabled, mutating operations are not recorded (see option --audit-file)")
+	}
+	store, err := getJobStore(c)
+	if err != nil {
+		logrus.Fatalf("failed to set up history of jobs: %v", err)
+	}
+	if store != nil {
+		utils.SetJobStore(store)
+	} else {
+		logrus.Warnln("History of jobs is disabled, only running jobs are listed")
+	}
+	jobsUnary, jobsStream := utils.JobInterceptors(audit.IsMutating)
+	authorizer := getAuthorizer(c)
+	rbacUnary, rbacStream := authorizer.Interceptors()
+	if authorizer != nil {
+		logrus.Infoln("Using role-based access control")
+		// The audit interceptors run after the access control, which records the denials itself
+		authorizer.OnDenial(recorder.RecordDenial)
+		// The permissions given on the name of a resource apply when it's designated by its ID
+		authorizer.ResolveWith(listeners.ResolveResource)
+	}
+	var metricsUnary grpc.UnaryServerInterceptor
+	var metricsStream grpc.StreamServerInterceptor
+	if address := c.String("metrics-listen"); address != "" {
+		if err := serveMetrics(address, c.Duration("metrics-refresh")); err != nil {
+			logrus.Fatalf("failed to start metrics endpoint: %v", err)
+		}
+		metricsUnary, metricsStream = utils.MetricsInterceptors()
+	}
+	unaryInterceptor := utils.ChainUnaryServer(metricsUnary, authUnary, tenantUnary, rbacUnary, utils.AsyncInterceptor(isAsyncEligible), auditUnary, jobsUnary)
+	serverOptions = append(serverOptions,
+		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.StreamInterceptor(utils.ChainStreamServer(metricsStream, authStream, tenantStream, rbacStream, auditStream, jobsStream)),
+	)
+	s := grpc.NewServer(serverOptions...)
 
 	logrus.Infoln("Registering services")
+	pb.RegisterAuditServiceServer(s, &listeners.AuditListener{Recorder: recorder})
 	pb.RegisterBucketServiceServer(s, &listeners.BucketListener{})
+	pb.RegisterClusterServiceServer(s, &listeners.ClusterListener{})
 	// pb.RegisterDataServiceServer(s, &listeners.DataListener{})
 	pb.RegisterHostServiceServer(s, &listeners.HostListener{})
 	pb.RegisterImageServiceServer(s, &listeners.ImageListener{})
 	pb.RegisterJobServiceServer(s, &listeners.JobManagerListener{})
+	pb.RegisterMetadataServiceServer(s, &listeners.MetadataListener{})
 	pb.RegisterNetworkServiceServer(s, &listeners.NetworkListener{})
+	pb.RegisterSecurityGroupServiceServer(s, &listeners.SecurityGroupListener{})
 	pb.RegisterShareServiceServer(s, &listeners.ShareListener{})
 	pb.RegisterSshServiceServer(s, &listeners.SSHListener{})
 	pb.RegisterTemplateServiceServer(s, &listeners.TemplateListener{})
 	pb.RegisterTenantServiceServer(s, &listeners.TenantListener{})
 	pb.RegisterVolumeServiceServer(s, &listeners.VolumeListener{})
 
+	if address := c.String("rest-listen"); address != "" {
+		services := map[string]interface{}{
+			"AuditService":         &listeners.AuditListener{Recorder: recorder},
+			"BucketService":        &listeners.BucketListener{},
+			"ClusterService":       &listeners.ClusterListener{},
+			"HostService":          &listeners.HostListener{},
+			"ImageService":         &listeners.ImageListener{},
+			"JobService":           &listeners.JobManagerListener{},
+			"MetadataService":      &listeners.MetadataListener{},
+			"NetworkService":       &listeners.NetworkListener{},
+			"SecurityGroupService": &listeners.SecurityGroupListener{},
+			"ShareService":         &listeners.ShareListener{},
+			"TemplateService":      &listeners.TemplateListener{},
+			"TenantService":        &listeners.TenantListener{},
+			"VolumeService":        &listeners.VolumeListener{},
+		}
+		if err := serveREST(address, security, services, unaryInterceptor); err != nil {
+			logrus.Fatalf("failed to start REST gateway: %v", err)
+		}
+	}
+
 	// logrus.Println("Initializing service factory")
 	// commands.InitServiceFactory()
 
@@ -121,6 +226,115 @@ func work() {
 	}
 }
 
+// serveREST starts in background the REST/JSON gateway listening at address, using the same TLS settings and
+// interceptors as the gRPC endpoint
+func serveREST(address string, security *utils.ServerSecurity, services map[string]interface{}, interceptor grpc.UnaryServerInterceptor) error {
+	gateway, err := rest.NewGateway(services, interceptor)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := security.TLSConfig()
+	if err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: gateway}
+	if tlsConfig != nil {
+		lis = tls.NewListener(lis, tlsConfig)
+	}
+	logrus.Infof("Serving REST gateway at %s (OpenAPI document at %s)", address, rest.OpenAPIPath)
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			logrus.Errorf("REST gateway stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// serveMetrics starts in background the HTTP endpoint exposing the metrics of safescaled to Prometheus at address,
+// the counts of managed resources being refreshed every refresh
+func serveMetrics(address string, refresh time.Duration) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	logrus.Infof("Serving metrics at %s/metrics", address)
+	go func() {
+		if err := http.Serve(lis, mux); err != nil {
+			logrus.Errorf("metrics endpoint stopped: %v", err)
+		}
+	}()
+	if refresh > 0 {
+		listeners.WatchManagedResources(refresh)
+	}
+	return nil
+}
+
+// getServerSecurity builds the security settings of safescaled from flags and environment
+func getServerSecurity(c *cli.Context) (*utils.ServerSecurity, error) {
+	security := &utils.ServerSecurity{
+		CertFile:     c.String("tls-cert"),
+		KeyFile:      c.String("tls-key"),
+		ClientCAFile: c.String("tls-client-ca"),
+	}
+	for _, t := range c.StringSlice("auth-token") {
+		if t = strings.TrimSpace(t); t != "" {
+			security.Tokens = append(security.Tokens, t)
+		}
+	}
+	if tokensFile := c.String("auth-tokens-file"); tokensFile != "" {
+		tokens, identities, err := utils.LoadTokens(tokensFile)
+		if err != nil {
+			return nil, err
+		}
+		security.Tokens = append(security.Tokens, tokens...)
+		security.Identities = identities
+	}
+	return security, nil
+}
+
+// getAuditRecorder builds the audit recorder of safescaled from flags and environment; returns nil if audit is disabled
+func getAuditRecorder(c *cli.Context) (*audit.Recorder, error) {
+	path := c.String("audit-file")
+	if path == "" {
+		return nil, nil
+	}
+	var bucket audit.BucketProvider
+	if c.Bool("audit-bucket") {
+		bucket = listeners.TenantMetadataBucket
+	}
+	return audit.NewRecorder(libutils.AbsPathify(path), bucket)
+}
+
+// getJobStore builds the store keeping the history of the jobs from flags and environment; returns nil if history is disabled
+func getJobStore(c *cli.Context) (*jobs.Store, error) {
+	path := c.String("jobs-db")
+	if path == "" {
+		return nil, nil
+	}
+	return jobs.NewStore(libutils.AbsPathify(path), c.Duration("jobs-retention"), c.Int("jobs-max"))
+}
+
+// isAsyncEligible tells if an RPC can be run as a background job: only the mutating RPCs not dealing with jobs themselves
+func isAsyncEligible(fullMethod string) bool {
+	service, _ := utils.SplitMethod(fullMethod)
+	return service != "JobService" && audit.IsMutating(fullMethod)
+}
+
+// getAuthorizer builds the Authorizer of safescaled from flags and environment; returns nil if authorization is disabled
+func getAuthorizer(c *cli.Context) *utils.Authorizer {
+	dsn := c.String("rbac-db-dsn")
+	if dsn == "" {
+		return nil
+	}
+	return utils.NewAuthorizer(c.String("rbac-db-dialect"), dsn, c.String("rbac-service"))
+}
+
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
@@ -154,6 +368,92 @@ func main() {
 			Usage: "Profiles binary; can contain 'cpu', 'ram', 'web' and a combination of them (ie 'cpu,ram')",
 			// TODO: extends profile to accept <what>:params, for example cpu:$HOME/safescale.cpu.pprof, or web:192.168.2.1:1666
 		},
+		cli.StringFlag{
+			Name:   "tls-cert",
+			Usage:  "Enables TLS using the server certificate in `FILE` (PEM)",
+			EnvVar: "SAFESCALED_TLS_CERT",
+		},
+		cli.StringFlag{
+			Name:   "tls-key",
+			Usage:  "Private key of the server certificate in `FILE` (PEM)",
+			EnvVar: "SAFESCALED_TLS_KEY",
+		},
+		cli.StringFlag{
+			Name:   "tls-client-ca",
+			Usage:  "Enables mutual TLS, accepting client certificates signed by the CA in `FILE` (PEM)",
+			EnvVar: "SAFESCALED_TLS_CLIENT_CA",
+		},
+		cli.StringSliceFlag{
+			Name:   "auth-token",
+			Usage:  "Enables authentication, accepting `TOKEN` as bearer token or API key (can be used several times)",
+			EnvVar: "SAFESCALED_AUTH_TOKENS",
+		},
+		cli.StringFlag{
+			Name:   "auth-tokens-file",
+			Usage:  "Enables authentication, accepting the tokens contained in `FILE` (one per line, optionally followed by the identity of their owner)",
+			EnvVar: "SAFESCALED_AUTH_TOKENS_FILE",
+		},
+		cli.StringFlag{
+			Name:   "audit-file",
+			Usage:  "Records the mutating operations in `FILE` (JSON lines); audit is disabled if empty (default)",
+			EnvVar: "SAFESCALED_AUDIT_FILE",
+		},
+		cli.BoolFlag{
+			Name:   "audit-bucket",
+			Usage:  "Copies also the audit records in the metadata bucket of the tenant",
+			EnvVar: "SAFESCALED_AUDIT_BUCKET",
+		},
+		cli.StringFlag{
+			Name:   "jobs-db",
+			Usage:  "Keeps the history of the jobs in the SQLite database `FILE`; only running jobs are kept in memory if empty",
+			Value:  "$HOME/.safescale/safescaled-jobs.db",
+			EnvVar: "SAFESCALED_JOBS_DB",
+		},
+		cli.DurationFlag{
+			Name:   "jobs-retention",
+			Usage:  "Duration the finished jobs are kept in history (0 to keep them regardless of their age)",
+			Value:  jobs.DefaultRetention,
+			EnvVar: "SAFESCALED_JOBS_RETENTION",
+		},
+		cli.IntFlag{
+			Name:   "jobs-max",
+			Usage:  "Maximum number of finished jobs kept in history (0 for no limit)",
+			Value:  jobs.DefaultMaxJobs,
+			EnvVar: "SAFESCALED_JOBS_MAX",
+		},
+		cli.StringFlag{
+			Name:   "rbac-db-dialect",
+			Usage:  "Dialect of the security database (sqlite3, mysql, postgres or mssql)",
+			Value:  "sqlite3",
+			EnvVar: "SAFESCALED_RBAC_DB_DIALECT",
+		},
+		cli.StringFlag{
+			Name:   "rbac-db-dsn",
+			Usage:  "Enables role-based access control, using the security database reached with `DSN`",
+			EnvVar: "SAFESCALED_RBAC_DB_DSN",
+		},
+		cli.StringFlag{
+			Name:   "rbac-service",
+			Usage:  "Name of the service of the security database holding the permissions on safescaled",
+			Value:  utils.DefaultAuthorizationService,
+			EnvVar: "SAFESCALED_RBAC_SERVICE",
+		},
+		cli.StringFlag{
+			Name:   "rest-listen",
+			Usage:  "Serves the REST/JSON gateway at `ADDRESS` (ie ':8080'); the gateway is disabled if empty",
+			EnvVar: "SAFESCALED_REST_LISTEN",
+		},
+		cli.StringFlag{
+			Name:   "metrics-listen",
+			Usage:  "Exposes the metrics of safescaled to Prometheus at http://`ADDRESS`/metrics (ie ':9100'); metrics are disabled if empty",
+			EnvVar: "SAFESCALED_METRICS_LISTEN",
+		},
+		cli.DurationFlag{
+			Name:   "metrics-refresh",
+			Usage:  "Period of the refresh of the numbers of hosts, networks and clusters per tenant (0 to disable them)",
+			Value:  time.Minute,
+			EnvVar: "SAFESCALED_METRICS_REFRESH",
+		},
 		// cli.IntFlag{
 		// 	Name:  "port, p",
 		// 	Usage: "Bind to specified port `PORT`",
@@ -191,7 +491,7 @@ func main() {
 	}
 
 	app.Action = func(c *cli.Context) error {
-		work()
+		work(c)
 		return nil
 	}
 
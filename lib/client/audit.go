@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// audit is the safescale client part handling the audit log
+type audit struct {
+	// session is not used currently
+	session *Session
+}
+
+// List returns the audit records of the current tenant since the time given, optionally restricted to a resource
+func (a *audit) List(since time.Time, resource string, timeout time.Duration) (*pb.AuditRecordList, error) {
+	a.session.Connect()
+	defer a.session.Disconnect()
+	service := pb.NewAuditServiceClient(a.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.AuditListRequest{Resource: resource}
+	if !since.IsZero() {
+		req.Since = since.Format(time.RFC3339)
+	}
+	return service.List(ctx, req)
+}
@@ -19,7 +19,9 @@ package client
 import (
 	"fmt"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -30,28 +32,37 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/CS-SI/SafeScale/lib/server/utils"
+	libutils "github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
 // Session units the different resources proposed by safescaled as safescale client
 type Session struct {
-	Bucket     *bucket
-	Data       *data
-	Host       *host
-	Image      *image
-	JobManager *jobManager
-	Network    *network
-	Share      *share
-	SSH        *ssh
-	Template   *template
-	Tenant     *tenant
-	Volume     *volume
+	Audit         *audit
+	Bucket        *bucket
+	Cluster       *cluster
+	Data          *data
+	Host          *host
+	Image         *image
+	JobManager    *jobManager
+	Metadata      *metadata
+	Network       *network
+	SecurityGroup *securityGroup
+	Share         *share
+	SSH           *ssh
+	Template      *template
+	Tenant        *tenant
+	Volume        *volume
 
 	safescaledHost string
 	safescaledPort int
 	connection     *grpc.ClientConn
 
+	// tenantName is the tenant targeted by the requests of the session; if empty, safescaled decides
 	tenantName string
+	// async tells safescaled to run the mutating requests of the session as background jobs
+	async bool
 }
 
 // Client is a instance of Session used temporarily until the session logic in safescaled is implemented
@@ -63,8 +74,25 @@ var (
 	DefaultExecutionTimeout  = temporal.GetExecutionTimeout()
 )
 
-// New returns an instance of safescale Client
+// New returns an instance of safescale Client targeting the default tenant
 func New() Client {
+	return NewWithTenant(DefaultTenant())
+}
+
+// NewWithTenant returns an instance of safescale Client targeting the tenant named tenant
+func NewWithTenant(tenant string) Client {
+	return newSession(tenant, false)
+}
+
+// Async returns an instance of safescale Client targeting the same tenant as c, whose mutating requests are run
+// by safescaled as background jobs: they return at once an empty response, and the job, identified by the uuid
+// of the request (see utils.GetUUID), can be followed with JobManager.Inspect, Watch or Wait
+func Async(c Client) Client {
+	return newSession(c.tenantName, true)
+}
+
+// newSession creates a Session
+func newSession(tenant string, async bool) *Session {
 	safescaledPort := 50051
 
 	if portCandidate := os.Getenv("SAFESCALED_PORT"); portCandidate != "" {
@@ -77,14 +105,20 @@ func New() Client {
 	s := &Session{
 		safescaledHost: "localhost",
 		safescaledPort: safescaledPort,
+		tenantName:     tenant,
+		async:          async,
 	}
 
+	s.Audit = &audit{session: s}
 	s.Bucket = &bucket{session: s}
+	s.Cluster = &cluster{session: s}
 	s.Data = &data{session: s}
 	s.Host = &host{session: s}
 	s.Image = &image{session: s}
 	s.Network = &network{session: s}
 	s.JobManager = &jobManager{session: s}
+	s.Metadata = &metadata{session: s}
+	s.SecurityGroup = &securityGroup{session: s}
 	s.Share = &share{session: s}
 	s.SSH = &ssh{session: s}
 	s.Template = &template{session: s}
@@ -93,10 +127,56 @@ func New() Client {
 	return s
 }
 
+// FromTask returns an instance of safescale Client targeting the tenant of the request handled by the task.
+// It is meant to be used by safescaled to call itself on behalf of a request; if the task
+// doesn't handle a request, the default tenant is targeted.
+func FromTask(task concurrency.Task) Client {
+	if task != nil {
+		if tenant := utils.TenantFromContext(task.GetContext()); tenant != "" {
+			return NewWithTenant(tenant)
+		}
+	}
+	return New()
+}
+
+// defaultTenantFile is the file where is stored the tenant used by default
+const defaultTenantFile = "$HOME/.safescale/tenant"
+
+// DefaultTenant returns the tenant targeted by default, from environment variable SAFESCALE_TENANT or,
+// if not set, from the tenant recorded with SetDefaultTenant.
+// An empty string means safescaled will choose (it succeeds only if there is one tenant)
+func DefaultTenant() string {
+	if tenant := os.Getenv("SAFESCALE_TENANT"); tenant != "" {
+		return tenant
+	}
+	content, err := ioutil.ReadFile(libutils.AbsPathify(defaultTenantFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// SetDefaultTenant records the tenant to target by default
+func SetDefaultTenant(tenant string) error {
+	path := libutils.AbsPathify(defaultTenantFile)
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(tenant+"\n"), 0600)
+}
+
 // Connect establishes connection with safescaled
 func (s *Session) Connect() {
 	if s.connection == nil {
-		s.connection = utils.GetConnection(s.safescaledHost, s.safescaledPort)
+		var kv []string
+		if s.tenantName != "" {
+			kv = append(kv, utils.TenantMetadataKey, s.tenantName)
+		}
+		if s.async {
+			kv = append(kv, utils.AsyncMetadataKey, "true")
+		}
+		s.connection = utils.GetConnection(s.safescaledHost, s.safescaledPort, utils.MetadataDialOptions(kv...)...)
 	}
 }
 
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// cluster is the safescale client part handling clusters
+type cluster struct {
+	// session is not used currently
+	session *Session
+}
+
+// List ...
+func (c *cluster) List(selector string, timeout time.Duration) (*pb.ClusterList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.List(ctx, &pb.ClusterListRequest{Selector: selector})
+}
+
+// Inspect ...
+func (c *cluster) Inspect(name string, timeout time.Duration) (*pb.Cluster, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.Reference{Name: name})
+}
+
+// Create ...
+func (c *cluster) Create(def pb.ClusterDefinition, timeout time.Duration) (*pb.Cluster, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Create(ctx, &def)
+}
+
+// CreateWithProgress creates a new cluster, calling progress with each progress event sent by the daemon
+func (c *cluster) CreateWithProgress(def pb.ClusterDefinition, timeout time.Duration, progress func(*pb.ProgressEvent)) (*pb.Cluster, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := service.CreateWithProgress(ctx, &def)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("creation of cluster '%s' ended without result", def.GetName())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if msg.GetEvent() != nil && progress != nil {
+			progress(msg.GetEvent())
+		}
+		if msg.GetCluster() != nil {
+			return msg.GetCluster(), nil
+		}
+	}
+}
+
+// Delete ...
+func (c *cluster) Delete(name string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Delete(ctx, &pb.Reference{Name: name})
+	return err
+}
+
+// Start ...
+func (c *cluster) Start(name string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Start(ctx, &pb.Reference{Name: name})
+	return err
+}
+
+// Stop ...
+func (c *cluster) Stop(name string, timeout time.Duration) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Stop(ctx, &pb.Reference{Name: name})
+	return err
+}
+
+// State ...
+func (c *cluster) State(name string, timeout time.Duration) (*pb.ClusterState, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.State(ctx, &pb.Reference{Name: name})
+}
+
+// Expand ...
+func (c *cluster) Expand(name string, count int, def *pb.HostDefinition, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Expand(ctx, &pb.ClusterResizeRequest{Name: name, Count: int32(count), NodeDefinition: def})
+}
+
+// Shrink ...
+func (c *cluster) Shrink(name string, count int, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Shrink(ctx, &pb.ClusterResizeRequest{Name: name, Count: int32(count)})
+}
+
+// AddFeature ...
+func (c *cluster) AddFeature(name, feature string, vars map[string]string, skipProxy bool, timeout time.Duration) (*pb.FeatureResponse, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.AddFeature(ctx, &pb.FeatureRequest{Name: feature, Target: name, Variables: vars, SkipProxy: skipProxy})
+}
+
+// CheckFeature ...
+func (c *cluster) CheckFeature(name, feature string, vars map[string]string, timeout time.Duration) (*pb.FeatureResponse, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.CheckFeature(ctx, &pb.FeatureRequest{Name: feature, Target: name, Variables: vars})
+}
+
+// DeleteFeature ...
+func (c *cluster) DeleteFeature(name, feature string, vars map[string]string, timeout time.Duration) (*pb.FeatureResponse, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.DeleteFeature(ctx, &pb.FeatureRequest{Name: feature, Target: name, Variables: vars})
+}
+
+// ListNodes ...
+func (c *cluster) ListNodes(name string, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ListNodes(ctx, &pb.Reference{Name: name})
+}
+
+// ListMasters ...
+func (c *cluster) ListMasters(name string, timeout time.Duration) (*pb.ClusterNodeList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ListMasters(ctx, &pb.Reference{Name: name})
+}
+
+// GetAdminPassword returns the password of the administrator account of the cluster
+func (c *cluster) GetAdminPassword(name string, timeout time.Duration) (*pb.ClusterAdminPassword, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.GetAdminPassword(ctx, &pb.Reference{Name: name})
+}
+
+// SetTags sets the tags in set and removes the tags in unset on the cluster name, then returns the resulting tags
+func (c *cluster) SetTags(name string, set map[string]string, unset []string, timeout time.Duration) (*pb.Tags, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewClusterServiceClient(c.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.SetTags(ctx, &pb.TagsRequest{
+		Resource: &pb.Reference{Name: name},
+		Set:      set,
+		Unset:    unset,
+	})
+}
@@ -17,6 +17,8 @@
 package client
 
 import (
+	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"time"
@@ -36,7 +38,7 @@ type host struct {
 }
 
 // List ...
-func (h *host) List(all bool, timeout time.Duration) (*pb.HostList, error) {
+func (h *host) List(all bool, selector string, timeout time.Duration) (*pb.HostList, error) {
 	h.session.Connect()
 	defer h.session.Disconnect()
 	service := pb.NewHostServiceClient(h.session.connection)
@@ -45,7 +47,7 @@ func (h *host) List(all bool, timeout time.Duration) (*pb.HostList, error) {
 		return nil, err
 	}
 
-	return service.List(ctx, &pb.HostListRequest{All: all})
+	return service.List(ctx, &pb.HostListRequest{All: all, Selector: selector})
 }
 
 // Inspect ...
@@ -129,6 +131,37 @@ func (h *host) Create(def pb.HostDefinition, timeout time.Duration) (*pb.Host, e
 	return service.Create(ctx, &def)
 }
 
+// CreateWithProgress creates a new host, calling progress with each progress event sent by the daemon
+func (h *host) CreateWithProgress(def pb.HostDefinition, timeout time.Duration, progress func(*pb.ProgressEvent)) (*pb.Host, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHostServiceClient(h.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := service.CreateWithProgress(ctx, &def)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("creation of host '%s' ended without result", def.GetName())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if msg.GetEvent() != nil && progress != nil {
+			progress(msg.GetEvent())
+		}
+		if msg.GetHost() != nil {
+			return msg.GetHost(), nil
+		}
+	}
+}
+
 // Delete deletes several hosts at the same time in goroutines
 func (h *host) Delete(names []string, timeout time.Duration) error {
 	h.session.Connect()
@@ -206,3 +239,35 @@ func (h *host) Resize(def pb.HostDefinition, duration time.Duration) (*pb.Host,
 
 	return service.Resize(ctx, &def)
 }
+
+// SetTags sets the tags in set and removes the tags in unset on the host name, then returns the resulting tags
+func (h *host) SetTags(name string, set map[string]string, unset []string, timeout time.Duration) (*pb.Tags, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHostServiceClient(h.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.SetTags(ctx, &pb.TagsRequest{
+		Resource: &pb.Reference{Name: name},
+		Set:      set,
+		Unset:    unset,
+	})
+}
+
+// Adopt creates the metadata of the host 'id' of the provider, created outside of SafeScale, accessed through SSH as
+// user on port (the operator user and 22 if empty); if privateKey is not empty, the SSH access to the host with this
+// key is checked before
+func (h *host) Adopt(id, user string, port int, privateKey string, timeout time.Duration) (*pb.Host, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHostServiceClient(h.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Adopt(ctx, &pb.HostAdoptRequest{Id: id, User: user, Port: int32(port), PrivateKey: privateKey})
+}
@@ -17,10 +17,12 @@
 package client
 
 import (
+	"strings"
 	"time"
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 )
 
 // host is the safescale client part handling hosts
@@ -41,3 +43,39 @@ func (img *image) List(all bool, timeout time.Duration) (*pb.ImageList, error) {
 
 	return service.List(ctx, &pb.ImageListRequest{All: all})
 }
+
+// Create creates an image named name from the host hostName
+func (img *image) Create(hostName string, name string, timeout time.Duration) (*pb.Image, error) {
+	img.session.Connect()
+	defer img.session.Disconnect()
+	service := pb.NewImageServiceClient(img.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Create(ctx, &pb.ImageDefinition{Host: &pb.Reference{Name: hostName}, Name: name})
+}
+
+// Delete deletes the images created from hosts named in names
+func (img *image) Delete(names []string, timeout time.Duration) error {
+	img.session.Connect()
+	defer img.session.Disconnect()
+	service := pb.NewImageServiceClient(img.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, name := range names {
+		_, err := service.Delete(ctx, &pb.Reference{Name: name})
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return clitools.ExitOnRPC(strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -17,10 +17,11 @@
 package client
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"time"
 
-	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
-
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
 )
@@ -31,8 +32,34 @@ type jobManager struct {
 	session *Session
 }
 
-// List ...
-func (c *jobManager) List(timeout time.Duration) (*pb.JobList, error) {
+// List returns the running jobs and, if all is true, the finished jobs kept in history
+func (c *jobManager) List(all bool, timeout time.Duration) (*pb.JobList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewJobServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.List(ctx, &pb.JobListRequest{All: all})
+}
+
+// Inspect returns the record of a job, running or finished
+func (c *jobManager) Inspect(uuid string, timeout time.Duration) (*pb.JobDefinition, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewJobServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.JobDefinition{Uuid: uuid})
+}
+
+// Wait waits the end of a job, calling changed (if not nil) each time its status changes, and returns its final record
+func (c *jobManager) Wait(uuid string, timeout time.Duration, changed func(*pb.JobDefinition)) (*pb.JobDefinition, error) {
 	c.session.Connect()
 	defer c.session.Disconnect()
 	service := pb.NewJobServiceClient(c.session.connection)
@@ -40,8 +67,33 @@ func (c *jobManager) List(timeout time.Duration) (*pb.JobList, error) {
 	if err != nil {
 		return nil, err
 	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	return service.List(ctx, &googleprotobuf.Empty{})
+	stream, err := service.Watch(ctx, &pb.JobDefinition{Uuid: uuid})
+	if err != nil {
+		return nil, err
+	}
+	var last *pb.JobDefinition
+	for {
+		job, err := stream.Recv()
+		if err == io.EOF {
+			if last == nil {
+				return nil, fmt.Errorf("watch of job '%s' ended without status", uuid)
+			}
+			return last, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		last = job
+		if changed != nil {
+			changed(job)
+		}
+	}
 }
 
 // Stop sends a signal to the server to stop a running job
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// metadata is the safescale client part handling the history of the metadata
+type metadata struct {
+	// session is not used currently
+	session *Session
+}
+
+// ListRevisions returns the revisions kept in the history of the metadata of kind 'kind' (host, network, share,
+// volume or cluster) designated by 'name'
+func (m *metadata) ListRevisions(kind, name string, timeout time.Duration) (*pb.MetadataRevisionList, error) {
+	m.session.Connect()
+	defer m.session.Disconnect()
+	service := pb.NewMetadataServiceClient(m.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ListRevisions(ctx, &pb.MetadataReference{Kind: kind, Name: name})
+}
+
+// Restore rolls the metadata of kind 'kind' designated by 'name' back to the revision 'revision'
+func (m *metadata) Restore(kind, name, revision string, timeout time.Duration) error {
+	m.session.Connect()
+	defer m.session.Disconnect()
+	service := pb.NewMetadataServiceClient(m.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Restore(ctx, &pb.MetadataRestoreRequest{
+		Metadata: &pb.MetadataReference{Kind: kind, Name: name},
+		Revision: revision,
+	})
+	return err
+}
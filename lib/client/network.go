@@ -17,6 +17,8 @@
 package client
 
 import (
+	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"time"
@@ -33,7 +35,7 @@ type network struct {
 }
 
 // List ...
-func (n *network) List(all bool, timeout time.Duration) (*pb.NetworkList, error) {
+func (n *network) List(all bool, selector string, timeout time.Duration) (*pb.NetworkList, error) {
 	n.session.Connect()
 	defer n.session.Disconnect()
 	service := pb.NewNetworkServiceClient(n.session.connection)
@@ -43,7 +45,8 @@ func (n *network) List(all bool, timeout time.Duration) (*pb.NetworkList, error)
 	}
 
 	return service.List(ctx, &pb.NetworkListRequest{
-		All: all,
+		All:      all,
+		Selector: selector,
 	})
 }
 
@@ -152,3 +155,64 @@ func (n *network) Create(def pb.NetworkDefinition, timeout time.Duration) (*pb.N
 	return service.Create(ctx, &def)
 
 }
+
+// CreateWithProgress creates a new network, calling progress with each progress event sent by the daemon
+func (n *network) CreateWithProgress(def pb.NetworkDefinition, timeout time.Duration, progress func(*pb.ProgressEvent)) (*pb.Network, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := service.CreateWithProgress(ctx, &def)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("creation of network '%s' ended without result", def.GetName())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if msg.GetEvent() != nil && progress != nil {
+			progress(msg.GetEvent())
+		}
+		if msg.GetNetwork() != nil {
+			return msg.GetNetwork(), nil
+		}
+	}
+}
+
+// SetTags sets the tags in set and removes the tags in unset on the network name, then returns the resulting tags
+func (n *network) SetTags(name string, set map[string]string, unset []string, timeout time.Duration) (*pb.Tags, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.SetTags(ctx, &pb.TagsRequest{
+		Resource: &pb.Reference{Name: name},
+		Set:      set,
+		Unset:    unset,
+	})
+}
+
+// Adopt creates the metadata of the network 'id' of the provider, created outside of SafeScale
+func (n *network) Adopt(id string, timeout time.Duration) (*pb.Network, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Adopt(ctx, &pb.Reference{Id: id})
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+)
+
+// securityGroup is the part of safescale client handling security groups
+type securityGroup struct {
+	// session is not used currently
+	session *Session
+}
+
+// List ...
+func (sg *securityGroup) List(all bool, timeout time.Duration) (*pb.SecurityGroupList, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.List(ctx, &pb.SecurityGroupListRequest{All: all})
+}
+
+// Inspect ...
+func (sg *securityGroup) Inspect(name string, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.Reference{Name: name})
+}
+
+// Create ...
+func (sg *securityGroup) Create(def pb.SecurityGroupDefinition, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Create(ctx, &def)
+}
+
+// Delete ...
+func (sg *securityGroup) Delete(names []string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+		errs  []string
+	)
+
+	sgDeleter := func(aname string) {
+		defer wg.Done()
+		_, err := service.Delete(ctx, &pb.Reference{Name: aname})
+		if err != nil {
+			mutex.Lock()
+			errs = append(errs, err.Error())
+			mutex.Unlock()
+		}
+	}
+
+	wg.Add(len(names))
+	for _, target := range names {
+		go sgDeleter(target)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return clitools.ExitOnRPC(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// AddRule ...
+func (sg *securityGroup) AddRule(name string, rule pb.SecurityGroupRule, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.AddRule(ctx, &pb.SecurityGroupRuleRequest{
+		SecurityGroup: &pb.Reference{Name: name},
+		Rule:          &rule,
+	})
+}
+
+// DeleteRule ...
+func (sg *securityGroup) DeleteRule(name string, ruleID string, timeout time.Duration) (*pb.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.DeleteRule(ctx, &pb.SecurityGroupRuleDeleteRequest{
+		SecurityGroup: &pb.Reference{Name: name},
+		RuleId:        ruleID,
+	})
+}
+
+// Bind ...
+func (sg *securityGroup) Bind(name string, hostName string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Bind(ctx, &pb.SecurityGroupBond{
+		SecurityGroup: &pb.Reference{Name: name},
+		Host:          &pb.Reference{Name: hostName},
+	})
+	return err
+}
+
+// Unbind ...
+func (sg *securityGroup) Unbind(name string, hostName string, timeout time.Duration) error {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+	service := pb.NewSecurityGroupServiceClient(sg.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Unbind(ctx, &pb.SecurityGroupBond{
+		SecurityGroup: &pb.Reference{Name: name},
+		Host:          &pb.Reference{Name: hostName},
+	})
+	return err
+}
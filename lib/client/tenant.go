@@ -57,8 +57,22 @@ func (t *tenant) Get(timeout time.Duration) (*pb.TenantName, error) {
 	return service.Get(ctx, &googleprotobuf.Empty{})
 }
 
-// Set ...
-func (t *tenant) Set(name string, timeout time.Duration) error {
+// Inspect returns the provider of the tenant named name (the current tenant if name is empty), its rate limits and
+// the state of its circuit breaker
+func (t *tenant) Inspect(name string, timeout time.Duration) (*pb.TenantInspection, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.TenantName{Name: name})
+}
+
+// RefreshCache empties the cache of the provider of the tenant named name (the current tenant if name is empty)
+func (t *tenant) RefreshCache(name string, timeout time.Duration) error {
 	t.session.Connect()
 	defer t.session.Disconnect()
 	service := pb.NewTenantServiceClient(t.session.connection)
@@ -67,6 +81,39 @@ func (t *tenant) Set(name string, timeout time.Duration) error {
 		return err
 	}
 
-	_, err = service.Set(ctx, &pb.TenantName{Name: name})
+	_, err = service.RefreshCache(ctx, &pb.TenantName{Name: name})
 	return err
 }
+
+// Set checks the tenant can be used by safescaled, then records it as the tenant to target by default
+func (t *tenant) Set(name string, timeout time.Duration) error {
+	// The check must not depend on the current default tenant, which may not be usable anymore
+	session := (*Session)(NewWithTenant(name))
+	session.Connect()
+	defer session.Disconnect()
+	service := pb.NewTenantServiceClient(session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Set(ctx, &pb.TenantName{Name: name})
+	if err != nil {
+		return err
+	}
+	return SetDefaultTenant(name)
+}
+
+// Fsck cross-checks the metadata of the tenant named name (the current tenant if name is empty) with the resources
+// of its provider; if repair is true, the inconsistencies found are repaired
+func (t *tenant) Fsck(name string, repair bool, timeout time.Duration) (*pb.TenantFsckReport, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Fsck(ctx, &pb.TenantFsckRequest{Name: name, Repair: repair})
+}
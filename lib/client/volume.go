@@ -33,7 +33,7 @@ type volume struct {
 }
 
 // List ...
-func (v *volume) List(all bool, timeout time.Duration) (*pb.VolumeList, error) {
+func (v *volume) List(all bool, selector string, timeout time.Duration) (*pb.VolumeList, error) {
 	v.session.Connect()
 	defer v.session.Disconnect()
 	service := pb.NewVolumeServiceClient(v.session.connection)
@@ -42,7 +42,7 @@ func (v *volume) List(all bool, timeout time.Duration) (*pb.VolumeList, error) {
 		return nil, err
 	}
 
-	return service.List(ctx, &pb.VolumeListRequest{All: all})
+	return service.List(ctx, &pb.VolumeListRequest{All: all, Selector: selector})
 
 }
 
@@ -145,3 +145,115 @@ func (v *volume) Detach(volumeName string, hostName string, timeout time.Duratio
 	})
 	return err
 }
+
+// Resize extends the volume volumeName to size GB
+func (v *volume) Resize(volumeName string, size int32, timeout time.Duration) (*pb.Volume, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Resize(ctx, &pb.VolumeResizeRequest{
+		Volume: &pb.Reference{Name: volumeName},
+		Size:   size,
+	})
+}
+
+// CreateSnapshot ...
+func (v *volume) CreateSnapshot(def pb.VolumeSnapshotDefinition, timeout time.Duration) (*pb.VolumeSnapshot, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.CreateSnapshot(ctx, &def)
+}
+
+// ListSnapshots lists the snapshots of the volume volumeName, or all the snapshots if volumeName is empty
+func (v *volume) ListSnapshots(volumeName string, all bool, timeout time.Duration) (*pb.VolumeSnapshotList, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.VolumeSnapshotListRequest{All: all}
+	if volumeName != "" {
+		req.Volume = &pb.Reference{Name: volumeName}
+	}
+	return service.ListSnapshots(ctx, req)
+}
+
+// DeleteSnapshot ...
+func (v *volume) DeleteSnapshot(names []string, timeout time.Duration) error {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, name := range names {
+		_, err := service.DeleteSnapshot(ctx, &pb.Reference{Name: name})
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return clitools.ExitOnRPC(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// RestoreSnapshot ...
+func (v *volume) RestoreSnapshot(def pb.VolumeSnapshotRestoreRequest, timeout time.Duration) (*pb.Volume, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.RestoreSnapshot(ctx, &def)
+}
+
+// SetTags sets the tags in set and removes the tags in unset on the volume name, then returns the resulting tags
+func (v *volume) SetTags(name string, set map[string]string, unset []string, timeout time.Duration) (*pb.Tags, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.SetTags(ctx, &pb.TagsRequest{
+		Resource: &pb.Reference{Name: name},
+		Set:      set,
+		Unset:    unset,
+	})
+}
+
+// Adopt creates the metadata of the volume 'id' of the provider, created outside of SafeScale
+func (v *volume) Adopt(id string, timeout time.Duration) (*pb.Volume, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Adopt(ctx, &pb.Reference{Id: id})
+}
@@ -9,7 +9,6 @@ import (
 	"strings"
 
 	"github.com/coreos/go-oidc"
-	"github.com/gobwas/glob"
 	"github.com/gorilla/websocket"
 	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
@@ -79,33 +78,13 @@ func authenticate(token string) (string, int) {
 }
 
 func authorize(email, service, resource, method string) bool {
-
 	da := model.NewDataAccess(cfg.DatabaseDialect, cfg.DatabaseDSN)
-	srv, err := da.GetServiceByName(service)
-	if err != nil {
-		return false
-	}
-	if srv == nil {
-		return false
-	}
-
-	permissions, err := da.GetUserAccessPermissionsByService(email, service)
+	ok, err := da.IsAuthorized(email, service, resource, method)
 	if err != nil {
+		log.Error(err)
 		return false
 	}
-
-	for _, permission := range permissions {
-		pattern := permission.ResourcePattern
-		g, err := glob.Compile(pattern)
-		if err != nil {
-			continue
-		}
-		if g.Match(resource) && (permission.Action == method) || (permission.Action == "ALL") {
-			return true
-		}
-	}
-	return false
-
+	return ok
 }
 
 func getServiceURL(service, resource string) (*url.URL, error) {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/gobwas/glob"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mssql"    //Import gorm mssql driver
 	_ "github.com/jinzhu/gorm/dialects/mysql"    //Import gorm mysql driver
@@ -93,6 +94,36 @@ func (da *DataAccess) GetUserAccessPermissionsByService(email, serviceName strin
 	return permissions, err
 }
 
+//ActionAll is the action of an AccessPermission granting every action on the matching resources
+const ActionAll = "ALL"
+
+//IsAuthorized tells if the user identified by email may do action on resource offered by the service
+func (da *DataAccess) IsAuthorized(email, serviceName, resource, action string) (bool, error) {
+	srv, err := da.GetServiceByName(serviceName)
+	if err != nil {
+		return false, err
+	}
+	if srv == nil {
+		return false, nil
+	}
+
+	permissions, err := da.GetUserAccessPermissionsByService(email, serviceName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		g, err := glob.Compile(permission.ResourcePattern)
+		if err != nil {
+			continue
+		}
+		if g.Match(resource) && (permission.Action == action || permission.Action == ActionAll) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 //GetServiceByName get service by name
 func (da *DataAccess) GetServiceByName(name string) (srv *Service, err error) {
 	db, err := da.Get()
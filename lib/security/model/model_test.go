@@ -2,6 +2,9 @@ package model_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/CS-SI/SafeScale/lib/security/model"
@@ -64,3 +67,55 @@ func TestModel(t *testing.T) {
 	fmt.Println(perms)
 
 }
+
+func TestIsAuthorized(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-authorization")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	da := model.NewDataAccess("sqlite3", filepath.Join(dir, "authorization.db"))
+	assert.Nil(t, da.Init())
+	db, err := da.Get()
+	assert.Nil(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	srv := model.Service{Name: "safescaled"}
+	assert.Nil(t, db.Create(&srv).Error)
+	usr := model.User{Email: "ci@c-s.fr"}
+	assert.Nil(t, db.Create(&usr).Error)
+	perms := []model.AccessPermission{
+		{Action: "Create", ResourcePattern: "tenant/HostService/*"},
+		{Action: model.ActionAll, ResourcePattern: "*/*/ci-*"},
+	}
+	role := model.Role{Name: "CI"}
+	assert.Nil(t, db.Create(&role).Error)
+	for _, perm := range perms {
+		assert.Nil(t, db.Create(&perm).Error)
+		assert.Nil(t, db.Model(&role).Association("AccessPermissions").Append(perm).Error)
+	}
+	assert.Nil(t, db.Model(&srv).Association("Roles").Append(role).Error)
+	assert.Nil(t, db.Model(&usr).Association("Roles").Append(role).Error)
+
+	cases := []struct {
+		email, service, resource, action string
+		expected                         bool
+	}{
+		{"ci@c-s.fr", "safescaled", "tenant/HostService/myhost", "Create", true},
+		{"ci@c-s.fr", "safescaled", "tenant/HostService/myhost", "Delete", false},
+		{"ci@c-s.fr", "safescaled", "tenant/NetworkService/mynet", "Delete", false},
+		{"ci@c-s.fr", "safescaled", "other/NetworkService/ci-net", "Delete", true},
+		{"ci@c-s.fr", "unknown", "tenant/HostService/myhost", "Create", false},
+		{"other@c-s.fr", "safescaled", "tenant/HostService/myhost", "Create", false},
+	}
+	for _, c := range cases {
+		ok, err := da.IsAuthorized(c.email, c.service, c.resource, c.action)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, ok, "%s %s %s", c.email, c.resource, c.action)
+	}
+}
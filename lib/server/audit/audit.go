@@ -0,0 +1,318 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+const (
+	// OutcomeSuccess is the outcome of a call that succeeded
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of a call that failed
+	OutcomeFailure = "failure"
+	// OutcomeDenied is the outcome of a call refused to its caller
+	OutcomeDenied = "denied"
+
+	// BucketFolder is the folder of the metadata bucket of a tenant where records are copied
+	BucketFolder = "audit"
+
+	redacted = "<redacted>"
+)
+
+// Record describes a call to a mutating RPC of safescaled, or a call denied to its caller
+type Record struct {
+	Time       time.Time              `json:"time"`
+	Caller     string                 `json:"caller,omitempty"`
+	Internal   bool                   `json:"internal,omitempty"`
+	Tenant     string                 `json:"tenant,omitempty"`
+	RPC        string                 `json:"rpc"`
+	Resource   string                 `json:"resource,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	Error      string                 `json:"error,omitempty"`
+	Duration   string                 `json:"duration"`
+}
+
+// BucketProvider returns the bucket where the records of a tenant have to be copied, or nil
+type BucketProvider func(tenant string) objectstorage.Bucket
+
+// Recorder writes records in an append-only JSON-lines file and, optionally, in the metadata bucket of the tenant
+// The file is opened for each record, so it can be rotated by moving it (by logrotate for example)
+type Recorder struct {
+	path   string
+	bucket BucketProvider
+	// mutex serializes the writers; readers don't take it
+	mutex sync.Mutex
+}
+
+// NewRecorder creates a Recorder appending records to the file at path; if bucket is not nil, each record
+// is also written as an object in the bucket it returns
+func NewRecorder(path string, bucket BucketProvider) (*Recorder, error) {
+	if path == "" {
+		return nil, fmt.Errorf("invalid empty audit file path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create folder of audit file '%s': %v", path, err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file '%s': %v", path, err)
+	}
+	_ = f.Close()
+	return &Recorder{path: path, bucket: bucket}, nil
+}
+
+// Write appends a record to the audit file and copies it to the bucket of the tenant, if any
+func (r *Recorder) Write(rec Record) error {
+	content, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err == nil {
+		_, err = f.Write(append(content, '\n'))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	r.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to write in audit file '%s': %v", r.path, err)
+	}
+
+	if r.bucket != nil && rec.Tenant != "" {
+		go r.copyToBucket(rec, content)
+	}
+	return nil
+}
+
+// copyToBucket writes the record as an object in the bucket of its tenant
+func (r *Recorder) copyToBucket(rec Record, content []byte) {
+	bucket := r.bucket(rec.Tenant)
+	if bucket == nil {
+		return
+	}
+	path := fmt.Sprintf("%s/%s/%d-%s.json", BucketFolder, rec.Time.UTC().Format("2006-01-02"), rec.Time.UnixNano(), strings.Replace(rec.RPC, "/", ".", -1))
+	_, err := bucket.WriteObject(path, bytes.NewReader(content), int64(len(content)), nil)
+	if err != nil {
+		logrus.Warnf("failed to copy audit record to bucket '%s': %v", bucket.GetName(), err)
+	}
+}
+
+// List returns the records of the tenant written since the time given, optionally restricted to a resource;
+// an empty tenant selects all the tenants
+// The file is read without blocking the writers: a record being written at the end of the file is ignored
+func (r *Recorder) List(tenant string, since time.Time, resource string) ([]Record, error) {
+	f, err := os.Open(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit file '%s': %v", r.path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var records []Record
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				// a line without end is a record still being written
+				break
+			}
+			return nil, fmt.Errorf("failed to read audit file '%s': %v", r.path, err)
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			logrus.Warnf("ignoring invalid record in audit file '%s': %v", r.path, err)
+			continue
+		}
+		if rec.Time.Before(since) {
+			continue
+		}
+		if tenant != "" && rec.Tenant != tenant {
+			continue
+		}
+		if resource != "" && rec.Resource != resource {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Interceptors returns the interceptors recording the calls to mutating RPCs.
+// They must be chained after the interceptors of ServerSecurity (identifying the caller) and of tenant resolution,
+// and after srvutils.AsyncInterceptor to record the outcome of the background jobs rather than their submission.
+func (r *Recorder) Interceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	if r == nil {
+		return nil, nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !IsMutating(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		r.record(ctx, info.FullMethod, req, start, err)
+		return resp, err
+	}
+	stream := func(srv interface{}, serverStream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !IsMutating(info.FullMethod) {
+			return handler(srv, serverStream)
+		}
+		start := time.Now()
+		wrapped := &srvutils.RequestServerStream{ServerStream: serverStream}
+		err := handler(srv, wrapped)
+		r.record(serverStream.Context(), info.FullMethod, wrapped.Request, start, err)
+		return err
+	}
+	return unary, stream
+}
+
+// RecordDenial records a call denied by the access control, whether the RPC is mutating or not.
+// It is meant to be given to srvutils.Authorizer.OnDenial, whose interceptors run before the ones of the Recorder.
+func (r *Recorder) RecordDenial(ctx context.Context, fullMethod string, req interface{}, err error) {
+	if r == nil {
+		return
+	}
+	r.record(ctx, fullMethod, req, time.Now(), err)
+}
+
+// record builds the record of a call and writes it
+func (r *Recorder) record(ctx context.Context, fullMethod string, req interface{}, start time.Time, err error) {
+	service, method := srvutils.SplitMethod(fullMethod)
+	caller := srvutils.CallerFromContext(ctx)
+	rec := Record{
+		Time:       start,
+		Caller:     caller.Identity,
+		Internal:   caller.Internal,
+		Tenant:     srvutils.TenantFromContext(ctx),
+		RPC:        service + "/" + method,
+		Resource:   srvutils.ResourceName(req),
+		Parameters: Parameters(req),
+		Outcome:    OutcomeSuccess,
+		Duration:   time.Since(start).String(),
+	}
+	if err != nil {
+		rec.Outcome = OutcomeFailure
+		if status.Code(err) == codes.PermissionDenied {
+			rec.Outcome = OutcomeDenied
+		}
+		rec.Error = err.Error()
+	}
+	if werr := r.Write(rec); werr != nil {
+		logrus.Errorf("failed to record audit of '%s': %v", rec.RPC, werr)
+	}
+}
+
+// readOnlyPrefixes contains the prefixes of the names of the methods that do not modify anything
+var readOnlyPrefixes = []string{"List", "Inspect", "Get", "Status", "State", "Check", "SSH"}
+
+// IsMutating tells if the RPC designated by its full method name may modify resources
+func IsMutating(fullMethod string) bool {
+	service, method := srvutils.SplitMethod(fullMethod)
+	switch service {
+	case "AuditService":
+		return false
+	case "TenantService":
+		// TenantService.Set only checks the tenant, the default tenant is kept on the client side
+		return false
+	}
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// secretMarkers contains the parts of parameter names designating a secret
+var secretMarkers = []string{"password", "secret", "token", "privatekey", "private_key", "apikey", "api_key", "credential"}
+
+// Parameters returns the content of a request as a map, with the values of secrets redacted
+func Parameters(req interface{}) map[string]interface{} {
+	if req == nil {
+		return nil
+	}
+	content, err := json.Marshal(req)
+	if err != nil {
+		return nil
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(content, &params); err != nil {
+		return nil
+	}
+	redact(params)
+	return params
+}
+
+// redact replaces recursively the values of secrets
+func redact(params map[string]interface{}) {
+	for k, v := range params {
+		if isSecret(k) {
+			params[k] = redacted
+			continue
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			redact(value)
+		case []interface{}:
+			for _, item := range value {
+				if m, ok := item.(map[string]interface{}); ok {
+					redact(m)
+				}
+			}
+		}
+	}
+}
+
+// isSecret tells if a parameter name designates a secret
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range secretMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+)
+
+func TestIsMutating(t *testing.T) {
+	assert.True(t, IsMutating("/safescale.HostService/Create"))
+	assert.True(t, IsMutating("/safescale.NetworkService/Delete"))
+	assert.True(t, IsMutating("/safescale.ClusterService/Expand"))
+	assert.False(t, IsMutating("/safescale.HostService/Inspect"))
+	assert.False(t, IsMutating("/safescale.HostService/List"))
+	assert.False(t, IsMutating("/safescale.ClusterService/ListNodes"))
+	assert.False(t, IsMutating("/safescale.TenantService/Set"))
+	assert.False(t, IsMutating("/safescale.AuditService/List"))
+}
+
+func TestParameters(t *testing.T) {
+	params := Parameters(&pb.FeatureRequest{
+		Name:      "remotedesktop",
+		Target:    "mycluster",
+		Variables: map[string]string{"Username": "cladm", "Password": "secret"},
+	})
+	assert.Equal(t, "remotedesktop", params["name"])
+	variables, ok := params["variables"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "cladm", variables["Username"])
+	assert.Equal(t, redacted, variables["Password"])
+
+	assert.Nil(t, Parameters(nil))
+}
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	recorder, err := NewRecorder(filepath.Join(dir, "audit.log"), nil)
+	require.Nil(t, err)
+
+	now := time.Now()
+	records := []Record{
+		{Time: now.Add(-48 * time.Hour), Tenant: "tenant", RPC: "NetworkService/Delete", Resource: "net-prod", Outcome: OutcomeSuccess},
+		{Time: now.Add(-time.Hour), Tenant: "tenant", RPC: "HostService/Delete", Resource: "gw-net-prod", Outcome: OutcomeSuccess},
+		{Time: now.Add(-time.Hour), Tenant: "other", RPC: "HostService/Delete", Resource: "gw-net-prod", Outcome: OutcomeFailure},
+	}
+	for _, r := range records {
+		require.Nil(t, recorder.Write(r))
+	}
+
+	found, err := recorder.List("tenant", now.Add(-24*time.Hour), "")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "gw-net-prod", found[0].Resource)
+
+	found, err = recorder.List("", time.Time{}, "gw-net-prod")
+	require.Nil(t, err)
+	assert.Len(t, found, 2)
+}
+
+func TestRecorder_ListIgnoresRecordBeingWritten(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "audit.log")
+	recorder, err := NewRecorder(path, nil)
+	require.Nil(t, err)
+	require.Nil(t, recorder.Write(Record{Time: time.Now(), Tenant: "tenant", RPC: "HostService/Delete", Resource: "host", Outcome: OutcomeSuccess}))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.Nil(t, err)
+	_, err = f.WriteString(`{"tenant":"tenant","rpc":"HostService/Cre`)
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+
+	// the writer mutex is held, List must not wait for it
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	found, err := recorder.List("", time.Time{}, "")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "host", found[0].Resource)
+}
+
+// requestStream is a grpc.ServerStream receiving a host definition named name
+type requestStream struct {
+	grpc.ServerStream
+	name string
+}
+
+func (s *requestStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *requestStream) RecvMsg(m interface{}) error {
+	m.(*pb.HostDefinition).Name = s.name
+	return nil
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	recorder, err := NewRecorder(filepath.Join(dir, "audit.log"), nil)
+	require.Nil(t, err)
+
+	_, stream := recorder.Interceptors()
+	info := &grpc.StreamServerInfo{FullMethod: "/safescale.HostService/CreateWithProgress", IsServerStream: true}
+	err = stream(nil, &requestStream{name: "ci-host"}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return ss.RecvMsg(&pb.HostDefinition{})
+	})
+	require.Nil(t, err)
+
+	found, err := recorder.List("", time.Time{}, "ci-host")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "HostService/CreateWithProgress", found[0].RPC)
+	assert.Equal(t, "ci-host", found[0].Parameters["name"])
+}
+
+func TestRecordDenial(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	recorder, err := NewRecorder(filepath.Join(dir, "audit.log"), nil)
+	require.Nil(t, err)
+
+	denial := status.Errorf(codes.PermissionDenied, "'dev' is not allowed to call 'HostService/Inspect' on 'tenant/HostService/prod-host'")
+	recorder.RecordDenial(context.Background(), "/safescale.HostService/Inspect", &pb.Reference{Name: "prod-host"}, denial)
+
+	found, err := recorder.List("", time.Time{}, "prod-host")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, OutcomeDenied, found[0].Outcome)
+	assert.Equal(t, "HostService/Inspect", found[0].RPC)
+
+	var nilRecorder *Recorder
+	nilRecorder.RecordDenial(context.Background(), "/safescale.HostService/Inspect", nil, denial)
+}
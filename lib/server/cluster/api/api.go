@@ -81,6 +81,11 @@ type Cluster interface {
 	// CountNodes counts the nodes of the cluster
 	CountNodes(concurrency.Task) (uint, error)
 
+	// GetTags returns the tags of the cluster
+	GetTags(concurrency.Task) (map[string]string, error)
+	// SetTags sets and removes tags of the cluster, and returns the resulting tags
+	SetTags(concurrency.Task, map[string]string, []string) (map[string]string, error)
+
 	// Delete allows to destroy infrastructure of cluster
 	Delete(concurrency.Task) error
 }
@@ -130,6 +130,18 @@ func (c *Controller) Create(task concurrency.Task, req Request, f Foreman) (err
 	}
 	// ENDVPL
 
+	err = c.Properties.LockForWrite(property.TagsV1).ThenUse(func(clonable data.Clonable) error {
+		tagsV1 := clonable.(*clusterpropsv1.Tags)
+		for k, v := range req.Tags {
+			tagsV1.Tags[k] = v
+		}
+		return nil
+	})
+	if err != nil {
+		c.Unlock(task)
+		return err
+	}
+
 	c.foreman = f.(*foreman)
 	c.Unlock(task)
 	return c.foreman.construct(task, req)
@@ -169,6 +181,23 @@ func (c *Controller) GetIdentity(task concurrency.Task) identity.Identity {
 	return c.Identity
 }
 
+// safescale returns a safescale client targeting the tenant of the cluster
+func (c *Controller) safescale(task concurrency.Task) client.Client {
+	var tenant string
+	if c.Properties != nil {
+		_ = c.Properties.LockForRead(property.CompositeV1).ThenUse(func(clonable data.Clonable) error {
+			if tenants := clonable.(*clusterpropsv1.Composite).Tenants; len(tenants) > 0 {
+				tenant = tenants[0]
+			}
+			return nil
+		})
+	}
+	if tenant != "" {
+		return client.NewWithTenant(tenant)
+	}
+	return client.FromTask(task)
+}
+
 // GetProperties returns the properties of the cluster
 func (c *Controller) GetProperties(task concurrency.Task) *serialize.JSONProperties {
 	if task == nil {
@@ -249,6 +278,62 @@ func (c *Controller) CountNodes(task concurrency.Task) (_ uint, err error) {
 	return count, nil
 }
 
+// GetTags returns the tags of the Cluster
+func (c *Controller) GetTags(task concurrency.Task) (_ map[string]string, err error) {
+	if c == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if task == nil {
+		task = concurrency.RootTask()
+	}
+
+	defer scerr.OnExitLogError(concurrency.NewTracer(task, "", false).TraceMessage(""), &err)()
+
+	tags := map[string]string{}
+	c.RLock(task)
+	defer c.RUnlock(task)
+	if !c.Properties.Lookup(property.TagsV1) {
+		return tags, nil
+	}
+	err = c.Properties.LockForRead(property.TagsV1).ThenUse(func(clonable data.Clonable) error {
+		for k, v := range clonable.(*clusterpropsv1.Tags).Tags {
+			tags[k] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// SetTags sets the tags in set and removes the tags in unset, then returns the resulting tags of the Cluster
+func (c *Controller) SetTags(task concurrency.Task, set map[string]string, unset []string) (tags map[string]string, err error) {
+	if c == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if task == nil {
+		task = concurrency.RootTask()
+	}
+
+	tracer := concurrency.NewTracer(task, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = c.UpdateMetadata(task, func() error {
+		return c.Properties.LockForWrite(property.TagsV1).ThenUse(func(clonable data.Clonable) error {
+			tagsV1 := clonable.(*clusterpropsv1.Tags)
+			tagsV1.Tags = resources.MergeTags(tagsV1.Tags, set, unset)
+			tags = resources.MergeTags(tagsV1.Tags, nil, nil)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
 // ListMasters lists the names of the master nodes in the Cluster
 func (c *Controller) ListMasters(task concurrency.Task) []*clusterpropsv1.Node {
 	if task == nil {
@@ -453,7 +538,7 @@ func (c *Controller) GetNode(task concurrency.Task, hostID string) (host *pb.Hos
 	if !found {
 		return nil, fmt.Errorf("failed to find node '%s' in Cluster '%s'", hostID, c.Name)
 	}
-	return client.New().Host.Inspect(hostID, temporal.GetExecutionTimeout())
+	return c.safescale(task).Host.Inspect(hostID, temporal.GetExecutionTimeout())
 }
 
 // SearchNode tells if an host ID corresponds to a node of the Cluster
@@ -484,7 +569,7 @@ func (c *Controller) FindAvailableMaster(task concurrency.Task) (result string,
 
 	masterID := ""
 	found := false
-	clientHost := client.New().Host
+	clientHost := c.safescale(task).Host
 	masterIDs := c.ListMasterIDs(task)
 
 	var lastError error
@@ -525,7 +610,7 @@ func (c *Controller) FindAvailableNode(task concurrency.Task) (id string, err er
 
 	hostID := ""
 	found := false
-	clientHost := client.New().Host
+	clientHost := c.safescale(task).Host
 	var lastError error
 	list := c.ListNodeIDs(task)
 	for _, hostID = range list {
@@ -568,10 +653,47 @@ func (c *Controller) UpdateMetadata(task concurrency.Task, updatefn func() error
 	c.Lock(task)
 	defer c.Unlock(task)
 
-	c.metadata.Acquire()
+	// The name of the cluster is needed to lock its metadata, so carry the controller if nothing has been read yet
+	if !c.metadata.Written() {
+		c.metadata.Carry(task, c)
+	}
+	err = c.metadata.Acquire()
+	if err != nil {
+		return err
+	}
 	defer c.metadata.Release()
 
-	err = c.metadata.Reload(task)
+	// If the metadata has been changed by someone else since it has been read, reloads it and applies updatefn again
+	retryErr := retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			innerErr := c.updateMetadata(task, updatefn)
+			if innerErr != nil {
+				if _, ok := innerErr.(scerr.ErrConflict); ok {
+					log.Warnf("cluster metadata has been changed concurrently, retrying update: %v", innerErr)
+					return innerErr
+				}
+				return retry.AbortedError("", innerErr)
+			}
+			return nil
+		},
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		switch realErr := retryErr.(type) {
+		case retry.ErrAborted:
+			return realErr.Cause()
+		case scerr.ErrTimeout:
+			return realErr
+		default:
+			return scerr.Cause(realErr)
+		}
+	}
+	return nil
+}
+
+// updateMetadata reloads the metadata, applies updatefn and writes the result
+func (c *Controller) updateMetadata(task concurrency.Task, updatefn func() error) error {
+	err := c.metadata.Reload(task)
 	if err != nil {
 		return err
 	}
@@ -610,7 +732,13 @@ func (c *Controller) DeleteMetadata(task concurrency.Task) (err error) {
 	c.Lock(task)
 	defer c.Unlock(task)
 
-	c.metadata.Acquire()
+	if !c.metadata.Written() {
+		c.metadata.Carry(task, c)
+	}
+	err = c.metadata.Acquire()
+	if err != nil {
+		return err
+	}
 	defer c.metadata.Release()
 
 	return c.metadata.Delete()
@@ -966,7 +1094,7 @@ func (c *Controller) deleteMaster(task concurrency.Task, hostID string) (err err
 	}()
 
 	// Finally delete host
-	err = client.New().Host.Delete([]string{master.ID}, temporal.GetLongOperationTimeout())
+	err = c.safescale(task).Host.Delete([]string{master.ID}, temporal.GetLongOperationTimeout())
 	if err != nil {
 		return err
 	}
@@ -1129,7 +1257,7 @@ func (c *Controller) deleteNode(task concurrency.Task, node *clusterpropsv1.Node
 	}
 
 	// Finally delete host
-	err = client.New().Host.Delete([]string{node.ID}, temporal.GetLongOperationTimeout())
+	err = c.safescale(task).Host.Delete([]string{node.ID}, temporal.GetLongOperationTimeout())
 	if err != nil {
 		if _, ok := err.(scerr.ErrNotFound); ok {
 			// host seems already deleted, so it's a success :-)
@@ -42,7 +42,6 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/nodetype"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
-	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/server/install"
 	providermetadata "github.com/CS-SI/SafeScale/lib/server/metadata"
@@ -108,7 +107,7 @@ type Makers struct {
 // Foreman interface, exposes public method
 type Foreman interface {
 	Cluster() api.Cluster
-	ExecuteScript(*rice.Box, map[string]interface{}, string, map[string]interface{}, string) (int, string, string, error)
+	ExecuteScript(concurrency.Task, *rice.Box, map[string]interface{}, string, map[string]interface{}, string) (int, string, string, error)
 }
 
 // foreman is the private side of Foreman...
@@ -132,11 +131,11 @@ func (b *foreman) Cluster() api.Cluster {
 
 // ExecuteScript executes the script template with the parameters on tarGetHost
 func (b *foreman) ExecuteScript(
-	box *rice.Box, funcMap map[string]interface{}, tmplName string, data map[string]interface{},
+	task concurrency.Task, box *rice.Box, funcMap map[string]interface{}, tmplName string, data map[string]interface{},
 	hostID string,
 ) (errCode int, stdOut string, stdErr string, err error) {
 
-	tracer := concurrency.NewTracer(nil, "("+hostID+")", true).GoingIn()
+	tracer := concurrency.NewTracer(task, "("+hostID+")", true).GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
@@ -152,7 +151,7 @@ func (b *foreman) ExecuteScript(
 	data["TemplateLongOperationTimeout"] = strings.Replace(temporal.GetHostTimeout().Truncate(time.Minute).String(), "0s", "", -1)
 	data["TemplatePullImagesTimeout"] = strings.Replace((2 * temporal.GetHostTimeout()).Truncate(time.Minute).String(), "0s", "", -1)
 
-	path, err := uploadTemplateToFile(box, funcMap, tmplName, data, hostID, tmplName)
+	path, err := uploadTemplateToFile(task, box, funcMap, tmplName, data, hostID, tmplName)
 	if err != nil {
 		return 0, "", "", err
 	}
@@ -160,7 +159,7 @@ func (b *foreman) ExecuteScript(
 	// cmd = fmt.Sprintf("sudo bash %s; rc=$?; if [[ rc -eq 0 ]]; then rm %s; fi; exit $rc", path, path)
 	cmd := fmt.Sprintf("sudo bash %s; rc=$?; exit $rc", path)
 
-	return client.New().SSH.Run(hostID, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), 2*temporal.GetLongOperationTimeout())
+	return b.cluster.safescale(task).SSH.Run(hostID, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), 2*temporal.GetLongOperationTimeout())
 }
 
 // construct ...
@@ -278,15 +277,8 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 	nodesDef := complementHostDefinition(req.NodesDef, *nodesDefault)
 
 	// Initialize service to use
-	clientInstance := client.New()
-	tenant, err := clientInstance.Tenant.Get(temporal.GetExecutionTimeout())
-	if err != nil {
-		return err
-	}
-	svc, err := iaas.UseService(tenant.Name)
-	if err != nil {
-		return err
-	}
+	clientInstance := client.NewWithTenant(req.Tenant)
+	svc := b.cluster.service
 
 	// Determine if Gateway Failover must be set
 	caps := svc.GetCapabilities()
@@ -538,7 +530,7 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 	// Starting from here, delete masters if exiting with error and req.KeepOnFailure is not true
 	defer func() {
 		if err != nil && !req.KeepOnFailure {
-			derr := client.New().Host.Delete(b.cluster.ListMasterIDs(task), temporal.GetExecutionTimeout())
+			derr := b.cluster.safescale(task).Host.Delete(b.cluster.ListMasterIDs(task), temporal.GetExecutionTimeout())
 			if derr != nil {
 				err = scerr.AddConsequence(err, derr)
 			}
@@ -744,7 +736,7 @@ func (b *foreman) destruct(task concurrency.Task) (err error) {
 	}
 
 	// Deletes the network
-	clientNetwork := client.New().Network
+	clientNetwork := b.cluster.safescale(task).Network
 	retryErr := retry.WhileUnsuccessfulDelay5SecondsTimeout(
 		func() error {
 			return clientNetwork.Delete([]string{networkID}, temporal.GetExecutionTimeout())
@@ -850,7 +842,7 @@ func (b *foreman) configureNode(task concurrency.Task, index int, pbHost *pb.Hos
 
 // unconfigureNode executes what has to be done to remove node from cluster
 func (b *foreman) unconfigureNode(task concurrency.Task, hostID string, selectedMasterID string) error {
-	pbHost, err := client.New().Host.Inspect(hostID, temporal.GetExecutionTimeout())
+	pbHost, err := b.cluster.safescale(task).Host.Inspect(hostID, temporal.GetExecutionTimeout())
 	if err != nil {
 		return err
 	}
@@ -886,6 +878,9 @@ func (b *foreman) configureCluster(task concurrency.Task, params concurrency.Tas
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
+	phaseDone := srvutils.StartPhase(task.GetContext(), "configure-cluster", b.cluster.Name)
+	defer func() { phaseDone(err) }()
+
 	logrus.Infof("[cluster %s] configuring cluster...", b.cluster.Name)
 	defer func() {
 		if err == nil {
@@ -990,7 +985,7 @@ func (b *foreman) createSwarm(task concurrency.Task, params concurrency.TaskPara
 		logrus.Debugf("secondary gateway not configured")
 	}
 
-	clientInstance := client.New()
+	clientInstance := b.cluster.safescale(task)
 	clientHost := clientInstance.Host
 	clientSSH := clientInstance.SSH
 
@@ -1084,7 +1079,7 @@ func (b *foreman) createSwarm(task concurrency.Task, params concurrency.TaskPara
 
 // getSwarmJoinCommand builds the command to obtain swarm token
 func (b *foreman) getSwarmJoinCommand(task concurrency.Task, selectedMaster *pb.Host, worker bool) (string, error) {
-	clientInstance := client.New()
+	clientInstance := b.cluster.safescale(task)
 	var memberType string
 	if worker {
 		memberType = "worker"
@@ -1102,14 +1097,14 @@ func (b *foreman) getSwarmJoinCommand(task concurrency.Task, selectedMaster *pb.
 
 // uploadTemplateToFile uploads a template named 'tmplName' coming from rice 'box' in a file to a remote host
 func uploadTemplateToFile(
-	box *rice.Box, funcMap map[string]interface{}, tmplName string, data map[string]interface{},
+	task concurrency.Task, box *rice.Box, funcMap map[string]interface{}, tmplName string, data map[string]interface{},
 	hostID string, fileName string,
 ) (string, error) {
 
 	if box == nil {
 		return "", scerr.InvalidParameterError("box", "cannot be nil!")
 	}
-	host, err := client.New().Host.Inspect(hostID, temporal.GetExecutionTimeout())
+	host, err := client.FromTask(task).Host.Inspect(hostID, temporal.GetExecutionTimeout())
 	if err != nil {
 		return "", fmt.Errorf("failed to get host information: %s", err)
 	}
@@ -1138,7 +1133,7 @@ func uploadTemplateToFile(
 	cmd := dataBuffer.String()
 	remotePath := utils.TempFolder + "/" + fileName
 
-	err = install.UploadStringToRemoteFile(cmd, host, remotePath, "", "", "")
+	err = install.UploadStringToRemoteFile(task, cmd, host, remotePath, "", "", "")
 	if err != nil {
 		return "", err
 	}
@@ -1158,7 +1153,7 @@ func (b *foreman) configureNodesFromList(task concurrency.Task, hosts []string)
 	)
 
 	var subtasks []concurrency.Task
-	clientHost := client.New().Host
+	clientHost := b.cluster.safescale(task).Host
 	length := len(hosts)
 	for i := 0; i < length; i++ {
 		host, err = clientHost.Inspect(hosts[i], temporal.GetExecutionTimeout())
@@ -1208,7 +1203,7 @@ func (b *foreman) joinNodesFromList(task concurrency.Task, hosts []string) error
 
 	logrus.Debugf("Joining nodes to cluster...")
 
-	clientInstance := client.New()
+	clientInstance := b.cluster.safescale(task)
 	clientHost := clientInstance.Host
 	clientSSH := clientInstance.SSH
 
@@ -1262,7 +1257,7 @@ func (b *foreman) leaveMastersFromList(task concurrency.Task, public bool, hosts
 
 	logrus.Debugf("Making Masters leaving cluster...")
 
-	clientHost := client.New().Host
+	clientHost := b.cluster.safescale(task).Host
 	// Joins to cluster is done sequentially, experience shows too many join at the same time
 	// may fail (depending of the cluster Flavor)
 	for _, hostID := range hosts {
@@ -1288,7 +1283,7 @@ func (b *foreman) leaveNodesFromList(task concurrency.Task, hosts []string, sele
 		return err
 	}
 
-	clientHost := client.New().Host
+	clientHost := b.cluster.safescale(task).Host
 
 	// Unjoins from cluster are done sequentially, experience shows too many join at the same time
 	// may fail (depending of the cluster Flavor)
@@ -1331,7 +1326,7 @@ func (b *foreman) leaveNodeFromSwarm(task concurrency.Task, pbHost *pb.Host, sel
 		}
 	}
 
-	clientSSH := client.New().SSH
+	clientSSH := b.cluster.safescale(task).SSH
 
 	// Check worker is member of the Swarm
 	cmd := fmt.Sprintf("docker node ls --format \"{{.Hostname}}\" --filter \"name=%s\" | grep -i %s", pbHost.Name, pbHost.Name)
@@ -1465,7 +1460,7 @@ func (b *foreman) installNodeRequirements(task concurrency.Task, nodeType nodety
 				return fmt.Errorf(msg)
 			}
 		}
-		err = install.UploadFile(path, pbHost, utils.BinFolder+"/safescale", "root", "root", "0755")
+		err = install.UploadFile(task, path, pbHost, utils.BinFolder+"/safescale", "root", "root", "0755")
 		if err != nil {
 			logrus.Errorf("failed to upload 'safescale' binary")
 			return fmt.Errorf("failed to upload 'safescale' binary': %s", err.Error())
@@ -1492,7 +1487,7 @@ func (b *foreman) installNodeRequirements(task concurrency.Task, nodeType nodety
 				return fmt.Errorf(msg)
 			}
 		}
-		err = install.UploadFile(path, pbHost, "/opt/safescale/bin/safescaled", "root", "root", "0755")
+		err = install.UploadFile(task, path, pbHost, "/opt/safescale/bin/safescaled", "root", "root", "0755")
 		if err != nil {
 			logrus.Errorf("failed to upload 'safescaled' binary")
 			return fmt.Errorf("failed to upload 'safescaled' binary': %s", err.Error())
@@ -1503,7 +1498,7 @@ func (b *foreman) installNodeRequirements(task concurrency.Task, nodeType nodety
 		if suffix != "" {
 			cmdTmpl := "sudo sed -i '/^SAFESCALE_METADATA_SUFFIX=/{h;s/=.*/=%s/};${x;/^$/{s//SAFESCALE_METADATA_SUFFIX=%s/;H};x}' /etc/environment"
 			cmd := fmt.Sprintf(cmdTmpl, suffix, suffix)
-			retcode, stdout, stderr, err := client.New().SSH.Run(pbHost.Id, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, 2*temporal.GetLongOperationTimeout())
+			retcode, stdout, stderr, err := b.cluster.safescale(task).SSH.Run(pbHost.Id, cmd, outputs.COLLECT, client.DefaultConnectionTimeout, 2*temporal.GetLongOperationTimeout())
 			if err != nil {
 				msg := fmt.Sprintf("failed to submit content of SAFESCALE_METADATA_SUFFIX to host '%s': %s", pbHost.Name, err.Error())
 				logrus.Errorf(utils.Capitalize(msg))
@@ -1545,7 +1540,7 @@ func (b *foreman) installNodeRequirements(task concurrency.Task, nodeType nodety
 	if netCfg.SecondaryGatewayIP != "" {
 		params["SecondaryGatewayIP"] = netCfg.SecondaryGatewayIP
 	}
-	retcode, _, _, err := b.ExecuteScript(box, funcMap, script, params, pbHost.Id)
+	retcode, _, _, err := b.ExecuteScript(task, box, funcMap, script, params, pbHost.Id)
 	if err != nil {
 		return err
 	}
@@ -1587,9 +1582,11 @@ func (b *foreman) taskInstallGateway(t concurrency.Task, params concurrency.Task
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	hostLabel := pbGateway.Name
+	phaseDone := srvutils.StartPhase(t.GetContext(), "install-gateway", hostLabel)
+	defer func() { phaseDone(err) }()
 	logrus.Debugf("[%s] starting installation...", hostLabel)
 
-	sshCfg, err := client.New().Host.SSHConfig(pbGateway.Id)
+	sshCfg, err := b.cluster.safescale(t).Host.SSHConfig(pbGateway.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -1636,6 +1633,9 @@ func (b *foreman) taskConfigureGateway(t concurrency.Task, params concurrency.Ta
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
+	phaseDone := srvutils.StartPhase(t.GetContext(), "configure-gateway", gw.Name)
+	defer func() { phaseDone(err) }()
+
 	logrus.Debugf("[%s] starting configuration...", gw.Name)
 
 	// First install ntp client
@@ -1722,6 +1722,8 @@ func (b *foreman) taskCreateMaster(t concurrency.Task, params concurrency.TaskPa
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	hostLabel := fmt.Sprintf("master #%d", index)
+	phaseDone := srvutils.StartPhase(t.GetContext(), "create-master", hostLabel)
+	defer func() { phaseDone(err) }()
 	logrus.Debugf("[%s] starting host resource creation...", hostLabel)
 
 	netCfg, err := b.cluster.GetNetworkConfig(t)
@@ -1737,7 +1739,7 @@ func (b *foreman) taskCreateMaster(t concurrency.Task, params concurrency.TaskPa
 
 	hostDef.Network = netCfg.NetworkID
 	hostDef.Public = false
-	clientHost := client.New().Host
+	clientHost := b.cluster.safescale(t).Host
 	pbHost, err := clientHost.Create(hostDef, timeout)
 	if pbHost != nil {
 		// Updates cluster metadata to keep track of created host, before testing if an error occurred during the creation
@@ -1821,7 +1823,7 @@ func (b *foreman) taskConfigureMasters(t concurrency.Task, params concurrency.Ta
 	logrus.Debugf("[cluster %s] Configuring masters...", b.cluster.Name)
 	started := time.Now()
 
-	clientHost := client.New().Host
+	clientHost := b.cluster.safescale(t).Host
 	var subtasks []concurrency.Task
 	for i, hostID := range b.cluster.ListMasterIDs(t) {
 		host, err := clientHost.Inspect(hostID, temporal.GetExecutionTimeout())
@@ -1884,6 +1886,8 @@ func (b *foreman) taskConfigureMaster(t concurrency.Task, params concurrency.Tas
 	started := time.Now()
 
 	hostLabel := fmt.Sprintf("master #%d (%s)", index, pbHost.Name)
+	phaseDone := srvutils.StartPhase(t.GetContext(), "configure-master", hostLabel)
+	defer func() { phaseDone(err) }()
 	logrus.Debugf("[%s] starting configuration...\n", hostLabel)
 
 	// install docker and docker-compose feature
@@ -2027,6 +2031,8 @@ func (b *foreman) taskCreateNode(t concurrency.Task, params concurrency.TaskPara
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	hostLabel := fmt.Sprintf("node #%d", index)
+	phaseDone := srvutils.StartPhase(t.GetContext(), "create-node", hostLabel)
+	defer func() { phaseDone(err) }()
 	logrus.Debugf("[%s] starting host resource creation...", hostLabel)
 
 	netCfg, err := b.cluster.GetNetworkConfig(t)
@@ -2045,7 +2051,7 @@ func (b *foreman) taskCreateNode(t concurrency.Task, params concurrency.TaskPara
 		timeout = temporal.GetLongOperationTimeout()
 	}
 
-	clientHost := client.New().Host
+	clientHost := b.cluster.safescale(t).Host
 	var node *clusterpropsv1.Node
 	pbHost, err := clientHost.Create(hostDef, timeout)
 	if pbHost != nil {
@@ -2130,7 +2136,7 @@ func (b *foreman) taskConfigureNodes(t concurrency.Task, params concurrency.Task
 	)
 
 	var subtasks []concurrency.Task
-	clientHost := client.New().Host
+	clientHost := b.cluster.safescale(t).Host
 	for i, hostID = range list {
 		pbHost, err = clientHost.Inspect(hostID, temporal.GetExecutionTimeout())
 		if err != nil {
@@ -2181,6 +2187,8 @@ func (b *foreman) taskConfigureNode(t concurrency.Task, params concurrency.TaskP
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
 	hostLabel := fmt.Sprintf("node #%d (%s)", index, pbHost.Name)
+	phaseDone := srvutils.StartPhase(t.GetContext(), "configure-node", hostLabel)
+	defer func() { phaseDone(err) }()
 	logrus.Debugf("[%s] starting configuration...", hostLabel)
 
 	// Docker and docker-compose installation is mandatory on all nodes
@@ -152,9 +152,13 @@ func (m *Metadata) Reload(task concurrency.Task) error {
 		return scerr.InvalidParameterError("m.item", "cannot be nil")
 	}
 
-	// If the metadata object has never been written yet, succeed doing nothing
+	// If the metadata object has never been read nor written, it may have been created by someone else in the meantime
 	if !m.item.Written() {
-		return nil
+		err := m.Read(task, m.name)
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil
+		}
+		return err
 	}
 
 	// Metadata had been written at least once, so try to reload (and propagate failure if it occurs)
@@ -242,10 +246,10 @@ func (m *Metadata) Browse(callback func(*Controller) error) error {
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (m *Metadata) Acquire() {
+func (m *Metadata) Acquire() error {
 	// m.lock.Lock()
 	// defer m.lock.Unlock()
-	m.item.Acquire()
+	return m.item.Acquire(m.name)
 }
 
 // Release unlocks the metadata
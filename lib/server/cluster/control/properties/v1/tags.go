@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// Tags contains the user-defined key/value tags of the cluster
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type Tags struct {
+	// Tags contains the tags, indexed on key
+	Tags map[string]string `json:"tags"`
+}
+
+func newTags() *Tags {
+	return &Tags{
+		Tags: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (t *Tags) Content() data.Clonable {
+	return t
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (t *Tags) Clone() data.Clonable {
+	return newTags().Replace(t)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (t *Tags) Replace(p data.Clonable) data.Clonable {
+	src := p.(*Tags)
+	t.Tags = make(map[string]string, len(src.Tags))
+	for k, v := range src.Tags {
+		t.Tags[k] = v
+	}
+	return t
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("clusters", property.TagsV1, newTags())
+}
@@ -0,0 +1,26 @@
+package propertiesv1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestTags_Clone(t *testing.T) {
+	ct := newTags()
+	ct.Tags["env"] = "prod"
+
+	clonedCt, ok := ct.Clone().(*Tags)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.Tags["env"] = "dev"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
@@ -46,4 +46,6 @@ type Request struct {
 	NodesDef *pb.HostDefinition
 	// DisabledDefaultFeatures contains the list of features that should be installed by default but we don't want actually
 	DisabledDefaultFeatures map[string]struct{}
+	// Tags contains the user-defined key/value tags of the cluster
+	Tags map[string]string
 }
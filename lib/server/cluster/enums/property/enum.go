@@ -40,4 +40,6 @@ const (
 	NetworkV2 = "10"
 	// ControlPlaneV1 contains optional additional info about Control Plane of the cluster
 	ControlPlaneV1 = "11"
+	// TagsV1 contains the user-defined key/value tags of the cluster
+	TagsV1 = "12"
 )
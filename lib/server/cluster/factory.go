@@ -35,15 +35,19 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
-// Load ...
+// Load loads the cluster named 'name' from the default tenant of the client
 func Load(task concurrency.Task, name string) (api.Cluster, error) {
-	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
+	svc, err := currentService()
 	if err != nil {
 		return nil, err
 	}
-	svc, err := iaas.UseService(tenant.Name)
-	if err != nil {
-		return nil, err
+	return LoadWithService(task, svc, name)
+}
+
+// LoadWithService loads the cluster named 'name' using the service passed as parameter
+func LoadWithService(task concurrency.Task, svc iaas.Service, name string) (api.Cluster, error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
 	}
 
 	m, err := control.NewMetadata(svc)
@@ -68,6 +72,15 @@ func Load(task concurrency.Task, name string) (api.Cluster, error) {
 	return controller, nil
 }
 
+// currentService returns the iaas.Service of the default tenant of the client
+func currentService() (iaas.Service, error) {
+	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
+	if err != nil {
+		return nil, err
+	}
+	return iaas.UseService(tenant.Name)
+}
+
 func setForeman(task concurrency.Task, controller *control.Controller) error {
 	f := controller.GetIdentity(task).Flavor
 	switch f {
@@ -86,8 +99,22 @@ func setForeman(task concurrency.Task, controller *control.Controller) error {
 	}
 }
 
-// Create creates a cluster following the parameters of the request
-func Create(task concurrency.Task, req control.Request) (_ api.Cluster, err error) {
+// Create creates a cluster following the parameters of the request, in the default tenant of the client
+func Create(task concurrency.Task, req control.Request) (api.Cluster, error) {
+	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
+	if err != nil {
+		return nil, err
+	}
+	svc, err := iaas.UseService(tenant.Name)
+	if err != nil {
+		return nil, err
+	}
+	req.Tenant = tenant.Name
+	return CreateWithService(task, svc, req)
+}
+
+// CreateWithService creates a cluster following the parameters of the request, using the service passed as parameter
+func CreateWithService(task concurrency.Task, svc iaas.Service, req control.Request) (_ api.Cluster, err error) {
 	tracer := concurrency.NewTracer(task, "", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
@@ -100,22 +127,19 @@ func Create(task concurrency.Task, req control.Request) (_ api.Cluster, err erro
 		return nil, scerr.InvalidParameterError("req.CIDR", "cannot be empty!")
 	}
 
-	log.Infof("Creating infrastructure for cluster '%s'", req.Name)
-
-	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
-	if err != nil {
-		return nil, err
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
 	}
-	svc, err := iaas.UseService(tenant.Name)
-	if err != nil {
-		return nil, err
+	if req.Tenant == "" {
+		return nil, scerr.InvalidParameterError("req.Tenant", "cannot be empty!")
 	}
 
+	log.Infof("Creating infrastructure for cluster '%s'", req.Name)
+
 	controller, err := control.NewController(svc)
 	if err != nil {
 		return nil, err
 	}
-	req.Tenant = tenant.Name
 	switch req.Flavor {
 	case flavor.BOH:
 		err = controller.Create(task, req, control.NewForeman(controller, boh.Makers))
@@ -152,7 +176,16 @@ func Create(task concurrency.Task, req control.Request) (_ api.Cluster, err erro
 
 // Delete deletes the infrastructure of the cluster named 'name'
 func Delete(task concurrency.Task, name string) error {
-	instance, err := Load(task, name)
+	svc, err := currentService()
+	if err != nil {
+		return err
+	}
+	return DeleteWithService(task, svc, name)
+}
+
+// DeleteWithService deletes the infrastructure of the cluster named 'name', using the service passed as parameter
+func DeleteWithService(task concurrency.Task, svc iaas.Service, name string) error {
+	instance, err := LoadWithService(task, svc, name)
 	if err != nil {
 		return fmt.Errorf("failed to find a cluster named '%s': %s", name, err.Error())
 	}
@@ -161,15 +194,19 @@ func Delete(task concurrency.Task, name string) error {
 	return instance.Delete(task)
 }
 
-// List lists the clusters already created
-func List() (clusterList []api.Cluster, err error) {
-	tenant, err := client.New().Tenant.Get(temporal.GetExecutionTimeout())
+// List lists the clusters already created in the default tenant of the client
+func List() ([]api.Cluster, error) {
+	svc, err := currentService()
 	if err != nil {
 		return nil, err
 	}
-	svc, err := iaas.UseService(tenant.Name)
-	if err != nil {
-		return nil, err
+	return ListWithService(svc)
+}
+
+// ListWithService lists the clusters already created, using the service passed as parameter
+func ListWithService(svc iaas.Service) (clusterList []api.Cluster, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
 	}
 
 	m, err := control.NewMetadata(svc)
@@ -153,7 +153,7 @@ func configureMaster(task concurrency.Task, foreman control.Foreman, index int,
 	if err != nil {
 		return err
 	}
-	retcode, _, _, err := foreman.ExecuteScript(box, funcMap, "dcos_configure_master.sh", map[string]interface{}{
+	retcode, _, _, err := foreman.ExecuteScript(task, box, funcMap, "dcos_configure_master.sh", map[string]interface{}{
 		"BootstrapIP":   netCfg.GatewayIP,
 		"BootstrapPort": bootstrapHTTPPort,
 	}, host.Id)
@@ -186,7 +186,7 @@ func configureNode(task concurrency.Task, foreman control.Foreman, index int, ho
 	if err != nil {
 		return err
 	}
-	retcode, _, _, err := foreman.ExecuteScript(box, funcMap, "dcos_configure_node.sh", map[string]interface{}{
+	retcode, _, _, err := foreman.ExecuteScript(task, box, funcMap, "dcos_configure_node.sh", map[string]interface{}{
 		"BootstrapIP":   netCfg.GatewayIP,
 		"BootstrapPort": bootstrapHTTPPort,
 	}, host.Id)
@@ -256,7 +256,7 @@ func configureGateway(task concurrency.Task, foreman control.Foreman) error {
 		"SSHPrivateKey":    identity.Keypair.PrivateKey,
 		"SSHPublicKey":     identity.Keypair.PublicKey,
 	}
-	retcode, _, _, err := foreman.ExecuteScript(box, funcMap, "dcos_prepare_bootstrap.sh", data, netCfg.GatewayID)
+	retcode, _, _, err := foreman.ExecuteScript(task, box, funcMap, "dcos_prepare_bootstrap.sh", data, netCfg.GatewayID)
 	if err != nil {
 		logrus.Errorf("[gateway] configuration failed: %s", err.Error())
 		return err
@@ -347,7 +347,7 @@ func getState(task concurrency.Task, foreman control.Foreman) (clusterstate.Enum
 	)
 
 	cmd := "/opt/mesosphere/bin/dcos-diagnostics --diag"
-	safescaleClt := client.New()
+	safescaleClt := client.FromTask(task)
 	safescaleCltHost := safescaleClt.Host
 	masterID, err := foreman.Cluster().FindAvailableMaster(task)
 	if err != nil {
@@ -346,7 +346,7 @@ func leaveNodeFromCluster(task concurrency.Task, b control.Foreman, pbHost *pb.H
 		}
 	}
 
-	clientSSH := client.New().SSH
+	clientSSH := client.FromTask(task).SSH
 
 	// Check worker belongs to k8s
 	cmd := "sudo -u cladm -i kubectl get node --selector='!node-role.kubernetes.io/master' | tail -n +2"
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+//go:generate mockgen -destination=../mocks/mock_auditapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers AuditAPI
+
+// AuditAPI defines API to read the audit log
+type AuditAPI interface {
+	List(ctx context.Context, tenant string, since time.Time, resource string) ([]audit.Record, error)
+}
+
+// AuditHandler audit service
+type AuditHandler struct {
+	recorder *audit.Recorder
+}
+
+// NewAuditHandler creates an Audit service
+func NewAuditHandler(recorder *audit.Recorder) AuditAPI {
+	return &AuditHandler{
+		recorder: recorder,
+	}
+}
+
+// List returns the records of the tenant since the time given, optionally restricted to a resource
+func (handler *AuditHandler) List(ctx context.Context, tenant string, since time.Time, resource string) ([]audit.Record, error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if handler.recorder == nil {
+		return nil, scerr.NotAvailableError("audit is disabled")
+	}
+	return handler.recorder.List(tenant, since, resource)
+}
@@ -0,0 +1,387 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/cluster"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/api"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/install"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+//go:generate mockgen -destination=../mocks/mock_clusterapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers ClusterAPI
+
+// ClusterAPI defines API to manipulate clusters
+type ClusterAPI interface {
+	Create(ctx context.Context, req control.Request) (api.Cluster, error)
+	Inspect(ctx context.Context, name string) (api.Cluster, error)
+	List(ctx context.Context, selector string) ([]api.Cluster, error)
+	Delete(ctx context.Context, name string) error
+	Start(ctx context.Context, name string) error
+	Stop(ctx context.Context, name string) error
+	State(ctx context.Context, name string) (clusterstate.Enum, error)
+	Expand(ctx context.Context, name string, count int, def *pb.HostDefinition) ([]*clusterpropsv1.Node, error)
+	Shrink(ctx context.Context, name string, count int) ([]*clusterpropsv1.Node, error)
+	AddFeature(ctx context.Context, name string, feature string, vars install.Variables, settings install.Settings) (install.Results, error)
+	CheckFeature(ctx context.Context, name string, feature string, vars install.Variables, settings install.Settings) (install.Results, error)
+	DeleteFeature(ctx context.Context, name string, feature string, vars install.Variables, settings install.Settings) (install.Results, error)
+	ListNodes(ctx context.Context, name string) ([]*clusterpropsv1.Node, error)
+	ListMasters(ctx context.Context, name string) ([]*clusterpropsv1.Node, error)
+	SetTags(ctx context.Context, name string, set map[string]string, unset []string) (map[string]string, error)
+}
+
+// ClusterHandler cluster service
+type ClusterHandler struct {
+	service iaas.Service
+}
+
+// NewClusterHandler creates a Cluster service
+func NewClusterHandler(svc iaas.Service) ClusterAPI {
+	return &ClusterHandler{
+		service: svc,
+	}
+}
+
+// Create creates a new cluster following the request
+func (handler *ClusterHandler) Create(ctx context.Context, req control.Request) (instance api.Cluster, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", req.Name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if err = resources.ValidateTags(req.Tags); err != nil {
+		return nil, err
+	}
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = cluster.LoadWithService(task, handler.service, req.Name)
+	if err == nil {
+		return nil, scerr.DuplicateError(fmt.Sprintf("cluster '%s' already exists", req.Name))
+	}
+	if _, ok := err.(scerr.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	instance, err = cluster.CreateWithService(task, handler.service, req)
+	if err != nil {
+		if instance != nil && !req.KeepOnFailure {
+			derr := instance.Delete(task)
+			if derr != nil {
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+		return nil, err
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("failed to create cluster '%s': unknown reason", req.Name)
+	}
+	return instance, nil
+}
+
+// Inspect returns the cluster identified by 'name'
+func (handler *ClusterHandler) Inspect(ctx context.Context, name string) (instance api.Cluster, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.LoadWithService(task, handler.service, name)
+}
+
+// List returns the clusters managed by SafeScale
+func (handler *ClusterHandler) List(ctx context.Context, selector string) (list []api.Cluster, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", selector), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	tagSelector, err := resources.ParseTagSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	all, err := cluster.ListWithService(handler.service)
+	if err != nil || tagSelector.Empty() {
+		return all, err
+	}
+
+	task := concurrency.RootTask()
+	for _, instance := range all {
+		tags, err := instance.GetTags(task)
+		if err != nil {
+			return nil, err
+		}
+		if tagSelector.Matches(tags) {
+			list = append(list, instance)
+		}
+	}
+	return list, nil
+}
+
+// Delete deletes the cluster identified by 'name' and all its resources
+func (handler *ClusterHandler) Delete(ctx context.Context, name string) (err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return cluster.DeleteWithService(task, handler.service, name)
+}
+
+// Start starts all the hosts of the cluster
+func (handler *ClusterHandler) Start(ctx context.Context, name string) (err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return err
+	}
+	return instance.Start(task)
+}
+
+// Stop stops all the hosts of the cluster
+func (handler *ClusterHandler) Stop(ctx context.Context, name string) (err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return err
+	}
+	return instance.Stop(task)
+}
+
+// State returns the current state of the cluster
+func (handler *ClusterHandler) State(ctx context.Context, name string) (state clusterstate.Enum, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return clusterstate.Unknown, err
+	}
+	return instance.GetState(task)
+}
+
+// Expand adds 'count' nodes to the cluster, using 'def' as node definition (or the cluster defaults if nil)
+func (handler *ClusterHandler) Expand(ctx context.Context, name string, count int, def *pb.HostDefinition) (nodes []*clusterpropsv1.Node, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if count <= 0 {
+		return nil, scerr.InvalidParameterError("count", "must be greater than 0")
+	}
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	hostIDs, err := instance.AddNodes(task, count, def)
+	if err != nil {
+		return nil, err
+	}
+
+	added := map[string]struct{}{}
+	for _, id := range hostIDs {
+		added[id] = struct{}{}
+	}
+	for _, node := range instance.ListNodes(task) {
+		if _, ok := added[node.ID]; ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// Shrink removes the 'count' last added nodes of the cluster
+func (handler *ClusterHandler) Shrink(ctx context.Context, name string, count int) (removed []*clusterpropsv1.Node, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if count <= 0 {
+		return nil, scerr.InvalidParameterError("count", "must be greater than 0")
+	}
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := instance.ListNodes(task)
+	if count > len(nodes) {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("cannot delete %d node(s), the cluster contains only %d of them", count, len(nodes)))
+	}
+
+	availableMaster, err := instance.FindAvailableMaster(task)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []string
+	for i := 0; i < count; i++ {
+		node := nodes[len(nodes)-1-i]
+		err := instance.DeleteLastNode(task, availableMaster)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("failed to delete node '%s': %s", node.Name, err.Error()))
+			continue
+		}
+		removed = append(removed, node)
+	}
+	if len(msgs) > 0 {
+		return removed, fmt.Errorf(strings.Join(msgs, "\n"))
+	}
+	return removed, nil
+}
+
+// AddFeature installs the feature 'feature' on the cluster
+func (handler *ClusterHandler) AddFeature(ctx context.Context, name string, feature string, vars install.Variables, settings install.Settings) (results install.Results, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	f, target, err := handler.prepareFeature(ctx, name, feature)
+	if err != nil {
+		return nil, err
+	}
+	return f.Add(target, vars, settings)
+}
+
+// CheckFeature checks if the feature 'feature' is installed on the cluster
+func (handler *ClusterHandler) CheckFeature(ctx context.Context, name string, feature string, vars install.Variables, settings install.Settings) (results install.Results, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	f, target, err := handler.prepareFeature(ctx, name, feature)
+	if err != nil {
+		return nil, err
+	}
+	return f.Check(target, vars, settings)
+}
+
+// DeleteFeature uninstalls the feature 'feature' from the cluster
+func (handler *ClusterHandler) DeleteFeature(ctx context.Context, name string, feature string, vars install.Variables, settings install.Settings) (results install.Results, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	f, target, err := handler.prepareFeature(ctx, name, feature)
+	if err != nil {
+		return nil, err
+	}
+	return f.Remove(target, vars, settings)
+}
+
+// ListNodes returns the nodes of the cluster
+func (handler *ClusterHandler) ListNodes(ctx context.Context, name string) (nodes []*clusterpropsv1.Node, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return instance.ListNodes(task), nil
+}
+
+// ListMasters returns the masters of the cluster
+func (handler *ClusterHandler) ListMasters(ctx context.Context, name string) (masters []*clusterpropsv1.Node, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return instance.ListMasters(task), nil
+}
+
+// SetTags sets and removes tags of the cluster identified by 'name', and returns the resulting tags
+func (handler *ClusterHandler) SetTags(ctx context.Context, name string, set map[string]string, unset []string) (tags map[string]string, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if err = resources.ValidateTags(set); err != nil {
+		return nil, err
+	}
+
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return instance.SetTags(task, set, unset)
+}
+
+// load creates a task bound to ctx and loads the cluster named 'name'
+func (handler *ClusterHandler) load(ctx context.Context, name string) (concurrency.Task, api.Cluster, error) {
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	instance, err := cluster.LoadWithService(task, handler.service, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, instance, nil
+}
+
+// prepareFeature loads the cluster and the feature, and builds the install target
+func (handler *ClusterHandler) prepareFeature(ctx context.Context, name string, feature string) (*install.Feature, install.Target, error) {
+	task, instance, err := handler.load(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := install.NewFeature(task, feature)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f == nil {
+		return nil, nil, scerr.NotFoundError(fmt.Sprintf("failed to find a feature named '%s'", feature))
+	}
+	target, err := install.NewClusterTarget(task, instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, target, nil
+}
@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -33,6 +35,8 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/securitygroupproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
 	"github.com/CS-SI/SafeScale/lib/server/install"
@@ -55,8 +59,8 @@ import (
 
 // HostAPI defines API to manipulate hosts
 type HostAPI interface {
-	Create(ctx context.Context, name string, net string, os string, public bool, sizingParam interface{}, force bool) (*resources.Host, error)
-	List(ctx context.Context, all bool) ([]*resources.Host, error)
+	Create(ctx context.Context, name string, net string, os string, public bool, sizingParam interface{}, force bool, tags map[string]string) (*resources.Host, error)
+	List(ctx context.Context, all bool, selector string) ([]*resources.Host, error)
 	ForceInspect(ctx context.Context, ref string) (*resources.Host, error)
 	Inspect(ctx context.Context, ref string) (*resources.Host, error)
 	Delete(ctx context.Context, ref string) error
@@ -65,6 +69,8 @@ type HostAPI interface {
 	Resize(ctx context.Context, name string, cpu int, ram float32, disk int, gpuNumber int, freq float32) (*resources.Host, error)
 	Start(ctx context.Context, ref string) error
 	Stop(ctx context.Context, ref string) error
+	SetTags(ctx context.Context, ref string, set map[string]string, unset []string) (map[string]string, error)
+	Adopt(ctx context.Context, id string, username string, port int, privateKey string) (*resources.Host, error)
 }
 
 // HostHandler host service
@@ -297,7 +303,7 @@ func (handler *HostHandler) Resize(ctx context.Context, ref string, cpu int, ram
 // 	force bool,
 func (handler *HostHandler) Create(
 	ctx context.Context,
-	name string, net string, los string, public bool, sizingParam interface{}, force bool,
+	name string, net string, los string, public bool, sizingParam interface{}, force bool, tags map[string]string,
 ) (newHost *resources.Host, err error) {
 
 	if handler == nil {
@@ -309,6 +315,9 @@ func (handler *HostHandler) Create(
 	if name == "" {
 		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
 	}
+	if err := resources.ValidateTags(tags); err != nil {
+		return nil, err
+	}
 
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s', %v, <sizingParam>, %v)", name, net, los, public, force), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
@@ -467,10 +476,13 @@ func (handler *HostHandler) Create(
 		Networks:       networks,
 		DefaultRouteIP: defaultRouteIP,
 		DefaultGateway: primaryGateway,
+		Tags:           tags,
 	}
 
 	var userData *userdata.Content
+	phaseDone := srvutils.StartPhase(ctx, "compute", name)
 	host, userData, err = handler.service.CreateHost(hostRequest)
+	phaseDone(err)
 	if err != nil {
 		switch err.(type) {
 		case scerr.ErrInvalidRequest:
@@ -566,19 +578,7 @@ func (handler *HostHandler) Create(
 	}
 
 	// Sets host extension DescriptionV1
-	creator := ""
-	hostname, _ := os.Hostname()
-	if curUser, err := user.Current(); err == nil {
-		creator = curUser.Username
-		if hostname != "" {
-			creator += "@" + hostname
-		}
-		if curUser.Name != "" {
-			creator += " (" + curUser.Name + ")"
-		}
-	} else {
-		creator = "unknown@" + hostname
-	}
+	creator := currentCreator()
 	err = host.Properties.LockForWrite(hostproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
 		hostDescriptionV1 := clonable.(*propsv1.HostDescription)
 		hostDescriptionV1.Created = time.Now()
@@ -588,6 +588,10 @@ func (handler *HostHandler) Create(
 	if err != nil {
 		return nil, err
 	}
+	err = writeTags(host.Properties, hostproperty.TagsV1, tags)
+	if err != nil {
+		return nil, err
+	}
 
 	// Updates host property propsv1.HostNetwork
 	var (
@@ -637,58 +641,78 @@ func (handler *HostHandler) Create(
 		return nil, err
 	}
 
+	// Hosts simulated by the provider cannot be reached with SSH: they are ready once linked to their networks
+	simulated := handler.service.GetCapabilities().SimulatedHosts
+
 	// A host claimed ready by a Cloud provider is not necessarily ready
 	// to be used until ssh service is up and running. So we wait for it before
 	// claiming host is created
-	logrus.Infof("Waiting start of SSH service on remote host '%s' ...", host.Name)
 	sshHandler := NewSSHHandler(handler.service)
-	sshCfg, err := sshHandler.GetConfig(ctx, host.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = sshCfg.WaitServerReady("phase1", temporal.GetHostCreationTimeout())
-	if err != nil {
-		derr := err
-		err = nil
-		if client.IsTimeoutError(derr) {
-			return nil, scerr.Wrap(derr, fmt.Sprintf("timeout waiting host '%s' to become ready", host.Name))
+	var sshCfg *system.SSHConfig
+	if !simulated {
+		logrus.Infof("Waiting start of SSH service on remote host '%s' ...", host.Name)
+		sshCfg, err = sshHandler.GetConfig(ctx, host.ID)
+		if err != nil {
+			return nil, err
 		}
 
-		if client.IsProvisioningError(derr) {
-			logrus.Errorf("%+v", derr)
-			return nil, fmt.Errorf("failed to provision host '%s', please check safescaled logs", host.Name)
-		}
+		phaseDone = srvutils.StartPhase(ctx, "phase1", host.Name)
+		_, err = sshCfg.WaitServerReady("phase1", temporal.GetHostCreationTimeout())
+		phaseDone(err)
+		if err != nil {
+			derr := err
+			err = nil
+			if client.IsTimeoutError(derr) {
+				return nil, scerr.Wrap(derr, fmt.Sprintf("timeout waiting host '%s' to become ready", host.Name))
+			}
+
+			if client.IsProvisioningError(derr) {
+				logrus.Errorf("%+v", derr)
+				return nil, fmt.Errorf("failed to provision host '%s', please check safescaled logs", host.Name)
+			}
 
-		return nil, scerr.Wrap(derr, fmt.Sprintf("failed to wait host '%s' to become ready", host.Name))
+			return nil, scerr.Wrap(derr, fmt.Sprintf("failed to wait host '%s' to become ready", host.Name))
+		}
 	}
 
-	// Updates host link with networks
+	// Updates host link with networks, under the lock of their metadata since other hosts may be added concurrently
 	for _, i := range networks {
-		err = i.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
-			networkHostsV1 := clonable.(*propsv1.NetworkHosts)
-			networkHostsV1.ByName[host.Name] = host.ID
-			networkHostsV1.ByID[host.ID] = host.Name
-			return nil
+		err = updateNetworkMetadata(handler.service, i.ID, func(network *resources.Network) error {
+			return network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+				networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+				networkHostsV1.ByName[host.Name] = host.ID
+				networkHostsV1.ByID[host.ID] = host.Name
+				return nil
+			})
 		})
-		if err != nil {
-			logrus.Errorf(err.Error())
-			continue
-		}
-		_, err = metadata.SaveNetwork(handler.service, i)
 		if err != nil {
 			logrus.Errorf(err.Error())
 		}
 	}
 
+	if simulated {
+		return host, nil
+	}
+	srvutils.ReportProgress(ctx, srvutils.ProgressSSHReady, "phase1", host.Name, "")
+
 	// Executes userdata phase2 script to finalize host installation
+	phaseDone = srvutils.StartPhase(ctx, "phase2", host.Name)
+	defer func() {
+		if phaseDone != nil {
+			phaseDone(err)
+		}
+	}()
 	userDataPhase2, err := userData.Generate("phase2")
 	if err != nil {
 		return nil, err
 	}
 
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 	filepath := utils.TempFolder + "/user_data.phase2.sh"
-	err = install.UploadStringToRemoteFile(string(userDataPhase2), srvutils.ToPBHost(host), filepath, "", "", "")
+	err = install.UploadStringToRemoteFile(task, string(userDataPhase2), srvutils.ToPBHost(host), filepath, "", "", "")
 	if err != nil {
 		return nil, err
 	}
@@ -721,6 +745,7 @@ func (handler *HostHandler) Create(
 			if v == verdict.Retry {
 				logrus.Debugf("Remote SSH service on host '%s' isn't ready, retrying...", host.Name)
 			}
+			srvutils.ProgressRetryNotifier(ctx, "phase2", host.Name)(t, v)
 		},
 	)
 	if retryErr != nil {
@@ -741,6 +766,8 @@ func (handler *HostHandler) Create(
 
 	// FIXME: AWS Retrieve data anyway
 	retrieveForensicsData(ctx, sshHandler, host)
+	phaseDone(nil)
+	phaseDone = srvutils.StartPhase(ctx, "reboot", host.Name)
 
 	// Reboot host
 	command = "sudo systemctl reboot"
@@ -766,6 +793,9 @@ func (handler *HostHandler) Create(
 		return nil, err
 	}
 	logrus.Infof("SSH service started on host '%s'.", host.Name)
+	phaseDone(nil)
+	phaseDone = nil
+	srvutils.ReportProgress(ctx, srvutils.ProgressSSHReady, "ready", host.Name, "")
 
 	select {
 	case <-ctx.Done():
@@ -864,12 +894,19 @@ func (handler *HostHandler) getOrCreateDefaultNetwork() (network *resources.Netw
 }
 
 // List returns the host list
-func (handler *HostHandler) List(ctx context.Context, all bool) (hosts []*resources.Host, err error) {
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+func (handler *HostHandler) List(ctx context.Context, all bool, selector string) (hosts []*resources.Host, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v, '%s')", all, selector), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
+	tagSelector, err := resources.ParseTagSelector(selector)
+	if err != nil {
+		return nil, err
+	}
 	if all {
+		if !tagSelector.Empty() {
+			return nil, scerr.InvalidParameterError("selector", "cannot be used when listing all the hosts of the tenant, tags are only known by SafeScale")
+		}
 		return handler.service.ListHosts()
 	}
 
@@ -878,7 +915,13 @@ func (handler *HostHandler) List(ctx context.Context, all bool) (hosts []*resour
 		return nil, err
 	}
 	err = m.Browse(func(host *resources.Host) error {
-		hosts = append(hosts, host)
+		tags, err := readTags(host.Properties, hostproperty.TagsV1)
+		if err != nil {
+			return err
+		}
+		if tagSelector.Matches(tags) {
+			hosts = append(hosts, host)
+		}
 		return nil
 	})
 	if err != nil {
@@ -1037,25 +1080,51 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 	}
 
 	// Update networks property prosv1.NetworkHosts to remove the reference to the host
-	netHandler := NewNetworkHandler(handler.service)
 	err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
 		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
 		for k := range hostNetworkV1.NetworksByID {
-			network, err := netHandler.Inspect(ctx, k)
+			err := updateNetworkMetadata(handler.service, k, func(network *resources.Network) error {
+				return network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+					networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+					delete(networkHostsV1.ByID, host.ID)
+					delete(networkHostsV1.ByName, host.Name)
+					return nil
+				})
+			})
+			if err != nil {
+				logrus.Errorf(err.Error())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update security groups property propsv1.SecurityGroupHosts to remove the reference to the host
+	err = host.Properties.LockForRead(hostproperty.SecurityGroupsV1).ThenUse(func(clonable data.Clonable) error {
+		hostSecurityGroupsV1 := clonable.(*propsv1.HostSecurityGroups)
+		for k := range hostSecurityGroupsV1.ByID {
+			msg, err := metadata.LoadSecurityGroup(handler.service, k)
 			if err != nil {
 				logrus.Errorf(err.Error())
 				continue
 			}
-			err = network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
-				networkHostsV1 := clonable.(*propsv1.NetworkHosts)
-				delete(networkHostsV1.ByID, host.ID)
-				delete(networkHostsV1.ByName, host.Name)
+			sg, err := msg.Get()
+			if err != nil {
+				logrus.Errorf(err.Error())
+				continue
+			}
+			err = sg.Properties.LockForWrite(securitygroupproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+				sgHostsV1 := clonable.(*propsv1.SecurityGroupHosts)
+				delete(sgHostsV1.ByID, host.ID)
+				delete(sgHostsV1.ByName, host.Name)
 				return nil
 			})
 			if err != nil {
 				logrus.Errorf(err.Error())
 			}
-			_, err = metadata.SaveNetwork(handler.service, network)
+			err = msg.Write()
 			if err != nil {
 				logrus.Errorf(err.Error())
 			}
@@ -1095,7 +1164,13 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 		}
 	}
 
+	// Removes the metadata under their lock, so that they are not being changed by someone else meanwhile
+	err = mh.Acquire()
+	if err != nil {
+		return err
+	}
 	err = mh.Delete()
+	mh.Release()
 	if err != nil {
 		return err
 	}
@@ -1108,7 +1183,11 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 	case <-ctx.Done():
 		logrus.Warnf("Host delete cancelled by safescale")
 		var hostBis *resources.Host
-		err2 := host.Properties.LockForRead(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
+		hostTags, err2 := readTags(host.Properties, hostproperty.TagsV1)
+		if err2 != nil {
+			return err2
+		}
+		err2 = host.Properties.LockForRead(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
 			hostSizingV1 := clonable.(*propsv1.HostSizing)
 			return host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
 				hostNetworkV1 := clonable.(*propsv1.HostNetwork)
@@ -1123,7 +1202,7 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 					MaxRAMSize:  hostSizingV1.AllocatedSize.RAMSize,
 					MinDiskSize: hostSizingV1.AllocatedSize.DiskSize,
 				}
-				hostBis, err3 = handler.Create(context.Background(), host.Name, hostNetworkV1.DefaultNetworkID, "ubuntu 18.04", (len(hostNetworkV1.PublicIPv4)+len(hostNetworkV1.PublicIPv6)) != 0, &sizing, true)
+				hostBis, err3 = handler.Create(context.Background(), host.Name, hostNetworkV1.DefaultNetworkID, "ubuntu 18.04", (len(hostNetworkV1.PublicIPv4)+len(hostNetworkV1.PublicIPv6)) != 0, &sizing, true, hostTags)
 				if err3 != nil {
 					return fmt.Errorf("failed to stop host deletion : %s", err3.Error())
 				}
@@ -1159,3 +1238,389 @@ func (handler *HostHandler) SSH(ctx context.Context, ref string) (sshConfig *sys
 	}
 	return sshConfig, nil
 }
+
+// SetTags sets the tags in set and removes the tags in unset on the host identified by ref, then returns the
+// resulting tags. Tags are propagated on provider side when supported
+func (handler *HostHandler) SetTags(ctx context.Context, ref string, set map[string]string, unset []string) (tags map[string]string, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if err = resources.ValidateTags(set); err != nil {
+		return nil, err
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mh, err := metadata.LoadHost(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("host", ref)
+		}
+		return nil, err
+	}
+	err = mh.Acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer mh.Release()
+	err = mh.Reload()
+	if err != nil {
+		return nil, err
+	}
+	host, err := mh.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := readTags(host.Properties, hostproperty.TagsV1)
+	if err != nil {
+		return nil, err
+	}
+	err = propagateTags("host", host.Name, handler.service.UpdateHostTags(host.ID, set, unset))
+	if err != nil {
+		return nil, err
+	}
+
+	tags = resources.MergeTags(current, set, unset)
+	err = writeTags(host.Properties, hostproperty.TagsV1, tags)
+	if err != nil {
+		return nil, err
+	}
+	err = mh.Write()
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Adopt creates the metadata of the host 'id' of the provider, created outside of SafeScale, to manage it.
+// 'username' and 'port', if not empty, override the operator user of the tenant and the port 22 to access the host
+// through SSH; they are kept in the metadata of the host.
+// If 'privateKey' is given, it becomes the key of the host, and the SSH access to the host with this key is checked
+// before adopting the host. Otherwise the host is adopted without check, and without key unless the provider tells it:
+// SafeScale cannot reach such a host through SSH.
+func (handler *HostHandler) Adopt(ctx context.Context, id string, username string, port int, privateKey string) (host *resources.Host, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if port < 0 || port > 65535 {
+		return nil, scerr.InvalidParameterError("port", "must be a valid TCP port")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', %d)", id, username, port), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return adoptHost(handler.service, id, func(host *resources.Host) error {
+		if username != "" || port != 0 {
+			err := host.Properties.LockForWrite(hostproperty.SSHV1).ThenUse(func(clonable data.Clonable) error {
+				hostSSHV1 := clonable.(*propsv1.HostSSH)
+				hostSSHV1.User = username
+				hostSSHV1.Port = port
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if privateKey == "" {
+			if host.PrivateKey == "" {
+				logrus.Warnf("host '%s' adopted without private key: SafeScale cannot reach it through SSH", host.Name)
+			}
+			return nil
+		}
+		host.PrivateKey = privateKey
+		return handler.checkSSH(ctx, host)
+	})
+}
+
+// checkSSH checks that a command can be run on 'host' through SSH
+func (handler *HostHandler) checkSSH(ctx context.Context, host *resources.Host) error {
+	sshConfig, err := NewSSHHandler(handler.service).GetConfig(ctx, host)
+	if err != nil {
+		return err
+	}
+	sshCmd, err := sshConfig.Command("true")
+	if err != nil {
+		return err
+	}
+	retcode, _, stderr, err := sshCmd.RunWithTimeout(nil, outputs.COLLECT, temporal.GetConnectSSHTimeout())
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to connect to host '%s' through SSH", host.Name))
+	}
+	if retcode != 0 {
+		return fmt.Errorf("failed to run a command on host '%s' through SSH: %s", host.Name, stderr)
+	}
+	return nil
+}
+
+// adoptHost creates the metadata of the host 'id' of the provider, created outside of SafeScale, and links it to
+// the managed networks it is connected to. A host named as the gateway of a managed network without gateway
+// ("gw-<network name>") becomes this gateway.
+// If not nil, 'checkfn' is called on the host before writing its metadata; the host is not adopted if it fails.
+func adoptHost(svc iaas.Service, id string, checkfn func(*resources.Host) error) (*resources.Host, error) {
+	host, err := svc.InspectHost(id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = metadata.LoadHost(svc, host.Name)
+	if err == nil {
+		return nil, scerr.DuplicateError(fmt.Sprintf("a host named '%s' is already managed", host.Name))
+	}
+	if _, ok := err.(scerr.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	// Looks for the managed networks the host is connected to
+	var networkIDs []string
+	err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		for networkID := range clonable.(*propsv1.HostNetwork).NetworksByID {
+			networkIDs = append(networkIDs, networkID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var networks []*resources.Network
+	for _, networkID := range networkIDs {
+		mn, err := metadata.LoadNetwork(svc, networkID)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); ok {
+				// Network not managed by SafeScale
+				continue
+			}
+			return nil, err
+		}
+		network, err := mn.Get()
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	isGatewayOf := func(network *resources.Network) bool {
+		return host.ID == network.GatewayID || host.ID == network.SecondaryGatewayID ||
+			(network.GatewayID == "" && host.Name == "gw-"+network.Name)
+	}
+
+	// Completes the properties of the host with what SafeScale knows
+	err = host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+		for _, network := range networks {
+			if isGatewayOf(network) {
+				hostNetworkV1.IsGateway = true
+			}
+		}
+		for _, network := range networks {
+			if hostNetworkV1.DefaultNetworkID == "" {
+				hostNetworkV1.DefaultNetworkID = network.ID
+			}
+			// A host without public IP is reached through the gateway of its network
+			public := hostNetworkV1.PublicIPv4 != "" || hostNetworkV1.PublicIPv6 != ""
+			if !public && !hostNetworkV1.IsGateway && hostNetworkV1.DefaultGatewayID == "" && network.GatewayID != "" {
+				hostNetworkV1.DefaultNetworkID = network.ID
+				hostNetworkV1.DefaultGatewayID = network.GatewayID
+				if mgw, err := metadata.LoadHost(svc, network.GatewayID); err == nil {
+					if gw, err := mgw.Get(); err == nil {
+						hostNetworkV1.DefaultGatewayPrivateIP = gw.GetPrivateIP()
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = host.Properties.LockForWrite(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
+		hostSizingV1 := clonable.(*propsv1.HostSizing)
+		// The size requested at creation is unknown, the allocated one is the best guess
+		if hostSizingV1.AllocatedSize != nil && (hostSizingV1.RequestedSize == nil || *hostSizingV1.RequestedSize == propsv1.HostSize{}) {
+			requested := *hostSizingV1.AllocatedSize
+			hostSizingV1.RequestedSize = &requested
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = host.Properties.LockForWrite(hostproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.HostDescription).Purpose = "adopted by " + currentCreator()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Records the attachments of the managed volumes found at the provider
+	attachments, err := svc.ListVolumeAttachments(host.ID)
+	if err != nil {
+		return nil, err
+	}
+	var volumes []*resources.Volume
+	for _, attachment := range attachments {
+		mv, err := metadata.LoadVolume(svc, attachment.VolumeID)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); ok {
+				// Volume not managed by SafeScale
+				continue
+			}
+			return nil, err
+		}
+		volume, err := mv.Get()
+		if err != nil {
+			return nil, err
+		}
+		err = addHostVolume(host, volume, attachment)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volume)
+	}
+
+	if checkfn != nil {
+		err = checkfn(host)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = metadata.SaveHost(svc, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, volume := range volumes {
+		err = updateVolumeMetadata(svc, volume.ID, func(volume *resources.Volume) error {
+			return volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+				volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
+				if volumeAttachedV1.Hosts == nil {
+					volumeAttachedV1.Hosts = map[string]string{}
+				}
+				volumeAttachedV1.Hosts[host.ID] = host.Name
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, network := range networks {
+		err = updateNetworkMetadata(svc, network.ID, func(network *resources.Network) error {
+			if isGatewayOf(network) {
+				// A gateway is not listed in the hosts of its network
+				if network.GatewayID == "" {
+					network.GatewayID = host.ID
+				}
+				return nil
+			}
+			return network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+				networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+				networkHostsV1.ByID[host.ID] = host.Name
+				networkHostsV1.ByName[host.Name] = host.ID
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return host, nil
+}
+
+// currentCreator returns the user running safescaled, as recorded in the description of the hosts
+func currentCreator() string {
+	hostname, _ := os.Hostname()
+	curUser, err := user.Current()
+	if err != nil {
+		return "unknown@" + hostname
+	}
+	creator := curUser.Username
+	if hostname != "" {
+		creator += "@" + hostname
+	}
+	if curUser.Name != "" {
+		creator += " (" + curUser.Name + ")"
+	}
+	return creator
+}
+
+// lockHostMetadata takes the lock of the metadata of the host 'ref', then reads them again since they may have been
+// changed before being locked; the lock is kept until the returned function is called
+func lockHostMetadata(svc iaas.Service, ref string) (*metadata.Host, *resources.Host, func(), error) {
+	mh, err := metadata.LoadHost(svc, ref)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = mh.Acquire()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var once sync.Once
+	release := func() { once.Do(mh.Release) }
+	err = mh.Reload()
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	host, err := mh.Get()
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	return mh, host, release, nil
+}
+
+// lockHosts takes the locks of the metadata of the hosts 'ids' in the order of their IDs, so that operations locking the
+// same hosts cannot wait for each other; returns the locked metadata by ID and the function releasing the locks
+func lockHosts(svc iaas.Service, ids ...string) (map[string]*metadata.Host, func(), error) {
+	locked := map[string]*metadata.Host{}
+	var sorted []string
+	for _, id := range ids {
+		if _, ok := locked[id]; !ok {
+			locked[id] = nil
+			sorted = append(sorted, id)
+		}
+	}
+	sort.Strings(sorted)
+
+	var releases []func()
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	for _, id := range sorted {
+		mh, _, releaseHost, err := lockHostMetadata(svc, id)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		locked[id] = mh
+		releases = append(releases, releaseHost)
+	}
+	return locked, release, nil
+}
+
+// updateHostMetadata applies 'updatefn' to the host 'ref' while its metadata is locked, then saves it
+func updateHostMetadata(svc iaas.Service, ref string, updatefn func(*resources.Host) error) error {
+	mh, host, release, err := lockHostMetadata(svc, ref)
+	if err != nil {
+		return err
+	}
+	defer release()
+	err = updatefn(host)
+	if err != nil {
+		return err
+	}
+	return mh.Write()
+}
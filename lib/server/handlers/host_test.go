@@ -0,0 +1,245 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/tests"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// loadNetwork returns the metadata of the network 'ref'
+func loadNetwork(t *testing.T, svc iaas.Service, ref string) *resources.Network {
+	mn, err := metadata.LoadNetwork(svc, ref)
+	require.Nil(t, err)
+	network, err := mn.Get()
+	require.Nil(t, err)
+	return network
+}
+
+// loadHost returns the metadata of the host 'ref'
+func loadHost(t *testing.T, svc iaas.Service, ref string) *resources.Host {
+	mh, err := metadata.LoadHost(svc, ref)
+	require.Nil(t, err)
+	host, err := mh.Get()
+	require.Nil(t, err)
+	return host
+}
+
+// networkHosts returns the hosts listed in the metadata of the network 'ref'
+func networkHosts(t *testing.T, svc iaas.Service, ref string) map[string]string {
+	var hosts map[string]string
+	err := loadNetwork(t, svc, ref).Properties.LockForRead(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+		hosts = clonable.(*propsv1.NetworkHosts).ByID
+		return nil
+	})
+	require.Nil(t, err)
+	return hosts
+}
+
+func TestHostHandler_Adopt_withoutKey(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestAdoptHostWithoutKey")
+	network := tests.CreateNetwork(t, svc, "net-nokey", "192.168.20.0/24")
+	host := tests.CreateHost(t, svc, "host-nokey", network, nil)
+	handler := NewHostHandler(svc)
+
+	_, err := handler.Adopt(context.Background(), host.ID, "", 70000, "")
+	require.NotNil(t, err)
+	_, ok := err.(scerr.ErrInvalidParameter)
+	assert.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+
+	// Without key, the host is adopted without SSH check, and its SSH access is kept
+	_, err = handler.Adopt(context.Background(), host.ID, "ubuntu", 2222, "")
+	require.Nil(t, err)
+	sshConfig, err := NewSSHHandler(svc).GetConfig(context.Background(), host.ID)
+	require.Nil(t, err)
+	assert.Equal(t, "ubuntu", sshConfig.User)
+	assert.Equal(t, 2222, sshConfig.Port)
+}
+
+func TestAdoptHost_duplicate(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestAdoptHostDuplicate")
+	network := tests.CreateNetwork(t, svc, "net-dup", "192.168.21.0/24")
+	host := tests.CreateHost(t, svc, "host-dup", network, nil)
+
+	_, err := adoptHost(svc, host.ID, nil)
+	require.Nil(t, err)
+	_, err = adoptHost(svc, host.ID, nil)
+	require.NotNil(t, err)
+	_, ok := err.(scerr.ErrDuplicate)
+	assert.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+}
+
+func TestAdoptHost_gateway(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestAdoptHostGateway")
+	network := tests.CreateNetwork(t, svc, "net-gw", "192.168.22.0/24")
+	gateway := tests.CreateHost(t, svc, "gw-net-gw", network, nil)
+	other := tests.CreateNetwork(t, svc, "net-other", "192.168.23.0/24")
+	notGateway := tests.CreateHost(t, svc, "gw-net-gw2", other, nil)
+
+	_, err := adoptNetwork(svc, network.ID)
+	require.Nil(t, err)
+	_, err = adoptNetwork(svc, other.ID)
+	require.Nil(t, err)
+
+	adopted, err := adoptHost(svc, gateway.ID, nil)
+	require.Nil(t, err)
+	err = adopted.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		assert.True(t, clonable.(*propsv1.HostNetwork).IsGateway)
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, gateway.ID, loadNetwork(t, svc, network.ID).GatewayID)
+	// A gateway is not listed in the hosts of its network
+	assert.Empty(t, networkHosts(t, svc, network.ID))
+
+	// Only the name of the network it is connected to makes a host a gateway
+	_, err = adoptHost(svc, notGateway.ID, nil)
+	require.Nil(t, err)
+	assert.Empty(t, loadNetwork(t, svc, other.ID).GatewayID)
+	assert.Equal(t, map[string]string{notGateway.ID: notGateway.Name}, networkHosts(t, svc, other.ID))
+}
+
+func TestAdoptHost_networks(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestAdoptHostNetworks")
+	network := tests.CreateNetwork(t, svc, "net-link", "192.168.24.0/24")
+	gateway := tests.CreateHost(t, svc, "gw-net-link", network, nil)
+	host := tests.CreateHost(t, svc, "host-link", network, gateway)
+	unmanaged := tests.CreateNetwork(t, svc, "net-unmanaged", "192.168.25.0/24")
+	outside := tests.CreateHost(t, svc, "host-outside", unmanaged, nil)
+
+	_, err := adoptNetwork(svc, network.ID)
+	require.Nil(t, err)
+	_, err = adoptHost(svc, gateway.ID, nil)
+	require.Nil(t, err)
+
+	adopted, err := adoptHost(svc, host.ID, nil)
+	require.Nil(t, err)
+	err = adopted.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+		assert.False(t, hostNetworkV1.IsGateway)
+		assert.Equal(t, network.ID, hostNetworkV1.DefaultNetworkID)
+		assert.Equal(t, gateway.ID, hostNetworkV1.DefaultGatewayID)
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{host.ID: host.Name}, networkHosts(t, svc, network.ID))
+
+	// A host connected to an unmanaged network is adopted without link to it
+	_, err = adoptHost(svc, outside.ID, nil)
+	require.Nil(t, err)
+	_, err = metadata.LoadNetwork(svc, unmanaged.ID)
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok)
+}
+
+func TestAdoptHost_failingCheck(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestAdoptHostFailingCheck")
+	network := tests.CreateNetwork(t, svc, "net-check", "192.168.26.0/24")
+	gateway := tests.CreateHost(t, svc, "gw-net-check", network, nil)
+	host := tests.CreateHost(t, svc, "host-check", network, gateway)
+	_, err := adoptNetwork(svc, network.ID)
+	require.Nil(t, err)
+	_, err = adoptHost(svc, gateway.ID, nil)
+	require.Nil(t, err)
+
+	_, err = adoptHost(svc, host.ID, func(*resources.Host) error {
+		return fmt.Errorf("SSH access refused")
+	})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "SSH access refused")
+
+	// Neither the host nor the links to it are recorded
+	_, err = metadata.LoadHost(svc, host.ID)
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok)
+	assert.Empty(t, networkHosts(t, svc, network.ID))
+}
+
+func TestAdoptHost_volumes(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestAdoptHostVolumes")
+	network := tests.CreateNetwork(t, svc, "net-vol", "192.168.27.0/24")
+	host := tests.CreateHost(t, svc, "host-vol", network, nil)
+	volume := tests.CreateVolume(t, svc, "vol-host", host.ID)
+	_, err := adoptVolume(svc, volume.ID)
+	require.Nil(t, err)
+
+	// The attachment of the managed volume is recorded when the host is adopted
+	adopted, err := adoptHost(svc, host.ID, nil)
+	require.Nil(t, err)
+	err = adopted.Properties.LockForRead(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+		hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+		assert.Contains(t, hostVolumesV1.VolumesByID, volume.ID)
+		assert.Equal(t, volume.ID, hostVolumesV1.VolumesByName[volume.Name])
+		return nil
+	})
+	require.Nil(t, err)
+
+	mv, err := metadata.LoadVolume(svc, volume.ID)
+	require.Nil(t, err)
+	managed, err := mv.Get()
+	require.Nil(t, err)
+	err = managed.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+		assert.Equal(t, map[string]string{host.ID: host.Name}, clonable.(*propsv1.VolumeAttachments).Hosts)
+		return nil
+	})
+	require.Nil(t, err)
+}
+
+func TestLockHosts(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestLockHosts")
+	network := tests.CreateNetwork(t, svc, "net-lock", "192.168.25.0/24")
+	host := tests.CreateHost(t, svc, "host-lock", network, nil)
+	_, err := adoptHost(svc, host.ID, nil)
+	require.Nil(t, err)
+
+	locks := func() []string {
+		list, err := svc.GetMetadataBucket().List("locks/hosts", "")
+		require.Nil(t, err)
+		return list
+	}
+
+	// A host given twice is locked once
+	locked, release, err := lockHosts(svc, host.ID, host.ID)
+	require.Nil(t, err)
+	assert.Len(t, locked, 1)
+	assert.NotNil(t, locked[host.ID])
+	assert.Len(t, locks(), 1)
+
+	release()
+	assert.Len(t, locks(), 0)
+	// Releasing twice is harmless
+	release()
+
+	_, _, err = lockHosts(svc, host.ID, "unknown")
+	require.NotNil(t, err)
+	assert.Len(t, locks(), 0)
+}
@@ -20,9 +20,16 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/imageproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 )
 
@@ -35,6 +42,8 @@ type ImageAPI interface {
 	List(ctx context.Context, all bool) ([]resources.Image, error)
 	Select(ctx context.Context, osfilter string) (*resources.Image, error)
 	Filter(ctx context.Context, osfilter string) ([]resources.Image, error)
+	Create(ctx context.Context, hostRef string, name string) (*resources.Image, error)
+	Delete(ctx context.Context, ref string) error
 }
 
 // ImageHandler image service
@@ -67,3 +76,145 @@ func (handler *ImageHandler) Select(ctx context.Context, osname string) (image *
 func (handler *ImageHandler) Filter(ctx context.Context, osname string) (image []resources.Image, err error) {
 	return nil, nil
 }
+
+// Create creates an image named name from the host identified by hostRef.
+// The features installed on the host are recorded in the metadata of the image, hosts created from it already having them
+func (handler *ImageHandler) Create(ctx context.Context, hostRef string, name string) (image *resources.Image, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if hostRef == "" {
+		return nil, scerr.InvalidParameterError("hostRef", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", hostRef, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	_, err = metadata.LoadImage(handler.service, name)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return nil, err
+		}
+	} else {
+		return nil, resources.ResourceDuplicateError("image", name)
+	}
+
+	mh, err := metadata.LoadHost(handler.service, hostRef)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("host", hostRef)
+		}
+		return nil, err
+	}
+	host, err := mh.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	installed := propsv1.NewHostFeatures()
+	err = host.Properties.LockForRead(hostproperty.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
+		installed.Replace(clonable.(*propsv1.HostFeatures))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := handler.service.CreateImage(host.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			derr := handler.service.DeleteImage(created.ID)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to delete image '%s': %v", name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	image = resources.NewImage()
+	image.ID = created.ID
+	image.Name = created.Name
+	image.URL = created.URL
+	image.Description = created.Description
+	image.StorageType = created.StorageType
+	err = image.Properties.LockForWrite(imageproperty.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
+		imageFeaturesV1 := clonable.(*propsv1.ImageFeatures)
+		imageFeaturesV1.HostID = host.ID
+		imageFeaturesV1.HostName = host.Name
+		imageFeaturesV1.Installed = installed.Installed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = metadata.SaveImage(handler.service, image)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Image creation cancelled by user")
+		derr := metadata.RemoveImage(handler.service, image.ID)
+		if derr != nil {
+			logrus.Warnf("failed to delete metadata of image '%s'", name)
+		}
+		err = fmt.Errorf("image creation cancelled by user")
+		return nil, err
+	default:
+	}
+
+	logrus.Infof("Image '%s' created from host '%s'", name, host.Name)
+	return image, nil
+}
+
+// Delete deletes the image identified by ref; only the images created by SafeScale can be deleted
+func (handler *ImageHandler) Delete(ctx context.Context, ref string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mi, err := metadata.LoadImage(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return resources.ResourceNotFoundError("image", ref)
+		}
+		return err
+	}
+	image, err := mi.Get()
+	if err != nil {
+		return err
+	}
+
+	err = handler.service.DeleteImage(image.ID)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return err
+		}
+		logrus.Warnf("Image '%s' not found on provider side, cleaning up metadata", image.Name)
+	}
+
+	err = mi.Delete()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Image '%s' deleted", image.Name)
+	return nil
+}
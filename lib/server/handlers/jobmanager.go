@@ -27,7 +27,8 @@ import (
 
 // JobManagerAPI defines API to manipulate process
 type JobManagerAPI interface {
-	List(ctx context.Context) (map[string]string, error)
+	List(ctx context.Context, all bool) ([]srvutils.JobRecord, error)
+	Inspect(ctx context.Context, uuid string) (*srvutils.JobRecord, error)
 	Stop(ctx context.Context, uuid string)
 }
 
@@ -43,9 +44,14 @@ func NewJobHandler(svc iaas.Service) JobManagerAPI {
 	}
 }
 
-// List returns the Running Process list
-func (pmh *JobManagerHandler) List(ctx context.Context) (map[string]string, error) {
-	return srvutils.JobList(), nil
+// List returns the running jobs and, if all is true, the finished jobs kept in history
+func (pmh *JobManagerHandler) List(ctx context.Context, all bool) ([]srvutils.JobRecord, error) {
+	return srvutils.JobRecords(all)
+}
+
+// Inspect returns the record of a job, running or kept in history
+func (pmh *JobManagerHandler) Inspect(ctx context.Context, uuid string) (*srvutils.JobRecord, error) {
+	return srvutils.JobInspect(uuid)
 }
 
 // Stop stop the designed Process
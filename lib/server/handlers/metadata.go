@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	utilsmetadata "github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+//go:generate mockgen -destination=../mocks/mock_metadataapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers MetadataAPI
+
+// MetadataAPI defines API to browse and restore the history of the metadata
+type MetadataAPI interface {
+	ListRevisions(ctx context.Context, kind, ref string) ([]utilsmetadata.Revision, error)
+	Restore(ctx context.Context, kind, ref, revision string) error
+}
+
+// MetadataHandler metadata service
+type MetadataHandler struct {
+	service iaas.Service
+}
+
+// NewMetadataHandler creates a Metadata service
+func NewMetadataHandler(svc iaas.Service) MetadataAPI {
+	return &MetadataHandler{
+		service: svc,
+	}
+}
+
+// ListRevisions returns the revisions kept in the history of the metadata of kind 'kind' (host, network, ...) designated by 'ref'
+func (handler *MetadataHandler) ListRevisions(ctx context.Context, kind, ref string) (revisions []utilsmetadata.Revision, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", kind, ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return metadata.Revisions(handler.service, kind, ref)
+}
+
+// Restore rolls the metadata of kind 'kind' designated by 'ref' back to the revision 'revision'
+func (handler *MetadataHandler) Restore(ctx context.Context, kind, ref, revision string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s')", kind, ref, revision), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return metadata.RestoreRevision(handler.service, kind, ref, revision)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
 
@@ -45,6 +46,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
@@ -54,11 +56,13 @@ import (
 
 // NetworkAPI defines API to manage networks
 type NetworkAPI interface {
-	Create(context.Context, string, string, ipversion.Enum, resources.SizingRequirements, string, string, bool) (*resources.Network, error)
-	List(context.Context, bool) ([]*resources.Network, error)
+	Create(context.Context, string, string, ipversion.Enum, resources.SizingRequirements, string, string, bool, map[string]string) (*resources.Network, error)
+	List(context.Context, bool, string) ([]*resources.Network, error)
 	Inspect(context.Context, string) (*resources.Network, error)
 	Delete(context.Context, string) error
 	Destroy(context.Context, string) error
+	SetTags(context.Context, string, map[string]string, []string) (map[string]string, error)
+	Adopt(context.Context, string) (*resources.Network, error)
 }
 
 // NetworkHandler an implementation of NetworkAPI
@@ -79,7 +83,7 @@ func (handler *NetworkHandler) Create(
 	ctx context.Context,
 	name string, cidr string, ipVersion ipversion.Enum,
 	sizing resources.SizingRequirements, theos string, gwname string,
-	failover bool,
+	failover bool, tags map[string]string,
 ) (network *resources.Network, err error) {
 
 	if handler == nil {
@@ -91,6 +95,9 @@ func (handler *NetworkHandler) Create(
 	if failover && gwname != "" {
 		return nil, scerr.InvalidParameterError("gwname", "cannot be set if failover is set")
 	}
+	if err = resources.ValidateTags(tags); err != nil {
+		return nil, err
+	}
 
 	tracer := concurrency.NewTracer(
 		nil,
@@ -125,11 +132,14 @@ func (handler *NetworkHandler) Create(
 
 	// Create the network
 	logrus.Debugf("Creating network '%s' ...", name)
+	phaseDone := safescaleutils.StartPhase(ctx, "network", name)
 	network, err = handler.service.CreateNetwork(resources.NetworkRequest{
 		Name:      name,
 		IPVersion: ipVersion,
 		CIDR:      cidr,
+		Tags:      tags,
 	})
+	phaseDone(err)
 	if err != nil {
 		switch err.(type) {
 		case scerr.ErrNotFound, scerr.ErrInvalidRequest, scerr.ErrTimeout:
@@ -194,6 +204,14 @@ func (handler *NetworkHandler) Create(
 		}()
 	}
 
+	if network.Properties == nil {
+		network.Properties = serialize.NewJSONProperties("resources.network")
+	}
+	err = writeTags(network.Properties, networkproperty.TagsV1, tags)
+	if err != nil {
+		return nil, err
+	}
+
 	logrus.Debugf("Saving network metadata '%s' ...", network.Name)
 	mn, err := metadata.SaveNetwork(handler.service, network)
 	if err != nil {
@@ -517,7 +535,9 @@ func (handler *NetworkHandler) createGateway(t concurrency.Task, params concurre
 	primary := inputs["primary"].(bool)
 
 	logrus.Infof("Requesting the creation of gateway '%s' using template '%s' with image '%s'", request.Name, request.TemplateID, request.ImageID)
+	phaseDone := safescaleutils.StartPhase(t.GetContext(), "compute", request.Name)
 	gw, userData, err := handler.service.CreateGateway(request)
+	phaseDone(err)
 	if err != nil {
 		switch err.(type) {
 		case scerr.ErrNotFound, scerr.ErrTimeout:
@@ -610,6 +630,9 @@ func (handler *NetworkHandler) waitForInstallPhase1OnGateway(
 ) (result concurrency.TaskResult, err error) {
 
 	gw := params.(*resources.Host)
+	if handler.service.GetCapabilities().SimulatedHosts {
+		return nil, nil
+	}
 
 	// A host claimed ready by a Cloud provider is not necessarily ready
 	// to be used until ssh service is up and running. So we wait for it before
@@ -622,7 +645,9 @@ func (handler *NetworkHandler) waitForInstallPhase1OnGateway(
 	}
 
 	logrus.Debugf("Provisioning gateway '%s', phase 1", gw.Name)
+	phaseDone := safescaleutils.StartPhase(task.GetContext(), "phase1", gw.Name)
 	_, err = ssh.WaitServerReady("phase1", temporal.GetHostCreationTimeout())
+	phaseDone(err)
 	if err != nil {
 		if client.IsTimeoutError(err) {
 			return nil, err
@@ -633,6 +658,7 @@ func (handler *NetworkHandler) waitForInstallPhase1OnGateway(
 		return nil, err
 	}
 	logrus.Infof("SSH service of gateway '%s' started.", gw.Name)
+	safescaleutils.ReportProgress(task.GetContext(), safescaleutils.ProgressSSHReady, "phase1", gw.Name, "")
 
 	return nil, nil
 }
@@ -649,6 +675,9 @@ func (handler *NetworkHandler) installPhase2OnGateway(task concurrency.Task, par
 	if userData, ok = params.(data.Map)["userdata"].(*userdata.Content); !ok {
 		return nil, scerr.InvalidParameterError("params", "missing field 'userdata'")
 	}
+	if handler.service.GetCapabilities().SimulatedHosts {
+		return nil, nil
+	}
 
 	// Executes userdata phase2 script to finalize host installation
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", gw.Name), true).WithStopwatch().GoingIn()
@@ -658,12 +687,16 @@ func (handler *NetworkHandler) installPhase2OnGateway(task concurrency.Task, par
 		fmt.Sprintf("Starting configuration phase 2 on the gateway '%s'...", gw.Name),
 		fmt.Sprintf("Ending configuration phase 2 on the gateway '%s'", gw.Name),
 	)()
+	phaseDone := safescaleutils.StartPhase(task.GetContext(), "phase2", gw.Name)
+	defer func() {
+		phaseDone(err)
+	}()
 
 	content, err := userData.Generate("phase2")
 	if err != nil {
 		return nil, err
 	}
-	err = install.UploadStringToRemoteFile(string(content), safescaleutils.ToPBHost(gw), utils.TempFolder+"/user_data.phase2.sh", "", "", "")
+	err = install.UploadStringToRemoteFile(task, string(content), safescaleutils.ToPBHost(gw), utils.TempFolder+"/user_data.phase2.sh", "", "", "")
 	if err != nil {
 		return nil, err
 	}
@@ -718,6 +751,7 @@ func (handler *NetworkHandler) installPhase2OnGateway(task concurrency.Task, par
 		}
 		return nil, err
 	}
+	safescaleutils.ReportProgress(task.GetContext(), safescaleutils.ProgressSSHReady, "ready", gw.Name, "")
 	return nil, nil
 }
 
@@ -768,12 +802,19 @@ func (handler *NetworkHandler) unbindHostFromVIP(vip *resources.VirtualIP, host
 }
 
 // List returns the network list
-func (handler *NetworkHandler) List(ctx context.Context, all bool) (netList []*resources.Network, err error) {
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+func (handler *NetworkHandler) List(ctx context.Context, all bool, selector string) (netList []*resources.Network, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v, '%s')", all, selector), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
+	tagSelector, err := resources.ParseTagSelector(selector)
+	if err != nil {
+		return nil, err
+	}
 	if all {
+		if !tagSelector.Empty() {
+			return nil, scerr.InvalidParameterError("selector", "cannot be used when listing all the networks of the tenant, tags are only known by SafeScale")
+		}
 		return handler.service.ListNetworks()
 	}
 
@@ -782,7 +823,13 @@ func (handler *NetworkHandler) List(ctx context.Context, all bool) (netList []*r
 		return nil, err
 	}
 	err = mn.Browse(func(network *resources.Network) error {
-		netList = append(netList, network)
+		tags, err := readTags(network.Properties, networkproperty.TagsV1)
+		if err != nil {
+			return err
+		}
+		if tagSelector.Matches(tags) {
+			netList = append(netList, network)
+		}
 		return nil
 	})
 
@@ -829,6 +876,16 @@ func (handler *NetworkHandler) Delete(ctx context.Context, ref string) (err erro
 		}
 		return err
 	}
+	// Locks the metadata of the network until it is deleted, so that no host can be added to it meanwhile
+	err = mn.Acquire()
+	if err != nil {
+		return err
+	}
+	defer mn.Release()
+	err = mn.Reload()
+	if err != nil {
+		return err
+	}
 	network, err := mn.Get()
 	if err != nil {
 		return err
@@ -898,7 +955,12 @@ func (handler *NetworkHandler) Delete(ctx context.Context, ref string) (err erro
 				}
 			}
 
+			err = mh.Acquire()
+			if err != nil {
+				return err
+			}
 			err = mh.Delete()
+			mh.Release()
 			if err != nil {
 				return err
 			}
@@ -928,7 +990,12 @@ func (handler *NetworkHandler) Delete(ctx context.Context, ref string) (err erro
 				}
 			}
 
+			err = mh.Acquire()
+			if err != nil {
+				return err
+			}
 			err = mh.Delete()
+			mh.Release()
 			if err != nil {
 				return err
 			}
@@ -1035,6 +1102,16 @@ func (handler *NetworkHandler) Destroy(ctx context.Context, ref string) (err err
 		}
 		return err
 	}
+	// Locks the metadata of the network until it is deleted, so that no host can be added to it meanwhile
+	err = mn.Acquire()
+	if err != nil {
+		return err
+	}
+	defer mn.Release()
+	err = mn.Reload()
+	if err != nil {
+		return err
+	}
 	network, err := mn.Get()
 	if err != nil {
 		return err
@@ -1069,7 +1146,12 @@ func (handler *NetworkHandler) Destroy(ctx context.Context, ref string) (err err
 				}
 			}
 
+			err = mh.Acquire()
+			if err != nil {
+				return err
+			}
 			err = mh.Delete()
+			mh.Release()
 			if err != nil {
 				return err
 			}
@@ -1099,7 +1181,12 @@ func (handler *NetworkHandler) Destroy(ctx context.Context, ref string) (err err
 				}
 			}
 
+			err = mh.Acquire()
+			if err != nil {
+				return err
+			}
 			err = mh.Delete()
+			mh.Release()
 			if err != nil {
 				return err
 			}
@@ -1183,3 +1270,149 @@ func (handler *NetworkHandler) Destroy(ctx context.Context, ref string) (err err
 
 	return nil
 }
+
+// SetTags sets the tags in set and removes the tags in unset on the network identified by ref, then returns the
+// resulting tags. Tags of networks are only kept in metadata
+func (handler *NetworkHandler) SetTags(ctx context.Context, ref string, set map[string]string, unset []string) (tags map[string]string, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if err = resources.ValidateTags(set); err != nil {
+		return nil, err
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mn, err := metadata.LoadNetwork(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("network", ref)
+		}
+		return nil, err
+	}
+	err = mn.Acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer mn.Release()
+	err = mn.Reload()
+	if err != nil {
+		return nil, err
+	}
+	network, err := mn.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := readTags(network.Properties, networkproperty.TagsV1)
+	if err != nil {
+		return nil, err
+	}
+	tags = resources.MergeTags(current, set, unset)
+	if network.Properties == nil {
+		network.Properties = serialize.NewJSONProperties("resources.network")
+	}
+	err = writeTags(network.Properties, networkproperty.TagsV1, tags)
+	if err != nil {
+		return nil, err
+	}
+	err = mn.Write()
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Adopt creates the metadata of the network 'id' of the provider, created outside of SafeScale, to manage it
+// Its gateway, if any, has to be adopted afterwards (see HostHandler.Adopt)
+func (handler *NetworkHandler) Adopt(ctx context.Context, id string) (network *resources.Network, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return adoptNetwork(handler.service, id)
+}
+
+// adoptNetwork creates the metadata of the network 'id' of the provider, created outside of SafeScale
+func adoptNetwork(svc iaas.Service, id string) (*resources.Network, error) {
+	network, err := svc.GetNetwork(id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = metadata.LoadNetwork(svc, network.Name)
+	if err == nil {
+		return nil, scerr.DuplicateError(fmt.Sprintf("a network named '%s' is already managed", network.Name))
+	}
+	if _, ok := err.(scerr.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	if network.Properties == nil {
+		network.Properties = serialize.NewJSONProperties("resources.network")
+	}
+	err = network.Properties.LockForWrite(networkproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.NetworkDescription).Purpose = "adopted by " + currentCreator()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = metadata.SaveNetwork(svc, network)
+	if err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+// lockNetworkMetadata takes the lock of the metadata of the network 'ref', then reads them again since they may have been
+// changed before being locked; the lock is kept until the returned function is called
+func lockNetworkMetadata(svc iaas.Service, ref string) (*metadata.Network, *resources.Network, func(), error) {
+	mn, err := metadata.LoadNetwork(svc, ref)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = mn.Acquire()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var once sync.Once
+	release := func() { once.Do(mn.Release) }
+	err = mn.Reload()
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	network, err := mn.Get()
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	return mn, network, release, nil
+}
+
+// updateNetworkMetadata applies 'updatefn' to the network 'ref' while its metadata is locked, then saves it
+func updateNetworkMetadata(svc iaas.Service, ref string, updatefn func(*resources.Network) error) error {
+	mn, network, release, err := lockNetworkMetadata(svc, ref)
+	if err != nil {
+		return err
+	}
+	defer release()
+	err = updatefn(network)
+	if err != nil {
+		return err
+	}
+	return mn.Write()
+}
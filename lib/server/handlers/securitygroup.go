@@ -0,0 +1,473 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/securitygroupproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+//go:generate mockgen -destination=../mocks/mock_securitygroupapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers SecurityGroupAPI
+
+// SecurityGroupAPI defines API to manipulate security groups
+type SecurityGroupAPI interface {
+	Create(ctx context.Context, name string, description string, networkRef string) (*resources.SecurityGroup, error)
+	List(ctx context.Context, all bool) ([]*resources.SecurityGroup, error)
+	Inspect(ctx context.Context, ref string) (*resources.SecurityGroup, error)
+	Delete(ctx context.Context, ref string) error
+	AddRule(ctx context.Context, ref string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error)
+	DeleteRule(ctx context.Context, ref string, ruleID string) (*resources.SecurityGroup, error)
+	Bind(ctx context.Context, ref string, hostRef string) error
+	Unbind(ctx context.Context, ref string, hostRef string) error
+}
+
+// SecurityGroupHandler security group service
+type SecurityGroupHandler struct {
+	service iaas.Service
+}
+
+// NewSecurityGroupHandler creates a SecurityGroup service
+func NewSecurityGroupHandler(svc iaas.Service) SecurityGroupAPI {
+	return &SecurityGroupHandler{
+		service: svc,
+	}
+}
+
+// Create creates a security group without rules
+func (handler *SecurityGroupHandler) Create(ctx context.Context, name string, description string, networkRef string) (sg *resources.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, networkRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	msg, err := metadata.LoadSecurityGroup(handler.service, name)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return nil, err
+		}
+	} else if msg != nil {
+		return nil, resources.ResourceDuplicateError("security group", name)
+	}
+
+	request := resources.SecurityGroupRequest{
+		Name:        name,
+		Description: description,
+	}
+	if networkRef != "" {
+		mn, err := metadata.LoadNetwork(handler.service, networkRef)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); ok {
+				return nil, resources.ResourceNotFoundError("network", networkRef)
+			}
+			return nil, err
+		}
+		network, err := mn.Get()
+		if err != nil {
+			return nil, err
+		}
+		request.NetworkID = network.ID
+	}
+
+	created, err := handler.service.CreateSecurityGroup(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			derr := handler.service.DeleteSecurityGroup(created.ID)
+			if derr != nil {
+				logrus.Errorf("failed to delete security group '%s' after failure: %v", name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	msg, err = metadata.SaveSecurityGroup(handler.service, created)
+	if err != nil {
+		return nil, err
+	}
+
+	// Starting from here, remove metadata if exiting with error
+	defer func() {
+		if err != nil {
+			derr := msg.Delete()
+			if derr != nil {
+				logrus.Errorf("failed to remove metadata of security group '%s' after failure: %v", name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Security group creation cancelled by user")
+		err = fmt.Errorf("security group creation cancelled by user")
+		return nil, err
+	default:
+	}
+
+	logrus.Infof("Security group '%s' created successfully", name)
+	return created, nil
+}
+
+// List returns the security groups created by SafeScale, or all the security groups of the tenant if all is true
+func (handler *SecurityGroupHandler) List(ctx context.Context, all bool) (list []*resources.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if all {
+		return handler.service.ListSecurityGroups()
+	}
+
+	msg, err := metadata.NewSecurityGroup(handler.service)
+	if err != nil {
+		return nil, err
+	}
+	err = msg.Browse(func(sg *resources.SecurityGroup) error {
+		list = append(list, sg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Inspect returns the security group identified by ref, with the rules known by the provider
+func (handler *SecurityGroupHandler) Inspect(ctx context.Context, ref string) (sg *resources.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	_, sg, err = handler.load(ref)
+	if err != nil {
+		return nil, err
+	}
+	current, err := handler.service.InspectSecurityGroup(sg.ID)
+	if err != nil {
+		return nil, err
+	}
+	sg.Rules = current.Rules
+	return sg, nil
+}
+
+// load returns the metadata of the security group identified by ref
+func (handler *SecurityGroupHandler) load(ref string) (*metadata.SecurityGroup, *resources.SecurityGroup, error) {
+	msg, err := metadata.LoadSecurityGroup(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, nil, resources.ResourceNotFoundError("security group", ref)
+		}
+		return nil, nil, err
+	}
+	sg, err := msg.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, sg, nil
+}
+
+// Delete deletes the security group identified by ref; it must not be bound to hosts anymore
+func (handler *SecurityGroupHandler) Delete(ctx context.Context, ref string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	msg, sg, err := handler.load(ref)
+	if err != nil {
+		return err
+	}
+	err = sg.Properties.LockForRead(securitygroupproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+		sgHostsV1 := clonable.(*propsv1.SecurityGroupHosts)
+		nbHosts := len(sgHostsV1.ByName)
+		if nbHosts > 0 {
+			var list []string
+			for k := range sgHostsV1.ByName {
+				list = append(list, k)
+			}
+			return scerr.NotAvailableError(fmt.Sprintf("security group '%s' is still bound to %d host%s: %s", sg.Name, nbHosts, utils.Plural(nbHosts), strings.Join(list, ", ")))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = handler.service.DeleteSecurityGroup(sg.ID)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return err
+		}
+		logrus.Warnf("security group '%s' not found on provider side, cleaning up metadata", sg.Name)
+	}
+	err = msg.Delete()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Security group '%s' deleted successfully", sg.Name)
+	return nil
+}
+
+// AddRule adds a rule to the security group identified by ref
+func (handler *SecurityGroupHandler) AddRule(ctx context.Context, ref string, rule resources.SecurityGroupRule) (sg *resources.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = rule.Validate()
+	if err != nil {
+		return nil, err
+	}
+	msg, sg, err := handler.load(ref)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := handler.service.AddRuleToSecurityGroup(sg.ID, rule)
+	if err != nil {
+		return nil, err
+	}
+	sg.Rules = updated.Rules
+	err = msg.Write()
+	if err != nil {
+		return nil, err
+	}
+	return sg, nil
+}
+
+// DeleteRule deletes the rule identified by ruleID from the security group identified by ref
+func (handler *SecurityGroupHandler) DeleteRule(ctx context.Context, ref string, ruleID string) (sg *resources.SecurityGroup, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if ruleID == "" {
+		return nil, scerr.InvalidParameterError("ruleID", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, ruleID), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	msg, sg, err := handler.load(ref)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := handler.service.DeleteRuleFromSecurityGroup(sg.ID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	sg.Rules = updated.Rules
+	err = msg.Write()
+	if err != nil {
+		return nil, err
+	}
+	return sg, nil
+}
+
+// Bind applies the rules of the security group identified by ref to the host identified by hostRef
+func (handler *SecurityGroupHandler) Bind(ctx context.Context, ref string, hostRef string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if hostRef == "" {
+		return scerr.InvalidParameterError("hostRef", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	msg, sg, err := handler.load(ref)
+	if err != nil {
+		return err
+	}
+	mh, host, release, err := lockHostMetadata(handler.service, hostRef)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return resources.ResourceNotFoundError("host", hostRef)
+		}
+		return err
+	}
+	defer release()
+
+	err = handler.service.BindSecurityGroupToHost(sg.ID, host.ID)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			derr := handler.service.UnbindSecurityGroupFromHost(sg.ID, host.ID)
+			if derr != nil {
+				logrus.Errorf("failed to unbind security group '%s' from host '%s' after failure: %v", sg.Name, host.Name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	err = setSecurityGroupBinding(sg, host, true)
+	if err != nil {
+		return err
+	}
+	err = mh.Write()
+	if err != nil {
+		return err
+	}
+	err = msg.Write()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Security group '%s' bound to host '%s'", sg.Name, host.Name)
+	return nil
+}
+
+// Unbind removes the rules of the security group identified by ref from the host identified by hostRef
+func (handler *SecurityGroupHandler) Unbind(ctx context.Context, ref string, hostRef string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if hostRef == "" {
+		return scerr.InvalidParameterError("hostRef", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	msg, sg, err := handler.load(ref)
+	if err != nil {
+		return err
+	}
+	mh, host, release, err := lockHostMetadata(handler.service, hostRef)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return resources.ResourceNotFoundError("host", hostRef)
+		}
+		return err
+	}
+	defer release()
+
+	err = handler.service.UnbindSecurityGroupFromHost(sg.ID, host.ID)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return err
+		}
+		logrus.Warnf("host '%s' not found on provider side, cleaning up metadata", host.Name)
+	}
+
+	err = setSecurityGroupBinding(sg, host, false)
+	if err != nil {
+		return err
+	}
+	err = mh.Write()
+	if err != nil {
+		return err
+	}
+	err = msg.Write()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Security group '%s' unbound from host '%s'", sg.Name, host.Name)
+	return nil
+}
+
+// setSecurityGroupBinding records (or forgets if bound is false) the binding between sg and host in their properties
+func setSecurityGroupBinding(sg *resources.SecurityGroup, host *resources.Host, bound bool) error {
+	err := sg.Properties.LockForWrite(securitygroupproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+		sgHostsV1 := clonable.(*propsv1.SecurityGroupHosts)
+		if bound {
+			sgHostsV1.ByID[host.ID] = host.Name
+			sgHostsV1.ByName[host.Name] = host.ID
+		} else {
+			delete(sgHostsV1.ByID, host.ID)
+			delete(sgHostsV1.ByName, host.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return host.Properties.LockForWrite(hostproperty.SecurityGroupsV1).ThenUse(func(clonable data.Clonable) error {
+		hostSecurityGroupsV1 := clonable.(*propsv1.HostSecurityGroups)
+		if bound {
+			hostSecurityGroupsV1.ByID[sg.ID] = sg.Name
+			hostSecurityGroupsV1.ByName[sg.Name] = sg.ID
+		} else {
+			delete(hostSecurityGroupsV1.ByID, sg.ID)
+			delete(hostSecurityGroupsV1.ByName, sg.Name)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/tests"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+func TestSecurityGroupHandler_Create_cancelled(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestSecurityGroupCancel")
+	handler := NewSecurityGroupHandler(svc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := handler.Create(ctx, "sg-cancelled", "", "")
+	require.NotNil(t, err)
+
+	// Neither the security group nor its metadata must be left behind
+	_, err = metadata.LoadSecurityGroup(svc, "sg-cancelled")
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok)
+	groups, err := svc.ListSecurityGroups()
+	require.Nil(t, err)
+	assert.Empty(t, groups)
+}
@@ -109,6 +109,14 @@ func (handler *ShareHandler) Create(
 		return nil, err
 	}
 
+	// Locks the metadata of the host until the share is recorded, so that the changes made concurrently by someone
+	// else are not overwritten
+	mh, server, release, err := lockHostMetadata(handler.service, server.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// Check if the path to share isn't a remote mount or contains a remote mount
 	err = server.Properties.LockForRead(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
 		serverMountsV1 := clonable.(*propsv1.HostMounts)
@@ -189,7 +197,7 @@ func (handler *ShareHandler) Create(
 		return nil, err
 	}
 
-	mh, err := metadata.SaveHost(handler.service, server)
+	err = mh.Write()
 	if err != nil {
 		return nil, err
 	}
@@ -261,8 +269,20 @@ func (handler *ShareHandler) Delete(ctx context.Context, name string) (err error
 		return fmt.Errorf("delete share: unable to found share of host '%s'", name)
 	}
 
+	// Locks the metadata of the host until the share is removed from them, so that it cannot be mounted meanwhile
+	mh, server, release, err := lockHostMetadata(handler.service, server.ID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	err = server.Properties.LockForWrite(hostproperty.SharesV1).ThenUse(func(clonable data.Clonable) error {
 		serverSharesV1 := clonable.(*propsv1.HostShares)
+		current, found := serverSharesV1.ByID[share.ID]
+		if !found {
+			return resources.ResourceNotFoundError("share", name)
+		}
+		share = current
 		if len(share.ClientsByName) > 0 {
 			var list []string
 			for k := range share.ClientsByName {
@@ -295,7 +315,7 @@ func (handler *ShareHandler) Delete(ctx context.Context, name string) (err error
 	}
 
 	// Save server metadata
-	_, err = metadata.SaveHost(handler.service, server)
+	err = mh.Write()
 	if err != nil {
 		return err
 	}
@@ -305,6 +325,7 @@ func (handler *ShareHandler) Delete(ctx context.Context, name string) (err error
 	if err != nil {
 		return err
 	}
+	release()
 
 	select {
 	case <-ctx.Done():
@@ -415,6 +436,24 @@ func (handler *ShareHandler) Mount(
 		}
 	}
 
+	// Locks the metadata of the server and of the target until the mount is recorded, so that the changes made
+	// concurrently by someone else are not overwritten
+	locked, release, err := lockHosts(handler.service, server.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	mh := locked[server.ID]
+	server, err = mh.Get()
+	if err != nil {
+		return nil, err
+	}
+	mt := locked[target.ID]
+	target, err = mt.Get()
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if share is already mounted
 	// Check if there is already volume mounted in the path (or in subpath)
 	err = target.Properties.LockForRead(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
@@ -539,7 +578,7 @@ func (handler *ShareHandler) Mount(
 		return nil, err
 	}
 
-	mh, err := metadata.SaveHost(handler.service, server)
+	err = mh.Write()
 	if err != nil {
 		return nil, err
 	}
@@ -564,7 +603,7 @@ func (handler *ShareHandler) Mount(
 	}()
 
 	if target != server {
-		_, err = metadata.SaveHost(handler.service, target)
+		err = mt.Write()
 		if err != nil {
 			return nil, err
 		}
@@ -584,7 +623,7 @@ func (handler *ShareHandler) Mount(
 				log.Warnf("failed to remove mounted share '%s' from host '%s' metadata", shareName, hostName)
 				err = scerr.AddConsequence(err, err2)
 			}
-			_, err2 = metadata.SaveHost(handler.service, target)
+			err2 = mt.Write()
 			if err2 != nil {
 				log.Warnf("failed to save host '%s' metadata : %s", hostName, err2.Error())
 				err = scerr.AddConsequence(err, err2)
@@ -619,6 +658,35 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 		return err
 	}
 
+	var target *resources.Host
+	if server.Name == hostName || server.ID == hostName {
+		target = server
+	} else {
+		hostSvc := NewHostHandler(handler.service)
+		target, err = hostSvc.ForceInspect(ctx, hostName)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Locks the metadata of the server and of the target until the unmount is recorded, so that the changes made
+	// concurrently by someone else are not overwritten
+	locked, release, err := lockHosts(handler.service, server.ID, target.ID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	mh := locked[server.ID]
+	server, err = mh.Get()
+	if err != nil {
+		return err
+	}
+	mt := locked[target.ID]
+	target, err = mt.Get()
+	if err != nil {
+		return err
+	}
+
 	var shareID string
 	err = server.Properties.LockForRead(hostproperty.SharesV1).ThenUse(func(clonable data.Clonable) error {
 		serverSharesV1 := clonable.(*propsv1.HostShares)
@@ -635,17 +703,6 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 		return err
 	}
 
-	var target *resources.Host
-	if server.Name == hostName || server.ID == hostName {
-		target = server
-	} else {
-		hostSvc := NewHostHandler(handler.service)
-		target, err = hostSvc.ForceInspect(ctx, hostName)
-		if err != nil {
-			return err
-		}
-	}
-
 	var mountPath string
 	err = target.Properties.LockForWrite(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
 		targetMountsV1 := clonable.(*propsv1.HostMounts)
@@ -691,16 +748,17 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 	}
 
 	// Saves metadata
-	_, err = metadata.SaveHost(handler.service, server)
+	err = mh.Write()
 	if err != nil {
 		return err
 	}
 	if server != target {
-		_, err = metadata.SaveHost(handler.service, target)
+		err = mt.Write()
 		if err != nil {
 			return err
 		}
 	}
+	release()
 
 	select {
 	case <-ctx.Done():
@@ -119,6 +119,10 @@ func (handler *SSHHandler) GetConfig(ctx context.Context, hostParam interface{})
 		Host:       host.GetAccessIP(),
 		User:       user,
 	}
+	err = applySSHSettings(host, sshConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
 		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
@@ -134,6 +138,10 @@ func (handler *SSHHandler) GetConfig(ctx context.Context, hostParam interface{})
 				Host:       gw.GetAccessIP(),
 				User:       user,
 			}
+			err = applySSHSettings(gw, &GatewayConfig)
+			if err != nil {
+				return err
+			}
 			sshConfig.GatewayConfig = &GatewayConfig
 		}
 		return nil
@@ -147,6 +155,23 @@ func (handler *SSHHandler) GetConfig(ctx context.Context, hostParam interface{})
 	return sshConfig, nil
 }
 
+// applySSHSettings overrides the user and the port of 'sshConfig' with the SSH access recorded for 'host', if any
+func applySSHSettings(host *resources.Host, sshConfig *system.SSHConfig) error {
+	if host.Properties == nil || !host.Properties.Lookup(hostproperty.SSHV1) {
+		return nil
+	}
+	return host.Properties.LockForRead(hostproperty.SSHV1).ThenUse(func(clonable data.Clonable) error {
+		hostSSHV1 := clonable.(*propsv1.HostSSH)
+		if hostSSHV1.User != "" {
+			sshConfig.User = hostSSHV1.User
+		}
+		if hostSSHV1.Port != 0 {
+			sshConfig.Port = hostSSHV1.Port
+		}
+		return nil
+	})
+}
+
 // WaitServerReady waits for remote SSH server to be ready. After timeout, fails
 func (handler *SSHHandler) WaitServerReady(ctx context.Context, hostParam interface{}, timeout time.Duration) (err error) {
 	if handler == nil {
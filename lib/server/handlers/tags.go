@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"github.com/sirupsen/logrus"
+
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// readTags returns a copy of the tags stored in the property key of props
+func readTags(props *serialize.JSONProperties, key string) (tags map[string]string, err error) {
+	tags = map[string]string{}
+	if props == nil || !props.Lookup(key) {
+		return tags, nil
+	}
+	err = props.LockForRead(key).ThenUse(func(clonable data.Clonable) error {
+		for k, v := range clonable.(*propsv1.ResourceTags).Tags {
+			tags[k] = v
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// writeTags replaces the tags stored in the property key of props
+func writeTags(props *serialize.JSONProperties, key string, tags map[string]string) error {
+	return props.LockForWrite(key).ThenUse(func(clonable data.Clonable) error {
+		tagsV1 := clonable.(*propsv1.ResourceTags)
+		tagsV1.Tags = make(map[string]string, len(tags))
+		for k, v := range tags {
+			tagsV1.Tags[k] = v
+		}
+		return nil
+	})
+}
+
+// propagateTags reports the result of the update of tags on provider side; providers without support of
+// tags (or labels) are not an error, the tags remaining in the metadata of SafeScale
+func propagateTags(kind string, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(scerr.ErrNotImplemented); ok {
+		logrus.Debugf("Tags of %s '%s' not propagated: provider doesn't support tags", kind, name)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,581 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+//go:generate mockgen -destination=../mocks/mock_tenantapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers TenantAPI
+
+// Categories of the inconsistencies found by Fsck
+const (
+	// FsckOrphanMetadata is the category of the metadata of resources that don't exist anymore at the provider
+	FsckOrphanMetadata = "orphan-metadata"
+	// FsckUnmanagedResource is the category of the resources of the provider without metadata
+	FsckUnmanagedResource = "unmanaged-resource"
+	// FsckDanglingReference is the category of the references, inside metadata, to resources that don't exist anymore
+	FsckDanglingReference = "dangling-reference"
+)
+
+// FsckFinding describes an inconsistency between the metadata and the resources of the provider
+type FsckFinding struct {
+	Category string // Category is one of FsckOrphanMetadata, FsckUnmanagedResource or FsckDanglingReference
+	Kind     string // Kind is the kind of the resource concerned (host, network, volume or cluster)
+	ID       string // ID is the id of the resource concerned (empty for a cluster)
+	Name     string // Name is the name of the resource concerned
+	Detail   string // Detail explains the inconsistency
+	Repaired bool   // Repaired tells if the inconsistency has been repaired
+	Error    string // Error contains the reason of the failure of the repair, if any
+}
+
+// TenantAPI defines API to check the tenants
+type TenantAPI interface {
+	Fsck(ctx context.Context, repair bool) ([]FsckFinding, error)
+}
+
+// TenantHandler tenant service
+type TenantHandler struct {
+	service iaas.Service
+}
+
+// NewTenantHandler creates a Tenant service
+func NewTenantHandler(svc iaas.Service) TenantAPI {
+	return &TenantHandler{
+		service: svc,
+	}
+}
+
+// Fsck cross-checks the metadata of hosts, networks, volumes and clusters with the resources of the provider, and
+// returns the inconsistencies found, sorted by category.
+// If 'repair' is true, the metadata of the resources that don't exist anymore are removed, the dangling references
+// are removed from the metadata, and the networks and volumes without metadata that follow the naming of SafeScale
+// are adopted (see checkNetworks and checkVolumes). The hosts without metadata, whose private key is not known, and
+// the other resources are left to an explicit adoption.
+func (handler *TenantHandler) Fsck(ctx context.Context, repair bool) (findings []FsckFinding, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", repair), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	f := &fsck{service: handler.service, repair: repair}
+	err = f.listResources()
+	if err != nil {
+		return nil, err
+	}
+	// Networks first: the hosts are reported with the managed network they are connected to
+	for _, check := range []func() error{f.checkNetworks, f.checkHosts, f.checkVolumes, f.checkClusters} {
+		err = check()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(f.findings, func(i, j int) bool {
+		a, b := f.findings[i], f.findings[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return f.findings, nil
+}
+
+// fsck contains the state of a check of the metadata
+type fsck struct {
+	service  iaas.Service
+	repair   bool
+	findings []FsckFinding
+
+	// names of the resources of the provider, indexed by id
+	hosts    map[string]string
+	networks map[string]string
+	volumes  map[string]string
+
+	// names of the resources of the provider with metadata (or adopted by the check, for networks), indexed by id
+	managedHosts    map[string]string
+	managedNetworks map[string]string
+}
+
+// listResources lists the resources of the provider
+// An error here must stop the check: all the metadata would be seen as orphans
+func (f *fsck) listResources() error {
+	hosts, err := f.service.ListHosts()
+	if err != nil {
+		return scerr.Wrap(err, "failed to list hosts of provider")
+	}
+	f.hosts = map[string]string{}
+	for _, h := range hosts {
+		f.hosts[h.ID] = h.Name
+	}
+
+	networks, err := f.service.ListNetworks()
+	if err != nil {
+		return scerr.Wrap(err, "failed to list networks of provider")
+	}
+	f.networks = map[string]string{}
+	for _, n := range networks {
+		f.networks[n.ID] = n.Name
+	}
+
+	volumes, err := f.service.ListVolumes()
+	if err != nil {
+		return scerr.Wrap(err, "failed to list volumes of provider")
+	}
+	f.volumes = map[string]string{}
+	for _, v := range volumes {
+		f.volumes[v.ID] = v.Name
+	}
+	return nil
+}
+
+// report records an inconsistency and, if asked for, repairs it with 'repairfn' (nil if it cannot be repaired)
+func (f *fsck) report(category, kind, id, name, detail string, repairfn func() error) {
+	finding := FsckFinding{
+		Category: category,
+		Kind:     kind,
+		ID:       id,
+		Name:     name,
+		Detail:   detail,
+	}
+	if f.repair {
+		if repairfn == nil {
+			finding.Error = "cannot be repaired automatically"
+		} else if err := repairfn(); err != nil {
+			finding.Error = err.Error()
+		} else {
+			finding.Repaired = true
+		}
+	}
+	f.findings = append(f.findings, finding)
+}
+
+// checkNetworks checks the metadata of the networks
+func (f *fsck) checkNetworks() error {
+	mn, err := metadata.NewNetwork(f.service)
+	if err != nil {
+		return err
+	}
+	var managed []*resources.Network
+	err = mn.Browse(func(network *resources.Network) error {
+		managed = append(managed, network)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	known := map[string]bool{}
+	f.managedNetworks = map[string]string{}
+	for _, network := range managed {
+		network := network
+		known[network.ID] = true
+		if _, ok := f.networks[network.ID]; !ok {
+			f.report(FsckOrphanMetadata, "network", network.ID, network.Name, "network doesn't exist anymore", func() error {
+				return metadata.RemoveNetwork(f.service, network)
+			})
+			continue
+		}
+		f.managedNetworks[network.ID] = network.Name
+
+		for _, gatewayID := range []string{network.GatewayID, network.SecondaryGatewayID} {
+			if _, ok := f.hosts[gatewayID]; gatewayID != "" && !ok {
+				f.report(FsckDanglingReference, "network", network.ID, network.Name, fmt.Sprintf("gateway '%s' doesn't exist anymore, the network has to be deleted", gatewayID), nil)
+			}
+		}
+
+		stale := map[string]string{}
+		err = network.Properties.LockForRead(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+			for id, name := range clonable.(*propsv1.NetworkHosts).ByID {
+				if _, ok := f.hosts[id]; !ok {
+					stale[id] = name
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for id, name := range stale {
+			id, name := id, name
+			f.report(FsckDanglingReference, "network", network.ID, network.Name, fmt.Sprintf("host '%s' (%s) doesn't exist anymore", name, id), func() error {
+				return updateNetworkMetadata(f.service, network.ID, func(network *resources.Network) error {
+					return network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+						networkHostsV1 := clonable.(*propsv1.NetworkHosts)
+						delete(networkHostsV1.ByID, id)
+						delete(networkHostsV1.ByName, name)
+						return nil
+					})
+				})
+			})
+		}
+	}
+
+	// Only the networks with a gateway named by SafeScale ("gw-<network name>") are adopted
+	hostNames := map[string]bool{}
+	for _, name := range f.hosts {
+		hostNames[name] = true
+	}
+	for id, name := range f.networks {
+		if known[id] {
+			continue
+		}
+		id := id
+		if !hostNames["gw-"+name] {
+			f.report(FsckUnmanagedResource, "network", id, name, fmt.Sprintf("network has no metadata and no gateway named 'gw-%s', adopt it with 'safescale network adopt' to manage it", name), nil)
+			continue
+		}
+		f.managedNetworks[id] = name
+		f.report(FsckUnmanagedResource, "network", id, name, "network has no metadata", func() error {
+			_, err := adoptNetwork(f.service, id)
+			return err
+		})
+	}
+	return nil
+}
+
+// checkHosts checks the metadata of the hosts
+func (f *fsck) checkHosts() error {
+	mh, err := metadata.NewHost(f.service)
+	if err != nil {
+		return err
+	}
+	var managed []*resources.Host
+	err = mh.Browse(func(host *resources.Host) error {
+		managed = append(managed, host)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	known := map[string]bool{}
+	f.managedHosts = map[string]string{}
+	for _, host := range managed {
+		host := host
+		known[host.ID] = true
+		if _, ok := f.hosts[host.ID]; !ok {
+			f.report(FsckOrphanMetadata, "host", host.ID, host.Name, "host doesn't exist anymore", func() error {
+				return metadata.RemoveHost(f.service, host)
+			})
+			continue
+		}
+		f.managedHosts[host.ID] = host.Name
+
+		staleVolumes := map[string]bool{}
+		err = host.Properties.LockForRead(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+			for id := range clonable.(*propsv1.HostVolumes).VolumesByID {
+				if _, ok := f.volumes[id]; !ok {
+					staleVolumes[id] = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for id := range staleVolumes {
+			id := id
+			f.report(FsckDanglingReference, "host", host.ID, host.Name, fmt.Sprintf("attached volume '%s' doesn't exist anymore", id), func() error {
+				return updateHostMetadata(f.service, host.ID, func(host *resources.Host) error {
+					return host.Properties.LockForWrite(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+						hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+						for name, volumeID := range hostVolumesV1.VolumesByName {
+							if volumeID == id {
+								delete(hostVolumesV1.VolumesByName, name)
+							}
+						}
+						if device, ok := hostVolumesV1.DevicesByID[id]; ok {
+							delete(hostVolumesV1.VolumesByDevice, device)
+						}
+						delete(hostVolumesV1.DevicesByID, id)
+						delete(hostVolumesV1.VolumesByID, id)
+						return nil
+					})
+				})
+			})
+		}
+
+		staleNetworks := map[string]string{}
+		err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+			for id, name := range clonable.(*propsv1.HostNetwork).NetworksByID {
+				if _, ok := f.networks[id]; !ok {
+					staleNetworks[id] = name
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for id, name := range staleNetworks {
+			id, name := id, name
+			f.report(FsckDanglingReference, "host", host.ID, host.Name, fmt.Sprintf("network '%s' (%s) doesn't exist anymore", name, id), func() error {
+				return updateHostMetadata(f.service, host.ID, func(host *resources.Host) error {
+					return host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+						hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+						delete(hostNetworkV1.NetworksByID, id)
+						delete(hostNetworkV1.NetworksByName, name)
+						delete(hostNetworkV1.IPv4Addresses, id)
+						delete(hostNetworkV1.IPv6Addresses, id)
+						return nil
+					})
+				})
+			})
+		}
+	}
+
+	for id, name := range f.hosts {
+		if known[id] {
+			continue
+		}
+		id := id
+		host, err := f.service.InspectHost(id)
+		if err != nil {
+			f.report(FsckUnmanagedResource, "host", id, name, fmt.Sprintf("host has no metadata, failed to inspect it: %v", err), nil)
+			continue
+		}
+		network, err := f.managedNetworkOf(host)
+		if err != nil {
+			return err
+		}
+		if network == "" {
+			f.report(FsckUnmanagedResource, "host", id, name, "host has no metadata and is not connected to a managed network, adopt it with 'safescale host adopt' to manage it", nil)
+			continue
+		}
+		// The private key of the host is not known by the provider: the host is left to an explicit adoption, which can
+		// be given its key
+		f.report(FsckUnmanagedResource, "host", id, name, fmt.Sprintf("host of managed network '%s' has no metadata, adopt it with 'safescale host adopt --key-file' to manage it", network), nil)
+	}
+	return nil
+}
+
+// managedNetworkOf returns the name of the managed network of 'host', a host without metadata, if the host follows the
+// naming of SafeScale: named as the gateway of a managed network ("gw-<network name>"), or connected to a managed
+// network. It returns an empty string otherwise.
+func (f *fsck) managedNetworkOf(host *resources.Host) (string, error) {
+	var networkIDs []string
+	err := host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		for id := range clonable.(*propsv1.HostNetwork).NetworksByID {
+			networkIDs = append(networkIDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(networkIDs)
+
+	for _, name := range f.managedNetworks {
+		if host.Name == "gw-"+name {
+			return name, nil
+		}
+	}
+	for _, id := range networkIDs {
+		if name, ok := f.managedNetworks[id]; ok {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// checkVolumes checks the metadata of the volumes
+func (f *fsck) checkVolumes() error {
+	mv, err := metadata.NewVolume(f.service)
+	if err != nil {
+		return err
+	}
+	var managed []*resources.Volume
+	err = mv.Browse(func(volume *resources.Volume) error {
+		managed = append(managed, volume)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	known := map[string]bool{}
+	for _, volume := range managed {
+		volume := volume
+		known[volume.ID] = true
+		if _, ok := f.volumes[volume.ID]; !ok {
+			f.report(FsckOrphanMetadata, "volume", volume.ID, volume.Name, "volume doesn't exist anymore", func() error {
+				return metadata.RemoveVolume(f.service, volume.ID)
+			})
+			continue
+		}
+
+		stale := map[string]string{}
+		err = volume.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+			for id, name := range clonable.(*propsv1.VolumeAttachments).Hosts {
+				if _, ok := f.hosts[id]; !ok {
+					stale[id] = name
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for id, name := range stale {
+			id := id
+			f.report(FsckDanglingReference, "volume", volume.ID, volume.Name, fmt.Sprintf("attachment to host '%s' (%s) that doesn't exist anymore", name, id), func() error {
+				return updateVolumeMetadata(f.service, volume.ID, func(volume *resources.Volume) error {
+					return volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+						delete(clonable.(*propsv1.VolumeAttachments).Hosts, id)
+						return nil
+					})
+				})
+			})
+		}
+	}
+
+	// Only the volumes attached to managed hosts are adopted
+	var attachedTo map[string]string
+	for id, name := range f.volumes {
+		if known[id] {
+			continue
+		}
+		if attachedTo == nil {
+			attachedTo = f.listAttachments()
+		}
+		id := id
+		host, ok := attachedTo[id]
+		if !ok {
+			f.report(FsckUnmanagedResource, "volume", id, name, "volume has no metadata and is not attached to a managed host, adopt it with 'safescale volume adopt' to manage it", nil)
+			continue
+		}
+		f.report(FsckUnmanagedResource, "volume", id, name, fmt.Sprintf("volume attached to managed host '%s' has no metadata", host), func() error {
+			_, err := adoptVolume(f.service, id)
+			return err
+		})
+	}
+	return nil
+}
+
+// listAttachments returns the names of the managed hosts the volumes are attached to, indexed by volume id
+func (f *fsck) listAttachments() map[string]string {
+	hostIDs := make([]string, 0, len(f.managedHosts))
+	for id := range f.managedHosts {
+		hostIDs = append(hostIDs, id)
+	}
+	sort.Strings(hostIDs)
+
+	attachedTo := map[string]string{}
+	for _, hostID := range hostIDs {
+		attachments, err := f.service.ListVolumeAttachments(hostID)
+		if err != nil {
+			// The volumes attached to this host are only reported
+			logrus.Warnf("failed to list volume attachments of host '%s': %v", f.managedHosts[hostID], err)
+			continue
+		}
+		for _, attachment := range attachments {
+			if _, ok := attachedTo[attachment.VolumeID]; !ok {
+				attachedTo[attachment.VolumeID] = f.managedHosts[hostID]
+			}
+		}
+	}
+	return attachedTo
+}
+
+// checkClusters checks that the nodes of the clusters still exist
+func (f *fsck) checkClusters() error {
+	m, err := control.NewMetadata(f.service)
+	if err != nil {
+		return err
+	}
+	var clusters []*control.Controller
+	err = m.Browse(func(controller *control.Controller) error {
+		clusters = append(clusters, controller)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	task := concurrency.RootTask()
+	for _, controller := range clusters {
+		controller := controller
+		name := controller.GetIdentity(task).Name
+
+		var stale []*clusterpropsv1.Node
+		err = controller.GetProperties(task).LockForRead(property.NodesV1).ThenUse(func(clonable data.Clonable) error {
+			nodesV1 := clonable.(*clusterpropsv1.Nodes)
+			for _, list := range [][]*clusterpropsv1.Node{nodesV1.Masters, nodesV1.PrivateNodes, nodesV1.PublicNodes} {
+				for _, node := range list {
+					if _, ok := f.hosts[node.ID]; !ok {
+						stale = append(stale, node)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, node := range stale {
+			node := node
+			f.report(FsckDanglingReference, "cluster", "", name, fmt.Sprintf("node '%s' (%s) doesn't exist anymore", node.Name, node.ID), func() error {
+				return controller.UpdateMetadata(task, func() error {
+					return controller.Properties.LockForWrite(property.NodesV1).ThenUse(func(clonable data.Clonable) error {
+						nodesV1 := clonable.(*clusterpropsv1.Nodes)
+						nodesV1.Masters = withoutNode(nodesV1.Masters, node.ID)
+						nodesV1.PrivateNodes = withoutNode(nodesV1.PrivateNodes, node.ID)
+						nodesV1.PublicNodes = withoutNode(nodesV1.PublicNodes, node.ID)
+						return nil
+					})
+				})
+			})
+		}
+	}
+	return nil
+}
+
+// withoutNode returns 'list' without the node 'id'
+func withoutNode(list []*clusterpropsv1.Node, id string) []*clusterpropsv1.Node {
+	result := make([]*clusterpropsv1.Node, 0, len(list))
+	for _, node := range list {
+		if node.ID != id {
+			result = append(result, node)
+		}
+	}
+	return result
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/tests"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// findFinding returns the finding of 'category' about the resource 'name' of 'kind'
+func findFinding(t *testing.T, findings []FsckFinding, category, kind, name string) FsckFinding {
+	for _, f := range findings {
+		if f.Category == category && f.Kind == kind && f.Name == name {
+			return f
+		}
+	}
+	require.Fail(t, fmt.Sprintf("no %s finding for %s '%s' in %v", category, kind, name, findings))
+	return FsckFinding{}
+}
+
+// assertRepaired checks that 'finding' has been repaired if asked for
+func assertRepaired(t *testing.T, finding FsckFinding, repair bool) {
+	assert.Equal(t, repair, finding.Repaired, finding.Detail)
+	assert.Empty(t, finding.Error)
+}
+
+// assertManaged checks if the metadata of a host, network or volume exists
+func assertManaged(t *testing.T, svc iaas.Service, kind, ref string, managed bool) {
+	var err error
+	switch kind {
+	case "host":
+		_, err = metadata.LoadHost(svc, ref)
+	case "network":
+		_, err = metadata.LoadNetwork(svc, ref)
+	case "volume":
+		_, err = metadata.LoadVolume(svc, ref)
+	}
+	if managed {
+		assert.Nil(t, err)
+		return
+	}
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok, fmt.Sprintf("%s '%s' is managed", kind, ref))
+}
+
+func TestTenantHandler_Fsck_orphanMetadata(t *testing.T) {
+	for _, repair := range []bool{false, true} {
+		repair := repair
+		t.Run(fmt.Sprintf("repair=%v", repair), func(t *testing.T) {
+			svc, _ := tests.NewFakeService(t, fmt.Sprintf("TestFsckOrphan%v", repair))
+			network := tests.CreateNetwork(t, svc, "net-orphan", "192.168.40.0/24")
+			gateway := tests.CreateHost(t, svc, "gw-net-orphan", network, nil)
+			host := tests.CreateHost(t, svc, "host-orphan", network, gateway)
+			volume := tests.CreateVolume(t, svc, "vol-orphan")
+			for _, adopt := range []func() error{
+				func() error { _, err := adoptNetwork(svc, network.ID); return err },
+				func() error { _, err := adoptHost(svc, gateway.ID, nil); return err },
+				func() error { _, err := adoptHost(svc, host.ID, nil); return err },
+				func() error { _, err := adoptVolume(svc, volume.ID); return err },
+			} {
+				require.Nil(t, adopt())
+			}
+			require.Nil(t, svc.DeleteHost(host.ID))
+			require.Nil(t, svc.DeleteVolume(volume.ID))
+
+			findings, err := NewTenantHandler(svc).Fsck(context.Background(), repair)
+			require.Nil(t, err)
+			assert.Len(t, findings, 3)
+			assertRepaired(t, findFinding(t, findings, FsckOrphanMetadata, "host", host.Name), repair)
+			assertRepaired(t, findFinding(t, findings, FsckOrphanMetadata, "volume", volume.Name), repair)
+			// The network still lists the host
+			assertRepaired(t, findFinding(t, findings, FsckDanglingReference, "network", network.Name), repair)
+
+			assertManaged(t, svc, "host", host.ID, !repair)
+			assertManaged(t, svc, "volume", volume.ID, !repair)
+			if repair {
+				assert.Empty(t, networkHosts(t, svc, network.ID))
+			} else {
+				assert.Contains(t, networkHosts(t, svc, network.ID), host.ID)
+			}
+		})
+	}
+}
+
+func TestTenantHandler_Fsck_unmanagedResources(t *testing.T) {
+	for _, repair := range []bool{false, true} {
+		repair := repair
+		t.Run(fmt.Sprintf("repair=%v", repair), func(t *testing.T) {
+			svc, _ := tests.NewFakeService(t, fmt.Sprintf("TestFsckUnmanaged%v", repair))
+			managedNetwork := tests.CreateNetwork(t, svc, "net-managed", "192.168.45.0/24")
+			managedHost := tests.CreateHost(t, svc, "db-managed", managedNetwork, nil)
+			_, err := adoptNetwork(svc, managedNetwork.ID)
+			require.Nil(t, err)
+			_, err = adoptHost(svc, managedHost.ID, nil)
+			require.Nil(t, err)
+
+			// Resources following the naming of SafeScale
+			network := tests.CreateNetwork(t, svc, "net-sc", "192.168.41.0/24")
+			volume := tests.CreateVolume(t, svc, "vol-sc", managedHost.ID)
+			gateway := tests.CreateHost(t, svc, "gw-net-sc", network, nil)
+			host := tests.CreateHost(t, svc, "web-sc", network, gateway)
+			// Other resources
+			external := tests.CreateNetwork(t, svc, "net-ext", "192.168.42.0/24")
+			vm := tests.CreateHost(t, svc, "vm-ext", external, nil)
+			disk := tests.CreateVolume(t, svc, "vol-ext")
+			attached := tests.CreateVolume(t, svc, "vol-web", host.ID)
+
+			findings, err := NewTenantHandler(svc).Fsck(context.Background(), repair)
+			require.Nil(t, err)
+			assert.Len(t, findings, 8)
+			assertRepaired(t, findFinding(t, findings, FsckUnmanagedResource, "network", network.Name), repair)
+			assertManaged(t, svc, "network", network.ID, repair)
+			assertRepaired(t, findFinding(t, findings, FsckUnmanagedResource, "volume", volume.Name), repair)
+			assertManaged(t, svc, "volume", volume.ID, repair)
+
+			// The other resources are left to an explicit adoption
+			for kind, name := range map[string]string{"network": external.Name, "volume": disk.Name} {
+				finding := findFinding(t, findings, FsckUnmanagedResource, kind, name)
+				assert.False(t, finding.Repaired)
+				assert.Contains(t, finding.Detail, "adopt")
+				if repair {
+					assert.NotEmpty(t, finding.Error)
+				}
+			}
+			assertManaged(t, svc, "network", external.ID, false)
+			assertManaged(t, svc, "volume", disk.ID, false)
+			assertManaged(t, svc, "volume", attached.ID, false)
+
+			// The hosts, whose private key is not known, are never adopted, even when they follow the naming of SafeScale
+			for _, h := range []*resources.Host{gateway, host, vm} {
+				finding := findFinding(t, findings, FsckUnmanagedResource, "host", h.Name)
+				assert.False(t, finding.Repaired)
+				assert.Contains(t, finding.Detail, "safescale host adopt")
+				if repair {
+					assert.NotEmpty(t, finding.Error)
+				}
+				assertManaged(t, svc, "host", h.ID, false)
+			}
+			assert.Contains(t, findFinding(t, findings, FsckUnmanagedResource, "host", host.Name).Detail, network.Name)
+
+			if repair {
+				mv, err := metadata.LoadVolume(svc, volume.ID)
+				require.Nil(t, err)
+				adopted, err := mv.Get()
+				require.Nil(t, err)
+				err = adopted.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+					assert.Equal(t, map[string]string{managedHost.ID: managedHost.Name}, clonable.(*propsv1.VolumeAttachments).Hosts)
+					return nil
+				})
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestTenantHandler_Fsck_staleHostVolumes(t *testing.T) {
+	for _, repair := range []bool{false, true} {
+		repair := repair
+		t.Run(fmt.Sprintf("repair=%v", repair), func(t *testing.T) {
+			svc, _ := tests.NewFakeService(t, fmt.Sprintf("TestFsckHostVolumes%v", repair))
+			network := tests.CreateNetwork(t, svc, "net-hv", "192.168.43.0/24")
+			host := tests.CreateHost(t, svc, "host-hv", network, nil)
+			volume := tests.CreateVolume(t, svc, "vol-hv", host.ID)
+			_, err := adoptNetwork(svc, network.ID)
+			require.Nil(t, err)
+			_, err = adoptHost(svc, host.ID, nil)
+			require.Nil(t, err)
+			_, err = adoptVolume(svc, volume.ID)
+			require.Nil(t, err)
+
+			// The volume disappears from the provider
+			attachments, err := svc.ListVolumeAttachments(host.ID)
+			require.Nil(t, err)
+			require.Len(t, attachments, 1)
+			require.Nil(t, svc.DeleteVolumeAttachment(host.ID, attachments[0].ID))
+			require.Nil(t, svc.DeleteVolume(volume.ID))
+
+			findings, err := NewTenantHandler(svc).Fsck(context.Background(), repair)
+			require.Nil(t, err)
+			assert.Len(t, findings, 2)
+			assertRepaired(t, findFinding(t, findings, FsckOrphanMetadata, "volume", volume.Name), repair)
+			finding := findFinding(t, findings, FsckDanglingReference, "host", host.Name)
+			assert.Contains(t, finding.Detail, volume.ID)
+			assertRepaired(t, finding, repair)
+
+			err = loadHost(t, svc, host.ID).Properties.LockForRead(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+				hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+				if repair {
+					assert.Empty(t, hostVolumesV1.VolumesByID)
+					assert.Empty(t, hostVolumesV1.VolumesByName)
+					assert.Empty(t, hostVolumesV1.VolumesByDevice)
+					assert.Empty(t, hostVolumesV1.DevicesByID)
+				} else {
+					assert.Contains(t, hostVolumesV1.VolumesByID, volume.ID)
+				}
+				return nil
+			})
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestTenantHandler_Fsck_clusterNodes(t *testing.T) {
+	for _, repair := range []bool{false, true} {
+		repair := repair
+		t.Run(fmt.Sprintf("repair=%v", repair), func(t *testing.T) {
+			svc, _ := tests.NewFakeService(t, fmt.Sprintf("TestFsckClusterNodes%v", repair))
+			network := tests.CreateNetwork(t, svc, "net-cl", "192.168.44.0/24")
+			master := tests.CreateHost(t, svc, "cl-master-1", network, nil)
+			_, err := adoptNetwork(svc, network.ID)
+			require.Nil(t, err)
+			_, err = adoptHost(svc, master.ID, nil)
+			require.Nil(t, err)
+
+			// Writes the metadata of a cluster with a node that doesn't exist at the provider
+			task := concurrency.RootTask()
+			controller, err := control.NewController(svc)
+			require.Nil(t, err)
+			controller.Identity.Name = "cluster-fsck"
+			err = controller.UpdateMetadata(task, func() error {
+				return controller.Properties.LockForWrite(property.NodesV1).ThenUse(func(clonable data.Clonable) error {
+					nodesV1 := clonable.(*clusterpropsv1.Nodes)
+					nodesV1.Masters = []*clusterpropsv1.Node{{ID: master.ID, Name: master.Name}}
+					nodesV1.PrivateNodes = []*clusterpropsv1.Node{{ID: "deleted-node-id", Name: "cl-node-1"}}
+					return nil
+				})
+			})
+			require.Nil(t, err)
+
+			findings, err := NewTenantHandler(svc).Fsck(context.Background(), repair)
+			require.Nil(t, err)
+			assert.Len(t, findings, 1)
+			finding := findFinding(t, findings, FsckDanglingReference, "cluster", "cluster-fsck")
+			assert.Contains(t, finding.Detail, "cl-node-1")
+			assertRepaired(t, finding, repair)
+
+			m, err := control.NewMetadata(svc)
+			require.Nil(t, err)
+			require.Nil(t, m.Read(task, "cluster-fsck"))
+			cluster, err := m.Get()
+			require.Nil(t, err)
+			err = cluster.Properties.LockForRead(property.NodesV1).ThenUse(func(clonable data.Clonable) error {
+				nodesV1 := clonable.(*clusterpropsv1.Nodes)
+				assert.Len(t, nodesV1.Masters, 1)
+				if repair {
+					assert.Empty(t, nodesV1.PrivateNodes)
+				} else {
+					assert.Len(t, nodesV1.PrivateNodes, 1)
+				}
+				return nil
+			})
+			require.Nil(t, err)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/sirupsen/logrus"
@@ -39,6 +40,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
@@ -47,11 +49,19 @@ import (
 // VolumeAPI defines API to manipulate hosts
 type VolumeAPI interface {
 	Delete(ctx context.Context, ref string) error
-	List(ctx context.Context, all bool) ([]resources.Volume, error)
+	List(ctx context.Context, all bool, selector string) ([]resources.Volume, error)
 	Inspect(ctx context.Context, ref string) (*resources.Volume, map[string]*propsv1.HostLocalMount, error)
-	Create(ctx context.Context, name string, size int, speed volumespeed.Enum) (*resources.Volume, error)
+	Create(ctx context.Context, name string, size int, speed volumespeed.Enum, tags map[string]string) (*resources.Volume, error)
 	Attach(ctx context.Context, volume string, host string, path string, format string, doNotFormat bool) error
 	Detach(ctx context.Context, volume string, host string) error
+	Resize(ctx context.Context, ref string, size int) (*resources.Volume, error)
+	SetTags(ctx context.Context, ref string, set map[string]string, unset []string) (map[string]string, error)
+	Adopt(ctx context.Context, id string) (*resources.Volume, error)
+
+	CreateSnapshot(ctx context.Context, volume string, name string, description string) (*resources.VolumeSnapshot, error)
+	ListSnapshots(ctx context.Context, volume string, all bool) ([]resources.VolumeSnapshot, error)
+	DeleteSnapshot(ctx context.Context, ref string) error
+	RestoreSnapshot(ctx context.Context, ref string, name string, size int, speed volumespeed.Enum) (*resources.Volume, error)
 }
 
 // VolumeHandler volume service
@@ -67,17 +77,24 @@ func NewVolumeHandler(svc iaas.Service) VolumeAPI {
 }
 
 // List returns the network list
-func (handler *VolumeHandler) List(ctx context.Context, all bool) (volumes []resources.Volume, err error) {
+func (handler *VolumeHandler) List(ctx context.Context, all bool, selector string) (volumes []resources.Volume, err error) {
 	if handler == nil {
 		return nil, scerr.InvalidInstanceError()
 	}
 	//FIXME: validate parameters
 
-	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v, '%s')", all, selector), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
+	tagSelector, err := resources.ParseTagSelector(selector)
+	if err != nil {
+		return nil, err
+	}
 	if all {
+		if !tagSelector.Empty() {
+			return nil, scerr.InvalidParameterError("selector", "cannot be used when listing all the volumes of the tenant, tags are only known by SafeScale")
+		}
 		volumes, err := handler.service.ListVolumes()
 		return volumes, err
 	}
@@ -87,7 +104,13 @@ func (handler *VolumeHandler) List(ctx context.Context, all bool) (volumes []res
 		return nil, err
 	}
 	err = mv.Browse(func(volume *resources.Volume) error {
-		volumes = append(volumes, *volume)
+		tags, err := readTags(volume.Properties, volumeproperty.TagsV1)
+		if err != nil {
+			return err
+		}
+		if tagSelector.Matches(tags) {
+			volumes = append(volumes, *volume)
+		}
 		return nil
 	})
 	if err != nil {
@@ -109,7 +132,8 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
-	mv, err := metadata.LoadVolume(handler.service, ref)
+	// Locks the metadata of the volume until it is deleted, so that it cannot be attached meanwhile
+	mv, volume, release, err := lockVolumeMetadata(handler.service, ref)
 	if err != nil {
 		switch err.(type) {
 		case scerr.ErrNotFound:
@@ -119,10 +143,7 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 			return err
 		}
 	}
-	volume, err := mv.Get()
-	if err != nil {
-		return err
-	}
+	defer release()
 
 	err = volume.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
 		volumeAttachmentsV1 := clonable.(*propsv1.VolumeAttachments)
@@ -151,6 +172,10 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 			return err
 		}
 	}
+	volumeTags, err := readTags(volume.Properties, volumeproperty.TagsV1)
+	if err != nil {
+		return err
+	}
 	err = mv.Delete()
 	if err != nil {
 		return err
@@ -159,7 +184,7 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 	select {
 	case <-ctx.Done():
 		logrus.Warnf("Volume deletion cancelled by user")
-		volumeBis, err := handler.Create(context.Background(), volume.Name, volume.Size, volume.Speed)
+		volumeBis, err := handler.Create(context.Background(), volume.Name, volume.Size, volume.Speed, volumeTags)
 		if err != nil {
 			return fmt.Errorf("failed to stop volume deletion")
 		}
@@ -244,11 +269,14 @@ func (handler *VolumeHandler) Inspect(
 }
 
 // Create a volume
-func (handler *VolumeHandler) Create(ctx context.Context, name string, size int, speed volumespeed.Enum) (volume *resources.Volume, err error) {
+func (handler *VolumeHandler) Create(ctx context.Context, name string, size int, speed volumespeed.Enum, tags map[string]string) (volume *resources.Volume, err error) {
 	if handler == nil {
 		return nil, scerr.InvalidInstanceError()
 	}
 	// FIXME: validate parameters
+	if err = resources.ValidateTags(tags); err != nil {
+		return nil, err
+	}
 
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d, %s)", name, size, speed.String()), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
@@ -267,6 +295,7 @@ func (handler *VolumeHandler) Create(ctx context.Context, name string, size int,
 		Name:  name,
 		Size:  size,
 		Speed: speed,
+		Tags:  tags,
 	})
 	if err != nil {
 		switch err.(type) {
@@ -296,6 +325,14 @@ func (handler *VolumeHandler) Create(ctx context.Context, name string, size int,
 		}
 	}()
 
+	if volume.Properties == nil {
+		volume.Properties = serialize.NewJSONProperties("resources.volume")
+	}
+	err = writeTags(volume.Properties, volumeproperty.TagsV1, tags)
+	if err != nil {
+		return nil, err
+	}
+
 	md, err := metadata.SaveVolume(handler.service, volume)
 	if err != nil {
 		logrus.Debugf("Error creating volume: saving volume metadata: %+v", err)
@@ -350,6 +387,19 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 		return err
 	}
 
+	// Locks the metadata of the volume, then of the host, until the attachment is recorded, so that the changes
+	// made concurrently by someone else are not overwritten
+	mv, volume, releaseVolume, err := lockVolumeMetadata(handler.service, volume.ID)
+	if err != nil {
+		return err
+	}
+	defer releaseVolume()
+	mh, host, releaseHost, err := lockHostMetadata(handler.service, host.ID)
+	if err != nil {
+		return err
+	}
+	defer releaseHost()
+
 	var (
 		deviceName string
 		volumeUUID string
@@ -358,6 +408,9 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 		server     *nfs.Server
 	)
 
+	// Hosts simulated by the provider have no system to query or to mount the volume on
+	simulated := handler.service.GetCapabilities().SimulatedHosts
+
 	err = volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
 		volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
 
@@ -408,9 +461,13 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 				// Note: most providers are not able to tell the real device name the volume
 				//       will have on the host, so we have to use a way that can work everywhere
 				// Get list of disks before attachment
-				oldDiskSet, err := handler.listAttachedDevices(ctx, host)
-				if err != nil {
-					return err
+				var oldDiskSet mapset.Set
+				if !simulated {
+					disks, err := handler.listAttachedDevices(ctx, host)
+					if err != nil {
+						return err
+					}
+					oldDiskSet = disks
 				}
 				vaID, err := handler.service.CreateVolumeAttachment(resources.VolumeAttachmentRequest{
 					Name:     fmt.Sprintf("%s-%s", volume.Name, host.Name),
@@ -446,45 +503,64 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 				// Updates volume properties
 				volumeAttachedV1.Hosts[host.ID] = host.Name
 
-				// Retries to acknowledge the volume is really attached to host
-				var newDisk mapset.Set
-				retryErr := retry.WhileUnsuccessfulDelay1Second(
-					func() error {
-						// Get new of disk after attachment
-						newDiskSet, err := handler.listAttachedDevices(ctx, host)
-						if err != nil {
-							return err
-						}
-						// Isolate the new device
-						newDisk = newDiskSet.Difference(oldDiskSet)
-						if newDisk.Cardinality() == 0 {
-							return fmt.Errorf("disk not yet attached, retrying")
-						}
-						return nil
-					},
-					temporal.GetExecutionTimeout(),
-				)
-				if retryErr != nil {
-					return fmt.Errorf("failed to confirm the disk attachment after %s", temporal.GetExecutionTimeout())
-				}
+				if simulated {
+					va, err := handler.service.GetVolumeAttachment(host.ID, vaID)
+					if err != nil {
+						return err
+					}
+					volumeUUID = va.Device
+				} else {
+					// Retries to acknowledge the volume is really attached to host
+					var newDisk mapset.Set
+					retryErr := retry.WhileUnsuccessfulDelay1Second(
+						func() error {
+							// Get new of disk after attachment
+							newDiskSet, err := handler.listAttachedDevices(ctx, host)
+							if err != nil {
+								return err
+							}
+							// Isolate the new device
+							newDisk = newDiskSet.Difference(oldDiskSet)
+							if newDisk.Cardinality() == 0 {
+								return fmt.Errorf("disk not yet attached, retrying")
+							}
+							return nil
+						},
+						temporal.GetExecutionTimeout(),
+					)
+					if retryErr != nil {
+						return fmt.Errorf("failed to confirm the disk attachment after %s", temporal.GetExecutionTimeout())
+					}
 
-				// Recovers real device name from the system
-				deviceName = "/dev/" + newDisk.ToSlice()[0].(string)
+					// Recovers real device name from the system
+					deviceName = "/dev/" + newDisk.ToSlice()[0].(string)
 
-				// Create mount point
-				sshHandler := NewSSHHandler(handler.service)
-				sshConfig, err := sshHandler.GetConfig(ctx, host.ID)
-				if err != nil {
-					return err
-				}
+					// Create mount point
+					sshHandler := NewSSHHandler(handler.service)
+					sshConfig, err := sshHandler.GetConfig(ctx, host.ID)
+					if err != nil {
+						return err
+					}
 
-				server, err = nfs.NewServer(sshConfig)
-				if err != nil {
-					return err
-				}
-				volumeUUID, err = server.MountBlockDevice(deviceName, mountPoint, format, doNotFormat)
-				if err != nil {
-					return err
+					server, err = nfs.NewServer(sshConfig)
+					if err != nil {
+						return err
+					}
+					volumeUUID, err = server.MountBlockDevice(deviceName, mountPoint, format, doNotFormat)
+					if err != nil {
+						return err
+					}
+
+					// Starting from here, unmount block device if exiting with error
+					defer func() {
+						if err != nil {
+							derr := server.UnmountBlockDevice(volumeUUID)
+							if derr != nil {
+								logrus.Errorf("failed to unmount volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
+								err = scerr.AddConsequence(err, derr)
+							}
+						}
+					}()
 				}
 
 				// Saves volume information in property
@@ -496,17 +572,6 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 				hostVolumesV1.VolumesByDevice[volumeUUID] = volume.ID
 				hostVolumesV1.DevicesByID[volume.ID] = volumeUUID
 
-				// Starting from here, unmount block device if exiting with error
-				defer func() {
-					if err != nil {
-						derr := server.UnmountBlockDevice(volumeUUID)
-						if derr != nil {
-							logrus.Errorf("failed to unmount volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
-							err = scerr.AddConsequence(err, derr)
-						}
-					}
-				}()
-
 				// Updates host properties
 				hostMountsV1.LocalMountsByPath[mountPoint] = &propsv1.HostLocalMount{
 					Device:     volumeUUID,
@@ -525,12 +590,14 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 
 	defer func() {
 		if err != nil {
-			derr := server.UnmountBlockDevice(volumeUUID)
-			if derr != nil {
-				logrus.Errorf("failed to unmount volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
-				err = scerr.AddConsequence(err, derr)
+			if server != nil {
+				derr := server.UnmountBlockDevice(volumeUUID)
+				if derr != nil {
+					logrus.Errorf("failed to unmount volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
+					err = scerr.AddConsequence(err, derr)
+				}
 			}
-			derr = handler.service.DeleteVolumeAttachment(host.ID, vaID)
+			derr := handler.service.DeleteVolumeAttachment(host.ID, vaID)
 			if derr != nil {
 				switch derr.(type) {
 				case scerr.ErrNotFound:
@@ -545,7 +612,7 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 		}
 	}()
 
-	_, err = metadata.SaveVolume(handler.service, volume)
+	err = mv.Write()
 	if err != nil {
 		return err
 	}
@@ -561,7 +628,7 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 				logrus.Warnf("failed to set volume %s metadatas", volumeName)
 				err = scerr.AddConsequence(err, err2)
 			}
-			_, err2 = metadata.SaveVolume(handler.service, volume)
+			err2 = mv.Write()
 			if err2 != nil {
 				logrus.Warnf("failed to save volume %s metadatas", volumeName)
 				err = scerr.AddConsequence(err, err2)
@@ -569,7 +636,7 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 		}
 	}()
 
-	mh, err := metadata.SaveHost(handler.service, host)
+	err = mh.Write()
 	if err != nil {
 		return err
 	}
@@ -710,6 +777,19 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 		return err
 	}
 
+	// Locks the metadata of the volume, then of the host, until the detachment is recorded, so that the changes
+	// made concurrently by someone else are not overwritten
+	mv, volume, releaseVolume, err := lockVolumeMetadata(handler.service, volume.ID)
+	if err != nil {
+		return err
+	}
+	defer releaseVolume()
+	mh, host, releaseHost, err := lockHostMetadata(handler.service, host.ID)
+	if err != nil {
+		return err
+	}
+	defer releaseHost()
+
 	// Obtain volume attachment ID
 	err = host.Properties.LockForWrite(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
 		hostVolumesV1 := clonable.(*propsv1.HostVolumes)
@@ -726,8 +806,62 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 			device := attachment.Device
 			mountPath = hostMountsV1.LocalMountsByDevice[device]
 			mount := hostMountsV1.LocalMountsByPath[mountPath]
+
+			detach := func() error {
+				// Unmount the Block Device ...
+				if !handler.service.GetCapabilities().SimulatedHosts {
+					sshHandler := NewSSHHandler(handler.service)
+					sshConfig, err := sshHandler.GetConfig(ctx, host.ID)
+					if err != nil {
+						return err
+					}
+					nfsServer, err := nfs.NewServer(sshConfig)
+					if err != nil {
+						return err
+					}
+					err = nfsServer.UnmountBlockDevice(attachment.Device)
+					if err != nil {
+						// FIXME Think about this
+						logrus.Error(err)
+						//return err
+					}
+				}
+
+				// ... then detach volume
+				err = handler.service.DeleteVolumeAttachment(host.ID, attachment.AttachID)
+				if err != nil {
+					switch err.(type) {
+					case scerr.ErrNotFound, scerr.ErrInvalidRequest, scerr.ErrTimeout:
+						return err
+					default:
+						return err
+					}
+				}
+
+				// Updates host property propsv1.VolumesV1
+				delete(hostVolumesV1.VolumesByID, volume.ID)
+				delete(hostVolumesV1.VolumesByName, volume.Name)
+				delete(hostVolumesV1.VolumesByDevice, attachment.Device)
+				delete(hostVolumesV1.DevicesByID, volume.ID)
+
+				// Updates host property propsv1.MountsV1
+				if mount != nil {
+					delete(hostMountsV1.LocalMountsByDevice, mount.Device)
+					delete(hostMountsV1.LocalMountsByPath, mount.Path)
+				}
+
+				// Updates volume property propsv1.VolumeAttachments
+				return volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+					volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
+					delete(volumeAttachedV1.Hosts, host.ID)
+					return nil
+				})
+			}
+
 			if mount == nil {
-				return fmt.Errorf("metadata inconsistency: no mount corresponding to volume attachment")
+				// The attachments found at the provider when the volume or the host has been adopted have no known
+				// mount point: there is nothing mounted by SafeScale inside the volume to check
+				return detach()
 			}
 
 			// Check if volume has other mount(s) inside it
@@ -757,51 +891,7 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 							volume.Name, host.Name, mount.Path, host.Name, v.Path)
 					}
 				}
-
-				// Unmount the Block Device ...
-				sshHandler := NewSSHHandler(handler.service)
-				sshConfig, err := sshHandler.GetConfig(ctx, host.ID)
-				if err != nil {
-					return err
-				}
-				nfsServer, err := nfs.NewServer(sshConfig)
-				if err != nil {
-					return err
-				}
-				err = nfsServer.UnmountBlockDevice(attachment.Device)
-				if err != nil {
-					// FIXME Think about this
-					logrus.Error(err)
-					//return err
-				}
-
-				// ... then detach volume
-				err = handler.service.DeleteVolumeAttachment(host.ID, attachment.AttachID)
-				if err != nil {
-					switch err.(type) {
-					case scerr.ErrNotFound, scerr.ErrInvalidRequest, scerr.ErrTimeout:
-						return err
-					default:
-						return err
-					}
-				}
-
-				// Updates host property propsv1.VolumesV1
-				delete(hostVolumesV1.VolumesByID, volume.ID)
-				delete(hostVolumesV1.VolumesByName, volume.Name)
-				delete(hostVolumesV1.VolumesByDevice, attachment.Device)
-				delete(hostVolumesV1.DevicesByID, volume.ID)
-
-				// Updates host property propsv1.MountsV1
-				delete(hostMountsV1.LocalMountsByDevice, mount.Device)
-				delete(hostMountsV1.LocalMountsByPath, mount.Path)
-
-				// Updates volume property propsv1.VolumeAttachments
-				return volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
-					volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
-					delete(volumeAttachedV1.Hosts, host.ID)
-					return nil
-				})
+				return detach()
 			})
 		})
 	})
@@ -810,14 +900,16 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 	}
 
 	// Updates metadata
-	_, err = metadata.SaveHost(handler.service, host)
+	err = mh.Write()
 	if err != nil {
 		return err
 	}
-	_, err = metadata.SaveVolume(handler.service, volume)
+	err = mv.Write()
 	if err != nil {
 		return err
 	}
+	releaseHost()
+	releaseVolume()
 
 	select {
 	case <-ctx.Done():
@@ -833,3 +925,325 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 
 	return nil
 }
+
+// Resize extends the volume identified by ref to size GB, then grows the filesystem of the volume if it is attached
+// to a host. When the provider cannot resize attached volumes, the volume is detached and attached back around the
+// operation.
+func (handler *VolumeHandler) Resize(ctx context.Context, ref string, size int) (volume *resources.Volume, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if size <= 0 {
+		return nil, scerr.InvalidParameterError("size", "must be greater than 0")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", ref, size), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	volume, mounts, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if size <= volume.Size {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("the new size of volume '%s' must be greater than its current size (%d GB)", volume.Name, volume.Size))
+	}
+
+	// A volume is attached to one host at most
+	var (
+		hostName string
+		mount    *propsv1.HostLocalMount
+	)
+	for k, v := range mounts {
+		hostName, mount = k, v
+	}
+
+	var resized *resources.Volume
+	if hostName != "" && handler.service.GetCapabilities().OfflineVolumeResize {
+		resized, err = handler.resizeDetached(ctx, volume, hostName, mount.Path, size)
+	} else {
+		resized, err = handler.service.ResizeVolume(volume.ID, size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach and Detach have updated the metadata, records the new size in the current metadata
+	err = updateVolumeMetadata(handler.service, volume.ID, func(current *resources.Volume) error {
+		current.Size = resized.Size
+		volume = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if hostName != "" && !handler.service.GetCapabilities().SimulatedHosts {
+		sshHandler := NewSSHHandler(handler.service)
+		sshConfig, err := sshHandler.GetConfig(ctx, hostName)
+		if err != nil {
+			return nil, err
+		}
+		server, err := nfs.NewServer(sshConfig)
+		if err != nil {
+			return nil, err
+		}
+		err = server.GrowBlockDevice(mount.Device)
+		if err != nil {
+			return nil, scerr.Wrap(err, fmt.Sprintf("volume '%s' has been resized, but its filesystem has not been grown on host '%s'", volume.Name, hostName))
+		}
+	}
+
+	logrus.Infof("Volume '%s' successfully resized to %d GB", volume.Name, volume.Size)
+	return volume, nil
+}
+
+// resizeDetached resizes a volume attached to a host by detaching it first, and attaching it back on the same path
+// whatever the outcome of the resize
+func (handler *VolumeHandler) resizeDetached(ctx context.Context, volume *resources.Volume, hostName, path string, size int) (resized *resources.Volume, err error) {
+	err = handler.Detach(ctx, volume.ID, hostName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		// The filesystem type is read from the device, the volume not being formatted again
+		derr := handler.Attach(context.Background(), volume.ID, hostName, path, "auto", true)
+		if derr != nil {
+			logrus.Errorf("failed to attach volume '%s' back to host '%s': %v", volume.Name, hostName, derr)
+			err = scerr.AddConsequence(err, derr)
+		}
+	}()
+
+	// The provider may take some time to acknowledge the detachment
+	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			var innerErr error
+			resized, innerErr = handler.service.ResizeVolume(volume.ID, size)
+			if innerErr != nil {
+				if _, ok := innerErr.(scerr.ErrNotAvailable); !ok {
+					return retry.AbortedError("", innerErr)
+				}
+			}
+			return innerErr
+		},
+		temporal.GetContextTimeout(),
+	)
+	if retryErr != nil {
+		switch err := retryErr.(type) {
+		case retry.ErrAborted:
+			return nil, err.Cause()
+		default:
+			return nil, err
+		}
+	}
+	return resized, nil
+}
+
+// SetTags sets the tags in set and removes the tags in unset on the volume identified by ref, then returns the
+// resulting tags. Tags are propagated on provider side when supported
+func (handler *VolumeHandler) SetTags(ctx context.Context, ref string, set map[string]string, unset []string) (tags map[string]string, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if err = resources.ValidateTags(set); err != nil {
+		return nil, err
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mv, volume, release, err := lockVolumeMetadata(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("volume", ref)
+		}
+		return nil, err
+	}
+	defer release()
+
+	current, err := readTags(volume.Properties, volumeproperty.TagsV1)
+	if err != nil {
+		return nil, err
+	}
+	err = propagateTags("volume", volume.Name, handler.service.UpdateVolumeTags(volume.ID, set, unset))
+	if err != nil {
+		return nil, err
+	}
+
+	tags = resources.MergeTags(current, set, unset)
+	if volume.Properties == nil {
+		volume.Properties = serialize.NewJSONProperties("resources.volume")
+	}
+	err = writeTags(volume.Properties, volumeproperty.TagsV1, tags)
+	if err != nil {
+		return nil, err
+	}
+	err = mv.Write()
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Adopt creates the metadata of the volume 'id' of the provider, created outside of SafeScale, to manage it
+// Its attachments to managed hosts are found at the provider; their mount points are not known by SafeScale
+func (handler *VolumeHandler) Adopt(ctx context.Context, id string) (volume *resources.Volume, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return adoptVolume(handler.service, id)
+}
+
+// adoptVolume creates the metadata of the volume 'id' of the provider, created outside of SafeScale, and records its
+// attachments to the managed hosts
+func adoptVolume(svc iaas.Service, id string) (*resources.Volume, error) {
+	volume, err := svc.GetVolume(id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = metadata.LoadVolume(svc, volume.Name)
+	if err == nil {
+		return nil, scerr.DuplicateError(fmt.Sprintf("a volume named '%s' is already managed", volume.Name))
+	}
+	if _, ok := err.(scerr.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	if volume.Properties == nil {
+		volume.Properties = serialize.NewJSONProperties("resources.volume")
+	}
+	err = volume.Properties.LockForWrite(volumeproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.VolumeDescription).Purpose = "adopted by " + currentCreator()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Looks at the provider for the attachments of the volume to the managed hosts
+	mh, err := metadata.NewHost(svc)
+	if err != nil {
+		return nil, err
+	}
+	attachments := map[*resources.Host]resources.VolumeAttachment{}
+	err = mh.Browse(func(host *resources.Host) error {
+		list, err := svc.ListVolumeAttachments(host.ID)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); ok {
+				// Host doesn't exist anymore at the provider
+				return nil
+			}
+			return err
+		}
+		for _, attachment := range list {
+			if attachment.VolumeID == volume.ID {
+				attachments[host] = attachment
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+		volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
+		if volumeAttachedV1.Hosts == nil {
+			volumeAttachedV1.Hosts = map[string]string{}
+		}
+		for host := range attachments {
+			volumeAttachedV1.Hosts[host.ID] = host.Name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = metadata.SaveVolume(svc, volume)
+	if err != nil {
+		return nil, err
+	}
+
+	for host, attachment := range attachments {
+		attachment := attachment
+		err = updateHostMetadata(svc, host.ID, func(host *resources.Host) error {
+			return addHostVolume(host, volume, attachment)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return volume, nil
+}
+
+// addHostVolume records in the properties of 'host' the attachment of 'volume', found at the provider
+// Its mount point is not known: the volume is not added to the mounts of the host
+func addHostVolume(host *resources.Host, volume *resources.Volume, attachment resources.VolumeAttachment) error {
+	return host.Properties.LockForWrite(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+		hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+		hostVolumesV1.VolumesByID[volume.ID] = &propsv1.HostVolume{
+			AttachID: attachment.ID,
+			Device:   attachment.Device,
+		}
+		hostVolumesV1.VolumesByName[volume.Name] = volume.ID
+		hostVolumesV1.VolumesByDevice[attachment.Device] = volume.ID
+		hostVolumesV1.DevicesByID[volume.ID] = attachment.Device
+		return nil
+	})
+}
+
+// lockVolumeMetadata takes the lock of the metadata of the volume 'ref', then reads them again since they may have been
+// changed before being locked; the lock is kept until the returned function is called
+func lockVolumeMetadata(svc iaas.Service, ref string) (*metadata.Volume, *resources.Volume, func(), error) {
+	mv, err := metadata.LoadVolume(svc, ref)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = mv.Acquire()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var once sync.Once
+	release := func() { once.Do(mv.Release) }
+	err = mv.Reload()
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	volume, err := mv.Get()
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+	return mv, volume, release, nil
+}
+
+// updateVolumeMetadata applies 'updatefn' to the volume 'ref' while its metadata is locked, then saves it
+func updateVolumeMetadata(svc iaas.Service, ref string, updatefn func(*resources.Volume) error) error {
+	mv, volume, release, err := lockVolumeMetadata(svc, ref)
+	if err != nil {
+		return err
+	}
+	defer release()
+	err = updatefn(volume)
+	if err != nil {
+		return err
+	}
+	return mv.Write()
+}
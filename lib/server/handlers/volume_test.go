@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/tests"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+func TestAdoptVolume_attachments(t *testing.T) {
+	svc, _ := tests.NewFakeService(t, "TestAdoptVolumeAttachments")
+	network := tests.CreateNetwork(t, svc, "net-attach", "192.168.30.0/24")
+	managed := tests.CreateHost(t, svc, "host-managed", network, nil)
+	unmanaged := tests.CreateHost(t, svc, "host-unmanaged", network, nil)
+	_, err := adoptNetwork(svc, network.ID)
+	require.Nil(t, err)
+	_, err = adoptHost(svc, managed.ID, nil)
+	require.Nil(t, err)
+	volume := tests.CreateVolume(t, svc, "vol-attach", managed.ID)
+	other := tests.CreateVolume(t, svc, "vol-other", unmanaged.ID)
+
+	adopted, err := adoptVolume(svc, volume.ID)
+	require.Nil(t, err)
+	err = adopted.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+		assert.Equal(t, map[string]string{managed.ID: managed.Name}, clonable.(*propsv1.VolumeAttachments).Hosts)
+		return nil
+	})
+	require.Nil(t, err)
+	err = loadHost(t, svc, managed.ID).Properties.LockForRead(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+		hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+		require.Contains(t, hostVolumesV1.VolumesByID, volume.ID)
+		assert.NotEmpty(t, hostVolumesV1.VolumesByID[volume.ID].AttachID)
+		assert.Equal(t, volume.ID, hostVolumesV1.VolumesByName[volume.Name])
+		assert.Equal(t, hostVolumesV1.DevicesByID[volume.ID], hostVolumesV1.VolumesByID[volume.ID].Device)
+		return nil
+	})
+	require.Nil(t, err)
+
+	// Attachments to unmanaged hosts are ignored
+	adopted, err = adoptVolume(svc, other.ID)
+	require.Nil(t, err)
+	err = adopted.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+		assert.Empty(t, clonable.(*propsv1.VolumeAttachments).Hosts)
+		return nil
+	})
+	require.Nil(t, err)
+
+	_, err = adoptVolume(svc, volume.ID)
+	_, ok := err.(scerr.ErrDuplicate)
+	assert.True(t, ok)
+}
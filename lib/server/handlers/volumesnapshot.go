@@ -0,0 +1,310 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// CreateSnapshot creates a snapshot named name of the volume identified by volumeRef.
+// The snapshot may be taken while the volume is attached and mounted; its content is then the one the volume would
+// have after a power loss of the host
+func (handler *VolumeHandler) CreateSnapshot(ctx context.Context, volumeRef string, name string, description string) (snap *resources.VolumeSnapshot, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if volumeRef == "" {
+		return nil, scerr.InvalidParameterError("volumeRef", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeRef, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	_, err = metadata.LoadVolumeSnapshot(handler.service, name)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return nil, err
+		}
+	} else {
+		return nil, resources.ResourceDuplicateError("volume snapshot", name)
+	}
+
+	mv, err := metadata.LoadVolume(handler.service, volumeRef)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("volume", volumeRef)
+		}
+		return nil, err
+	}
+	volume, err := mv.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err = handler.service.CreateVolumeSnapshot(resources.VolumeSnapshotRequest{
+		Name:        name,
+		Description: description,
+		VolumeID:    volume.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			derr := handler.service.DeleteVolumeSnapshot(snap.ID)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to delete volume snapshot '%s': %v", name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	// Providers don't always know the name of the volume; the metadata of SafeScale do
+	snap.VolumeName = volume.Name
+	_, err = metadata.SaveVolumeSnapshot(handler.service, snap)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Volume snapshot creation cancelled by user")
+		derr := metadata.RemoveVolumeSnapshot(handler.service, snap.ID)
+		if derr != nil {
+			logrus.Warnf("failed to delete metadata of volume snapshot '%s'", name)
+		}
+		err = fmt.Errorf("volume snapshot creation cancelled by user")
+		return nil, err
+	default:
+	}
+
+	logrus.Infof("Snapshot '%s' of volume '%s' created", name, volume.Name)
+	return snap, nil
+}
+
+// ListSnapshots returns the snapshots created by SafeScale (or all the snapshots of the tenant if all is true),
+// restricted to the ones of the volume identified by volumeRef if not empty
+func (handler *VolumeHandler) ListSnapshots(ctx context.Context, volumeRef string, all bool) (list []resources.VolumeSnapshot, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %v)", volumeRef, all), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	volumeID := ""
+	if volumeRef != "" {
+		mv, err := metadata.LoadVolume(handler.service, volumeRef)
+		if err != nil {
+			switch err.(type) {
+			case scerr.ErrNotFound:
+				// The volume may have been deleted, its snapshots remaining; volumeRef is then taken as an ID
+				volumeID = volumeRef
+			default:
+				return nil, err
+			}
+		} else {
+			volume, err := mv.Get()
+			if err != nil {
+				return nil, err
+			}
+			volumeID = volume.ID
+		}
+	}
+
+	current, err := handler.service.ListVolumeSnapshots(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return current, nil
+	}
+
+	// The state of a snapshot changes on provider side (creation may take a while), so it's taken from there
+	states := map[string]volumestate.Enum{}
+	for _, snap := range current {
+		states[snap.ID] = snap.State
+	}
+	mvs, err := metadata.NewVolumeSnapshot(handler.service)
+	if err != nil {
+		return nil, err
+	}
+	err = mvs.Browse(func(snap *resources.VolumeSnapshot) error {
+		if volumeID != "" && snap.VolumeID != volumeID {
+			return nil
+		}
+		if state, ok := states[snap.ID]; ok {
+			snap.State = state
+		} else {
+			snap.State = volumestate.OTHER
+		}
+		list = append(list, *snap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// loadSnapshot returns the metadata of the volume snapshot identified by ref
+func (handler *VolumeHandler) loadSnapshot(ref string) (*metadata.VolumeSnapshot, *resources.VolumeSnapshot, error) {
+	mvs, err := metadata.LoadVolumeSnapshot(handler.service, ref)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, nil, resources.ResourceNotFoundError("volume snapshot", ref)
+		}
+		return nil, nil, err
+	}
+	snap, err := mvs.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return mvs, snap, nil
+}
+
+// DeleteSnapshot deletes the volume snapshot identified by ref
+func (handler *VolumeHandler) DeleteSnapshot(ctx context.Context, ref string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mvs, snap, err := handler.loadSnapshot(ref)
+	if err != nil {
+		return err
+	}
+	err = handler.service.DeleteVolumeSnapshot(snap.ID)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return err
+		}
+		logrus.Warnf("volume snapshot '%s' not found on provider side, cleaning up metadata", snap.Name)
+	}
+	err = mvs.Delete()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Volume snapshot '%s' deleted", snap.Name)
+	return nil
+}
+
+// RestoreSnapshot creates the volume name from the content of the volume snapshot identified by ref.
+// If size is 0, the volume gets the size of the snapshot.
+func (handler *VolumeHandler) RestoreSnapshot(ctx context.Context, ref string, name string, size int, speed volumespeed.Enum) (volume *resources.Volume, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+	if size < 0 {
+		return nil, scerr.InvalidParameterError("size", "cannot be negative")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', %d, %s)", ref, name, size, speed.String()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	_, err = metadata.LoadVolume(handler.service, name)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return nil, err
+		}
+	} else {
+		return nil, scerr.DuplicateError(fmt.Sprintf("volume '%s' already exists", name))
+	}
+
+	_, snap, err := handler.loadSnapshot(ref)
+	if err != nil {
+		return nil, err
+	}
+	current, err := handler.service.GetVolumeSnapshot(snap.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.State != volumestate.AVAILABLE {
+		return nil, scerr.NotAvailableError(fmt.Sprintf("volume snapshot '%s' is not available yet (state %s)", snap.Name, current.State.String()))
+	}
+
+	volume, err = handler.service.CreateVolumeFromSnapshot(snap.ID, resources.VolumeRequest{
+		Name:  name,
+		Size:  size,
+		Speed: speed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// starting from here delete volume if function ends with failure
+	newVolume := volume
+	defer func() {
+		if err != nil {
+			derr := handler.service.DeleteVolume(newVolume.ID)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to delete volume '%s': %v", newVolume.Name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	md, err := metadata.SaveVolume(handler.service, volume)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Volume restoration cancelled by user")
+		derr := md.Delete()
+		if derr != nil {
+			logrus.Warnf("failed to delete metadata of volume '%s'", newVolume.Name)
+		}
+		err = fmt.Errorf("volume restoration cancelled by user")
+		return nil, err
+	default:
+	}
+
+	logrus.Infof("Volume '%s' restored from snapshot '%s'", name, snap.Name)
+	return volume, nil
+}
@@ -22,6 +22,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -31,6 +32,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/crypt"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 )
@@ -76,7 +78,23 @@ func UseService(tenantName string) (newService Service, err error) {
 	if err != nil {
 		return nil, err
 	}
+	return useService(tenantName, tenants)
+}
+
+// UseServiceFromFile returns the service of the tenant 'tenantName' declared in the tenants file 'path', instead of
+// the tenants file found in the usual places
+func UseServiceFromFile(tenantName, path string) (newService Service, err error) {
+	defer scerr.OnPanic(&err)
+
+	tenants, err := getTenantsFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return useService(tenantName, tenants)
+}
 
+// useService returns the service of the tenant 'tenantName' found in 'tenants'
+func useService(tenantName string, tenants []interface{}) (newService Service, err error) {
 	var (
 		tenantInCfg bool
 		found       bool
@@ -145,11 +163,44 @@ func UseService(tenantName string) (newService Service, err error) {
 		_, tenantObjectStorageFound := tenant["objectstorage"]
 		_, tenantMetadataFound := tenant["metadata"]
 
-		// Initializes Provider
-		providerInstance, err := svc.Build( /*tenantClient*/ tenant)
+		cassetteMode, cassettePath, err := getCassetteConfig(tenant)
+		if err != nil {
+			return nil, err
+		}
+
+		// Initializes Provider (or the cassette replaying it)
+		var providerInstance api.Provider
+		if cassetteMode == cassetteReplay {
+			providerInstance, err = api.NewReplayProvider(cassettePath)
+			if err != nil {
+				return nil, fmt.Errorf("error replaying cassette '%s' for tenant '%s': %s", cassettePath, tenantName, err.Error())
+			}
+			logrus.Infof("tenant '%s' replays the calls to provider '%s' recorded in '%s'", tenantName, provider, cassettePath)
+		} else {
+			providerInstance, err = svc.Build( /*tenantClient*/ tenant)
+			if err != nil {
+				return nil, fmt.Errorf("error creating tenant '%s' on provider '%s': %s", tenantName, provider, err.Error())
+			}
+			if cassetteMode == cassetteRecord {
+				providerInstance, err = api.NewRecordingProvider(providerInstance, provider, cassettePath)
+				if err != nil {
+					return nil, fmt.Errorf("error recording cassette '%s' for tenant '%s': %s", cassettePath, tenantName, err.Error())
+				}
+				logrus.Infof("tenant '%s' records the calls to provider '%s' in '%s'", tenantName, provider, cassettePath)
+			}
+		}
+		throttlingOptions, err := getThrottlingOptions(tenant)
 		if err != nil {
-			return nil, fmt.Errorf("error creating tenant '%s' on provider '%s': %s", tenantName, provider, err.Error())
+			return nil, err
+		}
+		throttledProvider := api.NewThrottledProvider(providerInstance, provider, throttlingOptions)
+		providerInstance = api.NewMetricsProvider(throttledProvider, provider, tenantName)
+		cacheTTL, err := getCacheTTL(tenant)
+		if err != nil {
+			return nil, err
 		}
+		cachedProvider := api.NewCachedProvider(providerInstance, provider, tenantName, cacheTTL)
+		providerInstance = cachedProvider
 		serviceCfg, err := providerInstance.GetConfigurationOptions()
 		if err != nil {
 			return nil, err
@@ -181,6 +232,7 @@ func UseService(tenantName string) (newService Service, err error) {
 		var (
 			metadataBucket   objectstorage.Bucket
 			metadataCryptKey *crypt.Key
+			metadataHistory  int
 		)
 		if tenantMetadataFound || tenantObjectStorageFound {
 			// FIXME: This requires tuning too
@@ -222,16 +274,23 @@ func UseService(tenantName string) (newService Service, err error) {
 				}
 				metadataCryptKey = ek
 			}
+			metadataHistory, err = getMetadataHistorySize(tenant)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			return nil, fmt.Errorf("failed to build service: 'metadata' section (and 'objectstorage' as fallback) is missing in configuration file for tenant '%s'", tenantName)
 		}
 
 		// Service is ready
 		newS := &service{
-			Provider:       providerInstance,
-			Location:       objectStorageLocation,
-			metadataBucket: metadataBucket,
-			metadataKey:    metadataCryptKey,
+			Provider:        providerInstance,
+			Location:        objectStorageLocation,
+			metadataBucket:  metadataBucket,
+			metadataKey:     metadataCryptKey,
+			metadataHistory: metadataHistory,
+			throttling:      throttledProvider,
+			cache:           cachedProvider,
 		}
 		return newS, validateRegexps(newS /*tenantClient*/, tenant)
 	}
@@ -400,6 +459,145 @@ func initObjectStorageLocationConfig(authOpts providers.Config, tenant map[strin
 	return config, nil
 }
 
+const (
+	cassetteRecord = "record"
+	cassetteReplay = "replay"
+)
+
+// getCassetteConfig returns the mode ("record", "replay" or "" if the tenant uses the provider as usual) and the path
+// of the cassette declared in the section 'cassette' of the tenant
+func getCassetteConfig(tenant map[string]interface{}) (string, string, error) {
+	cassette, ok := tenant["cassette"].(map[string]interface{})
+	if !ok {
+		return "", "", nil
+	}
+	mode, _ := cassette["Mode"].(string)
+	mode = strings.ToLower(mode)
+	if mode != cassetteRecord && mode != cassetteReplay {
+		return "", "", fmt.Errorf("invalid value '%s' for 'Mode' in section 'cassette': must be '%s' or '%s'", mode, cassetteRecord, cassetteReplay)
+	}
+	path, _ := cassette["Path"].(string)
+	if path == "" {
+		return "", "", fmt.Errorf("missing setting 'Path' in section 'cassette'")
+	}
+	return mode, utils.AbsPathify(path), nil
+}
+
+// getCacheTTL returns the time during which the images, templates, availability zones and regions of the provider
+// are kept in cache, declared by 'TTL' in the section 'cache' of the tenant; 0 disables the cache
+func getCacheTTL(tenant map[string]interface{}) (time.Duration, error) {
+	cache, ok := tenant["cache"].(map[string]interface{})
+	if !ok {
+		return api.DefaultCacheTTL, nil
+	}
+	anon, ok := cache["TTL"]
+	if !ok {
+		return api.DefaultCacheTTL, nil
+	}
+	switch ttl := anon.(type) {
+	case string:
+		duration, err := time.ParseDuration(ttl)
+		if err == nil && duration >= 0 {
+			return duration, nil
+		}
+	case int64:
+		if ttl == 0 {
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid value '%v' for 'TTL' in section 'cache': must be a duration like \"10m\", or 0 to disable the cache", anon)
+}
+
+// getMetadataHistorySize returns the number of revisions kept in the history of each metadata object, declared by
+// 'HistorySize' in the section 'metadata' of the tenant; history is disabled by default
+func getMetadataHistorySize(tenant map[string]interface{}) (int, error) {
+	metadataConfig, ok := tenant["metadata"].(map[string]interface{})
+	if !ok {
+		return 0, nil
+	}
+	switch size := metadataConfig["HistorySize"].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		if size >= 0 {
+			return size, nil
+		}
+	case int64:
+		if size >= 0 {
+			return int(size), nil
+		}
+	case float64:
+		if size >= 0 && size == float64(int(size)) {
+			return int(size), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid value '%v' for 'HistorySize' in section 'metadata': must be a positive integer, or 0 to disable history", metadataConfig["HistorySize"])
+}
+
+// getThrottlingOptions returns the rate limits and the settings of the circuit breaker declared in the section
+// 'throttling' of the tenant
+func getThrottlingOptions(tenant map[string]interface{}) (api.ThrottlingOptions, error) {
+	options := api.ThrottlingOptions{
+		Rates:            map[string]float64{},
+		Bursts:           map[string]int{},
+		BreakerThreshold: api.DefaultBreakerThreshold,
+		BreakerCooldown:  api.DefaultBreakerCooldown,
+	}
+	throttling, ok := tenant["throttling"].(map[string]interface{})
+	if !ok {
+		return options, nil
+	}
+
+	number := func(key string) (float64, bool, error) {
+		switch value := throttling[key].(type) {
+		case nil:
+			return 0, false, nil
+		case float64:
+			if value < 0 {
+				break
+			}
+			return value, true, nil
+		case int64:
+			if value < 0 {
+				break
+			}
+			return float64(value), true, nil
+		}
+		return 0, false, fmt.Errorf("invalid value '%v' for '%s' in section 'throttling': must be a positive number", throttling[key], key)
+	}
+	for _, class := range api.OperationClasses {
+		prefix := strings.Title(class)
+		rate, found, err := number(prefix + "Rate")
+		if err != nil {
+			return options, err
+		}
+		if found {
+			options.Rates[class] = rate
+		}
+		burst, found, err := number(prefix + "Burst")
+		if err != nil {
+			return options, err
+		}
+		if found {
+			options.Bursts[class] = int(burst)
+		}
+	}
+	threshold, found, err := number("BreakerThreshold")
+	if err != nil {
+		return options, err
+	}
+	if found {
+		options.BreakerThreshold = int(threshold)
+	}
+	if cooldown, ok := throttling["BreakerCooldown"].(string); ok {
+		options.BreakerCooldown, err = time.ParseDuration(cooldown)
+		if err != nil {
+			return options, fmt.Errorf("invalid value '%s' for 'BreakerCooldown' in section 'throttling': %v", cooldown, err)
+		}
+	}
+	return options, nil
+}
+
 func validateOVHObjectStorageRegionNaming(context, region, authURL string) error {
 	// If AuthURL contains OVH, special treatment due to change in object storage 'region'-ing since 2020/02/17
 	// Object Storage regions don't contain anymore an index like compute regions
@@ -601,7 +799,18 @@ func getTenantsFromCfg() ([]interface{}, error) {
 	v.AddConfigPath("$HOME/.config/safescale")
 	v.AddConfigPath("/etc/safescale")
 	v.SetConfigName("tenants")
+	return readTenants()
+}
+
+// getTenantsFromFile reads the tenants declared in the file 'path'
+func getTenantsFromFile(path string) ([]interface{}, error) {
+	v = viper.New()
+	v.SetConfigFile(path)
+	return readTenants()
+}
 
+// readTenants reads the tenants from the configuration file of 'v'
+func readTenants() ([]interface{}, error) {
 	if err := v.ReadInConfig(); err != nil { // Handle errors reading the config file
 		msg := fmt.Sprintf("error reading configuration file: %s", err.Error())
 		logrus.Errorf(msg)
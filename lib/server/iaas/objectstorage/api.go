@@ -113,6 +113,16 @@ type Bucket interface {
 	GetSize(string, string) (int64, string, error)
 }
 
+// ConditionalBucket is implemented by the Buckets able to change an object only if it is still in an expected version
+type ConditionalBucket interface {
+	// WriteObjectIfMatch writes into an object only if its ETag is the expected one ("" meaning the object must not exist yet);
+	// returns scerr.ErrDuplicate if the object has been changed in between
+	WriteObjectIfMatch(string, io.Reader, int64, ObjectMetadata, string) (Object, error)
+	// DeleteObjectIfMatch deletes an object only if its ETag is the expected one;
+	// returns scerr.ErrDuplicate if the object has been changed in between
+	DeleteObjectIfMatch(string, string) error
+}
+
 // ObjectMetadata ...
 type ObjectMetadata map[string]interface{}
 
@@ -0,0 +1,324 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/ncw/swift"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	gcs "google.golang.org/api/storage/v1"
+
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// conditionalClient changes an object only if it is still in an expected version, using the API of the Object Storage
+// directly since stow doesn't support preconditions
+type conditionalClient interface {
+	// writeIfMatch writes 'content' in the object if its ETag is 'etag' ("" meaning the object must not exist yet)
+	writeIfMatch(bucketName, objectName string, content []byte, metadata ObjectMetadata, etag string) error
+	// deleteIfMatch deletes the object if its ETag is 'etag'
+	deleteIfMatch(bucketName, objectName, etag string) error
+}
+
+// newConditionalClient returns the conditionalClient of the Object Storage described by 'conf', or nil if its type
+// doesn't support conditional writes
+func newConditionalClient(conf Config) (conditionalClient, error) {
+	switch conf.Type {
+	case "s3":
+		return newS3ConditionalClient(conf)
+	case "google":
+		return newGCSConditionalClient(conf)
+	case "swift":
+		return newSwiftConditionalClient(conf)
+	}
+	return nil, nil
+}
+
+// conditionalBucket is a bucket whose Object Storage supports conditional writes; implements ConditionalBucket
+type conditionalBucket struct {
+	*bucket
+	client conditionalClient
+}
+
+// WriteObjectIfMatch writes into an object only if its ETag is still etag ("" meaning the object must not exist yet)
+func (b *conditionalBucket) WriteObjectIfMatch(objectName string, source io.Reader, sourceSize int64, metadata ObjectMetadata, etag string) (Object, error) {
+	if b == nil || b.bucket == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if objectName == "" {
+		return nil, scerr.InvalidParameterError("objectName", "cannot be empty string")
+	}
+	if source == nil {
+		return nil, scerr.InvalidParameterError("source", "cannot be nil")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d, '%s')", objectName, sourceSize, etag), false /* Trace.ObjectStorage */).GoingIn().OnExitTrace()()
+
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, source, sourceSize); err != nil {
+		return nil, err
+	}
+	err := b.client.writeIfMatch(b.Name, objectName, buffer.Bytes(), metadata, unquoteETag(etag))
+	if err != nil {
+		return nil, err
+	}
+	return newObject(b.bucket, objectName)
+}
+
+// DeleteObjectIfMatch deletes an object only if its ETag is still etag
+func (b *conditionalBucket) DeleteObjectIfMatch(objectName string, etag string) error {
+	if b == nil || b.bucket == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if objectName == "" {
+		return scerr.InvalidParameterError("objectName", "cannot be empty string")
+	}
+	if etag == "" {
+		return scerr.InvalidParameterError("etag", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", objectName, etag), false /* Trace.ObjectStorage */).GoingIn().OnExitTrace()()
+
+	return b.client.deleteIfMatch(b.Name, objectName, unquoteETag(etag))
+}
+
+// unquoteETag removes the quotes some Object Storages put around ETags
+func unquoteETag(etag string) string {
+	return strings.Trim(etag, "\"")
+}
+
+// changedError returns the error telling that the object has been changed since version 'etag' has been read
+func changedError(objectName, etag string) error {
+	if etag == "" {
+		return scerr.DuplicateError(fmt.Sprintf("object '%s' has been created in the meantime", objectName))
+	}
+	return scerr.DuplicateError(fmt.Sprintf("object '%s' has been changed in the meantime", objectName))
+}
+
+// s3ConditionalClient uses the preconditions of S3 (If-Match and If-None-Match headers)
+type s3ConditionalClient struct {
+	client *s3.S3
+}
+
+func newS3ConditionalClient(conf Config) (conditionalClient, error) {
+	awsConfig := &aws.Config{
+		Region:           aws.String(conf.Region),
+		Credentials:      credentials.NewStaticCredentials(conf.User, conf.SecretKey, ""),
+		S3ForcePathStyle: aws.Bool(true),
+	}
+	if conf.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(conf.Endpoint)
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &s3ConditionalClient{client: s3.New(sess)}, nil
+}
+
+func (c *s3ConditionalClient) writeIfMatch(bucketName, objectName string, content []byte, metadata ObjectMetadata, etag string) error {
+	s3Metadata := map[string]*string{}
+	for k, v := range metadata {
+		s3Metadata[k] = aws.String(fmt.Sprintf("%v", v))
+	}
+	req, _ := c.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(objectName),
+		Body:     bytes.NewReader(content),
+		Metadata: s3Metadata,
+	})
+	if etag == "" {
+		req.HTTPRequest.Header.Set("If-None-Match", "*")
+	} else {
+		req.HTTPRequest.Header.Set("If-Match", fmt.Sprintf("%q", etag))
+	}
+	return c.translate(req.Send(), objectName, etag)
+}
+
+func (c *s3ConditionalClient) deleteIfMatch(bucketName, objectName, etag string) error {
+	req, _ := c.client.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectName),
+	})
+	req.HTTPRequest.Header.Set("If-Match", fmt.Sprintf("%q", etag))
+	return c.translate(req.Send(), objectName, etag)
+}
+
+// translate converts the failed preconditions reported by S3 in scerr.ErrDuplicate
+func (c *s3ConditionalClient) translate(err error, objectName, etag string) error {
+	if aerr, ok := err.(awserr.RequestFailure); ok {
+		switch aerr.StatusCode() {
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return changedError(objectName, etag)
+		case http.StatusNotFound:
+			return scerr.NotFoundError(fmt.Sprintf("object '%s' not found", objectName))
+		}
+	}
+	return err
+}
+
+// gcsConditionalClient uses the generation preconditions of Google Cloud Storage
+type gcsConditionalClient struct {
+	service *gcs.Service
+}
+
+func newGCSConditionalClient(conf Config) (conditionalClient, error) {
+	service, err := gcs.NewService(context.Background(), option.WithCredentialsJSON([]byte(conf.Credentials)))
+	if err != nil {
+		return nil, err
+	}
+	return &gcsConditionalClient{service: service}, nil
+}
+
+// generation returns the generation of the object if its ETag is still 'etag'; the generation precondition makes
+// sure it doesn't change between this call and the write
+func (c *gcsConditionalClient) generation(bucketName, objectName, etag string) (int64, error) {
+	if etag == "" {
+		// generation 0 means the object must not exist
+		return 0, nil
+	}
+	o, err := c.service.Objects.Get(bucketName, objectName).Do()
+	if err != nil {
+		if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == http.StatusNotFound {
+			return 0, scerr.DuplicateError(fmt.Sprintf("object '%s' has been deleted in the meantime", objectName))
+		}
+		return 0, err
+	}
+	if unquoteETag(o.Etag) != etag {
+		return 0, changedError(objectName, etag)
+	}
+	return o.Generation, nil
+}
+
+func (c *gcsConditionalClient) writeIfMatch(bucketName, objectName string, content []byte, metadata ObjectMetadata, etag string) error {
+	generation, err := c.generation(bucketName, objectName, etag)
+	if err != nil {
+		return err
+	}
+	gcsMetadata := map[string]string{}
+	for k, v := range metadata {
+		gcsMetadata[k] = fmt.Sprintf("%v", v)
+	}
+	_, err = c.service.Objects.Insert(bucketName, &gcs.Object{Name: objectName, Metadata: gcsMetadata}).
+		Media(bytes.NewReader(content)).
+		IfGenerationMatch(generation).
+		Do()
+	return c.translate(err, objectName, etag)
+}
+
+func (c *gcsConditionalClient) deleteIfMatch(bucketName, objectName, etag string) error {
+	generation, err := c.generation(bucketName, objectName, etag)
+	if err != nil {
+		return err
+	}
+	err = c.service.Objects.Delete(bucketName, objectName).IfGenerationMatch(generation).Do()
+	return c.translate(err, objectName, etag)
+}
+
+// translate converts the failed preconditions reported by Google Cloud Storage in scerr.ErrDuplicate
+func (c *gcsConditionalClient) translate(err error, objectName, etag string) error {
+	if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == http.StatusPreconditionFailed {
+		return changedError(objectName, etag)
+	}
+	return err
+}
+
+// swiftConditionalClient uses the preconditions of Swift; Swift only enforces 'If-None-Match: *' on PUT, so the creation
+// of an object is atomic but its replacement and deletion are checked just before being done
+type swiftConditionalClient struct {
+	connection *swift.Connection
+}
+
+func newSwiftConditionalClient(conf Config) (conditionalClient, error) {
+	connection := &swift.Connection{
+		UserName: conf.User,
+		ApiKey:   conf.SecretKey,
+		AuthUrl:  conf.AuthURL,
+		Domain:   conf.TenantDomain,
+		Tenant:   conf.Tenant,
+		Region:   conf.Region,
+	}
+	err := connection.Authenticate()
+	if err != nil {
+		return nil, err
+	}
+	return &swiftConditionalClient{connection: connection}, nil
+}
+
+// check verifies the object is still in version 'etag'
+func (c *swiftConditionalClient) check(bucketName, objectName, etag string) error {
+	o, _, err := c.connection.Object(bucketName, objectName)
+	if err != nil {
+		if err == swift.ObjectNotFound {
+			return scerr.DuplicateError(fmt.Sprintf("object '%s' has been deleted in the meantime", objectName))
+		}
+		return err
+	}
+	if unquoteETag(o.Hash) != etag {
+		return changedError(objectName, etag)
+	}
+	return nil
+}
+
+func (c *swiftConditionalClient) writeIfMatch(bucketName, objectName string, content []byte, metadata ObjectMetadata, etag string) error {
+	headers := swift.Headers{}
+	for k, v := range metadata {
+		headers["X-Object-Meta-"+k] = fmt.Sprintf("%v", v)
+	}
+	if etag == "" {
+		headers["If-None-Match"] = "*"
+	} else {
+		if err := c.check(bucketName, objectName, etag); err != nil {
+			return err
+		}
+		headers["If-Match"] = etag
+	}
+	_, err := c.connection.ObjectPut(bucketName, objectName, bytes.NewReader(content), false, "", "", headers)
+	return c.translate(err, objectName, etag)
+}
+
+func (c *swiftConditionalClient) deleteIfMatch(bucketName, objectName, etag string) error {
+	if err := c.check(bucketName, objectName, etag); err != nil {
+		return err
+	}
+	err := c.connection.ObjectDelete(bucketName, objectName)
+	if err == swift.ObjectNotFound {
+		return scerr.DuplicateError(fmt.Sprintf("object '%s' has been deleted in the meantime", objectName))
+	}
+	return c.translate(err, objectName, etag)
+}
+
+// translate converts the failed preconditions reported by Swift in scerr.ErrDuplicate
+func (c *swiftConditionalClient) translate(err error, objectName, etag string) error {
+	if serr, ok := err.(*swift.Error); ok && serr.StatusCode == http.StatusPreconditionFailed {
+		return changedError(objectName, etag)
+	}
+	return err
+}
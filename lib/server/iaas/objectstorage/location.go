@@ -37,6 +37,7 @@ import (
 type location struct {
 	stowLocation stow.Location
 	config       Config
+	conditional  conditionalClient
 
 	NbItem           int
 	IdentityEndpoint string
@@ -48,6 +49,9 @@ type location struct {
 
 // NewLocation creates an Object Storage Location based on config
 func NewLocation(conf Config) (Location, error) {
+	if conf.Type == MemoryType {
+		return memoryLocationFor(conf), nil
+	}
 	location := &location{
 		config: conf,
 	}
@@ -97,8 +101,25 @@ func (l *location) connect() error {
 	l.stowLocation, err = stow.Dial(kind, config)
 	if err != nil {
 		log.Debugf("failed dialing location: %v", err)
+		return err
+	}
+
+	l.conditional, err = newConditionalClient(l.config)
+	if err != nil {
+		log.Warnf("conditional writes are not available on Object Storage '%s', metadata locking will be best-effort: %v", kind, err)
+		l.conditional = nil
+	} else if l.conditional == nil {
+		log.Warnf("Object Storage '%s' doesn't support conditional writes, metadata locking will be best-effort", kind)
+	}
+	return nil
+}
+
+// withConditional returns the bucket 'b' able to do conditional writes if the location supports them
+func (l *location) withConditional(b *bucket) Bucket {
+	if l.conditional == nil {
+		return b
 	}
-	return err
+	return &conditionalBucket{bucket: b, client: l.conditional}
 }
 
 // GetType returns the type of ObjectStorage
@@ -183,7 +204,7 @@ func (l *location) GetBucket(bucketName string) (Bucket, error) {
 		//Note: No errors.Wrap here; error needs to be transmitted as-is
 		return nil, err
 	}
-	return b, nil
+	return l.withConditional(b), nil
 }
 
 // CreateBucket ...
@@ -201,11 +222,11 @@ func (l *location) CreateBucket(bucketName string) (Bucket, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &bucket{
+	return l.withConditional(&bucket{
 		location:  l.stowLocation,
 		container: c,
 		Name:      c.Name(),
-	}, nil
+	}), nil
 }
 
 // DeleteBucket removes a bucket from Object Storage
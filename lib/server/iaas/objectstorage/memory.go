@@ -0,0 +1,656 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// MemoryType is the type of the Object Storage locations kept in memory (used by the fake provider and by tests)
+const MemoryType = "memory"
+
+var (
+	// memoryStores contains the content of the memory locations, by tenant and region, so every Location
+	// created with the same Config sees the same buckets during the life of the process
+	memoryStores      = map[string]*memoryStore{}
+	memoryStoresMutex sync.Mutex
+)
+
+// memoryStore is the content of a memory location
+type memoryStore struct {
+	mutex   sync.RWMutex
+	buckets map[string]map[string]*memoryItem
+}
+
+// memoryItem is the content of an object of a memory location
+type memoryItem struct {
+	content  []byte
+	metadata ObjectMetadata
+	lastMod  time.Time
+	etag     string
+}
+
+// NewMemoryLocation returns a new empty Location kept in memory
+func NewMemoryLocation() Location {
+	return &memoryLocation{store: &memoryStore{buckets: map[string]map[string]*memoryItem{}}}
+}
+
+// memoryLocationFor returns the memory location corresponding to conf, created empty on first use
+func memoryLocationFor(conf Config) Location {
+	key := conf.Tenant + "@" + conf.Region
+	memoryStoresMutex.Lock()
+	defer memoryStoresMutex.Unlock()
+	store, ok := memoryStores[key]
+	if !ok {
+		store = &memoryStore{buckets: map[string]map[string]*memoryItem{}}
+		memoryStores[key] = store
+	}
+	return &memoryLocation{store: store}
+}
+
+// memoryLocation is a Location kept in memory
+type memoryLocation struct {
+	store *memoryStore
+}
+
+// GetType returns the type of ObjectStorage
+func (l *memoryLocation) GetType() string {
+	return MemoryType
+}
+
+// ListBuckets returns the names of the buckets starting with prefix
+func (l *memoryLocation) ListBuckets(prefix string) ([]string, error) {
+	l.store.mutex.RLock()
+	defer l.store.mutex.RUnlock()
+	var list []string
+	for name := range l.store.buckets {
+		if strings.HasPrefix(name, prefix) {
+			list = append(list, name)
+		}
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// FindBucket returns true if a bucket with the name exists in location
+func (l *memoryLocation) FindBucket(bucketName string) (bool, error) {
+	if bucketName == "" {
+		return false, scerr.InvalidParameterError("bucketName", "cannot be empty string")
+	}
+	l.store.mutex.RLock()
+	defer l.store.mutex.RUnlock()
+	_, ok := l.store.buckets[bucketName]
+	return ok, nil
+}
+
+// GetBucket ...
+func (l *memoryLocation) GetBucket(bucketName string) (Bucket, error) {
+	found, err := l.FindBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, scerr.NotFoundError(fmt.Sprintf("bucket '%s' not found", bucketName))
+	}
+	return &memoryBucket{store: l.store, name: bucketName}, nil
+}
+
+// CreateBucket ...
+func (l *memoryLocation) CreateBucket(bucketName string) (Bucket, error) {
+	if bucketName == "" {
+		return nil, scerr.InvalidParameterError("bucketName", "cannot be empty string")
+	}
+	l.store.mutex.Lock()
+	defer l.store.mutex.Unlock()
+	if _, ok := l.store.buckets[bucketName]; ok {
+		return nil, scerr.DuplicateError(fmt.Sprintf("bucket '%s' already exists", bucketName))
+	}
+	l.store.buckets[bucketName] = map[string]*memoryItem{}
+	return &memoryBucket{store: l.store, name: bucketName}, nil
+}
+
+// DeleteBucket removes a bucket, which must be empty
+func (l *memoryLocation) DeleteBucket(bucketName string) error {
+	if bucketName == "" {
+		return scerr.InvalidParameterError("bucketName", "cannot be empty string")
+	}
+	l.store.mutex.Lock()
+	defer l.store.mutex.Unlock()
+	items, ok := l.store.buckets[bucketName]
+	if !ok {
+		return scerr.NotFoundError(fmt.Sprintf("bucket '%s' not found", bucketName))
+	}
+	if len(items) > 0 {
+		return scerr.NotAvailableError(fmt.Sprintf("bucket '%s' is not empty", bucketName))
+	}
+	delete(l.store.buckets, bucketName)
+	return nil
+}
+
+// ClearBucket empties a bucket
+func (l *memoryLocation) ClearBucket(bucketName string, path, prefix string) error {
+	b, err := l.getBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	return b.Clear(path, prefix)
+}
+
+// ListObjects lists the objects in a Bucket
+func (l *memoryLocation) ListObjects(bucketName string, path, prefix string) ([]string, error) {
+	b, err := l.getBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.List(path, prefix)
+}
+
+// GetObject ...
+func (l *memoryLocation) GetObject(bucketName string, objectName string) (Object, error) {
+	if objectName == "" {
+		return nil, scerr.InvalidParameterError("objectName", "cannot be empty string")
+	}
+	b, err := l.getBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.newObject(objectName), nil
+}
+
+// ReadObject ...
+func (l *memoryLocation) ReadObject(bucketName, objectName string, writer io.Writer, from, to int64) error {
+	b, err := l.getBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	_, err = b.ReadObject(objectName, writer, from, to)
+	return err
+}
+
+// WriteMultiPartObject ...
+func (l *memoryLocation) WriteMultiPartObject(
+	bucketName string, objectName string,
+	source io.Reader, sourceSize int64,
+	chunkSize int,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	b, err := l.getBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.WriteMultiPartObject(objectName, source, sourceSize, chunkSize, metadata)
+}
+
+// WriteObject ...
+func (l *memoryLocation) WriteObject(
+	bucketName string, objectName string,
+	source io.Reader, size int64,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	b, err := l.getBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.WriteObject(objectName, source, size, metadata)
+}
+
+// DeleteObject ...
+func (l *memoryLocation) DeleteObject(bucketName, objectName string) error {
+	b, err := l.getBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	return b.DeleteObject(objectName)
+}
+
+func (l *memoryLocation) getBucket(bucketName string) (*memoryBucket, error) {
+	b, err := l.GetBucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return b.(*memoryBucket), nil
+}
+
+// memoryBucket is a Bucket of a memory location
+type memoryBucket struct {
+	store *memoryStore
+	name  string
+}
+
+// items returns the items of the bucket whose name starts with the full path built from path and prefix, sorted by name
+func (b *memoryBucket) items(path, prefix string) (names []string, items []*memoryItem, err error) {
+	fullPath := buildFullPath(path, prefix)
+	b.store.mutex.RLock()
+	defer b.store.mutex.RUnlock()
+	content, ok := b.store.buckets[b.name]
+	if !ok {
+		return nil, nil, scerr.NotFoundError(fmt.Sprintf("bucket '%s' not found", b.name))
+	}
+	for name := range content {
+		if strings.HasPrefix(name, fullPath) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		items = append(items, content[name])
+	}
+	return names, items, nil
+}
+
+// item returns the item named name, or nil if it doesn't exist
+func (b *memoryBucket) item(name string) *memoryItem {
+	b.store.mutex.RLock()
+	defer b.store.mutex.RUnlock()
+	return b.store.buckets[b.name][name]
+}
+
+// List lists the names of the objects of the Bucket
+func (b *memoryBucket) List(path, prefix string) ([]string, error) {
+	names, _, err := b.items(path, prefix)
+	return names, err
+}
+
+// Browse walks through the objects in the Bucket and executes callback on each Object found
+func (b *memoryBucket) Browse(path, prefix string, callback func(Object) error) error {
+	names, items, err := b.items(path, prefix)
+	if err != nil {
+		return err
+	}
+	for i, name := range names {
+		if err = callback(&memoryObject{bucket: b, name: name, item: items[i], metadata: items[i].metadata.Clone()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear deletes the objects of the Bucket
+func (b *memoryBucket) Clear(path, prefix string) error {
+	names, _, err := b.items(path, prefix)
+	if err != nil {
+		return err
+	}
+	b.store.mutex.Lock()
+	defer b.store.mutex.Unlock()
+	for _, name := range names {
+		delete(b.store.buckets[b.name], name)
+	}
+	return nil
+}
+
+// newObject returns the object named name, stored or not
+func (b *memoryBucket) newObject(name string) *memoryObject {
+	o := &memoryObject{bucket: b, name: name, metadata: ObjectMetadata{}}
+	if item := b.item(name); item != nil {
+		o.item = item
+		o.metadata = item.metadata.Clone()
+	}
+	return o
+}
+
+// CreateObject ...
+func (b *memoryBucket) CreateObject(objectName string) (Object, error) {
+	return b.newObject(objectName), nil
+}
+
+// GetObject ...
+func (b *memoryBucket) GetObject(objectName string) (Object, error) {
+	o := b.newObject(objectName)
+	if o.item == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return o, nil
+}
+
+// DeleteObject deletes an object from a bucket
+func (b *memoryBucket) DeleteObject(objectName string) error {
+	if objectName == "" {
+		return scerr.InvalidParameterError("objectName", "cannot be empty string")
+	}
+	return b.newObject(objectName).Delete()
+}
+
+// ReadObject ...
+func (b *memoryBucket) ReadObject(objectName string, target io.Writer, from int64, to int64) (Object, error) {
+	o := b.newObject(objectName)
+	if err := o.Read(target, from, to); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// WriteObject ...
+func (b *memoryBucket) WriteObject(objectName string, source io.Reader, sourceSize int64, metadata ObjectMetadata) (Object, error) {
+	o := b.newObject(objectName)
+	o.AddMetadata(metadata)
+	if err := o.Write(source, sourceSize); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// WriteObjectIfMatch writes into an object only if its ETag is still etag ("" meaning the object must not exist yet)
+func (b *memoryBucket) WriteObjectIfMatch(objectName string, source io.Reader, sourceSize int64, metadata ObjectMetadata, etag string) (Object, error) {
+	if source == nil {
+		return nil, scerr.InvalidParameterError("source", "cannot be nil")
+	}
+	content, err := readContent(source, sourceSize)
+	if err != nil {
+		return nil, err
+	}
+	o := b.newObject(objectName)
+	o.AddMetadata(metadata)
+	err = o.putIf(objectName, content, o.metadata, func(current *memoryItem) error {
+		return matchETag(objectName, current, etag)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// DeleteObjectIfMatch deletes an object only if its ETag is still etag
+func (b *memoryBucket) DeleteObjectIfMatch(objectName string, etag string) error {
+	if objectName == "" {
+		return scerr.InvalidParameterError("objectName", "cannot be empty string")
+	}
+	b.store.mutex.Lock()
+	defer b.store.mutex.Unlock()
+	items := b.store.buckets[b.name]
+	current, ok := items[objectName]
+	if !ok {
+		return scerr.NotFoundError(fmt.Sprintf("object '%s' not found in bucket '%s'", objectName, b.name))
+	}
+	if err := matchETag(objectName, current, etag); err != nil {
+		return err
+	}
+	delete(items, objectName)
+	return nil
+}
+
+// matchETag checks that the item currently stored for objectName is in the version etag
+func matchETag(objectName string, current *memoryItem, etag string) error {
+	switch {
+	case current == nil && etag == "":
+		return nil
+	case current == nil:
+		return scerr.DuplicateError(fmt.Sprintf("object '%s' has been deleted in the meantime", objectName))
+	case current.etag != etag:
+		return scerr.DuplicateError(fmt.Sprintf("object '%s' has been changed in the meantime", objectName))
+	}
+	return nil
+}
+
+// WriteMultiPartObject ...
+func (b *memoryBucket) WriteMultiPartObject(
+	objectName string,
+	source io.Reader, sourceSize int64,
+	chunkSize int,
+	metadata ObjectMetadata,
+) (Object, error) {
+
+	o := b.newObject(objectName)
+	o.AddMetadata(metadata)
+	if err := o.WriteMultiPart(source, sourceSize, chunkSize); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// GetName returns the name of the Bucket
+func (b *memoryBucket) GetName() string {
+	return b.name
+}
+
+// GetCount returns the count of objects in the Bucket
+func (b *memoryBucket) GetCount(path, prefix string) (int64, error) {
+	names, _, err := b.items(path, prefix)
+	if err != nil {
+		return -1, err
+	}
+	return int64(len(names)), nil
+}
+
+// GetSize returns the total size of the Objects inside the Bucket
+func (b *memoryBucket) GetSize(path, prefix string) (int64, string, error) {
+	_, items, err := b.items(path, prefix)
+	if err != nil {
+		return -1, "", err
+	}
+	var totalSize int64
+	for _, i := range items {
+		totalSize += int64(len(i.content))
+	}
+	return totalSize, humanReadableSize(totalSize), nil
+}
+
+// memoryObject is an Object of a memory location
+type memoryObject struct {
+	bucket   *memoryBucket
+	name     string
+	item     *memoryItem
+	metadata ObjectMetadata
+}
+
+// Stored return true if the object exists in Object Storage
+func (o *memoryObject) Stored() bool {
+	return o.item != nil
+}
+
+// Reload reloads the data of the Object from the Object Storage
+func (o *memoryObject) Reload() error {
+	item := o.bucket.item(o.name)
+	if item == nil {
+		return scerr.NotFoundError(fmt.Sprintf("object '%s' not found in bucket '%s'", o.name, o.bucket.name))
+	}
+	o.item = item
+	o.metadata = item.metadata.Clone()
+	return nil
+}
+
+// Read reads the content of the object from 'from' to 'to' (the whole content if 'to' is 0) and writes it in 'target'
+func (o *memoryObject) Read(target io.Writer, from, to int64) error {
+	if target == nil {
+		return scerr.InvalidParameterError("target", "cannot be nil")
+	}
+	if from > to {
+		return scerr.InvalidParameterError("from", "cannot be greater than 'to'")
+	}
+	if err := o.Reload(); err != nil {
+		return err
+	}
+
+	content := o.item.content
+	end := int64(len(content))
+	if to > 0 && to < end {
+		end = to
+	}
+	if from > end {
+		from = end
+	}
+	_, err := target.Write(content[from:end])
+	return err
+}
+
+// Write the source to the object in Object Storage
+func (o *memoryObject) Write(source io.Reader, sourceSize int64) error {
+	if source == nil {
+		return scerr.InvalidParameterError("source", "cannot be nil")
+	}
+	content, err := readContent(source, sourceSize)
+	if err != nil {
+		return err
+	}
+	return o.put(o.name, content, o.metadata)
+}
+
+// readContent reads sourceSize bytes from source, or everything if sourceSize is negative
+func readContent(source io.Reader, sourceSize int64) ([]byte, error) {
+	if sourceSize < 0 {
+		return ioutil.ReadAll(source)
+	}
+	content := make([]byte, sourceSize)
+	_, err := io.ReadFull(source, content)
+	return content, err
+}
+
+// put stores content with metadata in the object named name of the bucket
+func (o *memoryObject) put(name string, content []byte, metadata ObjectMetadata) error {
+	return o.putIf(name, content, metadata, nil)
+}
+
+// putIf stores content like put, provided match (if not nil) accepts the item currently stored (nil if none)
+func (o *memoryObject) putIf(name string, content []byte, metadata ObjectMetadata, match func(*memoryItem) error) error {
+	sum := md5.Sum(content)
+	item := &memoryItem{
+		content:  content,
+		metadata: metadata.Clone(),
+		lastMod:  time.Now(),
+		etag:     hex.EncodeToString(sum[:]),
+	}
+
+	o.bucket.store.mutex.Lock()
+	defer o.bucket.store.mutex.Unlock()
+	items, ok := o.bucket.store.buckets[o.bucket.name]
+	if !ok {
+		return scerr.NotFoundError(fmt.Sprintf("bucket '%s' not found", o.bucket.name))
+	}
+	if match != nil {
+		if err := match(items[name]); err != nil {
+			return err
+		}
+	}
+	items[name] = item
+	if name == o.name {
+		o.item = item
+	}
+	return nil
+}
+
+// WriteMultiPart writes big data to Object, by parts named after the object and the index of the part
+func (o *memoryObject) WriteMultiPart(source io.Reader, sourceSize int64, chunkSize int) error {
+	if chunkSize <= 0 {
+		return scerr.InvalidParameterError("chunkSize", "must be greater than 0")
+	}
+	metadata := o.metadata.Clone()
+	metadata["Split"] = o.name
+	for index, remaining := 0, sourceSize; remaining > 0; index++ {
+		size := int64(chunkSize)
+		if remaining < size {
+			size = remaining
+		}
+		content := make([]byte, size)
+		if _, err := io.ReadFull(source, content); err != nil {
+			return fmt.Errorf("failed to read data from source to write in chunk of object '%s' in bucket '%s': %v", o.name, o.bucket.name, err)
+		}
+		if err := o.put(o.name+strconv.Itoa(index), content, metadata); err != nil {
+			return err
+		}
+		remaining -= size
+	}
+	return nil
+}
+
+// Delete deletes the object from Object Storage
+func (o *memoryObject) Delete() error {
+	o.bucket.store.mutex.Lock()
+	defer o.bucket.store.mutex.Unlock()
+	items := o.bucket.store.buckets[o.bucket.name]
+	if _, ok := items[o.name]; !ok {
+		return scerr.NotFoundError(fmt.Sprintf("object '%s' not found in bucket '%s'", o.name, o.bucket.name))
+	}
+	delete(items, o.name)
+	o.item = nil
+	return nil
+}
+
+// AddMetadata adds missing entries in object metadata
+func (o *memoryObject) AddMetadata(newMetadata ObjectMetadata) {
+	for k, v := range newMetadata {
+		if _, found := o.metadata[k]; !found {
+			o.metadata[k] = v
+		}
+	}
+}
+
+// ForceAddMetadata overwrites the metadata entries of the object by the ones provided in parameter
+func (o *memoryObject) ForceAddMetadata(newMetadata ObjectMetadata) {
+	for k, v := range newMetadata {
+		o.metadata[k] = v
+	}
+}
+
+// ReplaceMetadata replaces object metadata with the ones provided in parameter
+func (o *memoryObject) ReplaceMetadata(newMetadata ObjectMetadata) {
+	o.metadata = newMetadata.Clone()
+}
+
+// GetID returns the ID of the object, which is its name
+func (o *memoryObject) GetID() string {
+	if o.item == nil {
+		return ""
+	}
+	return o.name
+}
+
+// GetName returns the name of the object
+func (o *memoryObject) GetName() string {
+	return o.name
+}
+
+// GetLastUpdate returns the date of last update
+func (o *memoryObject) GetLastUpdate() (time.Time, error) {
+	if o.item == nil {
+		return time.Now(), fmt.Errorf("object metadata not found")
+	}
+	return o.item.lastMod, nil
+}
+
+// GetSize returns the size of the content of the object
+func (o *memoryObject) GetSize() int64 {
+	if o.item == nil {
+		return -1
+	}
+	return int64(len(o.item.content))
+}
+
+// GetETag returns the md5sum of the content of the object
+func (o *memoryObject) GetETag() string {
+	if o.item == nil {
+		return ""
+	}
+	return o.item.etag
+}
+
+// GetMetadata returns the metadata of the object
+func (o *memoryObject) GetMetadata() ObjectMetadata {
+	return o.metadata.Clone()
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLocation(t *testing.T) {
+	location := NewMemoryLocation()
+	assert.Equal(t, MemoryType, location.GetType())
+
+	_, err := location.GetBucket("bucket")
+	assert.NotNil(t, err)
+
+	bucket, err := location.CreateBucket("bucket")
+	require.Nil(t, err)
+	_, err = location.CreateBucket("bucket")
+	assert.NotNil(t, err)
+
+	content := "0123456789"
+	o, err := bucket.WriteObject("dir/object", strings.NewReader(content), int64(len(content)), ObjectMetadata{"A": "B"})
+	require.Nil(t, err)
+	assert.True(t, o.Stored())
+	assert.Equal(t, int64(len(content)), o.GetSize())
+	assert.NotEmpty(t, o.GetETag())
+
+	var buffer bytes.Buffer
+	err = location.ReadObject("bucket", "dir/object", &buffer, 0, 0)
+	require.Nil(t, err)
+	assert.Equal(t, content, buffer.String())
+
+	buffer.Reset()
+	err = location.ReadObject("bucket", "dir/object", &buffer, 2, 5)
+	require.Nil(t, err)
+	assert.Equal(t, "234", buffer.String())
+
+	o, err = bucket.GetObject("dir/object")
+	require.Nil(t, err)
+	assert.Equal(t, "B", o.GetMetadata()["A"])
+
+	_, err = location.WriteMultiPartObject("bucket", "split", strings.NewReader(content), int64(len(content)), 4, ObjectMetadata{})
+	require.Nil(t, err)
+	names, err := location.ListObjects("bucket", RootPath, "split")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"split0", "split1", "split2"}, names)
+
+	names, err = bucket.List("dir", NoPrefix)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"dir/object"}, names)
+
+	// Locations created from the same configuration share their content
+	same, err := NewLocation(Config{Type: MemoryType, Tenant: "tenant", Region: "region"})
+	require.Nil(t, err)
+	_, err = same.CreateBucket("shared")
+	require.Nil(t, err)
+	other, err := NewLocation(Config{Type: MemoryType, Tenant: "tenant", Region: "region"})
+	require.Nil(t, err)
+	found, err := other.FindBucket("shared")
+	require.Nil(t, err)
+	assert.True(t, found)
+
+	err = location.DeleteBucket("bucket")
+	assert.NotNil(t, err)
+	err = location.ClearBucket("bucket", RootPath, NoPrefix)
+	require.Nil(t, err)
+	err = location.DeleteBucket("bucket")
+	assert.Nil(t, err)
+	err = location.DeleteObject("bucket", "dir/object")
+	assert.NotNil(t, err)
+}
+
+func TestMemoryConditionalWrite(t *testing.T) {
+	location := NewMemoryLocation()
+	b, err := location.CreateBucket("bucket")
+	require.Nil(t, err)
+	bucket, ok := b.(ConditionalBucket)
+	require.True(t, ok)
+
+	o, err := bucket.WriteObjectIfMatch("lock", strings.NewReader("first"), 5, nil, "")
+	require.Nil(t, err)
+	etag := o.GetETag()
+
+	// The object exists now, so creating it again must fail
+	_, err = bucket.WriteObjectIfMatch("lock", strings.NewReader("other"), 5, nil, "")
+	_, ok = err.(scerr.ErrDuplicate)
+	assert.True(t, ok)
+
+	o, err = bucket.WriteObjectIfMatch("lock", strings.NewReader("second"), 6, nil, etag)
+	require.Nil(t, err)
+	assert.NotEqual(t, etag, o.GetETag())
+
+	// etag is outdated
+	_, err = bucket.WriteObjectIfMatch("lock", strings.NewReader("third"), 5, nil, etag)
+	_, ok = err.(scerr.ErrDuplicate)
+	assert.True(t, ok)
+	err = bucket.DeleteObjectIfMatch("lock", etag)
+	_, ok = err.(scerr.ErrDuplicate)
+	assert.True(t, ok)
+
+	err = bucket.DeleteObjectIfMatch("lock", o.GetETag())
+	require.Nil(t, err)
+	names, err := b.List(RootPath, "lock")
+	require.Nil(t, err)
+	assert.Empty(t, names)
+}
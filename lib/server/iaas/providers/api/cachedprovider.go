@@ -0,0 +1,536 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+)
+
+// DefaultCacheTTL is the time during which the answers of the provider are kept in cache if not set in the tenant
+const DefaultCacheTTL = 10 * time.Minute
+
+// cachedCalls are the calls whose answers are kept in cache: they read resources changing seldom and are made
+// again and again (selection of templates, search of images, every host created, ...)
+var cachedCalls = []string{"ListImages", "ListTemplates", "GetImage", "GetTemplate", "ListAvailabilityZones", "ListRegions"}
+
+// cacheEntry is an answer of the provider kept in cache until expiration
+type cacheEntry struct {
+	value      interface{}
+	expiration time.Time
+}
+
+// providerCache keeps the answers of the provider, one utils.Cache per call, the key being the arguments of the call
+type providerCache struct {
+	lock  sync.RWMutex
+	ttl   time.Duration
+	calls map[string]utils.Cache
+}
+
+// get returns the answer to the call with key as arguments, if it is in cache and not expired
+func (pc *providerCache) get(call, key string) (interface{}, bool) {
+	pc.lock.RLock()
+	defer pc.lock.RUnlock()
+
+	anon, ok := pc.calls[call].Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry := anon.(cacheEntry)
+	if time.Now().After(entry.expiration) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set keeps value as the answer to the call with key as arguments
+func (pc *providerCache) set(call, key string, value interface{}) {
+	pc.lock.RLock()
+	defer pc.lock.RUnlock()
+
+	_ = pc.calls[call].ForceSet(key, cacheEntry{value: value, expiration: time.Now().Add(pc.ttl)})
+}
+
+// invalidate empties the cache of the calls, or of every call if none is given
+func (pc *providerCache) invalidate(calls ...string) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if len(calls) == 0 {
+		calls = cachedCalls
+	}
+	for _, call := range calls {
+		pc.calls[call] = utils.NewMapCache()
+	}
+}
+
+// CachedProvider is a Provider decorator keeping in cache, for a time (TTL), the answers of the provider it wraps to
+// the calls listing and getting images, templates, availability zones and regions.
+// The cache of the images is emptied when an image is created or deleted; Invalidate() empties the whole cache.
+// Each tenant having its own provider, each tenant has its own cache.
+type CachedProvider struct {
+	InnerProvider Provider
+	Name          string
+	Tenant        string
+	cache         *providerCache
+}
+
+// NewCachedProvider ...
+func NewCachedProvider(innerProvider Provider, name, tenant string, ttl time.Duration) *CachedProvider {
+	w := &CachedProvider{
+		InnerProvider: innerProvider,
+		Name:          name,
+		Tenant:        tenant,
+		cache:         &providerCache{ttl: ttl, calls: map[string]utils.Cache{}},
+	}
+	w.cache.invalidate()
+	return w
+}
+
+// Invalidate empties the cache, the next calls reaching the provider
+func (w CachedProvider) Invalidate() {
+	w.cache.invalidate()
+}
+
+// cached returns the answer in cache to the call with args as arguments; if there is none, it is asked to the
+// provider with fetch and kept in cache if no error occurred
+func (w CachedProvider) cached(call string, args []interface{}, fetch func() (interface{}, error)) (interface{}, error) {
+	if w.cache.ttl <= 0 {
+		return fetch()
+	}
+
+	key := fmt.Sprint(args...)
+	if value, ok := w.cache.get(call, key); ok {
+		metrics.ObserveProviderCache(w.Name, w.Tenant, call, true)
+		return value, nil
+	}
+	metrics.ObserveProviderCache(w.Name, w.Tenant, call, false)
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	w.cache.set(call, key, value)
+	return value, nil
+}
+
+// ListImages ...
+func (w CachedProvider) ListImages(all bool) ([]resources.Image, error) {
+	anon, err := w.cached("ListImages", []interface{}{all}, func() (interface{}, error) {
+		return w.InnerProvider.ListImages(all)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the caller gets its own slice, it may sort or filter it, and its own images, it may change them
+	cached := anon.([]resources.Image)
+	images := make([]resources.Image, 0, len(cached))
+	for i := range cached {
+		clone, err := cloneImage(&cached[i])
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, *clone)
+	}
+	return images, nil
+}
+
+// ListTemplates ...
+func (w CachedProvider) ListTemplates(all bool) ([]resources.HostTemplate, error) {
+	anon, err := w.cached("ListTemplates", []interface{}{all}, func() (interface{}, error) {
+		return w.InnerProvider.ListTemplates(all)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]resources.HostTemplate{}, anon.([]resources.HostTemplate)...), nil
+}
+
+// GetImage ...
+func (w CachedProvider) GetImage(id string) (*resources.Image, error) {
+	anon, err := w.cached("GetImage", []interface{}{id}, func() (interface{}, error) {
+		return w.InnerProvider.GetImage(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	image, _ := anon.(*resources.Image)
+	if image == nil {
+		return nil, nil
+	}
+	// the caller gets its own copy, it may change it
+	return cloneImage(image)
+}
+
+// cloneImage returns a copy of image not sharing its properties
+func cloneImage(image *resources.Image) (*resources.Image, error) {
+	if image.Properties == nil {
+		clone := *image
+		return &clone, nil
+	}
+	jsoned, err := image.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	clone := resources.NewImage()
+	err = clone.Deserialize(jsoned)
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// GetTemplate ...
+func (w CachedProvider) GetTemplate(id string) (*resources.HostTemplate, error) {
+	anon, err := w.cached("GetTemplate", []interface{}{id}, func() (interface{}, error) {
+		return w.InnerProvider.GetTemplate(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	template, _ := anon.(*resources.HostTemplate)
+	if template == nil {
+		return nil, nil
+	}
+	clone := *template
+	return &clone, nil
+}
+
+// ListAvailabilityZones ...
+func (w CachedProvider) ListAvailabilityZones() (map[string]bool, error) {
+	anon, err := w.cached("ListAvailabilityZones", nil, func() (interface{}, error) {
+		return w.InnerProvider.ListAvailabilityZones()
+	})
+	if err != nil {
+		return nil, err
+	}
+	zones := map[string]bool{}
+	for k, v := range anon.(map[string]bool) {
+		zones[k] = v
+	}
+	return zones, nil
+}
+
+// ListRegions ...
+func (w CachedProvider) ListRegions() ([]string, error) {
+	anon, err := w.cached("ListRegions", nil, func() (interface{}, error) {
+		return w.InnerProvider.ListRegions()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, anon.([]string)...), nil
+}
+
+// CreateImage ...
+func (w CachedProvider) CreateImage(hostID string, name string) (*resources.Image, error) {
+	defer w.cache.invalidate("ListImages", "GetImage")
+	return w.InnerProvider.CreateImage(hostID, name)
+}
+
+// DeleteImage ...
+func (w CachedProvider) DeleteImage(id string) error {
+	defer w.cache.invalidate("ListImages", "GetImage")
+	return w.InnerProvider.DeleteImage(id)
+}
+
+// Build ...
+func (w CachedProvider) Build(something map[string]interface{}) (Provider, error) {
+	return w.InnerProvider.Build(something)
+}
+
+// GetAuthenticationOptions ...
+func (w CachedProvider) GetAuthenticationOptions() (providers.Config, error) {
+	return w.InnerProvider.GetAuthenticationOptions()
+}
+
+// GetConfigurationOptions ...
+func (w CachedProvider) GetConfigurationOptions() (providers.Config, error) {
+	return w.InnerProvider.GetConfigurationOptions()
+}
+
+// GetName ...
+func (w CachedProvider) GetName() string {
+	return w.InnerProvider.GetName()
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (w CachedProvider) GetCapabilities() providers.Capabilities {
+	return w.InnerProvider.GetCapabilities()
+}
+
+// GetTenantParameters ...
+func (w CachedProvider) GetTenantParameters() map[string]interface{} {
+	return w.InnerProvider.GetTenantParameters()
+}
+
+// CreateKeyPair ...
+func (w CachedProvider) CreateKeyPair(name string) (*resources.KeyPair, error) {
+	return w.InnerProvider.CreateKeyPair(name)
+}
+
+// GetKeyPair ...
+func (w CachedProvider) GetKeyPair(id string) (*resources.KeyPair, error) {
+	return w.InnerProvider.GetKeyPair(id)
+}
+
+// ListKeyPairs ...
+func (w CachedProvider) ListKeyPairs() ([]resources.KeyPair, error) {
+	return w.InnerProvider.ListKeyPairs()
+}
+
+// DeleteKeyPair ...
+func (w CachedProvider) DeleteKeyPair(id string) error {
+	return w.InnerProvider.DeleteKeyPair(id)
+}
+
+// CreateNetwork ...
+func (w CachedProvider) CreateNetwork(req resources.NetworkRequest) (*resources.Network, error) {
+	return w.InnerProvider.CreateNetwork(req)
+}
+
+// GetNetwork ...
+func (w CachedProvider) GetNetwork(id string) (*resources.Network, error) {
+	return w.InnerProvider.GetNetwork(id)
+}
+
+// GetNetworkByName ...
+func (w CachedProvider) GetNetworkByName(name string) (*resources.Network, error) {
+	return w.InnerProvider.GetNetworkByName(name)
+}
+
+// ListNetworks ...
+func (w CachedProvider) ListNetworks() ([]*resources.Network, error) {
+	return w.InnerProvider.ListNetworks()
+}
+
+// DeleteNetwork ...
+func (w CachedProvider) DeleteNetwork(id string) error {
+	return w.InnerProvider.DeleteNetwork(id)
+}
+
+// CreateGateway ...
+func (w CachedProvider) CreateGateway(req resources.GatewayRequest) (*resources.Host, *userdata.Content, error) {
+	return w.InnerProvider.CreateGateway(req)
+}
+
+// DeleteGateway ...
+func (w CachedProvider) DeleteGateway(networkID string) error {
+	return w.InnerProvider.DeleteGateway(networkID)
+}
+
+// CreateVIP ...
+func (w CachedProvider) CreateVIP(networkID string, description string) (*resources.VirtualIP, error) {
+	return w.InnerProvider.CreateVIP(networkID, description)
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (w CachedProvider) AddPublicIPToVIP(vip *resources.VirtualIP) error {
+	return w.InnerProvider.AddPublicIPToVIP(vip)
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (w CachedProvider) BindHostToVIP(vip *resources.VirtualIP, hostID string) error {
+	return w.InnerProvider.BindHostToVIP(vip, hostID)
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (w CachedProvider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) error {
+	return w.InnerProvider.UnbindHostFromVIP(vip, hostID)
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (w CachedProvider) DeleteVIP(vip *resources.VirtualIP) error {
+	return w.InnerProvider.DeleteVIP(vip)
+}
+
+// CreateHost ...
+func (w CachedProvider) CreateHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error) {
+	return w.InnerProvider.CreateHost(request)
+}
+
+// InspectHost ...
+func (w CachedProvider) InspectHost(something interface{}) (*resources.Host, error) {
+	return w.InnerProvider.InspectHost(something)
+}
+
+// GetHostByName ...
+func (w CachedProvider) GetHostByName(name string) (*resources.Host, error) {
+	return w.InnerProvider.GetHostByName(name)
+}
+
+// GetHostState ...
+func (w CachedProvider) GetHostState(something interface{}) (hoststate.Enum, error) {
+	return w.InnerProvider.GetHostState(something)
+}
+
+// ListHosts ...
+func (w CachedProvider) ListHosts() ([]*resources.Host, error) {
+	return w.InnerProvider.ListHosts()
+}
+
+// DeleteHost ...
+func (w CachedProvider) DeleteHost(id string) error {
+	return w.InnerProvider.DeleteHost(id)
+}
+
+// StopHost ...
+func (w CachedProvider) StopHost(id string) error {
+	return w.InnerProvider.StopHost(id)
+}
+
+// StartHost ...
+func (w CachedProvider) StartHost(id string) error {
+	return w.InnerProvider.StartHost(id)
+}
+
+// RebootHost ...
+func (w CachedProvider) RebootHost(id string) error {
+	return w.InnerProvider.RebootHost(id)
+}
+
+// ResizeHost ...
+func (w CachedProvider) ResizeHost(id string, request resources.SizingRequirements) (*resources.Host, error) {
+	return w.InnerProvider.ResizeHost(id, request)
+}
+
+// UpdateHostTags ...
+func (w CachedProvider) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	return w.InnerProvider.UpdateHostTags(id, set, unset)
+}
+
+// CreateVolume ...
+func (w CachedProvider) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
+	return w.InnerProvider.CreateVolume(request)
+}
+
+// GetVolume ...
+func (w CachedProvider) GetVolume(id string) (*resources.Volume, error) {
+	return w.InnerProvider.GetVolume(id)
+}
+
+// ListVolumes ...
+func (w CachedProvider) ListVolumes() ([]resources.Volume, error) {
+	return w.InnerProvider.ListVolumes()
+}
+
+// DeleteVolume ...
+func (w CachedProvider) DeleteVolume(id string) error {
+	return w.InnerProvider.DeleteVolume(id)
+}
+
+// CreateVolumeSnapshot ...
+func (w CachedProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w CachedProvider) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w CachedProvider) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	return w.InnerProvider.ListVolumeSnapshots(volumeID)
+}
+
+// DeleteVolumeSnapshot ...
+func (w CachedProvider) DeleteVolumeSnapshot(id string) error {
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
+// CreateVolumeFromSnapshot ...
+func (w CachedProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	return w.InnerProvider.CreateVolumeFromSnapshot(snapshotID, request)
+}
+
+// ResizeVolume ...
+func (w CachedProvider) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	return w.InnerProvider.ResizeVolume(id, size)
+}
+
+// UpdateVolumeTags ...
+func (w CachedProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	return w.InnerProvider.UpdateVolumeTags(id, set, unset)
+}
+
+// CreateVolumeAttachment ...
+func (w CachedProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error) {
+	return w.InnerProvider.CreateVolumeAttachment(request)
+}
+
+// GetVolumeAttachment ...
+func (w CachedProvider) GetVolumeAttachment(serverID, id string) (*resources.VolumeAttachment, error) {
+	return w.InnerProvider.GetVolumeAttachment(serverID, id)
+}
+
+// ListVolumeAttachments ...
+func (w CachedProvider) ListVolumeAttachments(serverID string) ([]resources.VolumeAttachment, error) {
+	return w.InnerProvider.ListVolumeAttachments(serverID)
+}
+
+// DeleteVolumeAttachment ...
+func (w CachedProvider) DeleteVolumeAttachment(serverID, id string) error {
+	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
+}
+
+// CreateSecurityGroup ...
+func (w CachedProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w CachedProvider) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	return w.InnerProvider.InspectSecurityGroup(id)
+}
+
+// ListSecurityGroups ...
+func (w CachedProvider) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// DeleteSecurityGroup ...
+func (w CachedProvider) DeleteSecurityGroup(id string) error {
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w CachedProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	return w.InnerProvider.AddRuleToSecurityGroup(id, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w CachedProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(id, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w CachedProvider) BindSecurityGroupToHost(id, hostID string) error {
+	return w.InnerProvider.BindSecurityGroupToHost(id, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w CachedProvider) UnbindSecurityGroupFromHost(id, hostID string) error {
+	return w.InnerProvider.UnbindSecurityGroupFromHost(id, hostID)
+}
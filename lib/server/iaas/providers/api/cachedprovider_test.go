@@ -0,0 +1,188 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/imageproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// countingProvider serves a few images, counting the calls made to it
+type countingProvider struct {
+	Provider
+	calls  map[string]int
+	images []resources.Image
+}
+
+func (p *countingProvider) ListImages(all bool) ([]resources.Image, error) {
+	p.calls["ListImages"]++
+	return append([]resources.Image{}, p.images...), nil
+}
+
+func (p *countingProvider) GetImage(id string) (*resources.Image, error) {
+	p.calls["GetImage"]++
+	for _, i := range p.images {
+		if i.ID == id {
+			image := i
+			return &image, nil
+		}
+	}
+	return nil, scerr.NotFoundError("image not found")
+}
+
+func (p *countingProvider) DeleteImage(id string) error {
+	p.calls["DeleteImage"]++
+	for k, i := range p.images {
+		if i.ID == id {
+			p.images = append(p.images[:k], p.images[k+1:]...)
+			return nil
+		}
+	}
+	return scerr.NotFoundError("image not found")
+}
+
+func newCountingProvider() *countingProvider {
+	return &countingProvider{
+		calls:  map[string]int{},
+		images: []resources.Image{{ID: "1", Name: "Ubuntu 18.04"}, {ID: "2", Name: "CentOS 7"}},
+	}
+}
+
+func TestCachedProvider(t *testing.T) {
+	inner := newCountingProvider()
+	w := NewCachedProvider(inner, "stub", "tenant", time.Minute)
+
+	images, err := w.ListImages(false)
+	require.Nil(t, err)
+	assert.Len(t, images, 2)
+	images[0].Name = "changed by the caller"
+	images, err = w.ListImages(false)
+	require.Nil(t, err)
+	assert.Equal(t, "Ubuntu 18.04", images[0].Name)
+	assert.Equal(t, 1, inner.calls["ListImages"])
+
+	// the arguments are part of the key
+	_, err = w.ListImages(true)
+	require.Nil(t, err)
+	assert.Equal(t, 2, inner.calls["ListImages"])
+
+	image, err := w.GetImage("2")
+	require.Nil(t, err)
+	assert.Equal(t, "CentOS 7", image.Name)
+	_, err = w.GetImage("2")
+	require.Nil(t, err)
+	assert.Equal(t, 1, inner.calls["GetImage"])
+
+	// errors are not kept
+	_, err = w.GetImage("3")
+	assert.NotNil(t, err)
+	_, err = w.GetImage("3")
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, inner.calls["GetImage"])
+
+	// deleting an image empties the cache of the images
+	require.Nil(t, w.DeleteImage("2"))
+	images, err = w.ListImages(false)
+	require.Nil(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, 3, inner.calls["ListImages"])
+	_, err = w.GetImage("2")
+	assert.NotNil(t, err)
+
+	w.Invalidate()
+	_, err = w.ListImages(false)
+	require.Nil(t, err)
+	assert.Equal(t, 4, inner.calls["ListImages"])
+}
+
+func TestCachedProviderTTL(t *testing.T) {
+	inner := newCountingProvider()
+	w := NewCachedProvider(inner, "stub", "tenant", 20*time.Millisecond)
+	_, _ = w.ListImages(false)
+	_, _ = w.ListImages(false)
+	assert.Equal(t, 1, inner.calls["ListImages"])
+	time.Sleep(30 * time.Millisecond)
+	_, _ = w.ListImages(false)
+	assert.Equal(t, 2, inner.calls["ListImages"])
+
+	disabled := NewCachedProvider(inner, "stub", "tenant", 0)
+	_, _ = disabled.ListImages(false)
+	_, _ = disabled.ListImages(false)
+	assert.Equal(t, 4, inner.calls["ListImages"])
+}
+
+// installedFeatures returns the names of the features recorded in the properties of image
+func installedFeatures(t *testing.T, image *resources.Image) []string {
+	var names []string
+	err := image.Properties.LockForRead(imageproperty.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
+		for name := range clonable.(*propsv1.ImageFeatures).Installed {
+			names = append(names, name)
+		}
+		return nil
+	})
+	require.Nil(t, err)
+	return names
+}
+
+func TestCachedProviderImageProperties(t *testing.T) {
+	inner := newCountingProvider()
+	custom := resources.NewImage()
+	custom.ID = "3"
+	custom.Name = "docker-ready"
+	err := custom.Properties.LockForWrite(imageproperty.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.ImageFeatures).Installed["docker"] = &propsv1.HostInstalledFeature{HostContext: true}
+		return nil
+	})
+	require.Nil(t, err)
+	inner.images = append(inner.images, *custom)
+	w := NewCachedProvider(inner, "stub", "tenant", time.Minute)
+
+	// the properties changed by a caller are not changed in the cache
+	image, err := w.GetImage("3")
+	require.Nil(t, err)
+	err = image.Properties.LockForWrite(imageproperty.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
+		delete(clonable.(*propsv1.ImageFeatures).Installed, "docker")
+		return nil
+	})
+	require.Nil(t, err)
+	image, err = w.GetImage("3")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"docker"}, installedFeatures(t, image))
+
+	images, err := w.ListImages(false)
+	require.Nil(t, err)
+	require.Len(t, images, 3)
+	err = images[2].Properties.LockForWrite(imageproperty.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.ImageFeatures).Installed["ansible"] = &propsv1.HostInstalledFeature{}
+		return nil
+	})
+	require.Nil(t, err)
+	images, err = w.ListImages(false)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"docker"}, installedFeatures(t, &images[2]))
+	assert.Equal(t, 1, inner.calls["GetImage"])
+	assert.Equal(t, 1, inner.calls["ListImages"])
+}
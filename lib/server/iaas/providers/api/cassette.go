@@ -0,0 +1,449 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// Interaction is a call to a provider recorded in a cassette, with its arguments, its results and its error
+type Interaction struct {
+	Call      string            `json:"call"`
+	Arguments json.RawMessage   `json:"arguments,omitempty"`
+	Results   []json.RawMessage `json:"results,omitempty"`
+	Error     *RecordedError    `json:"error,omitempty"`
+}
+
+// RecordedError is an error returned by a provider, recorded with its kind to be rebuilt as the same scerr error on replay
+type RecordedError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// newRecordedError ...
+func newRecordedError(err error) *RecordedError {
+	if err == nil {
+		return nil
+	}
+	var kind string
+	switch err.(type) {
+	case scerr.ErrTimeout:
+		kind = "timeout"
+	case scerr.ErrNotFound:
+		kind = "notfound"
+	case scerr.ErrNotAvailable:
+		kind = "notavailable"
+	case scerr.ErrDuplicate:
+		kind = "duplicate"
+	case scerr.ErrInvalidRequest:
+		kind = "invalidrequest"
+	case scerr.ErrInvalidParameter:
+		kind = "invalidparameter"
+	case scerr.ErrUnauthorized:
+		kind = "unauthorized"
+	case scerr.ErrForbidden:
+		kind = "forbidden"
+	case scerr.ErrAborted:
+		kind = "aborted"
+	case scerr.ErrOverload:
+		kind = "overload"
+	case scerr.ErrNotImplemented:
+		kind = "notimplemented"
+	case scerr.ErrInconsistent:
+		kind = "inconsistent"
+	}
+	return &RecordedError{Kind: kind, Message: err.Error()}
+}
+
+// ToError rebuilds the error recorded
+func (re *RecordedError) ToError() error {
+	if re == nil {
+		return nil
+	}
+	switch re.Kind {
+	case "timeout":
+		return scerr.TimeoutError(re.Message, 0, nil)
+	case "notfound":
+		return scerr.NotFoundError(re.Message)
+	case "notavailable":
+		return scerr.NotAvailableError(re.Message)
+	case "duplicate":
+		return scerr.DuplicateError(re.Message)
+	case "invalidrequest":
+		return scerr.InvalidRequestError(re.Message)
+	case "invalidparameter":
+		// scerr.InvalidParameterError() would decorate the message a second time
+		return scerr.ErrInvalidParameter{ErrCore: scerr.ErrCore{Message: re.Message}}
+	case "unauthorized":
+		return scerr.UnauthorizedError(re.Message)
+	case "forbidden":
+		return scerr.ForbiddenError(re.Message)
+	case "aborted":
+		return scerr.AbortedError(re.Message, nil)
+	case "overload":
+		return scerr.OverloadError(re.Message)
+	case "notimplemented":
+		return scerr.ErrNotImplemented{ErrCore: scerr.ErrCore{Message: re.Message}}
+	case "inconsistent":
+		return scerr.InconsistentError(re.Message)
+	default:
+		return errors.New(re.Message)
+	}
+}
+
+// secretSettings are the substrings of the names of the settings and fields whose values are not written in a cassette
+var secretSettings = []string{"password", "secret", "token", "credential", "privatekey", "applicationkey", "consumerkey"}
+
+// redacted replaces the values of the secrets in a cassette
+const redacted = "********"
+
+// isSecret tells if the setting or field named name holds a secret ("Password", "private_key", "PrivateKey", ...)
+func isSecret(name string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, s := range secretSettings {
+		if strings.Contains(normalized, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecrets returns a copy of settings where the values of the secret settings are masked
+func redactSecrets(settings map[string]interface{}) map[string]interface{} {
+	if settings == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		if sub, ok := v.(map[string]interface{}); ok {
+			out[k] = redactSecrets(sub)
+			continue
+		}
+		out[k] = v
+		if isSecret(k) {
+			out[k] = redacted
+		}
+	}
+	return out
+}
+
+// marshalRedacted returns the JSON encoding of v where the non-empty strings of the secret fields (private keys and
+// passwords of key pairs, hosts, host requests and user data, ...) are masked
+func marshalRedacted(v interface{}) (json.RawMessage, error) {
+	jsoned, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsoned))
+	decoder.UseNumber()
+	var generic interface{}
+	err = decoder.Decode(&generic)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(generic))
+}
+
+// redactValue masks in place the secret fields of a decoded JSON value
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			if s, ok := sub.(string); ok && s != "" && isSecret(k) {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactValue(sub)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return v
+}
+
+// callArguments returns the arguments of a call as they are recorded; hosts are identified by their ID, to avoid
+// serializing properties that may be locked by the caller
+func callArguments(args ...interface{}) []interface{} {
+	out := make([]interface{}, 0, len(args))
+	for _, a := range args {
+		if host, ok := a.(*resources.Host); ok && host != nil {
+			if host.ID != "" {
+				out = append(out, host.ID)
+			} else {
+				out = append(out, host.Name)
+			}
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// cassetteRecorder appends the interactions to a cassette file, one JSON document per line
+type cassetteRecorder struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// newCassetteRecorder creates (or truncates) the cassette file at path
+func newCassetteRecorder(path string) (*cassetteRecorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &cassetteRecorder{file: file}, nil
+}
+
+// record writes an interaction; results are the values (or pointers to the values) returned by the call
+func (cr *cassetteRecorder) record(call string, args []interface{}, err error, results ...interface{}) error {
+	interaction := Interaction{Call: call, Error: newRecordedError(err)}
+	if len(args) > 0 {
+		jsoned, err := marshalRedacted(args)
+		if err != nil {
+			return err
+		}
+		interaction.Arguments = jsoned
+	}
+	for _, r := range results {
+		jsoned, err := marshalRedacted(r)
+		if err != nil {
+			return err
+		}
+		interaction.Results = append(interaction.Results, jsoned)
+	}
+	line, jerr := json.Marshal(&interaction)
+	if jerr != nil {
+		return jerr
+	}
+
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	_, werr := cr.file.Write(append(line, '\n'))
+	return werr
+}
+
+// cassettePlayer serves the interactions of a cassette
+type cassettePlayer struct {
+	lock         sync.Mutex
+	interactions []*Interaction
+	consumed     []bool
+}
+
+// ReadCassette reads the interactions recorded in the cassette file at path
+func ReadCassette(path string) ([]*Interaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var interactions []*Interaction
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		interaction := &Interaction{}
+		err = json.Unmarshal(scanner.Bytes(), interaction)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interaction at line %d of cassette '%s': %v", line, path, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return interactions, nil
+}
+
+// find returns the interaction answering the call
+// The first interaction not served yet with the same name and the same arguments is used; if there is none, the first
+// interaction not served yet with the same name (arguments like generated passwords change from a run to another);
+// if every interaction with this name has been served, the last one served with the same arguments is served again
+// (polling a state, reading a setting, ...)
+func (cp *cassettePlayer) find(call string, args json.RawMessage) *Interaction {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	sameName, again := -1, -1
+	for i, interaction := range cp.interactions {
+		if interaction.Call != call {
+			continue
+		}
+		sameArgs := bytes.Equal(interaction.Arguments, args)
+		if cp.consumed[i] {
+			if sameArgs {
+				again = i
+			}
+			continue
+		}
+		if sameArgs {
+			cp.consumed[i] = true
+			return interaction
+		}
+		if sameName < 0 {
+			sameName = i
+		}
+	}
+	if sameName >= 0 {
+		cp.consumed[sameName] = true
+		return cp.interactions[sameName]
+	}
+	if again >= 0 {
+		return cp.interactions[again]
+	}
+	return nil
+}
+
+// play serves a call, decoding the recorded results in the values pointed by results
+func (cp *cassettePlayer) play(call string, args []interface{}, results ...interface{}) error {
+	// The arguments are redacted like the recorded ones to be compared with them
+	var jsoned json.RawMessage
+	if len(args) > 0 {
+		var err error
+		jsoned, err = marshalRedacted(args)
+		if err != nil {
+			return err
+		}
+	}
+	interaction := cp.find(call, jsoned)
+	if interaction == nil {
+		return scerr.NotFoundError(fmt.Sprintf("no interaction '%s' recorded in the cassette for arguments %s", call, string(jsoned)))
+	}
+	if len(interaction.Results) != len(results) {
+		return scerr.InconsistentError(fmt.Sprintf("interaction '%s' recorded with %d results, %d expected", call, len(interaction.Results), len(results)))
+	}
+	for i, r := range results {
+		err := decodeResult(interaction.Results[i], reflect.ValueOf(r).Elem())
+		if err != nil {
+			return fmt.Errorf("failed to decode result %d of interaction '%s': %v", i, call, err)
+		}
+	}
+	return interaction.Error.ToError()
+}
+
+type deserializer interface {
+	Deserialize([]byte) error
+}
+
+var deserializerType = reflect.TypeOf((*deserializer)(nil)).Elem()
+
+// decodeResult decodes raw in target; the resources having properties are decoded with their Deserialize() method,
+// which knows the module of their properties
+func decodeResult(raw json.RawMessage, target reflect.Value) error {
+	t := target.Type()
+	if bytes.Equal(raw, []byte("null")) {
+		target.Set(reflect.Zero(t))
+		return nil
+	}
+	switch {
+	case t.Kind() == reflect.Ptr && t.Implements(deserializerType):
+		value := reflect.New(t.Elem())
+		err := value.Interface().(deserializer).Deserialize(raw)
+		if err != nil {
+			return err
+		}
+		target.Set(value)
+		return nil
+	case t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(deserializerType):
+		return target.Addr().Interface().(deserializer).Deserialize(raw)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		var items []json.RawMessage
+		err := json.Unmarshal(raw, &items)
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			err = decodeResult(item, slice.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	default:
+		return json.Unmarshal(raw, target.Addr().Interface())
+	}
+}
+
+// toConfigMap converts the settings decoded from JSON to the types used by the providers in their Config
+// (JSON gives []interface{}, map[string]interface{} and float64 where the providers set []string, map[string]string and int)
+func toConfigMap(settings map[string]interface{}) providers.ConfigMap {
+	cfg := providers.ConfigMap{}
+	for k, v := range settings {
+		cfg.Set(k, toConfigValue(v))
+	}
+	return cfg
+}
+
+// toConfigValue ...
+func toConfigValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case float64:
+		if value == math.Trunc(value) {
+			return int(value)
+		}
+	case []interface{}:
+		strs := make([]string, 0, len(value))
+		for _, item := range value {
+			str, ok := item.(string)
+			if !ok {
+				return v
+			}
+			strs = append(strs, str)
+		}
+		return strs
+	case map[string]interface{}:
+		strs := make(map[string]string, len(value))
+		for key, item := range value {
+			str, ok := item.(string)
+			if !ok {
+				return v
+			}
+			strs[key] = str
+		}
+		return strs
+	}
+	return v
+}
+
+// configToMap returns the settings of a Config as a map, for them to be recorded
+func configToMap(cfg providers.Config) map[string]interface{} {
+	if cfgMap, ok := cfg.(providers.ConfigMap); ok {
+		return redactSecrets(cfgMap)
+	}
+	return nil
+}
@@ -95,6 +95,18 @@ func (w LoggedProvider) GetImage(id string) (*resources.Image, error) {
 	return w.InnerProvider.GetImage(id)
 }
 
+// CreateImage ...
+func (w LoggedProvider) CreateImage(hostID string, name string) (*resources.Image, error) {
+	defer w.prepare(w.trace("CreateImage"))
+	return w.InnerProvider.CreateImage(hostID, name)
+}
+
+// DeleteImage ...
+func (w LoggedProvider) DeleteImage(id string) error {
+	defer w.prepare(w.trace("DeleteImage"))
+	return w.InnerProvider.DeleteImage(id)
+}
+
 // GetTemplate ...
 func (w LoggedProvider) GetTemplate(id string) (*resources.HostTemplate, error) {
 	defer w.prepare(w.trace("GetTemplate"))
@@ -257,6 +269,12 @@ func (w LoggedProvider) ResizeHost(id string, request resources.SizingRequiremen
 	return w.InnerProvider.ResizeHost(id, request)
 }
 
+// UpdateHostTags ...
+func (w LoggedProvider) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	defer w.prepare(w.trace("UpdateHostTags"))
+	return w.InnerProvider.UpdateHostTags(id, set, unset)
+}
+
 // CreateVolume ...
 func (w LoggedProvider) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
 	defer w.prepare(w.trace("CreateVolume"))
@@ -281,6 +299,48 @@ func (w LoggedProvider) DeleteVolume(id string) error {
 	return w.InnerProvider.DeleteVolume(id)
 }
 
+// CreateVolumeSnapshot ...
+func (w LoggedProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	defer w.prepare(w.trace("CreateVolumeSnapshot"))
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w LoggedProvider) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	defer w.prepare(w.trace("GetVolumeSnapshot"))
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w LoggedProvider) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	defer w.prepare(w.trace("ListVolumeSnapshots"))
+	return w.InnerProvider.ListVolumeSnapshots(volumeID)
+}
+
+// DeleteVolumeSnapshot ...
+func (w LoggedProvider) DeleteVolumeSnapshot(id string) error {
+	defer w.prepare(w.trace("DeleteVolumeSnapshot"))
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
+// CreateVolumeFromSnapshot ...
+func (w LoggedProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	defer w.prepare(w.trace("CreateVolumeFromSnapshot"))
+	return w.InnerProvider.CreateVolumeFromSnapshot(snapshotID, request)
+}
+
+// ResizeVolume ...
+func (w LoggedProvider) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	defer w.prepare(w.trace("ResizeVolume"))
+	return w.InnerProvider.ResizeVolume(id, size)
+}
+
+// UpdateVolumeTags ...
+func (w LoggedProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	defer w.prepare(w.trace("UpdateVolumeTags"))
+	return w.InnerProvider.UpdateVolumeTags(id, set, unset)
+}
+
 // CreateVolumeAttachment ...
 func (w LoggedProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error) {
 	defer w.prepare(w.trace("CreateVolumeAttachment"))
@@ -305,6 +365,54 @@ func (w LoggedProvider) DeleteVolumeAttachment(serverID, id string) error {
 	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
 }
 
+// CreateSecurityGroup ...
+func (w LoggedProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	defer w.prepare(w.trace("CreateSecurityGroup"))
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w LoggedProvider) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	defer w.prepare(w.trace("InspectSecurityGroup"))
+	return w.InnerProvider.InspectSecurityGroup(id)
+}
+
+// ListSecurityGroups ...
+func (w LoggedProvider) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	defer w.prepare(w.trace("ListSecurityGroups"))
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// DeleteSecurityGroup ...
+func (w LoggedProvider) DeleteSecurityGroup(id string) error {
+	defer w.prepare(w.trace("DeleteSecurityGroup"))
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w LoggedProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	defer w.prepare(w.trace("AddRuleToSecurityGroup"))
+	return w.InnerProvider.AddRuleToSecurityGroup(id, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w LoggedProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	defer w.prepare(w.trace("DeleteRuleFromSecurityGroup"))
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(id, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w LoggedProvider) BindSecurityGroupToHost(id, hostID string) error {
+	defer w.prepare(w.trace("BindSecurityGroupToHost"))
+	return w.InnerProvider.BindSecurityGroupToHost(id, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w LoggedProvider) UnbindSecurityGroupFromHost(id, hostID string) error {
+	defer w.prepare(w.trace("UnbindSecurityGroupFromHost"))
+	return w.InnerProvider.UnbindSecurityGroupFromHost(id, hostID)
+}
+
 // GetCapabilities returns the capabilities of the provider
 func (w LoggedProvider) GetCapabilities() providers.Capabilities {
 	defer w.prepare(w.trace("Getcapabilities"))
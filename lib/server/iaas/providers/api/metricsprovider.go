@@ -0,0 +1,433 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+)
+
+// MetricsProvider is a Provider decorator counting the calls, the errors and the latency of the calls to the
+// provider it wraps, for the tenant using it
+type MetricsProvider struct {
+	InnerProvider Provider
+	Name          string
+	Tenant        string
+}
+
+// NewMetricsProvider ...
+func NewMetricsProvider(innerProvider Provider, name, tenant string) *MetricsProvider {
+	return &MetricsProvider{InnerProvider: innerProvider, Name: name, Tenant: tenant}
+}
+
+// observe records a call named call started at start; err may be nil for calls that cannot fail
+func (w MetricsProvider) observe(call string, start time.Time, err *error) {
+	var e error
+	if err != nil {
+		e = *err
+	}
+	metrics.ObserveProviderCall(w.Name, w.Tenant, call, start, e)
+}
+
+// ListImages ...
+func (w MetricsProvider) ListImages(all bool) (_ []resources.Image, err error) {
+	defer w.observe("ListImages", time.Now(), &err)
+	return w.InnerProvider.ListImages(all)
+}
+
+// ListTemplates ...
+func (w MetricsProvider) ListTemplates(all bool) (_ []resources.HostTemplate, err error) {
+	defer w.observe("ListTemplates", time.Now(), &err)
+	return w.InnerProvider.ListTemplates(all)
+}
+
+// GetAuthenticationOptions ...
+func (w MetricsProvider) GetAuthenticationOptions() (_ providers.Config, err error) {
+	defer w.observe("GetAuthenticationOptions", time.Now(), &err)
+	return w.InnerProvider.GetAuthenticationOptions()
+}
+
+// GetConfigurationOptions ...
+func (w MetricsProvider) GetConfigurationOptions() (_ providers.Config, err error) {
+	defer w.observe("GetConfigurationOptions", time.Now(), &err)
+	return w.InnerProvider.GetConfigurationOptions()
+}
+
+// GetName ...
+func (w MetricsProvider) GetName() string {
+	defer w.observe("GetName", time.Now(), nil)
+	return w.InnerProvider.GetName()
+}
+
+// ListAvailabilityZones ...
+func (w MetricsProvider) ListAvailabilityZones() (_ map[string]bool, err error) {
+	defer w.observe("ListAvailabilityZones", time.Now(), &err)
+	return w.InnerProvider.ListAvailabilityZones()
+}
+
+// ListRegions ...
+func (w MetricsProvider) ListRegions() (_ []string, err error) {
+	defer w.observe("ListRegions", time.Now(), &err)
+	return w.InnerProvider.ListRegions()
+}
+
+// GetImage ...
+func (w MetricsProvider) GetImage(id string) (_ *resources.Image, err error) {
+	defer w.observe("GetImage", time.Now(), &err)
+	return w.InnerProvider.GetImage(id)
+}
+
+// CreateImage ...
+func (w MetricsProvider) CreateImage(hostID string, name string) (_ *resources.Image, err error) {
+	defer w.observe("CreateImage", time.Now(), &err)
+	return w.InnerProvider.CreateImage(hostID, name)
+}
+
+// DeleteImage ...
+func (w MetricsProvider) DeleteImage(id string) (err error) {
+	defer w.observe("DeleteImage", time.Now(), &err)
+	return w.InnerProvider.DeleteImage(id)
+}
+
+// GetTemplate ...
+func (w MetricsProvider) GetTemplate(id string) (_ *resources.HostTemplate, err error) {
+	defer w.observe("GetTemplate", time.Now(), &err)
+	return w.InnerProvider.GetTemplate(id)
+}
+
+// CreateKeyPair ...
+func (w MetricsProvider) CreateKeyPair(name string) (_ *resources.KeyPair, err error) {
+	defer w.observe("CreateKeyPair", time.Now(), &err)
+	return w.InnerProvider.CreateKeyPair(name)
+}
+
+// GetKeyPair ...
+func (w MetricsProvider) GetKeyPair(id string) (_ *resources.KeyPair, err error) {
+	defer w.observe("GetKeyPair", time.Now(), &err)
+	return w.InnerProvider.GetKeyPair(id)
+}
+
+// ListKeyPairs ...
+func (w MetricsProvider) ListKeyPairs() (_ []resources.KeyPair, err error) {
+	defer w.observe("ListKeyPairs", time.Now(), &err)
+	return w.InnerProvider.ListKeyPairs()
+}
+
+// DeleteKeyPair ...
+func (w MetricsProvider) DeleteKeyPair(id string) (err error) {
+	defer w.observe("DeleteKeyPair", time.Now(), &err)
+	return w.InnerProvider.DeleteKeyPair(id)
+}
+
+// CreateNetwork ...
+func (w MetricsProvider) CreateNetwork(req resources.NetworkRequest) (_ *resources.Network, err error) {
+	defer w.observe("CreateNetwork", time.Now(), &err)
+	return w.InnerProvider.CreateNetwork(req)
+}
+
+// GetNetwork ...
+func (w MetricsProvider) GetNetwork(id string) (_ *resources.Network, err error) {
+	defer w.observe("GetNetwork", time.Now(), &err)
+	return w.InnerProvider.GetNetwork(id)
+}
+
+// GetNetworkByName ...
+func (w MetricsProvider) GetNetworkByName(name string) (_ *resources.Network, err error) {
+	defer w.observe("GetNetworkByName", time.Now(), &err)
+	return w.InnerProvider.GetNetworkByName(name)
+}
+
+// ListNetworks ...
+func (w MetricsProvider) ListNetworks() (_ []*resources.Network, err error) {
+	defer w.observe("ListNetworks", time.Now(), &err)
+	return w.InnerProvider.ListNetworks()
+}
+
+// DeleteNetwork ...
+func (w MetricsProvider) DeleteNetwork(id string) (err error) {
+	defer w.observe("DeleteNetwork", time.Now(), &err)
+	return w.InnerProvider.DeleteNetwork(id)
+}
+
+// CreateGateway ...
+func (w MetricsProvider) CreateGateway(req resources.GatewayRequest) (_ *resources.Host, _ *userdata.Content, err error) {
+	defer w.observe("CreateGateway", time.Now(), &err)
+	return w.InnerProvider.CreateGateway(req)
+}
+
+// DeleteGateway ...
+func (w MetricsProvider) DeleteGateway(networkID string) (err error) {
+	defer w.observe("DeleteGateway", time.Now(), &err)
+	return w.InnerProvider.DeleteGateway(networkID)
+}
+
+// CreateVIP ...
+func (w MetricsProvider) CreateVIP(networkID string, description string) (_ *resources.VirtualIP, err error) {
+	defer w.observe("CreateVIP", time.Now(), &err)
+	return w.InnerProvider.CreateVIP(networkID, description)
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (w MetricsProvider) AddPublicIPToVIP(vip *resources.VirtualIP) (err error) {
+	defer w.observe("AddPublicIPToVIP", time.Now(), &err)
+	return w.InnerProvider.AddPublicIPToVIP(vip)
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (w MetricsProvider) BindHostToVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer w.observe("BindHostToVIP", time.Now(), &err)
+	return w.InnerProvider.BindHostToVIP(vip, hostID)
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (w MetricsProvider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer w.observe("UnbindHostFromVIP", time.Now(), &err)
+	return w.InnerProvider.UnbindHostFromVIP(vip, hostID)
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (w MetricsProvider) DeleteVIP(vip *resources.VirtualIP) (err error) {
+	defer w.observe("DeleteVIP", time.Now(), &err)
+	return w.InnerProvider.DeleteVIP(vip)
+}
+
+// CreateHost ...
+func (w MetricsProvider) CreateHost(request resources.HostRequest) (_ *resources.Host, _ *userdata.Content, err error) {
+	defer w.observe("CreateHost", time.Now(), &err)
+	return w.InnerProvider.CreateHost(request)
+}
+
+// InspectHost ...
+func (w MetricsProvider) InspectHost(something interface{}) (_ *resources.Host, err error) {
+	defer w.observe("InspectHost", time.Now(), &err)
+	return w.InnerProvider.InspectHost(something)
+}
+
+// GetHostByName ...
+func (w MetricsProvider) GetHostByName(name string) (_ *resources.Host, err error) {
+	defer w.observe("GetHostByName", time.Now(), &err)
+	return w.InnerProvider.GetHostByName(name)
+}
+
+// GetHostState ...
+func (w MetricsProvider) GetHostState(something interface{}) (_ hoststate.Enum, err error) {
+	defer w.observe("GetHostState", time.Now(), &err)
+	return w.InnerProvider.GetHostState(something)
+}
+
+// ListHosts ...
+func (w MetricsProvider) ListHosts() (_ []*resources.Host, err error) {
+	defer w.observe("ListHosts", time.Now(), &err)
+	return w.InnerProvider.ListHosts()
+}
+
+// DeleteHost ...
+func (w MetricsProvider) DeleteHost(id string) (err error) {
+	defer w.observe("DeleteHost", time.Now(), &err)
+	return w.InnerProvider.DeleteHost(id)
+}
+
+// StopHost ...
+func (w MetricsProvider) StopHost(id string) (err error) {
+	defer w.observe("StopHost", time.Now(), &err)
+	return w.InnerProvider.StopHost(id)
+}
+
+// StartHost ...
+func (w MetricsProvider) StartHost(id string) (err error) {
+	defer w.observe("StartHost", time.Now(), &err)
+	return w.InnerProvider.StartHost(id)
+}
+
+// RebootHost ...
+func (w MetricsProvider) RebootHost(id string) (err error) {
+	defer w.observe("RebootHost", time.Now(), &err)
+	return w.InnerProvider.RebootHost(id)
+}
+
+// ResizeHost ...
+func (w MetricsProvider) ResizeHost(id string, request resources.SizingRequirements) (_ *resources.Host, err error) {
+	defer w.observe("ResizeHost", time.Now(), &err)
+	return w.InnerProvider.ResizeHost(id, request)
+}
+
+// UpdateHostTags ...
+func (w MetricsProvider) UpdateHostTags(id string, set map[string]string, unset []string) (err error) {
+	defer w.observe("UpdateHostTags", time.Now(), &err)
+	return w.InnerProvider.UpdateHostTags(id, set, unset)
+}
+
+// CreateVolume ...
+func (w MetricsProvider) CreateVolume(request resources.VolumeRequest) (_ *resources.Volume, err error) {
+	defer w.observe("CreateVolume", time.Now(), &err)
+	return w.InnerProvider.CreateVolume(request)
+}
+
+// GetVolume ...
+func (w MetricsProvider) GetVolume(id string) (_ *resources.Volume, err error) {
+	defer w.observe("GetVolume", time.Now(), &err)
+	return w.InnerProvider.GetVolume(id)
+}
+
+// ListVolumes ...
+func (w MetricsProvider) ListVolumes() (_ []resources.Volume, err error) {
+	defer w.observe("ListVolumes", time.Now(), &err)
+	return w.InnerProvider.ListVolumes()
+}
+
+// DeleteVolume ...
+func (w MetricsProvider) DeleteVolume(id string) (err error) {
+	defer w.observe("DeleteVolume", time.Now(), &err)
+	return w.InnerProvider.DeleteVolume(id)
+}
+
+// CreateVolumeSnapshot ...
+func (w MetricsProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (_ *resources.VolumeSnapshot, err error) {
+	defer w.observe("CreateVolumeSnapshot", time.Now(), &err)
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w MetricsProvider) GetVolumeSnapshot(id string) (_ *resources.VolumeSnapshot, err error) {
+	defer w.observe("GetVolumeSnapshot", time.Now(), &err)
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w MetricsProvider) ListVolumeSnapshots(volumeID string) (_ []resources.VolumeSnapshot, err error) {
+	defer w.observe("ListVolumeSnapshots", time.Now(), &err)
+	return w.InnerProvider.ListVolumeSnapshots(volumeID)
+}
+
+// DeleteVolumeSnapshot ...
+func (w MetricsProvider) DeleteVolumeSnapshot(id string) (err error) {
+	defer w.observe("DeleteVolumeSnapshot", time.Now(), &err)
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
+// CreateVolumeFromSnapshot ...
+func (w MetricsProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (_ *resources.Volume, err error) {
+	defer w.observe("CreateVolumeFromSnapshot", time.Now(), &err)
+	return w.InnerProvider.CreateVolumeFromSnapshot(snapshotID, request)
+}
+
+// ResizeVolume ...
+func (w MetricsProvider) ResizeVolume(id string, size int) (_ *resources.Volume, err error) {
+	defer w.observe("ResizeVolume", time.Now(), &err)
+	return w.InnerProvider.ResizeVolume(id, size)
+}
+
+// UpdateVolumeTags ...
+func (w MetricsProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) (err error) {
+	defer w.observe("UpdateVolumeTags", time.Now(), &err)
+	return w.InnerProvider.UpdateVolumeTags(id, set, unset)
+}
+
+// CreateVolumeAttachment ...
+func (w MetricsProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (_ string, err error) {
+	defer w.observe("CreateVolumeAttachment", time.Now(), &err)
+	return w.InnerProvider.CreateVolumeAttachment(request)
+}
+
+// GetVolumeAttachment ...
+func (w MetricsProvider) GetVolumeAttachment(serverID, id string) (_ *resources.VolumeAttachment, err error) {
+	defer w.observe("GetVolumeAttachment", time.Now(), &err)
+	return w.InnerProvider.GetVolumeAttachment(serverID, id)
+}
+
+// ListVolumeAttachments ...
+func (w MetricsProvider) ListVolumeAttachments(serverID string) (_ []resources.VolumeAttachment, err error) {
+	defer w.observe("ListVolumeAttachments", time.Now(), &err)
+	return w.InnerProvider.ListVolumeAttachments(serverID)
+}
+
+// DeleteVolumeAttachment ...
+func (w MetricsProvider) DeleteVolumeAttachment(serverID, id string) (err error) {
+	defer w.observe("DeleteVolumeAttachment", time.Now(), &err)
+	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
+}
+
+// CreateSecurityGroup ...
+func (w MetricsProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (_ *resources.SecurityGroup, err error) {
+	defer w.observe("CreateSecurityGroup", time.Now(), &err)
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w MetricsProvider) InspectSecurityGroup(id string) (_ *resources.SecurityGroup, err error) {
+	defer w.observe("InspectSecurityGroup", time.Now(), &err)
+	return w.InnerProvider.InspectSecurityGroup(id)
+}
+
+// ListSecurityGroups ...
+func (w MetricsProvider) ListSecurityGroups() (_ []*resources.SecurityGroup, err error) {
+	defer w.observe("ListSecurityGroups", time.Now(), &err)
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// DeleteSecurityGroup ...
+func (w MetricsProvider) DeleteSecurityGroup(id string) (err error) {
+	defer w.observe("DeleteSecurityGroup", time.Now(), &err)
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w MetricsProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (_ *resources.SecurityGroup, err error) {
+	defer w.observe("AddRuleToSecurityGroup", time.Now(), &err)
+	return w.InnerProvider.AddRuleToSecurityGroup(id, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w MetricsProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (_ *resources.SecurityGroup, err error) {
+	defer w.observe("DeleteRuleFromSecurityGroup", time.Now(), &err)
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(id, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w MetricsProvider) BindSecurityGroupToHost(id, hostID string) (err error) {
+	defer w.observe("BindSecurityGroupToHost", time.Now(), &err)
+	return w.InnerProvider.BindSecurityGroupToHost(id, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w MetricsProvider) UnbindSecurityGroupFromHost(id, hostID string) (err error) {
+	defer w.observe("UnbindSecurityGroupFromHost", time.Now(), &err)
+	return w.InnerProvider.UnbindSecurityGroupFromHost(id, hostID)
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (w MetricsProvider) GetCapabilities() providers.Capabilities {
+	defer w.observe("GetCapabilities", time.Now(), nil)
+	return w.InnerProvider.GetCapabilities()
+}
+
+// Build ...
+func (w MetricsProvider) Build(something map[string]interface{}) (p Provider, err error) {
+	defer w.observe("Build", time.Now(), &err)
+	return w.InnerProvider.Build(something)
+}
+
+// GetTenantParameters ...
+func (w MetricsProvider) GetTenantParameters() map[string]interface{} {
+	defer w.observe("GetTenantParameters", time.Now(), nil)
+	return w.InnerProvider.GetTenantParameters()
+}
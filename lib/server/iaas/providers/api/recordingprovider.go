@@ -0,0 +1,455 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+)
+
+// RecordingProvider is a Provider decorator writing every call to the provider it wraps, with its arguments, its
+// results and its error, in a cassette file; the cassette can then be served offline by a ReplayProvider
+// The secrets in arguments and results (private keys, passwords) are masked, they are replayed masked
+type RecordingProvider struct {
+	InnerProvider Provider
+	Name          string
+	recorder      *cassetteRecorder
+}
+
+// NewRecordingProvider creates the cassette file at path and records in it the settings of the provider
+// The values of the settings looking like secrets (passwords, tokens, ...) are not recorded
+func NewRecordingProvider(innerProvider Provider, name, path string) (*RecordingProvider, error) {
+	recorder, err := newCassetteRecorder(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recorder.record("GetName", nil, nil, innerProvider.GetName())
+	if err != nil {
+		return nil, err
+	}
+	err = recorder.record("GetCapabilities", nil, nil, innerProvider.GetCapabilities())
+	if err != nil {
+		return nil, err
+	}
+	err = recorder.record("GetTenantParameters", nil, nil, redactSecrets(innerProvider.GetTenantParameters()))
+	if err != nil {
+		return nil, err
+	}
+	authOpts, authErr := innerProvider.GetAuthenticationOptions()
+	err = recorder.record("GetAuthenticationOptions", nil, authErr, configToMap(authOpts))
+	if err != nil {
+		return nil, err
+	}
+	cfgOpts, cfgErr := innerProvider.GetConfigurationOptions()
+	err = recorder.record("GetConfigurationOptions", nil, cfgErr, configToMap(cfgOpts))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordingProvider{InnerProvider: innerProvider, Name: name, recorder: recorder}, nil
+}
+
+// record writes in the cassette the call named call; err and results point to the values returned by the call
+func (w RecordingProvider) record(call string, args []interface{}, err *error, results ...interface{}) {
+	recErr := w.recorder.record(call, args, *err, results...)
+	if recErr != nil {
+		logrus.Warnf("failed to record call '%s' to provider '%s': %v", call, w.Name, recErr)
+	}
+}
+
+// Build ...
+func (w RecordingProvider) Build(something map[string]interface{}) (Provider, error) {
+	return w.InnerProvider.Build(something)
+}
+
+// GetAuthenticationOptions ...
+func (w RecordingProvider) GetAuthenticationOptions() (providers.Config, error) {
+	return w.InnerProvider.GetAuthenticationOptions()
+}
+
+// GetConfigurationOptions ...
+func (w RecordingProvider) GetConfigurationOptions() (providers.Config, error) {
+	return w.InnerProvider.GetConfigurationOptions()
+}
+
+// GetName ...
+func (w RecordingProvider) GetName() string {
+	return w.InnerProvider.GetName()
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (w RecordingProvider) GetCapabilities() providers.Capabilities {
+	return w.InnerProvider.GetCapabilities()
+}
+
+// GetTenantParameters ...
+func (w RecordingProvider) GetTenantParameters() map[string]interface{} {
+	return w.InnerProvider.GetTenantParameters()
+}
+
+// ListImages ...
+func (w RecordingProvider) ListImages(all bool) (images []resources.Image, err error) {
+	defer w.record("ListImages", callArguments(all), &err, &images)
+	return w.InnerProvider.ListImages(all)
+}
+
+// ListTemplates ...
+func (w RecordingProvider) ListTemplates(all bool) (templates []resources.HostTemplate, err error) {
+	defer w.record("ListTemplates", callArguments(all), &err, &templates)
+	return w.InnerProvider.ListTemplates(all)
+}
+
+// ListAvailabilityZones ...
+func (w RecordingProvider) ListAvailabilityZones() (zones map[string]bool, err error) {
+	defer w.record("ListAvailabilityZones", callArguments(), &err, &zones)
+	return w.InnerProvider.ListAvailabilityZones()
+}
+
+// ListRegions ...
+func (w RecordingProvider) ListRegions() (regions []string, err error) {
+	defer w.record("ListRegions", callArguments(), &err, &regions)
+	return w.InnerProvider.ListRegions()
+}
+
+// GetImage ...
+func (w RecordingProvider) GetImage(id string) (image *resources.Image, err error) {
+	defer w.record("GetImage", callArguments(id), &err, &image)
+	return w.InnerProvider.GetImage(id)
+}
+
+// CreateImage ...
+func (w RecordingProvider) CreateImage(hostID string, name string) (image *resources.Image, err error) {
+	defer w.record("CreateImage", callArguments(hostID, name), &err, &image)
+	return w.InnerProvider.CreateImage(hostID, name)
+}
+
+// DeleteImage ...
+func (w RecordingProvider) DeleteImage(id string) (err error) {
+	defer w.record("DeleteImage", callArguments(id), &err)
+	return w.InnerProvider.DeleteImage(id)
+}
+
+// GetTemplate ...
+func (w RecordingProvider) GetTemplate(id string) (template *resources.HostTemplate, err error) {
+	defer w.record("GetTemplate", callArguments(id), &err, &template)
+	return w.InnerProvider.GetTemplate(id)
+}
+
+// CreateKeyPair ...
+func (w RecordingProvider) CreateKeyPair(name string) (keypair *resources.KeyPair, err error) {
+	defer w.record("CreateKeyPair", callArguments(name), &err, &keypair)
+	return w.InnerProvider.CreateKeyPair(name)
+}
+
+// GetKeyPair ...
+func (w RecordingProvider) GetKeyPair(id string) (keypair *resources.KeyPair, err error) {
+	defer w.record("GetKeyPair", callArguments(id), &err, &keypair)
+	return w.InnerProvider.GetKeyPair(id)
+}
+
+// ListKeyPairs ...
+func (w RecordingProvider) ListKeyPairs() (keypairs []resources.KeyPair, err error) {
+	defer w.record("ListKeyPairs", callArguments(), &err, &keypairs)
+	return w.InnerProvider.ListKeyPairs()
+}
+
+// DeleteKeyPair ...
+func (w RecordingProvider) DeleteKeyPair(id string) (err error) {
+	defer w.record("DeleteKeyPair", callArguments(id), &err)
+	return w.InnerProvider.DeleteKeyPair(id)
+}
+
+// CreateNetwork ...
+func (w RecordingProvider) CreateNetwork(req resources.NetworkRequest) (network *resources.Network, err error) {
+	defer w.record("CreateNetwork", callArguments(req), &err, &network)
+	return w.InnerProvider.CreateNetwork(req)
+}
+
+// GetNetwork ...
+func (w RecordingProvider) GetNetwork(id string) (network *resources.Network, err error) {
+	defer w.record("GetNetwork", callArguments(id), &err, &network)
+	return w.InnerProvider.GetNetwork(id)
+}
+
+// GetNetworkByName ...
+func (w RecordingProvider) GetNetworkByName(name string) (network *resources.Network, err error) {
+	defer w.record("GetNetworkByName", callArguments(name), &err, &network)
+	return w.InnerProvider.GetNetworkByName(name)
+}
+
+// ListNetworks ...
+func (w RecordingProvider) ListNetworks() (networks []*resources.Network, err error) {
+	defer w.record("ListNetworks", callArguments(), &err, &networks)
+	return w.InnerProvider.ListNetworks()
+}
+
+// DeleteNetwork ...
+func (w RecordingProvider) DeleteNetwork(id string) (err error) {
+	defer w.record("DeleteNetwork", callArguments(id), &err)
+	return w.InnerProvider.DeleteNetwork(id)
+}
+
+// CreateGateway ...
+func (w RecordingProvider) CreateGateway(req resources.GatewayRequest) (host *resources.Host, content *userdata.Content, err error) {
+	defer w.record("CreateGateway", callArguments(req), &err, &host, &content)
+	return w.InnerProvider.CreateGateway(req)
+}
+
+// DeleteGateway ...
+func (w RecordingProvider) DeleteGateway(networkID string) (err error) {
+	defer w.record("DeleteGateway", callArguments(networkID), &err)
+	return w.InnerProvider.DeleteGateway(networkID)
+}
+
+// CreateVIP ...
+func (w RecordingProvider) CreateVIP(networkID string, description string) (vip *resources.VirtualIP, err error) {
+	defer w.record("CreateVIP", callArguments(networkID, description), &err, &vip)
+	return w.InnerProvider.CreateVIP(networkID, description)
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (w RecordingProvider) AddPublicIPToVIP(vip *resources.VirtualIP) (err error) {
+	defer w.record("AddPublicIPToVIP", callArguments(vip), &err)
+	return w.InnerProvider.AddPublicIPToVIP(vip)
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (w RecordingProvider) BindHostToVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer w.record("BindHostToVIP", callArguments(vip, hostID), &err)
+	return w.InnerProvider.BindHostToVIP(vip, hostID)
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (w RecordingProvider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer w.record("UnbindHostFromVIP", callArguments(vip, hostID), &err)
+	return w.InnerProvider.UnbindHostFromVIP(vip, hostID)
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (w RecordingProvider) DeleteVIP(vip *resources.VirtualIP) (err error) {
+	defer w.record("DeleteVIP", callArguments(vip), &err)
+	return w.InnerProvider.DeleteVIP(vip)
+}
+
+// CreateHost ...
+func (w RecordingProvider) CreateHost(request resources.HostRequest) (host *resources.Host, content *userdata.Content, err error) {
+	defer w.record("CreateHost", callArguments(request), &err, &host, &content)
+	return w.InnerProvider.CreateHost(request)
+}
+
+// InspectHost ...
+func (w RecordingProvider) InspectHost(something interface{}) (host *resources.Host, err error) {
+	defer w.record("InspectHost", callArguments(something), &err, &host)
+	return w.InnerProvider.InspectHost(something)
+}
+
+// GetHostByName ...
+func (w RecordingProvider) GetHostByName(name string) (host *resources.Host, err error) {
+	defer w.record("GetHostByName", callArguments(name), &err, &host)
+	return w.InnerProvider.GetHostByName(name)
+}
+
+// GetHostState ...
+func (w RecordingProvider) GetHostState(something interface{}) (state hoststate.Enum, err error) {
+	defer w.record("GetHostState", callArguments(something), &err, &state)
+	return w.InnerProvider.GetHostState(something)
+}
+
+// ListHosts ...
+func (w RecordingProvider) ListHosts() (hosts []*resources.Host, err error) {
+	defer w.record("ListHosts", callArguments(), &err, &hosts)
+	return w.InnerProvider.ListHosts()
+}
+
+// DeleteHost ...
+func (w RecordingProvider) DeleteHost(id string) (err error) {
+	defer w.record("DeleteHost", callArguments(id), &err)
+	return w.InnerProvider.DeleteHost(id)
+}
+
+// StopHost ...
+func (w RecordingProvider) StopHost(id string) (err error) {
+	defer w.record("StopHost", callArguments(id), &err)
+	return w.InnerProvider.StopHost(id)
+}
+
+// StartHost ...
+func (w RecordingProvider) StartHost(id string) (err error) {
+	defer w.record("StartHost", callArguments(id), &err)
+	return w.InnerProvider.StartHost(id)
+}
+
+// RebootHost ...
+func (w RecordingProvider) RebootHost(id string) (err error) {
+	defer w.record("RebootHost", callArguments(id), &err)
+	return w.InnerProvider.RebootHost(id)
+}
+
+// ResizeHost ...
+func (w RecordingProvider) ResizeHost(id string, request resources.SizingRequirements) (host *resources.Host, err error) {
+	defer w.record("ResizeHost", callArguments(id, request), &err, &host)
+	return w.InnerProvider.ResizeHost(id, request)
+}
+
+// UpdateHostTags ...
+func (w RecordingProvider) UpdateHostTags(id string, set map[string]string, unset []string) (err error) {
+	defer w.record("UpdateHostTags", callArguments(id, set, unset), &err)
+	return w.InnerProvider.UpdateHostTags(id, set, unset)
+}
+
+// CreateVolume ...
+func (w RecordingProvider) CreateVolume(request resources.VolumeRequest) (volume *resources.Volume, err error) {
+	defer w.record("CreateVolume", callArguments(request), &err, &volume)
+	return w.InnerProvider.CreateVolume(request)
+}
+
+// GetVolume ...
+func (w RecordingProvider) GetVolume(id string) (volume *resources.Volume, err error) {
+	defer w.record("GetVolume", callArguments(id), &err, &volume)
+	return w.InnerProvider.GetVolume(id)
+}
+
+// ListVolumes ...
+func (w RecordingProvider) ListVolumes() (volumes []resources.Volume, err error) {
+	defer w.record("ListVolumes", callArguments(), &err, &volumes)
+	return w.InnerProvider.ListVolumes()
+}
+
+// DeleteVolume ...
+func (w RecordingProvider) DeleteVolume(id string) (err error) {
+	defer w.record("DeleteVolume", callArguments(id), &err)
+	return w.InnerProvider.DeleteVolume(id)
+}
+
+// CreateVolumeSnapshot ...
+func (w RecordingProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (snapshot *resources.VolumeSnapshot, err error) {
+	defer w.record("CreateVolumeSnapshot", callArguments(request), &err, &snapshot)
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w RecordingProvider) GetVolumeSnapshot(id string) (snapshot *resources.VolumeSnapshot, err error) {
+	defer w.record("GetVolumeSnapshot", callArguments(id), &err, &snapshot)
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w RecordingProvider) ListVolumeSnapshots(volumeID string) (snapshots []resources.VolumeSnapshot, err error) {
+	defer w.record("ListVolumeSnapshots", callArguments(volumeID), &err, &snapshots)
+	return w.InnerProvider.ListVolumeSnapshots(volumeID)
+}
+
+// DeleteVolumeSnapshot ...
+func (w RecordingProvider) DeleteVolumeSnapshot(id string) (err error) {
+	defer w.record("DeleteVolumeSnapshot", callArguments(id), &err)
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
+// CreateVolumeFromSnapshot ...
+func (w RecordingProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (volume *resources.Volume, err error) {
+	defer w.record("CreateVolumeFromSnapshot", callArguments(snapshotID, request), &err, &volume)
+	return w.InnerProvider.CreateVolumeFromSnapshot(snapshotID, request)
+}
+
+// ResizeVolume ...
+func (w RecordingProvider) ResizeVolume(id string, size int) (volume *resources.Volume, err error) {
+	defer w.record("ResizeVolume", callArguments(id, size), &err, &volume)
+	return w.InnerProvider.ResizeVolume(id, size)
+}
+
+// UpdateVolumeTags ...
+func (w RecordingProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) (err error) {
+	defer w.record("UpdateVolumeTags", callArguments(id, set, unset), &err)
+	return w.InnerProvider.UpdateVolumeTags(id, set, unset)
+}
+
+// CreateVolumeAttachment ...
+func (w RecordingProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (id string, err error) {
+	defer w.record("CreateVolumeAttachment", callArguments(request), &err, &id)
+	return w.InnerProvider.CreateVolumeAttachment(request)
+}
+
+// GetVolumeAttachment ...
+func (w RecordingProvider) GetVolumeAttachment(serverID, id string) (attachment *resources.VolumeAttachment, err error) {
+	defer w.record("GetVolumeAttachment", callArguments(serverID, id), &err, &attachment)
+	return w.InnerProvider.GetVolumeAttachment(serverID, id)
+}
+
+// ListVolumeAttachments ...
+func (w RecordingProvider) ListVolumeAttachments(serverID string) (attachments []resources.VolumeAttachment, err error) {
+	defer w.record("ListVolumeAttachments", callArguments(serverID), &err, &attachments)
+	return w.InnerProvider.ListVolumeAttachments(serverID)
+}
+
+// DeleteVolumeAttachment ...
+func (w RecordingProvider) DeleteVolumeAttachment(serverID, id string) (err error) {
+	defer w.record("DeleteVolumeAttachment", callArguments(serverID, id), &err)
+	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
+}
+
+// CreateSecurityGroup ...
+func (w RecordingProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (group *resources.SecurityGroup, err error) {
+	defer w.record("CreateSecurityGroup", callArguments(req), &err, &group)
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w RecordingProvider) InspectSecurityGroup(id string) (group *resources.SecurityGroup, err error) {
+	defer w.record("InspectSecurityGroup", callArguments(id), &err, &group)
+	return w.InnerProvider.InspectSecurityGroup(id)
+}
+
+// ListSecurityGroups ...
+func (w RecordingProvider) ListSecurityGroups() (groups []*resources.SecurityGroup, err error) {
+	defer w.record("ListSecurityGroups", callArguments(), &err, &groups)
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// DeleteSecurityGroup ...
+func (w RecordingProvider) DeleteSecurityGroup(id string) (err error) {
+	defer w.record("DeleteSecurityGroup", callArguments(id), &err)
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w RecordingProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (group *resources.SecurityGroup, err error) {
+	defer w.record("AddRuleToSecurityGroup", callArguments(id, rule), &err, &group)
+	return w.InnerProvider.AddRuleToSecurityGroup(id, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w RecordingProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (group *resources.SecurityGroup, err error) {
+	defer w.record("DeleteRuleFromSecurityGroup", callArguments(id, ruleID), &err, &group)
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(id, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w RecordingProvider) BindSecurityGroupToHost(id, hostID string) (err error) {
+	defer w.record("BindSecurityGroupToHost", callArguments(id, hostID), &err)
+	return w.InnerProvider.BindSecurityGroupToHost(id, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w RecordingProvider) UnbindSecurityGroupFromHost(id, hostID string) (err error) {
+	defer w.record("UnbindSecurityGroupFromHost", callArguments(id, hostID), &err)
+	return w.InnerProvider.UnbindSecurityGroupFromHost(id, hostID)
+}
@@ -0,0 +1,429 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+)
+
+// ReplayProvider is a Provider serving offline the calls recorded in a cassette by a RecordingProvider
+// A call is answered by the first interaction not served yet with the same name and the same arguments (or with
+// the same name only, if no interaction has the same arguments); when every interaction with this name has been
+// served, the last one with the same arguments is served again. An error ErrNotFound is returned if nothing matches.
+type ReplayProvider struct {
+	Name             string
+	capabilities     providers.Capabilities
+	tenantParameters map[string]interface{}
+	player           *cassettePlayer
+}
+
+// NewReplayProvider reads the cassette file at path
+func NewReplayProvider(path string) (*ReplayProvider, error) {
+	interactions, err := ReadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &ReplayProvider{
+		player: &cassettePlayer{interactions: interactions, consumed: make([]bool, len(interactions))},
+	}
+	err = w.player.play("GetName", nil, &w.Name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassette '%s': %v", path, err)
+	}
+	err = w.player.play("GetCapabilities", nil, &w.capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassette '%s': %v", path, err)
+	}
+	err = w.player.play("GetTenantParameters", nil, &w.tenantParameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassette '%s': %v", path, err)
+	}
+	return w, nil
+}
+
+// Build returns the ReplayProvider itself, the cassette being the only configuration needed
+func (w ReplayProvider) Build(something map[string]interface{}) (Provider, error) {
+	return w, nil
+}
+
+// GetAuthenticationOptions ...
+func (w ReplayProvider) GetAuthenticationOptions() (providers.Config, error) {
+	var settings map[string]interface{}
+	err := w.player.play("GetAuthenticationOptions", nil, &settings)
+	if err != nil {
+		return nil, err
+	}
+	return toConfigMap(settings), nil
+}
+
+// GetConfigurationOptions ...
+func (w ReplayProvider) GetConfigurationOptions() (providers.Config, error) {
+	var settings map[string]interface{}
+	err := w.player.play("GetConfigurationOptions", nil, &settings)
+	if err != nil {
+		return nil, err
+	}
+	return toConfigMap(settings), nil
+}
+
+// GetName ...
+func (w ReplayProvider) GetName() string {
+	return w.Name
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (w ReplayProvider) GetCapabilities() providers.Capabilities {
+	return w.capabilities
+}
+
+// GetTenantParameters ...
+func (w ReplayProvider) GetTenantParameters() map[string]interface{} {
+	return w.tenantParameters
+}
+
+// ListImages ...
+func (w ReplayProvider) ListImages(all bool) (images []resources.Image, err error) {
+	err = w.player.play("ListImages", callArguments(all), &images)
+	return images, err
+}
+
+// ListTemplates ...
+func (w ReplayProvider) ListTemplates(all bool) (templates []resources.HostTemplate, err error) {
+	err = w.player.play("ListTemplates", callArguments(all), &templates)
+	return templates, err
+}
+
+// ListAvailabilityZones ...
+func (w ReplayProvider) ListAvailabilityZones() (zones map[string]bool, err error) {
+	err = w.player.play("ListAvailabilityZones", callArguments(), &zones)
+	return zones, err
+}
+
+// ListRegions ...
+func (w ReplayProvider) ListRegions() (regions []string, err error) {
+	err = w.player.play("ListRegions", callArguments(), &regions)
+	return regions, err
+}
+
+// GetImage ...
+func (w ReplayProvider) GetImage(id string) (image *resources.Image, err error) {
+	err = w.player.play("GetImage", callArguments(id), &image)
+	return image, err
+}
+
+// CreateImage ...
+func (w ReplayProvider) CreateImage(hostID string, name string) (image *resources.Image, err error) {
+	err = w.player.play("CreateImage", callArguments(hostID, name), &image)
+	return image, err
+}
+
+// DeleteImage ...
+func (w ReplayProvider) DeleteImage(id string) (err error) {
+	return w.player.play("DeleteImage", callArguments(id))
+}
+
+// GetTemplate ...
+func (w ReplayProvider) GetTemplate(id string) (template *resources.HostTemplate, err error) {
+	err = w.player.play("GetTemplate", callArguments(id), &template)
+	return template, err
+}
+
+// CreateKeyPair ...
+func (w ReplayProvider) CreateKeyPair(name string) (keypair *resources.KeyPair, err error) {
+	err = w.player.play("CreateKeyPair", callArguments(name), &keypair)
+	return keypair, err
+}
+
+// GetKeyPair ...
+func (w ReplayProvider) GetKeyPair(id string) (keypair *resources.KeyPair, err error) {
+	err = w.player.play("GetKeyPair", callArguments(id), &keypair)
+	return keypair, err
+}
+
+// ListKeyPairs ...
+func (w ReplayProvider) ListKeyPairs() (keypairs []resources.KeyPair, err error) {
+	err = w.player.play("ListKeyPairs", callArguments(), &keypairs)
+	return keypairs, err
+}
+
+// DeleteKeyPair ...
+func (w ReplayProvider) DeleteKeyPair(id string) (err error) {
+	return w.player.play("DeleteKeyPair", callArguments(id))
+}
+
+// CreateNetwork ...
+func (w ReplayProvider) CreateNetwork(req resources.NetworkRequest) (network *resources.Network, err error) {
+	err = w.player.play("CreateNetwork", callArguments(req), &network)
+	return network, err
+}
+
+// GetNetwork ...
+func (w ReplayProvider) GetNetwork(id string) (network *resources.Network, err error) {
+	err = w.player.play("GetNetwork", callArguments(id), &network)
+	return network, err
+}
+
+// GetNetworkByName ...
+func (w ReplayProvider) GetNetworkByName(name string) (network *resources.Network, err error) {
+	err = w.player.play("GetNetworkByName", callArguments(name), &network)
+	return network, err
+}
+
+// ListNetworks ...
+func (w ReplayProvider) ListNetworks() (networks []*resources.Network, err error) {
+	err = w.player.play("ListNetworks", callArguments(), &networks)
+	return networks, err
+}
+
+// DeleteNetwork ...
+func (w ReplayProvider) DeleteNetwork(id string) (err error) {
+	return w.player.play("DeleteNetwork", callArguments(id))
+}
+
+// CreateGateway ...
+func (w ReplayProvider) CreateGateway(req resources.GatewayRequest) (host *resources.Host, content *userdata.Content, err error) {
+	err = w.player.play("CreateGateway", callArguments(req), &host, &content)
+	return host, content, err
+}
+
+// DeleteGateway ...
+func (w ReplayProvider) DeleteGateway(networkID string) (err error) {
+	return w.player.play("DeleteGateway", callArguments(networkID))
+}
+
+// CreateVIP ...
+func (w ReplayProvider) CreateVIP(networkID string, description string) (vip *resources.VirtualIP, err error) {
+	err = w.player.play("CreateVIP", callArguments(networkID, description), &vip)
+	return vip, err
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (w ReplayProvider) AddPublicIPToVIP(vip *resources.VirtualIP) (err error) {
+	return w.player.play("AddPublicIPToVIP", callArguments(vip))
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (w ReplayProvider) BindHostToVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	return w.player.play("BindHostToVIP", callArguments(vip, hostID))
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (w ReplayProvider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	return w.player.play("UnbindHostFromVIP", callArguments(vip, hostID))
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (w ReplayProvider) DeleteVIP(vip *resources.VirtualIP) (err error) {
+	return w.player.play("DeleteVIP", callArguments(vip))
+}
+
+// CreateHost ...
+func (w ReplayProvider) CreateHost(request resources.HostRequest) (host *resources.Host, content *userdata.Content, err error) {
+	err = w.player.play("CreateHost", callArguments(request), &host, &content)
+	return host, content, err
+}
+
+// InspectHost ...
+func (w ReplayProvider) InspectHost(something interface{}) (host *resources.Host, err error) {
+	err = w.player.play("InspectHost", callArguments(something), &host)
+	return host, err
+}
+
+// GetHostByName ...
+func (w ReplayProvider) GetHostByName(name string) (host *resources.Host, err error) {
+	err = w.player.play("GetHostByName", callArguments(name), &host)
+	return host, err
+}
+
+// GetHostState ...
+func (w ReplayProvider) GetHostState(something interface{}) (state hoststate.Enum, err error) {
+	err = w.player.play("GetHostState", callArguments(something), &state)
+	return state, err
+}
+
+// ListHosts ...
+func (w ReplayProvider) ListHosts() (hosts []*resources.Host, err error) {
+	err = w.player.play("ListHosts", callArguments(), &hosts)
+	return hosts, err
+}
+
+// DeleteHost ...
+func (w ReplayProvider) DeleteHost(id string) (err error) {
+	return w.player.play("DeleteHost", callArguments(id))
+}
+
+// StopHost ...
+func (w ReplayProvider) StopHost(id string) (err error) {
+	return w.player.play("StopHost", callArguments(id))
+}
+
+// StartHost ...
+func (w ReplayProvider) StartHost(id string) (err error) {
+	return w.player.play("StartHost", callArguments(id))
+}
+
+// RebootHost ...
+func (w ReplayProvider) RebootHost(id string) (err error) {
+	return w.player.play("RebootHost", callArguments(id))
+}
+
+// ResizeHost ...
+func (w ReplayProvider) ResizeHost(id string, request resources.SizingRequirements) (host *resources.Host, err error) {
+	err = w.player.play("ResizeHost", callArguments(id, request), &host)
+	return host, err
+}
+
+// UpdateHostTags ...
+func (w ReplayProvider) UpdateHostTags(id string, set map[string]string, unset []string) (err error) {
+	return w.player.play("UpdateHostTags", callArguments(id, set, unset))
+}
+
+// CreateVolume ...
+func (w ReplayProvider) CreateVolume(request resources.VolumeRequest) (volume *resources.Volume, err error) {
+	err = w.player.play("CreateVolume", callArguments(request), &volume)
+	return volume, err
+}
+
+// GetVolume ...
+func (w ReplayProvider) GetVolume(id string) (volume *resources.Volume, err error) {
+	err = w.player.play("GetVolume", callArguments(id), &volume)
+	return volume, err
+}
+
+// ListVolumes ...
+func (w ReplayProvider) ListVolumes() (volumes []resources.Volume, err error) {
+	err = w.player.play("ListVolumes", callArguments(), &volumes)
+	return volumes, err
+}
+
+// DeleteVolume ...
+func (w ReplayProvider) DeleteVolume(id string) (err error) {
+	return w.player.play("DeleteVolume", callArguments(id))
+}
+
+// CreateVolumeSnapshot ...
+func (w ReplayProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (snapshot *resources.VolumeSnapshot, err error) {
+	err = w.player.play("CreateVolumeSnapshot", callArguments(request), &snapshot)
+	return snapshot, err
+}
+
+// GetVolumeSnapshot ...
+func (w ReplayProvider) GetVolumeSnapshot(id string) (snapshot *resources.VolumeSnapshot, err error) {
+	err = w.player.play("GetVolumeSnapshot", callArguments(id), &snapshot)
+	return snapshot, err
+}
+
+// ListVolumeSnapshots ...
+func (w ReplayProvider) ListVolumeSnapshots(volumeID string) (snapshots []resources.VolumeSnapshot, err error) {
+	err = w.player.play("ListVolumeSnapshots", callArguments(volumeID), &snapshots)
+	return snapshots, err
+}
+
+// DeleteVolumeSnapshot ...
+func (w ReplayProvider) DeleteVolumeSnapshot(id string) (err error) {
+	return w.player.play("DeleteVolumeSnapshot", callArguments(id))
+}
+
+// CreateVolumeFromSnapshot ...
+func (w ReplayProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (volume *resources.Volume, err error) {
+	err = w.player.play("CreateVolumeFromSnapshot", callArguments(snapshotID, request), &volume)
+	return volume, err
+}
+
+// ResizeVolume ...
+func (w ReplayProvider) ResizeVolume(id string, size int) (volume *resources.Volume, err error) {
+	err = w.player.play("ResizeVolume", callArguments(id, size), &volume)
+	return volume, err
+}
+
+// UpdateVolumeTags ...
+func (w ReplayProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) (err error) {
+	return w.player.play("UpdateVolumeTags", callArguments(id, set, unset))
+}
+
+// CreateVolumeAttachment ...
+func (w ReplayProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (id string, err error) {
+	err = w.player.play("CreateVolumeAttachment", callArguments(request), &id)
+	return id, err
+}
+
+// GetVolumeAttachment ...
+func (w ReplayProvider) GetVolumeAttachment(serverID, id string) (attachment *resources.VolumeAttachment, err error) {
+	err = w.player.play("GetVolumeAttachment", callArguments(serverID, id), &attachment)
+	return attachment, err
+}
+
+// ListVolumeAttachments ...
+func (w ReplayProvider) ListVolumeAttachments(serverID string) (attachments []resources.VolumeAttachment, err error) {
+	err = w.player.play("ListVolumeAttachments", callArguments(serverID), &attachments)
+	return attachments, err
+}
+
+// DeleteVolumeAttachment ...
+func (w ReplayProvider) DeleteVolumeAttachment(serverID, id string) (err error) {
+	return w.player.play("DeleteVolumeAttachment", callArguments(serverID, id))
+}
+
+// CreateSecurityGroup ...
+func (w ReplayProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (group *resources.SecurityGroup, err error) {
+	err = w.player.play("CreateSecurityGroup", callArguments(req), &group)
+	return group, err
+}
+
+// InspectSecurityGroup ...
+func (w ReplayProvider) InspectSecurityGroup(id string) (group *resources.SecurityGroup, err error) {
+	err = w.player.play("InspectSecurityGroup", callArguments(id), &group)
+	return group, err
+}
+
+// ListSecurityGroups ...
+func (w ReplayProvider) ListSecurityGroups() (groups []*resources.SecurityGroup, err error) {
+	err = w.player.play("ListSecurityGroups", callArguments(), &groups)
+	return groups, err
+}
+
+// DeleteSecurityGroup ...
+func (w ReplayProvider) DeleteSecurityGroup(id string) (err error) {
+	return w.player.play("DeleteSecurityGroup", callArguments(id))
+}
+
+// AddRuleToSecurityGroup ...
+func (w ReplayProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (group *resources.SecurityGroup, err error) {
+	err = w.player.play("AddRuleToSecurityGroup", callArguments(id, rule), &group)
+	return group, err
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w ReplayProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (group *resources.SecurityGroup, err error) {
+	err = w.player.play("DeleteRuleFromSecurityGroup", callArguments(id, ruleID), &group)
+	return group, err
+}
+
+// BindSecurityGroupToHost ...
+func (w ReplayProvider) BindSecurityGroupToHost(id, hostID string) (err error) {
+	return w.player.play("BindSecurityGroupToHost", callArguments(id, hostID))
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w ReplayProvider) UnbindSecurityGroupFromHost(id, hostID string) (err error) {
+	return w.player.play("UnbindSecurityGroupFromHost", callArguments(id, hostID))
+}
@@ -0,0 +1,588 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+)
+
+// ThrottledProvider is a Provider decorator limiting the rate of the calls to the provider it wraps, per class of
+// operations, and failing the calls at once with a circuit breaker when the provider fails too many times in a row
+type ThrottledProvider struct {
+	InnerProvider Provider
+	Name          string
+	limiters      map[string]*tokenBucket
+	breaker       *circuitBreaker
+}
+
+// NewThrottledProvider ...
+func NewThrottledProvider(innerProvider Provider, name string, options ThrottlingOptions) *ThrottledProvider {
+	w := &ThrottledProvider{
+		InnerProvider: innerProvider,
+		Name:          name,
+		limiters:      map[string]*tokenBucket{},
+		breaker:       newCircuitBreaker(name, options.BreakerThreshold, options.BreakerCooldown),
+	}
+	for _, class := range OperationClasses {
+		if rate := options.Rates[class]; rate > 0 {
+			w.limiters[class] = newTokenBucket(rate, options.Bursts[class])
+		}
+	}
+	return w
+}
+
+// call runs the provider call named call once its class of operations allows it and if the circuit breaker is closed
+func (w ThrottledProvider) call(call string, run func() error) error {
+	err := w.breaker.allow()
+	if err != nil {
+		return err
+	}
+	if limiter, ok := w.limiters[OperationClass(call)]; ok {
+		limiter.wait()
+	}
+	err = run()
+	w.breaker.done(err)
+	return err
+}
+
+// GetThrottlingStatus returns the rate limits and the state of the circuit breaker
+func (w ThrottledProvider) GetThrottlingStatus() ThrottlingStatus {
+	status := ThrottlingStatus{Breaker: w.breaker.status()}
+	for _, class := range OperationClasses {
+		rl := RateLimitStatus{Class: class}
+		if limiter, ok := w.limiters[class]; ok {
+			rl.Rate, rl.Burst = limiter.rate, limiter.burst
+		}
+		status.RateLimits = append(status.RateLimits, rl)
+	}
+	return status
+}
+
+// Build ...
+func (w ThrottledProvider) Build(something map[string]interface{}) (Provider, error) {
+	return w.InnerProvider.Build(something)
+}
+
+// GetAuthenticationOptions ...
+func (w ThrottledProvider) GetAuthenticationOptions() (providers.Config, error) {
+	return w.InnerProvider.GetAuthenticationOptions()
+}
+
+// GetConfigurationOptions ...
+func (w ThrottledProvider) GetConfigurationOptions() (providers.Config, error) {
+	return w.InnerProvider.GetConfigurationOptions()
+}
+
+// GetName ...
+func (w ThrottledProvider) GetName() string {
+	return w.InnerProvider.GetName()
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (w ThrottledProvider) GetCapabilities() providers.Capabilities {
+	return w.InnerProvider.GetCapabilities()
+}
+
+// GetTenantParameters ...
+func (w ThrottledProvider) GetTenantParameters() map[string]interface{} {
+	return w.InnerProvider.GetTenantParameters()
+}
+
+// ListImages ...
+func (w ThrottledProvider) ListImages(all bool) (images []resources.Image, err error) {
+	err = w.call("ListImages", func() (err error) {
+		images, err = w.InnerProvider.ListImages(all)
+		return err
+	})
+	return images, err
+}
+
+// ListTemplates ...
+func (w ThrottledProvider) ListTemplates(all bool) (templates []resources.HostTemplate, err error) {
+	err = w.call("ListTemplates", func() (err error) {
+		templates, err = w.InnerProvider.ListTemplates(all)
+		return err
+	})
+	return templates, err
+}
+
+// ListAvailabilityZones ...
+func (w ThrottledProvider) ListAvailabilityZones() (zones map[string]bool, err error) {
+	err = w.call("ListAvailabilityZones", func() (err error) {
+		zones, err = w.InnerProvider.ListAvailabilityZones()
+		return err
+	})
+	return zones, err
+}
+
+// ListRegions ...
+func (w ThrottledProvider) ListRegions() (regions []string, err error) {
+	err = w.call("ListRegions", func() (err error) {
+		regions, err = w.InnerProvider.ListRegions()
+		return err
+	})
+	return regions, err
+}
+
+// GetImage ...
+func (w ThrottledProvider) GetImage(id string) (image *resources.Image, err error) {
+	err = w.call("GetImage", func() (err error) {
+		image, err = w.InnerProvider.GetImage(id)
+		return err
+	})
+	return image, err
+}
+
+// CreateImage ...
+func (w ThrottledProvider) CreateImage(hostID string, name string) (image *resources.Image, err error) {
+	err = w.call("CreateImage", func() (err error) {
+		image, err = w.InnerProvider.CreateImage(hostID, name)
+		return err
+	})
+	return image, err
+}
+
+// DeleteImage ...
+func (w ThrottledProvider) DeleteImage(id string) (err error) {
+	return w.call("DeleteImage", func() error {
+		return w.InnerProvider.DeleteImage(id)
+	})
+}
+
+// GetTemplate ...
+func (w ThrottledProvider) GetTemplate(id string) (template *resources.HostTemplate, err error) {
+	err = w.call("GetTemplate", func() (err error) {
+		template, err = w.InnerProvider.GetTemplate(id)
+		return err
+	})
+	return template, err
+}
+
+// CreateKeyPair ...
+func (w ThrottledProvider) CreateKeyPair(name string) (keypair *resources.KeyPair, err error) {
+	err = w.call("CreateKeyPair", func() (err error) {
+		keypair, err = w.InnerProvider.CreateKeyPair(name)
+		return err
+	})
+	return keypair, err
+}
+
+// GetKeyPair ...
+func (w ThrottledProvider) GetKeyPair(id string) (keypair *resources.KeyPair, err error) {
+	err = w.call("GetKeyPair", func() (err error) {
+		keypair, err = w.InnerProvider.GetKeyPair(id)
+		return err
+	})
+	return keypair, err
+}
+
+// ListKeyPairs ...
+func (w ThrottledProvider) ListKeyPairs() (keypairs []resources.KeyPair, err error) {
+	err = w.call("ListKeyPairs", func() (err error) {
+		keypairs, err = w.InnerProvider.ListKeyPairs()
+		return err
+	})
+	return keypairs, err
+}
+
+// DeleteKeyPair ...
+func (w ThrottledProvider) DeleteKeyPair(id string) (err error) {
+	return w.call("DeleteKeyPair", func() error {
+		return w.InnerProvider.DeleteKeyPair(id)
+	})
+}
+
+// CreateNetwork ...
+func (w ThrottledProvider) CreateNetwork(req resources.NetworkRequest) (network *resources.Network, err error) {
+	err = w.call("CreateNetwork", func() (err error) {
+		network, err = w.InnerProvider.CreateNetwork(req)
+		return err
+	})
+	return network, err
+}
+
+// GetNetwork ...
+func (w ThrottledProvider) GetNetwork(id string) (network *resources.Network, err error) {
+	err = w.call("GetNetwork", func() (err error) {
+		network, err = w.InnerProvider.GetNetwork(id)
+		return err
+	})
+	return network, err
+}
+
+// GetNetworkByName ...
+func (w ThrottledProvider) GetNetworkByName(name string) (network *resources.Network, err error) {
+	err = w.call("GetNetworkByName", func() (err error) {
+		network, err = w.InnerProvider.GetNetworkByName(name)
+		return err
+	})
+	return network, err
+}
+
+// ListNetworks ...
+func (w ThrottledProvider) ListNetworks() (networks []*resources.Network, err error) {
+	err = w.call("ListNetworks", func() (err error) {
+		networks, err = w.InnerProvider.ListNetworks()
+		return err
+	})
+	return networks, err
+}
+
+// DeleteNetwork ...
+func (w ThrottledProvider) DeleteNetwork(id string) (err error) {
+	return w.call("DeleteNetwork", func() error {
+		return w.InnerProvider.DeleteNetwork(id)
+	})
+}
+
+// CreateGateway ...
+func (w ThrottledProvider) CreateGateway(req resources.GatewayRequest) (host *resources.Host, content *userdata.Content, err error) {
+	err = w.call("CreateGateway", func() (err error) {
+		host, content, err = w.InnerProvider.CreateGateway(req)
+		return err
+	})
+	return host, content, err
+}
+
+// DeleteGateway ...
+func (w ThrottledProvider) DeleteGateway(networkID string) (err error) {
+	return w.call("DeleteGateway", func() error {
+		return w.InnerProvider.DeleteGateway(networkID)
+	})
+}
+
+// CreateVIP ...
+func (w ThrottledProvider) CreateVIP(networkID string, description string) (vip *resources.VirtualIP, err error) {
+	err = w.call("CreateVIP", func() (err error) {
+		vip, err = w.InnerProvider.CreateVIP(networkID, description)
+		return err
+	})
+	return vip, err
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (w ThrottledProvider) AddPublicIPToVIP(vip *resources.VirtualIP) (err error) {
+	return w.call("AddPublicIPToVIP", func() error {
+		return w.InnerProvider.AddPublicIPToVIP(vip)
+	})
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (w ThrottledProvider) BindHostToVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	return w.call("BindHostToVIP", func() error {
+		return w.InnerProvider.BindHostToVIP(vip, hostID)
+	})
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (w ThrottledProvider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	return w.call("UnbindHostFromVIP", func() error {
+		return w.InnerProvider.UnbindHostFromVIP(vip, hostID)
+	})
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (w ThrottledProvider) DeleteVIP(vip *resources.VirtualIP) (err error) {
+	return w.call("DeleteVIP", func() error {
+		return w.InnerProvider.DeleteVIP(vip)
+	})
+}
+
+// CreateHost ...
+func (w ThrottledProvider) CreateHost(request resources.HostRequest) (host *resources.Host, content *userdata.Content, err error) {
+	err = w.call("CreateHost", func() (err error) {
+		host, content, err = w.InnerProvider.CreateHost(request)
+		return err
+	})
+	return host, content, err
+}
+
+// InspectHost ...
+func (w ThrottledProvider) InspectHost(something interface{}) (host *resources.Host, err error) {
+	err = w.call("InspectHost", func() (err error) {
+		host, err = w.InnerProvider.InspectHost(something)
+		return err
+	})
+	return host, err
+}
+
+// GetHostByName ...
+func (w ThrottledProvider) GetHostByName(name string) (host *resources.Host, err error) {
+	err = w.call("GetHostByName", func() (err error) {
+		host, err = w.InnerProvider.GetHostByName(name)
+		return err
+	})
+	return host, err
+}
+
+// GetHostState ...
+func (w ThrottledProvider) GetHostState(something interface{}) (state hoststate.Enum, err error) {
+	err = w.call("GetHostState", func() (err error) {
+		state, err = w.InnerProvider.GetHostState(something)
+		return err
+	})
+	return state, err
+}
+
+// ListHosts ...
+func (w ThrottledProvider) ListHosts() (hosts []*resources.Host, err error) {
+	err = w.call("ListHosts", func() (err error) {
+		hosts, err = w.InnerProvider.ListHosts()
+		return err
+	})
+	return hosts, err
+}
+
+// DeleteHost ...
+func (w ThrottledProvider) DeleteHost(id string) (err error) {
+	return w.call("DeleteHost", func() error {
+		return w.InnerProvider.DeleteHost(id)
+	})
+}
+
+// StopHost ...
+func (w ThrottledProvider) StopHost(id string) (err error) {
+	return w.call("StopHost", func() error {
+		return w.InnerProvider.StopHost(id)
+	})
+}
+
+// StartHost ...
+func (w ThrottledProvider) StartHost(id string) (err error) {
+	return w.call("StartHost", func() error {
+		return w.InnerProvider.StartHost(id)
+	})
+}
+
+// RebootHost ...
+func (w ThrottledProvider) RebootHost(id string) (err error) {
+	return w.call("RebootHost", func() error {
+		return w.InnerProvider.RebootHost(id)
+	})
+}
+
+// ResizeHost ...
+func (w ThrottledProvider) ResizeHost(id string, request resources.SizingRequirements) (host *resources.Host, err error) {
+	err = w.call("ResizeHost", func() (err error) {
+		host, err = w.InnerProvider.ResizeHost(id, request)
+		return err
+	})
+	return host, err
+}
+
+// UpdateHostTags ...
+func (w ThrottledProvider) UpdateHostTags(id string, set map[string]string, unset []string) (err error) {
+	return w.call("UpdateHostTags", func() error {
+		return w.InnerProvider.UpdateHostTags(id, set, unset)
+	})
+}
+
+// CreateVolume ...
+func (w ThrottledProvider) CreateVolume(request resources.VolumeRequest) (volume *resources.Volume, err error) {
+	err = w.call("CreateVolume", func() (err error) {
+		volume, err = w.InnerProvider.CreateVolume(request)
+		return err
+	})
+	return volume, err
+}
+
+// GetVolume ...
+func (w ThrottledProvider) GetVolume(id string) (volume *resources.Volume, err error) {
+	err = w.call("GetVolume", func() (err error) {
+		volume, err = w.InnerProvider.GetVolume(id)
+		return err
+	})
+	return volume, err
+}
+
+// ListVolumes ...
+func (w ThrottledProvider) ListVolumes() (volumes []resources.Volume, err error) {
+	err = w.call("ListVolumes", func() (err error) {
+		volumes, err = w.InnerProvider.ListVolumes()
+		return err
+	})
+	return volumes, err
+}
+
+// DeleteVolume ...
+func (w ThrottledProvider) DeleteVolume(id string) (err error) {
+	return w.call("DeleteVolume", func() error {
+		return w.InnerProvider.DeleteVolume(id)
+	})
+}
+
+// CreateVolumeSnapshot ...
+func (w ThrottledProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (snapshot *resources.VolumeSnapshot, err error) {
+	err = w.call("CreateVolumeSnapshot", func() (err error) {
+		snapshot, err = w.InnerProvider.CreateVolumeSnapshot(request)
+		return err
+	})
+	return snapshot, err
+}
+
+// GetVolumeSnapshot ...
+func (w ThrottledProvider) GetVolumeSnapshot(id string) (snapshot *resources.VolumeSnapshot, err error) {
+	err = w.call("GetVolumeSnapshot", func() (err error) {
+		snapshot, err = w.InnerProvider.GetVolumeSnapshot(id)
+		return err
+	})
+	return snapshot, err
+}
+
+// ListVolumeSnapshots ...
+func (w ThrottledProvider) ListVolumeSnapshots(volumeID string) (snapshots []resources.VolumeSnapshot, err error) {
+	err = w.call("ListVolumeSnapshots", func() (err error) {
+		snapshots, err = w.InnerProvider.ListVolumeSnapshots(volumeID)
+		return err
+	})
+	return snapshots, err
+}
+
+// DeleteVolumeSnapshot ...
+func (w ThrottledProvider) DeleteVolumeSnapshot(id string) (err error) {
+	return w.call("DeleteVolumeSnapshot", func() error {
+		return w.InnerProvider.DeleteVolumeSnapshot(id)
+	})
+}
+
+// CreateVolumeFromSnapshot ...
+func (w ThrottledProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (volume *resources.Volume, err error) {
+	err = w.call("CreateVolumeFromSnapshot", func() (err error) {
+		volume, err = w.InnerProvider.CreateVolumeFromSnapshot(snapshotID, request)
+		return err
+	})
+	return volume, err
+}
+
+// ResizeVolume ...
+func (w ThrottledProvider) ResizeVolume(id string, size int) (volume *resources.Volume, err error) {
+	err = w.call("ResizeVolume", func() (err error) {
+		volume, err = w.InnerProvider.ResizeVolume(id, size)
+		return err
+	})
+	return volume, err
+}
+
+// UpdateVolumeTags ...
+func (w ThrottledProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) (err error) {
+	return w.call("UpdateVolumeTags", func() error {
+		return w.InnerProvider.UpdateVolumeTags(id, set, unset)
+	})
+}
+
+// CreateVolumeAttachment ...
+func (w ThrottledProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (id string, err error) {
+	err = w.call("CreateVolumeAttachment", func() (err error) {
+		id, err = w.InnerProvider.CreateVolumeAttachment(request)
+		return err
+	})
+	return id, err
+}
+
+// GetVolumeAttachment ...
+func (w ThrottledProvider) GetVolumeAttachment(serverID, id string) (attachment *resources.VolumeAttachment, err error) {
+	err = w.call("GetVolumeAttachment", func() (err error) {
+		attachment, err = w.InnerProvider.GetVolumeAttachment(serverID, id)
+		return err
+	})
+	return attachment, err
+}
+
+// ListVolumeAttachments ...
+func (w ThrottledProvider) ListVolumeAttachments(serverID string) (attachments []resources.VolumeAttachment, err error) {
+	err = w.call("ListVolumeAttachments", func() (err error) {
+		attachments, err = w.InnerProvider.ListVolumeAttachments(serverID)
+		return err
+	})
+	return attachments, err
+}
+
+// DeleteVolumeAttachment ...
+func (w ThrottledProvider) DeleteVolumeAttachment(serverID, id string) (err error) {
+	return w.call("DeleteVolumeAttachment", func() error {
+		return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
+	})
+}
+
+// CreateSecurityGroup ...
+func (w ThrottledProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (group *resources.SecurityGroup, err error) {
+	err = w.call("CreateSecurityGroup", func() (err error) {
+		group, err = w.InnerProvider.CreateSecurityGroup(req)
+		return err
+	})
+	return group, err
+}
+
+// InspectSecurityGroup ...
+func (w ThrottledProvider) InspectSecurityGroup(id string) (group *resources.SecurityGroup, err error) {
+	err = w.call("InspectSecurityGroup", func() (err error) {
+		group, err = w.InnerProvider.InspectSecurityGroup(id)
+		return err
+	})
+	return group, err
+}
+
+// ListSecurityGroups ...
+func (w ThrottledProvider) ListSecurityGroups() (groups []*resources.SecurityGroup, err error) {
+	err = w.call("ListSecurityGroups", func() (err error) {
+		groups, err = w.InnerProvider.ListSecurityGroups()
+		return err
+	})
+	return groups, err
+}
+
+// DeleteSecurityGroup ...
+func (w ThrottledProvider) DeleteSecurityGroup(id string) (err error) {
+	return w.call("DeleteSecurityGroup", func() error {
+		return w.InnerProvider.DeleteSecurityGroup(id)
+	})
+}
+
+// AddRuleToSecurityGroup ...
+func (w ThrottledProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (group *resources.SecurityGroup, err error) {
+	err = w.call("AddRuleToSecurityGroup", func() (err error) {
+		group, err = w.InnerProvider.AddRuleToSecurityGroup(id, rule)
+		return err
+	})
+	return group, err
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w ThrottledProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (group *resources.SecurityGroup, err error) {
+	err = w.call("DeleteRuleFromSecurityGroup", func() (err error) {
+		group, err = w.InnerProvider.DeleteRuleFromSecurityGroup(id, ruleID)
+		return err
+	})
+	return group, err
+}
+
+// BindSecurityGroupToHost ...
+func (w ThrottledProvider) BindSecurityGroupToHost(id, hostID string) (err error) {
+	return w.call("BindSecurityGroupToHost", func() error {
+		return w.InnerProvider.BindSecurityGroupToHost(id, hostID)
+	})
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w ThrottledProvider) UnbindSecurityGroupFromHost(id, hostID string) (err error) {
+	return w.call("UnbindSecurityGroupFromHost", func() error {
+		return w.InnerProvider.UnbindSecurityGroupFromHost(id, hostID)
+	})
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// Classes of the operations of a provider, each one having its own rate limit
+const (
+	// ReadOperations are the calls whose name starts with Get, List or Inspect
+	ReadOperations = "read"
+	// CreateOperations are the calls creating or changing resources (Create, Add, Bind, Start, Stop, Resize, ...)
+	CreateOperations = "create"
+	// DeleteOperations are the calls whose name starts with Delete or Unbind
+	DeleteOperations = "delete"
+)
+
+// OperationClasses lists the classes of operations
+var OperationClasses = []string{ReadOperations, CreateOperations, DeleteOperations}
+
+// OperationClass returns the class of the provider call named call
+func OperationClass(call string) string {
+	switch {
+	case strings.HasPrefix(call, "Get"), strings.HasPrefix(call, "List"), strings.HasPrefix(call, "Inspect"):
+		return ReadOperations
+	case strings.HasPrefix(call, "Delete"), strings.HasPrefix(call, "Unbind"):
+		return DeleteOperations
+	default:
+		return CreateOperations
+	}
+}
+
+// States of a circuit breaker
+const (
+	// BreakerClosed means the calls go to the provider
+	BreakerClosed = "closed"
+	// BreakerOpen means the calls fail at once, the provider having failed too many times in a row
+	BreakerOpen = "open"
+	// BreakerHalfOpen means the cooldown is over and a trial call decides if the breaker closes or opens again
+	BreakerHalfOpen = "half-open"
+)
+
+// ThrottlingOptions are the settings of a ThrottledProvider
+type ThrottlingOptions struct {
+	// Rates are the calls per second allowed for each class of operations; 0 (or no value) means unlimited
+	Rates map[string]float64
+	// Bursts are the calls allowed at once for each class of operations, above the rate (1 if not set)
+	Bursts map[string]int
+	// BreakerThreshold is the number of failures of the provider in a row opening the circuit breaker; 0 disables it
+	BreakerThreshold int
+	// BreakerCooldown is the time during which the calls fail at once when the circuit breaker is open
+	BreakerCooldown time.Duration
+}
+
+const (
+	// DefaultBreakerThreshold is the number of failures in a row opening the circuit breaker if not set in the tenant
+	DefaultBreakerThreshold = 10
+	// DefaultBreakerCooldown is the cooldown of the circuit breaker if not set in the tenant
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// RateLimitStatus describes the rate limit of a class of operations
+type RateLimitStatus struct {
+	Class string
+	Rate  float64
+	Burst int
+}
+
+// CircuitBreakerStatus describes the state of a circuit breaker
+type CircuitBreakerStatus struct {
+	State               string
+	ConsecutiveFailures int
+	Threshold           int
+	Cooldown            time.Duration
+	OpenedAt            time.Time
+	LastError           string
+	Trips               int
+}
+
+// ThrottlingStatus describes the rate limits and the circuit breaker of a ThrottledProvider
+type ThrottlingStatus struct {
+	RateLimits []RateLimitStatus
+	Breaker    CircuitBreakerStatus
+}
+
+// tokenBucket limits the rate of the calls: a call takes a token, the tokens being refilled at rate per second up to burst
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket ...
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns the delay to wait before using it; the tokens taken in advance are owed,
+// so the callers are served in the order of their reservations
+func (tb *tokenBucket) reserve() time.Duration {
+	tb.lock.Lock()
+	defer tb.lock.Unlock()
+
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > float64(tb.burst) {
+		tb.tokens = float64(tb.burst)
+	}
+	tb.last = now
+	tb.tokens--
+	if tb.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
+
+// wait blocks until the caller may call the provider
+func (tb *tokenBucket) wait() {
+	if delay := tb.reserve(); delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// circuitBreaker fails the calls at once after threshold failures of the provider in a row, during cooldown;
+// then a single trial call is let through, closing the breaker if it succeeds or opening it again if it fails
+type circuitBreaker struct {
+	lock      sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	name      string
+	state     string
+	failures  int
+	openedAt  time.Time
+	lastError string
+	trips     int
+	trial     bool
+}
+
+// newCircuitBreaker ...
+func newCircuitBreaker(name string, threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{name: name, threshold: threshold, cooldown: cooldown, state: BreakerClosed}
+}
+
+// allow tells if a call can go to the provider; if not, the error returned explains why
+func (cb *circuitBreaker) allow() error {
+	if cb.threshold <= 0 {
+		return nil
+	}
+
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	switch cb.state {
+	case BreakerOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return scerr.NotAvailableError(fmt.Sprintf("calls to provider '%s' suspended until %s after %d failures in a row (last: %s)",
+				cb.name, cb.openedAt.Add(cb.cooldown).Format(time.RFC3339), cb.failures, cb.lastError))
+		}
+		cb.state = BreakerHalfOpen
+		cb.trial = true
+		logrus.Infof("circuit breaker of provider '%s' half-open, trying a call", cb.name)
+	case BreakerHalfOpen:
+		if cb.trial {
+			return scerr.NotAvailableError(fmt.Sprintf("calls to provider '%s' suspended, waiting for the result of a trial call", cb.name))
+		}
+		cb.trial = true
+	}
+	return nil
+}
+
+// done records the outcome of a call let through by allow()
+func (cb *circuitBreaker) done(err error) {
+	if cb.threshold <= 0 {
+		return
+	}
+
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	cb.trial = false
+	if err == nil || !isProviderFailure(err) {
+		cb.failures = 0
+		if cb.state == BreakerHalfOpen {
+			cb.state = BreakerClosed
+			logrus.Infof("circuit breaker of provider '%s' closed", cb.name)
+		}
+		return
+	}
+
+	cb.failures++
+	cb.lastError = err.Error()
+	if cb.state == BreakerHalfOpen || (cb.state == BreakerClosed && cb.failures >= cb.threshold) {
+		cb.state = BreakerOpen
+		cb.openedAt = time.Now()
+		cb.trips++
+		logrus.Warnf("circuit breaker of provider '%s' open for %s after %d failures in a row (last: %s)", cb.name, cb.cooldown, cb.failures, cb.lastError)
+	}
+}
+
+// status ...
+func (cb *circuitBreaker) status() CircuitBreakerStatus {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	state := cb.state
+	if cb.threshold <= 0 {
+		state = "disabled"
+	}
+	return CircuitBreakerStatus{
+		State:               state,
+		ConsecutiveFailures: cb.failures,
+		Threshold:           cb.threshold,
+		Cooldown:            cb.cooldown,
+		OpenedAt:            cb.openedAt,
+		LastError:           cb.lastError,
+		Trips:               cb.trips,
+	}
+}
+
+// serverErrorRE matches the HTTP codes 429 and 5xx in the errors the stacks build from the responses of the providers
+var serverErrorRE = regexp.MustCompile(`(?i)(code|status)[: =]+(429|5[0-9][0-9])\b`)
+
+// isProviderFailure tells if err comes from the provider being unable to answer (overloaded, or answering
+// "too many requests" or a server error), rather than from the request itself (resource not found, invalid parameter, ...)
+// Note: ErrNotAvailable and ErrTimeout don't count, the stacks also use them for the state of the resources (a volume
+// still being resized, a host not started yet, ...) that the callers poll until it changes
+func isProviderFailure(err error) bool {
+	if _, ok := scerr.Cause(err).(scerr.ErrOverload); ok {
+		return true
+	}
+	if _, ok := err.(scerr.ErrOverload); ok {
+		return true
+	}
+	return serverErrorRE.MatchString(err.Error())
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+func TestOperationClass(t *testing.T) {
+	assert.Equal(t, ReadOperations, OperationClass("InspectHost"))
+	assert.Equal(t, ReadOperations, OperationClass("ListVolumes"))
+	assert.Equal(t, DeleteOperations, OperationClass("DeleteNetwork"))
+	assert.Equal(t, DeleteOperations, OperationClass("UnbindHostFromVIP"))
+	assert.Equal(t, CreateOperations, OperationClass("CreateHost"))
+	assert.Equal(t, CreateOperations, OperationClass("StopHost"))
+}
+
+func TestTokenBucket(t *testing.T) {
+	tb := newTokenBucket(10, 2)
+	assert.Equal(t, time.Duration(0), tb.reserve())
+	assert.Equal(t, time.Duration(0), tb.reserve())
+	delay := tb.reserve()
+	assert.True(t, delay > 50*time.Millisecond && delay <= 100*time.Millisecond, fmt.Sprintf("unexpected delay %s", delay))
+	delay = tb.reserve()
+	assert.True(t, delay > 150*time.Millisecond && delay <= 200*time.Millisecond, fmt.Sprintf("unexpected delay %s", delay))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	cb := newCircuitBreaker("test", 3, 50*time.Millisecond)
+
+	// errors not due to the provider don't count
+	for i := 0; i < 5; i++ {
+		require.Nil(t, cb.allow())
+		cb.done(scerr.NotFoundError("not found"))
+	}
+	assert.Equal(t, BreakerClosed, cb.status().State)
+
+	for i := 0; i < 3; i++ {
+		require.Nil(t, cb.allow())
+		cb.done(fmt.Errorf("unexpected response code: code: 503, reason: unavailable"))
+	}
+	status := cb.status()
+	assert.Equal(t, BreakerOpen, status.State)
+	assert.Equal(t, 1, status.Trips)
+	err := cb.allow()
+	_, ok := err.(scerr.ErrNotAvailable)
+	assert.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+
+	// after the cooldown, a single trial call goes through; its failure opens the breaker again
+	time.Sleep(60 * time.Millisecond)
+	require.Nil(t, cb.allow())
+	assert.Equal(t, BreakerHalfOpen, cb.status().State)
+	assert.NotNil(t, cb.allow())
+	cb.done(scerr.OverloadError("too many requests"))
+	assert.Equal(t, BreakerOpen, cb.status().State)
+	assert.Equal(t, 2, cb.status().Trips)
+
+	// a successful trial call closes it
+	time.Sleep(60 * time.Millisecond)
+	require.Nil(t, cb.allow())
+	cb.done(nil)
+	status = cb.status()
+	assert.Equal(t, BreakerClosed, status.State)
+	assert.Equal(t, 0, status.ConsecutiveFailures)
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	cb := newCircuitBreaker("test", 0, time.Minute)
+	for i := 0; i < 20; i++ {
+		require.Nil(t, cb.allow())
+		cb.done(scerr.OverloadError("too many requests"))
+	}
+	assert.Equal(t, "disabled", cb.status().State)
+}
+
+// resizingProvider is a provider whose volumes are still being resized for the first calls
+type resizingProvider struct {
+	Provider
+	calls int
+}
+
+func (p *resizingProvider) GetVolume(id string) (*resources.Volume, error) {
+	p.calls++
+	if p.calls < 20 {
+		return nil, scerr.NotAvailableError(fmt.Sprintf("volume '%s' is still being resized", id))
+	}
+	return &resources.Volume{ID: id}, nil
+}
+
+func TestCircuitBreakerStateConflicts(t *testing.T) {
+	inner := &resizingProvider{}
+	w := NewThrottledProvider(inner, "test", ThrottlingOptions{BreakerThreshold: 3, BreakerCooldown: time.Minute})
+
+	// polling the state of a volume is not a failure of the provider
+	var err error
+	for i := 0; i < 30; i++ {
+		_, err = w.GetVolume("vol")
+		if err == nil {
+			break
+		}
+		_, ok := err.(scerr.ErrNotAvailable)
+		require.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+	}
+	require.Nil(t, err)
+	assert.Equal(t, 20, inner.calls)
+	status := w.GetThrottlingStatus()
+	assert.Equal(t, BreakerClosed, status.Breaker.State)
+	assert.Equal(t, 0, status.Breaker.Trips)
+}
@@ -113,6 +113,26 @@ func (w ErrorTraceProvider) GetImage(id string) (images *resources.Image, err er
 	return w.InnerProvider.GetImage(id)
 }
 
+// CreateImage ...
+func (w ErrorTraceProvider) CreateImage(hostID string, name string) (_ *resources.Image, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:CreateImage", w.Name))
+	return w.InnerProvider.CreateImage(hostID, name)
+}
+
+// DeleteImage ...
+func (w ErrorTraceProvider) DeleteImage(id string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:DeleteImage", w.Name))
+	return w.InnerProvider.DeleteImage(id)
+}
+
 // GetTemplate ...
 func (w ErrorTraceProvider) GetTemplate(id string) (templates *resources.HostTemplate, err error) {
 	defer func(prefix string) {
@@ -383,6 +403,16 @@ func (w ErrorTraceProvider) ResizeHost(id string, request resources.SizingRequir
 	return w.InnerProvider.ResizeHost(id, request)
 }
 
+// UpdateHostTags ...
+func (w ErrorTraceProvider) UpdateHostTags(id string, set map[string]string, unset []string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:UpdateHostTags", w.Name))
+	return w.InnerProvider.UpdateHostTags(id, set, unset)
+}
+
 // CreateVolume ...
 func (w ErrorTraceProvider) CreateVolume(request resources.VolumeRequest) (_ *resources.Volume, err error) {
 	defer func(prefix string) {
@@ -423,6 +453,76 @@ func (w ErrorTraceProvider) DeleteVolume(id string) (err error) {
 	return w.InnerProvider.DeleteVolume(id)
 }
 
+// CreateVolumeSnapshot ...
+func (w ErrorTraceProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (_ *resources.VolumeSnapshot, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:CreateVolumeSnapshot", w.Name))
+	return w.InnerProvider.CreateVolumeSnapshot(request)
+}
+
+// GetVolumeSnapshot ...
+func (w ErrorTraceProvider) GetVolumeSnapshot(id string) (_ *resources.VolumeSnapshot, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:GetVolumeSnapshot", w.Name))
+	return w.InnerProvider.GetVolumeSnapshot(id)
+}
+
+// ListVolumeSnapshots ...
+func (w ErrorTraceProvider) ListVolumeSnapshots(volumeID string) (_ []resources.VolumeSnapshot, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:ListVolumeSnapshots", w.Name))
+	return w.InnerProvider.ListVolumeSnapshots(volumeID)
+}
+
+// DeleteVolumeSnapshot ...
+func (w ErrorTraceProvider) DeleteVolumeSnapshot(id string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:DeleteVolumeSnapshot", w.Name))
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
+// CreateVolumeFromSnapshot ...
+func (w ErrorTraceProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (_ *resources.Volume, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:CreateVolumeFromSnapshot", w.Name))
+	return w.InnerProvider.CreateVolumeFromSnapshot(snapshotID, request)
+}
+
+// ResizeVolume ...
+func (w ErrorTraceProvider) ResizeVolume(id string, size int) (_ *resources.Volume, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:ResizeVolume", w.Name))
+	return w.InnerProvider.ResizeVolume(id, size)
+}
+
+// UpdateVolumeTags ...
+func (w ErrorTraceProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:UpdateVolumeTags", w.Name))
+	return w.InnerProvider.UpdateVolumeTags(id, set, unset)
+}
+
 // CreateVolumeAttachment ...
 func (w ErrorTraceProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (_ string, err error) {
 	defer func(prefix string) {
@@ -463,6 +563,86 @@ func (w ErrorTraceProvider) DeleteVolumeAttachment(serverID, id string) (err err
 	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
 }
 
+// CreateSecurityGroup ...
+func (w ErrorTraceProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (_ *resources.SecurityGroup, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:CreateSecurityGroup", w.Name))
+	return w.InnerProvider.CreateSecurityGroup(req)
+}
+
+// InspectSecurityGroup ...
+func (w ErrorTraceProvider) InspectSecurityGroup(id string) (_ *resources.SecurityGroup, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:InspectSecurityGroup", w.Name))
+	return w.InnerProvider.InspectSecurityGroup(id)
+}
+
+// ListSecurityGroups ...
+func (w ErrorTraceProvider) ListSecurityGroups() (_ []*resources.SecurityGroup, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:ListSecurityGroups", w.Name))
+	return w.InnerProvider.ListSecurityGroups()
+}
+
+// DeleteSecurityGroup ...
+func (w ErrorTraceProvider) DeleteSecurityGroup(id string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:DeleteSecurityGroup", w.Name))
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w ErrorTraceProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (_ *resources.SecurityGroup, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:AddRuleToSecurityGroup", w.Name))
+	return w.InnerProvider.AddRuleToSecurityGroup(id, rule)
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w ErrorTraceProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (_ *resources.SecurityGroup, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:DeleteRuleFromSecurityGroup", w.Name))
+	return w.InnerProvider.DeleteRuleFromSecurityGroup(id, ruleID)
+}
+
+// BindSecurityGroupToHost ...
+func (w ErrorTraceProvider) BindSecurityGroupToHost(id, hostID string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:BindSecurityGroupToHost", w.Name))
+	return w.InnerProvider.BindSecurityGroupToHost(id, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w ErrorTraceProvider) UnbindSecurityGroupFromHost(id, hostID string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:UnbindSecurityGroupFromHost", w.Name))
+	return w.InnerProvider.UnbindSecurityGroupFromHost(id, hostID)
+}
+
 // GetCapabilities ...
 func (w ErrorTraceProvider) GetCapabilities() providers.Capabilities {
 	return w.InnerProvider.GetCapabilities()
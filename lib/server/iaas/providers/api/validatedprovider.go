@@ -113,6 +113,20 @@ func (w ValidatedProvider) GetImage(id string) (res *resources.Image, err error)
 	return res, err
 }
 
+// CreateImage ...
+func (w ValidatedProvider) CreateImage(hostID string, name string) (res *resources.Image, err error) {
+	res, err = w.InnerProvider.CreateImage(hostID, name)
+	if err == nil && res != nil && (res.ID == "" || res.Name == "") {
+		logrus.Warnf("Invalid image: %v", *res)
+	}
+	return res, err
+}
+
+// DeleteImage ...
+func (w ValidatedProvider) DeleteImage(id string) (err error) {
+	return w.InnerProvider.DeleteImage(id)
+}
+
 // GetTemplate ...
 func (w ValidatedProvider) GetTemplate(id string) (res *resources.HostTemplate, err error) {
 	res, err = w.InnerProvider.GetTemplate(id)
@@ -337,6 +351,11 @@ func (w ValidatedProvider) ResizeHost(id string, request resources.SizingRequire
 	return res, err
 }
 
+// UpdateHostTags ...
+func (w ValidatedProvider) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	return w.InnerProvider.UpdateHostTags(id, set, unset)
+}
+
 // CreateVolume ...
 func (w ValidatedProvider) CreateVolume(request resources.VolumeRequest) (res *resources.Volume, err error) {
 	res, err = w.InnerProvider.CreateVolume(request)
@@ -381,6 +400,65 @@ func (w ValidatedProvider) DeleteVolume(id string) (err error) {
 	return w.InnerProvider.DeleteVolume(id)
 }
 
+// CreateVolumeSnapshot ...
+func (w ValidatedProvider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (res *resources.VolumeSnapshot, err error) {
+	res, err = w.InnerProvider.CreateVolumeSnapshot(request)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid volume snapshot: %v", *res)
+	}
+	return res, err
+}
+
+// GetVolumeSnapshot ...
+func (w ValidatedProvider) GetVolumeSnapshot(id string) (res *resources.VolumeSnapshot, err error) {
+	res, err = w.InnerProvider.GetVolumeSnapshot(id)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid volume snapshot: %v", *res)
+	}
+	return res, err
+}
+
+// ListVolumeSnapshots ...
+func (w ValidatedProvider) ListVolumeSnapshots(volumeID string) (res []resources.VolumeSnapshot, err error) {
+	res, err = w.InnerProvider.ListVolumeSnapshots(volumeID)
+	if err == nil {
+		for _, item := range res {
+			if !item.OK() {
+				logrus.Warnf("Invalid volume snapshot: %v", item)
+			}
+		}
+	}
+	return res, err
+}
+
+// DeleteVolumeSnapshot ...
+func (w ValidatedProvider) DeleteVolumeSnapshot(id string) (err error) {
+	return w.InnerProvider.DeleteVolumeSnapshot(id)
+}
+
+// CreateVolumeFromSnapshot ...
+func (w ValidatedProvider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (res *resources.Volume, err error) {
+	res, err = w.InnerProvider.CreateVolumeFromSnapshot(snapshotID, request)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid volume: %v", *res)
+	}
+	return res, err
+}
+
+// ResizeVolume ...
+func (w ValidatedProvider) ResizeVolume(id string, size int) (res *resources.Volume, err error) {
+	res, err = w.InnerProvider.ResizeVolume(id, size)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid volume: %v", *res)
+	}
+	return res, err
+}
+
+// UpdateVolumeTags ...
+func (w ValidatedProvider) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	return w.InnerProvider.UpdateVolumeTags(id, set, unset)
+}
+
 // CreateVolumeAttachment ...
 func (w ValidatedProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (id string, err error) {
 	return w.InnerProvider.CreateVolumeAttachment(request)
@@ -416,3 +494,67 @@ func (w ValidatedProvider) ListVolumeAttachments(serverID string) (res []resourc
 func (w ValidatedProvider) DeleteVolumeAttachment(serverID, id string) (err error) {
 	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
 }
+
+// CreateSecurityGroup ...
+func (w ValidatedProvider) CreateSecurityGroup(req resources.SecurityGroupRequest) (res *resources.SecurityGroup, err error) {
+	res, err = w.InnerProvider.CreateSecurityGroup(req)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid security group: %v", *res)
+	}
+	return res, err
+}
+
+// InspectSecurityGroup ...
+func (w ValidatedProvider) InspectSecurityGroup(id string) (res *resources.SecurityGroup, err error) {
+	res, err = w.InnerProvider.InspectSecurityGroup(id)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid security group: %v", *res)
+	}
+	return res, err
+}
+
+// ListSecurityGroups ...
+func (w ValidatedProvider) ListSecurityGroups() (res []*resources.SecurityGroup, err error) {
+	res, err = w.InnerProvider.ListSecurityGroups()
+	if err == nil {
+		for _, item := range res {
+			if item != nil && !item.OK() {
+				logrus.Warnf("Invalid security group: %v", *item)
+			}
+		}
+	}
+	return res, err
+}
+
+// DeleteSecurityGroup ...
+func (w ValidatedProvider) DeleteSecurityGroup(id string) (err error) {
+	return w.InnerProvider.DeleteSecurityGroup(id)
+}
+
+// AddRuleToSecurityGroup ...
+func (w ValidatedProvider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (res *resources.SecurityGroup, err error) {
+	res, err = w.InnerProvider.AddRuleToSecurityGroup(id, rule)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid security group: %v", *res)
+	}
+	return res, err
+}
+
+// DeleteRuleFromSecurityGroup ...
+func (w ValidatedProvider) DeleteRuleFromSecurityGroup(id, ruleID string) (res *resources.SecurityGroup, err error) {
+	res, err = w.InnerProvider.DeleteRuleFromSecurityGroup(id, ruleID)
+	if err == nil && res != nil && !res.OK() {
+		logrus.Warnf("Invalid security group: %v", *res)
+	}
+	return res, err
+}
+
+// BindSecurityGroupToHost ...
+func (w ValidatedProvider) BindSecurityGroupToHost(id, hostID string) (err error) {
+	return w.InnerProvider.BindSecurityGroupToHost(id, hostID)
+}
+
+// UnbindSecurityGroupFromHost ...
+func (w ValidatedProvider) UnbindSecurityGroupFromHost(id, hostID string) (err error) {
+	return w.InnerProvider.UnbindSecurityGroupFromHost(id, hostID)
+}
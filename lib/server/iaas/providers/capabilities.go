@@ -24,4 +24,9 @@ type Capabilities struct {
 	PrivateVirtualIP bool
 	// Layer3Networking indicates if the provider uses Layer3 networking
 	Layer3Networking bool
+	// SimulatedHosts indicates if the hosts are simulated by the provider and cannot be reached with SSH; the steps
+	// run on the hosts during their creation are then skipped
+	SimulatedHosts bool
+	// OfflineVolumeResize indicates if a volume has to be detached from its host before being resized
+	OfflineVolumeResize bool
 }
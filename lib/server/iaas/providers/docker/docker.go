@@ -0,0 +1,165 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"os"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	apiprovider "github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/docker"
+)
+
+const providerName = "docker"
+
+// provider is the provider implementation of the Docker provider, mapping hosts to containers
+type provider struct {
+	*docker.Stack
+
+	tenantParameters map[string]interface{}
+}
+
+// New creates a new instance of Docker provider
+func New() apiprovider.Provider {
+	return &provider{}
+}
+
+// Build build a new Client from configuration parameter
+func (p *provider) Build(params map[string]interface{}) (apiprovider.Provider, error) {
+	tenantName, _ := params["name"].(string)
+
+	computeCfg, _ := params["compute"].(map[string]interface{})
+	networkCfg, _ := params["network"].(map[string]interface{})
+
+	endpoint, _ := computeCfg["Endpoint"].(string)
+	if endpoint == "" {
+		endpoint = os.Getenv("DOCKER_HOST")
+	}
+	apiVersion, _ := computeCfg["APIVersion"].(string)
+	defaultImage, _ := computeCfg["DefaultImage"].(string)
+	operatorUsername := resources.DefaultUser
+	if operatorUsernameIf, ok := computeCfg["OperatorUsername"]; ok {
+		operatorUsername = operatorUsernameIf.(string)
+	}
+	publicNetwork, _ := networkCfg["PublicNetwork"].(string)
+
+	authOptions := stacks.AuthenticationOptions{
+		ProjectName:      tenantName,
+		Region:           "local",
+		AvailabilityZone: "local",
+	}
+
+	dockerOptions := stacks.DockerConfiguration{
+		Endpoint:      endpoint,
+		APIVersion:    apiVersion,
+		PublicNetwork: publicNetwork,
+	}
+
+	metadataBucketName, err := objectstorage.BuildMetadataBucketName(providerName, "local", "", tenantName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgOptions := stacks.ConfigurationOptions{
+		DNSList: []string{"8.8.8.8", "1.1.1.1"},
+		VolumeSpeeds: map[string]volumespeed.Enum{
+			"standard":   volumespeed.COLD,
+			"performant": volumespeed.HDD,
+			"ssd":        volumespeed.SSD,
+		},
+		MetadataBucket:   metadataBucketName,
+		DefaultImage:     defaultImage,
+		OperatorUsername: operatorUsername,
+		ProviderName:     providerName,
+	}
+
+	stack, err := docker.New(authOptions, dockerOptions, cfgOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	newP := &provider{
+		Stack:            stack,
+		tenantParameters: params,
+	}
+
+	etrace := apiprovider.NewErrorTraceProvider(newP, providerName)
+	prov := apiprovider.NewLoggedProvider(etrace, providerName)
+	return prov, nil
+}
+
+// GetAuthenticationOptions returns the auth options
+func (p *provider) GetAuthenticationOptions() (providers.Config, error) {
+	cfg := providers.ConfigMap{}
+
+	opts := p.Stack.GetAuthenticationOptions()
+	cfg.Set("ProjectName", opts.ProjectName)
+	cfg.Set("Region", opts.Region)
+	cfg.Set("AvailabilityZone", opts.AvailabilityZone)
+	cfg.Set("Endpoint", p.Stack.DockerConfig.Endpoint)
+	return cfg, nil
+}
+
+// GetConfigurationOptions return configuration parameters
+func (p *provider) GetConfigurationOptions() (providers.Config, error) {
+	cfg := providers.ConfigMap{}
+
+	opts := p.Stack.GetConfigurationOptions()
+	cfg.Set("DNSList", opts.DNSList)
+	cfg.Set("AutoHostNetworkInterfaces", opts.AutoHostNetworkInterfaces)
+	cfg.Set("UseLayer3Networking", opts.UseLayer3Networking)
+	cfg.Set("DefaultImage", opts.DefaultImage)
+	cfg.Set("MetadataBucketName", opts.MetadataBucket)
+	cfg.Set("OperatorUsername", opts.OperatorUsername)
+	cfg.Set("ProviderName", p.GetName())
+	cfg.Set("PublicNetwork", p.Stack.DockerConfig.PublicNetwork)
+	return cfg, nil
+}
+
+// GetName returns the providerName
+func (p *provider) GetName() string {
+	return providerName
+}
+
+// ListImages ...
+func (p *provider) ListImages(all bool) ([]resources.Image, error) {
+	return p.Stack.ListImages()
+}
+
+// ListTemplates ...
+func (p *provider) ListTemplates(all bool) ([]resources.HostTemplate, error) {
+	return p.Stack.ListTemplates()
+}
+
+// GetTenantParameters returns the tenant parameters as-is
+func (p *provider) GetTenantParameters() map[string]interface{} {
+	return p.tenantParameters
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (p *provider) GetCapabilities() providers.Capabilities {
+	return providers.Capabilities{}
+}
+
+func init() {
+	iaas.Register(providerName, &provider{})
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	apiprovider "github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/fake"
+)
+
+const providerName = "fake"
+
+var (
+	// instances contains the stacks built, by tenant name, to allow tests to change their behavior
+	instances      = map[string]*fake.Stack{}
+	instancesMutex sync.Mutex
+)
+
+// provider is the provider implementation of the fake provider, keeping every resource in memory
+type provider struct {
+	*fake.Stack
+
+	tenantParameters map[string]interface{}
+}
+
+// New creates a new instance of fake provider
+func New() apiprovider.Provider {
+	return &provider{}
+}
+
+// GetStack returns the fake stack used by the tenant named tenantName, if it has been built
+func GetStack(tenantName string) (*fake.Stack, bool) {
+	instancesMutex.Lock()
+	defer instancesMutex.Unlock()
+	stack, ok := instances[tenantName]
+	return stack, ok
+}
+
+// Build build a new Client from configuration parameter
+func (p *provider) Build(params map[string]interface{}) (apiprovider.Provider, error) {
+	tenantName, _ := params["name"].(string)
+
+	computeCfg, _ := params["compute"].(map[string]interface{})
+
+	region, _ := computeCfg["Region"].(string)
+	if region == "" {
+		region = "fake-region"
+	}
+	zone, _ := computeCfg["AvailabilityZone"].(string)
+	defaultImage, _ := computeCfg["DefaultImage"].(string)
+	operatorUsername := resources.DefaultUser
+	if operatorUsernameIf, ok := computeCfg["OperatorUsername"]; ok {
+		operatorUsername = operatorUsernameIf.(string)
+	}
+
+	behavior := fake.Behavior{}
+	if latency, ok := computeCfg["Latency"].(string); ok {
+		var err error
+		behavior.Latency, err = time.ParseDuration(latency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for 'Latency' in section compute: %v", latency, err)
+		}
+	}
+	switch errorRate := computeCfg["ErrorRate"].(type) {
+	case nil:
+	case float64:
+		behavior.ErrorRate = errorRate
+	case int64:
+		behavior.ErrorRate = float64(errorRate)
+	default:
+		return nil, fmt.Errorf("invalid value '%v' for 'ErrorRate' in section compute: must be a number between 0 and 1", errorRate)
+	}
+	if behavior.ErrorRate < 0 || behavior.ErrorRate > 1 {
+		return nil, fmt.Errorf("invalid value '%v' for 'ErrorRate' in section compute: must be a number between 0 and 1", behavior.ErrorRate)
+	}
+
+	authOptions := stacks.AuthenticationOptions{
+		ProjectName:      tenantName,
+		Region:           region,
+		AvailabilityZone: zone,
+	}
+
+	metadataBucketName, err := objectstorage.BuildMetadataBucketName(providerName, region, "", tenantName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgOptions := stacks.ConfigurationOptions{
+		DNSList: []string{"8.8.8.8", "1.1.1.1"},
+		VolumeSpeeds: map[string]volumespeed.Enum{
+			"standard":   volumespeed.COLD,
+			"performant": volumespeed.HDD,
+			"ssd":        volumespeed.SSD,
+		},
+		MetadataBucket:   metadataBucketName,
+		DefaultImage:     defaultImage,
+		OperatorUsername: operatorUsername,
+		ProviderName:     providerName,
+	}
+
+	stack := fake.New(authOptions, cfgOptions, behavior)
+	instancesMutex.Lock()
+	instances[tenantName] = stack
+	instancesMutex.Unlock()
+
+	newP := &provider{
+		Stack:            stack,
+		tenantParameters: params,
+	}
+
+	etrace := apiprovider.NewErrorTraceProvider(newP, providerName)
+	prov := apiprovider.NewLoggedProvider(etrace, providerName)
+	return prov, nil
+}
+
+// GetAuthenticationOptions returns the auth options
+func (p *provider) GetAuthenticationOptions() (providers.Config, error) {
+	cfg := providers.ConfigMap{}
+
+	opts := p.Stack.GetAuthenticationOptions()
+	cfg.Set("ProjectName", opts.ProjectName)
+	cfg.Set("Region", opts.Region)
+	cfg.Set("AvailabilityZone", opts.AvailabilityZone)
+	return cfg, nil
+}
+
+// GetConfigurationOptions return configuration parameters
+func (p *provider) GetConfigurationOptions() (providers.Config, error) {
+	cfg := providers.ConfigMap{}
+
+	opts := p.Stack.GetConfigurationOptions()
+	cfg.Set("DNSList", opts.DNSList)
+	cfg.Set("AutoHostNetworkInterfaces", opts.AutoHostNetworkInterfaces)
+	cfg.Set("UseLayer3Networking", opts.UseLayer3Networking)
+	cfg.Set("DefaultImage", opts.DefaultImage)
+	cfg.Set("MetadataBucketName", opts.MetadataBucket)
+	cfg.Set("OperatorUsername", opts.OperatorUsername)
+	cfg.Set("ProviderName", p.GetName())
+	return cfg, nil
+}
+
+// GetName returns the providerName
+func (p *provider) GetName() string {
+	return providerName
+}
+
+// ListImages ...
+func (p *provider) ListImages(all bool) ([]resources.Image, error) {
+	return p.Stack.ListImages()
+}
+
+// ListTemplates ...
+func (p *provider) ListTemplates(all bool) ([]resources.HostTemplate, error) {
+	return p.Stack.ListTemplates()
+}
+
+// GetTenantParameters returns the tenant parameters as-is
+func (p *provider) GetTenantParameters() map[string]interface{} {
+	return p.tenantParameters
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (p *provider) GetCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		PublicVirtualIP:  true,
+		PrivateVirtualIP: true,
+		SimulatedHosts:   true,
+	}
+}
+
+func init() {
+	iaas.Register(providerName, &provider{})
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/api"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers/fake"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/tests"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+var tester *tests.ServiceTester
+
+// getTester builds the service of a fake tenant, whose resources and metadata are kept in memory
+func getTester(t *testing.T) *tests.ServiceTester {
+	if tester == nil {
+		service, _ := tests.NewFakeService(t, "TestFake")
+		tester = &tests.ServiceTester{
+			Service: service,
+		}
+	}
+	return tester
+}
+
+func Test_Capabilities(t *testing.T) {
+	cli := getTester(t)
+	assert.True(t, cli.Service.GetCapabilities().SimulatedHosts)
+}
+
+func Test_ListHostTemplates(t *testing.T) {
+	getTester(t).ListHostTemplates(t)
+}
+
+func Test_ListKeyPairs(t *testing.T) {
+	getTester(t).ListKeyPairs(t)
+}
+
+func Test_GetKeyPair(t *testing.T) {
+	getTester(t).GetKeyPair(t)
+}
+
+func Test_Networks(t *testing.T) {
+	getTester(t).Networks(t)
+}
+
+func Test_Hosts(t *testing.T) {
+	getTester(t).Hosts(t)
+}
+
+func Test_StartStopHost(t *testing.T) {
+	getTester(t).StartStopHost(t)
+}
+
+func Test_Volume(t *testing.T) {
+	getTester(t).Volume(t)
+}
+
+func Test_VolumeAttachment(t *testing.T) {
+	getTester(t).VolumeAttachment(t)
+}
+
+func Test_VolumeSnapshots(t *testing.T) {
+	getTester(t).VolumeSnapshots(t)
+}
+
+func Test_VolumeResize(t *testing.T) {
+	getTester(t).VolumeResize(t)
+}
+
+func Test_SecurityGroups(t *testing.T) {
+	getTester(t).SecurityGroups(t)
+}
+
+func Test_Tags(t *testing.T) {
+	getTester(t).Tags(t)
+}
+
+func Test_Images(t *testing.T) {
+	getTester(t).Images(t)
+}
+
+func Test_Containers(t *testing.T) {
+	getTester(t).Containers(t)
+}
+
+func Test_ErrorInjection(t *testing.T) {
+	cli := getTester(t)
+	stack, ok := fake.GetStack("TestFake")
+	require.True(t, ok)
+
+	injected := scerr.NotAvailableError("quota exceeded")
+	stack.FailNext("CreateNetwork", injected, 1)
+	_, err := cli.Service.CreateNetwork(resources.NetworkRequest{Name: "unit_test_network_7", CIDR: "1.1.7.0/24"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "quota exceeded")
+
+	network, err := cli.Service.CreateNetwork(resources.NetworkRequest{Name: "unit_test_network_7", CIDR: "1.1.7.0/24"})
+	require.Nil(t, err)
+	defer func() {
+		_ = cli.Service.DeleteNetwork(network.ID)
+	}()
+
+	_, err = cli.Service.CreateNetwork(resources.NetworkRequest{Name: "unit_test_network_7", CIDR: "1.1.8.0/24"})
+	require.NotNil(t, err)
+	_, ok = err.(scerr.ErrDuplicate)
+	assert.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+}
+
+func Test_RecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "safescale-cassette")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "fake.cassette")
+
+	provider, err := fake.New().Build(map[string]interface{}{
+		"name":     "TestRecord",
+		"compute":  map[string]interface{}{"Region": "fake-region"},
+		"identity": map[string]interface{}{"Password": "not to be recorded"},
+	})
+	require.Nil(t, err)
+	recorder, err := api.NewRecordingProvider(provider, "fake", path)
+	require.Nil(t, err)
+
+	request := resources.NetworkRequest{Name: "unit_test_network_8", CIDR: "1.1.9.0/24"}
+	recorded, err := recorder.CreateNetwork(request)
+	require.Nil(t, err)
+	_, err = recorder.GetNetwork("unknown")
+	require.NotNil(t, err)
+	recordedList, err := recorder.ListNetworks()
+	require.Nil(t, err)
+	require.Nil(t, recorder.DeleteNetwork(recorded.ID))
+
+	content, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	assert.NotContains(t, string(content), "not to be recorded")
+
+	replayer, err := api.NewReplayProvider(path)
+	require.Nil(t, err)
+	assert.Equal(t, recorder.GetName(), replayer.GetName())
+	assert.Equal(t, recorder.GetCapabilities(), replayer.GetCapabilities())
+	cfg, err := replayer.GetConfigurationOptions()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"8.8.8.8", "1.1.1.1"}, cfg.GetSliceOfStrings("DNSList"))
+
+	replayed, err := replayer.CreateNetwork(request)
+	require.Nil(t, err)
+	assert.Equal(t, recorded.ID, replayed.ID)
+	assert.Equal(t, recorded.CIDR, replayed.CIDR)
+	assert.NotNil(t, replayed.Properties)
+	_, err = replayer.GetNetwork("unknown")
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+	replayedList, err := replayer.ListNetworks()
+	require.Nil(t, err)
+	assert.Equal(t, len(recordedList), len(replayedList))
+	assert.Nil(t, replayer.DeleteNetwork(recorded.ID))
+
+	// the calls not recorded cannot be replayed
+	_, err = replayer.GetImage("unknown")
+	_, ok = err.(scerr.ErrNotFound)
+	assert.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+}
+
+func Test_RecordSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "safescale-cassette")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "secrets.cassette")
+
+	provider, err := fake.New().Build(map[string]interface{}{
+		"name":    "TestRecordSecrets",
+		"compute": map[string]interface{}{"Region": "fake-region"},
+	})
+	require.Nil(t, err)
+	recorder, err := api.NewRecordingProvider(provider, "fake", path)
+	require.Nil(t, err)
+
+	keypair, err := recorder.CreateKeyPair("unit_test_kp")
+	require.Nil(t, err)
+	require.NotEmpty(t, keypair.PrivateKey)
+	network, err := recorder.CreateNetwork(resources.NetworkRequest{Name: "unit_test_network_9", CIDR: "1.1.10.0/24"})
+	require.Nil(t, err)
+	request := resources.HostRequest{
+		ResourceName: "unit_test_host_secrets",
+		Networks:     []*resources.Network{network},
+		PublicIP:     true,
+		TemplateID:   "fake-tiny",
+		ImageID:      "fake-ubuntu-1804",
+		KeyPair:      keypair,
+		Password:     "host console password",
+	}
+	host, _, err := recorder.CreateHost(request)
+	require.Nil(t, err)
+	require.NotEmpty(t, host.PrivateKey)
+
+	content, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	assert.NotContains(t, string(content), keypair.PrivateKey)
+	assert.NotContains(t, string(content), host.PrivateKey)
+	assert.NotContains(t, string(content), "host console password")
+	assert.Contains(t, string(content), "unit_test_host_secrets")
+
+	// The arguments of the calls are redacted the same way to find the recorded interactions
+	replayer, err := api.NewReplayProvider(path)
+	require.Nil(t, err)
+	replayed, _, err := replayer.CreateHost(request)
+	require.Nil(t, err)
+	assert.Equal(t, host.ID, replayed.ID)
+	assert.NotEqual(t, host.PrivateKey, replayed.PrivateKey)
+}
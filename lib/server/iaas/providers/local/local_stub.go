@@ -70,6 +70,12 @@ func (provider *provider) ListImages(all bool) ([]resources.Image, error) {
 func (provider *provider) GetImage(id string) (*resources.Image, error) {
 	return nil, fmt.Errorf(errorStr)
 }
+func (provider *provider) CreateImage(hostID string, name string) (*resources.Image, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteImage(id string) error {
+	return fmt.Errorf(errorStr)
+}
 
 func (provider *provider) GetTemplate(id string) (*resources.HostTemplate, error) {
 	return nil, fmt.Errorf(errorStr)
@@ -134,6 +140,9 @@ func (provider *provider) CreateHost(request resources.HostRequest) (*resources.
 func (provider *provider) ResizeHost(id string, request resources.SizingRequirements) (*resources.Host, error) {
 	return nil, fmt.Errorf(errorStr)
 }
+func (provider *provider) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	return fmt.Errorf(errorStr)
+}
 func (provider *provider) InspectHost(interface{}) (*resources.Host, error) {
 	return nil, fmt.Errorf(errorStr)
 }
@@ -171,6 +180,27 @@ func (provider *provider) ListVolumes() ([]resources.Volume, error) {
 func (provider *provider) DeleteVolume(id string) error {
 	return fmt.Errorf(errorStr)
 }
+func (provider *provider) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteVolumeSnapshot(id string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	return fmt.Errorf(errorStr)
+}
 
 func (provider *provider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error) {
 	return "", fmt.Errorf(errorStr)
@@ -184,6 +214,30 @@ func (provider *provider) ListVolumeAttachments(serverID string) ([]resources.Vo
 func (provider *provider) DeleteVolumeAttachment(serverID, id string) error {
 	return fmt.Errorf(errorStr)
 }
+func (provider *provider) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteSecurityGroup(id string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) BindSecurityGroupToHost(id, hostID string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) UnbindSecurityGroupFromHost(id, hostID string) error {
+	return fmt.Errorf(errorStr)
+}
 func (provider *provider) GetName() string {
 	return "local_disabled"
 }
@@ -80,6 +80,30 @@ type Image struct {
 	URL         string `json:"url,omitempty"`
 	Description string `json:"description,omitempty"`
 	StorageType string `json:"storagetype,omitempty"`
+	// Properties are only set on the images created by SafeScale from a host
+	Properties *serialize.JSONProperties `json:"properties,omitempty"`
+}
+
+// NewImage ...
+func NewImage() *Image {
+	return &Image{
+		Properties: serialize.NewJSONProperties("resources.image"),
+	}
+}
+
+// Serialize serializes Image instance into bytes (output json code)
+func (i *Image) Serialize() ([]byte, error) {
+	return serialize.ToJSON(i)
+}
+
+// Deserialize reads json code and restores an Image
+func (i *Image) Deserialize(buf []byte) error {
+	if i.Properties == nil {
+		i.Properties = serialize.NewJSONProperties("resources.image")
+	} else {
+		i.Properties.SetModule("resources.image")
+	}
+	return serialize.FromJSON(buf, i)
 }
 
 // HostRequest represents requirements to create host
@@ -108,6 +132,8 @@ type HostRequest struct {
 	DiskSize int
 	// Use spot instance
 	Spot bool
+	// Tags contains the user-defined key/value tags to set on the host, where the provider supports it
+	Tags map[string]string
 }
 
 // HostDefinition ...
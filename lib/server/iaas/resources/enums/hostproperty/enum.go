@@ -31,4 +31,10 @@ const (
 	SharesV1 = "6"
 	// MountsV1 contains optional additional info about mounted devices (locally attached or remote filesystem)
 	MountsV1 = "7"
+	// SecurityGroupsV1 contains optional additional info about the security groups bound to the host
+	SecurityGroupsV1 = "8"
+	// TagsV1 contains the user-defined key/value tags of the host
+	TagsV1 = "9"
+	// SSHV1 contains the SSH access to the host, when it differs from the defaults of SafeScale (adopted hosts)
+	SSHV1 = "10"
 )
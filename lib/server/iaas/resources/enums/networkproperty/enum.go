@@ -21,4 +21,6 @@ const (
 	DescriptionV1 = "1"
 	// HostsV1 contains list of hosts attached to the network
 	HostsV1 = "2"
+	// TagsV1 contains the user-defined key/value tags of the network
+	TagsV1 = "3"
 )
@@ -21,4 +21,6 @@ const (
 	DescriptionV1 = "1"
 	// AttachedV1 contains additional information about hosts attaching the volume
 	AttachedV1 = "2"
+	// TagsV1 contains the user-defined key/value tags of the volume
+	TagsV1 = "3"
 )
@@ -49,6 +49,8 @@ type NetworkRequest struct {
 	DNSServers []string
 	// HA tells if 2 gateways and a VIP needs to be created; the VIP IP address will be used as gateway
 	HA bool
+	// Tags contains the user-defined key/value tags to set on the network, where the provider supports it
+	Tags map[string]string
 }
 
 type SubNetwork struct {
@@ -661,6 +661,90 @@ func (hf *HostFeatures) Replace(p data.Clonable) data.Clonable {
 	return hf
 }
 
+// HostSecurityGroups contains information about the security groups bound to the host
+// !!! FROZEN !!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type HostSecurityGroups struct {
+	ByID   map[string]string `json:"by_id"`   // contains the name of the bound security groups, indexed by ID
+	ByName map[string]string `json:"by_name"` // contains the ID of the bound security groups, indexed by name
+}
+
+// NewHostSecurityGroups ...
+func NewHostSecurityGroups() *HostSecurityGroups {
+	return &HostSecurityGroups{
+		ByID:   map[string]string{},
+		ByName: map[string]string{},
+	}
+}
+
+// Reset resets the content of the property
+func (hsg *HostSecurityGroups) Reset() {
+	*hsg = HostSecurityGroups{
+		ByID:   map[string]string{},
+		ByName: map[string]string{},
+	}
+}
+
+// Content ...
+func (hsg *HostSecurityGroups) Content() data.Clonable {
+	return hsg
+}
+
+// Clone ...
+func (hsg *HostSecurityGroups) Clone() data.Clonable {
+	return NewHostSecurityGroups().Replace(hsg)
+}
+
+// Replace ...
+func (hsg *HostSecurityGroups) Replace(p data.Clonable) data.Clonable {
+	src := p.(*HostSecurityGroups)
+	hsg.ByID = make(map[string]string, len(src.ByID))
+	for k, v := range src.ByID {
+		hsg.ByID[k] = v
+	}
+	hsg.ByName = make(map[string]string, len(src.ByName))
+	for k, v := range src.ByName {
+		hsg.ByName[k] = v
+	}
+	return hsg
+}
+
+// HostSSH contains the SSH access to the host, when it differs from the defaults of SafeScale
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type HostSSH struct {
+	User string `json:"user,omitempty"` // User is the user used to access the host (the operator user of the tenant if empty)
+	Port int    `json:"port,omitempty"` // Port is the port of the SSH server of the host (22 if 0)
+}
+
+// NewHostSSH ...
+func NewHostSSH() *HostSSH {
+	return &HostSSH{}
+}
+
+// Reset resets the content of the property
+func (hs *HostSSH) Reset() {
+	*hs = HostSSH{}
+}
+
+// Content ...
+func (hs *HostSSH) Content() data.Clonable {
+	return hs
+}
+
+// Clone ...
+func (hs *HostSSH) Clone() data.Clonable {
+	return NewHostSSH().Replace(hs)
+}
+
+// Replace ...
+func (hs *HostSSH) Replace(p data.Clonable) data.Clonable {
+	*hs = *p.(*HostSSH)
+	return hs
+}
+
 func init() {
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.DescriptionV1, NewHostDescription())
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.NetworkV1, NewHostNetwork())
@@ -669,4 +753,6 @@ func init() {
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.VolumesV1, NewHostVolumes())
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.MountsV1, NewHostMounts())
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.FeaturesV1, NewHostFeatures())
+	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.SecurityGroupsV1, NewHostSecurityGroups())
+	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.SSHV1, NewHostSSH())
 }
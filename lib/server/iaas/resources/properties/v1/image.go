@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/imageproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// ImageFeatures contains the features installed on the host an image has been created from; hosts created
+// from the image already have them
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type ImageFeatures struct {
+	HostID    string                           `json:"host_id,omitempty"`   // ID of the host the image has been created from
+	HostName  string                           `json:"host_name,omitempty"` // name of the host the image has been created from
+	Installed map[string]*HostInstalledFeature `json:"installed,omitempty"` // copy of the features installed on the host, indexed on feature name
+}
+
+// NewImageFeatures ...
+func NewImageFeatures() *ImageFeatures {
+	return &ImageFeatures{
+		Installed: map[string]*HostInstalledFeature{},
+	}
+}
+
+// Reset resets the content of the property
+func (imf *ImageFeatures) Reset() {
+	*imf = ImageFeatures{
+		Installed: map[string]*HostInstalledFeature{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (imf *ImageFeatures) Content() data.Clonable {
+	return imf
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (imf *ImageFeatures) Clone() data.Clonable {
+	return NewImageFeatures().Replace(imf)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (imf *ImageFeatures) Replace(p data.Clonable) data.Clonable {
+	src := p.(*ImageFeatures)
+	imf.HostID = src.HostID
+	imf.HostName = src.HostName
+	imf.Installed = make(map[string]*HostInstalledFeature, len(src.Installed))
+	for k, v := range src.Installed {
+		imf.Installed[k] = v.Clone().(*HostInstalledFeature)
+	}
+	return imf
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.image", imageproperty.FeaturesV1, NewImageFeatures())
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/securitygroupproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// SecurityGroupHosts contains the hosts bound to the security group
+// !!! FROZEN !!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type SecurityGroupHosts struct {
+	ByID   map[string]string `json:"by_id,omitempty"`   // Contains the name of the hosts bound to the security group, indexed by ID
+	ByName map[string]string `json:"by_name,omitempty"` // Contains the ID of the hosts bound to the security group, indexed by name
+}
+
+// NewSecurityGroupHosts ...
+func NewSecurityGroupHosts() *SecurityGroupHosts {
+	return &SecurityGroupHosts{
+		ByID:   map[string]string{},
+		ByName: map[string]string{},
+	}
+}
+
+// Reset resets the content of the property
+func (sgh *SecurityGroupHosts) Reset() {
+	*sgh = SecurityGroupHosts{
+		ByID:   map[string]string{},
+		ByName: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (sgh *SecurityGroupHosts) Content() data.Clonable {
+	return sgh
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (sgh *SecurityGroupHosts) Clone() data.Clonable {
+	return NewSecurityGroupHosts().Replace(sgh)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (sgh *SecurityGroupHosts) Replace(p data.Clonable) data.Clonable {
+	src := p.(*SecurityGroupHosts)
+	sgh.ByID = make(map[string]string, len(src.ByID))
+	for k, v := range src.ByID {
+		sgh.ByID[k] = v
+	}
+	sgh.ByName = make(map[string]string, len(src.ByName))
+	for k, v := range src.ByName {
+		sgh.ByName[k] = v
+	}
+	return sgh
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.securitygroup", securitygroupproperty.HostsV1, NewSecurityGroupHosts())
+}
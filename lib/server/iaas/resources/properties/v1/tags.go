@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// ResourceTags contains the user-defined key/value tags of a resource (host, network, volume or cluster)
+// !!! FROZEN !!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type ResourceTags struct {
+	Tags map[string]string `json:"tags,omitempty"` // tags, indexed on key
+}
+
+// NewResourceTags ...
+func NewResourceTags() *ResourceTags {
+	return &ResourceTags{
+		Tags: map[string]string{},
+	}
+}
+
+// Reset resets the content of the property
+func (rt *ResourceTags) Reset() {
+	*rt = ResourceTags{
+		Tags: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (rt *ResourceTags) Content() data.Clonable {
+	return rt
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (rt *ResourceTags) Clone() data.Clonable {
+	return NewResourceTags().Replace(rt)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (rt *ResourceTags) Replace(p data.Clonable) data.Clonable {
+	src := p.(*ResourceTags)
+	rt.Tags = make(map[string]string, len(src.Tags))
+	for k, v := range src.Tags {
+		rt.Tags[k] = v
+	}
+	return rt
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.TagsV1, NewResourceTags())
+	serialize.PropertyTypeRegistry.Register("resources.network", networkproperty.TagsV1, NewResourceTags())
+	serialize.PropertyTypeRegistry.Register("resources.volume", volumeproperty.TagsV1, NewResourceTags())
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// SecurityGroupRequest represents a security group request
+type SecurityGroupRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// NetworkID is the network the security group is created for; needed by providers scoping security groups
+	// (like AWS, where a security group belongs to a VPC)
+	NetworkID string `json:"network_id,omitempty"`
+}
+
+// SecurityGroupRule represents a rule of a security group
+type SecurityGroupRule struct {
+	ID          string             `json:"id,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Direction   ruledirection.Enum `json:"direction"`
+	EtherType   ipversion.Enum     `json:"ether_type,omitempty"`
+	// Protocol is "tcp", "udp", "icmp", or empty for all the protocols
+	Protocol string `json:"protocol,omitempty"`
+	// PortFrom and PortTo delimit the range of ports allowed (TCP and UDP only); 0 means all the ports
+	PortFrom int `json:"port_from,omitempty"`
+	PortTo   int `json:"port_to,omitempty"`
+	// CIDR is the range of addresses the traffic comes from (ingress) or goes to (egress)
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// Validate checks the content of the rule, and sets the default values of the fields not set
+func (r *SecurityGroupRule) Validate() error {
+	switch r.Protocol {
+	case "tcp", "udp":
+	case "", "icmp":
+		if r.PortFrom != 0 || r.PortTo != 0 {
+			return scerr.InvalidParameterError("rule.Protocol", "ports can only be set with protocols 'tcp' and 'udp'")
+		}
+	default:
+		return scerr.InvalidParameterError("rule.Protocol", fmt.Sprintf("'%s' is not a valid protocol; must be 'tcp', 'udp', 'icmp' or empty", r.Protocol))
+	}
+	if r.PortFrom < 0 || r.PortFrom > 65535 || r.PortTo < 0 || r.PortTo > 65535 {
+		return scerr.InvalidParameterError("rule.PortFrom", "ports must be between 0 and 65535")
+	}
+	if r.PortTo == 0 {
+		r.PortTo = r.PortFrom
+	}
+	if r.PortTo < r.PortFrom {
+		return scerr.InvalidParameterError("rule.PortTo", "cannot be lower than PortFrom")
+	}
+	if r.CIDR == "" {
+		r.CIDR = "0.0.0.0/0"
+		if r.EtherType == ipversion.IPv6 {
+			r.CIDR = "::/0"
+		}
+	}
+	ip, _, err := net.ParseCIDR(r.CIDR)
+	if err != nil {
+		return scerr.InvalidParameterError("rule.CIDR", fmt.Sprintf("'%s' is not a valid CIDR", r.CIDR))
+	}
+	if r.EtherType != ipversion.IPv6 {
+		r.EtherType = ipversion.IPv4
+	}
+	if (ip.To4() == nil) != (r.EtherType == ipversion.IPv6) {
+		return scerr.InvalidParameterError("rule.CIDR", fmt.Sprintf("'%s' is not an IPv%d CIDR", r.CIDR, r.EtherType))
+	}
+	return nil
+}
+
+// SecurityGroup represents a set of rules filtering the network traffic of the hosts bound to it
+type SecurityGroup struct {
+	ID          string                    `json:"id,omitempty"`
+	Name        string                    `json:"name,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	NetworkID   string                    `json:"network_id,omitempty"`
+	Rules       []SecurityGroupRule       `json:"rules,omitempty"`
+	Properties  *serialize.JSONProperties `json:"properties,omitempty"`
+}
+
+// NewSecurityGroup ...
+func NewSecurityGroup() *SecurityGroup {
+	return &SecurityGroup{
+		Properties: serialize.NewJSONProperties("resources.securitygroup"),
+	}
+}
+
+// OK ...
+func (sg *SecurityGroup) OK() bool {
+	result := true
+	result = result && sg.ID != ""
+	result = result && sg.Name != ""
+	result = result && sg.Properties != nil
+	return result
+}
+
+// Serialize serializes SecurityGroup instance into bytes (output json code)
+func (sg *SecurityGroup) Serialize() ([]byte, error) {
+	return serialize.ToJSON(sg)
+}
+
+// Deserialize reads json code and restores a SecurityGroup
+func (sg *SecurityGroup) Deserialize(buf []byte) error {
+	if sg.Properties == nil {
+		sg.Properties = serialize.NewJSONProperties("resources.securitygroup")
+	} else {
+		sg.Properties.SetModule("resources.securitygroup")
+	}
+	return serialize.FromJSON(buf, sg)
+}
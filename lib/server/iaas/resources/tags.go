@@ -0,0 +1,173 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// maxTagLength is the maximum length of a tag key or value, the lowest limit among the providers (GCP labels)
+const maxTagLength = 63
+
+// ValidateTags checks that tags can be used as labels of a resource
+func ValidateTags(tags map[string]string) error {
+	for k, v := range tags {
+		if k == "" {
+			return scerr.InvalidParameterError("tags", "tag key cannot be empty string")
+		}
+		if strings.ContainsAny(k, "=!, ") {
+			return scerr.InvalidParameterError("tags", fmt.Sprintf("tag key '%s' cannot contain '=', '!', ',' or space", k))
+		}
+		if strings.ContainsAny(v, ", ") {
+			return scerr.InvalidParameterError("tags", fmt.Sprintf("value of tag '%s' cannot contain ',' or space", k))
+		}
+		if len(k) > maxTagLength || len(v) > maxTagLength {
+			return scerr.InvalidParameterError("tags", fmt.Sprintf("key and value of tag '%s' cannot be longer than %d characters", k, maxTagLength))
+		}
+	}
+	return nil
+}
+
+// ParseTags converts a list of 'key=value' strings to tags; a missing value means empty string
+func ParseTags(list []string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, item := range list {
+		parts := strings.SplitN(item, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		value := ""
+		if len(parts) == 2 {
+			value = strings.TrimSpace(parts[1])
+		}
+		tags[key] = value
+	}
+	if err := ValidateTags(tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// MergeTags returns the tags resulting of the update of current with set, then the removal of the keys in unset
+func MergeTags(current map[string]string, set map[string]string, unset []string) map[string]string {
+	result := make(map[string]string, len(current)+len(set))
+	for k, v := range current {
+		result[k] = v
+	}
+	for k, v := range set {
+		result[k] = v
+	}
+	for _, k := range unset {
+		delete(result, k)
+	}
+	return result
+}
+
+// tagRequirement is one of the comma-separated terms of a TagSelector
+type tagRequirement struct {
+	key    string
+	value  string
+	negate bool // 'key!=value', or '!key' if hasValue is false
+	// hasValue is false when only the existence of the key is checked ('key' or '!key')
+	hasValue bool
+}
+
+func (r tagRequirement) matches(tags map[string]string) bool {
+	value, ok := tags[r.key]
+	if !r.hasValue {
+		return ok != r.negate
+	}
+	if r.negate {
+		return !ok || value != r.value
+	}
+	return ok && value == r.value
+}
+
+func (r tagRequirement) String() string {
+	switch {
+	case !r.hasValue && r.negate:
+		return "!" + r.key
+	case !r.hasValue:
+		return r.key
+	case r.negate:
+		return r.key + "!=" + r.value
+	default:
+		return r.key + "=" + r.value
+	}
+}
+
+// TagSelector selects resources on their tags, using an expression like 'env=prod,team=geo'.
+// Each comma-separated term must be satisfied; supported terms are 'key=value', 'key!=value',
+// 'key' (the tag is set) and '!key' (the tag is not set)
+// An empty TagSelector selects everything.
+type TagSelector []tagRequirement
+
+// ParseTagSelector converts a selector expression to TagSelector
+func ParseTagSelector(expr string) (TagSelector, error) {
+	var selector TagSelector
+	for _, term := range strings.Split(expr, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var r tagRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			r = tagRequirement{key: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1]), negate: true, hasValue: true}
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			r = tagRequirement{key: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1]), hasValue: true}
+		case strings.HasPrefix(term, "!"):
+			r = tagRequirement{key: strings.TrimSpace(term[1:]), negate: true}
+		default:
+			r = tagRequirement{key: term}
+		}
+		if r.key == "" || strings.ContainsAny(r.key, "=!") {
+			return nil, scerr.InvalidParameterError("selector", fmt.Sprintf("invalid term '%s'", term))
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// Matches tells if tags satisfy all the terms of the selector
+func (ts TagSelector) Matches(tags map[string]string) bool {
+	for _, r := range ts {
+		if !r.matches(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty tells if the selector selects everything
+func (ts TagSelector) Empty() bool {
+	return len(ts) == 0
+}
+
+// String returns the canonical form of the selector
+func (ts TagSelector) String() string {
+	terms := make([]string, 0, len(ts))
+	for _, r := range ts {
+		terms = append(terms, r.String())
+	}
+	sort.Strings(terms)
+	return strings.Join(terms, ",")
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagSelector(t *testing.T) {
+	tags := map[string]string{"env": "prod", "team": "geo"}
+
+	cases := map[string]bool{
+		"":                   true,
+		"env=prod":           true,
+		"env=prod,team=geo":  true,
+		" env = prod , team": true,
+		"env=dev":            false,
+		"env!=dev":           true,
+		"env!=prod":          false,
+		"owner!=me":          true,
+		"!owner":             true,
+		"!env":               false,
+		"owner":              false,
+		"env=prod,team=maps": false,
+	}
+	for expr, expected := range cases {
+		selector, err := ParseTagSelector(expr)
+		require.Nil(t, err, expr)
+		assert.Equal(t, expected, selector.Matches(tags), expr)
+	}
+
+	for _, expr := range []string{"=prod", "!", "env=prod,!=geo"} {
+		_, err := ParseTagSelector(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags([]string{"env=prod", "team = geo", "flag"})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "geo", "flag": ""}, tags)
+
+	_, err = ParseTags([]string{"=prod"})
+	assert.NotNil(t, err)
+	_, err = ParseTags([]string{"env=prod,dev"})
+	assert.NotNil(t, err)
+}
+
+func TestMergeTags(t *testing.T) {
+	current := map[string]string{"env": "prod", "team": "geo"}
+	merged := MergeTags(current, map[string]string{"env": "dev", "owner": "me"}, []string{"team"})
+	assert.Equal(t, map[string]string{"env": "dev", "owner": "me"}, merged)
+	assert.Equal(t, "prod", current["env"])
+}
@@ -17,6 +17,8 @@
 package resources
 
 import (
+	"time"
+
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
 	"github.com/CS-SI/SafeScale/lib/utils/serialize"
@@ -24,9 +26,10 @@ import (
 
 // VolumeRequest represents a volume request
 type VolumeRequest struct {
-	Name  string           `json:"name,omitempty"`
-	Size  int              `json:"size,omitempty"`
-	Speed volumespeed.Enum `json:"speed,omitempty"`
+	Name  string            `json:"name,omitempty"`
+	Size  int               `json:"size,omitempty"`
+	Speed volumespeed.Enum  `json:"speed,omitempty"`
+	Tags  map[string]string `json:"tags,omitempty"`
 }
 
 // Volume represents a block volume
@@ -105,3 +108,54 @@ func (va VolumeAttachment) OK() bool {
 	result = result && va.Format != ""
 	return result
 }
+
+// VolumeSnapshotRequest represents a volume snapshot request
+type VolumeSnapshotRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	VolumeID    string `json:"volume_id,omitempty"`
+}
+
+// VolumeSnapshot represents a point-in-time copy of a block volume
+type VolumeSnapshot struct {
+	ID          string                    `json:"id,omitempty"`
+	Name        string                    `json:"name,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	VolumeID    string                    `json:"volume_id,omitempty"`
+	VolumeName  string                    `json:"volume_name,omitempty"`
+	Size        int                       `json:"size,omitempty"`
+	State       volumestate.Enum          `json:"state,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at,omitempty"`
+	Properties  *serialize.JSONProperties `json:"properties,omitempty"`
+}
+
+// NewVolumeSnapshot ...
+func NewVolumeSnapshot() *VolumeSnapshot {
+	return &VolumeSnapshot{
+		Properties: serialize.NewJSONProperties("resources.volumesnapshot"),
+	}
+}
+
+// OK ...
+func (vs *VolumeSnapshot) OK() bool {
+	result := true
+	result = result && vs.ID != ""
+	result = result && vs.Name != ""
+	result = result && vs.VolumeID != ""
+	return result
+}
+
+// Serialize serializes VolumeSnapshot instance into bytes (output json code)
+func (vs *VolumeSnapshot) Serialize() ([]byte, error) {
+	return serialize.ToJSON(vs)
+}
+
+// Deserialize reads json code and restores a VolumeSnapshot
+func (vs *VolumeSnapshot) Deserialize(buf []byte) error {
+	if vs.Properties == nil {
+		vs.Properties = serialize.NewJSONProperties("resources.volumesnapshot")
+	} else {
+		vs.Properties.SetModule("resources.volumesnapshot")
+	}
+	return serialize.FromJSON(buf, vs)
+}
@@ -59,6 +59,9 @@ type Service interface {
 	FilterImages(string) ([]resources.Image, error)
 	GetMetadataKey() *crypt.Key
 	GetMetadataBucket() objectstorage.Bucket
+	GetMetadataHistorySize() int
+	GetThrottlingStatus() providers.ThrottlingStatus
+	InvalidateCache()
 	ListHostsByName() (map[string]*resources.Host, error)
 	SearchImage(string) (*resources.Image, error)
 	SelectTemplatesBySize(resources.SizingRequirements, bool) ([]*resources.HostTemplate, error)
@@ -78,8 +81,11 @@ type Service interface {
 type service struct {
 	providers.Provider
 	objectstorage.Location
-	metadataBucket objectstorage.Bucket
-	metadataKey    *crypt.Key
+	metadataBucket  objectstorage.Bucket
+	metadataKey     *crypt.Key
+	metadataHistory int
+	throttling      *providers.ThrottledProvider
+	cache           *providers.CachedProvider
 
 	whitelistTemplateRE *regexp.Regexp
 	blacklistTemplateRE *regexp.Regexp
@@ -129,10 +135,30 @@ func (svc *service) GetMetadataBucket() objectstorage.Bucket {
 	return svc.metadataBucket
 }
 
+// InvalidateCache empties the cache of the answers of the provider (images, templates, availability zones and regions)
+func (svc *service) InvalidateCache() {
+	if svc.cache != nil {
+		svc.cache.Invalidate()
+	}
+}
+
+// GetThrottlingStatus returns the rate limits of the calls to the provider and the state of its circuit breaker
+func (svc *service) GetThrottlingStatus() providers.ThrottlingStatus {
+	if svc.throttling == nil {
+		return providers.ThrottlingStatus{}
+	}
+	return svc.throttling.GetThrottlingStatus()
+}
+
 func (svc *service) GetMetadataKey() *crypt.Key {
 	return svc.metadataKey
 }
 
+// GetMetadataHistorySize returns the number of revisions kept in the history of each metadata object (0 if history is disabled)
+func (svc *service) GetMetadataHistorySize() int {
+	return svc.metadataHistory
+}
+
 // SetProvider allows to change provider interface of service object (mainly for test purposes)
 func (svc *service) SetProvider(provider providers.Provider) {
 	svc.Provider = provider
@@ -576,6 +602,10 @@ func (svc *service) SearchImage(osname string) (*resources.Image, error) {
 			return &img, nil
 		}
 	}
+	// Images created from a host are not always listed by the provider, but may be used by ID
+	if img, err := svc.GetImage(osname); err == nil && img != nil && img.ID == osname {
+		return img, nil
+	}
 
 	maxscore := 0.0
 	maxi := -1
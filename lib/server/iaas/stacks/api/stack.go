@@ -35,6 +35,10 @@ type Stack interface {
 
 	// GetImage returns the Image referenced by id
 	GetImage(id string) (*resources.Image, error)
+	// CreateImage creates an OS image from the system disk of the host identified by hostID
+	CreateImage(hostID string, name string) (*resources.Image, error)
+	// DeleteImage deletes the OS image identified by id
+	DeleteImage(id string) error
 
 	// GetTemplate returns the Template referenced by id
 	GetTemplate(id string) (*resources.HostTemplate, error)
@@ -94,6 +98,8 @@ type Stack interface {
 	RebootHost(id string) error
 	// Resize host
 	ResizeHost(id string, request resources.SizingRequirements) (*resources.Host, error)
+	// UpdateHostTags sets the tags in set and removes the tags in unset on the host identified by id, on provider side
+	UpdateHostTags(id string, set map[string]string, unset []string) error
 
 	// CreateVolume creates a block volume
 	CreateVolume(request resources.VolumeRequest) (*resources.Volume, error)
@@ -104,6 +110,23 @@ type Stack interface {
 	// DeleteVolume deletes the volume identified by id
 	DeleteVolume(id string) error
 
+	// CreateVolumeSnapshot creates a snapshot of a block volume
+	CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error)
+	// GetVolumeSnapshot returns the volume snapshot identified by id
+	GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error)
+	// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID, or all the snapshots if volumeID is empty
+	ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error)
+	// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+	DeleteVolumeSnapshot(id string) error
+	// CreateVolumeFromSnapshot creates a block volume from the content of the snapshot identified by snapshotID
+	// (if request.Size is 0, the volume gets the size of the snapshot)
+	CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error)
+	// ResizeVolume extends the block volume identified by id to size GB; depending on the provider, the volume may
+	// have to be detached first (see providers.Capabilities.OfflineVolumeResize)
+	ResizeVolume(id string, size int) (*resources.Volume, error)
+	// UpdateVolumeTags sets the tags in set and removes the tags in unset on the volume identified by id, on provider side
+	UpdateVolumeTags(id string, set map[string]string, unset []string) error
+
 	// CreateVolumeAttachment attaches a volume to an host
 	CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error)
 	// GetVolumeAttachment returns the volume attachment identified by id
@@ -112,6 +135,23 @@ type Stack interface {
 	ListVolumeAttachments(serverID string) ([]resources.VolumeAttachment, error)
 	// DeleteVolumeAttachment deletes the volume attachment identified by id
 	DeleteVolumeAttachment(serverID, id string) error
+
+	// CreateSecurityGroup creates a security group without rules
+	CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error)
+	// InspectSecurityGroup returns the security group identified by id
+	InspectSecurityGroup(id string) (*resources.SecurityGroup, error)
+	// ListSecurityGroups lists the security groups
+	ListSecurityGroups() ([]*resources.SecurityGroup, error)
+	// DeleteSecurityGroup deletes the security group identified by id
+	DeleteSecurityGroup(id string) error
+	// AddRuleToSecurityGroup adds a rule to the security group identified by id, and returns the security group updated
+	AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error)
+	// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by id
+	DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error)
+	// BindSecurityGroupToHost applies the rules of the security group identified by id to the host identified by hostID
+	BindSecurityGroupToHost(id, hostID string) error
+	// UnbindSecurityGroupFromHost removes the rules of the security group identified by id from the host identified by hostID
+	UnbindSecurityGroupFromHost(id, hostID string) error
 }
 
 // Reserved is an interface about the methods only available to providers internally
@@ -36,6 +36,16 @@ func (sp StackProxy) GetImage(id string) (*resources.Image, error) {
 	return rv, errorTranslator(err)
 }
 
+func (sp StackProxy) CreateImage(hostID string, name string) (*resources.Image, error) {
+	rv, err := sp.InnerStack.CreateImage(hostID, name)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteImage(id string) error {
+	err := sp.InnerStack.DeleteImage(id)
+	return errorTranslator(err)
+}
+
 func (sp StackProxy) GetTemplate(id string) (*resources.HostTemplate, error) {
 	rv, err := sp.InnerStack.GetTemplate(id)
 	return rv, errorTranslator(err)
@@ -171,6 +181,11 @@ func (sp StackProxy) ResizeHost(id string, request resources.SizingRequirements)
 	return rv, errorTranslator(err)
 }
 
+func (sp StackProxy) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	err := sp.InnerStack.UpdateHostTags(id, set, unset)
+	return errorTranslator(err)
+}
+
 func (sp StackProxy) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
 	rv, err := sp.InnerStack.CreateVolume(request)
 	return rv, errorTranslator(err)
@@ -191,6 +206,41 @@ func (sp StackProxy) DeleteVolume(id string) error {
 	return errorTranslator(err)
 }
 
+func (sp StackProxy) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	rv, err := sp.InnerStack.CreateVolumeSnapshot(request)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	rv, err := sp.InnerStack.GetVolumeSnapshot(id)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	rv, err := sp.InnerStack.ListVolumeSnapshots(volumeID)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteVolumeSnapshot(id string) error {
+	err := sp.InnerStack.DeleteVolumeSnapshot(id)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	rv, err := sp.InnerStack.CreateVolumeFromSnapshot(snapshotID, request)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	rv, err := sp.InnerStack.ResizeVolume(id, size)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	err := sp.InnerStack.UpdateVolumeTags(id, set, unset)
+	return errorTranslator(err)
+}
+
 func (sp StackProxy) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error) {
 	rv, err := sp.InnerStack.CreateVolumeAttachment(request)
 	return rv, errorTranslator(err)
@@ -210,3 +260,43 @@ func (sp StackProxy) DeleteVolumeAttachment(serverID, id string) error {
 	err := sp.InnerStack.DeleteVolumeAttachment(serverID, id)
 	return errorTranslator(err)
 }
+
+func (sp StackProxy) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	rv, err := sp.InnerStack.CreateSecurityGroup(req)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	rv, err := sp.InnerStack.InspectSecurityGroup(id)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	rv, err := sp.InnerStack.ListSecurityGroups()
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteSecurityGroup(id string) error {
+	err := sp.InnerStack.DeleteSecurityGroup(id)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	rv, err := sp.InnerStack.AddRuleToSecurityGroup(id, rule)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	rv, err := sp.InnerStack.DeleteRuleFromSecurityGroup(id, ruleID)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) BindSecurityGroupToHost(id, hostID string) error {
+	err := sp.InnerStack.BindSecurityGroupToHost(id, hostID)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) UnbindSecurityGroupFromHost(id, hostID string) error {
+	err := sp.InnerStack.UnbindSecurityGroupFromHost(id, hostID)
+	return errorTranslator(err)
+}
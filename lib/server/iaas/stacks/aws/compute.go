@@ -199,9 +199,126 @@ func (s *Stack) GetImage(id string) (*resources.Image, error) {
 		}
 	}
 
+	// The images created from hosts are owned by the account, and not listed by ListImages
+	out, err := s.EC2Service.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: []*string{aws.String(id)},
+		Owners:   []*string{aws.String("self")},
+	})
+	if err == nil && len(out.Images) > 0 {
+		return &resources.Image{
+			ID:          aws.StringValue(out.Images[0].ImageId),
+			Name:        aws.StringValue(out.Images[0].Name),
+			Description: aws.StringValue(out.Images[0].Description),
+			StorageType: aws.StringValue(out.Images[0].RootDeviceType),
+		}, nil
+	}
+
 	return nil, resources.ResourceNotFoundError("Image", id)
 }
 
+// CreateImage creates an AMI from the host identified by hostID; the instance is not rebooted, so the content of
+// its disks is the one they would have after a power loss
+func (s *Stack) CreateImage(hostID string, name string) (*resources.Image, error) {
+	if hostID == "" {
+		return nil, scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	out, err := s.EC2Service.CreateImage(&ec2.CreateImageInput{
+		InstanceId: aws.String(hostID),
+		Name:       aws.String(name),
+		NoReboot:   aws.Bool(true),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case "InvalidInstanceID.NotFound", "InvalidInstanceID.Malformed":
+				return nil, resources.ResourceNotFoundError("host", hostID)
+			case "InvalidAMIName.Duplicate":
+				return nil, resources.ResourceDuplicateError("image", name)
+			}
+		}
+		return nil, err
+	}
+
+	// Waits for the AMI to be available, i.e. for the snapshots of its volumes to be completed
+	imageID := out.ImageId
+	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			dout, err := s.EC2Service.DescribeImages(&ec2.DescribeImagesInput{
+				ImageIds: []*string{imageID},
+			})
+			if err != nil {
+				return err
+			}
+			if len(dout.Images) == 0 {
+				return scerr.NotFoundError(fmt.Sprintf("image '%s' not found", aws.StringValue(imageID)))
+			}
+			switch aws.StringValue(dout.Images[0].State) {
+			case ec2.ImageStateAvailable:
+				return nil
+			case ec2.ImageStateFailed, ec2.ImageStateError, ec2.ImageStateInvalid:
+				return retry.AbortedError(fmt.Sprintf("image '%s' is in state '%s'", name, aws.StringValue(dout.Images[0].State)), nil)
+			default:
+				return scerr.NotAvailableError(fmt.Sprintf("image '%s' is not yet available", name))
+			}
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	if retryErr != nil {
+		derr := s.DeleteImage(aws.StringValue(imageID))
+		if derr != nil {
+			retryErr = scerr.AddConsequence(retryErr, derr)
+		}
+		return nil, retryErr
+	}
+
+	return &resources.Image{
+		ID:          aws.StringValue(imageID),
+		Name:        name,
+		StorageType: "ebs",
+	}, nil
+}
+
+// DeleteImage deregisters the AMI identified by id and deletes the snapshots of its volumes
+func (s *Stack) DeleteImage(id string) error {
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	out, err := s.EC2Service.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == "InvalidAMIID.NotFound" || aerr.Code() == "InvalidAMIID.Malformed") {
+			return resources.ResourceNotFoundError("image", id)
+		}
+		return err
+	}
+	if len(out.Images) == 0 {
+		return resources.ResourceNotFoundError("image", id)
+	}
+
+	_, err = s.EC2Service.DeregisterImage(&ec2.DeregisterImageInput{ImageId: aws.String(id)})
+	if err != nil {
+		return err
+	}
+
+	// The snapshots backing the AMI are not removed by the deregistration
+	for _, bdm := range out.Images[0].BlockDeviceMappings {
+		if bdm.Ebs == nil || bdm.Ebs.SnapshotId == nil {
+			continue
+		}
+		_, err = s.EC2Service.DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: bdm.Ebs.SnapshotId})
+		if err != nil {
+			logrus.Warnf("failed to delete snapshot '%s' of image '%s': %v", aws.StringValue(bdm.Ebs.SnapshotId), id, err)
+		}
+	}
+	return nil
+}
+
 func (s *Stack) GetTemplate(id string) (*resources.HostTemplate, error) {
 	template := resources.HostTemplate{}
 
@@ -706,6 +823,12 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 			host.ID = server.ID
 			host.Name = server.Name
 
+			if len(request.Tags) > 0 {
+				if tagErr := s.updateTags(host.ID, request.Tags, nil); tagErr != nil {
+					logrus.Warnf("failed to set tags of host '%s': %v", host.Name, tagErr)
+				}
+			}
+
 			// Wait until Host is ready, not just until the build is started
 			_, err = s.WaitHostReady(host, temporal.GetLongOperationTimeout())
 			if err != nil {
@@ -212,12 +212,12 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Netw
 
 	_, err = s.EC2Service.CreateTags(&ec2.CreateTagsInput{
 		Resources: subnetIds,
-		Tags: []*ec2.Tag{
+		Tags: append([]*ec2.Tag{
 			{
 				Key:   aws.String("Name"),
 				Value: aws.String(req.Name),
 			},
-		},
+		}, toEC2Tags(req.Tags)...),
 	})
 	if err != nil {
 		logrus.Warn("Error creating tags")
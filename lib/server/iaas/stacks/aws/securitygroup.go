@@ -16,8 +16,427 @@
 
 package aws
 
-import "github.com/CS-SI/SafeScale/lib/utils/scerr"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
 
 func (s *Stack) createSecurityGroup(vpcID string, name string) (string, error) {
 	return "", scerr.NotImplementedError("createSecurityGroup() not implemented yet") // FIXME Technical debt
 }
+
+// EC2 doesn't identify the rules of a security group; the ID of a rule is built from its content, in the form
+// <direction>:<protocol>:<port from>-<port to>:<cidr>
+func ruleID(direction ruledirection.Enum, protocol string, from, to int64, cidr string) string {
+	return fmt.Sprintf("%s:%s:%d-%d:%s", strings.ToLower(direction.String()), protocol, from, to, cidr)
+}
+
+// parseRuleID rebuilds the rule from its ID
+func parseRuleID(id string) (*resources.SecurityGroupRule, error) {
+	parts := strings.SplitN(id, ":", 4)
+	if len(parts) != 4 {
+		return nil, scerr.InvalidParameterError("ruleID", fmt.Sprintf("'%s' is not a valid rule ID", id))
+	}
+	rule := resources.SecurityGroupRule{ID: id, CIDR: parts[3]}
+	switch parts[0] {
+	case "ingress":
+		rule.Direction = ruledirection.INGRESS
+	case "egress":
+		rule.Direction = ruledirection.EGRESS
+	default:
+		return nil, scerr.InvalidParameterError("ruleID", fmt.Sprintf("'%s' is not a valid rule ID", id))
+	}
+	if parts[1] != "-1" {
+		rule.Protocol = parts[1]
+	}
+	ports := strings.SplitN(parts[2], "-", 2)
+	if len(ports) == 2 && rule.Protocol != "icmp" && rule.Protocol != "" {
+		from, err := strconv.Atoi(ports[0])
+		if err != nil {
+			return nil, scerr.InvalidParameterError("ruleID", fmt.Sprintf("'%s' is not a valid rule ID", id))
+		}
+		to, err := strconv.Atoi(ports[1])
+		if err != nil {
+			return nil, scerr.InvalidParameterError("ruleID", fmt.Sprintf("'%s' is not a valid rule ID", id))
+		}
+		rule.PortFrom, rule.PortTo = from, to
+	}
+	rule.EtherType = ipversion.IPv4
+	if strings.Contains(rule.CIDR, ":") {
+		rule.EtherType = ipversion.IPv6
+	}
+	return &rule, nil
+}
+
+// toIPPermission converts a rule to an EC2 IP permission
+func toIPPermission(rule resources.SecurityGroupRule) *ec2.IpPermission {
+	perm := &ec2.IpPermission{
+		IpProtocol: aws.String("-1"),
+	}
+	switch rule.Protocol {
+	case "tcp", "udp":
+		perm.SetIpProtocol(rule.Protocol).SetFromPort(int64(rule.PortFrom)).SetToPort(int64(rule.PortTo))
+		if rule.PortFrom == 0 {
+			perm.SetFromPort(0).SetToPort(65535)
+		}
+	case "icmp":
+		perm.SetIpProtocol(rule.Protocol).SetFromPort(-1).SetToPort(-1)
+	}
+	if rule.EtherType == ipversion.IPv6 {
+		perm.SetIpv6Ranges([]*ec2.Ipv6Range{{CidrIpv6: aws.String(rule.CIDR), Description: descriptionOf(rule)}})
+	} else {
+		perm.SetIpRanges([]*ec2.IpRange{{CidrIp: aws.String(rule.CIDR), Description: descriptionOf(rule)}})
+	}
+	return perm
+}
+
+func descriptionOf(rule resources.SecurityGroupRule) *string {
+	if rule.Description == "" {
+		return nil
+	}
+	return aws.String(rule.Description)
+}
+
+// toRules converts EC2 IP permissions to rules
+func toRules(direction ruledirection.Enum, perms []*ec2.IpPermission) []resources.SecurityGroupRule {
+	var rules []resources.SecurityGroupRule
+	for _, perm := range perms {
+		protocol := aws.StringValue(perm.IpProtocol)
+		from, to := aws.Int64Value(perm.FromPort), aws.Int64Value(perm.ToPort)
+		newRule := func(etherType ipversion.Enum, cidr string, description *string) resources.SecurityGroupRule {
+			rule := resources.SecurityGroupRule{
+				ID:          ruleID(direction, protocol, from, to, cidr),
+				Description: aws.StringValue(description),
+				Direction:   direction,
+				EtherType:   etherType,
+				CIDR:        cidr,
+			}
+			if protocol != "-1" {
+				rule.Protocol = protocol
+			}
+			if protocol == "tcp" || protocol == "udp" {
+				rule.PortFrom, rule.PortTo = int(from), int(to)
+			}
+			return rule
+		}
+		for _, r := range perm.IpRanges {
+			rules = append(rules, newRule(ipversion.IPv4, aws.StringValue(r.CidrIp), r.Description))
+		}
+		for _, r := range perm.Ipv6Ranges {
+			rules = append(rules, newRule(ipversion.IPv6, aws.StringValue(r.CidrIpv6), r.Description))
+		}
+	}
+	return rules
+}
+
+// toSecurityGroup converts an EC2 security group to a resources.SecurityGroup
+func toSecurityGroup(group *ec2.SecurityGroup) *resources.SecurityGroup {
+	sg := resources.NewSecurityGroup()
+	sg.ID = aws.StringValue(group.GroupId)
+	sg.Name = aws.StringValue(group.GroupName)
+	sg.Description = aws.StringValue(group.Description)
+	sg.NetworkID = aws.StringValue(group.VpcId)
+	sg.Rules = append(toRules(ruledirection.INGRESS, group.IpPermissions), toRules(ruledirection.EGRESS, group.IpPermissionsEgress)...)
+	return sg
+}
+
+// translateSecurityGroupError converts the EC2 errors about security groups to SafeScale errors
+func translateSecurityGroupError(err error, id string) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "InvalidGroup.NotFound", "InvalidGroupId.Malformed":
+			return resources.ResourceNotFoundError("security group", id)
+		case "InvalidInstanceID.NotFound", "InvalidInstanceID.Malformed":
+			return resources.ResourceNotFoundError("host", id)
+		case "InvalidGroup.Duplicate", "InvalidPermission.Duplicate":
+			return resources.ResourceDuplicateError("security group", id)
+		case "InvalidPermission.NotFound":
+			return resources.ResourceNotFoundError("security group rule", id)
+		case "DependencyViolation":
+			return scerr.NotAvailableError(fmt.Sprintf("security group '%s' is still in use", id))
+		}
+	}
+	return scerr.Wrap(err, fmt.Sprintf("error on security group '%s'", id))
+}
+
+// vpcOf returns the ID of the VPC of the network identified by networkID (VPC or subnet);
+// if networkID is empty, returns the VPC of the tenant
+func (s *Stack) vpcOf(networkID string) (string, error) {
+	if networkID == "" {
+		nets, err := s.ListNetworks()
+		if err != nil {
+			return "", err
+		}
+		for _, n := range nets {
+			if !n.Subnet && n.Name == s.AwsConfig.NetworkName {
+				return n.ID, nil
+			}
+		}
+		return "", resources.ResourceNotFoundError("network", s.AwsConfig.NetworkName)
+	}
+	n, err := s.GetNetwork(networkID)
+	if err != nil {
+		return "", err
+	}
+	if n.Subnet {
+		return n.Parent, nil
+	}
+	return n.ID, nil
+}
+
+// CreateSecurityGroup creates a security group without rules in the VPC of the network requested
+// Note: EC2 adds by itself a rule allowing all the egress traffic; it is removed to start from an empty set
+func (s *Stack) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, scerr.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	vpcID, err := s.vpcOf(req.NetworkID)
+	if err != nil {
+		return nil, err
+	}
+	description := req.Description
+	if description == "" {
+		// EC2 requires a description
+		description = req.Name
+	}
+	out, err := s.EC2Service.CreateSecurityGroup(&ec2.CreateSecurityGroupInput{
+		Description: aws.String(description),
+		GroupName:   aws.String(req.Name),
+		VpcId:       aws.String(vpcID),
+	})
+	if err != nil {
+		return nil, translateSecurityGroupError(err, req.Name)
+	}
+	_, err = s.EC2Service.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+		GroupId: out.GroupId,
+		IpPermissions: []*ec2.IpPermission{
+			(&ec2.IpPermission{}).SetIpProtocol("-1").SetIpRanges([]*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}),
+		},
+	})
+	if err != nil {
+		_, _ = s.EC2Service.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: out.GroupId})
+		return nil, translateSecurityGroupError(err, req.Name)
+	}
+	return s.InspectSecurityGroup(aws.StringValue(out.GroupId))
+}
+
+// InspectSecurityGroup returns the security group identified by id
+func (s *Stack) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	out, err := s.EC2Service.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return nil, translateSecurityGroupError(err, id)
+	}
+	if len(out.SecurityGroups) == 0 {
+		return nil, resources.ResourceNotFoundError("security group", id)
+	}
+	return toSecurityGroup(out.SecurityGroups[0]), nil
+}
+
+// ListSecurityGroups lists the security groups, except the ones created by default by EC2
+func (s *Stack) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	out, err := s.EC2Service.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{})
+	if err != nil {
+		return nil, scerr.Wrap(err, "error listing security groups")
+	}
+	var list []*resources.SecurityGroup
+	for _, group := range out.SecurityGroups {
+		if aws.StringValue(group.GroupName) == "default" {
+			continue
+		}
+		list = append(list, toSecurityGroup(group))
+	}
+	return list, nil
+}
+
+// DeleteSecurityGroup deletes the security group identified by id
+func (s *Stack) DeleteSecurityGroup(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	_, err := s.EC2Service.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String(id)})
+	if err != nil {
+		return translateSecurityGroupError(err, id)
+	}
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by id, and returns the security group updated
+func (s *Stack) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	perms := []*ec2.IpPermission{toIPPermission(rule)}
+	var err error
+	if rule.Direction == ruledirection.EGRESS {
+		_, err = s.EC2Service.AuthorizeSecurityGroupEgress(&ec2.AuthorizeSecurityGroupEgressInput{
+			GroupId:       aws.String(id),
+			IpPermissions: perms,
+		})
+	} else {
+		_, err = s.EC2Service.AuthorizeSecurityGroupIngress(&ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId:       aws.String(id),
+			IpPermissions: perms,
+		})
+	}
+	if err != nil {
+		return nil, translateSecurityGroupError(err, id)
+	}
+	return s.InspectSecurityGroup(id)
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by id
+func (s *Stack) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	rule, err := parseRuleID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	perms := []*ec2.IpPermission{toIPPermission(*rule)}
+	if rule.Direction == ruledirection.EGRESS {
+		_, err = s.EC2Service.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+			GroupId:       aws.String(id),
+			IpPermissions: perms,
+		})
+	} else {
+		_, err = s.EC2Service.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+			GroupId:       aws.String(id),
+			IpPermissions: perms,
+		})
+	}
+	if err != nil {
+		return nil, translateSecurityGroupError(err, id)
+	}
+	return s.InspectSecurityGroup(id)
+}
+
+// hostSecurityGroups returns the IDs of the security groups the instance identified by hostID belongs to
+func (s *Stack) hostSecurityGroups(hostID string) ([]*string, error) {
+	out, err := s.EC2Service.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(hostID)},
+	})
+	if err != nil {
+		return nil, translateSecurityGroupError(err, hostID)
+	}
+	if len(out.Reservations) == 0 || len(out.Reservations[0].Instances) == 0 {
+		return nil, resources.ResourceNotFoundError("host", hostID)
+	}
+	var groups []*string
+	for _, g := range out.Reservations[0].Instances[0].SecurityGroups {
+		groups = append(groups, g.GroupId)
+	}
+	return groups, nil
+}
+
+// BindSecurityGroupToHost applies the rules of the security group identified by id to the host identified by hostID
+func (s *Stack) BindSecurityGroupToHost(id, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	groups, err := s.hostSecurityGroups(hostID)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if aws.StringValue(g) == id {
+			return nil
+		}
+	}
+	_, err = s.EC2Service.ModifyInstanceAttribute(&ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(hostID),
+		Groups:     append(groups, aws.String(id)),
+	})
+	if err != nil {
+		return translateSecurityGroupError(err, id)
+	}
+	return nil
+}
+
+// UnbindSecurityGroupFromHost removes the rules of the security group identified by id from the host identified by hostID
+// Note: EC2 requires an instance to belong to at least one security group; the last one cannot be unbound
+func (s *Stack) UnbindSecurityGroupFromHost(id, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	groups, err := s.hostSecurityGroups(hostID)
+	if err != nil {
+		return err
+	}
+	var remaining []*string
+	for _, g := range groups {
+		if aws.StringValue(g) != id {
+			remaining = append(remaining, g)
+		}
+	}
+	if len(remaining) == len(groups) {
+		return nil
+	}
+	if len(remaining) == 0 {
+		return scerr.NotAvailableError(fmt.Sprintf("security group '%s' is the last one of host '%s' and cannot be unbound", id, hostID))
+	}
+	_, err = s.EC2Service.ModifyInstanceAttribute(&ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(hostID),
+		Groups:     remaining,
+	})
+	if err != nil {
+		return translateSecurityGroupError(err, id)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// toEC2Tags converts user-defined tags to EC2 tags, sorted on key
+// The tag 'Name' is skipped, being reserved to the name of the resources created by SafeScale
+func toEC2Tags(tags map[string]string) []*ec2.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		if k == "Name" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := make([]*ec2.Tag, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, &ec2.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(tags[k]),
+		})
+	}
+	return list
+}
+
+// updateTags sets the tags in set and removes the tags in unset on the EC2 resource identified by id
+func (s *Stack) updateTags(id string, set map[string]string, unset []string) error {
+	if tags := toEC2Tags(set); len(tags) > 0 {
+		_, err := s.EC2Service.CreateTags(&ec2.CreateTagsInput{
+			Resources: []*string{aws.String(id)},
+			Tags:      tags,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var removed []*ec2.Tag
+	for _, k := range unset {
+		if k != "Name" {
+			removed = append(removed, &ec2.Tag{Key: aws.String(k)})
+		}
+	}
+	if len(removed) > 0 {
+		_, err := s.EC2Service.DeleteTags(&ec2.DeleteTagsInput{
+			Resources: []*string{aws.String(id)},
+			Tags:      removed,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateHostTags sets the tags in set and removes the tags in unset on the instance identified by id
+func (s *Stack) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	return s.updateTags(id, set, unset)
+}
+
+// UpdateVolumeTags sets the tags in set and removes the tags in unset on the volume identified by id
+func (s *Stack) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	return s.updateTags(id, set, unset)
+}
@@ -18,12 +18,16 @@ package aws
 
 import (
 	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
@@ -41,12 +45,12 @@ func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume
 
 	_, err = s.EC2Service.CreateTags(&ec2.CreateTagsInput{
 		Resources: []*string{v.VolumeId},
-		Tags: []*ec2.Tag{
+		Tags: append([]*ec2.Tag{
 			{
 				Key:   aws.String("Name"),
 				Value: aws.String(request.Name),
 			},
-		},
+		}, toEC2Tags(request.Tags)...),
 	})
 	if err != nil {
 		// FIXME Should we delete the volume if we cannot name it ?
@@ -247,3 +251,226 @@ func (s *Stack) DeleteVolumeAttachment(serverID, id string) error {
 	})
 	return err
 }
+
+func toSnapshotState(s *string) volumestate.Enum {
+	// SnapshotStatePending = "pending"
+	// SnapshotStateCompleted = "completed"
+	// SnapshotStateError = "error"
+	switch aws.StringValue(s) {
+	case "pending":
+		return volumestate.CREATING
+	case "completed":
+		return volumestate.AVAILABLE
+	case "error":
+		return volumestate.ERROR
+	}
+	return volumestate.OTHER
+}
+
+func toVolumeSnapshot(snap *ec2.Snapshot) *resources.VolumeSnapshot {
+	vs := resources.NewVolumeSnapshot()
+	vs.ID = aws.StringValue(snap.SnapshotId)
+	vs.Name = vs.ID
+	for _, tag := range snap.Tags {
+		if tag != nil && aws.StringValue(tag.Key) == "Name" {
+			vs.Name = aws.StringValue(tag.Value)
+		}
+	}
+	vs.Description = aws.StringValue(snap.Description)
+	vs.VolumeID = aws.StringValue(snap.VolumeId)
+	vs.Size = int(aws.Int64Value(snap.VolumeSize))
+	vs.State = toSnapshotState(snap.State)
+	vs.CreatedAt = aws.TimeValue(snap.StartTime)
+	return vs
+}
+
+// translateSnapshotError converts the errors of EC2 about snapshots to scerr errors
+func translateSnapshotError(err error, id string) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "InvalidSnapshot.NotFound", "InvalidSnapshotID.Malformed":
+			return resources.ResourceNotFoundError("volume snapshot", id)
+		case "InvalidVolume.NotFound", "InvalidVolumeID.Malformed":
+			return resources.ResourceNotFoundError("volume", id)
+		case "InvalidSnapshot.InUse":
+			return scerr.NotAvailableError(fmt.Sprintf("volume snapshot '%s' is in use", id))
+		}
+	}
+	return err
+}
+
+// CreateVolumeSnapshot creates an EBS snapshot of a volume; the snapshot is taken at once but remains
+// in state CREATING while EC2 copies the data
+func (s *Stack) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	snap, err := s.EC2Service.CreateSnapshot(&ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(request.VolumeID),
+		Description: aws.String(request.Description),
+	})
+	if err != nil {
+		return nil, translateSnapshotError(err, request.VolumeID)
+	}
+
+	_, err = s.EC2Service.CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{snap.SnapshotId},
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String("Name"),
+				Value: aws.String(request.Name),
+			},
+		},
+	})
+	if err != nil {
+		_, derr := s.EC2Service.DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: snap.SnapshotId})
+		if derr != nil {
+			err = scerr.AddConsequence(err, derr)
+		}
+		return nil, err
+	}
+
+	vs := toVolumeSnapshot(snap)
+	vs.Name = request.Name
+	return vs, nil
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *Stack) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	out, err := s.EC2Service.DescribeSnapshots(&ec2.DescribeSnapshotsInput{
+		SnapshotIds: []*string{aws.String(id)},
+	})
+	if err != nil {
+		return nil, translateSnapshotError(err, id)
+	}
+	if len(out.Snapshots) == 0 {
+		return nil, resources.ResourceNotFoundError("volume snapshot", id)
+	}
+	return toVolumeSnapshot(out.Snapshots[0]), nil
+}
+
+// ListVolumeSnapshots lists the snapshots owned by the account, restricted to those of the volume identified
+// by volumeID if not empty
+func (s *Stack) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	input := &ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{aws.String("self")},
+	}
+	if volumeID != "" {
+		input.Filters = []*ec2.Filter{
+			{
+				Name:   aws.String("volume-id"),
+				Values: []*string{aws.String(volumeID)},
+			},
+		}
+	}
+	var list []resources.VolumeSnapshot
+	err := s.EC2Service.DescribeSnapshotsPages(input, func(out *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		for _, snap := range out.Snapshots {
+			list = append(list, *toVolumeSnapshot(snap))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *Stack) DeleteVolumeSnapshot(id string) error {
+	_, err := s.EC2Service.DeleteSnapshot(&ec2.DeleteSnapshotInput{
+		SnapshotId: aws.String(id),
+	})
+	if err != nil {
+		return translateSnapshotError(err, id)
+	}
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a volume from the snapshot identified by snapshotID
+func (s *Stack) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	input := &ec2.CreateVolumeInput{
+		SnapshotId:       aws.String(snapshotID),
+		VolumeType:       aws.String(toVolumeType(request.Speed)),
+		AvailabilityZone: aws.String(s.AwsConfig.Zone),
+	}
+	if request.Size > 0 {
+		input.Size = aws.Int64(int64(request.Size))
+	}
+	v, err := s.EC2Service.CreateVolume(input)
+	if err != nil {
+		return nil, translateSnapshotError(err, snapshotID)
+	}
+
+	_, err = s.EC2Service.CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{v.VolumeId},
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String("Name"),
+				Value: aws.String(request.Name),
+			},
+		},
+	})
+	if err != nil {
+		_, derr := s.EC2Service.DeleteVolume(&ec2.DeleteVolumeInput{VolumeId: v.VolumeId})
+		if derr != nil {
+			err = scerr.AddConsequence(err, derr)
+		}
+		return nil, err
+	}
+
+	volume := resources.Volume{
+		ID:    aws.StringValue(v.VolumeId),
+		Name:  request.Name,
+		Size:  int(aws.Int64Value(v.Size)),
+		Speed: toVolumeSpeed(v.VolumeType),
+		State: toVolumeState(v.State),
+	}
+	return &volume, nil
+}
+
+// ResizeVolume extends an EBS volume to size GB; EC2 modifies the volume online, the new size being usable
+// as soon as the modification reaches state 'optimizing'
+func (s *Stack) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if size <= 0 {
+		return nil, scerr.InvalidParameterError("size", "must be greater than 0")
+	}
+
+	_, err := s.EC2Service.ModifyVolume(&ec2.ModifyVolumeInput{
+		VolumeId: aws.String(id),
+		Size:     aws.Int64(int64(size)),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "IncorrectModificationState" {
+			return nil, scerr.NotAvailableError(fmt.Sprintf("volume '%s' is already being modified", id))
+		}
+		return nil, translateSnapshotError(err, id)
+	}
+
+	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			out, err := s.EC2Service.DescribeVolumesModifications(&ec2.DescribeVolumesModificationsInput{
+				VolumeIds: []*string{aws.String(id)},
+			})
+			if err != nil {
+				return err
+			}
+			if len(out.VolumesModifications) == 0 {
+				return scerr.NotFoundError(fmt.Sprintf("modification of volume '%s' not found", id))
+			}
+			switch aws.StringValue(out.VolumesModifications[0].ModificationState) {
+			case ec2.VolumeModificationStateOptimizing, ec2.VolumeModificationStateCompleted:
+				return nil
+			case ec2.VolumeModificationStateFailed:
+				return retry.AbortedError(aws.StringValue(out.VolumesModifications[0].StatusMessage), nil)
+			default:
+				return scerr.NotAvailableError(fmt.Sprintf("volume '%s' is still being modified", id))
+			}
+		},
+		temporal.GetBigDelay(),
+	)
+	if retryErr != nil {
+		return nil, retryErr
+	}
+	return s.GetVolume(id)
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// client talks to the Docker Engine API
+type client struct {
+	http    *http.Client
+	baseURL string
+}
+
+// newClient creates a client of the Docker Engine API listening at endpoint
+func newClient(endpoint, apiVersion string) (*client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, scerr.InvalidParameterError("endpoint", fmt.Sprintf("'%s' is not a valid URL: %v", endpoint, err))
+	}
+
+	c := &client{
+		http: &http.Client{Timeout: temporal.GetLongOperationTimeout()},
+	}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		c.http.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+		c.baseURL = "http://docker"
+	case "tcp", "http":
+		c.baseURL = "http://" + u.Host
+	case "https":
+		c.baseURL = "https://" + u.Host
+	default:
+		return nil, scerr.InvalidParameterError("endpoint", fmt.Sprintf("scheme '%s' is not supported", u.Scheme))
+	}
+	if apiVersion != "" {
+		c.baseURL += "/v" + strings.TrimPrefix(apiVersion, "v")
+	}
+	return c, nil
+}
+
+// filters returns the query parameter 'filters' of the Docker Engine API selecting the objects having the labels
+func filters(labels ...string) url.Values {
+	content, _ := json.Marshal(map[string][]string{"label": labels})
+	return url.Values{"filters": []string{string(content)}}
+}
+
+// call sends a request with in encoded in JSON as body and decodes the JSON response in out (if not nil)
+func (c *client) call(method, path string, query url.Values, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		content, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(content)
+	}
+	return c.send(method, path, query, "application/json", body, out)
+}
+
+// send sends a request with body of type contentType and decodes the JSON response in out (if not nil)
+func (c *client) send(method, path string, query url.Values, contentType string, body io.Reader, out interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return scerr.Errorf(fmt.Sprintf("failed to reach Docker Engine API: %v", err), err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return apiError(resp.StatusCode, content)
+	}
+	if out != nil && len(content) > 0 {
+		return json.Unmarshal(content, out)
+	}
+	return nil
+}
+
+// apiError converts the error returned by Docker Engine API to an error of package scerr
+func apiError(status int, content []byte) error {
+	var body struct {
+		Message string `json:"message"`
+	}
+	msg := strings.TrimSpace(string(content))
+	if json.Unmarshal(content, &body) == nil && body.Message != "" {
+		msg = body.Message
+	}
+
+	switch status {
+	case http.StatusNotModified:
+		// The object is already in the requested state
+		return nil
+	case http.StatusBadRequest:
+		return scerr.InvalidRequestError(msg)
+	case http.StatusForbidden:
+		return scerr.ForbiddenError(msg)
+	case http.StatusNotFound:
+		return scerr.NotFoundError(msg)
+	case http.StatusConflict:
+		return scerr.DuplicateError(msg)
+	default:
+		return scerr.Errorf(fmt.Sprintf("Docker Engine API error %d: %s", status, msg), nil)
+	}
+}
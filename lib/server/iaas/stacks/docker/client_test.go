@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+func TestAPIErrors(t *testing.T) {
+	assert.Nil(t, apiError(http.StatusNotModified, nil))
+
+	_, ok := apiError(http.StatusNotFound, []byte(`{"message":"No such container: foo"}`)).(scerr.ErrNotFound)
+	assert.True(t, ok)
+	_, ok = apiError(http.StatusConflict, []byte(`{"message":"name already in use"}`)).(scerr.ErrDuplicate)
+	assert.True(t, ok)
+	_, ok = apiError(http.StatusBadRequest, []byte("bad parameter")).(scerr.ErrInvalidRequest)
+	assert.True(t, ok)
+	_, ok = apiError(http.StatusForbidden, nil).(scerr.ErrForbidden)
+	assert.True(t, ok)
+	assert.Contains(t, apiError(http.StatusInternalServerError, []byte(`{"message":"boom"}`)).Error(), "boom")
+}
+
+func TestHostStates(t *testing.T) {
+	assert.Equal(t, hoststate.STARTED, toHostState("running"))
+	assert.Equal(t, hoststate.STOPPED, toHostState("exited"))
+	assert.Equal(t, hoststate.STARTING, toHostState("created"))
+	assert.Equal(t, hoststate.ERROR, toHostState("dead"))
+	assert.Equal(t, hoststate.UNKNOWN, toHostState("whatever"))
+}
+
+func TestStackWithFakeEngine(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1.39/_ping", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/v1.39/networks", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `{"label":["safescale.network"]}`, r.URL.Query().Get("filters"))
+		_ = json.NewEncoder(w).Encode([]networkInfo{
+			{ID: "n2", Name: "net-b", Labels: map[string]string{labelNetwork: "net-b"}},
+			{ID: "n1", Name: "net-a", Labels: map[string]string{labelNetwork: "net-a"}},
+		})
+	})
+	mux.HandleFunc("/v1.39/volumes/vol", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(volumeInfo{
+			Name:   "vol",
+			Labels: map[string]string{labelVolume: "vol", labelVolumeSize: "10", labelVolumeSpeed: "2"},
+		})
+	})
+	mux.HandleFunc("/v1.39/volumes/other", func(w http.ResponseWriter, r *http.Request) {
+		// A volume not created by SafeScale
+		_ = json.NewEncoder(w).Encode(volumeInfo{Name: "other"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	stack, err := New(stacks.AuthenticationOptions{}, stacks.DockerConfiguration{Endpoint: "tcp://" + server.Listener.Addr().String()}, stacks.ConfigurationOptions{})
+	require.Nil(t, err)
+
+	networks, err := stack.ListNetworks()
+	require.Nil(t, err)
+	require.Len(t, networks, 2)
+	assert.Equal(t, "net-a", networks[0].Name)
+
+	volume, err := stack.GetVolume("vol")
+	require.Nil(t, err)
+	assert.Equal(t, 10, volume.Size)
+	assert.Equal(t, 2, int(volume.Speed))
+
+	_, err = stack.GetVolume("other")
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok)
+
+	// Volumes could never be attached, they are not created
+	_, err = stack.CreateVolume(resources.VolumeRequest{Name: "data", Size: 10})
+	_, ok = err.(scerr.ErrNotImplemented)
+	assert.True(t, ok)
+	attachments, err := stack.ListVolumeAttachments("host")
+	require.Nil(t, err)
+	assert.Empty(t, attachments)
+
+	keypairs, err := stack.ListKeyPairs()
+	require.Nil(t, err)
+	assert.Empty(t, keypairs)
+	_, err = stack.GetKeyPair("kp")
+	_, ok = err.(scerr.ErrNotFound)
+	assert.True(t, ok)
+}
@@ -0,0 +1,750 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	converters "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+const (
+	// Labels set on the containers to find again the information not kept by Docker
+	labelDefaultNetwork   = "safescale.network.default"
+	labelDefaultGateway   = "safescale.gateway"
+	labelDefaultGatewayIP = "safescale.gateway.ip"
+	labelIsGateway        = "safescale.gateway.self"
+
+	// phase1Path is the path of the script of phase 1 in the containers
+	phase1Path = "/var/tmp/user_data.phase1.sh"
+)
+
+// templates are the host templates proposed by the Docker stack
+// Note: the disk size is indicative, the containers share the disk of the Docker host
+var templates = []resources.HostTemplate{
+	{ID: "docker-tiny", Name: "docker.tiny", Cores: 1, RAMSize: 1, DiskSize: 10},
+	{ID: "docker-small", Name: "docker.small", Cores: 1, RAMSize: 2, DiskSize: 20},
+	{ID: "docker-medium", Name: "docker.medium", Cores: 2, RAMSize: 4, DiskSize: 50},
+	{ID: "docker-large", Name: "docker.large", Cores: 4, RAMSize: 8, DiskSize: 100},
+}
+
+// imageSummary is an image as listed by Docker Engine API
+type imageSummary struct {
+	ID       string `json:"Id"`
+	RepoTags []string
+	Labels   map[string]string
+}
+
+// imageInfo is an image as inspected by Docker Engine API
+type imageInfo struct {
+	ID       string `json:"Id"`
+	RepoTags []string
+	Config   struct {
+		Labels map[string]string
+	}
+}
+
+// containerConfig is the body of the request creating a container
+type containerConfig struct {
+	Hostname         string
+	Image            string
+	Labels           map[string]string
+	StopSignal       string
+	HostConfig       hostConfig
+	NetworkingConfig struct {
+		EndpointsConfig map[string]endpointSettings
+	}
+}
+
+// hostConfig contains the settings of a container depending on the Docker host
+type hostConfig struct {
+	Privileged bool              `json:",omitempty"`
+	NanoCpus   int64             `json:",omitempty"`
+	Memory     int64             `json:",omitempty"`
+	MemorySwap int64             `json:",omitempty"`
+	Tmpfs      map[string]string `json:",omitempty"`
+	Binds      []string          `json:",omitempty"`
+}
+
+// endpointSettings contains the settings of a container in a network
+type endpointSettings struct {
+	NetworkID string `json:",omitempty"`
+	IPAddress string `json:",omitempty"`
+}
+
+// containerInfo is a container as inspected by Docker Engine API
+type containerInfo struct {
+	ID    string `json:"Id"`
+	Name  string
+	State struct {
+		Status string
+	}
+	Config struct {
+		Labels map[string]string
+	}
+	HostConfig      hostConfig
+	NetworkSettings struct {
+		Networks map[string]endpointSettings
+	}
+}
+
+// containerSummary is a container as listed by Docker Engine API
+type containerSummary struct {
+	ID string `json:"Id"`
+}
+
+// ListAvailabilityZones lists the usable Availability Zones
+func (s *Stack) ListAvailabilityZones() (map[string]bool, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	return map[string]bool{"local": true}, nil
+}
+
+// ListRegions returns a list with the regions available
+func (s *Stack) ListRegions() ([]string, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	return []string{"local"}, nil
+}
+
+// ListImages lists the Docker images labelled as usable by SafeScale
+func (s *Stack) ListImages() ([]resources.Image, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var summaries []imageSummary
+	err := s.client.call("GET", "/images/json", filters(labelImage), nil, &summaries)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to list images: %v", err), err)
+	}
+	var list []resources.Image
+	for _, i := range summaries {
+		list = append(list, toImage(i.ID, i.RepoTags, i.Labels))
+	}
+	return list, nil
+}
+
+// GetImage returns the Image referenced by id
+func (s *Stack) GetImage(id string) (*resources.Image, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	var info imageInfo
+	err := s.client.call("GET", "/images/"+url.PathEscape(id)+"/json", nil, nil, &info)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("image", id)
+		}
+		return nil, err
+	}
+	if _, ok := info.Config.Labels[labelImage]; !ok {
+		return nil, resources.ResourceNotFoundError("image", id)
+	}
+	image := toImage(info.ID, info.RepoTags, info.Config.Labels)
+	return &image, nil
+}
+
+// CreateImage creates an image from a host
+func (s *Stack) CreateImage(hostID string, name string) (*resources.Image, error) {
+	return nil, scerr.NotImplementedError("CreateImage() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteImage deletes an image
+func (s *Stack) DeleteImage(id string) error {
+	return scerr.NotImplementedError("DeleteImage() not implemented yet") // FIXME Technical debt
+}
+
+// toImage converts a Docker image to a resources.Image, named after its label safescale.image
+func toImage(id string, tags []string, labels map[string]string) resources.Image {
+	image := resources.Image{ID: id, Name: labels[labelImage]}
+	if len(tags) > 0 {
+		image.URL = tags[0]
+	}
+	if image.Name == "" {
+		image.Name = image.URL
+	}
+	return image
+}
+
+// ListTemplates lists available host templates
+func (s *Stack) ListTemplates() ([]resources.HostTemplate, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	list := make([]resources.HostTemplate, len(templates))
+	copy(list, templates)
+	return list, nil
+}
+
+// GetTemplate returns the Template referenced by id
+func (s *Stack) GetTemplate(id string) (*resources.HostTemplate, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	for _, t := range templates {
+		if t.ID == id {
+			template := t
+			return &template, nil
+		}
+	}
+	return nil, resources.ResourceNotFoundError("template", id)
+}
+
+// CreateKeyPair creates a key pair
+// Note: Docker doesn't store key pairs, the public key is installed in the container by the script of phase 1
+func (s *Stack) CreateKeyPair(name string) (*resources.KeyPair, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+	return crypt.GenerateRSAKeyPair(name)
+}
+
+// GetKeyPair returns the key pair identified by id
+// Note: Docker doesn't store key pairs, none can be found
+func (s *Stack) GetKeyPair(id string) (*resources.KeyPair, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	return nil, resources.ResourceNotFoundError("keypair", id)
+}
+
+// ListKeyPairs lists available key pairs
+// Note: Docker doesn't store key pairs, the list is always empty
+func (s *Stack) ListKeyPairs() ([]resources.KeyPair, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	return []resources.KeyPair{}, nil
+}
+
+// DeleteKeyPair deletes the key pair identified by id
+func (s *Stack) DeleteKeyPair(id string) error {
+	return nil
+}
+
+// CreateHost creates a container that fulfils the request, then runs in it the script of phase 1
+func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host, userData *userdata.Content, err error) {
+	if s == nil {
+		return nil, nil, scerr.InvalidInstanceError()
+	}
+	if request.ResourceName == "" {
+		return nil, nil, scerr.InvalidParameterError("request.ResourceName", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", request.ResourceName), true).WithStopwatch().GoingIn().OnExitTrace()()
+	defer scerr.OnPanic(&err)()
+
+	userData = userdata.NewContent()
+
+	if len(request.Networks) == 0 {
+		return nil, userData, scerr.InvalidRequestError(fmt.Sprintf("the host %s must be on at least one network (even if public)", request.ResourceName))
+	}
+	if request.DefaultGateway == nil && !request.PublicIP {
+		return nil, userData, scerr.InvalidRequestError(fmt.Sprintf("the host %s must have a gateway or be public", request.ResourceName))
+	}
+
+	template, err := s.GetTemplate(request.TemplateID)
+	if err != nil {
+		return nil, userData, err
+	}
+	image, err := s.GetImage(request.ImageID)
+	if err != nil {
+		return nil, userData, err
+	}
+
+	if request.KeyPair == nil {
+		request.KeyPair, err = s.CreateKeyPair(request.ResourceName)
+		if err != nil {
+			return nil, userData, scerr.Errorf(fmt.Sprintf("failed to create host key pair: %v", err), err)
+		}
+	}
+	if request.Password == "" {
+		request.Password, err = utils.GeneratePassword(16)
+		if err != nil {
+			return nil, userData, scerr.Errorf(fmt.Sprintf("failed to generate password: %v", err), err)
+		}
+	}
+
+	// The Default Network is the first of the provided list, by convention
+	defaultNetwork := request.Networks[0]
+	isGateway := request.DefaultGateway == nil && defaultNetwork.Name != resources.SingleHostNetworkName
+	defaultGatewayID := ""
+	defaultGatewayPrivateIP := ""
+	if request.DefaultGateway != nil {
+		defaultGatewayID = request.DefaultGateway.ID
+		defaultGatewayPrivateIP = request.DefaultGateway.GetPrivateIP()
+	}
+
+	err = userData.Prepare(*s.Config, request, defaultNetwork.CIDR, "")
+	if err != nil {
+		return nil, userData, scerr.Errorf(fmt.Sprintf("failed to prepare user data content: %v", err), err)
+	}
+	userDataPhase1, err := userData.Generate("phase1")
+	if err != nil {
+		return nil, userData, err
+	}
+
+	// systemd needs a privileged container, with /run in tmpfs and the cgroups of the Docker host
+	config := containerConfig{
+		Hostname: userData.HostName,
+		Image:    image.ID,
+		Labels: map[string]string{
+			labelHost:             request.ResourceName,
+			labelTemplate:         template.ID,
+			labelDefaultNetwork:   defaultNetwork.ID,
+			labelDefaultGateway:   defaultGatewayID,
+			labelDefaultGatewayIP: defaultGatewayPrivateIP,
+			labelIsGateway:        strconv.FormatBool(isGateway),
+		},
+		StopSignal: "SIGRTMIN+3",
+		HostConfig: hostConfig{
+			Privileged: true,
+			NanoCpus:   int64(template.Cores) * int64(time.Second),
+			Memory:     int64(template.RAMSize * 1024 * 1024 * 1024),
+			MemorySwap: -1,
+			Tmpfs:      map[string]string{"/run": "", "/run/lock": ""},
+			Binds:      []string{"/sys/fs/cgroup:/sys/fs/cgroup:ro"},
+		},
+	}
+	config.NetworkingConfig.EndpointsConfig = map[string]endpointSettings{
+		defaultNetwork.ID: {NetworkID: defaultNetwork.ID},
+	}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	query := url.Values{"name": []string{request.ResourceName}}
+	err = s.client.call("POST", "/containers/create", query, config, &created)
+	if err != nil {
+		if _, ok := err.(scerr.ErrDuplicate); ok {
+			return nil, userData, resources.ResourceDuplicateError("host", request.ResourceName)
+		}
+		return nil, userData, scerr.Errorf(fmt.Sprintf("failed to create container '%s': %v", request.ResourceName, err), err)
+	}
+
+	// Starting from here, delete the container if exiting with error
+	defer func() {
+		if err != nil {
+			logrus.Infof("Cleanup, deleting host '%s'", request.ResourceName)
+			derr := s.DeleteHost(created.ID)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to delete host '%s': %v", request.ResourceName, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	for _, n := range request.Networks[1:] {
+		err = s.connect(n.ID, created.ID)
+		if err != nil {
+			return nil, userData, err
+		}
+	}
+	if request.PublicIP {
+		err = s.connect(s.DockerConfig.PublicNetwork, created.ID)
+		if err != nil {
+			return nil, userData, err
+		}
+	}
+
+	err = s.client.call("POST", "/containers/"+created.ID+"/start", nil, nil, nil)
+	if err != nil {
+		return nil, userData, scerr.Errorf(fmt.Sprintf("failed to start container '%s': %v", request.ResourceName, err), err)
+	}
+	err = s.runScript(created.ID, phase1Path, userDataPhase1)
+	if err != nil {
+		return nil, userData, scerr.Errorf(fmt.Sprintf("failed to run script of phase 1 in container '%s': %v", request.ResourceName, err), err)
+	}
+
+	host = resources.NewHost()
+	host.ID = created.ID
+	host.PrivateKey = request.KeyPair.PrivateKey
+	host.Password = request.Password
+	host, err = s.InspectHost(host)
+	if err != nil {
+		return nil, userData, err
+	}
+	return host, userData, nil
+}
+
+// connect connects the container to the network
+func (s *Stack) connect(networkID, containerID string) error {
+	body := map[string]string{"Container": containerID}
+	err := s.client.call("POST", "/networks/"+url.PathEscape(networkID)+"/connect", nil, body, nil)
+	if err != nil {
+		return scerr.Errorf(fmt.Sprintf("failed to connect container to network '%s': %v", networkID, err), err)
+	}
+	return nil
+}
+
+// runScript copies the script in the container at path and runs it in background
+func (s *Stack) runScript(containerID, path string, script []byte) error {
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	err := writer.WriteHeader(&tar.Header{
+		Name:    path[strings.LastIndex(path, "/")+1:],
+		Mode:    0755,
+		Size:    int64(len(script)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(script); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	query := url.Values{"path": []string{path[:strings.LastIndex(path, "/")]}}
+	err = s.client.send("PUT", "/containers/"+containerID+"/archive", query, "application/x-tar", &archive, nil)
+	if err != nil {
+		return err
+	}
+
+	var exec struct {
+		ID string `json:"Id"`
+	}
+	body := map[string]interface{}{"Cmd": []string{"/bin/bash", path}}
+	err = s.client.call("POST", "/containers/"+containerID+"/exec", nil, body, &exec)
+	if err != nil {
+		return err
+	}
+	return s.client.call("POST", "/exec/"+exec.ID+"/start", nil, map[string]bool{"Detach": true}, nil)
+}
+
+// hostRef returns the reference of the host designated by hostParam (string or *resources.Host)
+func hostRef(hostParam interface{}) (string, error) {
+	switch hostParam := hostParam.(type) {
+	case string:
+		if hostParam == "" {
+			return "", scerr.InvalidParameterError("hostParam", "cannot be an empty string")
+		}
+		return hostParam, nil
+	case *resources.Host:
+		if hostParam == nil {
+			return "", scerr.InvalidParameterError("hostParam", "cannot be nil")
+		}
+		if hostParam.ID != "" {
+			return hostParam.ID, nil
+		}
+		if hostParam.Name != "" {
+			return hostParam.Name, nil
+		}
+		return "", scerr.InvalidParameterError("hostParam", "must have an ID or a name")
+	default:
+		return "", scerr.InvalidParameterError("hostParam", "must be a string or a *resources.Host")
+	}
+}
+
+// inspectContainer returns the container managed by SafeScale referenced by ref (ID or name)
+func (s *Stack) inspectContainer(ref string) (*containerInfo, error) {
+	var info containerInfo
+	err := s.client.call("GET", "/containers/"+url.PathEscape(ref)+"/json", nil, nil, &info)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("host", ref)
+		}
+		return nil, err
+	}
+	if _, ok := info.Config.Labels[labelHost]; !ok {
+		return nil, resources.ResourceNotFoundError("host", ref)
+	}
+	return &info, nil
+}
+
+// InspectHost returns the host identified by id or updates content of a *resources.Host
+func (s *Stack) InspectHost(hostParam interface{}) (*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	ref, err := hostRef(hostParam)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.inspectContainer(ref)
+	if err != nil {
+		return nil, err
+	}
+	host, ok := hostParam.(*resources.Host)
+	if !ok {
+		host = resources.NewHost()
+	}
+	err = s.complementHost(host, info)
+	if err != nil {
+		return nil, err
+	}
+	return host, nil
+}
+
+// complementHost fills host with the content of the container
+func (s *Stack) complementHost(host *resources.Host, info *containerInfo) error {
+	labels := info.Config.Labels
+	host.ID = info.ID
+	host.Name = strings.TrimPrefix(info.Name, "/")
+	host.LastState = toHostState(info.State.Status)
+
+	err := host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+		if hostNetworkV1.DefaultNetworkID == "" {
+			hostNetworkV1.DefaultNetworkID = labels[labelDefaultNetwork]
+			hostNetworkV1.DefaultGatewayID = labels[labelDefaultGateway]
+			hostNetworkV1.DefaultGatewayPrivateIP = labels[labelDefaultGatewayIP]
+			hostNetworkV1.IsGateway = labels[labelIsGateway] == "true"
+		}
+		for name, endpoint := range info.NetworkSettings.Networks {
+			if name == s.DockerConfig.PublicNetwork {
+				hostNetworkV1.PublicIPv4 = endpoint.IPAddress
+				continue
+			}
+			hostNetworkV1.IPv4Addresses[endpoint.NetworkID] = endpoint.IPAddress
+			hostNetworkV1.NetworksByID[endpoint.NetworkID] = name
+			hostNetworkV1.NetworksByName[name] = endpoint.NetworkID
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return host.Properties.LockForWrite(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
+		hostSizingV1 := clonable.(*propsv1.HostSizing)
+		if hostSizingV1.Template == "" {
+			hostSizingV1.Template = labels[labelTemplate]
+		}
+		size := &propsv1.HostSize{}
+		if template, err := s.GetTemplate(labels[labelTemplate]); err == nil {
+			size = converters.ModelHostTemplateToPropertyHostSize(template)
+		}
+		if info.HostConfig.NanoCpus > 0 {
+			size.Cores = int(info.HostConfig.NanoCpus / int64(time.Second))
+		}
+		if info.HostConfig.Memory > 0 {
+			size.RAMSize = float32(info.HostConfig.Memory) / (1024 * 1024 * 1024)
+		}
+		hostSizingV1.AllocatedSize = size
+		return nil
+	})
+}
+
+// toHostState converts the status of a container to a host state
+func toHostState(status string) hoststate.Enum {
+	switch status {
+	case "created", "restarting":
+		return hoststate.STARTING
+	case "running":
+		return hoststate.STARTED
+	case "paused", "exited":
+		return hoststate.STOPPED
+	case "removing":
+		return hoststate.STOPPING
+	case "dead":
+		return hoststate.ERROR
+	default:
+		return hoststate.UNKNOWN
+	}
+}
+
+// GetHostByName returns the host identified by name
+func (s *Stack) GetHostByName(name string) (*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	host, err := s.InspectHost(name)
+	if err != nil {
+		return nil, err
+	}
+	if host.Name != name {
+		// Docker also accepts a prefix of the container ID as reference
+		return nil, resources.ResourceNotFoundError("host", name)
+	}
+	return host, nil
+}
+
+// GetHostState returns the current state of the host identified by id
+func (s *Stack) GetHostState(hostParam interface{}) (hoststate.Enum, error) {
+	if s == nil {
+		return hoststate.ERROR, scerr.InvalidInstanceError()
+	}
+	ref, err := hostRef(hostParam)
+	if err != nil {
+		return hoststate.ERROR, err
+	}
+
+	info, err := s.inspectContainer(ref)
+	if err != nil {
+		return hoststate.ERROR, err
+	}
+	return toHostState(info.State.Status), nil
+}
+
+// ListHosts lists all hosts
+func (s *Stack) ListHosts() ([]*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var summaries []containerSummary
+	query := filters(labelHost)
+	query.Set("all", "1")
+	err := s.client.call("GET", "/containers/json", query, nil, &summaries)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to list containers: %v", err), err)
+	}
+
+	var list []*resources.Host
+	for _, c := range summaries {
+		host, err := s.InspectHost(c.ID)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); ok {
+				// Deleted meanwhile
+				continue
+			}
+			return nil, err
+		}
+		list = append(list, host)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteHost deletes the container identified by id, even if running
+func (s *Stack) DeleteHost(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	query := url.Values{"force": []string{"1"}, "v": []string{"1"}}
+	err := s.client.call("DELETE", "/containers/"+url.PathEscape(id), query, nil, nil)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return resources.ResourceNotFoundError("host", id)
+		}
+		return err
+	}
+	return nil
+}
+
+// containerAction applies the action (start, stop or restart) to the container identified by id
+func (s *Stack) containerAction(id, action string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	err := s.client.call("POST", "/containers/"+url.PathEscape(id)+"/"+action, nil, nil, nil)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return resources.ResourceNotFoundError("host", id)
+		}
+		return scerr.Errorf(fmt.Sprintf("failed to %s container '%s': %v", action, id, err), err)
+	}
+	return nil
+}
+
+// StopHost stops the host identified by id
+func (s *Stack) StopHost(id string) error {
+	return s.containerAction(id, "stop")
+}
+
+// StartHost starts the host identified by id
+func (s *Stack) StartHost(id string) error {
+	return s.containerAction(id, "start")
+}
+
+// RebootHost reboots the host identified by id
+func (s *Stack) RebootHost(id string) error {
+	return s.containerAction(id, "restart")
+}
+
+// ResizeHost changes the CPU and memory limits of the container identified by id
+// Note: the disk size can't be changed, the containers share the disk of the Docker host
+func (s *Stack) ResizeHost(id string, request resources.SizingRequirements) (*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	update := hostConfig{}
+	if request.MinCores > 0 {
+		update.NanoCpus = int64(request.MinCores) * int64(time.Second)
+	}
+	if request.MinRAMSize > 0 {
+		update.Memory = int64(request.MinRAMSize * 1024 * 1024 * 1024)
+		update.MemorySwap = -1
+	}
+	err := s.client.call("POST", "/containers/"+url.PathEscape(id)+"/update", nil, update, nil)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("host", id)
+		}
+		return nil, scerr.Errorf(fmt.Sprintf("failed to resize container '%s': %v", id, err), err)
+	}
+	return s.InspectHost(id)
+}
+
+// UpdateHostTags updates the tags of the host on provider side
+func (s *Stack) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	return scerr.NotImplementedError("UpdateHostTags() not implemented yet") // FIXME Technical debt
+}
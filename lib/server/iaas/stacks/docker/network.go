@@ -0,0 +1,253 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// networkIPAMConfig is an IP address range of a Docker network
+type networkIPAMConfig struct {
+	Subnet string `json:",omitempty"`
+}
+
+// networkConfig is the body of the request creating a network
+type networkConfig struct {
+	Name           string
+	Driver         string
+	CheckDuplicate bool
+	IPAM           struct {
+		Config []networkIPAMConfig
+	}
+	Labels map[string]string
+}
+
+// networkInfo is a network as inspected or listed by Docker Engine API
+type networkInfo struct {
+	ID   string `json:"Id"`
+	Name string
+	IPAM struct {
+		Config []networkIPAMConfig
+	}
+	Labels map[string]string
+}
+
+// CreateNetwork creates a Docker bridge network named name
+func (s *Stack) CreateNetwork(req resources.NetworkRequest) (*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, scerr.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	_, ipNet, err := net.ParseCIDR(req.CIDR)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to create network '%s (%s)': %s", req.Name, req.CIDR, err.Error()), err)
+	}
+
+	config := networkConfig{
+		Name:           req.Name,
+		Driver:         "bridge",
+		CheckDuplicate: true,
+		Labels:         map[string]string{labelNetwork: req.Name},
+	}
+	config.IPAM.Config = []networkIPAMConfig{{Subnet: ipNet.String()}}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	err = s.client.call("POST", "/networks/create", nil, config, &created)
+	if err != nil {
+		if _, ok := err.(scerr.ErrDuplicate); ok {
+			return nil, resources.ResourceDuplicateError("network", req.Name)
+		}
+		return nil, scerr.Errorf(fmt.Sprintf("failed to create network '%s (%s)': %v", req.Name, req.CIDR, err), err)
+	}
+
+	network := resources.NewNetwork()
+	network.ID = created.ID
+	network.Name = req.Name
+	network.CIDR = ipNet.String()
+	network.IPVersion = ipversion.IPv4
+	return network, nil
+}
+
+// inspectNetwork returns the network managed by SafeScale referenced by ref (ID or name)
+func (s *Stack) inspectNetwork(ref string) (*networkInfo, error) {
+	var info networkInfo
+	err := s.client.call("GET", "/networks/"+url.PathEscape(ref), nil, nil, &info)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("network", ref)
+		}
+		return nil, err
+	}
+	if _, ok := info.Labels[labelNetwork]; !ok {
+		return nil, resources.ResourceNotFoundError("network", ref)
+	}
+	return &info, nil
+}
+
+// toNetwork converts a Docker network to a resources.Network
+func toNetwork(info *networkInfo) *resources.Network {
+	network := resources.NewNetwork()
+	network.ID = info.ID
+	network.Name = info.Name
+	network.IPVersion = ipversion.IPv4
+	if len(info.IPAM.Config) > 0 {
+		network.CIDR = info.IPAM.Config[0].Subnet
+	}
+	return network
+}
+
+// GetNetwork returns the network identified by id
+func (s *Stack) GetNetwork(id string) (*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	info, err := s.inspectNetwork(id)
+	if err != nil {
+		return nil, err
+	}
+	return toNetwork(info), nil
+}
+
+// GetNetworkByName returns the network identified by name
+func (s *Stack) GetNetworkByName(name string) (*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	info, err := s.inspectNetwork(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.Name != name {
+		// Docker also accepts a prefix of the network ID as reference
+		return nil, resources.ResourceNotFoundError("network", name)
+	}
+	return toNetwork(info), nil
+}
+
+// ListNetworks lists the Docker networks managed by SafeScale
+func (s *Stack) ListNetworks() ([]*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var infos []networkInfo
+	err := s.client.call("GET", "/networks", filters(labelNetwork), nil, &infos)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to list networks: %v", err), err)
+	}
+	var list []*resources.Network
+	for i := range infos {
+		list = append(list, toNetwork(&infos[i]))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteNetwork deletes the network identified by id; the network must not contain containers anymore
+func (s *Stack) DeleteNetwork(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	err := s.client.call("DELETE", "/networks/"+url.PathEscape(id), nil, nil, nil)
+	if err != nil {
+		switch err.(type) {
+		case scerr.ErrNotFound:
+			return resources.ResourceNotFoundError("network", id)
+		case scerr.ErrForbidden:
+			return scerr.NotAvailableError(fmt.Sprintf("network '%s' still contains hosts: %v", id, err))
+		}
+		return scerr.Errorf(fmt.Sprintf("failed to delete network '%s': %v", id, err), err)
+	}
+	return nil
+}
+
+// CreateGateway creates a container connected to the private network and to the public network
+func (s *Stack) CreateGateway(req resources.GatewayRequest) (*resources.Host, *userdata.Content, error) {
+	if s == nil {
+		return nil, nil, scerr.InvalidInstanceError()
+	}
+	if req.Network == nil {
+		return nil, nil, scerr.InvalidParameterError("req.Network", "cannot be nil")
+	}
+
+	name := req.Name
+	if name == "" {
+		name = "gw-" + req.Network.Name
+	}
+	return s.CreateHost(resources.HostRequest{
+		ResourceName: name,
+		Networks:     []*resources.Network{req.Network},
+		PublicIP:     true,
+		TemplateID:   req.TemplateID,
+		ImageID:      req.ImageID,
+		KeyPair:      req.KeyPair,
+	})
+}
+
+// DeleteGateway deletes the gateway identified by id
+func (s *Stack) DeleteGateway(id string) error {
+	return s.DeleteHost(id)
+}
+
+// CreateVIP creates a private virtual IP
+func (s *Stack) CreateVIP(networkID string, name string) (*resources.VirtualIP, error) {
+	return nil, scerr.NotImplementedError("CreateVIP() not implemented yet") // FIXME Technical debt
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (s *Stack) AddPublicIPToVIP(vip *resources.VirtualIP) error {
+	return scerr.NotImplementedError("AddPublicIPToVIP() not implemented yet") // FIXME Technical debt
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (s *Stack) BindHostToVIP(vip *resources.VirtualIP, hostID string) error {
+	return scerr.NotImplementedError("BindHostToVIP() not implemented yet") // FIXME Technical debt
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (s *Stack) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) error {
+	return scerr.NotImplementedError("UnbindHostFromVIP() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (s *Stack) DeleteVIP(vip *resources.VirtualIP) error {
+	return scerr.NotImplementedError("DeleteVIP() not implemented yet") // FIXME Technical debt
+}
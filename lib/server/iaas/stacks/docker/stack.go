@@ -0,0 +1,90 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+const (
+	// defaultEndpoint is the address of the local Docker Engine API
+	defaultEndpoint = "unix:///var/run/docker.sock"
+	// defaultAPIVersion is the oldest version of the Docker Engine API supporting everything used by the stack
+	defaultAPIVersion = "1.39"
+
+	// Labels set on the Docker objects managed by SafeScale
+	labelHost        = "safescale.host"
+	labelTemplate    = "safescale.template"
+	labelNetwork     = "safescale.network"
+	labelVolume      = "safescale.volume"
+	labelVolumeSize  = "safescale.volume.size"
+	labelVolumeSpeed = "safescale.volume.speed"
+	// labelImage marks the Docker images usable as SafeScale hosts; its value is the name of the OS
+	labelImage = "safescale.image"
+)
+
+// Stack is the stack mapping SafeScale hosts to Docker containers
+type Stack struct {
+	Config       *stacks.ConfigurationOptions
+	AuthOptions  *stacks.AuthenticationOptions
+	DockerConfig *stacks.DockerConfiguration
+
+	client *client
+}
+
+// New creates a stack using the Docker Engine API at dockerCfg.Endpoint
+func New(auth stacks.AuthenticationOptions, dockerCfg stacks.DockerConfiguration, cfg stacks.ConfigurationOptions) (*Stack, error) {
+	if dockerCfg.Endpoint == "" {
+		dockerCfg.Endpoint = defaultEndpoint
+	}
+	if dockerCfg.APIVersion == "" {
+		dockerCfg.APIVersion = defaultAPIVersion
+	}
+	if dockerCfg.PublicNetwork == "" {
+		dockerCfg.PublicNetwork = "bridge"
+	}
+
+	c, err := newClient(dockerCfg.Endpoint, dockerCfg.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	stack := &Stack{
+		Config:       &cfg,
+		AuthOptions:  &auth,
+		DockerConfig: &dockerCfg,
+		client:       c,
+	}
+
+	err = c.call("GET", "/_ping", nil, nil, nil)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to connect to Docker Engine at '%s': %v", dockerCfg.Endpoint, err), err)
+	}
+	return stack, nil
+}
+
+// GetConfigurationOptions ...
+func (s *Stack) GetConfigurationOptions() stacks.ConfigurationOptions {
+	return *s.Config
+}
+
+// GetAuthenticationOptions ...
+func (s *Stack) GetAuthenticationOptions() stacks.AuthenticationOptions {
+	return *s.AuthOptions
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// volumeInfo is a volume as inspected or listed by Docker Engine API
+type volumeInfo struct {
+	Name   string
+	Labels map[string]string
+}
+
+// CreateVolume refuses to create a volume: a Docker volume can only be bound to a container when the container is
+// created, and is a directory where SafeScale expects a block device to format and mount, so it could never be attached
+func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	return nil, scerr.NotImplementedError("volumes are not supported by the Docker stack: they cannot be attached to running containers")
+}
+
+// inspectVolume returns the volume managed by SafeScale named name
+func (s *Stack) inspectVolume(name string) (*volumeInfo, error) {
+	var info volumeInfo
+	err := s.client.call("GET", "/volumes/"+url.PathEscape(name), nil, nil, &info)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("volume", name)
+		}
+		return nil, err
+	}
+	if _, ok := info.Labels[labelVolume]; !ok {
+		return nil, resources.ResourceNotFoundError("volume", name)
+	}
+	return &info, nil
+}
+
+// toVolume converts a Docker volume to a resources.Volume
+func toVolume(info *volumeInfo) *resources.Volume {
+	volume := resources.NewVolume()
+	volume.ID = info.Name
+	volume.Name = info.Name
+	volume.State = volumestate.AVAILABLE
+	if size, err := strconv.Atoi(info.Labels[labelVolumeSize]); err == nil {
+		volume.Size = size
+	}
+	if speed, err := strconv.Atoi(info.Labels[labelVolumeSpeed]); err == nil {
+		volume.Speed = volumespeed.Enum(speed)
+	}
+	return volume
+}
+
+// GetVolume returns the volume identified by id
+func (s *Stack) GetVolume(id string) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	info, err := s.inspectVolume(id)
+	if err != nil {
+		return nil, err
+	}
+	return toVolume(info), nil
+}
+
+// ListVolumes lists the Docker volumes managed by SafeScale
+func (s *Stack) ListVolumes() ([]resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var result struct {
+		Volumes []volumeInfo
+	}
+	err := s.client.call("GET", "/volumes", filters(labelVolume), nil, &result)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to list volumes: %v", err), err)
+	}
+	var list []resources.Volume
+	for i := range result.Volumes {
+		list = append(list, *toVolume(&result.Volumes[i]))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteVolume deletes the volume identified by id
+func (s *Stack) DeleteVolume(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	err := s.client.call("DELETE", "/volumes/"+url.PathEscape(id), nil, nil, nil)
+	if err != nil {
+		switch err.(type) {
+		case scerr.ErrNotFound:
+			return resources.ResourceNotFoundError("volume", id)
+		case scerr.ErrDuplicate:
+			// Docker answers 409 when the volume is in use
+			return scerr.NotAvailableError(fmt.Sprintf("volume '%s' is in use: %v", id, err))
+		}
+		return scerr.Errorf(fmt.Sprintf("failed to delete volume '%s': %v", id, err), err)
+	}
+	return nil
+}
+
+// CreateVolumeAttachment attaches a volume to an host
+// Note: Docker can't mount a volume in a running container
+func (s *Stack) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	return "", scerr.NotImplementedError("volumes cannot be attached to running containers")
+}
+
+// GetVolumeAttachment returns the volume attachment identified by id
+// Note: there is none, see CreateVolumeAttachment
+func (s *Stack) GetVolumeAttachment(serverID, id string) (*resources.VolumeAttachment, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	return nil, resources.ResourceNotFoundError("volume attachment", id)
+}
+
+// ListVolumeAttachments lists available volume attachment
+// Note: there is none, see CreateVolumeAttachment
+func (s *Stack) ListVolumeAttachments(serverID string) ([]resources.VolumeAttachment, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	return []resources.VolumeAttachment{}, nil
+}
+
+// DeleteVolumeAttachment deletes the volume attachment identified by id
+// Note: there is none, see CreateVolumeAttachment
+func (s *Stack) DeleteVolumeAttachment(serverID, id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	return resources.ResourceNotFoundError("volume attachment", id)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s *Stack) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *Stack) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("GetVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume
+func (s *Stack) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *Stack) DeleteVolumeSnapshot(id string) error {
+	return scerr.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// CreateVolumeFromSnapshot creates a block volume from a snapshot
+func (s *Stack) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	return nil, scerr.NotImplementedError("CreateVolumeFromSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// ResizeVolume extends a block volume
+func (s *Stack) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	return nil, scerr.NotImplementedError("ResizeVolume() not implemented yet") // FIXME Technical debt
+}
+
+// UpdateVolumeTags updates the tags of the volume on provider side
+func (s *Stack) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	return scerr.NotImplementedError("UpdateVolumeTags() not implemented yet") // FIXME Technical debt
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stacks
+
+// DockerConfiguration configuration options of the Docker stack
+type DockerConfiguration struct {
+	// Endpoint is the address of the Docker Engine API, like "unix:///var/run/docker.sock" or "tcp://localhost:2375"
+	Endpoint string
+	// APIVersion is the version of the Docker Engine API to use
+	APIVersion string
+	// PublicNetwork is the name of the Docker network used to reach the containers from the machine running safescaled
+	PublicNetwork string
+}
@@ -110,6 +110,16 @@ func (s *StackEbrc) GetImage(id string) (*resources.Image, error) {
 	return nil, nil
 }
 
+// CreateImage creates an image from a host
+func (s *StackEbrc) CreateImage(hostID string, name string) (*resources.Image, error) {
+	return nil, scerr.NotImplementedError("CreateImage() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteImage deletes an image
+func (s *StackEbrc) DeleteImage(id string) error {
+	return scerr.NotImplementedError("DeleteImage() not implemented yet") // FIXME Technical debt
+}
+
 //-------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStackEbrc ListTemplate method to filter wind and flex instance and add GPU configuration
@@ -846,6 +856,11 @@ func (s *StackEbrc) ResizeHost(id string, request resources.SizingRequirements)
 	return nil, scerr.Errorf(fmt.Sprintf("Not implemented yet"), nil)
 }
 
+// UpdateHostTags updates the tags of the host on provider side
+func (s *StackEbrc) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	return scerr.NotImplementedError("UpdateHostTags() not implemented yet") // FIXME Technical debt
+}
+
 // ListHosts lists available hosts
 func (s *StackEbrc) ListHosts() ([]*resources.Host, error) {
 	logrus.Debug("ebrc.Client.ListHosts() called")
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ebrc
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// CreateSecurityGroup creates a security group without rules
+func (s *StackEbrc) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	return nil, scerr.NotImplementedError("CreateSecurityGroup() not implemented yet") // FIXME Technical debt
+}
+
+// InspectSecurityGroup returns the security group identified by id
+func (s *StackEbrc) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	return nil, scerr.NotImplementedError("InspectSecurityGroup() not implemented yet") // FIXME Technical debt
+}
+
+// ListSecurityGroups lists the security groups
+func (s *StackEbrc) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	return nil, scerr.NotImplementedError("ListSecurityGroups() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteSecurityGroup deletes the security group identified by id
+func (s *StackEbrc) DeleteSecurityGroup(id string) error {
+	return scerr.NotImplementedError("DeleteSecurityGroup() not implemented yet") // FIXME Technical debt
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by id
+func (s *StackEbrc) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	return nil, scerr.NotImplementedError("AddRuleToSecurityGroup() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by id
+func (s *StackEbrc) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	return nil, scerr.NotImplementedError("DeleteRuleFromSecurityGroup() not implemented yet") // FIXME Technical debt
+}
+
+// BindSecurityGroupToHost applies the rules of the security group identified by id to the host identified by hostID
+func (s *StackEbrc) BindSecurityGroupToHost(id, hostID string) error {
+	return scerr.NotImplementedError("BindSecurityGroupToHost() not implemented yet") // FIXME Technical debt
+}
+
+// UnbindSecurityGroupFromHost removes the rules of the security group identified by id from the host identified by hostID
+func (s *StackEbrc) UnbindSecurityGroupFromHost(id, hostID string) error {
+	return scerr.NotImplementedError("UnbindSecurityGroupFromHost() not implemented yet") // FIXME Technical debt
+}
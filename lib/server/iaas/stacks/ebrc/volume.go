@@ -319,3 +319,38 @@ func (s *StackEbrc) ListVolumeAttachments(serverID string) ([]resources.VolumeAt
 
 	return attachments, nil
 }
+
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s *StackEbrc) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *StackEbrc) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("GetVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume
+func (s *StackEbrc) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *StackEbrc) DeleteVolumeSnapshot(id string) error {
+	return scerr.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// CreateVolumeFromSnapshot creates a block volume from a snapshot
+func (s *StackEbrc) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	return nil, scerr.NotImplementedError("CreateVolumeFromSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// ResizeVolume extends a block volume
+func (s *StackEbrc) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	return nil, scerr.NotImplementedError("ResizeVolume() not implemented yet") // FIXME Technical debt
+}
+
+// UpdateVolumeTags updates the tags of the volume on provider side
+func (s *StackEbrc) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	return scerr.NotImplementedError("UpdateVolumeTags() not implemented yet") // FIXME Technical debt
+}
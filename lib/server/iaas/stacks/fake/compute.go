@@ -0,0 +1,779 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
+	converters "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+var (
+	// images are the OS images proposed by the fake stack
+	images = []resources.Image{
+		{ID: "fake-ubuntu-1804", Name: "Ubuntu 18.04", URL: "fake://images/ubuntu-1804"},
+		{ID: "fake-ubuntu-1604", Name: "Ubuntu 16.04", URL: "fake://images/ubuntu-1604"},
+		{ID: "fake-centos-7", Name: "CentOS 7.3", URL: "fake://images/centos-7"},
+		{ID: "fake-debian-9", Name: "Debian 9", URL: "fake://images/debian-9"},
+	}
+
+	// templates are the host templates proposed by the fake stack
+	templates = []resources.HostTemplate{
+		{ID: "fake-tiny", Name: "fake.tiny", Cores: 1, RAMSize: 1, DiskSize: 10},
+		{ID: "fake-small", Name: "fake.small", Cores: 2, RAMSize: 4, DiskSize: 20},
+		{ID: "fake-medium", Name: "fake.medium", Cores: 4, RAMSize: 8, DiskSize: 50},
+		{ID: "fake-large", Name: "fake.large", Cores: 8, RAMSize: 16, DiskSize: 100},
+		{ID: "fake-xlarge", Name: "fake.xlarge", Cores: 16, RAMSize: 64, DiskSize: 200},
+		{ID: "fake-gpu", Name: "fake.gpu", Cores: 8, RAMSize: 32, DiskSize: 100, GPUNumber: 1, GPUType: "fake"},
+	}
+)
+
+// ListAvailabilityZones lists the usable Availability Zones
+func (s *Stack) ListAvailabilityZones() (map[string]bool, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListAvailabilityZones"); err != nil {
+		return nil, err
+	}
+	zone := s.AuthOptions.AvailabilityZone
+	if zone == "" {
+		zone = "fake-zone"
+	}
+	return map[string]bool{zone: true}, nil
+}
+
+// ListRegions returns a list with the regions available
+func (s *Stack) ListRegions() ([]string, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListRegions"); err != nil {
+		return nil, err
+	}
+	region := s.AuthOptions.Region
+	if region == "" {
+		region = "fake-region"
+	}
+	return []string{region}, nil
+}
+
+// ListImages lists available OS images
+func (s *Stack) ListImages() ([]resources.Image, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListImages"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	list := make([]resources.Image, len(images))
+	copy(list, images)
+	for _, image := range s.customImages {
+		list = append(list, resources.Image{ID: image.ID, Name: image.Name, URL: image.URL})
+	}
+	return list, nil
+}
+
+// GetImage returns the Image referenced by id
+func (s *Stack) GetImage(id string) (*resources.Image, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("GetImage"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.getImage(id)
+}
+
+// getImage returns the image identified by id, proposed by the stack or created from a host
+// Note: the stack must be locked by the caller
+func (s *Stack) getImage(id string) (*resources.Image, error) {
+	for _, i := range images {
+		if i.ID == id {
+			image := i
+			return &image, nil
+		}
+	}
+	if i, ok := s.customImages[id]; ok {
+		return &resources.Image{ID: i.ID, Name: i.Name, URL: i.URL}, nil
+	}
+	return nil, resources.ResourceNotFoundError("image", id)
+}
+
+// CreateImage creates an image from the host identified by hostID
+func (s *Stack) CreateImage(hostID string, name string) (*resources.Image, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if hostID == "" {
+		return nil, scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+	if err := s.enter("CreateImage"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.findHost(hostID) == nil {
+		return nil, resources.ResourceNotFoundError("host", hostID)
+	}
+	for _, i := range s.customImages {
+		if sameName(i.Name, name) {
+			return nil, resources.ResourceDuplicateError("image", name)
+		}
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	image := &resources.Image{ID: id, Name: name, URL: "fake://images/" + id}
+	s.customImages[id] = image
+	return &resources.Image{ID: image.ID, Name: image.Name, URL: image.URL}, nil
+}
+
+// DeleteImage deletes the image identified by id; only the images created from hosts can be deleted
+func (s *Stack) DeleteImage(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteImage"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.customImages[id]; !ok {
+		return resources.ResourceNotFoundError("image", id)
+	}
+	delete(s.customImages, id)
+	return nil
+}
+
+// ListTemplates lists available host templates
+func (s *Stack) ListTemplates() ([]resources.HostTemplate, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListTemplates"); err != nil {
+		return nil, err
+	}
+	list := make([]resources.HostTemplate, len(templates))
+	copy(list, templates)
+	return list, nil
+}
+
+// GetTemplate returns the Template referenced by id
+func (s *Stack) GetTemplate(id string) (*resources.HostTemplate, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("GetTemplate"); err != nil {
+		return nil, err
+	}
+	return getTemplate(id)
+}
+
+func getTemplate(id string) (*resources.HostTemplate, error) {
+	for _, t := range templates {
+		if t.ID == id {
+			template := t
+			return &template, nil
+		}
+	}
+	return nil, resources.ResourceNotFoundError("template", id)
+}
+
+// CreateKeyPair creates and import a key pair
+func (s *Stack) CreateKeyPair(name string) (*resources.KeyPair, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+	if err := s.enter("CreateKeyPair"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.keypairs[name]; ok {
+		return nil, resources.ResourceDuplicateError("key pair", name)
+	}
+	kp, err := crypt.GenerateRSAKeyPair(name)
+	if err != nil {
+		return nil, err
+	}
+	// Like on real providers, the private key is only given at creation
+	stored := *kp
+	stored.PrivateKey = ""
+	s.keypairs[kp.ID] = &stored
+	return kp, nil
+}
+
+// GetKeyPair returns the key pair identified by id
+func (s *Stack) GetKeyPair(id string) (*resources.KeyPair, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("GetKeyPair"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kp, ok := s.keypairs[id]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("key pair", id)
+	}
+	found := *kp
+	return &found, nil
+}
+
+// ListKeyPairs lists available key pairs
+func (s *Stack) ListKeyPairs() ([]resources.KeyPair, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListKeyPairs"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var list []resources.KeyPair
+	for _, kp := range s.keypairs {
+		list = append(list, *kp)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteKeyPair deletes the key pair identified by id
+func (s *Stack) DeleteKeyPair(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteKeyPair"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.keypairs[id]; !ok {
+		return resources.ResourceNotFoundError("key pair", id)
+	}
+	delete(s.keypairs, id)
+	return nil
+}
+
+// CreateHost creates an host that fulfils the request
+func (s *Stack) CreateHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error) {
+	if s == nil {
+		return nil, nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("CreateHost"); err != nil {
+		return nil, nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.createHost(request)
+}
+
+// createHost creates an host that fulfils the request
+// Note: the stack must be locked by the caller
+func (s *Stack) createHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error) {
+	if request.ResourceName == "" {
+		return nil, nil, scerr.InvalidParameterError("request.ResourceName", "cannot be empty string")
+	}
+	if len(request.Networks) == 0 {
+		return nil, nil, scerr.InvalidRequestError(fmt.Sprintf("the host %s must be on at least one network (even if public)", request.ResourceName))
+	}
+	if request.DefaultGateway == nil && !request.PublicIP {
+		return nil, nil, scerr.InvalidRequestError(fmt.Sprintf("the host %s must have a gateway or be public", request.ResourceName))
+	}
+	if s.findHost(request.ResourceName) != nil {
+		return nil, nil, resources.ResourceDuplicateError("host", request.ResourceName)
+	}
+
+	template, err := getTemplate(request.TemplateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if request.DiskSize > template.DiskSize {
+		template.DiskSize = request.DiskSize
+	}
+	if _, err = s.getImage(request.ImageID); err != nil {
+		return nil, nil, err
+	}
+
+	if request.KeyPair == nil {
+		request.KeyPair, err = crypt.GenerateRSAKeyPair(request.ResourceName)
+		if err != nil {
+			return nil, nil, scerr.Errorf(fmt.Sprintf("failed to create host key pair: %v", err), err)
+		}
+	}
+	if request.Password == "" {
+		request.Password, err = utils.GeneratePassword(16)
+		if err != nil {
+			return nil, nil, scerr.Errorf(fmt.Sprintf("failed to generate password: %v", err), err)
+		}
+	}
+
+	// The Default Network is the first of the provided list, by convention
+	defaultNetwork := request.Networks[0]
+	isGateway := request.DefaultGateway == nil && defaultNetwork.Name != resources.SingleHostNetworkName
+	defaultGatewayID := ""
+	defaultGatewayPrivateIP := ""
+	if request.DefaultGateway != nil {
+		defaultGatewayID = request.DefaultGateway.ID
+		defaultGatewayPrivateIP = request.DefaultGateway.GetPrivateIP()
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, nil, err
+	}
+	host := resources.NewHost()
+	host.ID = id
+	host.Name = request.ResourceName
+	host.LastState = hoststate.STARTED
+	host.PrivateKey = request.KeyPair.PrivateKey
+	host.Password = request.Password
+
+	err = host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+		hostNetworkV1.DefaultNetworkID = defaultNetwork.ID
+		hostNetworkV1.DefaultGatewayID = defaultGatewayID
+		hostNetworkV1.DefaultGatewayPrivateIP = defaultGatewayPrivateIP
+		hostNetworkV1.IsGateway = isGateway
+		for _, n := range request.Networks {
+			ip, err := s.allocateAddress(n.ID, n.CIDR)
+			if err != nil {
+				return err
+			}
+			hostNetworkV1.IPv4Addresses[n.ID] = ip
+			hostNetworkV1.NetworksByID[n.ID] = n.Name
+			hostNetworkV1.NetworksByName[n.Name] = n.ID
+		}
+		if request.PublicIP {
+			hostNetworkV1.PublicIPv4 = s.allocatePublicAddress()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = host.Properties.LockForWrite(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
+		hostSizingV1 := clonable.(*propsv1.HostSizing)
+		hostSizingV1.Template = request.TemplateID
+		hostSizingV1.AllocatedSize = converters.ModelHostTemplateToPropertyHostSize(template)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// There is no system to configure on the host, only the information used by the callers is set
+	userData := userdata.NewContent()
+	userData.User = s.Config.OperatorUsername
+	userData.PublicKey = request.KeyPair.PublicKey
+	userData.PrivateKey = request.KeyPair.PrivateKey
+	userData.Password = request.Password
+	userData.IsGateway = isGateway
+	userData.CIDR = defaultNetwork.CIDR
+	userData.DefaultRouteIP = request.DefaultRouteIP
+	userData.DNSServers = s.Config.DNSList
+	userData.ProviderName = s.Config.ProviderName
+	userData.HostName = request.ResourceName
+	if request.HostName != "" {
+		userData.HostName = request.HostName
+	}
+
+	stored, err := cloneHost(host)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.hosts[host.ID] = stored
+	s.tags[host.ID] = resources.MergeTags(nil, request.Tags, nil)
+	return host, userData, nil
+}
+
+// cloneHost returns a deep copy of host
+func cloneHost(host *resources.Host) (*resources.Host, error) {
+	serialized, err := host.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	cloned := resources.NewHost()
+	err = cloned.Deserialize(serialized)
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// findHost returns the host stored with ref as ID or name, nil if not found
+// Note: the stack must be locked by the caller
+func (s *Stack) findHost(ref string) *resources.Host {
+	if host, ok := s.hosts[ref]; ok {
+		return host
+	}
+	for _, host := range s.hosts {
+		if sameName(host.Name, ref) {
+			return host
+		}
+	}
+	return nil
+}
+
+// getHost returns a copy of the host stored with ref as ID or name
+// Note: the stack must be locked by the caller
+func (s *Stack) getHost(ref string) (*resources.Host, error) {
+	host := s.findHost(ref)
+	if host == nil {
+		return nil, resources.ResourceNotFoundError("host", ref)
+	}
+	return cloneHost(host)
+}
+
+// hostRef returns the reference of the host designated by hostParam (string or *resources.Host)
+func hostRef(hostParam interface{}) (string, error) {
+	switch hostParam := hostParam.(type) {
+	case string:
+		if hostParam == "" {
+			return "", scerr.InvalidParameterError("hostParam", "cannot be an empty string")
+		}
+		return hostParam, nil
+	case *resources.Host:
+		if hostParam == nil {
+			return "", scerr.InvalidParameterError("hostParam", "cannot be nil")
+		}
+		if hostParam.ID != "" {
+			return hostParam.ID, nil
+		}
+		if hostParam.Name != "" {
+			return hostParam.Name, nil
+		}
+		return "", scerr.InvalidParameterError("hostParam", "must have an ID or a name")
+	default:
+		return "", scerr.InvalidParameterError("hostParam", "must be a string or a *resources.Host")
+	}
+}
+
+// InspectHost returns the host identified by id or updates content of a *resources.Host
+func (s *Stack) InspectHost(hostParam interface{}) (*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	ref, err := hostRef(hostParam)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.enter("InspectHost"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	host, err := s.getHost(ref)
+	if err != nil {
+		return nil, err
+	}
+	if h, ok := hostParam.(*resources.Host); ok {
+		h.Replace(host)
+		return h, nil
+	}
+	return host, nil
+}
+
+// GetHostByName returns the host identified by name
+func (s *Stack) GetHostByName(name string) (*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+	if err := s.enter("GetHostByName"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, host := range s.hosts {
+		if sameName(host.Name, name) {
+			return cloneHost(host)
+		}
+	}
+	return nil, resources.ResourceNotFoundError("host", name)
+}
+
+// GetHostState returns the current state of the host identified by id
+func (s *Stack) GetHostState(hostParam interface{}) (hoststate.Enum, error) {
+	if s == nil {
+		return hoststate.ERROR, scerr.InvalidInstanceError()
+	}
+	ref, err := hostRef(hostParam)
+	if err != nil {
+		return hoststate.ERROR, err
+	}
+	if err = s.enter("GetHostState"); err != nil {
+		return hoststate.ERROR, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	host := s.findHost(ref)
+	if host == nil {
+		return hoststate.ERROR, resources.ResourceNotFoundError("host", ref)
+	}
+	return host.LastState, nil
+}
+
+// ListHosts lists all hosts
+func (s *Stack) ListHosts() ([]*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListHosts"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var list []*resources.Host
+	for _, host := range s.hosts {
+		cloned, err := cloneHost(host)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, cloned)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteHost deletes the host identified by id
+func (s *Stack) DeleteHost(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteHost"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.deleteHost(id)
+}
+
+// deleteHost deletes the host identified by id, detaching its volumes and unbinding it from the VIPs
+// Note: the stack must be locked by the caller
+func (s *Stack) deleteHost(id string) error {
+	host := s.findHost(id)
+	if host == nil {
+		return resources.ResourceNotFoundError("host", id)
+	}
+	for vaID, va := range s.attachments {
+		if va.ServerID == host.ID {
+			if volume, ok := s.volumes[va.VolumeID]; ok {
+				volume.State = volumestate.AVAILABLE
+			}
+			delete(s.attachments, vaID)
+		}
+	}
+	for _, vip := range s.vips {
+		vip.Hosts = removeString(vip.Hosts, host.ID)
+	}
+	for _, hosts := range s.bindings {
+		delete(hosts, host.ID)
+	}
+	delete(s.hosts, host.ID)
+	delete(s.tags, host.ID)
+	return nil
+}
+
+// setHostState changes the state of the host identified by id
+func (s *Stack) setHostState(call, id string, state hoststate.Enum) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter(call); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	host := s.findHost(id)
+	if host == nil {
+		return resources.ResourceNotFoundError("host", id)
+	}
+	host.LastState = state
+	return nil
+}
+
+// StopHost stops the host identified by id
+func (s *Stack) StopHost(id string) error {
+	return s.setHostState("StopHost", id, hoststate.STOPPED)
+}
+
+// StartHost starts the host identified by id
+func (s *Stack) StartHost(id string) error {
+	return s.setHostState("StartHost", id, hoststate.STARTED)
+}
+
+// RebootHost reboots the host identified by id
+func (s *Stack) RebootHost(id string) error {
+	return s.setHostState("RebootHost", id, hoststate.STARTED)
+}
+
+// ResizeHost changes the sizing of the host identified by id to the minimum values of request
+func (s *Stack) ResizeHost(id string, request resources.SizingRequirements) (*resources.Host, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("ResizeHost"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	host := s.findHost(id)
+	if host == nil {
+		return nil, resources.ResourceNotFoundError("host", id)
+	}
+	err := host.Properties.LockForWrite(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
+		hostSizingV1 := clonable.(*propsv1.HostSizing)
+		if hostSizingV1.AllocatedSize == nil {
+			hostSizingV1.AllocatedSize = &propsv1.HostSize{}
+		}
+		if request.MinCores > 0 {
+			hostSizingV1.AllocatedSize.Cores = request.MinCores
+		}
+		if request.MinRAMSize > 0 {
+			hostSizingV1.AllocatedSize.RAMSize = request.MinRAMSize
+		}
+		if request.MinDiskSize > hostSizingV1.AllocatedSize.DiskSize {
+			hostSizingV1.AllocatedSize.DiskSize = request.MinDiskSize
+		}
+		if request.MinGPU > 0 {
+			hostSizingV1.AllocatedSize.GPUNumber = request.MinGPU
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneHost(host)
+}
+
+// UpdateHostTags sets the tags in set and removes the tags in unset on the host identified by id
+func (s *Stack) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("UpdateHostTags"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	host := s.findHost(id)
+	if host == nil {
+		return resources.ResourceNotFoundError("host", id)
+	}
+	s.tags[host.ID] = resources.MergeTags(s.tags[host.ID], set, unset)
+	return nil
+}
+
+// removeString returns list without the occurrences of value
+func removeString(list []string, value string) []string {
+	var result []string
+	for _, v := range list {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,404 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// CreateNetwork creates a network named name
+func (s *Stack) CreateNetwork(req resources.NetworkRequest) (*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, scerr.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	_, ipNet, err := net.ParseCIDR(req.CIDR)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to create network '%s (%s)': %s", req.Name, req.CIDR, err.Error()), err)
+	}
+	if err = s.enter("CreateNetwork"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.findNetwork(req.Name) != nil {
+		return nil, resources.ResourceDuplicateError("network", req.Name)
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	network := resources.NewNetwork()
+	network.ID = id
+	network.Name = req.Name
+	network.CIDR = ipNet.String()
+	network.IPVersion = req.IPVersion
+	if network.IPVersion != ipversion.IPv6 {
+		network.IPVersion = ipversion.IPv4
+	}
+
+	stored, err := cloneNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+	s.networks[id] = stored
+	return network, nil
+}
+
+// cloneNetwork returns a deep copy of network
+func cloneNetwork(network *resources.Network) (*resources.Network, error) {
+	serialized, err := network.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	cloned := resources.NewNetwork()
+	err = cloned.Deserialize(serialized)
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// findNetwork returns the network stored with ref as ID or name, nil if not found
+// Note: the stack must be locked by the caller
+func (s *Stack) findNetwork(ref string) *resources.Network {
+	if network, ok := s.networks[ref]; ok {
+		return network
+	}
+	for _, network := range s.networks {
+		if sameName(network.Name, ref) {
+			return network
+		}
+	}
+	return nil
+}
+
+// GetNetwork returns the network identified by id
+func (s *Stack) GetNetwork(id string) (*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("GetNetwork"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	network, ok := s.networks[id]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("network", id)
+	}
+	return cloneNetwork(network)
+}
+
+// GetNetworkByName returns the network identified by name
+func (s *Stack) GetNetworkByName(name string) (*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+	if err := s.enter("GetNetworkByName"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, network := range s.networks {
+		if sameName(network.Name, name) {
+			return cloneNetwork(network)
+		}
+	}
+	return nil, resources.ResourceNotFoundError("network", name)
+}
+
+// ListNetworks lists all networks
+func (s *Stack) ListNetworks() ([]*resources.Network, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListNetworks"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var list []*resources.Network
+	for _, network := range s.networks {
+		cloned, err := cloneNetwork(network)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, cloned)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteNetwork deletes the network identified by id; the network must not contain hosts anymore
+func (s *Stack) DeleteNetwork(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteNetwork"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	network := s.findNetwork(id)
+	if network == nil {
+		return resources.ResourceNotFoundError("network", id)
+	}
+	for _, host := range s.hosts {
+		inNetwork := false
+		err := host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+			_, inNetwork = clonable.(*propsv1.HostNetwork).NetworksByID[network.ID]
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if inNetwork {
+			return scerr.NotAvailableError(fmt.Sprintf("network '%s' still contains host '%s'", network.Name, host.Name))
+		}
+	}
+	for vipID, vip := range s.vips {
+		if vip.NetworkID == network.ID {
+			delete(s.vips, vipID)
+		}
+	}
+	delete(s.networks, network.ID)
+	delete(s.addresses, network.ID)
+	return nil
+}
+
+// CreateGateway creates a public Gateway for a private network
+func (s *Stack) CreateGateway(req resources.GatewayRequest) (*resources.Host, *userdata.Content, error) {
+	if s == nil {
+		return nil, nil, scerr.InvalidInstanceError()
+	}
+	if req.Network == nil {
+		return nil, nil, scerr.InvalidParameterError("req.Network", "cannot be nil")
+	}
+	if err := s.enter("CreateGateway"); err != nil {
+		return nil, nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name := req.Name
+	if name == "" {
+		name = "gw-" + req.Network.Name
+	}
+	return s.createHost(resources.HostRequest{
+		ResourceName: name,
+		Networks:     []*resources.Network{req.Network},
+		PublicIP:     true,
+		TemplateID:   req.TemplateID,
+		ImageID:      req.ImageID,
+		KeyPair:      req.KeyPair,
+	})
+}
+
+// DeleteGateway deletes the public gateway identified by id
+func (s *Stack) DeleteGateway(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteGateway"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.deleteHost(id)
+}
+
+// CreateVIP creates a private virtual IP in the network identified by networkID
+func (s *Stack) CreateVIP(networkID string, name string) (*resources.VirtualIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if networkID == "" {
+		return nil, scerr.InvalidParameterError("networkID", "cannot be empty string")
+	}
+	if err := s.enter("CreateVIP"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	network := s.findNetwork(networkID)
+	if network == nil {
+		return nil, resources.ResourceNotFoundError("network", networkID)
+	}
+	ip, err := s.allocateAddress(network.ID, network.CIDR)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	vip := &resources.VirtualIP{
+		ID:        id,
+		Name:      name,
+		NetworkID: network.ID,
+		PrivateIP: ip,
+	}
+	s.vips[id] = vip.Clone().(*resources.VirtualIP)
+	return vip, nil
+}
+
+// getVIP returns the VIP stored with the ID of vip
+// Note: the stack must be locked by the caller
+func (s *Stack) getVIP(vip *resources.VirtualIP) (*resources.VirtualIP, error) {
+	if vip == nil {
+		return nil, scerr.InvalidParameterError("vip", "cannot be nil")
+	}
+	stored, ok := s.vips[vip.ID]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("VIP", vip.ID)
+	}
+	return stored, nil
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (s *Stack) AddPublicIPToVIP(vip *resources.VirtualIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if err := s.enter("AddPublicIPToVIP"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, err := s.getVIP(vip)
+	if err != nil {
+		return err
+	}
+	if stored.PublicIP == "" {
+		stored.PublicIP = s.allocatePublicAddress()
+	}
+	vip.PublicIP = stored.PublicIP
+	return nil
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (s *Stack) BindHostToVIP(vip *resources.VirtualIP, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if err := s.enter("BindHostToVIP"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, err := s.getVIP(vip)
+	if err != nil {
+		return err
+	}
+	if s.findHost(hostID) == nil {
+		return resources.ResourceNotFoundError("host", hostID)
+	}
+	stored.Hosts = append(removeString(stored.Hosts, hostID), hostID)
+	vip.Hosts = append(removeString(vip.Hosts, hostID), hostID)
+	return nil
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (s *Stack) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if err := s.enter("UnbindHostFromVIP"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, err := s.getVIP(vip)
+	if err != nil {
+		return err
+	}
+	stored.Hosts = removeString(stored.Hosts, hostID)
+	vip.Hosts = removeString(vip.Hosts, hostID)
+	return nil
+}
+
+// DeleteVIP deletes the VIP
+func (s *Stack) DeleteVIP(vip *resources.VirtualIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if err := s.enter("DeleteVIP"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, err := s.getVIP(vip)
+	if err != nil {
+		return err
+	}
+	delete(s.vips, stored.ID)
+	return nil
+}
@@ -0,0 +1,275 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// CreateSecurityGroup creates a security group without rules
+func (s *Stack) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, scerr.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	if err := s.enter("CreateSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.findSecurityGroup(req.Name) != nil {
+		return nil, resources.ResourceDuplicateError("security group", req.Name)
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	sg := resources.NewSecurityGroup()
+	sg.ID = id
+	sg.Name = req.Name
+	sg.Description = req.Description
+	sg.NetworkID = req.NetworkID
+
+	stored, err := cloneSecurityGroup(sg)
+	if err != nil {
+		return nil, err
+	}
+	s.securityGroups[id] = stored
+	s.bindings[id] = map[string]bool{}
+	return sg, nil
+}
+
+// cloneSecurityGroup returns a deep copy of sg
+func cloneSecurityGroup(sg *resources.SecurityGroup) (*resources.SecurityGroup, error) {
+	serialized, err := sg.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	cloned := resources.NewSecurityGroup()
+	err = cloned.Deserialize(serialized)
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// findSecurityGroup returns the security group stored with ref as ID or name, nil if not found
+// Note: the stack must be locked by the caller
+func (s *Stack) findSecurityGroup(ref string) *resources.SecurityGroup {
+	if sg, ok := s.securityGroups[ref]; ok {
+		return sg
+	}
+	for _, sg := range s.securityGroups {
+		if sameName(sg.Name, ref) {
+			return sg
+		}
+	}
+	return nil
+}
+
+// InspectSecurityGroup returns the security group identified by id
+func (s *Stack) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("InspectSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sg := s.findSecurityGroup(id)
+	if sg == nil {
+		return nil, resources.ResourceNotFoundError("security group", id)
+	}
+	return cloneSecurityGroup(sg)
+}
+
+// ListSecurityGroups lists the security groups
+func (s *Stack) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListSecurityGroups"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var list []*resources.SecurityGroup
+	for _, sg := range s.securityGroups {
+		cloned, err := cloneSecurityGroup(sg)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, cloned)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteSecurityGroup deletes the security group identified by id; it must not be bound to hosts anymore
+func (s *Stack) DeleteSecurityGroup(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteSecurityGroup"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sg := s.findSecurityGroup(id)
+	if sg == nil {
+		return resources.ResourceNotFoundError("security group", id)
+	}
+	if len(s.bindings[sg.ID]) > 0 {
+		return scerr.NotAvailableError(fmt.Sprintf("security group '%s' is still bound to %d host(s)", sg.Name, len(s.bindings[sg.ID])))
+	}
+	delete(s.securityGroups, sg.ID)
+	delete(s.bindings, sg.ID)
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by id, and returns the security group updated
+func (s *Stack) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.enter("AddRuleToSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sg := s.findSecurityGroup(id)
+	if sg == nil {
+		return nil, resources.ResourceNotFoundError("security group", id)
+	}
+	for _, r := range sg.Rules {
+		if r.Direction == rule.Direction && r.EtherType == rule.EtherType && r.Protocol == rule.Protocol &&
+			r.PortFrom == rule.PortFrom && r.PortTo == rule.PortTo && r.CIDR == rule.CIDR {
+			return nil, resources.ResourceDuplicateError("security group rule", r.ID)
+		}
+	}
+	ruleID, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = ruleID
+	sg.Rules = append(sg.Rules, rule)
+	return cloneSecurityGroup(sg)
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by id
+func (s *Stack) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if ruleID == "" {
+		return nil, scerr.InvalidParameterError("ruleID", "cannot be empty string")
+	}
+	if err := s.enter("DeleteRuleFromSecurityGroup"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sg := s.findSecurityGroup(id)
+	if sg == nil {
+		return nil, resources.ResourceNotFoundError("security group", id)
+	}
+	for i, r := range sg.Rules {
+		if r.ID == ruleID {
+			sg.Rules = append(sg.Rules[:i], sg.Rules[i+1:]...)
+			return cloneSecurityGroup(sg)
+		}
+	}
+	return nil, resources.ResourceNotFoundError("security group rule", ruleID)
+}
+
+// BindSecurityGroupToHost applies the rules of the security group identified by id to the host identified by hostID
+func (s *Stack) BindSecurityGroupToHost(id, hostID string) error {
+	return s.setBinding("BindSecurityGroupToHost", id, hostID, true)
+}
+
+// UnbindSecurityGroupFromHost removes the rules of the security group identified by id from the host identified by hostID
+func (s *Stack) UnbindSecurityGroupFromHost(id, hostID string) error {
+	return s.setBinding("UnbindSecurityGroupFromHost", id, hostID, false)
+}
+
+// setBinding binds or unbinds the security group identified by id and the host identified by hostID
+func (s *Stack) setBinding(call, id, hostID string, bound bool) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if err := s.enter(call); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sg := s.findSecurityGroup(id)
+	if sg == nil {
+		return resources.ResourceNotFoundError("security group", id)
+	}
+	host := s.findHost(hostID)
+	if host == nil {
+		return resources.ResourceNotFoundError("host", hostID)
+	}
+	if bound {
+		s.bindings[sg.ID][host.ID] = true
+	} else {
+		delete(s.bindings[sg.ID], host.ID)
+	}
+	return nil
+}
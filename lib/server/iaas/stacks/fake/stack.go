@@ -0,0 +1,193 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// Behavior defines how the fake stack simulates the latency and the failures of a real provider
+type Behavior struct {
+	// Latency is the duration of every call to the stack
+	Latency time.Duration
+	// ErrorRate is the probability (between 0 and 1) for a call to fail with a ErrNotAvailable error
+	ErrorRate float64
+}
+
+// Stack is a stack keeping every resource in memory, without any real infrastructure behind
+type Stack struct {
+	Config      *stacks.ConfigurationOptions
+	AuthOptions *stacks.AuthenticationOptions
+
+	mutex    sync.Mutex
+	behavior Behavior
+	random   *rand.Rand
+	failures map[string][]error
+
+	hosts       map[string]*resources.Host
+	networks    map[string]*resources.Network
+	vips        map[string]*resources.VirtualIP
+	volumes     map[string]*resources.Volume
+	snapshots   map[string]*resources.VolumeSnapshot
+	attachments map[string]*resources.VolumeAttachment
+	keypairs    map[string]*resources.KeyPair
+
+	// customImages contains the images created from hosts, by ID
+	customImages   map[string]*resources.Image
+	securityGroups map[string]*resources.SecurityGroup
+	// bindings contains the IDs of the hosts bound to each security group, by security group ID
+	bindings map[string]map[string]bool
+	// tags contains the provider-side tags of hosts and volumes, by resource ID
+	tags map[string]map[string]string
+
+	// addresses contains the last host number allocated in each network, by network ID
+	addresses map[string]uint32
+	// publicAddresses contains the last public host number allocated
+	publicAddresses uint32
+}
+
+// New creates a new empty fake stack
+func New(auth stacks.AuthenticationOptions, cfg stacks.ConfigurationOptions, behavior Behavior) *Stack {
+	return &Stack{
+		Config:      &cfg,
+		AuthOptions: &auth,
+		behavior:    behavior,
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		failures:    map[string][]error{},
+		hosts:       map[string]*resources.Host{},
+		networks:    map[string]*resources.Network{},
+		vips:        map[string]*resources.VirtualIP{},
+		volumes:     map[string]*resources.Volume{},
+		snapshots:   map[string]*resources.VolumeSnapshot{},
+		attachments: map[string]*resources.VolumeAttachment{},
+		keypairs:    map[string]*resources.KeyPair{},
+		addresses:   map[string]uint32{},
+
+		customImages:   map[string]*resources.Image{},
+		securityGroups: map[string]*resources.SecurityGroup{},
+		bindings:       map[string]map[string]bool{},
+		tags:           map[string]map[string]string{},
+	}
+}
+
+// GetConfigurationOptions ...
+func (s *Stack) GetConfigurationOptions() stacks.ConfigurationOptions {
+	return *s.Config
+}
+
+// GetAuthenticationOptions ...
+func (s *Stack) GetAuthenticationOptions() stacks.AuthenticationOptions {
+	return *s.AuthOptions
+}
+
+// SetBehavior changes the latency and the error rate of the stack
+func (s *Stack) SetBehavior(behavior Behavior) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.behavior = behavior
+}
+
+// FailNext makes the 'count' next calls of the method named 'call' (like "CreateHost") fail with 'err'
+func (s *Stack) FailNext(call string, err error, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i := 0; i < count; i++ {
+		s.failures[call] = append(s.failures[call], err)
+	}
+}
+
+// enter simulates the latency of the call named 'call' and returns the error to inject, if any
+func (s *Stack) enter(call string) error {
+	s.mutex.Lock()
+	latency := s.behavior.Latency
+	s.mutex.Unlock()
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if errs := s.failures[call]; len(errs) > 0 {
+		s.failures[call] = errs[1:]
+		return errs[0]
+	}
+	if s.behavior.ErrorRate > 0 && s.random.Float64() < s.behavior.ErrorRate {
+		return scerr.NotAvailableError(fmt.Sprintf("%s failed: error injected by fake stack", call))
+	}
+	return nil
+}
+
+// newID returns a new unique identifier for a resource
+func newID() (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", scerr.Errorf(fmt.Sprintf("failed to generate ID: %v", err), err)
+	}
+	return id.String(), nil
+}
+
+// allocateAddress returns the next free private IP address in cidr, for the network identified by networkID
+// Note: the stack must be locked by the caller
+func (s *Stack) allocateAddress(networkID, cidr string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", scerr.InvalidParameterError("cidr", fmt.Sprintf("'%s' is not a valid CIDR: %v", cidr, err))
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits != 32 {
+		return "", scerr.NotImplementedError("IPv6 networks are not supported by fake stack")
+	}
+	size := uint32(1) << uint(bits-ones)
+	next := s.addresses[networkID] + 1
+	if next >= size-1 {
+		return "", resources.ResourceNotAvailableError("address in network", networkID)
+	}
+	s.addresses[networkID] = next
+	return uint32ToIP(ipToUint32(ipNet.IP) + next).String(), nil
+}
+
+// allocatePublicAddress returns a new public IP address, taken in the range reserved for documentation (RFC 5737)
+// Note: the stack must be locked by the caller
+func (s *Stack) allocatePublicAddress() string {
+	s.publicAddresses++
+	return uint32ToIP(ipToUint32(net.IPv4(203, 0, 113, 0)) + s.publicAddresses).String()
+}
+
+func ipToUint32(ip net.IP) uint32 {
+	ip = ip.To4()
+	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+}
+
+func uint32ToIP(n uint32) net.IP {
+	return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// sameName tells if the names are the same, case ignored
+func sameName(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
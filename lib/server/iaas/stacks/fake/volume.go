@@ -0,0 +1,362 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// CreateVolume creates a block volume
+func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, scerr.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.Size <= 0 {
+		return nil, scerr.InvalidParameterError("request.Size", "must be greater than 0")
+	}
+	if err := s.enter("CreateVolume"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.findVolume(request.Name) != nil {
+		return nil, resources.ResourceDuplicateError("volume", request.Name)
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	volume := resources.NewVolume()
+	volume.ID = id
+	volume.Name = request.Name
+	volume.Size = request.Size
+	volume.Speed = request.Speed
+	volume.State = volumestate.AVAILABLE
+
+	stored, err := cloneVolume(volume)
+	if err != nil {
+		return nil, err
+	}
+	s.volumes[id] = stored
+	s.tags[id] = resources.MergeTags(nil, request.Tags, nil)
+	return volume, nil
+}
+
+// cloneVolume returns a deep copy of volume
+func cloneVolume(volume *resources.Volume) (*resources.Volume, error) {
+	serialized, err := volume.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	cloned := resources.NewVolume()
+	err = cloned.Deserialize(serialized)
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// findVolume returns the volume stored with ref as ID or name, nil if not found
+// Note: the stack must be locked by the caller
+func (s *Stack) findVolume(ref string) *resources.Volume {
+	if volume, ok := s.volumes[ref]; ok {
+		return volume
+	}
+	for _, volume := range s.volumes {
+		if sameName(volume.Name, ref) {
+			return volume
+		}
+	}
+	return nil
+}
+
+// GetVolume returns the volume identified by id
+func (s *Stack) GetVolume(id string) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("GetVolume"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	volume, ok := s.volumes[id]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("volume", id)
+	}
+	return cloneVolume(volume)
+}
+
+// ListVolumes list available volumes
+func (s *Stack) ListVolumes() ([]resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListVolumes"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var list []resources.Volume
+	for _, volume := range s.volumes {
+		cloned, err := cloneVolume(volume)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *cloned)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteVolume deletes the volume identified by id; the volume must not be attached
+func (s *Stack) DeleteVolume(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteVolume"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	volume, ok := s.volumes[id]
+	if !ok {
+		return resources.ResourceNotFoundError("volume", id)
+	}
+	if volume.State == volumestate.USED {
+		return scerr.NotAvailableError(fmt.Sprintf("volume '%s' is still attached", volume.Name))
+	}
+	delete(s.volumes, id)
+	delete(s.tags, id)
+	return nil
+}
+
+// ResizeVolume extends the volume identified by id to size GB; like most providers, the fake one resizes
+// attached volumes
+func (s *Stack) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if size <= 0 {
+		return nil, scerr.InvalidParameterError("size", "must be greater than 0")
+	}
+	if err := s.enter("ResizeVolume"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	volume, ok := s.volumes[id]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("volume", id)
+	}
+	if size < volume.Size {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("volume '%s' cannot be shrunk (current size: %d GB)", volume.Name, volume.Size))
+	}
+	volume.Size = size
+	return cloneVolume(volume)
+}
+
+// UpdateVolumeTags sets the tags in set and removes the tags in unset on the volume identified by id
+func (s *Stack) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("UpdateVolumeTags"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	volume := s.findVolume(id)
+	if volume == nil {
+		return resources.ResourceNotFoundError("volume", id)
+	}
+	s.tags[volume.ID] = resources.MergeTags(s.tags[volume.ID], set, unset)
+	return nil
+}
+
+// CreateVolumeAttachment attaches a volume to an host
+func (s *Stack) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if request.VolumeID == "" {
+		return "", scerr.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+	if request.HostID == "" {
+		return "", scerr.InvalidParameterError("request.HostID", "cannot be empty string")
+	}
+	if err := s.enter("CreateVolumeAttachment"); err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	volume, ok := s.volumes[request.VolumeID]
+	if !ok {
+		return "", resources.ResourceNotFoundError("volume", request.VolumeID)
+	}
+	host := s.findHost(request.HostID)
+	if host == nil {
+		return "", resources.ResourceNotFoundError("host", request.HostID)
+	}
+	if volume.State == volumestate.USED {
+		return "", resources.ResourceNotAvailableError("volume", volume.Name)
+	}
+
+	// Devices are named like on KVM hypervisors, the first one after the system disk being /dev/vdb
+	used := map[string]bool{}
+	for _, va := range s.attachments {
+		if va.ServerID == host.ID {
+			used[va.Device] = true
+		}
+	}
+	device := ""
+	for c := 'b'; c <= 'z'; c++ {
+		if candidate := fmt.Sprintf("/dev/vd%c", c); !used[candidate] {
+			device = candidate
+			break
+		}
+	}
+	if device == "" {
+		return "", scerr.NotAvailableError(fmt.Sprintf("no more device available on host '%s'", host.Name))
+	}
+
+	// A volume being attached to one host at most, the attachment is identified by the ID of the volume
+	id := volume.ID
+	s.attachments[id] = &resources.VolumeAttachment{
+		ID:       id,
+		Name:     request.Name,
+		VolumeID: volume.ID,
+		ServerID: host.ID,
+		Device:   device,
+	}
+	volume.State = volumestate.USED
+	return id, nil
+}
+
+// GetVolumeAttachment returns the volume attachment identified by id
+func (s *Stack) GetVolumeAttachment(serverID, id string) (*resources.VolumeAttachment, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return nil, scerr.InvalidParameterError("serverID", "cannot be empty string")
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("GetVolumeAttachment"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	va, ok := s.attachments[id]
+	if !ok || va.ServerID != serverID {
+		return nil, resources.ResourceNotFoundError("volume attachment", id)
+	}
+	found := *va
+	return &found, nil
+}
+
+// ListVolumeAttachments lists available volume attachment
+func (s *Stack) ListVolumeAttachments(serverID string) ([]resources.VolumeAttachment, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return nil, scerr.InvalidParameterError("serverID", "cannot be empty string")
+	}
+	if err := s.enter("ListVolumeAttachments"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var list []resources.VolumeAttachment
+	for _, va := range s.attachments {
+		if va.ServerID == serverID {
+			list = append(list, *va)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Device < list[j].Device })
+	return list, nil
+}
+
+// DeleteVolumeAttachment deletes the volume attachment identified by id
+func (s *Stack) DeleteVolumeAttachment(serverID, id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return scerr.InvalidParameterError("serverID", "cannot be empty string")
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteVolumeAttachment"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	va, ok := s.attachments[id]
+	if !ok || va.ServerID != serverID {
+		return resources.ResourceNotFoundError("volume attachment", id)
+	}
+	if volume, ok := s.volumes[va.VolumeID]; ok {
+		volume.State = volumestate.AVAILABLE
+	}
+	delete(s.attachments, id)
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// CreateVolumeSnapshot creates a snapshot of a block volume; the snapshot is available at once
+func (s *Stack) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, scerr.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.VolumeID == "" {
+		return nil, scerr.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+	if err := s.enter("CreateVolumeSnapshot"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	volume, ok := s.volumes[request.VolumeID]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("volume", request.VolumeID)
+	}
+	for _, snap := range s.snapshots {
+		if sameName(snap.Name, request.Name) {
+			return nil, resources.ResourceDuplicateError("volume snapshot", request.Name)
+		}
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	snap := resources.NewVolumeSnapshot()
+	snap.ID = id
+	snap.Name = request.Name
+	snap.Description = request.Description
+	snap.VolumeID = volume.ID
+	snap.VolumeName = volume.Name
+	snap.Size = volume.Size
+	snap.State = volumestate.AVAILABLE
+	snap.CreatedAt = time.Now().UTC()
+
+	stored, err := cloneVolumeSnapshot(snap)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshots[id] = stored
+	return snap, nil
+}
+
+// cloneVolumeSnapshot returns a deep copy of snap
+func cloneVolumeSnapshot(snap *resources.VolumeSnapshot) (*resources.VolumeSnapshot, error) {
+	serialized, err := snap.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	cloned := resources.NewVolumeSnapshot()
+	err = cloned.Deserialize(serialized)
+	if err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *Stack) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("GetVolumeSnapshot"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	snap, ok := s.snapshots[id]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("volume snapshot", id)
+	}
+	return cloneVolumeSnapshot(snap)
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID, or all the snapshots if volumeID is empty
+func (s *Stack) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if err := s.enter("ListVolumeSnapshots"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var list []resources.VolumeSnapshot
+	for _, snap := range s.snapshots {
+		if volumeID != "" && snap.VolumeID != volumeID {
+			continue
+		}
+		cloned, err := cloneVolumeSnapshot(snap)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *cloned)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *Stack) DeleteVolumeSnapshot(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := s.enter("DeleteVolumeSnapshot"); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.snapshots[id]; !ok {
+		return resources.ResourceNotFoundError("volume snapshot", id)
+	}
+	delete(s.snapshots, id)
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a block volume from the snapshot identified by snapshotID
+func (s *Stack) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if snapshotID == "" {
+		return nil, scerr.InvalidParameterError("snapshotID", "cannot be empty string")
+	}
+	if request.Name == "" {
+		return nil, scerr.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if err := s.enter("CreateVolumeFromSnapshot"); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	snap, ok := s.snapshots[snapshotID]
+	if !ok {
+		return nil, resources.ResourceNotFoundError("volume snapshot", snapshotID)
+	}
+	if request.Size == 0 {
+		request.Size = snap.Size
+	}
+	if request.Size < snap.Size {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("the volume cannot be smaller than the snapshot (%d GB)", snap.Size))
+	}
+	if s.findVolume(request.Name) != nil {
+		return nil, resources.ResourceDuplicateError("volume", request.Name)
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	volume := resources.NewVolume()
+	volume.ID = id
+	volume.Name = request.Name
+	volume.Size = request.Size
+	volume.Speed = request.Speed
+	volume.State = volumestate.AVAILABLE
+
+	stored, err := cloneVolume(volume)
+	if err != nil {
+		return nil, err
+	}
+	s.volumes[id] = stored
+	s.tags[id] = resources.MergeTags(nil, request.Tags, nil)
+	return volume, nil
+}
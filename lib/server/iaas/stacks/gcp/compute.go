@@ -100,9 +100,84 @@ func (s *Stack) GetImage(id string) (*resources.Image, error) {
 		}
 	}
 
+	// The images created from hosts belong to the project, and are not listed by ListImages
+	image, err := s.ComputeService.Images.Get(s.GcpConfig.ProjectID, id).Do()
+	if err == nil {
+		return &resources.Image{Name: image.Name, URL: image.SelfLink, ID: strconv.FormatUint(image.Id, 10)}, nil
+	}
+
 	return nil, scerr.Errorf(fmt.Sprintf("image with id [%s] not found", id), nil)
 }
 
+// CreateImage creates an image in the project from the boot disk of the host identified by hostID; the host
+// is not stopped, so the content of the image is the one the disk would have after a power loss
+func (s *Stack) CreateImage(hostID string, name string) (*resources.Image, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if hostID == "" {
+		return nil, scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	instance, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, hostID).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resources.ResourceNotFoundError("host", hostID)
+		}
+		return nil, err
+	}
+	sourceDisk := ""
+	for _, disk := range instance.Disks {
+		if disk.Boot {
+			sourceDisk = disk.Source
+			break
+		}
+	}
+	if sourceDisk == "" {
+		return nil, scerr.InconsistentError(fmt.Sprintf("no boot disk found for host '%s'", instance.Name))
+	}
+
+	op, err := s.ComputeService.Images.Insert(s.GcpConfig.ProjectID, &compute.Image{
+		Name:       name,
+		SourceDisk: sourceDisk,
+	}).ForceCreate(true).Do()
+	if err != nil {
+		return nil, err
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := s.ComputeService.Images.Get(s.GcpConfig.ProjectID, name).Do()
+	if err != nil {
+		return nil, err
+	}
+	return &resources.Image{Name: image.Name, URL: image.SelfLink, ID: strconv.FormatUint(image.Id, 10)}, nil
+}
+
+// DeleteImage deletes the image of the project identified by id (or name)
+func (s *Stack) DeleteImage(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	op, err := s.ComputeService.Images.Delete(s.GcpConfig.ProjectID, id).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return resources.ResourceNotFoundError("image", id)
+		}
+		return err
+	}
+	return s.waitForOperation(op)
+}
+
 //-------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStackGcp ListTemplate method to filter wind and flex instance and add GPU configuration
@@ -377,7 +452,7 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 	// Retry creation until success, for 10 minutes
 	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
 		func() error {
-			server, err := buildGcpMachine(s.ComputeService, s.GcpConfig.ProjectID, request.ResourceName, rim.URL, s.GcpConfig.Region, s.GcpConfig.Zone, s.GcpConfig.NetworkName, defaultNetwork.Name, string(userDataPhase1), isGateway, template)
+			server, err := buildGcpMachine(s.ComputeService, s.GcpConfig.ProjectID, request.ResourceName, rim.URL, s.GcpConfig.Region, s.GcpConfig.Zone, s.GcpConfig.NetworkName, defaultNetwork.Name, string(userDataPhase1), isGateway, template, request.Tags)
 			if err != nil {
 				if server != nil {
 					// try deleting server
@@ -535,7 +610,7 @@ func publicAccess(isPublic bool) []*compute.AccessConfig {
 }
 
 // buildGcpMachine ...
-func buildGcpMachine(service *compute.Service, projectID string, instanceName string, imageID string, region string, zone string, network string, subnetwork string, userdata string, isPublic bool, template *resources.HostTemplate) (*resources.Host, error) {
+func buildGcpMachine(service *compute.Service, projectID string, instanceName string, imageID string, region string, zone string, network string, subnetwork string, userdata string, isPublic bool, template *resources.HostTemplate, tags map[string]string) (*resources.Host, error) {
 	prefix := "https://www.googleapis.com/compute/v1/projects/" + projectID
 
 	imageURL := imageID
@@ -553,6 +628,7 @@ func buildGcpMachine(service *compute.Service, projectID string, instanceName st
 		Tags: &compute.Tags{
 			Items: []string{tag},
 		},
+		Labels: toGCPLabels(tags),
 		Disks: []*compute.AttachedDisk{
 			{
 				AutoDelete: true,
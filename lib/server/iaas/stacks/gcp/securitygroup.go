@@ -0,0 +1,398 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// GCP has no security groups; a security group is emulated by a network tag:
+// - the security group itself is a disabled firewall rule named after the tag, holding its name and description
+// - each rule of the security group is a firewall rule named '<tag>-<suffix>' targeting the tag
+// - binding a host to the security group adds the tag to the instance
+const securityGroupPrefix = "safescale-sg-"
+
+var securityGroupNameRegexp = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,40}[a-z0-9])?$`)
+
+// waitForOperation waits until the operation op is done
+func (s *Stack) waitForOperation(op *compute.Operation) error {
+	oco := OpContext{
+		Operation:    op,
+		ProjectID:    s.GcpConfig.ProjectID,
+		Service:      s.ComputeService,
+		DesiredState: "DONE",
+	}
+	return waitUntilOperationIsSuccessfulOrTimeout(oco, temporal.GetMinDelay(), temporal.GetHostTimeout())
+}
+
+// isNotFound tells if err is a googleapi 404 error
+func isNotFound(err error) bool {
+	gerr, ok := err.(*googleapi.Error)
+	return ok && gerr.Code == 404
+}
+
+// securityGroupFirewalls returns the firewall rules of the security group identified by id
+func (s *Stack) securityGroupFirewalls(id string) ([]*compute.Firewall, error) {
+	var firewalls []*compute.Firewall
+	err := s.ComputeService.Firewalls.List(s.GcpConfig.ProjectID).Pages(context.Background(), func(page *compute.FirewallList) error {
+		for _, fw := range page.Items {
+			if strings.HasPrefix(fw.Name, id+"-") && len(fw.TargetTags) == 1 && fw.TargetTags[0] == id {
+				firewalls = append(firewalls, fw)
+			}
+		}
+		return nil
+	})
+	return firewalls, err
+}
+
+// toRule converts a firewall rule to a resources.SecurityGroupRule
+func toRule(fw *compute.Firewall) resources.SecurityGroupRule {
+	rule := resources.SecurityGroupRule{
+		ID:          fw.Name,
+		Description: fw.Description,
+		Direction:   ruledirection.INGRESS,
+		EtherType:   ipversion.IPv4,
+	}
+	ranges := fw.SourceRanges
+	if fw.Direction == "EGRESS" {
+		rule.Direction = ruledirection.EGRESS
+		ranges = fw.DestinationRanges
+	}
+	if len(ranges) > 0 {
+		rule.CIDR = ranges[0]
+		if strings.Contains(rule.CIDR, ":") {
+			rule.EtherType = ipversion.IPv6
+		}
+	}
+	if len(fw.Allowed) > 0 {
+		if fw.Allowed[0].IPProtocol != "all" {
+			rule.Protocol = fw.Allowed[0].IPProtocol
+		}
+		if len(fw.Allowed[0].Ports) > 0 {
+			ports := strings.SplitN(fw.Allowed[0].Ports[0], "-", 2)
+			rule.PortFrom, _ = strconv.Atoi(ports[0])
+			rule.PortTo = rule.PortFrom
+			if len(ports) == 2 {
+				rule.PortTo, _ = strconv.Atoi(ports[1])
+			}
+		}
+	}
+	return rule
+}
+
+// CreateSecurityGroup creates a security group without rules
+func (s *Stack) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if !securityGroupNameRegexp.MatchString(req.Name) {
+		return nil, scerr.InvalidParameterError("req.Name", "must be made of lowercase letters, digits and dashes, starting with a letter, with at most 42 characters")
+	}
+
+	id := securityGroupPrefix + req.Name
+	fw := compute.Firewall{
+		Name:        id,
+		Description: req.Description,
+		Network:     fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/global/networks/%s", s.GcpConfig.ProjectID, s.GcpConfig.NetworkName),
+		Direction:   "INGRESS",
+		Disabled:    true,
+		Allowed:     []*compute.FirewallAllowed{{IPProtocol: "all"}},
+		TargetTags:  []string{id},
+		// Priority is the lowest possible, the marker rule never filters anything
+		Priority:     65535,
+		SourceRanges: []string{"0.0.0.0/32"},
+	}
+	op, err := s.ComputeService.Firewalls.Insert(s.GcpConfig.ProjectID, &fw).Do()
+	if err != nil {
+		if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == 409 {
+			return nil, resources.ResourceDuplicateError("security group", req.Name)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error creating security group '%s'", req.Name))
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := resources.NewSecurityGroup()
+	sg.ID = id
+	sg.Name = req.Name
+	sg.Description = req.Description
+	sg.NetworkID = req.NetworkID
+	return sg, nil
+}
+
+// InspectSecurityGroup returns the security group identified by id
+func (s *Stack) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	fw, err := s.ComputeService.Firewalls.Get(s.GcpConfig.ProjectID, id).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resources.ResourceNotFoundError("security group", id)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error getting security group '%s'", id))
+	}
+	if !strings.HasPrefix(fw.Name, securityGroupPrefix) {
+		return nil, resources.ResourceNotFoundError("security group", id)
+	}
+
+	sg := resources.NewSecurityGroup()
+	sg.ID = fw.Name
+	sg.Name = strings.TrimPrefix(fw.Name, securityGroupPrefix)
+	sg.Description = fw.Description
+	firewalls, err := s.securityGroupFirewalls(id)
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("error listing rules of security group '%s'", id))
+	}
+	for _, fw := range firewalls {
+		sg.Rules = append(sg.Rules, toRule(fw))
+	}
+	return sg, nil
+}
+
+// ListSecurityGroups lists the security groups
+func (s *Stack) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var (
+		list  []*resources.SecurityGroup
+		rules = map[string][]resources.SecurityGroupRule{}
+	)
+	err := s.ComputeService.Firewalls.List(s.GcpConfig.ProjectID).Pages(context.Background(), func(page *compute.FirewallList) error {
+		for _, fw := range page.Items {
+			if !strings.HasPrefix(fw.Name, securityGroupPrefix) || len(fw.TargetTags) != 1 {
+				continue
+			}
+			if fw.Name == fw.TargetTags[0] {
+				sg := resources.NewSecurityGroup()
+				sg.ID = fw.Name
+				sg.Name = strings.TrimPrefix(fw.Name, securityGroupPrefix)
+				sg.Description = fw.Description
+				list = append(list, sg)
+			} else {
+				rules[fw.TargetTags[0]] = append(rules[fw.TargetTags[0]], toRule(fw))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, scerr.Wrap(err, "error listing security groups")
+	}
+	for _, sg := range list {
+		sg.Rules = rules[sg.ID]
+	}
+	return list, nil
+}
+
+// DeleteSecurityGroup deletes the security group identified by id, and its rules
+func (s *Stack) DeleteSecurityGroup(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	if _, err := s.InspectSecurityGroup(id); err != nil {
+		return err
+	}
+	firewalls, err := s.securityGroupFirewalls(id)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("error listing rules of security group '%s'", id))
+	}
+	for _, fw := range firewalls {
+		if err = s.deleteFirewall(fw.Name); err != nil {
+			return err
+		}
+	}
+	return s.deleteFirewall(id)
+}
+
+// deleteFirewall deletes the firewall rule named name
+func (s *Stack) deleteFirewall(name string) error {
+	op, err := s.ComputeService.Firewalls.Delete(s.GcpConfig.ProjectID, name).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return scerr.Wrap(err, fmt.Sprintf("error deleting firewall rule '%s'", name))
+	}
+	return s.waitForOperation(op)
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by id, and returns the security group updated
+func (s *Stack) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.InspectSecurityGroup(id); err != nil {
+		return nil, err
+	}
+
+	suffix, err := uuid.NewV4()
+	if err != nil {
+		return nil, scerr.Wrap(err, "failed to generate rule name")
+	}
+	allowed := &compute.FirewallAllowed{IPProtocol: "all"}
+	if rule.Protocol != "" {
+		allowed.IPProtocol = rule.Protocol
+	}
+	if rule.PortFrom > 0 {
+		if rule.PortTo == rule.PortFrom {
+			allowed.Ports = []string{strconv.Itoa(rule.PortFrom)}
+		} else {
+			allowed.Ports = []string{fmt.Sprintf("%d-%d", rule.PortFrom, rule.PortTo)}
+		}
+	}
+	fw := compute.Firewall{
+		Name:        fmt.Sprintf("%s-%s", id, suffix.String()[:8]),
+		Description: rule.Description,
+		Network:     fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/global/networks/%s", s.GcpConfig.ProjectID, s.GcpConfig.NetworkName),
+		Direction:   "INGRESS",
+		Allowed:     []*compute.FirewallAllowed{allowed},
+		TargetTags:  []string{id},
+		Priority:    900,
+	}
+	if rule.Direction == ruledirection.EGRESS {
+		fw.Direction = "EGRESS"
+		fw.DestinationRanges = []string{rule.CIDR}
+	} else {
+		fw.SourceRanges = []string{rule.CIDR}
+	}
+	op, err := s.ComputeService.Firewalls.Insert(s.GcpConfig.ProjectID, &fw).Do()
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("error adding rule to security group '%s'", id))
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return nil, err
+	}
+	return s.InspectSecurityGroup(id)
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by id
+func (s *Stack) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if !strings.HasPrefix(ruleID, id+"-") {
+		return nil, resources.ResourceNotFoundError("security group rule", ruleID)
+	}
+
+	_, err := s.ComputeService.Firewalls.Get(s.GcpConfig.ProjectID, ruleID).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resources.ResourceNotFoundError("security group rule", ruleID)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error getting rule '%s'", ruleID))
+	}
+	if err = s.deleteFirewall(ruleID); err != nil {
+		return nil, err
+	}
+	return s.InspectSecurityGroup(id)
+}
+
+// BindSecurityGroupToHost applies the rules of the security group identified by id to the host identified by hostID
+func (s *Stack) BindSecurityGroupToHost(id, hostID string) error {
+	return s.setInstanceTag(id, hostID, true)
+}
+
+// UnbindSecurityGroupFromHost removes the rules of the security group identified by id from the host identified by hostID
+func (s *Stack) UnbindSecurityGroupFromHost(id, hostID string) error {
+	return s.setInstanceTag(id, hostID, false)
+}
+
+// setInstanceTag adds (or removes if present is false) the network tag of the security group identified by id on the instance identified by hostID
+func (s *Stack) setInstanceTag(id, hostID string, present bool) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	instance, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, hostID).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return resources.ResourceNotFoundError("host", hostID)
+		}
+		return scerr.Wrap(err, fmt.Sprintf("error getting host '%s'", hostID))
+	}
+	tags := instance.Tags
+	if tags == nil {
+		tags = &compute.Tags{}
+	}
+	var items []string
+	found := false
+	for _, t := range tags.Items {
+		if t == id {
+			found = true
+			if !present {
+				continue
+			}
+		}
+		items = append(items, t)
+	}
+	if found == present {
+		return nil
+	}
+	if present {
+		items = append(items, id)
+	}
+	op, err := s.ComputeService.Instances.SetTags(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instance.Name, &compute.Tags{
+		Items:       items,
+		Fingerprint: tags.Fingerprint,
+	}).Do()
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("error updating network tags of host '%s'", hostID))
+	}
+	return s.waitForOperation(op)
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"regexp"
+	"strings"
+
+	"google.golang.org/api/compute/v1"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// invalidLabelChars matches the characters not allowed in GCP labels
+var invalidLabelChars = regexp.MustCompile("[^a-z0-9_-]")
+
+// toGCPLabel converts a tag key or value to a valid GCP label key or value (lowercase letters, digits, '_' and '-')
+func toGCPLabel(s string) string {
+	return invalidLabelChars.ReplaceAllString(strings.ToLower(s), "_")
+}
+
+// toGCPLabels converts user-defined tags to GCP labels
+func toGCPLabels(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(tags))
+	for k, v := range tags {
+		labels[toGCPLabel(k)] = toGCPLabel(v)
+	}
+	return labels
+}
+
+// mergeGCPLabels returns the labels resulting of the update of current with set and the removal of unset
+func mergeGCPLabels(current map[string]string, set map[string]string, unset []string) map[string]string {
+	removed := make([]string, 0, len(unset))
+	for _, k := range unset {
+		removed = append(removed, toGCPLabel(k))
+	}
+	return resources.MergeTags(current, toGCPLabels(set), removed)
+}
+
+// UpdateHostTags sets the tags in set and removes the tags in unset on the instance identified by id;
+// tags are stored as labels, converted to the characters allowed by GCP
+func (s *Stack) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	instance, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return resources.ResourceNotFoundError("host", id)
+		}
+		return err
+	}
+	op, err := s.ComputeService.Instances.SetLabels(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instance.Name, &compute.InstancesSetLabelsRequest{
+		Labels:           mergeGCPLabels(instance.Labels, set, unset),
+		LabelFingerprint: instance.LabelFingerprint,
+	}).Do()
+	if err != nil {
+		return err
+	}
+	return s.waitForOperation(op)
+}
+
+// UpdateVolumeTags sets the tags in set and removes the tags in unset on the disk identified by id;
+// tags are stored as labels, converted to the characters allowed by GCP
+func (s *Stack) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	disk, err := s.ComputeService.Disks.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return resources.ResourceNotFoundError("volume", id)
+		}
+		return err
+	}
+	op, err := s.ComputeService.Disks.SetLabels(s.GcpConfig.ProjectID, s.GcpConfig.Zone, disk.Name, &compute.ZoneSetLabelsRequest{
+		Labels:           mergeGCPLabels(disk.Labels, set, unset),
+		LabelFingerprint: disk.LabelFingerprint,
+	}).Do()
+	if err != nil {
+		return err
+	}
+	return s.waitForOperation(op)
+}
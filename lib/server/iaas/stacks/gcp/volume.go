@@ -17,9 +17,11 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/api/compute/v1"
 
@@ -52,6 +54,7 @@ func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume
 		SizeGb: int64(request.Size),
 		Type:   selectedType,
 		Zone:   s.GcpConfig.Zone,
+		Labels: toGCPLabels(request.Tags),
 	}
 
 	service := s.ComputeService
@@ -363,3 +366,183 @@ func newGcpDiskAttachmentFromID(theID string) *gcpDiskAttachment {
 	}
 	return nil
 }
+
+//-------------Volume Snapshots Management------------------------------------------------------------------------------
+
+func snapshotStateConvert(gcpSnapshotStatus string) volumestate.Enum {
+	switch gcpSnapshotStatus {
+	case "CREATING", "UPLOADING":
+		return volumestate.CREATING
+	case "READY":
+		return volumestate.AVAILABLE
+	case "DELETING":
+		return volumestate.DELETING
+	case "FAILED":
+		return volumestate.ERROR
+	default:
+		return volumestate.OTHER
+	}
+}
+
+func toVolumeSnapshot(snap *compute.Snapshot) *resources.VolumeSnapshot {
+	vs := resources.NewVolumeSnapshot()
+	vs.ID = strconv.FormatUint(snap.Id, 10)
+	vs.Name = snap.Name
+	vs.Description = snap.Description
+	vs.VolumeID = snap.SourceDiskId
+	vs.VolumeName = getResourceNameFromSelfLink(genURL(snap.SourceDisk))
+	vs.Size = int(snap.DiskSizeGb)
+	vs.State = snapshotStateConvert(snap.Status)
+	vs.CreatedAt, _ = time.Parse(time.RFC3339, snap.CreationTimestamp)
+	return vs
+}
+
+// CreateVolumeSnapshot creates a snapshot of a persistent disk; the name of the snapshot must be unique in the project
+// and follow the naming rules of GCP (lowercase letters, digits and dashes)
+func (s *Stack) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, scerr.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+
+	op, err := s.ComputeService.Disks.CreateSnapshot(s.GcpConfig.ProjectID, s.GcpConfig.Zone, request.VolumeID, &compute.Snapshot{
+		Name:        request.Name,
+		Description: request.Description,
+	}).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resources.ResourceNotFoundError("volume", request.VolumeID)
+		}
+		return nil, err
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetVolumeSnapshot(request.Name)
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by ref (id or name)
+func (s *Stack) GetVolumeSnapshot(ref string) (*resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	snap, err := s.ComputeService.Snapshots.Get(s.GcpConfig.ProjectID, ref).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resources.ResourceNotFoundError("volume snapshot", ref)
+		}
+		return nil, err
+	}
+	return toVolumeSnapshot(snap), nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the project, restricted to those of the disk identified by volumeID
+// (id or name) if not empty
+func (s *Stack) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var list []resources.VolumeSnapshot
+	err := s.ComputeService.Snapshots.List(s.GcpConfig.ProjectID).Pages(context.Background(), func(page *compute.SnapshotList) error {
+		for _, snap := range page.Items {
+			vs := toVolumeSnapshot(snap)
+			if volumeID == "" || vs.VolumeID == volumeID || vs.VolumeName == volumeID {
+				list = append(list, *vs)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("cannot list volume snapshots: %v", err), err)
+	}
+	return list, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by ref (id or name)
+func (s *Stack) DeleteVolumeSnapshot(ref string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+
+	op, err := s.ComputeService.Snapshots.Delete(s.GcpConfig.ProjectID, ref).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return resources.ResourceNotFoundError("volume snapshot", ref)
+		}
+		return err
+	}
+	return s.waitForOperation(op)
+}
+
+// CreateVolumeFromSnapshot creates a persistent disk from the snapshot identified by snapshotID (id or name)
+func (s *Stack) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	snap, err := s.ComputeService.Snapshots.Get(s.GcpConfig.ProjectID, snapshotID).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resources.ResourceNotFoundError("volume snapshot", snapshotID)
+		}
+		return nil, err
+	}
+
+	selectedType := fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-standard", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
+	if request.Speed == volumespeed.SSD {
+		selectedType = fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-ssd", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
+	}
+	newDisk := &compute.Disk{
+		Name:           request.Name,
+		SizeGb:         int64(request.Size),
+		SourceSnapshot: snap.SelfLink,
+		Type:           selectedType,
+		Zone:           s.GcpConfig.Zone,
+	}
+	op, err := s.ComputeService.Disks.Insert(s.GcpConfig.ProjectID, s.GcpConfig.Zone, newDisk).Do()
+	if err != nil {
+		return nil, err
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetVolume(request.Name)
+}
+
+// ResizeVolume extends the persistent disk identified by ref (id or name) to size GB; GCE resizes disks
+// attached to running instances
+func (s *Stack) ResizeVolume(ref string, size int) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if size <= 0 {
+		return nil, scerr.InvalidParameterError("size", "must be greater than 0")
+	}
+
+	op, err := s.ComputeService.Disks.Resize(s.GcpConfig.ProjectID, s.GcpConfig.Zone, ref, &compute.DisksResizeRequest{
+		SizeGb: int64(size),
+	}).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resources.ResourceNotFoundError("volume", ref)
+		}
+		return nil, err
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetVolume(ref)
+}
@@ -384,6 +384,7 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 		FlavorRef:        request.TemplateID,
 		UserData:         userDataPhase1,
 		AvailabilityZone: az,
+		Metadata:         request.Tags,
 	}
 	// Defines host "Extension bootfromvolume" options
 	bdOpts := bootdiskCreateOptsExt{
@@ -208,3 +208,7 @@ func (s *Stack) InitDefaultSecurityGroup() error {
 	s.Stack.DefaultSecurityGroupDescription = "Default security group for VPC " + s.authOpts.VPCName
 	return s.Stack.InitDefaultSecurityGroup()
 }
+
+// Note: user-managed security groups (CreateSecurityGroup, AddRuleToSecurityGroup, BindSecurityGroupToHost, ...)
+// are inherited from the openstack stack, Huawei Cloud providing the neutron security groups API and the
+// os-security-groups extension of nova
@@ -100,6 +100,7 @@ func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume
 		Name:             request.Name,
 		Size:             request.Size,
 		VolumeType:       s.getVolumeType(request.Speed),
+		Metadata:         request.Tags,
 	}
 	vol, err := volumes.Create(s.Stack.VolumeClient, opts).Extract()
 	if err != nil {
@@ -186,3 +187,7 @@ func (s *Stack) ListVolumeAttachments(serverID string) ([]resources.VolumeAttach
 func (s *Stack) DeleteVolumeAttachment(serverID, vaID string) error {
 	return s.Stack.DeleteVolumeAttachment(serverID, vaID)
 }
+
+// Note: volume snapshots (CreateVolumeSnapshot, ListVolumeSnapshots, CreateVolumeFromSnapshot, ...) and ResizeVolume
+// are inherited from the openstack stack, the EVS service of Huawei Cloud providing the snapshots and volume actions
+// APIs of cinder v2
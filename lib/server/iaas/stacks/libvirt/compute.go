@@ -188,6 +188,175 @@ func (s *Stack) GetImage(id string) (image *resources.Image, err error) {
 	return nil, scerr.Errorf(fmt.Sprintf("image with id=%s not found", id), err)
 }
 
+// readImagesJSON reads the content of the images description file
+func readImagesJSON(s *Stack) (map[string]interface{}, error) {
+	byteValue, err := ioutil.ReadFile(s.LibvirtConfig.ImagesJSONPath)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to read %s : %s", s.LibvirtConfig.ImagesJSONPath, err.Error()), err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(byteValue, &result); err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to unmarshal jsonFile %s : %s", s.LibvirtConfig.ImagesJSONPath, err.Error()), err)
+	}
+	return result, nil
+}
+
+// writeImagesJSON replaces the content of the images description file
+func writeImagesJSON(s *Stack, content map[string]interface{}) error {
+	byteValue, err := json.MarshalIndent(content, "", "    ")
+	if err != nil {
+		return scerr.Errorf(fmt.Sprintf("failed to marshal images : %s", err.Error()), err)
+	}
+	err = ioutil.WriteFile(s.LibvirtConfig.ImagesJSONPath, byteValue, 0644)
+	if err != nil {
+		return scerr.Errorf(fmt.Sprintf("failed to write %s : %s", s.LibvirtConfig.ImagesJSONPath, err.Error()), err)
+	}
+	return nil
+}
+
+// CreateImage creates an image from the root disk of the host identified by hostID
+// The host is shut down during the copy of its disk then restarted
+func (s *Stack) CreateImage(hostID string, name string) (*resources.Image, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if hostID == "" {
+		return nil, scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	if _, err := s.GetImage(name); err == nil {
+		return nil, resources.ResourceDuplicateError("image", name)
+	}
+
+	_, domain, err := s.getHostAndDomainFromRef(hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	domainXML, err := domain.GetXMLDesc(0)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed get xml description of a domain : %s", err.Error()), err)
+	}
+	domainDescription := &libvirtxml.Domain{}
+	err = xml.Unmarshal([]byte(domainXML), domainDescription)
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed unmarshall the domain description : %s", err.Error()), err)
+	}
+	if len(domainDescription.Devices.Disks) == 0 || domainDescription.Devices.Disks[0].Source == nil || domainDescription.Devices.Disks[0].Source.File == nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to find the root disk of host '%s'", hostID), nil)
+	}
+	rootDiskPath := domainDescription.Devices.Disks[0].Source.File.File
+
+	imageDir := filepath.Join(s.LibvirtConfig.LibvirtStorage, "images")
+	if err = os.MkdirAll(imageDir, 0755); err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to create directory %s : %s", imageDir, err.Error()), err)
+	}
+	imagePath := filepath.Join(imageDir, name+".qcow2")
+
+	isActive, err := domain.IsActive()
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to know if the domain is active : %s", err.Error()), err)
+	}
+	if isActive {
+		err = s.StopHost(hostID)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if derr := s.StartHost(hostID); derr != nil {
+				logrus.Warnf("failed to restart host '%s' after image creation: %v", hostID, derr)
+			}
+		}()
+		err = retry.WhileUnsuccessfulDelay5Seconds(
+			func() error {
+				active, innerErr := domain.IsActive()
+				if innerErr != nil {
+					return innerErr
+				}
+				if active {
+					return fmt.Errorf("host '%s' is still running", hostID)
+				}
+				return nil
+			},
+			temporal.GetHostTimeout(),
+		)
+		if err != nil {
+			return nil, scerr.Errorf(fmt.Sprintf("failed to stop host '%s' : %s", hostID, err.Error()), err)
+		}
+	}
+
+	command := fmt.Sprintf("qemu-img convert -O qcow2 %s %s && virt-inspector -a %s | xmllint --xpath 'string(//operatingsystem/root)' -", rootDiskPath, imagePath, imagePath)
+	cmd := exec.Command("bash", "-c", command)
+	cmdOutput := &bytes.Buffer{}
+	cmdError := &bytes.Buffer{}
+	cmd.Stdout = cmdOutput
+	cmd.Stderr = cmdError
+	if err = cmd.Run(); err != nil {
+		_ = os.Remove(imagePath)
+		logrus.Errorf("Commands failed: [%s] with error [%s], stdOutput [%s] and stdError [%s]", command, err.Error(), cmdOutput.String(), cmdError.String())
+		return nil, scerr.Errorf(fmt.Sprintf("Commands failed : \n%s\n%s", command, err.Error()), err)
+	}
+	rootDisk := strings.TrimSpace(cmdOutput.String())
+
+	result, err := readImagesJSON(s)
+	if err != nil {
+		_ = os.Remove(imagePath)
+		return nil, err
+	}
+	image := resources.Image{
+		ID:   uuid.NewV4().String(),
+		Name: name,
+	}
+	result["images"] = append(result["images"].([]interface{}), map[string]interface{}{
+		"imageID":   image.ID,
+		"imageName": image.Name,
+		"imagePath": imagePath,
+		"disk":      rootDisk,
+	})
+	if err = writeImagesJSON(s, result); err != nil {
+		_ = os.Remove(imagePath)
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// DeleteImage deletes an image created with CreateImage
+func (s *Stack) DeleteImage(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	result, err := readImagesJSON(s)
+	if err != nil {
+		return err
+	}
+
+	imagesJSON := result["images"].([]interface{})
+	for i, imageJSON := range imagesJSON {
+		if imageID, _ := imageJSON.(map[string]interface{})["imageID"]; imageID == id {
+			path := imageJSON.(map[string]interface{})["imagePath"].(string)
+			result["images"] = append(imagesJSON[:i], imagesJSON[i+1:]...)
+			if err = writeImagesJSON(s, result); err != nil {
+				return err
+			}
+			if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+				logrus.Warnf("failed to remove image file '%s': %v", path, err)
+			}
+			return nil
+		}
+	}
+
+	return resources.ResourceNotFoundError("image", id)
+}
+
 //-------------TEMPLATES------------------------------------------------------------------------------------------------
 
 // ListTemplates overload OpenStack ListTemplate method to filter wind and flex instance and add GPU configuration
@@ -1138,6 +1307,11 @@ func (s *Stack) ResizeHost(id string, request resources.SizingRequirements) (*re
 	return nil, scerr.NotImplementedError("ResizeHost() not implemented yet") // FIXME Technical debt
 }
 
+// UpdateHostTags updates the tags of the host on provider side
+func (s *Stack) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	return scerr.NotImplementedError("UpdateHostTags() not implemented yet") // FIXME Technical debt
+}
+
 // ListHosts lists available hosts
 func (s *Stack) ListHosts() ([]*resources.Host, error) {
 	var hosts []*resources.Host
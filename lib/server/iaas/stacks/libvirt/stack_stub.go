@@ -55,6 +55,16 @@ func (s *Stack) GetImage(id string) (*resources.Image, error) {
 	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// CreateImage stub
+func (s *Stack) CreateImage(hostID string, name string) (*resources.Image, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteImage stub
+func (s *Stack) DeleteImage(id string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // GetTemplate stub
 func (s *Stack) GetTemplate(id string) (*resources.HostTemplate, error) {
 	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
@@ -155,6 +165,11 @@ func (s *Stack) ResizeHost(id string, request resources.SizingRequirements) (*re
 	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// UpdateHostTags stub
+func (s *Stack) UpdateHostTags(id string, set map[string]string, unset []string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // InspectHost stub
 func (s *Stack) InspectHost(interface{}) (*resources.Host, error) {
 	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
@@ -215,6 +230,41 @@ func (s *Stack) DeleteVolume(id string) error {
 	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// CreateVolumeSnapshot stub
+func (s *Stack) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// GetVolumeSnapshot stub
+func (s *Stack) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// ListVolumeSnapshots stub
+func (s *Stack) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteVolumeSnapshot stub
+func (s *Stack) DeleteVolumeSnapshot(id string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// CreateVolumeFromSnapshot stub
+func (s *Stack) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// ResizeVolume stub
+func (s *Stack) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// UpdateVolumeTags stub
+func (s *Stack) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // CreateVolumeAttachment stub
 func (s *Stack) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (string, error) {
 	return "", scerr.Errorf(fmt.Sprintf(errorStr), nil)
@@ -235,6 +285,46 @@ func (s *Stack) DeleteVolumeAttachment(serverID, id string) error {
 	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// CreateSecurityGroup stub
+func (s *Stack) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// InspectSecurityGroup stub
+func (s *Stack) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// ListSecurityGroups stub
+func (s *Stack) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteSecurityGroup stub
+func (s *Stack) DeleteSecurityGroup(id string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// AddRuleToSecurityGroup stub
+func (s *Stack) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DeleteRuleFromSecurityGroup stub
+func (s *Stack) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// BindSecurityGroupToHost stub
+func (s *Stack) BindSecurityGroupToHost(id, hostID string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// UnbindSecurityGroupFromHost stub
+func (s *Stack) UnbindSecurityGroupFromHost(id, hostID string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // GetConfigurationOptions stub
 func (s *Stack) GetConfigurationOptions() stacks.ConfigurationOptions {
 	return stacks.ConfigurationOptions{}
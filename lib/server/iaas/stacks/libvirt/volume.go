@@ -31,6 +31,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/libvirt/libvirt-go"
 	libvirtxml "github.com/libvirt/libvirt-go-xml"
 )
@@ -498,3 +499,38 @@ func (s *Stack) ListVolumeAttachments(serverID string) ([]resources.VolumeAttach
 
 	return volumeAttachments, nil
 }
+
+// CreateVolumeSnapshot creates a snapshot of a block volume
+func (s *Stack) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *Stack) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("GetVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume
+func (s *Stack) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	return nil, scerr.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME Technical debt
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *Stack) DeleteVolumeSnapshot(id string) error {
+	return scerr.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// CreateVolumeFromSnapshot creates a block volume from a snapshot
+func (s *Stack) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	return nil, scerr.NotImplementedError("CreateVolumeFromSnapshot() not implemented yet") // FIXME Technical debt
+}
+
+// ResizeVolume extends a block volume
+func (s *Stack) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	return nil, scerr.NotImplementedError("ResizeVolume() not implemented yet") // FIXME Technical debt
+}
+
+// UpdateVolumeTags updates the tags of the volume on provider side
+func (s *Stack) UpdateVolumeTags(id string, set map[string]string, unset []string) error {
+	return scerr.NotImplementedError("UpdateVolumeTags() not implemented yet") // FIXME Technical debt
+}
@@ -185,6 +185,85 @@ func (s *Stack) GetImage(id string) (image *resources.Image, err error) {
 	return &resources.Image{ID: img.ID, Name: img.Name}, nil
 }
 
+// CreateImage creates an image from the system disk of the host identified by hostID
+// The image is usable when this function returns
+func (s *Stack) CreateImage(hostID string, name string) (image *resources.Image, err error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if hostID == "" {
+		return nil, scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", hostID, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	imageID, err := servers.CreateImage(s.ComputeClient, hostID, servers.CreateImageOpts{Name: name}).ExtractImageID()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return nil, resources.ResourceNotFoundError("host", hostID)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error creating image: %s", ProviderErrorToString(err)))
+	}
+
+	// Nova snapshots the disk then uploads it to Glance; waits for the image to become active
+	var img *images.Image
+	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			var innerErr error
+			img, innerErr = images.Get(s.ComputeClient, imageID).Extract()
+			if innerErr != nil {
+				return innerErr
+			}
+			switch img.Status {
+			case images.ImageStatusActive:
+				return nil
+			case images.ImageStatusKilled, images.ImageStatusDeleted:
+				return retry.AbortedError(fmt.Sprintf("image '%s' is in state '%s'", name, img.Status), nil)
+			default:
+				return scerr.NotAvailableError(fmt.Sprintf("image '%s' is not yet active", name))
+			}
+		},
+		temporal.GetLongOperationTimeout(),
+	)
+	if retryErr != nil {
+		derr := images.Delete(s.ComputeClient, imageID).ExtractErr()
+		if derr != nil {
+			retryErr = scerr.AddConsequence(retryErr, derr)
+		}
+		return nil, retryErr
+	}
+
+	return &resources.Image{ID: img.ID, Name: img.Name}, nil
+}
+
+// DeleteImage deletes the image identified by id
+func (s *Stack) DeleteImage(id string) (err error) {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = images.Delete(s.ComputeClient, id).ExtractErr()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return resources.ResourceNotFoundError("image", id)
+		}
+		return scerr.Wrap(err, fmt.Sprintf("error deleting image: %s", ProviderErrorToString(err)))
+	}
+	return nil
+}
+
 // GetTemplate returns the Template referenced by id
 func (s *Stack) GetTemplate(id string) (template *resources.HostTemplate, err error) {
 	if s == nil {
@@ -780,6 +859,7 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 		ImageRef:         request.ImageID,
 		UserData:         userDataPhase1,
 		AvailabilityZone: azone,
+		Metadata:         request.Tags,
 	}
 
 	// --- Initializes resources.Host ---
@@ -1377,3 +1457,38 @@ func (s *Stack) ResizeHost(id string, request resources.SizingRequirements) (*re
 
 	return nil, scerr.NotImplementedError("ResizeHost() not implemented yet") // FIXME Technical debt
 }
+
+// UpdateHostTags sets the tags in set and removes the tags in unset on the host identified by id;
+// tags are stored as metadata of the server
+func (s *Stack) UpdateHostTags(id string, set map[string]string, unset []string) (err error) {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if len(set) > 0 {
+		_, err = servers.UpdateMetadata(s.ComputeClient, id, servers.MetadataOpts(set)).Extract()
+		if err != nil {
+			if _, ok := err.(gc.ErrDefault404); ok {
+				return resources.ResourceNotFoundError("host", id)
+			}
+			return scerr.Wrap(err, fmt.Sprintf("error setting tags of host: %s", ProviderErrorToString(err)))
+		}
+	}
+	for _, key := range unset {
+		err = servers.DeleteMetadatum(s.ComputeClient, id, key).ExtractErr()
+		if err != nil {
+			if _, ok := err.(gc.ErrDefault404); ok {
+				continue
+			}
+			return scerr.Wrap(err, fmt.Sprintf("error removing tag '%s' of host: %s", key, ProviderErrorToString(err)))
+		}
+	}
+	return nil
+}
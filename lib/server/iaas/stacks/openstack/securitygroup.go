@@ -18,12 +18,19 @@ package openstack
 
 import (
 	"fmt"
-	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 
-	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	gc "github.com/gophercloud/gophercloud"
+	computesecgroups "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/secgroups"
 	secgroups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	secrules "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
 	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ruledirection"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 )
 
 // GetSecurityGroup returns the default security group
@@ -276,3 +283,253 @@ func (s *Stack) InitDefaultSecurityGroup() error {
 	s.SecurityGroup = group
 	return nil
 }
+
+// toSecurityGroup converts a neutron security group to a resources.SecurityGroup
+func toSecurityGroup(group *secgroups.SecGroup) *resources.SecurityGroup {
+	sg := resources.NewSecurityGroup()
+	sg.ID = group.ID
+	sg.Name = group.Name
+	sg.Description = group.Description
+	for _, r := range group.Rules {
+		rule := resources.SecurityGroupRule{
+			ID:          r.ID,
+			Description: r.Description,
+			Direction:   ruledirection.INGRESS,
+			EtherType:   ipversion.IPv4,
+			Protocol:    r.Protocol,
+			PortFrom:    r.PortRangeMin,
+			PortTo:      r.PortRangeMax,
+			CIDR:        r.RemoteIPPrefix,
+		}
+		if r.Direction == string(secrules.DirEgress) {
+			rule.Direction = ruledirection.EGRESS
+		}
+		if r.EtherType == string(secrules.EtherType6) {
+			rule.EtherType = ipversion.IPv6
+		}
+		sg.Rules = append(sg.Rules, rule)
+	}
+	return sg
+}
+
+// CreateSecurityGroup creates a security group without rules
+// Note: neutron adds by itself rules allowing all the egress traffic; they are removed to start from an empty set
+func (s *Stack) CreateSecurityGroup(req resources.SecurityGroupRequest) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nil, scerr.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("('%s')", req.Name), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	opts := secgroups.CreateOpts{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	group, err := secgroups.Create(s.NetworkClient, opts).Extract()
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("error creating security group '%s': %s", req.Name, ProviderErrorToString(err)))
+	}
+	for _, r := range group.Rules {
+		err = secrules.Delete(s.NetworkClient, r.ID).ExtractErr()
+		if err != nil {
+			_ = secgroups.Delete(s.NetworkClient, group.ID).ExtractErr()
+			return nil, scerr.Wrap(err, fmt.Sprintf("error cleaning default rules of security group '%s': %s", req.Name, ProviderErrorToString(err)))
+		}
+	}
+	group.Rules = nil
+
+	sg := toSecurityGroup(group)
+	sg.NetworkID = req.NetworkID
+	return sg, nil
+}
+
+// InspectSecurityGroup returns the security group identified by id
+func (s *Stack) InspectSecurityGroup(id string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	group, err := secgroups.Get(s.NetworkClient, id).Extract()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return nil, resources.ResourceNotFoundError("security group", id)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error getting security group: %s", ProviderErrorToString(err)))
+	}
+	return toSecurityGroup(group), nil
+}
+
+// ListSecurityGroups lists the security groups, except the default one used internally by SafeScale
+func (s *Stack) ListSecurityGroups() ([]*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	defer concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var list []*resources.SecurityGroup
+	err := secgroups.List(s.NetworkClient, secgroups.ListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+		groups, err := secgroups.ExtractGroups(page)
+		if err != nil {
+			return false, err
+		}
+		for i := range groups {
+			if groups[i].Name == s.DefaultSecurityGroupName || groups[i].Name == "default" {
+				continue
+			}
+			list = append(list, toSecurityGroup(&groups[i]))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("error listing security groups: %s", ProviderErrorToString(err)))
+	}
+	return list, nil
+}
+
+// DeleteSecurityGroup deletes the security group identified by id
+func (s *Stack) DeleteSecurityGroup(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := secgroups.Delete(s.NetworkClient, id).ExtractErr()
+	if err != nil {
+		switch err.(type) {
+		case gc.ErrDefault404:
+			return resources.ResourceNotFoundError("security group", id)
+		case gc.ErrDefault409:
+			return scerr.NotAvailableError(fmt.Sprintf("security group '%s' is still in use", id))
+		}
+		return scerr.Wrap(err, fmt.Sprintf("error deleting security group: %s", ProviderErrorToString(err)))
+	}
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to the security group identified by id, and returns the security group updated
+func (s *Stack) AddRuleToSecurityGroup(id string, rule resources.SecurityGroupRule) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	opts := secrules.CreateOpts{
+		Description:    rule.Description,
+		Direction:      secrules.DirIngress,
+		EtherType:      secrules.EtherType4,
+		SecGroupID:     id,
+		PortRangeMin:   rule.PortFrom,
+		PortRangeMax:   rule.PortTo,
+		Protocol:       secrules.RuleProtocol(rule.Protocol),
+		RemoteIPPrefix: rule.CIDR,
+	}
+	if rule.Direction == ruledirection.EGRESS {
+		opts.Direction = secrules.DirEgress
+	}
+	if rule.EtherType == ipversion.IPv6 {
+		opts.EtherType = secrules.EtherType6
+	}
+	_, err := secrules.Create(s.NetworkClient, opts).Extract()
+	if err != nil {
+		switch err.(type) {
+		case gc.ErrDefault404:
+			return nil, resources.ResourceNotFoundError("security group", id)
+		case gc.ErrDefault409:
+			return nil, resources.ResourceDuplicateError("security group rule", fmt.Sprintf("%s %s %d-%d %s", rule.Direction, rule.Protocol, rule.PortFrom, rule.PortTo, rule.CIDR))
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error adding rule to security group: %s", ProviderErrorToString(err)))
+	}
+	return s.InspectSecurityGroup(id)
+}
+
+// DeleteRuleFromSecurityGroup deletes the rule identified by ruleID from the security group identified by id
+func (s *Stack) DeleteRuleFromSecurityGroup(id, ruleID string) (*resources.SecurityGroup, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if ruleID == "" {
+		return nil, scerr.InvalidParameterError("ruleID", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s, %s)", id, ruleID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := secrules.Delete(s.NetworkClient, ruleID).ExtractErr()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return nil, resources.ResourceNotFoundError("security group rule", ruleID)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error deleting rule of security group: %s", ProviderErrorToString(err)))
+	}
+	return s.InspectSecurityGroup(id)
+}
+
+// BindSecurityGroupToHost applies the rules of the security group identified by id to the host identified by hostID
+func (s *Stack) BindSecurityGroupToHost(id, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s, %s)", id, hostID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := computesecgroups.AddServer(s.ComputeClient, hostID, id).ExtractErr()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return resources.ResourceNotFoundError("host", hostID)
+		}
+		return scerr.Wrap(err, fmt.Sprintf("error binding security group to host: %s", ProviderErrorToString(err)))
+	}
+	return nil
+}
+
+// UnbindSecurityGroupFromHost removes the rules of the security group identified by id from the host identified by hostID
+func (s *Stack) UnbindSecurityGroupFromHost(id, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s, %s)", id, hostID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := computesecgroups.RemoveServer(s.ComputeClient, hostID, id).ExtractErr()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return resources.ResourceNotFoundError("host", hostID)
+		}
+		return scerr.Wrap(err, fmt.Sprintf("error unbinding security group from host: %s", ProviderErrorToString(err)))
+	}
+	return nil
+}
@@ -26,7 +26,9 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	gc "github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/volumeactions"
 	volumesv1 "github.com/gophercloud/gophercloud/openstack/blockstorage/v1/volumes"
+	snapshotsv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/snapshots"
 	volumesv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
 	"github.com/gophercloud/gophercloud/pagination"
@@ -122,6 +124,7 @@ func (s *Stack) CreateVolume(request resources.VolumeRequest) (volume *resources
 			Name:             request.Name,
 			Size:             request.Size,
 			VolumeType:       s.getVolumeType(request.Speed),
+			Metadata:         request.Tags,
 		}).Extract()
 		if err != nil {
 			break
@@ -144,6 +147,7 @@ func (s *Stack) CreateVolume(request resources.VolumeRequest) (volume *resources
 			Name:             request.Name,
 			Size:             request.Size,
 			VolumeType:       s.getVolumeType(request.Speed),
+			Metadata:         request.Tags,
 		}).Extract()
 		if err != nil {
 			break
@@ -388,3 +392,263 @@ func (s *Stack) DeleteVolumeAttachment(serverID, vaID string) error {
 	}
 	return nil
 }
+
+// toVolumeSnapshot converts a cinder snapshot to a resources.VolumeSnapshot
+func toVolumeSnapshot(snap *snapshotsv2.Snapshot) *resources.VolumeSnapshot {
+	vs := resources.NewVolumeSnapshot()
+	vs.ID = snap.ID
+	vs.Name = snap.Name
+	vs.Description = snap.Description
+	vs.VolumeID = snap.VolumeID
+	vs.Size = snap.Size
+	vs.State = toVolumeState(snap.Status)
+	vs.CreatedAt = snap.CreatedAt
+	return vs
+}
+
+// CreateVolumeSnapshot creates a snapshot of a block volume; the volume may be attached to a host,
+// the snapshot is then crash-consistent
+func (s *Stack) CreateVolumeSnapshot(request resources.VolumeSnapshotRequest) (*resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nil, scerr.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+	if request.VolumeID == "" {
+		return nil, scerr.InvalidParameterError("request.VolumeID", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", request.VolumeID, request.Name), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	snap, err := snapshotsv2.Create(s.VolumeClient, snapshotsv2.CreateOpts{
+		VolumeID:    request.VolumeID,
+		Name:        request.Name,
+		Description: request.Description,
+		Force:       true,
+	}).Extract()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return nil, resources.ResourceNotFoundError("volume", request.VolumeID)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error creating volume snapshot: %s", ProviderErrorToString(err)))
+	}
+	return toVolumeSnapshot(snap), nil
+}
+
+// GetVolumeSnapshot returns the volume snapshot identified by id
+func (s *Stack) GetVolumeSnapshot(id string) (*resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	snap, err := snapshotsv2.Get(s.VolumeClient, id).Extract()
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return nil, resources.ResourceNotFoundError("volume snapshot", id)
+		}
+		return nil, scerr.Wrap(err, fmt.Sprintf("error getting volume snapshot: %s", ProviderErrorToString(err)))
+	}
+	return toVolumeSnapshot(snap), nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID, or all the snapshots if volumeID is empty
+func (s *Stack) ListVolumeSnapshots(volumeID string) ([]resources.VolumeSnapshot, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", volumeID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var list []resources.VolumeSnapshot
+	err := snapshotsv2.List(s.VolumeClient, snapshotsv2.ListOpts{VolumeID: volumeID}).EachPage(func(page pagination.Page) (bool, error) {
+		snaps, err := snapshotsv2.ExtractSnapshots(page)
+		if err != nil {
+			return false, err
+		}
+		for _, snap := range snaps {
+			snap := snap
+			list = append(list, *toVolumeSnapshot(&snap))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("error listing volume snapshots: %s", ProviderErrorToString(err)))
+	}
+	return list, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s *Stack) DeleteVolumeSnapshot(id string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := snapshotsv2.Delete(s.VolumeClient, id).ExtractErr()
+	if err != nil {
+		switch err.(type) {
+		case gc.ErrDefault404:
+			return resources.ResourceNotFoundError("volume snapshot", id)
+		case gc.ErrDefault400:
+			return scerr.NotAvailableError(fmt.Sprintf("volume snapshot '%s' cannot be deleted in its current state: %s", id, ProviderErrorToString(err)))
+		default:
+			return scerr.Wrap(err, fmt.Sprintf("error deleting volume snapshot: %s", ProviderErrorToString(err)))
+		}
+	}
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a block volume from the content of the snapshot identified by snapshotID
+func (s *Stack) CreateVolumeFromSnapshot(snapshotID string, request resources.VolumeRequest) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if snapshotID == "" {
+		return nil, scerr.InvalidParameterError("snapshotID", "cannot be empty string")
+	}
+	if request.Name == "" {
+		return nil, scerr.InvalidParameterError("request.Name", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", snapshotID, request.Name), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	snap, err := s.GetVolumeSnapshot(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Size == 0 {
+		request.Size = snap.Size
+	}
+	if request.Size < snap.Size {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("the volume cannot be smaller than the snapshot (%d GB)", snap.Size))
+	}
+
+	az, err := s.SelectedAvailabilityZone()
+	if err != nil {
+		return nil, err
+	}
+	vol, err := volumesv2.Create(s.VolumeClient, volumesv2.CreateOpts{
+		AvailabilityZone: az,
+		Name:             request.Name,
+		Size:             request.Size,
+		SnapshotID:       snapshotID,
+		VolumeType:       s.getVolumeType(request.Speed),
+	}).Extract()
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("error creating volume from snapshot: %s", ProviderErrorToString(err)))
+	}
+	return &resources.Volume{
+		ID:    vol.ID,
+		Name:  vol.Name,
+		Size:  vol.Size,
+		Speed: s.getVolumeSpeed(vol.VolumeType),
+		State: toVolumeState(vol.Status),
+	}, nil
+}
+
+// ResizeVolume extends the volume identified by id to size GB
+// Note: Cinder only extends volumes in state 'available', so the volume has to be detached first
+func (s *Stack) ResizeVolume(id string, size int) (*resources.Volume, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+	if size <= 0 {
+		return nil, scerr.InvalidParameterError("size", "must be greater than 0")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", id, size), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := volumeactions.ExtendSize(s.VolumeClient, id, volumeactions.ExtendSizeOpts{NewSize: size}).ExtractErr()
+	if err != nil {
+		switch err.(type) {
+		case gc.ErrDefault404:
+			return nil, resources.ResourceNotFoundError("volume", id)
+		case gc.ErrDefault400:
+			return nil, scerr.NotAvailableError(fmt.Sprintf("volume '%s' cannot be resized in its current state: %s", id, ProviderErrorToString(err)))
+		default:
+			return nil, scerr.Wrap(err, fmt.Sprintf("error resizing volume: %s", ProviderErrorToString(err)))
+		}
+	}
+
+	// Cinder extends the volume asynchronously; waits for the volume to come back to 'available' with its new size
+	var volume *resources.Volume
+	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
+		func() error {
+			volume, err = s.GetVolume(id)
+			if err != nil {
+				return err
+			}
+			if volume.State == volumestate.ERROR {
+				return retry.AbortedError("volume in error state after resize", nil)
+			}
+			if volume.State != volumestate.AVAILABLE || volume.Size != size {
+				return scerr.NotAvailableError(fmt.Sprintf("volume '%s' is still being resized", id))
+			}
+			return nil
+		},
+		temporal.GetBigDelay(),
+	)
+	if retryErr != nil {
+		return nil, retryErr
+	}
+	return volume, nil
+}
+
+// UpdateVolumeTags sets the tags in set and removes the tags in unset on the volume identified by id;
+// tags are stored as metadata of the volume
+func (s *Stack) UpdateVolumeTags(id string, set map[string]string, unset []string) (err error) {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	// Cinder replaces the whole metadata of the volume on update, so the current one has to be merged first
+	switch s.versions["volume"] {
+	case "v1":
+		var vol *volumesv1.Volume
+		vol, err = volumesv1.Get(s.VolumeClient, id).Extract()
+		if err != nil {
+			break
+		}
+		_, err = volumesv1.Update(s.VolumeClient, id, volumesv1.UpdateOpts{
+			Metadata: resources.MergeTags(vol.Metadata, set, unset),
+		}).Extract()
+	case "v2":
+		var vol *volumesv2.Volume
+		vol, err = volumesv2.Get(s.VolumeClient, id).Extract()
+		if err != nil {
+			break
+		}
+		_, err = volumesv2.Update(s.VolumeClient, id, volumesv2.UpdateOpts{
+			Metadata: resources.MergeTags(vol.Metadata, set, unset),
+		}).Extract()
+	default:
+		return scerr.Errorf(fmt.Sprintf("unmanaged service 'volume' version '%s'", s.versions["volume"]), nil)
+	}
+	if err != nil {
+		if _, ok := err.(gc.ErrDefault404); ok {
+			return resources.ResourceNotFoundError("volume", id)
+		}
+		return scerr.Wrap(err, fmt.Sprintf("error updating tags of volume: %s", ProviderErrorToString(err)))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	fakeprovider "github.com/CS-SI/SafeScale/lib/server/iaas/providers/fake"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+	fakestack "github.com/CS-SI/SafeScale/lib/server/iaas/stacks/fake"
+)
+
+const fakeTenantsFile = `
+[[tenants]]
+name = "%s"
+client = "fake"
+
+[tenants.compute]
+Region = "fake-region"
+
+[tenants.objectstorage]
+Type = "memory"
+`
+
+// NewFakeService builds the service of the fake tenant 'tenant', whose resources and metadata are kept in memory.
+// Each test uses its own tenant name to start from an empty tenant.
+func NewFakeService(t *testing.T, tenant string) (iaas.Service, *fakestack.Stack) {
+	dir, err := ioutil.TempDir("", "safescale-fake")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "tenants.toml")
+	err = ioutil.WriteFile(path, []byte(fmt.Sprintf(fakeTenantsFile, tenant)), 0600)
+	require.Nil(t, err)
+
+	svc, err := iaas.UseServiceFromFile(tenant, path)
+	require.Nil(t, err)
+	stack, ok := fakeprovider.GetStack(tenant)
+	require.True(t, ok)
+	return svc, stack
+}
+
+// CreateNetwork creates a network at the provider, without metadata
+func CreateNetwork(t *testing.T, svc iaas.Service, name, cidr string) *resources.Network {
+	network, err := svc.CreateNetwork(resources.NetworkRequest{Name: name, CIDR: cidr})
+	require.Nil(t, err)
+	return network
+}
+
+// CreateHost creates a host connected to 'network' at the fake provider, without metadata; the host is public if
+// 'gateway' is nil
+func CreateHost(t *testing.T, svc iaas.Service, name string, network *resources.Network, gateway *resources.Host) *resources.Host {
+	host, _, err := svc.CreateHost(resources.HostRequest{
+		ResourceName:   name,
+		Networks:       []*resources.Network{network},
+		DefaultGateway: gateway,
+		PublicIP:       gateway == nil,
+		TemplateID:     "fake-tiny",
+		ImageID:        "fake-ubuntu-1804",
+	})
+	require.Nil(t, err)
+	return host
+}
+
+// CreateVolume creates a volume at the provider, without metadata, and attaches it to the hosts 'hostIDs'
+func CreateVolume(t *testing.T, svc iaas.Service, name string, hostIDs ...string) *resources.Volume {
+	volume, err := svc.CreateVolume(resources.VolumeRequest{Name: name, Size: 10, Speed: volumespeed.HDD})
+	require.Nil(t, err)
+	for _, hostID := range hostIDs {
+		_, err = svc.CreateVolumeAttachment(resources.VolumeAttachmentRequest{Name: name + "-" + hostID, VolumeID: volume.ID, HostID: hostID})
+		require.Nil(t, err)
+	}
+	return volume
+}
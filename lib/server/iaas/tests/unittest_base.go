@@ -35,11 +35,14 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ruledirection"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/api"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/docker"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/fake"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/gcp"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/huaweicloud"
 
@@ -48,6 +51,8 @@ import (
 
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/aws"            // Imported to initialize tenant ovh
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/cloudferro"     // Imported to initialize tenant ovh
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/docker"         // Imported to initialize tenant docker
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/fake"           // Imported to initialize tenant fake
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/flexibleengine" // Imported to initialize tenant flexibleengine
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/gcp"            // Imported to initialize tenant gcp
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/local"          // Imported to initialize tenant local
@@ -69,6 +74,8 @@ func (tester *ServiceTester) VerifyStacks(t *testing.T) {
 	stack = &openstack.Stack{}   // nolint
 	stack = &gcp.Stack{}         // nolint
 	stack = &aws.Stack{}         // nolint
+	stack = &fake.Stack{}        // nolint
+	stack = &docker.Stack{}      // nolint
 
 	_ = stack
 }
@@ -646,6 +653,225 @@ func (tester *ServiceTester) VolumeAttachment(t *testing.T) {
 	}
 }
 
+//VolumeSnapshots test
+func (tester *ServiceTester) VolumeSnapshots(t *testing.T) {
+	v1, err := tester.Service.CreateVolume(resources.VolumeRequest{
+		Name:  "test_volume_snap",
+		Size:  20,
+		Speed: volumespeed.HDD,
+	})
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteVolume(v1.ID)
+	}()
+	_, err = tester.Service.WaitVolumeState(v1.ID, volumestate.AVAILABLE, temporal.GetBigDelay())
+	require.Nil(t, err)
+
+	snap, err := tester.Service.CreateVolumeSnapshot(resources.VolumeSnapshotRequest{
+		Name:        "test-volume-snap-1",
+		Description: "unit test",
+		VolumeID:    v1.ID,
+	})
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteVolumeSnapshot(snap.ID)
+	}()
+	assert.Equal(t, "test-volume-snap-1", snap.Name)
+	assert.Equal(t, v1.ID, snap.VolumeID)
+	assert.Equal(t, v1.Size, snap.Size)
+
+	_, err = tester.Service.CreateVolumeSnapshot(resources.VolumeSnapshotRequest{
+		Name:     "test-volume-snap-2",
+		VolumeID: "unknown-volume",
+	})
+	assert.NotNil(t, err)
+
+	got, err := tester.Service.GetVolumeSnapshot(snap.ID)
+	require.Nil(t, err)
+	assert.Equal(t, snap.Name, got.Name)
+
+	lst, err := tester.Service.ListVolumeSnapshots(v1.ID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(lst))
+	assert.Equal(t, snap.ID, lst[0].ID)
+
+	_, err = tester.Service.CreateVolumeFromSnapshot(snap.ID, resources.VolumeRequest{
+		Name: "test_volume_restored_small",
+		Size: v1.Size - 1,
+	})
+	assert.NotNil(t, err)
+
+	v2, err := tester.Service.CreateVolumeFromSnapshot(snap.ID, resources.VolumeRequest{
+		Name:  "test_volume_restored",
+		Speed: volumespeed.SSD,
+	})
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteVolume(v2.ID)
+	}()
+	assert.Equal(t, "test_volume_restored", v2.Name)
+	assert.Equal(t, v1.Size, v2.Size)
+	assert.Equal(t, volumespeed.SSD, v2.Speed)
+
+	err = tester.Service.DeleteVolumeSnapshot(snap.ID)
+	assert.Nil(t, err)
+	_, err = tester.Service.GetVolumeSnapshot(snap.ID)
+	assert.NotNil(t, err)
+}
+
+//VolumeResize test
+func (tester *ServiceTester) VolumeResize(t *testing.T) {
+	v1, err := tester.Service.CreateVolume(resources.VolumeRequest{
+		Name:  "test_volume_resize",
+		Size:  20,
+		Speed: volumespeed.HDD,
+	})
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteVolume(v1.ID)
+	}()
+	_, err = tester.Service.WaitVolumeState(v1.ID, volumestate.AVAILABLE, temporal.GetBigDelay())
+	require.Nil(t, err)
+
+	resized, err := tester.Service.ResizeVolume(v1.ID, 40)
+	require.Nil(t, err)
+	assert.Equal(t, v1.ID, resized.ID)
+	assert.Equal(t, 40, resized.Size)
+
+	got, err := tester.Service.GetVolume(v1.ID)
+	require.Nil(t, err)
+	assert.Equal(t, 40, got.Size)
+
+	_, err = tester.Service.ResizeVolume("unknown-volume", 40)
+	assert.NotNil(t, err)
+}
+
+//Tags test
+func (tester *ServiceTester) Tags(t *testing.T) {
+	network, _ := tester.CreateNetwork(t, "unit_test_network_tags", false, "1.1.9.0/24")
+	defer func() {
+		_ = tester.Service.DeleteNetwork(network.ID)
+	}()
+	host, _, err := tester.CreateHost(t, "host-tags", network, false)
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteHost(host.ID)
+	}()
+
+	err = tester.Service.UpdateHostTags(host.ID, map[string]string{"env": "prod", "team": "geo"}, nil)
+	assert.Nil(t, err)
+	err = tester.Service.UpdateHostTags(host.ID, nil, []string{"team", "unknown"})
+	assert.Nil(t, err)
+	err = tester.Service.UpdateHostTags("unknown-host", map[string]string{"env": "prod"}, nil)
+	assert.NotNil(t, err)
+
+	v1, err := tester.Service.CreateVolume(resources.VolumeRequest{
+		Name:  "test_volume_tags",
+		Size:  20,
+		Speed: volumespeed.HDD,
+		Tags:  map[string]string{"backup": "daily"},
+	})
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteVolume(v1.ID)
+	}()
+
+	err = tester.Service.UpdateVolumeTags(v1.ID, map[string]string{"backup": "weekly"}, []string{"owner"})
+	assert.Nil(t, err)
+	err = tester.Service.UpdateVolumeTags("unknown-volume", nil, []string{"backup"})
+	assert.NotNil(t, err)
+}
+
+//SecurityGroups test
+func (tester *ServiceTester) SecurityGroups(t *testing.T) {
+	network, gw := tester.CreateNetwork(t, "unit-test-sg-network", true, "1.1.9.0/24")
+	defer func() {
+		_ = tester.Service.DeleteGateway(gw.ID)
+		_ = tester.Service.DeleteNetwork(network.ID)
+	}()
+	host, _, err := tester.CreateHost(t, "unit-test-sg-host", network, false)
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteHost(host.ID)
+	}()
+
+	sg, err := tester.Service.CreateSecurityGroup(resources.SecurityGroupRequest{Name: "unit-test-sg", NetworkID: network.ID})
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteSecurityGroup(sg.ID)
+	}()
+	assert.Equal(t, "unit-test-sg", sg.Name)
+	assert.Empty(t, sg.Rules)
+
+	_, err = tester.Service.AddRuleToSecurityGroup(sg.ID, resources.SecurityGroupRule{Protocol: "icmp", PortFrom: 22})
+	assert.NotNil(t, err)
+
+	sg, err = tester.Service.AddRuleToSecurityGroup(sg.ID, resources.SecurityGroupRule{
+		Direction: ruledirection.INGRESS,
+		Protocol:  "tcp",
+		PortFrom:  22,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(sg.Rules))
+	rule := sg.Rules[0]
+	assert.NotEmpty(t, rule.ID)
+	assert.Equal(t, 22, rule.PortTo)
+	assert.Equal(t, "0.0.0.0/0", rule.CIDR)
+	assert.Equal(t, ipversion.IPv4, rule.EtherType)
+
+	err = tester.Service.BindSecurityGroupToHost(sg.ID, host.ID)
+	require.Nil(t, err)
+	err = tester.Service.DeleteSecurityGroup(sg.ID)
+	assert.NotNil(t, err)
+	err = tester.Service.UnbindSecurityGroupFromHost(sg.ID, host.ID)
+	require.Nil(t, err)
+
+	sg, err = tester.Service.DeleteRuleFromSecurityGroup(sg.ID, rule.ID)
+	require.Nil(t, err)
+	assert.Empty(t, sg.Rules)
+
+	err = tester.Service.DeleteSecurityGroup(sg.ID)
+	require.Nil(t, err)
+	_, err = tester.Service.InspectSecurityGroup(sg.ID)
+	assert.NotNil(t, err)
+}
+
+// Images tests the creation of images from a host
+func (tester *ServiceTester) Images(t *testing.T) {
+	network, gw := tester.CreateNetwork(t, "unit-test-image-network", true, "1.1.10.0/24")
+	defer func() {
+		_ = tester.Service.DeleteGateway(gw.ID)
+		_ = tester.Service.DeleteNetwork(network.ID)
+	}()
+	host, _, err := tester.CreateHost(t, "unit-test-image-host", network, false)
+	require.Nil(t, err)
+	defer func() {
+		_ = tester.Service.DeleteHost(host.ID)
+	}()
+
+	_, err = tester.Service.CreateImage("unknown-host", "unit-test-image")
+	assert.NotNil(t, err)
+
+	image, err := tester.Service.CreateImage(host.ID, "unit-test-image")
+	require.Nil(t, err)
+	assert.NotEmpty(t, image.ID)
+	assert.Equal(t, "unit-test-image", image.Name)
+
+	_, err = tester.Service.CreateImage(host.ID, "unit-test-image")
+	assert.NotNil(t, err)
+
+	got, err := tester.Service.GetImage(image.ID)
+	require.Nil(t, err)
+	assert.Equal(t, image.Name, got.Name)
+
+	err = tester.Service.DeleteImage(image.ID)
+	require.Nil(t, err)
+	_, err = tester.Service.GetImage(image.ID)
+	assert.NotNil(t, err)
+	err = tester.Service.DeleteImage(image.ID)
+	assert.NotNil(t, err)
+}
+
 //Containers test
 func (tester *ServiceTester) Containers(t *testing.T) {
 	_, err := tester.Service.CreateBucket("testC")
@@ -588,7 +588,7 @@ func (f *Feature) setImplicitParameters(t Target, v Variables) error {
 		}
 
 		// FIXME: host may be on a network with 2 gateways + missing variables like DefaultRouteIP, ...
-		gw := gatewayFromHost(host)
+		gw := gatewayFromHost(f.task, host)
 		if gw != nil {
 			v["GatewayIP"] = gw.PrivateIp // legacy
 			v["PrimaryGatewayIP"] = gw.PrivateIp
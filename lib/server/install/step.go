@@ -27,6 +27,7 @@ import (
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
 	"github.com/CS-SI/SafeScale/lib/server/install/enums/action"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
@@ -390,6 +391,17 @@ func (is *step) taskRunOnHost(t concurrency.Task, params concurrency.TaskParamet
 	host := p["host"].(*pb.Host)
 	variables := p["variables"].(Variables)
 
+	// Reports the result of the step to the followers of the progress of the operation
+	defer func() {
+		if r, ok := result.(stepResult); ok {
+			message := "success"
+			if r.err != nil {
+				message = r.err.Error()
+			}
+			srvutils.ReportProgress(t.GetContext(), srvutils.ProgressStepResult, is.Worker.feature.DisplayName()+"/"+is.Name, host.Name, message)
+		}
+	}()
+
 	// FIXME Time and again
 	variables["TemplateOperationDelay"] = uint(math.Ceil(2 * temporal.GetDefaultDelay().Seconds()))
 	variables["TemplateOperationTimeout"] = strings.Replace((temporal.GetHostTimeout() / 2).Truncate(time.Minute).String(), "0s", "", -1)
@@ -404,7 +416,7 @@ func (is *step) taskRunOnHost(t concurrency.Task, params concurrency.TaskParamet
 
 	// If options file is defined, upload it to the remote host
 	if is.OptionsFileContent != "" {
-		err := UploadStringToRemoteFile(is.OptionsFileContent, host, utils.TempFolder+"/options.json", "cladm", "safescale", "ug+rw-x,o-rwx")
+		err := UploadStringToRemoteFile(t, is.OptionsFileContent, host, utils.TempFolder+"/options.json", "cladm", "safescale", "ug+rw-x,o-rwx")
 		if err != nil {
 			return stepResult{err: err}, nil
 		}
@@ -412,7 +424,7 @@ func (is *step) taskRunOnHost(t concurrency.Task, params concurrency.TaskParamet
 
 	// Uploads then executes command
 	filename := fmt.Sprintf("%s/feature.%s.%s_%s.sh", utils.TempFolder, is.Worker.feature.DisplayName(), strings.ToLower(is.Action.String()), is.Name)
-	err = UploadStringToRemoteFile(command, host, filename, "", "", "")
+	err = UploadStringToRemoteFile(t, command, host, filename, "", "", "")
 	if err != nil {
 		return stepResult{err: err}, nil
 	}
@@ -421,7 +433,7 @@ func (is *step) taskRunOnHost(t concurrency.Task, params concurrency.TaskParamet
 	command = fmt.Sprintf("sudo bash %s; rc=$?; exit $rc", filename)
 
 	// Executes the script on the remote host
-	retcode, _, _, err := client.New().SSH.Run(host.Name, command, outputs.COLLECT, temporal.GetConnectionTimeout(), is.WallTime)
+	retcode, _, _, err := client.FromTask(t).SSH.Run(host.Name, command, outputs.COLLECT, temporal.GetConnectionTimeout(), is.WallTime)
 	if err != nil {
 		return stepResult{err: err}, nil
 	}
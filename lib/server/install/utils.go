@@ -158,7 +158,7 @@ var featureScriptTemplate atomic.Value
 // }
 
 // UploadFile uploads a file to remote host
-func UploadFile(localpath string, host *pb.Host, remotepath, owner, group, rights string) (err error) {
+func UploadFile(task concurrency.Task, localpath string, host *pb.Host, remotepath, owner, group, rights string) (err error) {
 	if localpath == "" {
 		return scerr.InvalidParameterError("localpath", "cannot be empty string")
 	}
@@ -175,7 +175,7 @@ func UploadFile(localpath string, host *pb.Host, remotepath, owner, group, right
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
-	sshClt := client.New().SSH
+	sshClt := client.FromTask(task).SSH
 	networkError := false
 	retryErr := retry.WhileUnsuccessful(
 		func() error {
@@ -262,7 +262,7 @@ func UploadFile(localpath string, host *pb.Host, remotepath, owner, group, right
 }
 
 // UploadStringToRemoteFile creates a file 'filename' on remote 'host' with the content 'content'
-func UploadStringToRemoteFile(content string, host *pb.Host, filename string, owner, group, rights string) error {
+func UploadStringToRemoteFile(task concurrency.Task, content string, host *pb.Host, filename string, owner, group, rights string) error {
 	if content == "" {
 		return scerr.InvalidParameterError("content", "cannot be empty string")
 	}
@@ -289,7 +289,7 @@ func UploadStringToRemoteFile(content string, host *pb.Host, filename string, ow
 		return fmt.Errorf("failed to create temporary file: %s", err.Error())
 	}
 
-	err = UploadFile(f.Name(), host, filename, owner, group, rights)
+	err = UploadFile(task, f.Name(), host, filename, owner, group, rights)
 	_ = os.Remove(f.Name())
 	return err
 }
@@ -421,13 +421,13 @@ func checkParameters(f *Feature, v Variables) error {
 	return nil
 }
 
-func gatewayFromHost(host *pb.Host) *pb.Host {
+func gatewayFromHost(task concurrency.Task, host *pb.Host) *pb.Host {
 	gwID := host.GetGatewayId()
 	// If host has no gateway, host is gateway
 	if gwID == "" {
 		return host
 	}
-	gw, err := client.New().Host.Inspect(gwID, temporal.GetExecutionTimeout())
+	gw, err := client.FromTask(task).Host.Inspect(gwID, temporal.GetExecutionTimeout())
 	if err != nil {
 		return nil
 	}
@@ -151,7 +151,7 @@ func (w *worker) identifyAvailableMaster() (*pb.Host, error) {
 		if err != nil {
 			return nil, err
 		}
-		w.availableMaster, err = client.New().Host.Inspect(hostID, temporal.GetExecutionTimeout())
+		w.availableMaster, err = client.FromTask(w.feature.task).Host.Inspect(hostID, temporal.GetExecutionTimeout())
 		if err != nil {
 			return nil, err
 		}
@@ -169,7 +169,7 @@ func (w *worker) identifyAvailableNode() (*pb.Host, error) {
 		if err != nil {
 			return nil, err
 		}
-		host, err := client.New().Host.Inspect(hostID, temporal.GetExecutionTimeout())
+		host, err := client.FromTask(w.feature.task).Host.Inspect(hostID, temporal.GetExecutionTimeout())
 		if err != nil {
 			return nil, err
 		}
@@ -249,7 +249,7 @@ func (w *worker) identifyAllRunningMasters() ([]*pb.Host, error) {
 	}
 	if w.allMasters == nil || len(w.allMasters) == 0 {
 		w.allMasters = []*pb.Host{}
-		safescale := client.New().Host
+		safescale := client.FromTask(w.feature.task).Host
 		for _, i := range w.cluster.ListMasterIDs(w.feature.task) {
 			host, err := safescale.Inspect(i, temporal.GetExecutionTimeout())
 			if err != nil {
@@ -297,7 +297,7 @@ func (w *worker) identifyAllRunningNodes() ([]*pb.Host, error) {
 	}
 
 	if w.allNodes == nil {
-		hostClt := client.New().Host
+		hostClt := client.FromTask(w.feature.task).Host
 		var allHosts []*pb.Host
 		for _, i := range w.cluster.ListNodeIDs(w.feature.task) {
 			host, err := hostClt.Inspect(i, temporal.GetExecutionTimeout())
@@ -322,12 +322,12 @@ func (w *worker) identifyAllRunningNodes() ([]*pb.Host, error) {
 // For now, only one gateway is allowed, but in the future we may have 2 for High Availability
 func (w *worker) identifyAvailableGateway() (*pb.Host, error) {
 	if w.cluster == nil {
-		return gatewayFromHost(w.host), nil
+		return gatewayFromHost(w.feature.task, w.host), nil
 	}
 	if w.availableGateway == nil {
 		netCfg, err := w.cluster.GetNetworkConfig(w.feature.task)
 		if err == nil {
-			w.availableGateway, err = client.New().Host.Inspect(netCfg.GatewayID, temporal.GetExecutionTimeout())
+			w.availableGateway, err = client.FromTask(w.feature.task).Host.Inspect(netCfg.GatewayID, temporal.GetExecutionTimeout())
 		}
 		if err != nil {
 			return nil, err
@@ -342,7 +342,7 @@ func (w *worker) identifyConcernedGateways() ([]*pb.Host, error) {
 	var hosts []*pb.Host
 
 	if w.host != nil {
-		host := gatewayFromHost(w.host)
+		host := gatewayFromHost(w.feature.task, w.host)
 		hosts = []*pb.Host{host}
 	} else if w.cluster != nil {
 		var err error
@@ -376,7 +376,7 @@ func (w *worker) identifyAllGateways() ([]*pb.Host, error) {
 	if err != nil {
 		return nil, err
 	}
-	hostClt := client.New().Host
+	hostClt := client.FromTask(w.feature.task).Host
 	gw, err := hostClt.Inspect(netCfg.GatewayID, temporal.GetExecutionTimeout())
 	if err != nil {
 		return nil, err
@@ -384,7 +384,7 @@ func (w *worker) identifyAllGateways() ([]*pb.Host, error) {
 	results = append(results, gw)
 
 	if netCfg.SecondaryGatewayID != "" {
-		gw, err = client.New().Host.Inspect(netCfg.SecondaryGatewayID, temporal.GetExecutionTimeout())
+		gw, err = client.FromTask(w.feature.task).Host.Inspect(netCfg.SecondaryGatewayID, temporal.GetExecutionTimeout())
 		if err != nil {
 			return nil, err
 		}
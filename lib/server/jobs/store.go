@@ -0,0 +1,224 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jobs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // Import gorm sqlite driver
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+const (
+	// DefaultRetention is the default duration finished jobs are kept
+	DefaultRetention = 30 * 24 * time.Hour
+	// DefaultMaxJobs is the default number of finished jobs kept
+	DefaultMaxJobs = 1000
+)
+
+// job is the row of a job in the database
+type job struct {
+	UUID      string `gorm:"primary_key"`
+	Command   string
+	Owner     string
+	Tenant    string `gorm:"index"`
+	Status    string
+	Error     string    `gorm:"type:text"`
+	Result    string    `gorm:"type:text"`
+	StartTime time.Time `gorm:"index"`
+	EndTime   *time.Time
+}
+
+// TableName tells gorm the name of the table of the jobs
+func (job) TableName() string {
+	return "jobs"
+}
+
+// Store keeps the jobs of safescaled in a SQLite database; finished jobs are kept during a retention
+// duration and up to a maximum count
+type Store struct {
+	db        *gorm.DB
+	retention time.Duration
+	max       int
+	mutex     sync.Mutex
+}
+
+// NewStore opens (creating it if needed) the SQLite database at path. Finished jobs older than retention
+// are removed, as well as the oldest finished jobs beyond max; retention and max are not enforced if not positive.
+// The jobs found running are marked as interrupted, safescaled having stopped before their end.
+func NewStore(path string, retention time.Duration, max int) (*Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("invalid empty jobs database path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create folder of jobs database '%s': %v", path, err)
+	}
+	db, err := gorm.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open jobs database '%s': %v", path, err)
+	}
+	if err := db.AutoMigrate(&job{}).Error; err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize jobs database '%s': %v", path, err)
+	}
+
+	s := &Store{db: db, retention: retention, max: max}
+	now := time.Now()
+	err = db.Model(&job{}).Where("status = ?", srvutils.JobRunning).Updates(map[string]interface{}{
+		"status":   srvutils.JobInterrupted,
+		"error":    "safescaled stopped while the job was running",
+		"end_time": now,
+	}).Error
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to update interrupted jobs in '%s': %v", path, err)
+	}
+	if err := s.purge(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save creates or replaces the record of a job; saving a finished job removes the jobs out of retention
+func (s *Store) Save(record srvutils.JobRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	row := fromRecord(record)
+	count := 0
+	if err := s.db.Model(&job{}).Where("uuid = ?", row.UUID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to save job '%s': %v", record.UUID, err)
+	}
+	var err error
+	if count == 0 {
+		err = s.db.Create(&row).Error
+	} else {
+		err = s.db.Save(&row).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save job '%s': %v", record.UUID, err)
+	}
+	if row.EndTime != nil {
+		return s.purge()
+	}
+	return nil
+}
+
+// Get returns the record of a job, or scerr.ErrNotFound
+func (s *Store) Get(uuid string) (*srvutils.JobRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var row job
+	err := s.db.Where("uuid = ?", uuid).Take(&row).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, scerr.NotFoundError(fmt.Sprintf("no job with uuid '%s'", uuid))
+		}
+		return nil, fmt.Errorf("failed to read job '%s': %v", uuid, err)
+	}
+	record := row.toRecord()
+	return &record, nil
+}
+
+// List returns the records of the jobs kept, the most recent first
+func (s *Store) List() ([]srvutils.JobRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var rows []job
+	if err := s.db.Order("start_time desc").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+	records := make([]srvutils.JobRecord, 0, len(rows))
+	for _, r := range rows {
+		records = append(records, r.toRecord())
+	}
+	return records, nil
+}
+
+// purge removes the finished jobs out of retention; must be called with s.mutex locked (or before s is shared)
+func (s *Store) purge() error {
+	if s.retention > 0 {
+		err := s.db.Where("end_time IS NOT NULL AND end_time < ?", time.Now().Add(-s.retention)).Delete(&job{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to remove jobs older than %s: %v", s.retention, err)
+		}
+	}
+	if s.max > 0 {
+		var uuids []string
+		err := s.db.Model(&job{}).Where("end_time IS NOT NULL").Order("start_time desc").Pluck("uuid", &uuids).Error
+		if err != nil {
+			return fmt.Errorf("failed to count jobs: %v", err)
+		}
+		if len(uuids) > s.max {
+			err = s.db.Where("uuid IN (?)", uuids[s.max:]).Delete(&job{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to remove jobs beyond %d: %v", s.max, err)
+			}
+		}
+	}
+	return nil
+}
+
+func fromRecord(record srvutils.JobRecord) job {
+	row := job{
+		UUID:      record.UUID,
+		Command:   record.Command,
+		Owner:     record.Owner,
+		Tenant:    record.Tenant,
+		Status:    record.Status,
+		Error:     record.Error,
+		Result:    record.Result,
+		StartTime: record.StartTime,
+	}
+	if !record.EndTime.IsZero() {
+		end := record.EndTime
+		row.EndTime = &end
+	}
+	return row
+}
+
+func (j job) toRecord() srvutils.JobRecord {
+	record := srvutils.JobRecord{
+		UUID:      j.UUID,
+		Command:   j.Command,
+		Owner:     j.Owner,
+		Tenant:    j.Tenant,
+		Status:    j.Status,
+		Error:     j.Error,
+		Result:    j.Result,
+		StartTime: j.StartTime,
+	}
+	if j.EndTime != nil {
+		record.EndTime = *j.EndTime
+	}
+	return record
+}
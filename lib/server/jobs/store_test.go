@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jobs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobs")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	path := filepath.Join(dir, "jobs.db")
+
+	store, err := NewStore(path, 0, 2)
+	require.Nil(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		err = store.Save(srvutils.JobRecord{
+			UUID:      fmt.Sprintf("job-%d", i),
+			Command:   "Create Host host" + fmt.Sprint(i),
+			Owner:     "alice@example.com",
+			Tenant:    "tenantY",
+			Status:    srvutils.JobRunning,
+			StartTime: start.Add(time.Duration(i) * time.Minute),
+		})
+		require.Nil(t, err)
+	}
+
+	record, err := store.Get("job-0")
+	require.Nil(t, err)
+	record.Status = srvutils.JobFailed
+	record.Error = "quota exceeded"
+	record.EndTime = time.Now()
+	require.Nil(t, store.Save(*record))
+
+	record, err = store.Get("job-0")
+	require.Nil(t, err)
+	assert.Equal(t, srvutils.JobFailed, record.Status)
+	assert.Equal(t, "quota exceeded", record.Error)
+	assert.False(t, record.EndTime.IsZero())
+
+	_, err = store.Get("unknown")
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok)
+
+	records, err := store.List()
+	require.Nil(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "job-2", records[0].UUID)
+
+	// Reopening the store marks the running jobs as interrupted
+	require.Nil(t, store.Close())
+	store, err = NewStore(path, 0, 2)
+	require.Nil(t, err)
+	defer func() {
+		_ = store.Close()
+	}()
+	record, err = store.Get("job-2")
+	require.Nil(t, err)
+	assert.Equal(t, srvutils.JobInterrupted, record.Status)
+
+	// The oldest finished job is removed beyond the maximum count
+	records, err = store.List()
+	require.Nil(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "job-2", records[0].UUID)
+	assert.Equal(t, "job-1", records[1].UUID)
+}
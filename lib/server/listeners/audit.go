@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// AuditHandler exists to ease integration tests
+var AuditHandler = handlers.NewAuditHandler
+
+// AuditListener audit service server grpc
+type AuditListener struct {
+	// Recorder is the audit recorder of safescaled; nil if audit is disabled
+	Recorder *audit.Recorder
+}
+
+// List returns the audit records of the tenant
+func (s *AuditListener) List(ctx context.Context, in *pb.AuditListRequest) (rl *pb.AuditRecordList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+
+	var since time.Time
+	if in.GetSince() != "" {
+		since, err = time.Parse(time.RFC3339, in.GetSince())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value '%s' for since: %s", in.GetSince(), err.Error())
+		}
+	}
+	resource := in.GetResource()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", in.GetSince(), resource), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Audit List"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't list audit records: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list audit records: no tenant set")
+	}
+
+	handler := AuditHandler(s.Recorder)
+	records, err := handler.List(ctx, tenant.name, since, resource)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotAvailable); ok {
+			return nil, status.Errorf(codes.Unavailable, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	rl = &pb.AuditRecordList{}
+	for _, r := range records {
+		rl.Records = append(rl.Records, toPBAuditRecord(r))
+	}
+	return rl, nil
+}
+
+// toPBAuditRecord converts an audit.Record to a pb.AuditRecord
+func toPBAuditRecord(in audit.Record) *pb.AuditRecord {
+	out := &pb.AuditRecord{
+		Time:     in.Time.Format(time.RFC3339Nano),
+		Caller:   in.Caller,
+		Internal: in.Internal,
+		Tenant:   in.Tenant,
+		Rpc:      in.RPC,
+		Resource: in.Resource,
+		Outcome:  in.Outcome,
+		Error:    in.Error,
+		Duration: in.Duration,
+	}
+	if len(in.Parameters) > 0 {
+		if params, err := json.Marshal(in.Parameters); err == nil {
+			out.Parameters = string(params)
+		}
+	}
+	return out
+}
@@ -57,7 +57,7 @@ func (s *BucketListener) List(ctx context.Context, in *googleprotobuf.Empty) (bl
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Can't list buckets: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list buckets: no tenant set")
@@ -85,7 +85,7 @@ func (s *BucketListener) Create(ctx context.Context, in *pb.Bucket) (empty *goog
 		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Can't create bucket: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create bucket: no tenant set")
@@ -114,7 +114,7 @@ func (s *BucketListener) Destroy(ctx context.Context, in *pb.Bucket) (empty *goo
 		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Cannot destroy buckets: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete bucket: no tenant set")
@@ -143,7 +143,7 @@ func (s *BucketListener) Delete(ctx context.Context, in *pb.Bucket) (empty *goog
 		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Cannot delete buckets: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete bucket: no tenant set")
@@ -171,7 +171,7 @@ func (s *BucketListener) Inspect(ctx context.Context, in *pb.Bucket) (bmp *pb.Bu
 		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Cannot inspect bucket: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect bucket: no tenant set")
@@ -202,7 +202,7 @@ func (s *BucketListener) Mount(ctx context.Context, in *pb.BucketMountingPoint)
 		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Cannot mount buckets: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot mount bucket: no tenant set")
@@ -230,7 +230,7 @@ func (s *BucketListener) Unmount(ctx context.Context, in *pb.BucketMountingPoint
 		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Cannot unmount bucket: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot unmount bucket: no tenant set")
@@ -0,0 +1,880 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/api"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/control"
+	clusterpropsv1 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v1"
+	clusterpropsv2 "github.com/CS-SI/SafeScale/lib/server/cluster/control/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/complexity"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/flavor"
+	"github.com/CS-SI/SafeScale/lib/server/cluster/enums/property"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/install"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// ClusterHandler ...
+var ClusterHandler = handlers.NewClusterHandler
+
+// ClusterListener cluster service server grpc
+type ClusterListener struct{}
+
+// detachedContext returns a context carrying the values of ctx, but not its cancellation (see srvutils.DetachContext).
+// Long operations on clusters must survive the disconnection of the client; they can still be
+// cancelled with JobService.Stop through the cancel function registered in the job manager.
+func detachedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithCancel(srvutils.DetachContext(ctx))
+}
+
+// Create creates a new cluster
+func (s *ClusterListener) Create(ctx context.Context, in *pb.ClusterDefinition) (c *pb.Cluster, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("name", "cannot be empty string").Error())
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Create Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't create cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot create cluster: no tenant set")
+	}
+
+	req := fromPBClusterDefinition(in)
+	req.Tenant = tenant.name
+
+	handler := ClusterHandler(tenant.Service)
+	instance, err := handler.Create(ctx, *req)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	log.Infof("Cluster '%s' created", name)
+	return toPBCluster(instance)
+}
+
+// CreateWithProgress creates a new cluster, streaming the progress of the creation before the cluster created
+func (s *ClusterListener) CreateWithProgress(in *pb.ClusterDefinition, stream pb.ClusterService_CreateWithProgressServer) error {
+	if s == nil {
+		return status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+
+	reporter, sendErr := srvutils.ProgressStream(func(event *pb.ProgressEvent) error {
+		return stream.Send(&pb.ClusterCreationProgress{Event: event})
+	})
+	cluster, err := s.Create(srvutils.ContextWithProgress(stream.Context(), reporter), in)
+	if err != nil {
+		return err
+	}
+	if err := sendErr(); err != nil {
+		log.Warn(err)
+	}
+	return stream.Send(&pb.ClusterCreationProgress{Cluster: cluster})
+}
+
+// Inspect returns information about a cluster
+func (s *ClusterListener) Inspect(ctx context.Context, in *pb.Reference) (c *pb.Cluster, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect cluster: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Inspect Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't inspect cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	instance, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return toPBCluster(instance)
+}
+
+// List lists the clusters managed by SafeScale
+func (s *ClusterListener) List(ctx context.Context, in *pb.ClusterListRequest) (cl *pb.ClusterList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	selector := in.GetSelector()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", selector), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "List Clusters"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't list clusters: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list clusters: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	list, err := handler.List(ctx, selector)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	var pbList []*pb.Cluster
+	for _, instance := range list {
+		pbCluster, err := toPBCluster(instance)
+		if err != nil {
+			return nil, err
+		}
+		pbList = append(pbList, pbCluster)
+	}
+	return &pb.ClusterList{Clusters: pbList}, nil
+}
+
+// Delete deletes a cluster and all its resources
+func (s *ClusterListener) Delete(ctx context.Context, in *pb.Reference) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete cluster: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Delete Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't delete cluster: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	err = handler.Delete(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	log.Infof("Cluster '%s' successfully deleted", ref)
+	return empty, nil
+}
+
+// Start starts all the hosts of a cluster
+func (s *ClusterListener) Start(ctx context.Context, in *pb.Reference) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot start cluster: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Start Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't start cluster: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot start cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	err = handler.Start(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	log.Infof("Cluster '%s' successfully started", ref)
+	return empty, nil
+}
+
+// Stop stops all the hosts of a cluster
+func (s *ClusterListener) Stop(ctx context.Context, in *pb.Reference) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot stop cluster: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Stop Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't stop cluster: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot stop cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	err = handler.Stop(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	log.Infof("Cluster '%s' successfully stopped", ref)
+	return empty, nil
+}
+
+// State returns the current state of a cluster
+func (s *ClusterListener) State(ctx context.Context, in *pb.Reference) (cs *pb.ClusterState, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get cluster state: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "State of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't get cluster state: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get cluster state: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	state, err := handler.State(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return &pb.ClusterState{Name: ref, State: int32(state), StateLabel: state.String()}, nil
+}
+
+// Expand adds nodes to a cluster
+func (s *ClusterListener) Expand(ctx context.Context, in *pb.ClusterResizeRequest) (nl *pb.ClusterNodeList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	name := in.GetName()
+	count := int(in.GetCount())
+	if count == 0 {
+		count = 1
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Expand Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't expand cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot expand cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	nodes, err := handler.Expand(ctx, name, count, in.GetNodeDefinition())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	log.Infof("Cluster '%s' expanded with %d node(s)", name, len(nodes))
+	return toPBClusterNodeList(nodes), nil
+}
+
+// Shrink removes the last added nodes from a cluster
+func (s *ClusterListener) Shrink(ctx context.Context, in *pb.ClusterResizeRequest) (nl *pb.ClusterNodeList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	name := in.GetName()
+	count := int(in.GetCount())
+	if count == 0 {
+		count = 1
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", name, count), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Shrink Cluster "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't shrink cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot shrink cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	nodes, err := handler.Shrink(ctx, name, count)
+	if err != nil {
+		return toPBClusterNodeList(nodes), status.Errorf(toGRPCCode(err), err.Error())
+	}
+	log.Infof("Cluster '%s' shrunk by %d node(s)", name, len(nodes))
+	return toPBClusterNodeList(nodes), nil
+}
+
+// AddFeature installs a feature on a cluster
+func (s *ClusterListener) AddFeature(ctx context.Context, in *pb.FeatureRequest) (fr *pb.FeatureResponse, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	target, feature := in.GetTarget(), in.GetName()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", target, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Add Feature "+feature+" on Cluster "+target); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't add feature: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add feature: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	settings := install.Settings{SkipProxy: in.GetSkipProxy()}
+	results, err := handler.AddFeature(ctx, target, feature, fromPBVariables(in.GetVariables()), settings)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return toPBFeatureResponse(results), nil
+}
+
+// CheckFeature checks if a feature is installed on a cluster
+func (s *ClusterListener) CheckFeature(ctx context.Context, in *pb.FeatureRequest) (fr *pb.FeatureResponse, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	target, feature := in.GetTarget(), in.GetName()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", target, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Check Feature "+feature+" on Cluster "+target); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't check feature: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot check feature: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	results, err := handler.CheckFeature(ctx, target, feature, fromPBVariables(in.GetVariables()), install.Settings{})
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return toPBFeatureResponse(results), nil
+}
+
+// DeleteFeature uninstalls a feature from a cluster
+func (s *ClusterListener) DeleteFeature(ctx context.Context, in *pb.FeatureRequest) (fr *pb.FeatureResponse, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	target, feature := in.GetTarget(), in.GetName()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", target, feature), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := detachedContext(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Delete Feature "+feature+" from Cluster "+target); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't delete feature: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete feature: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	// TODO: Reverse proxy rules are not yet purged when feature is removed, but current code
+	// will try to apply them... Quick fix: Setting SkipProxy to true prevent this
+	settings := install.Settings{SkipProxy: true}
+	results, err := handler.DeleteFeature(ctx, target, feature, fromPBVariables(in.GetVariables()), settings)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return toPBFeatureResponse(results), nil
+}
+
+// ListNodes lists the nodes of a cluster
+func (s *ClusterListener) ListNodes(ctx context.Context, in *pb.Reference) (nl *pb.ClusterNodeList, err error) {
+	return s.listHosts(ctx, in, false)
+}
+
+// ListMasters lists the masters of a cluster
+func (s *ClusterListener) ListMasters(ctx context.Context, in *pb.Reference) (nl *pb.ClusterNodeList, err error) {
+	return s.listHosts(ctx, in, true)
+}
+
+// listHosts lists the masters or the nodes of a cluster
+func (s *ClusterListener) listHosts(ctx context.Context, in *pb.Reference, masters bool) (nl *pb.ClusterNodeList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list cluster hosts: neither name nor id given as reference")
+	}
+	kind := "nodes"
+	if masters {
+		kind = "masters"
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %s)", ref, kind), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "List "+kind+" of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Infof("Can't list cluster %s: no tenant set", kind)
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list cluster %s: no tenant set", kind)
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	var list []*clusterpropsv1.Node
+	if masters {
+		list, err = handler.ListMasters(ctx, ref)
+	} else {
+		list, err = handler.ListNodes(ctx, ref)
+	}
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return toPBClusterNodeList(list), nil
+}
+
+// SetTags sets and removes tags of a cluster
+func (s *ClusterListener) SetTags(ctx context.Context, in *pb.TagsRequest) (t *pb.Tags, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetResource())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set tags of cluster: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Set tags of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't set tags of cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set tags of cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	tags, err := handler.SetTags(ctx, ref, in.GetSet(), in.GetUnset())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return &pb.Tags{Tags: tags}, nil
+}
+
+// GetAdminPassword returns the password of the administrator account of a cluster; Inspect and List don't return it,
+// so that it can be given only to the callers having the permission on this RPC
+func (s *ClusterListener) GetAdminPassword(ctx context.Context, in *pb.Reference) (ap *pb.ClusterAdminPassword, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get admin password of cluster: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Get admin password of Cluster "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't get admin password of cluster: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get admin password of cluster: no tenant set")
+	}
+
+	handler := ClusterHandler(tenant.Service)
+	instance, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	identity := instance.GetIdentity(concurrency.RootTask())
+	return &pb.ClusterAdminPassword{
+		Name:          identity.Name,
+		AdminLogin:    "cladm",
+		AdminPassword: identity.AdminPassword,
+	}, nil
+}
+
+// toGRPCCode returns the gRPC code corresponding to the kind of err
+func toGRPCCode(err error) codes.Code {
+	switch err.(type) {
+	case scerr.ErrNotFound:
+		return codes.NotFound
+	case scerr.ErrDuplicate:
+		return codes.AlreadyExists
+	case scerr.ErrConflict:
+		return codes.Aborted
+	case scerr.ErrInvalidRequest, scerr.ErrInvalidParameter:
+		return codes.InvalidArgument
+	case scerr.ErrNotAvailable:
+		return codes.Unavailable
+	case scerr.ErrTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// fromPBClusterDefinition converts a pb.ClusterDefinition to a control.Request
+func fromPBClusterDefinition(in *pb.ClusterDefinition) *control.Request {
+	req := control.Request{
+		Name:                    in.GetName(),
+		CIDR:                    in.GetCidr(),
+		Complexity:              complexity.Enum(in.GetComplexity()),
+		Flavor:                  flavor.Enum(in.GetFlavor()),
+		KeepOnFailure:           in.GetKeepOnFailure(),
+		GatewaysDef:             in.GetGateways(),
+		MastersDef:              in.GetMasters(),
+		NodesDef:                in.GetNodes(),
+		DisabledDefaultFeatures: map[string]struct{}{},
+		Tags:                    in.GetTags(),
+	}
+	if req.Complexity == 0 {
+		req.Complexity = complexity.Small
+	}
+	if req.Flavor == 0 {
+		req.Flavor = flavor.K8S
+	}
+	if req.CIDR == "" {
+		req.CIDR = "192.168.0.0/16"
+	}
+	for _, v := range in.GetDisabledFeatures() {
+		req.DisabledDefaultFeatures[strings.ToLower(v)] = struct{}{}
+	}
+	if req.Flavor == flavor.DCOS {
+		// DCOS forces to use RHEL/CentOS/CoreOS, and we've chosen to use CentOS, so ignore requested image
+		for _, def := range []*pb.HostDefinition{req.GatewaysDef, req.MastersDef, req.NodesDef} {
+			if def != nil {
+				def.ImageId = ""
+			}
+		}
+	}
+	return &req
+}
+
+// fromPBVariables converts feature variables received from gRPC to install.Variables
+func fromPBVariables(in map[string]string) install.Variables {
+	out := install.Variables{}
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+// toPBFeatureResponse converts install.Results to a pb.FeatureResponse
+func toPBFeatureResponse(results install.Results) *pb.FeatureResponse {
+	out := &pb.FeatureResponse{Success: results.Successful()}
+	if !out.Success {
+		out.Output = results.AllErrorMessages()
+	}
+	return out
+}
+
+// toPBClusterNode converts a cluster node to a pb.ClusterNode
+func toPBClusterNode(in *clusterpropsv1.Node) *pb.ClusterNode {
+	return &pb.ClusterNode{
+		Id:        in.ID,
+		Name:      in.Name,
+		PublicIp:  in.PublicIP,
+		PrivateIp: in.PrivateIP,
+	}
+}
+
+// toPBClusterNodeList converts a slice of cluster nodes to a pb.ClusterNodeList
+func toPBClusterNodeList(in []*clusterpropsv1.Node) *pb.ClusterNodeList {
+	out := &pb.ClusterNodeList{}
+	for _, n := range in {
+		out.Nodes = append(out.Nodes, toPBClusterNode(n))
+	}
+	return out
+}
+
+// toPBCluster converts an api.Cluster to a pb.Cluster; the password of the administrator is left empty
+// (see GetAdminPassword)
+func toPBCluster(instance api.Cluster) (*pb.Cluster, error) {
+	task := concurrency.RootTask()
+	identity := instance.GetIdentity(task)
+
+	out := &pb.Cluster{
+		Identity: &pb.ClusterIdentity{
+			Name:            identity.Name,
+			Complexity:      int32(identity.Complexity),
+			ComplexityLabel: identity.Complexity.String(),
+			Flavor:          int32(identity.Flavor),
+			FlavorLabel:     identity.Flavor.String(),
+			AdminLogin:      "cladm",
+		},
+		InstalledFeatures: map[string]string{},
+	}
+
+	netCfg, err := instance.GetNetworkConfig(task)
+	if err != nil {
+		return nil, err
+	}
+	out.Network = &pb.ClusterNetwork{
+		NetworkId:          netCfg.NetworkID,
+		Cidr:               netCfg.CIDR,
+		GatewayId:          netCfg.GatewayID,
+		GatewayIp:          netCfg.GatewayIP,
+		SecondaryGatewayId: netCfg.SecondaryGatewayID,
+		SecondaryGatewayIp: netCfg.SecondaryGatewayIP,
+		DefaultRouteIp:     netCfg.DefaultRouteIP,
+		PrimaryPublicIp:    netCfg.PrimaryPublicIP,
+		SecondaryPublicIp:  netCfg.SecondaryPublicIP,
+		EndpointIp:         netCfg.EndpointIP,
+	}
+
+	properties := instance.GetProperties(task)
+	err = properties.LockForRead(property.CompositeV1).ThenUse(func(clonable data.Clonable) error {
+		tenants := clonable.(*clusterpropsv1.Composite).Tenants
+		if len(tenants) > 0 {
+			out.Tenant = tenants[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !properties.Lookup(property.DefaultsV2) {
+		err = properties.LockForRead(property.DefaultsV1).ThenUse(func(clonable data.Clonable) error {
+			defaultsV1 := clonable.(*clusterpropsv1.Defaults)
+			out.Defaults = &pb.ClusterDefaults{
+				Image:         defaultsV1.Image,
+				GatewaySizing: srvutils.ToPBHostDefinition(&defaultsV1.GatewaySizing).Sizing,
+				MasterSizing:  srvutils.ToPBHostDefinition(&defaultsV1.MasterSizing).Sizing,
+				NodeSizing:    srvutils.ToPBHostDefinition(&defaultsV1.NodeSizing).Sizing,
+			}
+			return nil
+		})
+	} else {
+		err = properties.LockForRead(property.DefaultsV2).ThenUse(func(clonable data.Clonable) error {
+			defaultsV2 := clonable.(*clusterpropsv2.Defaults)
+			gwSizing := srvutils.ToPBHostSizing(defaultsV2.GatewaySizing)
+			masterSizing := srvutils.ToPBHostSizing(defaultsV2.MasterSizing)
+			nodeSizing := srvutils.ToPBHostSizing(defaultsV2.NodeSizing)
+			out.Defaults = &pb.ClusterDefaults{
+				Image:         defaultsV2.Image,
+				GatewaySizing: &gwSizing,
+				MasterSizing:  &masterSizing,
+				NodeSizing:    &nodeSizing,
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.LockForRead(property.NodesV1).ThenUse(func(clonable data.Clonable) error {
+		nodesV1 := clonable.(*clusterpropsv1.Nodes)
+		for _, n := range nodesV1.Masters {
+			out.Masters = append(out.Masters, toPBClusterNode(n))
+		}
+		for _, n := range nodesV1.PrivateNodes {
+			out.Nodes = append(out.Nodes, toPBClusterNode(n))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.LockForRead(property.FeaturesV1).ThenUse(func(clonable data.Clonable) error {
+		featuresV1 := clonable.(*clusterpropsv1.Features)
+		for k, v := range featuresV1.Installed {
+			out.InstalledFeatures[k] = v
+		}
+		for k := range featuresV1.Disabled {
+			out.DisabledFeatures = append(out.DisabledFeatures, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = properties.LockForRead(property.StateV1).ThenUse(func(clonable data.Clonable) error {
+		state := clonable.(*clusterpropsv1.State).State
+		out.State = int32(state)
+		out.StateLabel = state.String()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if properties.Lookup(property.TagsV1) {
+		err = properties.LockForRead(property.TagsV1).ThenUse(func(clonable data.Clonable) error {
+			tags := clonable.(*clusterpropsv1.Tags).Tags
+			if len(tags) > 0 {
+				out.Tags = make(map[string]string, len(tags))
+				for k, v := range tags {
+					out.Tags[k] = v
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
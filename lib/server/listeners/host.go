@@ -84,7 +84,7 @@ func (s *HostListener) Start(ctx context.Context, in *pb.Reference) (empty *goog
 		return empty, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't start host: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot start host: no tenant set")
@@ -120,7 +120,7 @@ func (s *HostListener) Stop(ctx context.Context, in *pb.Reference) (empty *googl
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't stop host: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot stop host: no tenant set")
@@ -157,7 +157,7 @@ func (s *HostListener) Reboot(ctx context.Context, in *pb.Reference) (empty *goo
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't reboot host: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot reboot host: no tenant set")
@@ -179,8 +179,9 @@ func (s *HostListener) List(ctx context.Context, in *pb.HostListRequest) (hl *pb
 		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
 	}
 	all := in.GetAll()
+	selector := in.GetSelector()
 
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v, '%s')", all, selector), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)
 
@@ -190,16 +191,16 @@ func (s *HostListener) List(ctx context.Context, in *pb.HostListRequest) (hl *pb
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't list host: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list hosts: no tenant set")
 	}
 
 	handler := HostHandler(tenant.Service)
-	hosts, err := handler.List(ctx, all)
+	hosts, err := handler.List(ctx, all, selector)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
 	}
 
 	// Map resources.Host to pb.Host
@@ -230,7 +231,7 @@ func (s *HostListener) Create(ctx context.Context, in *pb.HostDefinition) (h *pb
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't create host: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create host: no tenant set")
@@ -260,15 +261,35 @@ func (s *HostListener) Create(ctx context.Context, in *pb.HostDefinition) (h *pb
 		in.GetPublic(),
 		sizing,
 		in.Force,
+		in.GetTags(),
 	)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
 	}
 	log.Infof("Host '%s' created", name)
 	return srvutils.ToPBHost(host), nil
 
 }
 
+// CreateWithProgress creates a new host, streaming the progress of the creation before the host created
+func (s *HostListener) CreateWithProgress(in *pb.HostDefinition, stream pb.HostService_CreateWithProgressServer) error {
+	if s == nil {
+		return status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+
+	reporter, sendErr := srvutils.ProgressStream(func(event *pb.ProgressEvent) error {
+		return stream.Send(&pb.HostCreationProgress{Event: event})
+	})
+	host, err := s.Create(srvutils.ContextWithProgress(stream.Context(), reporter), in)
+	if err != nil {
+		return err
+	}
+	if err := sendErr(); err != nil {
+		log.Warn(err)
+	}
+	return stream.Send(&pb.HostCreationProgress{Host: host})
+}
+
 // Resize an host
 func (s *HostListener) Resize(ctx context.Context, in *pb.HostDefinition) (h *pb.Host, err error) {
 	if s == nil {
@@ -288,7 +309,7 @@ func (s *HostListener) Resize(ctx context.Context, in *pb.HostDefinition) (h *pb
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't resize host: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot resize host: no tenant set")
@@ -332,7 +353,7 @@ func (s *HostListener) Status(ctx context.Context, in *pb.Reference) (ht *pb.Hos
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't get host status: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot get host status: no tenant set")
@@ -368,7 +389,7 @@ func (s *HostListener) Inspect(ctx context.Context, in *pb.Reference) (h *pb.Hos
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't inspect host: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect host: no tenant set")
@@ -405,7 +426,7 @@ func (s *HostListener) Delete(ctx context.Context, in *pb.Reference) (empty *goo
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't delete host: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete host: no tenant set")
@@ -442,16 +463,90 @@ func (s *HostListener) SSH(ctx context.Context, in *pb.Reference) (sc *pb.SshCon
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("cannot delete host: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot ssh host: no tenant set")
 	}
 
-	handler := HostHandler(currentTenant.Service)
+	handler := HostHandler(tenant.Service)
 	sshConfig, err := handler.SSH(ctx, ref)
 	if err != nil {
 		return nil, err
 	}
 	return srvutils.ToPBSshConfig(sshConfig), nil
 }
+
+// SetTags sets and removes tags of an host
+func (s *HostListener) SetTags(ctx context.Context, in *pb.TagsRequest) (t *pb.Tags, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetResource())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set tags of host: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Set tags of Host "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't set tags of host: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set tags of host: no tenant set")
+	}
+
+	handler := HostHandler(tenant.Service)
+	tags, err := handler.SetTags(ctx, ref, in.GetSet(), in.GetUnset())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return &pb.Tags{Tags: tags}, nil
+}
+
+// Adopt creates the metadata of a host of the provider created outside of SafeScale, to manage it
+func (s *HostListener) Adopt(ctx context.Context, in *pb.HostAdoptRequest) (ph *pb.Host, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	id := in.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot adopt host: id of the host at the provider is missing")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Adopt host "+id); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't adopt host: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot adopt host: no tenant set")
+	}
+
+	handler := HostHandler(tenant.Service)
+	host, err := handler.Adopt(ctx, id, in.GetUser(), int(in.GetPort()), in.GetPrivateKey())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	log.Infof("Host '%s' (%s) adopted", host.Name, host.ID)
+	return srvutils.ToPBHost(host), nil
+}
@@ -18,7 +18,9 @@ package listeners
 
 import (
 	"context"
+	"fmt"
 
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -53,13 +55,13 @@ func (s *ImageListener) List(ctx context.Context, in *pb.ImageListRequest) (il *
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		logrus.Info("Can't list images: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list images: no tenant set")
 	}
 
-	handler := ImageHandler(currentTenant.Service)
+	handler := ImageHandler(tenant.Service)
 	images, err := handler.List(ctx, in.GetAll())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
@@ -73,3 +75,84 @@ func (s *ImageListener) List(ctx context.Context, in *pb.ImageListRequest) (il *
 	rv := &pb.ImageList{Images: pbImages}
 	return rv, nil
 }
+
+// Create creates an image from a host
+func (s *ImageListener) Create(ctx context.Context, in *pb.ImageDefinition) (_ *pb.Image, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	hostRef := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot create image: neither name nor id given as reference for host")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot create image: name cannot be empty")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", hostRef, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Image create "+name+" from host "+hostRef); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot create image: no tenant set")
+	}
+
+	handler := ImageHandler(tenant.Service)
+	image, err := handler.Create(ctx, hostRef, name)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	logrus.Infof("Image '%s' created from host '%s'", name, hostRef)
+	return srvutils.ToPBImage(image), nil
+}
+
+// Delete deletes an image created by SafeScale
+func (s *ImageListener) Delete(ctx context.Context, in *pb.Reference) (_ *googleprotobuf.Empty, err error) {
+	empty := &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot delete image: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Image delete "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete image: no tenant set")
+	}
+
+	handler := ImageHandler(tenant.Service)
+	err = handler.Delete(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), fmt.Sprintf("cannot delete image '%s': %s", ref, err.Error()))
+	}
+
+	logrus.Infof("Image '%s' deleted", ref)
+	return empty, nil
+}
@@ -19,6 +19,7 @@ package listeners
 import (
 	"context"
 	"fmt"
+	"time"
 
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
@@ -63,7 +64,7 @@ func (s *JobManagerListener) Stop(ctx context.Context, in *pb.JobDefinition) (em
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't stop process: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "Can't stop process: no tenant set")
@@ -75,13 +76,14 @@ func (s *JobManagerListener) Stop(ctx context.Context, in *pb.JobDefinition) (em
 	return empty, nil
 }
 
-// List running process
-func (s *JobManagerListener) List(ctx context.Context, in *googleprotobuf.Empty) (jl *pb.JobList, err error) {
+// List running jobs and, if asked, finished jobs kept in history
+func (s *JobManagerListener) List(ctx context.Context, in *pb.JobListRequest) (jl *pb.JobList, err error) {
 	if s == nil {
 		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
 	}
+	all := in.GetAll()
 
-	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
@@ -91,21 +93,129 @@ func (s *JobManagerListener) List(ctx context.Context, in *googleprotobuf.Empty)
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't list process : no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "Can't list process: no tenant set")
 	}
 
 	handler := JobManagerHandler(tenant.Service)
-	processMap, err := handler.List(ctx)
+	records, err := handler.List(ctx, all)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list Process %s", err.Error())
+		return nil, status.Errorf(codes.Internal, "failed to list jobs: %v", err)
 	}
 	var pbProcessList []*pb.JobDefinition
-	for uuid, info := range processMap {
-		pbProcessList = append(pbProcessList, &pb.JobDefinition{Uuid: uuid, Info: info})
+	for _, r := range records {
+		pbProcessList = append(pbProcessList, toPBJob(r))
 	}
 
 	return &pb.JobList{List: pbProcessList}, nil
 }
+
+// Inspect returns the record of a job, running or finished
+func (s *JobManagerListener) Inspect(ctx context.Context, in *pb.JobDefinition) (jd *pb.JobDefinition, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	uuid := in.GetUuid()
+	if uuid == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot inspect job: job id not set")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", uuid), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't inspect job: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect job: no tenant set")
+	}
+
+	handler := JobManagerHandler(tenant.Service)
+	record, err := handler.Inspect(ctx, uuid)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return toPBJob(*record), nil
+}
+
+// Watch sends the record of a job each time its status changes, until its end
+func (s *JobManagerListener) Watch(in *pb.JobDefinition, stream pb.JobService_WatchServer) (err error) {
+	if s == nil {
+		return status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	uuid := in.GetUuid()
+	if uuid == "" {
+		return status.Errorf(codes.InvalidArgument, "cannot watch job: job id not set")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", uuid), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx := stream.Context()
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't watch job: no tenant set")
+		return status.Errorf(codes.FailedPrecondition, "cannot watch job: no tenant set")
+	}
+
+	handler := JobManagerHandler(tenant.Service)
+	lastStatus := ""
+	for {
+		record, err := handler.Inspect(ctx, uuid)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); ok {
+				return status.Errorf(codes.NotFound, err.Error())
+			}
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		if record.Status != lastStatus {
+			if err := stream.Send(toPBJob(*record)); err != nil {
+				return err
+			}
+			lastStatus = record.Status
+		}
+		if record.Status != srvutils.JobRunning {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.Errorf(codes.Canceled, "watch of job '%s' cancelled", uuid)
+		case <-time.After(jobWatchPeriod):
+		}
+	}
+}
+
+// jobWatchPeriod is the delay between two checks of the status of a watched job
+const jobWatchPeriod = time.Second
+
+// toPBJob converts a job record to its protobuf message
+func toPBJob(r srvutils.JobRecord) *pb.JobDefinition {
+	pbJob := &pb.JobDefinition{
+		Uuid:      r.UUID,
+		Info:      fmt.Sprintf("Task : %s\nCreation time : %s", r.Command, r.StartTime.String()),
+		Command:   r.Command,
+		Owner:     r.Owner,
+		Tenant:    r.Tenant,
+		Status:    r.Status,
+		Error:     r.Error,
+		Result:    r.Result,
+		StartTime: r.StartTime.Format(time.RFC3339),
+	}
+	if !r.EndTime.IsZero() {
+		pbJob.EndTime = r.EndTime.Format(time.RFC3339)
+	}
+	return pbJob
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// MetadataHandler exists to ease integration tests
+var MetadataHandler = handlers.NewMetadataHandler
+
+// safescale metadata history host myhost
+// safescale metadata restore host myhost 20200401T101010.000000000Z
+
+// MetadataListener metadata service server grpc
+type MetadataListener struct{}
+
+// ListRevisions returns the revisions kept in the history of a metadata
+func (s *MetadataListener) ListRevisions(ctx context.Context, in *pb.MetadataReference) (rl *pb.MetadataRevisionList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	kind, ref := in.GetKind(), in.GetName()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", kind, ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Metadata ListRevisions "+kind+" "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't list metadata revisions: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list metadata revisions: no tenant set")
+	}
+
+	handler := MetadataHandler(tenant.Service)
+	revisions, err := handler.ListRevisions(ctx, kind, ref)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	rl = &pb.MetadataRevisionList{}
+	for _, r := range revisions {
+		rl.Revisions = append(rl.Revisions, &pb.MetadataRevision{
+			Id:   r.ID,
+			Time: r.Time.Format(time.RFC3339Nano),
+			Size: r.Size,
+		})
+	}
+	return rl, nil
+}
+
+// Restore rolls a metadata back to one of its revisions
+func (s *MetadataListener) Restore(ctx context.Context, in *pb.MetadataRestoreRequest) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	kind, ref, revision := in.GetMetadata().GetKind(), in.GetMetadata().GetName(), in.GetRevision()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s')", kind, ref, revision), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Metadata Restore "+kind+" "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't restore metadata: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot restore metadata: no tenant set")
+	}
+
+	handler := MetadataHandler(tenant.Service)
+	err = handler.Restore(ctx, kind, ref, revision)
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	log.Infof("Metadata of %s '%s' restored to revision '%s'", kind, ref, revision)
+	return empty, nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/cluster"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+)
+
+// WatchManagedResources updates every period the metrics giving the number of hosts, networks and clusters managed
+// in the tenants used so far
+func WatchManagedResources(period time.Duration) {
+	go func() {
+		for {
+			tenantsMutex.Lock()
+			used := make([]*Tenant, 0, len(tenants))
+			for _, t := range tenants {
+				used = append(used, t)
+			}
+			tenantsMutex.Unlock()
+
+			for _, t := range used {
+				countManagedResources(t.name, t.Service)
+			}
+			time.Sleep(period)
+		}
+	}()
+}
+
+// countManagedResources updates the metrics of the resources managed in a tenant, read from its metadata
+func countManagedResources(name string, svc iaas.Service) {
+	if mh, err := metadata.NewHost(svc); err == nil {
+		count := 0
+		if err = mh.Browse(func(*resources.Host) error { count++; return nil }); err == nil {
+			metrics.SetManagedResources(name, "host", count)
+		} else {
+			log.Debugf("failed to count hosts of tenant '%s': %v", name, err)
+		}
+	}
+	if mn, err := metadata.NewNetwork(svc); err == nil {
+		count := 0
+		if err = mn.Browse(func(*resources.Network) error { count++; return nil }); err == nil {
+			metrics.SetManagedResources(name, "network", count)
+		} else {
+			log.Debugf("failed to count networks of tenant '%s': %v", name, err)
+		}
+	}
+	if clusters, err := cluster.ListWithService(svc); err == nil {
+		metrics.SetManagedResources(name, "cluster", len(clusters))
+	} else {
+		log.Debugf("failed to count clusters of tenant '%s': %v", name, err)
+	}
+}
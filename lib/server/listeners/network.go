@@ -42,6 +42,7 @@ var NetworkHandler = handlers.NewNetworkHandler
 // safescale network list
 // safescale network delete net1
 // safescale network inspect net1
+// safescale network adopt 0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b
 
 // NetworkListener network service server grpc
 type NetworkListener struct{}
@@ -65,7 +66,7 @@ func (s *NetworkListener) Create(ctx context.Context, in *pb.NetworkDefinition)
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't create network: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create network: no tenant set")
@@ -104,15 +105,35 @@ func (s *NetworkListener) Create(ctx context.Context, in *pb.NetworkDefinition)
 		gwImageID,
 		gwName,
 		in.FailOver,
+		in.GetTags(),
 	)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
 	}
 
 	log.Infof("Network '%s' successfuly created.", networkName)
 	return srvutils.ToPBNetwork(network), nil
 }
 
+// CreateWithProgress creates a new network, streaming the progress of the creation before the network created
+func (s *NetworkListener) CreateWithProgress(in *pb.NetworkDefinition, stream pb.NetworkService_CreateWithProgressServer) error {
+	if s == nil {
+		return status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+
+	reporter, sendErr := srvutils.ProgressStream(func(event *pb.ProgressEvent) error {
+		return stream.Send(&pb.NetworkCreationProgress{Event: event})
+	})
+	network, err := s.Create(srvutils.ContextWithProgress(stream.Context(), reporter), in)
+	if err != nil {
+		return err
+	}
+	if err := sendErr(); err != nil {
+		log.Warn(err)
+	}
+	return stream.Send(&pb.NetworkCreationProgress{Network: network})
+}
+
 // List existing networks
 func (s *NetworkListener) List(ctx context.Context, in *pb.NetworkListRequest) (rv *pb.NetworkList, err error) {
 	if s == nil {
@@ -133,16 +154,16 @@ func (s *NetworkListener) List(ctx context.Context, in *pb.NetworkListRequest) (
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't list network: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list networks: no tenant set")
 	}
 
 	handler := NetworkHandler(tenant.Service)
-	networks, err := handler.List(ctx, in.GetAll())
+	networks, err := handler.List(ctx, in.GetAll(), in.GetSelector())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
 	}
 
 	// Map resources.Network to pb.Network
@@ -176,13 +197,13 @@ func (s *NetworkListener) Inspect(ctx context.Context, in *pb.Reference) (net *p
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't inspect network: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect network: no tenant set")
 	}
 
-	handler := NetworkHandler(currentTenant.Service)
+	handler := NetworkHandler(tenant.Service)
 	network, err := handler.Inspect(ctx, ref)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
@@ -216,13 +237,13 @@ func (s *NetworkListener) Delete(ctx context.Context, in *pb.Reference) (buf *go
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't delete network: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete network: no tenant set")
 	}
 
-	handler := NetworkHandler(currentTenant.Service)
+	handler := NetworkHandler(tenant.Service)
 	err = handler.Delete(ctx, ref)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
@@ -254,13 +275,13 @@ func (s *NetworkListener) Destroy(ctx context.Context, in *pb.Reference) (buf *g
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't delete network: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete network: no tenant set")
 	}
 
-	handler := NetworkHandler(currentTenant.Service)
+	handler := NetworkHandler(tenant.Service)
 	err = handler.Destroy(ctx, ref)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
@@ -269,3 +290,76 @@ func (s *NetworkListener) Destroy(ctx context.Context, in *pb.Reference) (buf *g
 	log.Infof("Network '%s' successfully deleted.", ref)
 	return &googleprotobuf.Empty{}, nil
 }
+
+// SetTags sets and removes tags of a network
+func (s *NetworkListener) SetTags(ctx context.Context, in *pb.TagsRequest) (t *pb.Tags, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetResource())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set tags of network: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Set tags of network "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set tags of network: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.Service)
+	tags, err := handler.SetTags(ctx, ref, in.GetSet(), in.GetUnset())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return &pb.Tags{Tags: tags}, nil
+}
+
+// Adopt creates the metadata of a network of the provider created outside of SafeScale, to manage it
+func (s *NetworkListener) Adopt(ctx context.Context, in *pb.Reference) (pn *pb.Network, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	id := in.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot adopt network: id of the network at the provider is missing")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Adopt network "+id); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't adopt network: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot adopt network: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.Service)
+	network, err := handler.Adopt(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	log.Infof("Network '%s' (%s) adopted", network.Name, network.ID)
+	return srvutils.ToPBNetwork(network), nil
+}
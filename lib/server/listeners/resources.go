@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+)
+
+// ResolveResource returns the ID and the name of the resource 'ref' (ID or name) of the gRPC service 'service' in the
+// tenant targeted by the request; implements srvutils.ResourceResolver
+func ResolveResource(ctx context.Context, service string, ref string) (string, string, error) {
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return "", "", nil
+	}
+	svc := tenant.Service
+
+	switch service {
+	case "HostService", "SshService":
+		mh, err := metadata.LoadHost(svc, ref)
+		if err != nil {
+			return "", "", err
+		}
+		host, err := mh.Get()
+		if err != nil {
+			return "", "", err
+		}
+		return host.ID, host.Name, nil
+	case "NetworkService":
+		mn, err := metadata.LoadNetwork(svc, ref)
+		if err != nil {
+			return "", "", err
+		}
+		network, err := mn.Get()
+		if err != nil {
+			return "", "", err
+		}
+		return network.ID, network.Name, nil
+	case "VolumeService":
+		mv, err := metadata.LoadVolume(svc, ref)
+		if err != nil {
+			return "", "", err
+		}
+		volume, err := mv.Get()
+		if err != nil {
+			return "", "", err
+		}
+		return volume.ID, volume.Name, nil
+	case "SecurityGroupService":
+		msg, err := metadata.LoadSecurityGroup(svc, ref)
+		if err != nil {
+			return "", "", err
+		}
+		sg, err := msg.Get()
+		if err != nil {
+			return "", "", err
+		}
+		return sg.ID, sg.Name, nil
+	case "ImageService":
+		mi, err := metadata.LoadImage(svc, ref)
+		if err != nil {
+			return "", "", err
+		}
+		image, err := mi.Get()
+		if err != nil {
+			return "", "", err
+		}
+		return image.ID, image.Name, nil
+	case "ShareService":
+		ms, err := metadata.NewShare(svc)
+		if err != nil {
+			return "", "", err
+		}
+		err = ms.ReadByReference(ref)
+		if err != nil {
+			return "", "", err
+		}
+		return ms.GetIdentity()
+	}
+	// The other resources (clusters, buckets, ...) are only known by their name
+	return "", "", nil
+}
@@ -0,0 +1,338 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// safescale security-group create sg1 --network net1 --description "web servers"
+// safescale security-group rule add sg1 --direction ingress --protocol tcp --port 443
+// safescale security-group rule delete sg1 <rule id>
+// safescale security-group bind sg1 host1
+// safescale security-group unbind sg1 host1
+// safescale security-group list [--all]
+// safescale security-group inspect sg1
+// safescale security-group delete sg1
+
+// SecurityGroupHandler ...
+var SecurityGroupHandler = handlers.NewSecurityGroupHandler
+
+// SecurityGroupListener is the security group service grpc server
+type SecurityGroupListener struct{}
+
+// Create creates a security group
+func (s *SecurityGroupListener) Create(ctx context.Context, in *pb.SecurityGroupDefinition) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot create security group: name cannot be empty")
+	}
+	networkRef := srvutils.GetReference(in.GetNetwork())
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", name, networkRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Security group create "+name); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot create security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.Create(ctx, name, in.GetDescription(), networkRef)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	log.Infof("Security group '%s' created", name)
+	return srvutils.ToPBSecurityGroup(sg), nil
+}
+
+// List lists the security groups
+func (s *SecurityGroupListener) List(ctx context.Context, in *pb.SecurityGroupListRequest) (_ *pb.SecurityGroupList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	all := in.GetAll()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Security groups list"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list security groups: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	list, err := handler.List(ctx, all)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	out := &pb.SecurityGroupList{}
+	for _, sg := range list {
+		out.SecurityGroups = append(out.SecurityGroups, srvutils.ToPBSecurityGroup(sg))
+	}
+	return out, nil
+}
+
+// Inspect returns the security group identified by in
+func (s *SecurityGroupListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot inspect security group: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Security group inspect "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return srvutils.ToPBSecurityGroup(sg), nil
+}
+
+// Delete deletes the security group identified by in
+func (s *SecurityGroupListener) Delete(ctx context.Context, in *pb.Reference) (_ *googleprotobuf.Empty, err error) {
+	empty := &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot delete security group: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Security group delete "+ref); err != nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	err = handler.Delete(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), fmt.Sprintf("cannot delete security group '%s': %s", ref, err.Error()))
+	}
+	log.Infof("Security group '%s' successfully deleted", ref)
+	return empty, nil
+}
+
+// AddRule adds a rule to a security group
+func (s *SecurityGroupListener) AddRule(ctx context.Context, in *pb.SecurityGroupRuleRequest) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetSecurityGroup())
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot add rule: neither name nor id given as reference for security group")
+	}
+	if in.GetRule() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot add rule: no rule given")
+	}
+	rule := srvutils.FromPBSecurityGroupRule(in.GetRule())
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Security group add rule to "+ref); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add rule to security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.AddRule(ctx, ref, rule)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return srvutils.ToPBSecurityGroup(sg), nil
+}
+
+// DeleteRule deletes a rule from a security group
+func (s *SecurityGroupListener) DeleteRule(ctx context.Context, in *pb.SecurityGroupRuleDeleteRequest) (_ *pb.SecurityGroup, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetSecurityGroup())
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot delete rule: neither name nor id given as reference for security group")
+	}
+	ruleID := in.GetRuleId()
+	if ruleID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot delete rule: no rule id given")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, ruleID), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Security group delete rule "+ruleID+" from "+ref); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete rule from security group: no tenant set")
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	sg, err := handler.DeleteRule(ctx, ref, ruleID)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return srvutils.ToPBSecurityGroup(sg), nil
+}
+
+// Bind applies the rules of a security group to a host
+func (s *SecurityGroupListener) Bind(ctx context.Context, in *pb.SecurityGroupBond) (_ *googleprotobuf.Empty, err error) {
+	return s.bond(ctx, in, true)
+}
+
+// Unbind removes the rules of a security group from a host
+func (s *SecurityGroupListener) Unbind(ctx context.Context, in *pb.SecurityGroupBond) (_ *googleprotobuf.Empty, err error) {
+	return s.bond(ctx, in, false)
+}
+
+// bond binds (or unbinds if bind is false) a security group and a host
+func (s *SecurityGroupListener) bond(ctx context.Context, in *pb.SecurityGroupBond, bind bool) (_ *googleprotobuf.Empty, err error) {
+	empty := &googleprotobuf.Empty{}
+	action := "bind"
+	if !bind {
+		action = "unbind"
+	}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetSecurityGroup())
+	if ref == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot %s security group: neither name nor id given as reference for security group", action)
+	}
+	hostRef := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot %s security group: neither name nor id given as reference for host", action)
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', %v)", ref, hostRef, bind), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Security group "+action+" "+ref+" and host "+hostRef); err != nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot %s security group: no tenant set", action)
+	}
+
+	handler := SecurityGroupHandler(tenant.Service)
+	if bind {
+		err = handler.Bind(ctx, ref, hostRef)
+	} else {
+		err = handler.Unbind(ctx, ref, hostRef)
+	}
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return empty, nil
+}
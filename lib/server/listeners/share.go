@@ -69,7 +69,7 @@ func (s *ShareListener) Create(ctx context.Context, in *pb.ShareDefinition) (sd
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't create share: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create share: no tenant set")
@@ -105,7 +105,7 @@ func (s *ShareListener) Delete(ctx context.Context, in *pb.Reference) (empty *go
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't delete share: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete share: no tenant set")
@@ -144,7 +144,7 @@ func (s *ShareListener) List(ctx context.Context, in *googleprotobuf.Empty) (sl
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't list share: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list shares: no tenant set")
@@ -190,7 +190,7 @@ func (s *ShareListener) Mount(ctx context.Context, in *pb.ShareMountDefinition)
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't mount share: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot mount share: no tenant set")
@@ -230,7 +230,7 @@ func (s *ShareListener) Unmount(ctx context.Context, in *pb.ShareMountDefinition
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't mount share: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot unmount share: no tenant set")
@@ -265,7 +265,7 @@ func (s *ShareListener) Inspect(ctx context.Context, in *pb.Reference) (sml *pb.
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't inspect share: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect share: no tenant set")
@@ -67,7 +67,7 @@ func (s *SSHListener) Run(ctx context.Context, in *pb.SshCommand) (sr *pb.SshRes
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't execute ssh command: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot execute ssh command: no tenant set")
@@ -107,7 +107,7 @@ func (s *SSHListener) Copy(ctx context.Context, in *pb.SshCopyCommand) (sr *pb.S
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't copy by ssh command: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot copy by ssh: no tenant set")
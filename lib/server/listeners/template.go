@@ -54,7 +54,7 @@ func (s *TemplateListener) List(ctx context.Context, in *pb.TemplateListRequest)
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't list templates: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list templates: no tenant set")
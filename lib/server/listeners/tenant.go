@@ -19,14 +19,19 @@ package listeners
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
@@ -39,32 +44,130 @@ type Tenant struct {
 }
 
 var (
-	currentTenant *Tenant
+	// tenants contains the tenants already used, by name
+	tenants = map[string]*Tenant{}
+	// pendingTenants contains the tenants whose service is being created, by name; the channel is closed once done
+	pendingTenants = map[string]chan struct{}{}
+	// tenantsMutex protects tenants and pendingTenants; it's never held while creating a service
+	tenantsMutex sync.Mutex
 )
 
-// GetCurrentTenant contains the current tenant
+// useService creates the service of a tenant; replaced in tests
+var useService = iaas.UseService
+
+// GetCurrentTenant returns the tenant targeted by the request carried by ctx
 var GetCurrentTenant = getCurrentTenant
 
-// getCurrentTenant returns the tenant used for commands or, if not set, set the tenant to use if it is the only one registered
-func getCurrentTenant() *Tenant {
-	if currentTenant == nil {
-		tenants, err := iaas.GetTenantNames()
-		if err != nil || len(tenants) != 1 {
-			return nil
+// getCurrentTenant returns the tenant targeted by the request, resolved by the tenant interceptors
+func getCurrentTenant(ctx context.Context) *Tenant {
+	name := srvutils.TenantFromContext(ctx)
+	if name == "" {
+		return nil
+	}
+	tenant, err := useTenant(name)
+	if err != nil {
+		log.Errorf("failed to use tenant '%s': %v", name, err)
+		return nil
+	}
+	return tenant
+}
+
+// useTenant returns the tenant named name, creating its service on first use
+// The service is created without holding tenantsMutex, so that a slow or unreachable provider doesn't block the
+// requests to the other tenants; the concurrent requests to the same tenant wait for this creation
+func useTenant(name string) (*Tenant, error) {
+	for {
+		tenantsMutex.Lock()
+		if tenant, ok := tenants[name]; ok {
+			tenantsMutex.Unlock()
+			return tenant, nil
+		}
+		if pending, ok := pendingTenants[name]; ok {
+			tenantsMutex.Unlock()
+			// Once done, the tenant is found in cache, or the creation failed and is tried again
+			<-pending
+			continue
+		}
+		pending := make(chan struct{})
+		pendingTenants[name] = pending
+		tenantsMutex.Unlock()
+
+		var tenant *Tenant
+		service, err := useService(name)
+		tenantsMutex.Lock()
+		if err == nil {
+			tenant = &Tenant{name: name, Service: service}
+			tenants[name] = tenant
 		}
-		// Set unique tenant as selected
-		log.Println("Unique tenant set")
-		for name := range tenants {
-			service, err := iaas.UseService(name)
-			if err != nil {
-				return nil
-			}
-			currentTenant = &Tenant{name: name, Service: service}
+		delete(pendingTenants, name)
+		tenantsMutex.Unlock()
+		close(pending)
+		return tenant, err
+	}
+}
+
+// TenantMetadataBucket returns the metadata bucket of the tenant, or nil if the tenant cannot be used
+func TenantMetadataBucket(name string) objectstorage.Bucket {
+	tenant, err := useTenant(name)
+	if err != nil {
+		log.Warnf("failed to use tenant '%s': %v", name, err)
+		return nil
+	}
+	return tenant.Service.GetMetadataBucket()
+}
+
+// defaultTenantName returns the name of the tenant to use when the request doesn't tell, if it is the only one registered
+func defaultTenantName() string {
+	names, err := iaas.GetTenantNames()
+	if err != nil || len(names) != 1 {
+		return ""
+	}
+	for name := range names {
+		return name
+	}
+	return ""
+}
+
+// resolveTenant checks the tenant targeted by the request and returns a context telling which tenant to use
+func resolveTenant(ctx context.Context) (context.Context, error) {
+	name := srvutils.TenantFromContext(ctx)
+	if name == "" {
+		name = defaultTenantName()
+		if name == "" {
+			// Let the listeners decide if a tenant is needed
+			return ctx, nil
+		}
+	}
+	if _, err := useTenant(name); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot use tenant '%s': %s", name, err.Error())
+	}
+	return srvutils.ContextWithTenant(ctx, name), nil
+}
+
+// TenantInterceptors returns the interceptors resolving the tenant targeted by each unary and streaming call
+func TenantInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := resolveTenant(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, serverStream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := resolveTenant(serverStream.Context())
+		if err != nil {
+			return err
 		}
+		return handler(srv, &srvutils.WrappedServerStream{ServerStream: serverStream, WrappedContext: ctx})
 	}
-	return currentTenant
+	return unary, stream
 }
 
+// TenantHandler exists to ease integration tests
+var TenantHandler = handlers.NewTenantHandler
+
+// safescale tenant fsck --repair
+
 // TenantListener server is used to implement SafeScale.safescale.
 type TenantListener struct{}
 
@@ -100,7 +203,7 @@ func (s *TenantListener) List(ctx context.Context, in *googleprotobuf.Empty) (li
 	return &pb.TenantList{Tenants: tl}, nil
 }
 
-// Get returns the name of the current tenant used
+// Get returns the name of the tenant targeted by the request
 func (s *TenantListener) Get(ctx context.Context, in *googleprotobuf.Empty) (tn *pb.TenantName, err error) {
 	if s == nil {
 		// FIXME: return a status.Errorf
@@ -117,15 +220,15 @@ func (s *TenantListener) Get(ctx context.Context, in *googleprotobuf.Empty) (tn
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	getCurrentTenant()
-	if currentTenant == nil {
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
 		log.Info("Can't get tenant: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot get tenant: no tenant set")
 	}
-	return &pb.TenantName{Name: currentTenant.name}, nil
+	return &pb.TenantName{Name: tenant.name}, nil
 }
 
-// Set the the tenant to use for each command
+// Set checks the tenant the client wants to use by default
 func (s *TenantListener) Set(ctx context.Context, in *pb.TenantName) (empty *googleprotobuf.Empty, err error) {
 	empty = &googleprotobuf.Empty{}
 	if s == nil {
@@ -147,15 +250,171 @@ func (s *TenantListener) Set(ctx context.Context, in *pb.TenantName) (empty *goo
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	if currentTenant != nil && currentTenant.name == in.GetName() {
-		return empty, nil
+	// The tenant is chosen by each request; Set only checks the tenant can be used, the client keeping it as default
+	_, err = useTenant(name)
+	if err != nil {
+		return empty, fmt.Errorf("unable to set tenant '%s': %s", name, err.Error())
+	}
+	log.Infof("Tenant '%s' checked", name)
+	return empty, nil
+}
+
+// Inspect returns the provider of a tenant (the tenant targeted by the request if no name is given), the rate limits
+// of the calls to the provider and the state of its circuit breaker
+func (s *TenantListener) Inspect(ctx context.Context, in *pb.TenantName) (ti *pb.TenantInspection, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
 	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	name := in.GetName()
 
-	service, err := iaas.UseService(in.GetName())
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant Inspect "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	var tenant *Tenant
+	if name == "" {
+		tenant = GetCurrentTenant(ctx)
+		if tenant == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect tenant: no tenant set")
+		}
+	} else {
+		tenant, err = useTenant(name)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "cannot inspect tenant '%s': %s", name, err.Error())
+		}
+	}
+
+	names, err := iaas.GetTenantNames()
 	if err != nil {
-		return empty, fmt.Errorf("unable to set tenant '%s': %s", name, err.Error())
+		return nil, err
+	}
+	throttling := tenant.Service.GetThrottlingStatus()
+	breaker := throttling.Breaker
+	ti = &pb.TenantInspection{
+		Name:     tenant.name,
+		Provider: names[tenant.name],
+		CircuitBreaker: &pb.TenantCircuitBreaker{
+			State:               breaker.State,
+			ConsecutiveFailures: int32(breaker.ConsecutiveFailures),
+			Threshold:           int32(breaker.Threshold),
+			Cooldown:            int32(breaker.Cooldown.Seconds()),
+			LastError:           breaker.LastError,
+			Trips:               int32(breaker.Trips),
+		},
+	}
+	if !breaker.OpenedAt.IsZero() {
+		ti.CircuitBreaker.OpenedAt = breaker.OpenedAt.Format(time.RFC3339)
+	}
+	for _, rl := range throttling.RateLimits {
+		ti.RateLimits = append(ti.RateLimits, &pb.TenantRateLimit{
+			Operations: rl.Class,
+			Rate:       rl.Rate,
+			Burst:      int32(rl.Burst),
+		})
+	}
+	return ti, nil
+}
+
+// RefreshCache empties the cache of the images, templates, availability zones and regions of the provider of a tenant
+// (the tenant targeted by the request if no name is given)
+func (s *TenantListener) RefreshCache(ctx context.Context, in *pb.TenantName) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	name := in.GetName()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant RefreshCache "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	var tenant *Tenant
+	if name == "" {
+		tenant = GetCurrentTenant(ctx)
+		if tenant == nil {
+			return empty, status.Errorf(codes.FailedPrecondition, "cannot refresh cache: no tenant set")
+		}
+	} else {
+		tenant, err = useTenant(name)
+		if err != nil {
+			return empty, status.Errorf(codes.NotFound, "cannot refresh cache of tenant '%s': %s", name, err.Error())
+		}
 	}
-	currentTenant = &Tenant{name: in.GetName(), Service: service}
-	log.Infof("Current tenant is now '%s'", name)
+	tenant.Service.InvalidateCache()
+	log.Infof("Cache of tenant '%s' emptied", tenant.name)
 	return empty, nil
 }
+
+// Fsck cross-checks the metadata of a tenant (the tenant targeted by the request if no name is given) with the
+// resources of its provider, and repairs the inconsistencies found if asked for
+func (s *TenantListener) Fsck(ctx context.Context, in *pb.TenantFsckRequest) (report *pb.TenantFsckReport, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	name := in.GetName()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %v)", name, in.GetRepair()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant Fsck "+name); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	var tenant *Tenant
+	if name == "" {
+		tenant = GetCurrentTenant(ctx)
+		if tenant == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot check tenant: no tenant set")
+		}
+	} else {
+		tenant, err = useTenant(name)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "cannot check tenant '%s': %s", name, err.Error())
+		}
+	}
+
+	handler := TenantHandler(tenant.Service)
+	findings, err := handler.Fsck(ctx, in.GetRepair())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	report = &pb.TenantFsckReport{Name: tenant.name}
+	for _, f := range findings {
+		report.Findings = append(report.Findings, &pb.TenantFsckFinding{
+			Category: f.Category,
+			Kind:     f.Kind,
+			Id:       f.ID,
+			Name:     f.Name,
+			Detail:   f.Detail,
+			Repaired: f.Repaired,
+			Error:    f.Error,
+		})
+	}
+	log.Infof("Tenant '%s' checked: %d inconsistencies found", tenant.name, len(findings))
+	return report, nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+)
+
+func TestUseTenant_CreatesServiceOutsideLock(t *testing.T) {
+	oldUseService := useService
+	defer func() {
+		useService = oldUseService
+		tenantsMutex.Lock()
+		delete(tenants, "slow")
+		delete(tenants, "fast")
+		tenantsMutex.Unlock()
+	}()
+
+	var created int32
+	started, release := make(chan struct{}), make(chan struct{})
+	useService = func(name string) (iaas.Service, error) {
+		atomic.AddInt32(&created, 1)
+		if name == "slow" {
+			close(started)
+			<-release
+		}
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	slow := make([]*Tenant, 5)
+	for i := range slow {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tenant, err := useTenant("slow")
+			assert.Nil(t, err)
+			slow[i] = tenant
+		}(i)
+	}
+
+	// The tenant "fast" is usable while the service of "slow" is being created
+	<-started
+	done := make(chan struct{})
+	go func() {
+		_, err := useTenant("fast")
+		assert.Nil(t, err)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("creating the service of a tenant blocks the other tenants")
+	}
+
+	close(release)
+	wg.Wait()
+	// The service of "slow" has been created once, and shared by all the requests
+	assert.Equal(t, int32(2), atomic.LoadInt32(&created))
+	require.NotNil(t, slow[0])
+	for _, tenant := range slow {
+		assert.True(t, tenant == slow[0])
+	}
+}
@@ -39,6 +39,8 @@ import (
 // safescale volume delete v1
 // safescale volume inspect v1
 // safescale volume update v1 --speed="HDD" --size=1000
+// safescale volume adopt 5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a
+// safescale volume resize v1 --size=2000
 
 // FIXME Think about this
 // //go:generate mockgen -destination=../mocks/mock_volumeserviceserver.go -package=mocks github.com/CS-SI/SafeScale/lib VolumeServiceServer
@@ -58,8 +60,9 @@ func (s *VolumeListener) List(ctx context.Context, in *pb.VolumeListRequest) (_
 		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
 	}
 	all := in.GetAll()
+	selector := in.GetSelector()
 
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v, '%s')", all, selector), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
@@ -69,16 +72,16 @@ func (s *VolumeListener) List(ctx context.Context, in *pb.VolumeListRequest) (_
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't list volumes: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list volumes: no tenant set")
 	}
 
 	handler := VolumeHandler(tenant.Service)
-	volumes, err := handler.List(ctx, in.GetAll())
+	volumes, err := handler.List(ctx, all, selector)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
 	}
 
 	// Map resources.Volume to pb.Volume
@@ -113,16 +116,16 @@ func (s *VolumeListener) Create(ctx context.Context, in *pb.VolumeDefinition) (_
 	}
 	defer srvutils.JobDeregister(ctx)
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't create volumes: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create volume: no tenant set")
 	}
 
 	handler := VolumeHandler(tenant.Service)
-	vol, err := handler.Create(ctx, name, int(size), volumespeed.Enum(speed))
+	vol, err := handler.Create(ctx, name, int(size), volumespeed.Enum(speed), in.GetTags())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
 	}
 
 	log.Infof("Volume '%s' created", name)
@@ -169,7 +172,7 @@ func (s *VolumeListener) Attach(ctx context.Context, in *pb.VolumeAttachment) (_
 	}
 	defer srvutils.JobDeregister(ctx)
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't attach volumes: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot attach volume: no tenant set")
@@ -212,7 +215,7 @@ func (s *VolumeListener) Detach(ctx context.Context, in *pb.VolumeDetachment) (_
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't detach volumes: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot detach volume: no tenant set")
@@ -228,6 +231,48 @@ func (s *VolumeListener) Detach(ctx context.Context, in *pb.VolumeDetachment) (_
 	return empty, nil
 }
 
+// Resize extends a volume and grows its filesystem if the volume is attached
+func (s *VolumeListener) Resize(ctx context.Context, in *pb.VolumeResizeRequest) (_ *pb.Volume, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetVolume())
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot resize volume: neither name nor id given as reference")
+	}
+	size := in.GetSize()
+	if size <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot resize volume: size must be greater than 0")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", ref, size), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume resize "+ref); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot resize volume: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	volume, err := handler.Resize(ctx, ref, int(size))
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	log.Infof("Volume '%s' resized to %d GB", ref, volume.Size)
+	return srvutils.ToPBVolume(volume), nil
+}
+
 // Delete a volume
 func (s *VolumeListener) Delete(ctx context.Context, in *pb.Reference) (_ *googleprotobuf.Empty, err error) {
 	empty := &googleprotobuf.Empty{}
@@ -252,7 +297,7 @@ func (s *VolumeListener) Delete(ctx context.Context, in *pb.Reference) (_ *googl
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		log.Info("Can't delete volumes: no tenant set")
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete volume: no tenant set")
@@ -290,7 +335,7 @@ func (s *VolumeListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.V
 		defer srvutils.JobDeregister(ctx)
 	}
 
-	tenant := GetCurrentTenant()
+	tenant := GetCurrentTenant(ctx)
 	if tenant == nil {
 		// log.Info("Can't inspect volumes: no tenant set")
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect volume: no tenant set")
@@ -307,3 +352,76 @@ func (s *VolumeListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.V
 
 	return srvutils.ToPBVolumeInfo(volume, mounts), nil
 }
+
+// SetTags sets and removes tags of a volume
+func (s *VolumeListener) SetTags(ctx context.Context, in *pb.TagsRequest) (t *pb.Tags, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetResource())
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot set tags of volume: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume Set tags "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot set tags of volume: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	tags, err := handler.SetTags(ctx, ref, in.GetSet(), in.GetUnset())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+	return &pb.Tags{Tags: tags}, nil
+}
+
+// Adopt creates the metadata of a volume of the provider created outside of SafeScale, to manage it
+func (s *VolumeListener) Adopt(ctx context.Context, in *pb.Reference) (pv *pb.Volume, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	id := in.GetId()
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot adopt volume: id of the volume at the provider is missing")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", id), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Adopt volume "+id); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		log.Info("Can't adopt volume: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot adopt volume: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	volume, err := handler.Adopt(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	log.Infof("Volume '%s' (%s) adopted", volume.Name, volume.ID)
+	return srvutils.ToPBVolume(volume), nil
+}
@@ -76,7 +76,7 @@ func TestCreate(t *testing.T) {
 	// Mock GetCurrentTenant
 	oldGetCurrentTeant := listeners.GetCurrentTenant
 	defer func() { listeners.GetCurrentTenant = oldGetCurrentTeant }()
-	listeners.GetCurrentTenant = func() *listeners.Tenant {
+	listeners.GetCurrentTenant = func(ctx context.Context) *listeners.Tenant {
 		return &listeners.Tenant{}
 	}
 
@@ -129,7 +129,7 @@ func TestCreate_Err(t *testing.T) {
 	// Mock GetCurrentTenant
 	oldGetCurrentTeant := listeners.GetCurrentTenant
 	defer func() { listeners.GetCurrentTenant = oldGetCurrentTeant }()
-	listeners.GetCurrentTenant = func() *listeners.Tenant {
+	listeners.GetCurrentTenant = func(ctx context.Context) *listeners.Tenant {
 		return &listeners.Tenant{}
 	}
 
@@ -152,7 +152,7 @@ func TestCreate_Err_NoTenantSet(t *testing.T) {
 	// Mock GetCurrentTenant
 	oldGetCurrentTeant := listeners.GetCurrentTenant
 	defer func() { listeners.GetCurrentTenant = oldGetCurrentTeant }()
-	listeners.GetCurrentTenant = func() *listeners.Tenant {
+	listeners.GetCurrentTenant = func(ctx context.Context) *listeners.Tenant {
 		return nil
 	}
 	myMockedVolService := &MyMockedVolService{err: errors.New("plop")}
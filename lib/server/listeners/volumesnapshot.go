@@ -0,0 +1,197 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// CreateSnapshot creates a snapshot of a volume
+func (s *VolumeListener) CreateSnapshot(ctx context.Context, in *pb.VolumeSnapshotDefinition) (_ *pb.VolumeSnapshot, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	volumeRef := srvutils.GetReference(in.GetVolume())
+	if volumeRef == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot create volume snapshot: neither name nor id given as reference for volume")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot create volume snapshot: name cannot be empty")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeRef, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume snapshot create "+name+" of volume "+volumeRef); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot create volume snapshot: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	snap, err := handler.CreateSnapshot(ctx, volumeRef, name, in.GetDescription())
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	log.Infof("Snapshot '%s' of volume '%s' created", name, volumeRef)
+	return srvutils.ToPBVolumeSnapshot(snap), nil
+}
+
+// ListSnapshots lists the snapshots, of a volume if one is given
+func (s *VolumeListener) ListSnapshots(ctx context.Context, in *pb.VolumeSnapshotListRequest) (_ *pb.VolumeSnapshotList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	volumeRef := srvutils.GetReference(in.GetVolume())
+	all := in.GetAll()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %v)", volumeRef, all), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume snapshots list"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list volume snapshots: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	snaps, err := handler.ListSnapshots(ctx, volumeRef, all)
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	out := &pb.VolumeSnapshotList{}
+	for i := range snaps {
+		out.Snapshots = append(out.Snapshots, srvutils.ToPBVolumeSnapshot(&snaps[i]))
+	}
+	return out, nil
+}
+
+// DeleteSnapshot deletes a volume snapshot
+func (s *VolumeListener) DeleteSnapshot(ctx context.Context, in *pb.Reference) (_ *googleprotobuf.Empty, err error) {
+	empty := &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot delete volume snapshot: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume snapshot delete "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete volume snapshot: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	err = handler.DeleteSnapshot(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(toGRPCCode(err), fmt.Sprintf("cannot delete volume snapshot '%s': %s", ref, err.Error()))
+	}
+
+	log.Infof("Volume snapshot '%s' deleted", ref)
+	return empty, nil
+}
+
+// RestoreSnapshot creates a volume from a volume snapshot
+func (s *VolumeListener) RestoreSnapshot(ctx context.Context, in *pb.VolumeSnapshotRestoreRequest) (_ *pb.Volume, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Error())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Error())
+	}
+	ref := srvutils.GetReference(in.GetSnapshot())
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot restore volume snapshot: neither name nor id given as reference")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot restore volume snapshot: name of the volume cannot be empty")
+	}
+	size := in.GetSize()
+	speed := in.GetSpeed()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', %d, %s)", ref, name, size, speed.String()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume snapshot restore "+ref+" to volume "+name); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", err.Error()).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant(ctx)
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot restore volume snapshot: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.Service)
+	volume, err := handler.RestoreSnapshot(ctx, ref, name, int(size), volumespeed.Enum(speed))
+	if err != nil {
+		return nil, status.Errorf(toGRPCCode(err), err.Error())
+	}
+
+	log.Infof("Volume '%s' restored from snapshot '%s'", name, ref)
+	return srvutils.ToPBVolume(volume), nil
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// historyFolders contains the folder of each kind of metadata whose history can be browsed and restored
+var historyFolders = map[string]string{
+	"host":    hostsFolderName,
+	"network": networksFolderName,
+	"share":   shareFolderName,
+	"volume":  volumesFolderName,
+	// Cluster metadata are managed by lib/server/cluster/control, in objects named as the clusters (no byID/byName)
+	"cluster": "clusters",
+}
+
+// HistoryKinds returns the kinds of metadata having a history
+func HistoryKinds() []string {
+	var kinds []string
+	for k := range historyFolders {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// rawContent is a metadata content written as is
+type rawContent []byte
+
+// Serialize ...
+func (c *rawContent) Serialize() ([]byte, error) {
+	return *c, nil
+}
+
+// Deserialize ...
+func (c *rawContent) Deserialize(buf []byte) error {
+	*c = append((*c)[:0], buf...)
+	return nil
+}
+
+// locateHistory returns the folder and the subfolder of the metadata of kind 'kind' designated by 'ref' that have a history
+func locateHistory(svc iaas.Service, kind, ref string) (*metadata.Folder, string, error) {
+	if svc == nil {
+		return nil, "", scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, "", scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	folderName, ok := historyFolders[kind]
+	if !ok {
+		return nil, "", scerr.InvalidParameterError("kind", fmt.Sprintf("must be one of %s", strings.Join(HistoryKinds(), ", ")))
+	}
+	folder, err := metadata.NewFolder(svc, folderName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	paths := []string{ByIDFolderName, ByNameFolderName}
+	if kind == "cluster" {
+		paths = []string{"."}
+	}
+	for _, path := range paths {
+		revisions, err := folder.Revisions(path, ref)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(revisions) > 0 {
+			return folder, path, nil
+		}
+	}
+	return nil, "", scerr.NotFoundError(fmt.Sprintf("no revision of %s '%s' found in metadata history", kind, ref))
+}
+
+// Revisions returns the revisions kept in the history of the metadata of kind 'kind' designated by 'ref' (ID or name),
+// from the oldest to the most recent
+func Revisions(svc iaas.Service, kind, ref string) (_ []metadata.Revision, err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", kind, ref), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	folder, path, err := locateHistory(svc, kind, ref)
+	if err != nil {
+		return nil, err
+	}
+	return folder.Revisions(path, ref)
+}
+
+// RestoreRevision writes back the revision 'revision' of the metadata of kind 'kind' designated by 'ref' (ID or name)
+// The content replaced is kept in history, so a restoration can be reverted the same way
+func RestoreRevision(svc iaas.Service, kind, ref, revision string) (err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s')", kind, ref, revision), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	if revision == "" {
+		return scerr.InvalidParameterError("revision", "cannot be empty string")
+	}
+	folder, path, err := locateHistory(svc, kind, ref)
+	if err != nil {
+		return err
+	}
+	content := rawContent{}
+	err = folder.ReadRevision(path, ref, revision, content.Deserialize)
+	if err != nil {
+		return err
+	}
+	id, name, err := identify(kind, ref, content)
+	if err != nil {
+		return err
+	}
+
+	item, err := metadata.NewItem(svc, historyFolders[kind])
+	if err != nil {
+		return err
+	}
+	err = item.Acquire(id)
+	if err != nil {
+		return err
+	}
+	defer item.Release()
+
+	// Reads the current content first, so that it's replaced instead of being created again
+	paths := map[string]string{ByIDFolderName: id, ByNameFolderName: name}
+	if kind == "cluster" {
+		paths = map[string]string{".": name}
+	}
+	for path, key := range paths {
+		err = item.ReadFrom(path, key, func(buf []byte) (serialize.Serializable, error) {
+			return &rawContent{}, nil
+		})
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); !ok {
+				return err
+			}
+		}
+	}
+
+	item.Carry(&content)
+	if kind == "cluster" {
+		return item.Write(name)
+	}
+	err = item.WriteInto(ByIDFolderName, id)
+	if err != nil {
+		return err
+	}
+	return item.WriteInto(ByNameFolderName, name)
+}
+
+// identify returns the ID and the name of the resource described by the metadata 'content' of kind 'kind'
+func identify(kind, ref string, content []byte) (id string, name string, err error) {
+	switch kind {
+	case "cluster":
+		// The object of a cluster is named as the cluster, which is also used to lock it
+		return ref, ref, nil
+	case "host":
+		host := resources.NewHost()
+		err = host.Deserialize(content)
+		id, name = host.ID, host.Name
+	case "network":
+		network := resources.NewNetwork()
+		err = network.Deserialize(content)
+		id, name = network.ID, network.Name
+	case "volume":
+		volume := resources.NewVolume()
+		err = volume.Deserialize(content)
+		id, name = volume.ID, volume.Name
+	case "share":
+		var si shareItem
+		err = (&si).Deserialize(content)
+		id, name = si.ShareID, si.ShareName
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if id == "" || name == "" {
+		return "", "", scerr.InconsistentError(fmt.Sprintf("revision of %s '%s' doesn't contain its ID and name", kind, ref))
+	}
+	return id, name, nil
+}
@@ -101,6 +101,29 @@ func (mh *Host) Write() (err error) {
 	return mh.item.WriteInto(ByIDFolderName, *mh.id)
 }
 
+// Reload reloads the content of the Object Storage, overriding what is in the metadata instance
+func (mh *Host) Reload() (err error) {
+	if mh == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mh.id == nil {
+		return scerr.InvalidInstanceContentError("mh.id", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = mh.ReadByID(*mh.id)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return scerr.NotFoundError(fmt.Sprintf("the metadata of Host '%s' vanished", *mh.name))
+		}
+		return err
+	}
+	return nil
+}
+
 // ReadByReference ...
 func (mh *Host) ReadByReference(ref string) (err error) {
 	if mh == nil {
@@ -382,8 +405,11 @@ func LoadHost(svc iaas.Service, ref string) (mh *Host, err error) {
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (mh *Host) Acquire() {
-	mh.item.Acquire()
+func (mh *Host) Acquire() error {
+	if mh.id == nil {
+		return scerr.InvalidInstanceContentError("mh.id", "cannot be nil")
+	}
+	return mh.item.Acquire(*mh.id)
 }
 
 // Release unlocks the metadata
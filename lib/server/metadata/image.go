@@ -0,0 +1,382 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// imagesFolderName is the technical name of the container used to store image info
+	imagesFolderName = "images"
+)
+
+// Image links Object Storage folder and Images
+// Note: only the images created by SafeScale from a host have metadata
+type Image struct {
+	item *metadata.Item
+	name *string
+	id   *string
+}
+
+// NewImage creates an instance of metadata.Image
+func NewImage(svc iaas.Service) (*Image, error) {
+	if svc == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	anItem, err := metadata.NewItem(svc, imagesFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &Image{
+		item: anItem,
+		name: nil,
+		id:   nil,
+	}, nil
+}
+
+// Carry links a Image instance to the Metadata instance
+func (mi *Image) Carry(image *resources.Image) (*Image, error) {
+	if mi == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return nil, scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+	if image == nil {
+		return nil, scerr.InvalidParameterError("image", "cannot be nil!")
+	}
+	if image.Properties == nil {
+		image.Properties = serialize.NewJSONProperties("resources.image")
+	}
+	mi.item.Carry(image)
+	mi.name = &image.Name
+	mi.id = &image.ID
+	return mi, nil
+}
+
+// Get returns the Image instance linked to metadata
+func (mi *Image) Get() (*resources.Image, error) {
+	if mi == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return nil, scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+	if image, ok := mi.item.Get().(*resources.Image); ok {
+		return image, nil
+	}
+	return nil, scerr.InconsistentError("invalid content in image metadata")
+}
+
+// Write updates the metadata corresponding to the image in the Object Storage
+func (mi *Image) Write() error {
+	if mi == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return scerr.InvalidInstanceContentError("mi.item", "cannot be nil!")
+	}
+
+	err := mi.item.WriteInto(ByIDFolderName, *mi.id)
+	if err != nil {
+		return err
+	}
+	return mi.item.WriteInto(ByNameFolderName, *mi.name)
+}
+
+// Reload reloads the content of the Object Storage, overriding what is in the metadata instance
+func (mi *Image) Reload() error {
+	if mi == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+	err := mi.ReadByID(*mi.id)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return scerr.NotFoundError(fmt.Sprintf("metadata of image '%s' vanished", *mi.name))
+		}
+		return err
+	}
+	return nil
+}
+
+// ReadByReference tries to read with 'ref' as id, then if not found as name
+func (mi *Image) ReadByReference(ref string) (err error) {
+	if mi == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	errID := mi.mayReadByID(ref)
+	errName := mi.mayReadByName(ref)
+
+	if errID != nil && errName != nil {
+		return scerr.NotFoundErrorWithCause(fmt.Sprintf("reference %s not found", ref), scerr.ErrListError([]error{errID, errName}))
+	}
+
+	return nil
+}
+
+// mayReadByID reads the metadata of a image identified by ID from Object Storage
+// Doesn't log error or validate parameters by design; caller does that
+func (mi *Image) mayReadByID(id string) error {
+	image := resources.NewImage()
+	err := mi.item.ReadFrom(ByIDFolderName, id, func(buf []byte) (serialize.Serializable, error) {
+		err := image.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return image, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = mi.Carry(image)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mayReadByName reads the metadata of a image identified by name
+// Doesn't log error or validate parameters by design; caller does that
+func (mi *Image) mayReadByName(name string) error {
+	image := resources.NewImage()
+	err := mi.item.ReadFrom(ByNameFolderName, name, func(buf []byte) (serialize.Serializable, error) {
+		err := image.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return image, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = mi.Carry(image)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadByID reads the metadata of a image identified by ID from Object Storage
+func (mi *Image) ReadByID(id string) (err error) {
+	if mi == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+id+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mi.mayReadByID(id)
+}
+
+// ReadByName reads the metadata of a image identified by name
+func (mi *Image) ReadByName(name string) (err error) {
+	if mi == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+	if name == "" {
+		return scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mi.mayReadByName(name)
+}
+
+// Delete delete the metadata corresponding to the image
+func (mi *Image) Delete() (err error) {
+	if mi == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = mi.item.DeleteFrom(ByIDFolderName, *mi.id)
+	if err != nil {
+		return err
+	}
+	err = mi.item.DeleteFrom(ByNameFolderName, *mi.name)
+	if err != nil {
+		return err
+	}
+	mi.item.Reset()
+	mi.name = nil
+	mi.id = nil
+	return nil
+}
+
+// Browse walks through image folder and executes a callback for each entries
+func (mi *Image) Browse(callback func(*resources.Image) error) (err error) {
+	if mi == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mi.item == nil {
+		return scerr.InvalidInstanceContentError("mi.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mi.item.BrowseInto(ByIDFolderName, func(buf []byte) error {
+		image := resources.NewImage()
+		err := image.Deserialize(buf)
+		if err != nil {
+			return err
+		}
+		return callback(image)
+	})
+}
+
+// SaveImage saves the Image definition in Object Storage
+func SaveImage(svc iaas.Service, image *resources.Image) (mi *Image, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if image == nil {
+		return nil, scerr.InvalidParameterError("image", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+image.Name+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mi, err = NewImage(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	mio, err := mi.Carry(image)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mio.Write()
+	if err != nil {
+		return nil, err
+	}
+
+	return mi, nil
+}
+
+// RemoveImage removes the Image definition from Object Storage
+func RemoveImage(svc iaas.Service, imageID string) (err error) {
+	if svc == nil {
+		return scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if imageID == "" {
+		return scerr.InvalidParameterError("imageID", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+imageID+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	m, err := LoadImage(svc, imageID)
+	if err != nil {
+		return err
+	}
+	return m.Delete()
+}
+
+// LoadImage gets the Image definition from Object Storage
+// logic: Read by ID; if error is ErrNotFound then read by name; if error is ErrNotFound return this error
+//        In case of any other error, abort the retry to propagate the error
+//        If retry times out, return errNotFound
+func LoadImage(svc iaas.Service, ref string) (mi *Image, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+ref+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mi, err = NewImage(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	retryErr := retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			innerErr := mi.ReadByReference(ref)
+			if innerErr != nil {
+				if _, ok := innerErr.(scerr.ErrNotFound); ok {
+					return retry.AbortedError("no metadata found", innerErr)
+				}
+				return innerErr
+			}
+			return nil
+		},
+		2*temporal.GetDefaultDelay(),
+	)
+	if retryErr != nil {
+		switch err := retryErr.(type) {
+		case retry.ErrAborted:
+			return nil, err.Cause()
+		case scerr.ErrTimeout:
+			return nil, err
+		default:
+			return nil, scerr.Cause(err)
+		}
+	}
+
+	return mi, nil
+}
@@ -417,8 +417,11 @@ func (m *Network) ListHosts() (list []*resources.Host, err error) {
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (m *Network) Acquire() {
-	m.item.Acquire()
+func (m *Network) Acquire() error {
+	if m.id == nil {
+		return scerr.InvalidInstanceContentError("m.id", "cannot be nil")
+	}
+	return m.item.Acquire(*m.id)
 }
 
 // Release unlocks the metadata
@@ -679,10 +682,14 @@ func (mg *Gateway) Delete() (err error) {
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
-	mg.network.Acquire()
+	err = mg.network.Acquire()
+	if err != nil {
+		return err
+	}
 
 	mgm, err := mg.network.Get()
 	if err != nil {
+		mg.network.Release()
 		return err
 	}
 
@@ -692,14 +699,17 @@ func (mg *Gateway) Delete() (err error) {
 	if err != nil {
 		return err
 	}
-	mg.host.Acquire()
+	err = mg.host.Acquire()
+	if err != nil {
+		return err
+	}
 	defer mg.host.Release()
 	return mg.host.Delete()
 }
 
 // Acquire waits until the write lock is available, then locks the metadata
-func (mg *Gateway) Acquire() {
-	mg.host.Acquire()
+func (mg *Gateway) Acquire() error {
+	return mg.host.Acquire()
 }
 
 // Release unlocks the metadata
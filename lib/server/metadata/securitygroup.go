@@ -0,0 +1,381 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// securityGroupsFolderName is the technical name of the container used to store security group info
+	securityGroupsFolderName = "securitygroups"
+)
+
+// SecurityGroup links Object Storage folder and SecurityGroups
+type SecurityGroup struct {
+	item *metadata.Item
+	name *string
+	id   *string
+}
+
+// NewSecurityGroup creates an instance of metadata.SecurityGroup
+func NewSecurityGroup(svc iaas.Service) (*SecurityGroup, error) {
+	if svc == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	anItem, err := metadata.NewItem(svc, securityGroupsFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &SecurityGroup{
+		item: anItem,
+		name: nil,
+		id:   nil,
+	}, nil
+}
+
+// Carry links a SecurityGroup instance to the Metadata instance
+func (msg *SecurityGroup) Carry(sg *resources.SecurityGroup) (*SecurityGroup, error) {
+	if msg == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return nil, scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+	if sg == nil {
+		return nil, scerr.InvalidParameterError("sg", "cannot be nil!")
+	}
+	if sg.Properties == nil {
+		sg.Properties = serialize.NewJSONProperties("resources.securitygroup")
+	}
+	msg.item.Carry(sg)
+	msg.name = &sg.Name
+	msg.id = &sg.ID
+	return msg, nil
+}
+
+// Get returns the SecurityGroup instance linked to metadata
+func (msg *SecurityGroup) Get() (*resources.SecurityGroup, error) {
+	if msg == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return nil, scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+	if sg, ok := msg.item.Get().(*resources.SecurityGroup); ok {
+		return sg, nil
+	}
+	return nil, scerr.InconsistentError("invalid content in security group metadata")
+}
+
+// Write updates the metadata corresponding to the security group in the Object Storage
+func (msg *SecurityGroup) Write() error {
+	if msg == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return scerr.InvalidInstanceContentError("msg.item", "cannot be nil!")
+	}
+
+	err := msg.item.WriteInto(ByIDFolderName, *msg.id)
+	if err != nil {
+		return err
+	}
+	return msg.item.WriteInto(ByNameFolderName, *msg.name)
+}
+
+// Reload reloads the content of the Object Storage, overriding what is in the metadata instance
+func (msg *SecurityGroup) Reload() error {
+	if msg == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+	err := msg.ReadByID(*msg.id)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return scerr.NotFoundError(fmt.Sprintf("metadata of security group '%s' vanished", *msg.name))
+		}
+		return err
+	}
+	return nil
+}
+
+// ReadByReference tries to read with 'ref' as id, then if not found as name
+func (msg *SecurityGroup) ReadByReference(ref string) (err error) {
+	if msg == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	errID := msg.mayReadByID(ref)
+	errName := msg.mayReadByName(ref)
+
+	if errID != nil && errName != nil {
+		return scerr.NotFoundErrorWithCause(fmt.Sprintf("reference %s not found", ref), scerr.ErrListError([]error{errID, errName}))
+	}
+
+	return nil
+}
+
+// mayReadByID reads the metadata of a security group identified by ID from Object Storage
+// Doesn't log error or validate parameters by design; caller does that
+func (msg *SecurityGroup) mayReadByID(id string) error {
+	sg := resources.NewSecurityGroup()
+	err := msg.item.ReadFrom(ByIDFolderName, id, func(buf []byte) (serialize.Serializable, error) {
+		err := sg.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return sg, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = msg.Carry(sg)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mayReadByName reads the metadata of a security group identified by name
+// Doesn't log error or validate parameters by design; caller does that
+func (msg *SecurityGroup) mayReadByName(name string) error {
+	sg := resources.NewSecurityGroup()
+	err := msg.item.ReadFrom(ByNameFolderName, name, func(buf []byte) (serialize.Serializable, error) {
+		err := sg.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return sg, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = msg.Carry(sg)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadByID reads the metadata of a security group identified by ID from Object Storage
+func (msg *SecurityGroup) ReadByID(id string) (err error) {
+	if msg == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+id+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return msg.mayReadByID(id)
+}
+
+// ReadByName reads the metadata of a security group identified by name
+func (msg *SecurityGroup) ReadByName(name string) (err error) {
+	if msg == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+	if name == "" {
+		return scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return msg.mayReadByName(name)
+}
+
+// Delete delete the metadata corresponding to the security group
+func (msg *SecurityGroup) Delete() (err error) {
+	if msg == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = msg.item.DeleteFrom(ByIDFolderName, *msg.id)
+	if err != nil {
+		return err
+	}
+	err = msg.item.DeleteFrom(ByNameFolderName, *msg.name)
+	if err != nil {
+		return err
+	}
+	msg.item.Reset()
+	msg.name = nil
+	msg.id = nil
+	return nil
+}
+
+// Browse walks through security group folder and executes a callback for each entries
+func (msg *SecurityGroup) Browse(callback func(*resources.SecurityGroup) error) (err error) {
+	if msg == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if msg.item == nil {
+		return scerr.InvalidInstanceContentError("msg.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return msg.item.BrowseInto(ByIDFolderName, func(buf []byte) error {
+		sg := resources.NewSecurityGroup()
+		err := sg.Deserialize(buf)
+		if err != nil {
+			return err
+		}
+		return callback(sg)
+	})
+}
+
+// SaveSecurityGroup saves the SecurityGroup definition in Object Storage
+func SaveSecurityGroup(svc iaas.Service, sg *resources.SecurityGroup) (msg *SecurityGroup, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if sg == nil {
+		return nil, scerr.InvalidParameterError("sg", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+sg.Name+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	msg, err = NewSecurityGroup(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	msgo, err := msg.Carry(sg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = msgo.Write()
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// RemoveSecurityGroup removes the SecurityGroup definition from Object Storage
+func RemoveSecurityGroup(svc iaas.Service, sgID string) (err error) {
+	if svc == nil {
+		return scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if sgID == "" {
+		return scerr.InvalidParameterError("sgID", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+sgID+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	m, err := LoadSecurityGroup(svc, sgID)
+	if err != nil {
+		return err
+	}
+	return m.Delete()
+}
+
+// LoadSecurityGroup gets the SecurityGroup definition from Object Storage
+// logic: Read by ID; if error is ErrNotFound then read by name; if error is ErrNotFound return this error
+//        In case of any other error, abort the retry to propagate the error
+//        If retry times out, return errNotFound
+func LoadSecurityGroup(svc iaas.Service, ref string) (msg *SecurityGroup, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+ref+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	msg, err = NewSecurityGroup(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	retryErr := retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			innerErr := msg.ReadByReference(ref)
+			if innerErr != nil {
+				if _, ok := innerErr.(scerr.ErrNotFound); ok {
+					return retry.AbortedError("no metadata found", innerErr)
+				}
+				return innerErr
+			}
+			return nil
+		},
+		2*temporal.GetDefaultDelay(),
+	)
+	if retryErr != nil {
+		switch err := retryErr.(type) {
+		case retry.ErrAborted:
+			return nil, err.Cause()
+		case scerr.ErrTimeout:
+			return nil, err
+		default:
+			return nil, scerr.Cause(err)
+		}
+	}
+
+	return msg, nil
+}
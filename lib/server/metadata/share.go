@@ -115,6 +115,21 @@ func (ms *Share) Get() (string, error) {
 	return "", scerr.InconsistentError("share metadata content must be a *shareItem")
 }
 
+// GetIdentity returns the ID and the name of the share
+func (ms *Share) GetIdentity() (string, string, error) {
+	if ms == nil {
+		return "", "", scerr.InvalidInstanceError()
+	}
+	if ms.item == nil {
+		return "", "", scerr.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	if ei, ok := ms.item.Get().(*shareItem); ok {
+		return ei.ShareID, ei.ShareName, nil
+	}
+
+	return "", "", scerr.InconsistentError("share metadata content must be a *shareItem")
+}
+
 // Write updates the metadata corresponding to the share in the Object Storage
 func (ms *Share) Write() error {
 	if ms == nil {
@@ -314,16 +329,17 @@ func (ms *Share) Browse(callback func(string, string) error) error {
 // }
 
 // Acquire waits until the write lock is available, then locks the metadata.
-//
-// May panic (see scerr.OnPanic() usage to intercept and translate it to an error)
-func (ms *Share) Acquire() {
+func (ms *Share) Acquire() error {
 	if ms == nil {
-		panic("invalid instance")
+		return scerr.InvalidInstanceError()
 	}
 	if ms.item == nil {
-		panic("invalid instance content: ms.item cannot be nil")
+		return scerr.InvalidInstanceContentError("ms.item", "cannot be nil")
+	}
+	if ms.id == nil {
+		return scerr.InvalidInstanceContentError("ms.id", "cannot be nil")
 	}
-	ms.item.Acquire()
+	return ms.item.Acquire(*ms.id)
 }
 
 // Release unlocks the metadata
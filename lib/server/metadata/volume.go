@@ -281,6 +281,25 @@ func (mv *Volume) Browse(callback func(*resources.Volume) error) (err error) {
 	})
 }
 
+// Acquire waits until the write lock is available, then locks the metadata
+func (mv *Volume) Acquire() error {
+	if mv == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mv.item == nil {
+		return scerr.InvalidInstanceContentError("mv.item", "cannot be nil")
+	}
+	if mv.id == nil {
+		return scerr.InvalidInstanceContentError("mv.id", "cannot be nil")
+	}
+	return mv.item.Acquire(*mv.id)
+}
+
+// Release unlocks the metadata
+func (mv *Volume) Release() {
+	mv.item.Release()
+}
+
 // SaveVolume saves the Volume definition in Object Storage
 func SaveVolume(svc iaas.Service, volume *resources.Volume) (mv *Volume, err error) {
 	if svc == nil {
@@ -0,0 +1,381 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// volumeSnapshotsFolderName is the technical name of the container used to store volume snapshot info
+	volumeSnapshotsFolderName = "volumesnapshots"
+)
+
+// VolumeSnapshot links Object Storage folder and VolumeSnapshots
+type VolumeSnapshot struct {
+	item *metadata.Item
+	name *string
+	id   *string
+}
+
+// NewVolumeSnapshot creates an instance of metadata.VolumeSnapshot
+func NewVolumeSnapshot(svc iaas.Service) (*VolumeSnapshot, error) {
+	if svc == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	anItem, err := metadata.NewItem(svc, volumeSnapshotsFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &VolumeSnapshot{
+		item: anItem,
+		name: nil,
+		id:   nil,
+	}, nil
+}
+
+// Carry links a VolumeSnapshot instance to the Metadata instance
+func (mvs *VolumeSnapshot) Carry(snap *resources.VolumeSnapshot) (*VolumeSnapshot, error) {
+	if mvs == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return nil, scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+	if snap == nil {
+		return nil, scerr.InvalidParameterError("snap", "cannot be nil!")
+	}
+	if snap.Properties == nil {
+		snap.Properties = serialize.NewJSONProperties("resources.volumesnapshot")
+	}
+	mvs.item.Carry(snap)
+	mvs.name = &snap.Name
+	mvs.id = &snap.ID
+	return mvs, nil
+}
+
+// Get returns the VolumeSnapshot instance linked to metadata
+func (mvs *VolumeSnapshot) Get() (*resources.VolumeSnapshot, error) {
+	if mvs == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return nil, scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+	if snap, ok := mvs.item.Get().(*resources.VolumeSnapshot); ok {
+		return snap, nil
+	}
+	return nil, scerr.InconsistentError("invalid content in volume snapshot metadata")
+}
+
+// Write updates the metadata corresponding to the volume snapshot in the Object Storage
+func (mvs *VolumeSnapshot) Write() error {
+	if mvs == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return scerr.InvalidInstanceContentError("mvs.item", "cannot be nil!")
+	}
+
+	err := mvs.item.WriteInto(ByIDFolderName, *mvs.id)
+	if err != nil {
+		return err
+	}
+	return mvs.item.WriteInto(ByNameFolderName, *mvs.name)
+}
+
+// Reload reloads the content of the Object Storage, overriding what is in the metadata instance
+func (mvs *VolumeSnapshot) Reload() error {
+	if mvs == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+	err := mvs.ReadByID(*mvs.id)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return scerr.NotFoundError(fmt.Sprintf("metadata of volume snapshot '%s' vanished", *mvs.name))
+		}
+		return err
+	}
+	return nil
+}
+
+// ReadByReference tries to read with 'ref' as id, then if not found as name
+func (mvs *VolumeSnapshot) ReadByReference(ref string) (err error) {
+	if mvs == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	errID := mvs.mayReadByID(ref)
+	errName := mvs.mayReadByName(ref)
+
+	if errID != nil && errName != nil {
+		return scerr.NotFoundErrorWithCause(fmt.Sprintf("reference %s not found", ref), scerr.ErrListError([]error{errID, errName}))
+	}
+
+	return nil
+}
+
+// mayReadByID reads the metadata of a volume snapshot identified by ID from Object Storage
+// Doesn't log error or validate parameters by design; caller does that
+func (mvs *VolumeSnapshot) mayReadByID(id string) error {
+	snap := resources.NewVolumeSnapshot()
+	err := mvs.item.ReadFrom(ByIDFolderName, id, func(buf []byte) (serialize.Serializable, error) {
+		err := snap.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return snap, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = mvs.Carry(snap)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mayReadByName reads the metadata of a volume snapshot identified by name
+// Doesn't log error or validate parameters by design; caller does that
+func (mvs *VolumeSnapshot) mayReadByName(name string) error {
+	snap := resources.NewVolumeSnapshot()
+	err := mvs.item.ReadFrom(ByNameFolderName, name, func(buf []byte) (serialize.Serializable, error) {
+		err := snap.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return snap, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = mvs.Carry(snap)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadByID reads the metadata of a volume snapshot identified by ID from Object Storage
+func (mvs *VolumeSnapshot) ReadByID(id string) (err error) {
+	if mvs == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+id+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mvs.mayReadByID(id)
+}
+
+// ReadByName reads the metadata of a volume snapshot identified by name
+func (mvs *VolumeSnapshot) ReadByName(name string) (err error) {
+	if mvs == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+	if name == "" {
+		return scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mvs.mayReadByName(name)
+}
+
+// Delete delete the metadata corresponding to the volume snapshot
+func (mvs *VolumeSnapshot) Delete() (err error) {
+	if mvs == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = mvs.item.DeleteFrom(ByIDFolderName, *mvs.id)
+	if err != nil {
+		return err
+	}
+	err = mvs.item.DeleteFrom(ByNameFolderName, *mvs.name)
+	if err != nil {
+		return err
+	}
+	mvs.item.Reset()
+	mvs.name = nil
+	mvs.id = nil
+	return nil
+}
+
+// Browse walks through volume snapshot folder and executes a callback for each entries
+func (mvs *VolumeSnapshot) Browse(callback func(*resources.VolumeSnapshot) error) (err error) {
+	if mvs == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mvs.item == nil {
+		return scerr.InvalidInstanceContentError("mvs.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mvs.item.BrowseInto(ByIDFolderName, func(buf []byte) error {
+		snap := resources.NewVolumeSnapshot()
+		err := snap.Deserialize(buf)
+		if err != nil {
+			return err
+		}
+		return callback(snap)
+	})
+}
+
+// SaveVolumeSnapshot saves the VolumeSnapshot definition in Object Storage
+func SaveVolumeSnapshot(svc iaas.Service, snap *resources.VolumeSnapshot) (mvs *VolumeSnapshot, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if snap == nil {
+		return nil, scerr.InvalidParameterError("snap", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+snap.Name+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mvs, err = NewVolumeSnapshot(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	mvso, err := mvs.Carry(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mvso.Write()
+	if err != nil {
+		return nil, err
+	}
+
+	return mvs, nil
+}
+
+// RemoveVolumeSnapshot removes the VolumeSnapshot definition from Object Storage
+func RemoveVolumeSnapshot(svc iaas.Service, snapshotID string) (err error) {
+	if svc == nil {
+		return scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if snapshotID == "" {
+		return scerr.InvalidParameterError("snapshotID", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+snapshotID+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	m, err := LoadVolumeSnapshot(svc, snapshotID)
+	if err != nil {
+		return err
+	}
+	return m.Delete()
+}
+
+// LoadVolumeSnapshot gets the VolumeSnapshot definition from Object Storage
+// logic: Read by ID; if error is ErrNotFound then read by name; if error is ErrNotFound return this error
+//        In case of any other error, abort the retry to propagate the error
+//        If retry times out, return errNotFound
+func LoadVolumeSnapshot(svc iaas.Service, ref string) (mvs *VolumeSnapshot, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+ref+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mvs, err = NewVolumeSnapshot(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	retryErr := retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			innerErr := mvs.ReadByReference(ref)
+			if innerErr != nil {
+				if _, ok := innerErr.(scerr.ErrNotFound); ok {
+					return retry.AbortedError("no metadata found", innerErr)
+				}
+				return innerErr
+			}
+			return nil
+		},
+		2*temporal.GetDefaultDelay(),
+	)
+	if retryErr != nil {
+		switch err := retryErr.(type) {
+		case retry.ErrAborted:
+			return nil, err.Cause()
+		case scerr.ErrTimeout:
+			return nil, err
+		default:
+			return nil, scerr.Cause(err)
+		}
+	}
+
+	return mvs, nil
+}
@@ -0,0 +1,477 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+const (
+	// TenantHeader is the HTTP header selecting the tenant targeted by a request (the current tenant of safescaled if absent)
+	TenantHeader = "X-SafeScale-Tenant"
+	// AsyncHeader is the HTTP header asking, when 'true', to run a mutating request as a background job
+	AsyncHeader = "X-SafeScale-Async"
+	// JobHeader is the HTTP header of the responses giving the uuid of the job run for the request
+	JobHeader = "X-SafeScale-Job"
+
+	// OpenAPIPath is the path of the OpenAPI document describing the gateway
+	OpenAPIPath = "/v1/openapi.json"
+)
+
+// Route maps HTTP requests to a unary RPC of safescaled
+type Route struct {
+	// Method is the HTTP method of the route
+	Method string
+	// Path is the pattern of the path of the route; a segment '{field}' sets the field of the request message
+	// (nested fields are separated by dots, as in '{volume.name}')
+	Path string
+	// Service and RPC name the RPC called, as declared in safescale.proto
+	Service string
+	RPC     string
+	// Body tells if the request message is taken from the body of the HTTP request; otherwise its fields are
+	// taken from the query parameters
+	Body bool
+	// Summary describes the route in the OpenAPI document
+	Summary string
+}
+
+// Routes are the routes served by the gateway
+var Routes = []Route{
+	{"GET", "/v1/tenants", "TenantService", "List", false, "Lists the tenants"},
+	{"GET", "/v1/tenants/current", "TenantService", "Get", false, "Returns the tenant used when none is selected"},
+	{"GET", "/v1/tenants/{name}", "TenantService", "Inspect", false, "Inspects a tenant: provider, rate limits and circuit breaker"},
+	{"POST", "/v1/tenants/{name}/refresh-cache", "TenantService", "RefreshCache", false, "Empties the cache of the provider of a tenant"},
+	{"POST", "/v1/tenants/{name}/fsck", "TenantService", "Fsck", true, "Cross-checks the metadata of a tenant with its provider, and repairs them if asked for"},
+
+	{"GET", "/v1/images", "ImageService", "List", false, "Lists the images"},
+	{"POST", "/v1/images", "ImageService", "Create", true, "Creates an image from a host"},
+	{"DELETE", "/v1/images/{name}", "ImageService", "Delete", false, "Deletes an image created from a host"},
+	{"GET", "/v1/templates", "TemplateService", "List", false, "Lists the templates"},
+
+	{"GET", "/v1/networks", "NetworkService", "List", false, "Lists the networks"},
+	{"POST", "/v1/networks", "NetworkService", "Create", true, "Creates a network"},
+	{"POST", "/v1/networks/adopt", "NetworkService", "Adopt", true, "Adopts a network of the provider created outside of SafeScale"},
+	{"GET", "/v1/networks/{name}", "NetworkService", "Inspect", false, "Inspects a network"},
+	{"DELETE", "/v1/networks/{name}", "NetworkService", "Delete", false, "Deletes a network"},
+	{"POST", "/v1/networks/{resource.name}/tags", "NetworkService", "SetTags", true, "Sets and removes tags of a network"},
+
+	{"GET", "/v1/hosts", "HostService", "List", false, "Lists the hosts"},
+	{"POST", "/v1/hosts", "HostService", "Create", true, "Creates a host"},
+	{"POST", "/v1/hosts/adopt", "HostService", "Adopt", true, "Adopts a host of the provider created outside of SafeScale"},
+	{"GET", "/v1/hosts/{name}", "HostService", "Inspect", false, "Inspects a host"},
+	{"PUT", "/v1/hosts/{name}", "HostService", "Resize", true, "Resizes a host"},
+	{"DELETE", "/v1/hosts/{name}", "HostService", "Delete", false, "Deletes a host"},
+	{"GET", "/v1/hosts/{name}/status", "HostService", "Status", false, "Returns the status of a host"},
+	{"GET", "/v1/hosts/{name}/ssh", "HostService", "SSH", false, "Returns the SSH configuration of a host"},
+	{"POST", "/v1/hosts/{name}/start", "HostService", "Start", false, "Starts a host"},
+	{"POST", "/v1/hosts/{name}/stop", "HostService", "Stop", false, "Stops a host"},
+	{"POST", "/v1/hosts/{name}/reboot", "HostService", "Reboot", false, "Reboots a host"},
+	{"POST", "/v1/hosts/{resource.name}/tags", "HostService", "SetTags", true, "Sets and removes tags of a host"},
+
+	{"GET", "/v1/volumes", "VolumeService", "List", false, "Lists the volumes"},
+	{"POST", "/v1/volumes", "VolumeService", "Create", true, "Creates a volume"},
+	{"POST", "/v1/volumes/adopt", "VolumeService", "Adopt", true, "Adopts a volume of the provider created outside of SafeScale"},
+	{"GET", "/v1/volumes/{name}", "VolumeService", "Inspect", false, "Inspects a volume"},
+	{"DELETE", "/v1/volumes/{name}", "VolumeService", "Delete", false, "Deletes a volume"},
+	{"POST", "/v1/volumes/{volume.name}/attach", "VolumeService", "Attach", true, "Attaches a volume to a host"},
+	{"POST", "/v1/volumes/{volume.name}/detach", "VolumeService", "Detach", true, "Detaches a volume from a host"},
+	{"POST", "/v1/volumes/{volume.name}/resize", "VolumeService", "Resize", true, "Resizes a volume and grows its filesystem"},
+	{"POST", "/v1/volumes/{resource.name}/tags", "VolumeService", "SetTags", true, "Sets and removes tags of a volume"},
+	{"POST", "/v1/volumes/{volume.name}/snapshots", "VolumeService", "CreateSnapshot", true, "Creates a snapshot of a volume"},
+	{"GET", "/v1/volume-snapshots", "VolumeService", "ListSnapshots", false, "Lists the volume snapshots (of a volume with 'volume.name')"},
+	{"DELETE", "/v1/volume-snapshots/{name}", "VolumeService", "DeleteSnapshot", false, "Deletes a volume snapshot"},
+	{"POST", "/v1/volume-snapshots/{snapshot.name}/restore", "VolumeService", "RestoreSnapshot", true, "Creates a volume from a volume snapshot"},
+
+	{"GET", "/v1/security-groups", "SecurityGroupService", "List", false, "Lists the security groups"},
+	{"POST", "/v1/security-groups", "SecurityGroupService", "Create", true, "Creates a security group"},
+	{"GET", "/v1/security-groups/{name}", "SecurityGroupService", "Inspect", false, "Inspects a security group"},
+	{"DELETE", "/v1/security-groups/{name}", "SecurityGroupService", "Delete", false, "Deletes a security group"},
+	{"POST", "/v1/security-groups/{security_group.name}/rules", "SecurityGroupService", "AddRule", true, "Adds a rule to a security group"},
+	{"DELETE", "/v1/security-groups/{security_group.name}/rules/{rule_id}", "SecurityGroupService", "DeleteRule", false, "Deletes a rule from a security group"},
+	{"POST", "/v1/security-groups/{security_group.name}/bind", "SecurityGroupService", "Bind", true, "Binds a security group to a host"},
+	{"POST", "/v1/security-groups/{security_group.name}/unbind", "SecurityGroupService", "Unbind", true, "Unbinds a security group from a host"},
+
+	{"GET", "/v1/shares", "ShareService", "List", false, "Lists the shares"},
+	{"POST", "/v1/shares", "ShareService", "Create", true, "Creates a share"},
+	{"GET", "/v1/shares/{name}", "ShareService", "Inspect", false, "Inspects a share"},
+	{"DELETE", "/v1/shares/{name}", "ShareService", "Delete", false, "Deletes a share"},
+	{"POST", "/v1/shares/{share.name}/mount", "ShareService", "Mount", true, "Mounts a share on a host"},
+	{"POST", "/v1/shares/{share.name}/unmount", "ShareService", "Unmount", true, "Unmounts a share from a host"},
+
+	{"GET", "/v1/buckets", "BucketService", "List", false, "Lists the buckets"},
+	{"POST", "/v1/buckets", "BucketService", "Create", true, "Creates a bucket"},
+	{"GET", "/v1/buckets/{name}", "BucketService", "Inspect", false, "Inspects a bucket"},
+	{"DELETE", "/v1/buckets/{name}", "BucketService", "Delete", false, "Deletes a bucket"},
+	{"POST", "/v1/buckets/{bucket}/mount", "BucketService", "Mount", true, "Mounts a bucket on a host"},
+	{"POST", "/v1/buckets/{bucket}/unmount", "BucketService", "Unmount", true, "Unmounts a bucket from a host"},
+
+	{"GET", "/v1/clusters", "ClusterService", "List", false, "Lists the clusters"},
+	{"POST", "/v1/clusters", "ClusterService", "Create", true, "Creates a cluster"},
+	{"GET", "/v1/clusters/{name}", "ClusterService", "Inspect", false, "Inspects a cluster"},
+	{"DELETE", "/v1/clusters/{name}", "ClusterService", "Delete", false, "Deletes a cluster"},
+	{"GET", "/v1/clusters/{name}/admin-password", "ClusterService", "GetAdminPassword", false, "Returns the password of the administrator of a cluster"},
+	{"POST", "/v1/clusters/{resource.name}/tags", "ClusterService", "SetTags", true, "Sets and removes tags of a cluster"},
+
+	{"GET", "/v1/jobs", "JobService", "List", false, "Lists the jobs"},
+	{"GET", "/v1/jobs/{uuid}", "JobService", "Inspect", false, "Inspects a job"},
+	{"DELETE", "/v1/jobs/{uuid}", "JobService", "Stop", false, "Stops a job"},
+
+	{"GET", "/v1/audit", "AuditService", "List", false, "Lists the audit records"},
+
+	{"GET", "/v1/metadata/{kind}/{name}/revisions", "MetadataService", "ListRevisions", false, "Lists the revisions kept in the history of a metadata"},
+	{"POST", "/v1/metadata/{metadata.kind}/{metadata.name}/restore", "MetadataService", "Restore", true, "Rolls a metadata back to one of its revisions"},
+}
+
+// Gateway serves a REST/JSON API in front of the gRPC services of safescaled.
+// The requests are converted to the request messages of the RPCs and handled in-process by the implementations of
+// the services, through the same interceptors as the gRPC endpoint: authentication (bearer token, API key or client
+// certificate), tenant resolution, access control, audit and jobs apply the same way.
+type Gateway struct {
+	interceptor grpc.UnaryServerInterceptor
+	routes      []boundRoute
+	openAPI     []byte
+}
+
+type boundRoute struct {
+	Route
+	segments []string
+	server   interface{}
+	method   reflect.Value
+}
+
+// NewGateway returns a Gateway serving Routes with the implementations of the services in services (indexed by the
+// name of the service), through interceptor (may be nil). The routes whose service is missing are not served.
+func NewGateway(services map[string]interface{}, interceptor grpc.UnaryServerInterceptor) (*Gateway, error) {
+	g := &Gateway{interceptor: interceptor}
+	for _, r := range Routes {
+		server, ok := services[r.Service]
+		if !ok || server == nil {
+			logrus.Debugf("REST gateway: service '%s' not available, route '%s %s' not served", r.Service, r.Method, r.Path)
+			continue
+		}
+		method := reflect.ValueOf(server).MethodByName(r.RPC)
+		if !method.IsValid() || !isUnaryMethod(method.Type()) {
+			return nil, fmt.Errorf("'%s' is not a unary RPC of %s", r.RPC, r.Service)
+		}
+		g.routes = append(g.routes, boundRoute{
+			Route:    r,
+			segments: splitPath(r.Path),
+			server:   server,
+			method:   method,
+		})
+	}
+
+	document, err := json.MarshalIndent(g.OpenAPI(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	g.openAPI = document
+	return g, nil
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+)
+
+// isUnaryMethod tells if t is the type of a method implementing a unary RPC: func(context.Context, *In) (*Out, error)
+func isUnaryMethod(t reflect.Type) bool {
+	return t.NumIn() == 2 && t.In(0) == contextType && t.In(1).Implements(messageType) &&
+		t.NumOut() == 2 && t.Out(0).Implements(messageType) && t.Out(1) == errorType
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// match returns the route matching the method and the path of a request, with the values of its path parameters.
+// allowed is set if the path matches routes with other methods only.
+func (g *Gateway) match(method, path string) (route *boundRoute, params map[string]string, allowed []string) {
+	segments := splitPath(path)
+	for i := range g.routes {
+		r := &g.routes[i]
+		values, ok := matchSegments(r.segments, segments)
+		if !ok {
+			continue
+		}
+		if r.Method != method {
+			allowed = append(allowed, r.Method)
+			continue
+		}
+		return r, values, nil
+	}
+	return nil, nil, allowed
+}
+
+func matchSegments(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	values := map[string]string{}
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			values[p[1:len(p)-1]] = segments[i]
+			continue
+		}
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == OpenAPIPath {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(g.openAPI)
+		return
+	}
+
+	route, params, allowed := g.match(r.Method, r.URL.Path)
+	if route == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+			return
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no route for '%s'", r.URL.Path))
+		return
+	}
+
+	in, err := route.request(r, params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	jobUUID, err := uuid.NewV4()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set(JobHeader, jobUUID.String())
+
+	info := &grpc.UnaryServerInfo{Server: route.server, FullMethod: "/" + route.Service + "/" + route.RPC}
+	var handled int32
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		out := route.method.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(req)})
+		atomic.StoreInt32(&handled, 1)
+		err, _ := out[1].Interface().(error)
+		return out[0].Interface(), err
+	}
+	ctx := incomingContext(r, jobUUID.String())
+	var out interface{}
+	if g.interceptor != nil {
+		out, err = g.interceptor(ctx, in, info, handler)
+	} else {
+		out, err = handler(ctx, in)
+	}
+	if err != nil {
+		st, _ := status.FromError(err)
+		writeError(w, HTTPStatus(st.Code()), st.Message())
+		return
+	}
+	code := http.StatusOK
+	if atomic.LoadInt32(&handled) == 0 {
+		// the request goes on as a background job (see AsyncHeader)
+		code = http.StatusAccepted
+	}
+
+	msg, ok := out.(proto.Message)
+	if !ok || reflect.ValueOf(msg).IsNil() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var buffer bytes.Buffer
+	marshaler := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	if err = marshaler.Marshal(&buffer, msg); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(buffer.Bytes())
+}
+
+// request builds the request message of the RPC of the route from the body (if the route has one), the query
+// parameters and the path parameters of r, the latter taking precedence
+func (route *boundRoute) request(r *http.Request, params map[string]string) (proto.Message, error) {
+	fields := map[string]interface{}{}
+	if route.Body && r.Body != nil {
+		content, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %v", err)
+		}
+		if len(bytes.TrimSpace(content)) > 0 {
+			if err = json.Unmarshal(content, &fields); err != nil {
+				return nil, fmt.Errorf("invalid request body: %v", err)
+			}
+		}
+	} else {
+		for k, v := range r.URL.Query() {
+			if len(v) > 0 {
+				setField(fields, k, queryValue(v[len(v)-1]))
+			}
+		}
+	}
+	for k, v := range params {
+		setField(fields, k, v)
+	}
+
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	in := reflect.New(route.method.Type().In(1).Elem()).Interface().(proto.Message)
+	if err = jsonpb.Unmarshal(bytes.NewReader(content), in); err != nil {
+		return nil, fmt.Errorf("invalid request: %v", err)
+	}
+	return in, nil
+}
+
+// setField sets the field designated by a dotted path in fields, creating the intermediate objects
+func setField(fields map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	for _, p := range parts[:len(parts)-1] {
+		sub, ok := fields[p].(map[string]interface{})
+		if !ok {
+			sub = map[string]interface{}{}
+			fields[p] = sub
+		}
+		fields = sub
+	}
+	fields[parts[len(parts)-1]] = value
+}
+
+// queryValue converts the value of a query parameter; booleans are converted, other values are kept as strings,
+// which jsonpb accepts for numeric fields
+func queryValue(v string) interface{} {
+	switch strings.ToLower(v) {
+	case "true", "":
+		// a parameter without value, as in '?all', is a flag
+		return true
+	case "false":
+		return false
+	}
+	return v
+}
+
+// incomingContext returns the context of the in-process call handling r, carrying the metadata and the peer the
+// interceptors expect from a gRPC call
+func incomingContext(r *http.Request, jobUUID string) context.Context {
+	md := metadata.MD{"uuid": []string{jobUUID}}
+	if v := r.Header.Get("Authorization"); v != "" {
+		md["authorization"] = []string{v}
+	}
+	if v := r.Header.Get("X-Api-Key"); v != "" {
+		md["x-api-key"] = []string{v}
+	}
+	if v := r.Header.Get(TenantHeader); v != "" {
+		md[srvutils.TenantMetadataKey] = []string{v}
+	}
+	if strings.EqualFold(r.Header.Get(AsyncHeader), "true") {
+		md[srvutils.AsyncMetadataKey] = []string{"true"}
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+
+	p := &peer.Peer{Addr: remoteAddr(r.RemoteAddr)}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+	}
+	return peer.NewContext(ctx, p)
+}
+
+// remoteAddr is the address of an HTTP client, as net.Addr
+type remoteAddr string
+
+func (a remoteAddr) Network() string { return "tcp" }
+func (a remoteAddr) String() string  { return string(a) }
+
+// HTTPStatus returns the HTTP status corresponding to a gRPC status code
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorBody is the body of the responses of failed requests
+type errorBody struct {
+	Code  int    `json:"code"`
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(errorBody{Code: code, Error: message})
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// fakeHostService implements the RPCs of HostService used by the tests
+type fakeHostService struct {
+	pb.HostServiceServer
+	created *pb.HostDefinition
+}
+
+func (s *fakeHostService) Create(ctx context.Context, in *pb.HostDefinition) (*pb.Host, error) {
+	s.created = in
+	return &pb.Host{Id: "id-" + in.Name, Name: in.Name}, nil
+}
+
+func (s *fakeHostService) Inspect(ctx context.Context, in *pb.Reference) (*pb.Host, error) {
+	if in.Name != "known" {
+		return nil, status.Errorf(codes.NotFound, "host '%s' not found", in.Name)
+	}
+	return &pb.Host{Id: "id-known", Name: in.Name}, nil
+}
+
+func (s *fakeHostService) Delete(ctx context.Context, in *pb.Reference) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func TestGateway(t *testing.T) {
+	var seenMethod, seenTenant string
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		seenMethod = info.FullMethod
+		seenTenant = srvutils.TenantFromContext(ctx)
+		if md, _ := metadata.FromIncomingContext(ctx); len(md.Get("authorization")) == 0 {
+			return nil, status.Errorf(codes.Unauthenticated, "missing authentication token")
+		}
+		return handler(ctx, req)
+	}
+	service := &fakeHostService{}
+	g, err := NewGateway(map[string]interface{}{"HostService": service}, interceptor)
+	require.Nil(t, err)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer secret")
+		r.Header.Set(TenantHeader, "TestTenant")
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do("POST", "/v1/hosts", `{"name": "myhost", "public": true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/HostService/Create", seenMethod)
+	assert.Equal(t, "TestTenant", seenTenant)
+	assert.NotEmpty(t, w.Header().Get(JobHeader))
+	require.NotNil(t, service.created)
+	assert.Equal(t, "myhost", service.created.Name)
+	assert.True(t, service.created.Public)
+	host := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &host))
+	assert.Equal(t, "id-myhost", host["id"])
+
+	w = do("GET", "/v1/hosts/known", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/HostService/Inspect", seenMethod)
+
+	w = do("GET", "/v1/hosts/unknown", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "host 'unknown' not found")
+
+	w = do("PATCH", "/v1/hosts/known", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = do("GET", "/v1/networks", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do("POST", "/v1/hosts", `{"unknown_field": 1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	r := httptest.NewRequest("DELETE", "/v1/hosts/known", nil)
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOpenAPI(t *testing.T) {
+	g, err := NewGateway(map[string]interface{}{"HostService": &fakeHostService{}}, nil)
+	require.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", OpenAPIPath, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	document := struct {
+		Swagger     string                                       `json:"swagger"`
+		Paths       map[string]map[string]map[string]interface{} `json:"paths"`
+		Definitions map[string]map[string]interface{}            `json:"definitions"`
+	}{}
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &document))
+	assert.Equal(t, "2.0", document.Swagger)
+	assert.Equal(t, "HostService_Create", document.Paths["/v1/hosts"]["post"]["operationId"])
+	assert.Contains(t, document.Paths["/v1/hosts/{name}"], "get")
+	assert.NotContains(t, document.Paths, "/v1/networks")
+	require.Contains(t, document.Definitions, "HostDefinition")
+	assert.Contains(t, document.Definitions["HostDefinition"]["properties"], "image_id")
+	assert.Contains(t, document.Definitions, "HostSizing")
+}
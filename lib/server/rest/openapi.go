@@ -0,0 +1,219 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// OpenAPI returns the OpenAPI (version 2.0) document describing the routes served by the gateway.
+// The schemas are generated from the Go types protoc generates from safescale.proto, using the field names of the
+// proto file, as the gateway does; the tests check them against the JSON actually read and written by the gateway
+// for each route.
+func (g *Gateway) OpenAPI() map[string]interface{} {
+	definitions := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":  map[string]interface{}{"type": "integer", "format": "int32"},
+				"error": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+	paths := map[string]interface{}{}
+	for _, r := range g.routes {
+		item, ok := paths[r.Path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[r.Path] = item
+		}
+		item[strings.ToLower(r.Method)] = operation(r, definitions)
+	}
+
+	return map[string]interface{}{
+		"swagger": "2.0",
+		"info": map[string]interface{}{
+			"title":       "SafeScale",
+			"description": "REST/JSON API of safescaled",
+			"version":     "v1",
+		},
+		"consumes": []string{"application/json"},
+		"produces": []string{"application/json"},
+		"securityDefinitions": map[string]interface{}{
+			"bearer": map[string]interface{}{"type": "apiKey", "in": "header", "name": "Authorization"},
+			"apiKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-Api-Key"},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearer": []string{}},
+			map[string]interface{}{"apiKey": []string{}},
+		},
+		"paths":       paths,
+		"definitions": definitions,
+	}
+}
+
+// operation returns the OpenAPI operation of a route, adding the schemas it uses to definitions
+func operation(r boundRoute, definitions map[string]interface{}) map[string]interface{} {
+	in := r.method.Type().In(1).Elem()
+	out := r.method.Type().Out(0).Elem()
+
+	parameters := []interface{}{
+		map[string]interface{}{
+			"name": TenantHeader, "in": "header", "type": "string", "required": false,
+			"description": "Tenant targeted by the request; the current tenant of safescaled if absent",
+		},
+	}
+	inPath := map[string]bool{}
+	for _, s := range r.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			name := s[1 : len(s)-1]
+			inPath[name] = true
+			parameters = append(parameters, map[string]interface{}{
+				"name": name, "in": "path", "type": "string", "required": true,
+			})
+		}
+	}
+	if r.Body {
+		parameters = append(parameters, map[string]interface{}{
+			"name": "body", "in": "body", "required": true, "schema": schemaRef(in, definitions),
+		})
+	} else {
+		for _, f := range messageFields(in) {
+			if inPath[f.name] {
+				continue
+			}
+			s := schema(f.typ, definitions)
+			if t := s["type"]; t == nil || t == "object" || t == "array" {
+				continue
+			}
+			parameter := map[string]interface{}{"name": f.name, "in": "query", "required": false}
+			for k, v := range s {
+				parameter[k] = v
+			}
+			parameters = append(parameters, parameter)
+		}
+	}
+	if r.Method != http.MethodGet {
+		parameters = append(parameters, map[string]interface{}{
+			"name": AsyncHeader, "in": "header", "type": "boolean", "required": false,
+			"description": "Runs a mutating request as a background job, whose uuid is returned in the header " + JobHeader,
+		})
+	}
+
+	success := map[string]interface{}{"description": "Success", "schema": schemaRef(out, definitions)}
+	responses := map[string]interface{}{
+		"200":     success,
+		"default": map[string]interface{}{"description": "Error", "schema": map[string]interface{}{"$ref": "#/definitions/Error"}},
+	}
+	if r.Method != http.MethodGet {
+		responses["202"] = map[string]interface{}{"description": "Accepted, the request goes on as a background job"}
+	}
+	return map[string]interface{}{
+		"operationId": r.Service + "_" + r.RPC,
+		"summary":     r.Summary,
+		"tags":        []string{strings.TrimSuffix(r.Service, "Service")},
+		"parameters":  parameters,
+		"responses":   responses,
+	}
+}
+
+type messageField struct {
+	name string
+	typ  reflect.Type
+}
+
+// messageFields returns the fields of the generated struct t, named as in the proto file; oneof fields are skipped
+func messageFields(t reflect.Type) []messageField {
+	var fields []messageField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("protobuf")
+		if tag == "" {
+			continue
+		}
+		name := ""
+		for _, part := range strings.Split(tag, ",") {
+			if strings.HasPrefix(part, "name=") {
+				name = strings.TrimPrefix(part, "name=")
+			}
+		}
+		if name == "" {
+			continue
+		}
+		fields = append(fields, messageField{name: name, typ: f.Type})
+	}
+	return fields
+}
+
+// schemaRef returns a reference to the schema of the message type t, defining it in definitions if needed
+func schemaRef(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := t.Name()
+	if strings.HasPrefix(t.PkgPath(), "github.com/golang/protobuf/ptypes") {
+		// well-known types of protobuf (google.protobuf.Empty, ...)
+		name = "google.protobuf." + name
+	}
+	if _, ok := definitions[name]; !ok {
+		definitions[name] = nil // breaks recursion
+		properties := map[string]interface{}{}
+		for _, f := range messageFields(t) {
+			properties[f.name] = schema(f.typ, definitions)
+		}
+		definitions[name] = map[string]interface{}{"type": "object", "properties": properties}
+	}
+	return map[string]interface{}{"$ref": "#/definitions/" + name}
+}
+
+// schema returns the schema of a field of type t, as encoded by jsonpb
+func schema(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Struct:
+		return schemaRef(t, definitions)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schema(t.Elem(), definitions)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schema(t.Elem(), definitions)}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Int32:
+		if t.Name() != "int32" {
+			// enumerations are encoded with the name of their values
+			return map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Int64, reflect.Uint64:
+		// 64-bit integers are encoded as strings
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	default:
+		return map[string]interface{}{}
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+)
+
+// allServices returns a server for every service of Routes; their methods are never called (see TestOpenAPI_MatchesGatewayJSON)
+func allServices() map[string]interface{} {
+	return map[string]interface{}{
+		"TenantService":        struct{ pb.TenantServiceServer }{},
+		"ImageService":         struct{ pb.ImageServiceServer }{},
+		"TemplateService":      struct{ pb.TemplateServiceServer }{},
+		"NetworkService":       struct{ pb.NetworkServiceServer }{},
+		"HostService":          struct{ pb.HostServiceServer }{},
+		"VolumeService":        struct{ pb.VolumeServiceServer }{},
+		"SecurityGroupService": struct{ pb.SecurityGroupServiceServer }{},
+		"ShareService":         struct{ pb.ShareServiceServer }{},
+		"BucketService":        struct{ pb.BucketServiceServer }{},
+		"ClusterService":       struct{ pb.ClusterServiceServer }{},
+		"JobService":           struct{ pb.JobServiceServer }{},
+		"AuditService":         struct{ pb.AuditServiceServer }{},
+		"MetadataService":      struct{ pb.MetadataServiceServer }{},
+	}
+}
+
+// populate sets every field of the message pointed by v, so that its JSON contains all the fields; the messages
+// nested deeper than depth are left nil
+func populate(v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if depth <= 0 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		populate(v.Elem(), depth-1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Tag.Get("protobuf") != "" {
+				populate(v.Field(i), depth)
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte("content"))
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Ptr && depth <= 0 {
+			// jsonpb doesn't accept nil messages in lists
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		populate(v.Index(0), depth)
+	case reflect.Map:
+		if v.Type().Elem().Kind() == reflect.Ptr && depth <= 0 {
+			return
+		}
+		key := reflect.New(v.Type().Key()).Elem()
+		populate(key, depth)
+		value := reflect.New(v.Type().Elem()).Elem()
+		populate(value, depth)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int32:
+		if v.Type().Name() == "int32" {
+			v.SetInt(1)
+		}
+		// enumerations keep their first value, which jsonpb encodes with its name
+	case reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	}
+}
+
+// checkJSON checks that value, decoded from the JSON produced by jsonpb, matches the schema s of the OpenAPI document
+func checkJSON(value interface{}, s map[string]interface{}, definitions map[string]interface{}, path string) []string {
+	if ref, ok := s["$ref"].(string); ok {
+		if value == nil {
+			// message not set
+			return nil
+		}
+		definition, ok := definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: missing definition '%s'", path, ref)}
+		}
+		s = definition
+	}
+
+	var problems []string
+	switch s["type"] {
+	case "object":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object, got %v", path, value)}
+		}
+		if additional, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			for k, v := range fields {
+				problems = append(problems, checkJSON(v, additional, definitions, path+"."+k)...)
+			}
+			return problems
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		for k, v := range fields {
+			property, ok := properties[k].(map[string]interface{})
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: field '%s' is not in the document", path, k))
+				continue
+			}
+			problems = append(problems, checkJSON(v, property, definitions, path+"."+k)...)
+		}
+		for k := range properties {
+			if _, ok := fields[k]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: field '%s' of the document is not in the JSON", path, k))
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an array, got %v", path, value)}
+		}
+		for i, item := range items {
+			problems = append(problems, checkJSON(item, s["items"].(map[string]interface{}), definitions, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected a string, got %v", path, value))
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			problems = append(problems, fmt.Sprintf("%s: expected an integer, got %v", path, value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected a number, got %v", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected a boolean, got %v", path, value))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("%s: unexpected schema %v", path, s))
+	}
+	return problems
+}
+
+// TestOpenAPI_MatchesGatewayJSON checks, for each route, that the request and response messages as encoded by the
+// gateway match the schemas of the OpenAPI document
+func TestOpenAPI_MatchesGatewayJSON(t *testing.T) {
+	responses := map[string]reflect.Type{}
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		out := reflect.New(responses[info.FullMethod].Elem())
+		populate(out.Elem(), 3)
+		return out.Interface(), nil
+	}
+	g, err := NewGateway(allServices(), interceptor)
+	require.Nil(t, err)
+	require.Len(t, g.routes, len(Routes))
+	for _, r := range g.routes {
+		responses["/"+r.Service+"/"+r.RPC] = r.method.Type().Out(0)
+	}
+
+	document := struct {
+		Paths       map[string]map[string]map[string]interface{} `json:"paths"`
+		Definitions map[string]interface{}                       `json:"definitions"`
+	}{}
+	require.Nil(t, json.Unmarshal(g.openAPI, &document))
+
+	for _, r := range g.routes {
+		name := r.Method + " " + r.Path
+		operation := document.Paths[r.Path][strings.ToLower(r.Method)]
+		require.NotNil(t, operation, name)
+
+		path := r.Path
+		var body bytes.Buffer
+		for _, p := range operation["parameters"].([]interface{}) {
+			parameter := p.(map[string]interface{})
+			switch parameter["in"] {
+			case "path":
+				path = strings.Replace(path, "{"+parameter["name"].(string)+"}", "value", 1)
+			case "body":
+				in := reflect.New(r.method.Type().In(1).Elem())
+				populate(in.Elem(), 3)
+				marshaler := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+				require.Nil(t, marshaler.Marshal(&body, in.Interface().(proto.Message)), name)
+				fields := map[string]interface{}{}
+				require.Nil(t, json.Unmarshal(body.Bytes(), &fields), name)
+				schema := parameter["schema"].(map[string]interface{})
+				assert.Empty(t, checkJSON(fields, schema, document.Definitions, "body"), name)
+			}
+		}
+		require.NotContains(t, path, "{", name)
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(r.Method, path, &body))
+		require.Contains(t, []int{http.StatusOK, http.StatusAccepted}, w.Code, "%s: %s", name, w.Body.String())
+		out := map[string]interface{}{}
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &out), name)
+		schema := operation["responses"].(map[string]interface{})["200"].(map[string]interface{})["schema"].(map[string]interface{})
+		assert.Empty(t, checkJSON(out, schema, document.Definitions, "response"), name)
+	}
+}
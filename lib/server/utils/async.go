@@ -0,0 +1,107 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AsyncMetadataKey is the gRPC metadata key asking safescaled to run a mutating request as a background job
+const AsyncMetadataKey = "async"
+
+// IsAsync tells if the request carried by ctx asks to be run as a background job
+func IsAsync(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(AsyncMetadataKey)
+	return len(values) > 0 && values[0] == "true"
+}
+
+// DetachContext returns a context carrying the gRPC metadata, the caller, the progress reporter and
+// the job tracking of ctx, but not its cancellation nor its deadline
+func DetachContext(ctx context.Context) context.Context {
+	detached := context.Background()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		detached = metadata.NewIncomingContext(detached, md)
+	}
+	detached = ContextWithCaller(detached, CallerFromContext(ctx))
+	detached = ContextWithProgress(detached, ProgressFromContext(ctx))
+	return InheritJobTracking(detached, ctx)
+}
+
+// AsyncInterceptor returns the interceptor running as background jobs the unary requests asking it (see AsyncMetadataKey)
+// when eligible returns true for their RPC (all the RPCs if eligible is nil); other requests are run synchronously.
+// The job is identified by the uuid of the request and can be followed with JobService; the caller receives at once an
+// empty response.
+// It must be chained after the interceptors of ServerSecurity, tenant resolution and authorization, and before the ones
+// that have to see the end of the job (audit, job history).
+func AsyncInterceptor(eligible func(fullMethod string) bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !IsAsync(ctx) || (eligible != nil && !eligible(info.FullMethod)) {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("uuid")
+		if len(values) == 0 || values[0] == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "cannot run '%s' asynchronously: no uuid in grpc metadata", info.FullMethod)
+		}
+		uuid := values[0]
+		resp, err := emptyResponse(info)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+
+		// The job is registered before returning, so it can be followed at once; the handler registers it again
+		// with its own command and cancel function
+		jobCtx, cancelFunc := context.WithCancel(DetachContext(ctx))
+		service, method := SplitMethod(info.FullMethod)
+		command := strings.TrimSpace(fmt.Sprintf("%s/%s %s", service, method, ResourceName(req)))
+		if err := JobRegister(jobCtx, cancelFunc, command); err != nil {
+			cancelFunc()
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		go func() {
+			defer cancelFunc()
+			defer JobDeregisterUUID(uuid)
+			if _, err := handler(jobCtx, req); err != nil {
+				logrus.Warnf("asynchronous job '%s' (%s) failed: %v", uuid, info.FullMethod, err)
+			}
+		}()
+		return resp, nil
+	}
+}
+
+// emptyResponse returns an empty response of the type returned by the RPC
+func emptyResponse(info *grpc.UnaryServerInfo) (interface{}, error) {
+	_, method := SplitMethod(info.FullMethod)
+	m := reflect.ValueOf(info.Server).MethodByName(method)
+	if !m.IsValid() || m.Type().NumOut() == 0 || m.Type().Out(0).Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("cannot determine the response type of '%s'", info.FullMethod)
+	}
+	return reflect.New(m.Type().Out(0).Elem()).Interface(), nil
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/security/model"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// DefaultAuthorizationService is the name of the service of the security model describing safescaled
+const DefaultAuthorizationService = "safescaled"
+
+// Authorizer checks the permissions of the callers of safescaled using the security model (see lib/security/model).
+//
+// Each RPC is mapped to a permission:
+//   - the action is the name of the method (for example "Delete" for HostService/Delete)
+//   - the resource is "<tenant>/<gRPC service>/<resource name>" (for example "tenantY/HostService/ci-host")
+//
+// so an AccessPermission with ResourcePattern "tenantY/NetworkService/*" and Action "Create" allows
+// to create networks in tenant "tenantY", and an AccessPermission with ResourcePattern "*/*/ci-*" and Action "ALL"
+// allows to do anything on resources whose name starts with "ci-".
+//
+// A resource may be designated by its ID or by its name: when a ResourceResolver is set, the permission is checked
+// on both, so that a permission given on the name cannot be bypassed using the ID.
+type Authorizer struct {
+	dataAccess *model.DataAccess
+	service    string
+	onDenial   func(ctx context.Context, fullMethod string, req interface{}, err error)
+	resolver   ResourceResolver
+}
+
+// ResourceResolver returns the ID and the name of the resource designated by 'ref' (ID or name) in the gRPC service
+// 'service' ("HostService" for example) of the tenant of ctx; returns a scerr.ErrNotFound if the resource doesn't
+// exist (yet), and an empty ID and name if the resources of the service cannot be resolved
+type ResourceResolver func(ctx context.Context, service string, ref string) (id string, name string, err error)
+
+// NewAuthorizer creates an Authorizer using the security database reached with dialect and dsn,
+// checking the permissions given on the service of the security model
+func NewAuthorizer(dialect, dsn, service string) *Authorizer {
+	if service == "" {
+		service = DefaultAuthorizationService
+	}
+	return &Authorizer{
+		dataAccess: model.NewDataAccess(dialect, dsn),
+		service:    service,
+	}
+}
+
+// OnDenial sets the function called with each denied call, for example to record it in the audit log
+func (a *Authorizer) OnDenial(fn func(ctx context.Context, fullMethod string, req interface{}, err error)) {
+	if a != nil {
+		a.onDenial = fn
+	}
+}
+
+// ResolveWith sets the function resolving the references of the resources to their ID and name
+func (a *Authorizer) ResolveWith(fn ResourceResolver) {
+	if a != nil {
+		a.resolver = fn
+	}
+}
+
+// Interceptors returns the interceptors denying unauthorized unary and streaming calls with codes.PermissionDenied.
+// They must be chained after the interceptors of ServerSecurity (identifying the caller) and of tenant resolution.
+func (a *Authorizer) Interceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	if a == nil {
+		return nil, nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.check(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, serverStream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.IsClientStream {
+			// The requests of a client stream are not known yet, the permission applies to any resource name
+			if err := a.check(serverStream.Context(), info.FullMethod, nil); err != nil {
+				return err
+			}
+			return handler(srv, serverStream)
+		}
+		// The single request of a server stream is checked when the handler receives it, before the listener is called
+		return handler(srv, &RequestServerStream{
+			ServerStream: serverStream,
+			OnRequest: func(req interface{}) error {
+				return a.check(serverStream.Context(), info.FullMethod, req)
+			},
+		})
+	}
+	return unary, stream
+}
+
+// check authorizes the call and reports its denial
+func (a *Authorizer) check(ctx context.Context, fullMethod string, req interface{}) error {
+	err := a.authorize(ctx, fullMethod, req)
+	if err != nil && a.onDenial != nil {
+		a.onDenial(ctx, fullMethod, req, err)
+	}
+	return err
+}
+
+// authorize returns nil if the caller found in ctx is allowed to call fullMethod with req
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string, req interface{}) error {
+	caller := CallerFromContext(ctx)
+	if caller.Internal {
+		return nil
+	}
+	if caller.Identity == "" {
+		return status.Errorf(codes.PermissionDenied, "unidentified caller is not allowed to call '%s'", fullMethod)
+	}
+
+	service, action := SplitMethod(fullMethod)
+	refs, err := a.resolve(ctx, service, ResourceName(req))
+	if err != nil {
+		logrus.Errorf("failed to resolve resource of call of '%s/%s' by '%s': %v", service, action, caller.Identity, err)
+		return status.Errorf(codes.PermissionDenied, "failed to check permissions of '%s'", caller.Identity)
+	}
+	var resource string
+	for _, ref := range refs {
+		resource = strings.Join([]string{TenantFromContext(ctx), service, ref}, "/")
+		ok, err := a.dataAccess.IsAuthorized(caller.Identity, a.service, resource, action)
+		if err != nil {
+			logrus.Errorf("failed to check permissions of '%s': %v", caller.Identity, err)
+			return status.Errorf(codes.PermissionDenied, "failed to check permissions of '%s'", caller.Identity)
+		}
+		if ok {
+			return nil
+		}
+	}
+	logrus.Warnf("denied call of '%s/%s' on '%s' to '%s'", service, action, resource, caller.Identity)
+	return status.Errorf(codes.PermissionDenied, "'%s' is not allowed to call '%s/%s' on '%s'", caller.Identity, service, action, resource)
+}
+
+// resolve returns the references under which the permissions on the resource 'ref' of 'service' are checked: its
+// name and its ID if the resource exists, 'ref' itself otherwise
+func (a *Authorizer) resolve(ctx context.Context, service string, ref string) ([]string, error) {
+	if ref == "" || a.resolver == nil {
+		return []string{ref}, nil
+	}
+	id, name, err := a.resolver(ctx, service, ref)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return []string{ref}, nil
+		}
+		return nil, err
+	}
+	var refs []string
+	for _, r := range []string{name, id} {
+		if r != "" && (len(refs) == 0 || refs[0] != r) {
+			refs = append(refs, r)
+		}
+	}
+	if len(refs) == 0 {
+		return []string{ref}, nil
+	}
+	return refs, nil
+}
+
+// SplitMethod splits a gRPC full method name ("/safescale.HostService/Delete") into service ("HostService")
+// and method ("Delete")
+func SplitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	service, method := fullMethod, ""
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		service, method = fullMethod[:i], fullMethod[i+1:]
+	}
+	if i := strings.LastIndex(service, "."); i >= 0 {
+		service = service[i+1:]
+	}
+	return service, method
+}
+
+// ResourceName returns the name (or id) of the resource targeted by a request, or an empty string if
+// the request doesn't target a specific resource
+func ResourceName(req interface{}) string {
+	switch in := req.(type) {
+	case nil:
+		return ""
+	case *pb.Reference:
+		return GetReference(in)
+	case *pb.TagsRequest:
+		return GetReference(in.GetResource())
+	case *pb.VolumeAttachment:
+		return GetReference(in.GetVolume())
+	case *pb.VolumeDetachment:
+		return GetReference(in.GetVolume())
+	case *pb.VolumeResizeRequest:
+		return GetReference(in.GetVolume())
+	case *pb.VolumeSnapshotDefinition:
+		return GetReference(in.GetVolume())
+	case *pb.VolumeSnapshotListRequest:
+		return GetReference(in.GetVolume())
+	case *pb.SecurityGroupRuleRequest:
+		return GetReference(in.GetSecurityGroup())
+	case *pb.SecurityGroupRuleDeleteRequest:
+		return GetReference(in.GetSecurityGroup())
+	case *pb.SecurityGroupBond:
+		return GetReference(in.GetSecurityGroup())
+	case *pb.BucketMountingPoint:
+		return in.GetBucket()
+	case *pb.SshCommand:
+		return GetReference(in.GetHost())
+	case *pb.SshCopyCommand:
+		for _, p := range []string{in.GetSource(), in.GetDestination()} {
+			if i := strings.Index(p, ":"); i > 0 {
+				return p[:i]
+			}
+		}
+		return ""
+	case *pb.ShareMountDefinition:
+		return GetReference(in.GetShare())
+	case *pb.FeatureRequest:
+		return in.GetTarget()
+	case *pb.JobDefinition:
+		return in.GetUuid()
+	case *pb.HostAdoptRequest:
+		return in.GetId()
+	case *pb.MetadataRestoreRequest:
+		return in.GetMetadata().GetName()
+	case interface{ GetName() string }:
+		return in.GetName()
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/security/model"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// newTestAuthorizer creates an Authorizer whose security database gives to 'email' the permissions 'perms'; the
+// database is removed by the function returned
+func newTestAuthorizer(t *testing.T, email string, perms []model.AccessPermission) (*Authorizer, func()) {
+	dir, err := ioutil.TempDir("", "test-authorizer")
+	require.Nil(t, err)
+	cleanup := func() {
+		_ = os.RemoveAll(dir)
+	}
+	dsn := filepath.Join(dir, "authorization.db")
+
+	da := model.NewDataAccess("sqlite3", dsn)
+	require.Nil(t, da.Init())
+	db, err := da.Get()
+	require.Nil(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+	srv := model.Service{Name: DefaultAuthorizationService}
+	require.Nil(t, db.Create(&srv).Error)
+	usr := model.User{Email: email}
+	require.Nil(t, db.Create(&usr).Error)
+	role := model.Role{Name: "CI"}
+	require.Nil(t, db.Create(&role).Error)
+	for _, perm := range perms {
+		perm := perm
+		require.Nil(t, db.Create(&perm).Error)
+		require.Nil(t, db.Model(&role).Association("AccessPermissions").Append(perm).Error)
+	}
+	require.Nil(t, db.Model(&srv).Association("Roles").Append(role).Error)
+	require.Nil(t, db.Model(&usr).Association("Roles").Append(role).Error)
+
+	return NewAuthorizer("sqlite3", dsn, ""), cleanup
+}
+
+func TestAuthorizer_ResolvesIDAndName(t *testing.T) {
+	authorizer, cleanup := newTestAuthorizer(t, "ci@c-s.fr", []model.AccessPermission{
+		{Action: "Delete", ResourcePattern: "tenant/HostService/ci-*"},
+	})
+	defer cleanup()
+	hosts := map[string][2]string{
+		"ci-host":    {"0001", "ci-host"},
+		"0001":       {"0001", "ci-host"},
+		"other-host": {"0002", "other-host"},
+		"0002":       {"0002", "other-host"},
+	}
+	authorizer.ResolveWith(func(ctx context.Context, service string, ref string) (string, string, error) {
+		if host, ok := hosts[ref]; ok && service == "HostService" {
+			return host[0], host[1], nil
+		}
+		return "", "", scerr.NotFoundError("resource not found")
+	})
+	ctx := ContextWithTenant(ContextWithCaller(context.Background(), Caller{Identity: "ci@c-s.fr"}), "tenant")
+
+	cases := []struct {
+		ref      *pb.Reference
+		expected bool
+	}{
+		{&pb.Reference{Name: "ci-host"}, true},
+		{&pb.Reference{Id: "0001"}, true},
+		{&pb.Reference{Name: "other-host"}, false},
+		// The ID of a resource whose name is not allowed must not be allowed either
+		{&pb.Reference{Id: "0002"}, false},
+		// A resource not found is checked under the reference given
+		{&pb.Reference{Name: "ci-unknown"}, true},
+		{&pb.Reference{Id: "0003"}, false},
+	}
+	for _, c := range cases {
+		err := authorizer.authorize(ctx, "/safescale.HostService/Delete", c.ref)
+		assert.Equal(t, c.expected, err == nil, "%s", GetReference(c.ref))
+	}
+}
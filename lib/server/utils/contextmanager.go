@@ -31,6 +31,9 @@ var (
 	mutexContextManager sync.Mutex
 )
 
+// TenantMetadataKey is the gRPC metadata key carrying the name of the tenant targeted by a request
+const TenantMetadataKey = "tenant"
+
 //--------------------- CLIENT ---------------------------------
 
 // GetContext ...
@@ -78,3 +81,33 @@ func generateUUID(store bool) (string, error) {
 	}
 	return newUUID.String(), nil
 }
+
+//--------------------- SERVER ---------------------------------
+
+// TenantFromContext returns the name of the tenant targeted by the request carried by ctx, or "" if not set
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(TenantMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// ContextWithTenant returns a copy of ctx whose request targets the tenant named tenant
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	md[TenantMetadataKey] = []string{tenant}
+	return metadata.NewIncomingContext(ctx, md)
+}
@@ -18,13 +18,20 @@ package utils
 
 import (
 	"math"
+	"time"
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ruledirection"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/securitygroupproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
 )
 
 // ToPBSshConfig converts a system.SSHConfig into a SshConfig
@@ -64,9 +71,26 @@ func ToPBVolume(in *resources.Volume) *pb.Volume {
 		Name:  in.Name,
 		Size:  int32(in.Size),
 		Speed: pb.VolumeSpeed(in.Speed),
+		Tags:  toPBTags(in.Properties, volumeproperty.TagsV1),
 	}
 }
 
+// ToPBVolumeSnapshot converts a resources.VolumeSnapshot to a *pb.VolumeSnapshot
+func ToPBVolumeSnapshot(in *resources.VolumeSnapshot) *pb.VolumeSnapshot {
+	out := &pb.VolumeSnapshot{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		Volume:      &pb.Reference{Id: in.VolumeID, Name: in.VolumeName},
+		Size:        int32(in.Size),
+		State:       in.State.String(),
+	}
+	if !in.CreatedAt.IsZero() {
+		out.CreatedAt = in.CreatedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
 // ToPBVolumeAttachment converts an api.Volume to a *Volume
 func ToPBVolumeAttachment(in *resources.VolumeAttachment) *pb.VolumeAttachment {
 	return &pb.VolumeAttachment{
@@ -84,6 +108,7 @@ func ToPBVolumeInfo(volume *resources.Volume, mounts map[string]*propsv1.HostLoc
 		Name:  volume.Name,
 		Size:  int32(volume.Size),
 		Speed: pb.VolumeSpeed(volume.Speed),
+		Tags:  toPBTags(volume.Properties, volumeproperty.TagsV1),
 	}
 	if len(mounts) > 0 {
 		for k, mount := range mounts {
@@ -194,6 +219,7 @@ func ToPBHost(in *resources.Host) *pb.Host {
 		Ram:                 hostSizingV1.AllocatedSize.RAMSize,
 		State:               pb.HostState(in.LastState),
 		AttachedVolumeNames: volumes,
+		Tags:                toPBTags(in.Properties, hostproperty.TagsV1),
 	}
 }
 
@@ -282,7 +308,26 @@ func ToPBNetwork(in *resources.Network) *pb.Network {
 		SecondaryGatewayId: in.SecondaryGatewayID,
 		VirtualIp:          &pbVIP,
 		Failover:           in.SecondaryGatewayID != "",
+		Tags:               toPBTags(in.Properties, networkproperty.TagsV1),
+	}
+}
+
+// toPBTags returns the tags stored in the property key of props, nil if there is none
+func toPBTags(props *serialize.JSONProperties, key string) map[string]string {
+	if props == nil || !props.Lookup(key) {
+		return nil
 	}
+	var tags map[string]string
+	_ = props.LockForRead(key).ThenUse(func(clonable data.Clonable) error {
+		for k, v := range clonable.(*propsv1.ResourceTags).Tags {
+			if tags == nil {
+				tags = map[string]string{}
+			}
+			tags[k] = v
+		}
+		return nil
+	})
+	return tags
 }
 
 // ToPBFileList convert a list of file names from api to protocolbuffer FileList format
@@ -333,3 +378,54 @@ func ToPBVirtualIP(src resources.VirtualIP) pb.VirtualIp {
 	copy(dest.Hosts, src.Hosts)
 	return dest
 }
+
+// ToPBSecurityGroupRule converts a resources.SecurityGroupRule to a *pb.SecurityGroupRule
+func ToPBSecurityGroupRule(in resources.SecurityGroupRule) *pb.SecurityGroupRule {
+	return &pb.SecurityGroupRule{
+		Id:          in.ID,
+		Description: in.Description,
+		Direction:   pb.RuleDirection(in.Direction),
+		EtherType:   int32(in.EtherType),
+		Protocol:    in.Protocol,
+		PortFrom:    int32(in.PortFrom),
+		PortTo:      int32(in.PortTo),
+		Cidr:        in.CIDR,
+	}
+}
+
+// FromPBSecurityGroupRule converts a *pb.SecurityGroupRule to a resources.SecurityGroupRule
+func FromPBSecurityGroupRule(in *pb.SecurityGroupRule) resources.SecurityGroupRule {
+	return resources.SecurityGroupRule{
+		ID:          in.GetId(),
+		Description: in.GetDescription(),
+		Direction:   ruledirection.Enum(in.GetDirection()),
+		EtherType:   ipversion.Enum(in.GetEtherType()),
+		Protocol:    in.GetProtocol(),
+		PortFrom:    int(in.GetPortFrom()),
+		PortTo:      int(in.GetPortTo()),
+		CIDR:        in.GetCidr(),
+	}
+}
+
+// ToPBSecurityGroup converts a *resources.SecurityGroup to a *pb.SecurityGroup
+func ToPBSecurityGroup(in *resources.SecurityGroup) *pb.SecurityGroup {
+	out := &pb.SecurityGroup{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		NetworkId:   in.NetworkID,
+	}
+	for _, r := range in.Rules {
+		out.Rules = append(out.Rules, ToPBSecurityGroupRule(r))
+	}
+	if in.Properties != nil {
+		_ = in.Properties.LockForRead(securitygroupproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+			sgHostsV1 := clonable.(*propsv1.SecurityGroupHosts)
+			for id, name := range sgHostsV1.ByID {
+				out.Hosts = append(out.Hosts, &pb.Reference{Id: id, Name: name})
+			}
+			return nil
+		})
+	}
+	return out
+}
@@ -17,19 +17,196 @@
 package utils
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
-	pb "github.com/CS-SI/SafeScale/lib"
-	"google.golang.org/grpc"
 	"log"
+	"os"
 	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/CS-SI/SafeScale/lib"
 )
 
+// ClientSecurity contains the settings used by a client to connect to a secured safescaled
+type ClientSecurity struct {
+	// CAFiles contains the CA certificates used to check the certificate of safescaled; TLS is used if not empty
+	CAFiles []string
+	// CertFile and KeyFile contain the client certificate, presented when safescaled requires mutual TLS
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name used to check the certificate of safescaled
+	ServerName string
+	// Token is sent as bearer token with each call
+	Token string
+}
+
+var (
+	clientSecurity      *ClientSecurity
+	clientSecurityMutex sync.Mutex
+)
+
+// SetClientSecurity defines the security settings used by GetConnection, overriding the ones read from environment
+func SetClientSecurity(cs ClientSecurity) {
+	clientSecurityMutex.Lock()
+	defer clientSecurityMutex.Unlock()
+	clientSecurity = &cs
+}
+
+// clientConfigPaths contains the folders where the client configuration file is searched, in that order
+var clientConfigPaths = []string{"$HOME/.safescale", "$HOME/.config/safescale", "/etc/safescale"}
+
+// GetClientSecurity returns the security settings used by GetConnection.
+// If not set explicitly, they are read from the client configuration file (client.yml, client.toml or client.json in
+// $HOME/.safescale, $HOME/.config/safescale or /etc/safescale), then overridden by the environment variables
+// SAFESCALE_TLS_CA, SAFESCALE_TLS_CERT, SAFESCALE_TLS_KEY, SAFESCALE_TLS_SERVER_NAME and SAFESCALE_TOKEN
+func GetClientSecurity() ClientSecurity {
+	clientSecurityMutex.Lock()
+	defer clientSecurityMutex.Unlock()
+	if clientSecurity != nil {
+		return *clientSecurity
+	}
+
+	cs := readClientConfig()
+	if ca := os.Getenv("SAFESCALE_TLS_CA"); ca != "" {
+		cs.CAFiles = strings.Split(ca, ",")
+	}
+	for env, field := range map[string]*string{
+		"SAFESCALE_TLS_CERT":        &cs.CertFile,
+		"SAFESCALE_TLS_KEY":         &cs.KeyFile,
+		"SAFESCALE_TLS_SERVER_NAME": &cs.ServerName,
+		"SAFESCALE_TOKEN":           &cs.Token,
+	} {
+		if value := os.Getenv(env); value != "" {
+			*field = value
+		}
+	}
+	return cs
+}
+
+// readClientConfig reads the security settings in the client configuration file, if there is one:
+//
+//	tls:
+//	  ca: <CA certificate(s)>
+//	  cert: <client certificate>
+//	  key: <client key>
+//	  server_name: <name checked in the certificate of safescaled>
+//	token: <token>
+func readClientConfig() ClientSecurity {
+	v := viper.New()
+	for _, path := range clientConfigPaths {
+		v.AddConfigPath(path)
+	}
+	v.SetConfigName("client")
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			log.Printf("failed to read client configuration file: %v", err)
+		}
+		return ClientSecurity{}
+	}
+
+	cs := ClientSecurity{
+		CAFiles:    v.GetStringSlice("tls.ca"),
+		CertFile:   v.GetString("tls.cert"),
+		KeyFile:    v.GetString("tls.key"),
+		ServerName: v.GetString("tls.server_name"),
+		Token:      v.GetString("token"),
+	}
+	if len(cs.CAFiles) == 1 && strings.Contains(cs.CAFiles[0], ",") {
+		cs.CAFiles = strings.Split(cs.CAFiles[0], ",")
+	}
+	if cs.Token != "" {
+		if info, err := os.Stat(v.ConfigFileUsed()); err == nil && info.Mode().Perm()&0077 != 0 {
+			log.Printf("warning: the client configuration file '%s' contains a token and can be read by other users", v.ConfigFileUsed())
+		}
+	}
+	return cs
+}
+
+// TLSEnabled tells if the connection to safescaled has to use TLS
+func (cs ClientSecurity) TLSEnabled() bool {
+	return len(cs.CAFiles) > 0 || cs.CertFile != ""
+}
+
+// DialOptions returns the grpc.DialOption corresponding to the security settings
+func (cs ClientSecurity) DialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if cs.TLSEnabled() {
+		config := &tls.Config{ServerName: cs.ServerName}
+		if len(cs.CAFiles) > 0 {
+			pool, err := loadCertPool(cs.CAFiles...)
+			if err != nil {
+				return nil, err
+			}
+			config.RootCAs = pool
+		}
+		if cs.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(cs.CertFile, cs.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %v", err)
+			}
+			config.Certificates = []tls.Certificate{cert}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	if cs.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: cs.Token, secure: cs.TLSEnabled()}))
+	}
+	return opts, nil
+}
+
+// tokenCredentials sends a bearer token with each call
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
+
+// GetRequestMetadata returns the metadata carrying the token
+func (tc tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{authorizationMetadataKey: bearerPrefix + tc.token}, nil
+}
+
+// RequireTransportSecurity tells if the token can only be sent on a secured connection
+func (tc tokenCredentials) RequireTransportSecurity() bool {
+	return tc.secure
+}
+
+// MetadataDialOptions returns the grpc.DialOption adding the pairs of keys and values kv to the metadata of each call
+func MetadataDialOptions(kv ...string) []grpc.DialOption {
+	if len(kv) == 0 {
+		return nil
+	}
+
+	unary := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, kv...), method, req, reply, cc, opts...)
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(metadata.AppendToOutgoingContext(ctx, kv...), desc, cc, method, opts...)
+	}
+	return []grpc.DialOption{grpc.WithUnaryInterceptor(unary), grpc.WithStreamInterceptor(stream)}
+}
+
 // GetConnection returns a connection to GRPC server
-func GetConnection(host string, port int) *grpc.ClientConn {
+func GetConnection(host string, port int, extraOpts ...grpc.DialOption) *grpc.ClientConn {
 	address := fmt.Sprintf("%s:%d", host, port)
 
+	opts, err := GetClientSecurity().DialOptions()
+	if err != nil {
+		log.Fatalf("failed to prepare connection to safescaled (%s:%d): %v", host, port, err)
+	}
+	opts = append(opts, extraOpts...)
+
 	// Set up a connection to the server.
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	conn, err := grpc.Dial(address, opts...)
 	if err != nil {
 		log.Fatalf("failed to connect to safescaled (%s:%d): %v", host, port, err)
 	}
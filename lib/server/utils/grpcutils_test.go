@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClientSecurity_ConfigFileAndEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-client-config")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	content := `
+tls:
+  ca: /etc/safescale/ca.pem,/etc/safescale/other-ca.pem
+  cert: /etc/safescale/client.pem
+  key: /etc/safescale/client-key.pem
+token: from-file
+`
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "client.yml"), []byte(content), 0600))
+
+	oldPaths := clientConfigPaths
+	clientConfigPaths = []string{dir}
+	defer func() { clientConfigPaths = oldPaths }()
+	for _, env := range []string{"SAFESCALE_TLS_CA", "SAFESCALE_TLS_CERT", "SAFESCALE_TLS_KEY", "SAFESCALE_TLS_SERVER_NAME", "SAFESCALE_TOKEN"} {
+		if value, ok := os.LookupEnv(env); ok {
+			defer func(env, value string) { _ = os.Setenv(env, value) }(env, value)
+		} else {
+			defer func(env string) { _ = os.Unsetenv(env) }(env)
+		}
+		require.Nil(t, os.Unsetenv(env))
+	}
+
+	cs := GetClientSecurity()
+	assert.Equal(t, []string{"/etc/safescale/ca.pem", "/etc/safescale/other-ca.pem"}, cs.CAFiles)
+	assert.Equal(t, "/etc/safescale/client.pem", cs.CertFile)
+	assert.Equal(t, "/etc/safescale/client-key.pem", cs.KeyFile)
+	assert.Equal(t, "from-file", cs.Token)
+
+	// The environment variables override the file
+	require.Nil(t, os.Setenv("SAFESCALE_TOKEN", "from-env"))
+	require.Nil(t, os.Setenv("SAFESCALE_TLS_SERVER_NAME", "safescaled.example.com"))
+	cs = GetClientSecurity()
+	assert.Equal(t, "from-env", cs.Token)
+	assert.Equal(t, "safescaled.example.com", cs.ServerName)
+	assert.Equal(t, "/etc/safescale/client.pem", cs.CertFile)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ChainUnaryServer combines unary interceptors into one; the first one is the outermost.
+// nil interceptors are ignored.
+func ChainUnaryServer(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	var chain []grpc.UnaryServerInterceptor
+	for _, i := range interceptors {
+		if i != nil {
+			chain = append(chain, i)
+		}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(chain) - 1; i >= 0; i-- {
+			interceptor, inner := chain[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// ChainStreamServer combines stream interceptors into one; the first one is the outermost.
+// nil interceptors are ignored.
+func ChainStreamServer(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	var chain []grpc.StreamServerInterceptor
+	for _, i := range interceptors {
+		if i != nil {
+			chain = append(chain, i)
+		}
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		next := handler
+		for i := len(chain) - 1; i >= 0; i-- {
+			interceptor, inner := chain[i], next
+			next = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, inner)
+			}
+		}
+		return next(srv, ss)
+	}
+}
+
+// WrappedServerStream is a grpc.ServerStream whose context can be replaced by an interceptor
+type WrappedServerStream struct {
+	grpc.ServerStream
+	WrappedContext context.Context
+}
+
+// Context returns the replaced context
+func (w *WrappedServerStream) Context() context.Context {
+	return w.WrappedContext
+}
+
+// RequestServerStream is a grpc.ServerStream keeping the first message received from the client, that is the request
+// of a server-streaming RPC, so interceptors can use it like the request of a unary RPC
+type RequestServerStream struct {
+	grpc.ServerStream
+	// OnRequest, if not nil, is called with the request when the handler receives it; if it fails, the handler gets
+	// its error instead of the request
+	OnRequest func(req interface{}) error
+	// Request is the first message received, nil until the handler receives it
+	Request interface{}
+}
+
+// RecvMsg receives a message and keeps it if it is the first one
+func (w *RequestServerStream) RecvMsg(m interface{}) error {
+	if err := w.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if w.Request != nil {
+		return nil
+	}
+	w.Request = m
+	if w.OnRequest != nil {
+		return w.OnRequest(m)
+	}
+	return nil
+}
@@ -19,45 +19,143 @@ package utils
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// Statuses of jobs
+const (
+	// JobRunning is the status of a job not finished yet
+	JobRunning = "running"
+	// JobSucceeded is the status of a job finished without error
+	JobSucceeded = "succeeded"
+	// JobFailed is the status of a job finished with an error
+	JobFailed = "failed"
+	// JobCancelled is the status of a job stopped by JobService.Stop or by its client
+	JobCancelled = "cancelled"
+	// JobInterrupted is the status of a job still running when safescaled stopped
+	JobInterrupted = "interrupted"
 )
 
+// JobRecord describes a job, running or finished
+type JobRecord struct {
+	UUID      string
+	Command   string
+	Owner     string
+	Tenant    string
+	Status    string
+	Error     string
+	Result    string
+	StartTime time.Time
+	EndTime   time.Time // zero while the job is running
+}
+
+// JobStore keeps the history of the jobs across restarts of safescaled
+type JobStore interface {
+	// Save creates or replaces the record of a job
+	Save(JobRecord) error
+	// Get returns the record of a job, or scerr.ErrNotFound
+	Get(uuid string) (*JobRecord, error)
+	// List returns the records of the jobs kept, the most recent first
+	List() ([]JobRecord, error)
+}
+
 type jobInfo struct {
 	commandName string
 	launchTime  time.Time
+	owner       string
+	tenant      string
 	context     context.Context
 	cancelFunc  func()
+	tracking    *jobTracking
 }
 
 func (ji *jobInfo) toString() string {
 	return fmt.Sprintf("Task : %s\nCreation time : %s", ji.commandName, ji.launchTime.String())
 }
 
+func (ji *jobInfo) toRecord(uuid string) JobRecord {
+	return JobRecord{
+		UUID:      uuid,
+		Command:   ji.commandName,
+		Owner:     ji.owner,
+		Tenant:    ji.tenant,
+		Status:    JobRunning,
+		StartTime: ji.launchTime,
+	}
+}
+
+// jobTracking follows the job of a RPC whose outcome has to be kept in the JobStore
+type jobTracking struct {
+	mutex     sync.Mutex
+	uuid      string
+	cancelled bool
+}
+
+type jobTrackingKey struct{}
+
 var (
 	jobMap          = map[string]jobInfo{}
 	mutexJobManager sync.Mutex
+	jobStore        JobStore
 )
 
+// SetJobStore sets the store keeping the history of the jobs; nil keeps only the running jobs in memory
+func SetJobStore(store JobStore) {
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+	jobStore = store
+}
+
 // JobRegister ...
 func JobRegister(ctx context.Context, cancelFunc func(), command string) error {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return fmt.Errorf("no uuid in grpc metadata")
 	}
-	mutexJobManager.Lock()
-	defer mutexJobManager.Unlock()
+	uuid := md.Get("uuid")[0]
 
-	jobMap[md.Get("uuid")[0]] = jobInfo{
+	caller := CallerFromContext(ctx)
+	owner := caller.Identity
+	if owner == "" && caller.Internal {
+		owner = "safescaled"
+	}
+	info := jobInfo{
 		commandName: command,
 		launchTime:  time.Now(),
+		owner:       owner,
+		tenant:      TenantFromContext(ctx),
 		context:     ctx,
 		cancelFunc:  cancelFunc,
 	}
 
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
+	// Only the first job registered by a tracked RPC is kept in the store
+	if tracking, ok := ctx.Value(jobTrackingKey{}).(*jobTracking); ok && jobStore != nil {
+		tracking.mutex.Lock()
+		if tracking.uuid == "" {
+			tracking.uuid = uuid
+			info.tracking = tracking
+			if err := jobStore.Save(info.toRecord(uuid)); err != nil {
+				logrus.Warnf("failed to save job '%s': %v", uuid, err)
+			}
+		}
+		tracking.mutex.Unlock()
+	}
+	jobMap[uuid] = info
+
 	return nil
 }
 
@@ -66,6 +164,11 @@ func JobCancelUUID(uuid string) {
 	mutexJobManager.Lock()
 	defer mutexJobManager.Unlock()
 	if info, found := jobMap[uuid]; found {
+		if info.tracking != nil {
+			info.tracking.mutex.Lock()
+			info.tracking.cancelled = true
+			info.tracking.mutex.Unlock()
+		}
 		info.cancelFunc()
 	}
 }
@@ -90,9 +193,159 @@ func JobDeregister(ctx context.Context) {
 
 // JobList ...
 func JobList() map[string]string {
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
 	listMap := map[string]string{}
 	for uuid, info := range jobMap {
 		listMap[uuid] = info.toString()
 	}
 	return listMap
 }
+
+// JobRecords returns the running jobs and, if all is true, the finished jobs kept in the store, the most recent first
+func JobRecords(all bool) ([]JobRecord, error) {
+	mutexJobManager.Lock()
+	var records []JobRecord
+	running := map[string]bool{}
+	for uuid, info := range jobMap {
+		records = append(records, info.toRecord(uuid))
+		running[uuid] = true
+	}
+	store := jobStore
+	mutexJobManager.Unlock()
+
+	if all && store != nil {
+		stored, err := store.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range stored {
+			if !running[r.UUID] {
+				records = append(records, r)
+			}
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].StartTime.After(records[j].StartTime)
+	})
+	return records, nil
+}
+
+// JobInspect returns the record of a job, running or kept in the store
+func JobInspect(uuid string) (*JobRecord, error) {
+	mutexJobManager.Lock()
+	info, found := jobMap[uuid]
+	store := jobStore
+	mutexJobManager.Unlock()
+
+	if found {
+		record := info.toRecord(uuid)
+		return &record, nil
+	}
+	if store != nil {
+		return store.Get(uuid)
+	}
+	return nil, scerr.NotFoundError(fmt.Sprintf("no job with uuid '%s'", uuid))
+}
+
+// JobInterceptors returns the interceptors keeping in the JobStore the outcome of the jobs registered
+// by the RPCs for which track returns true (all the RPCs if track is nil).
+// They must be chained after the interceptors of ServerSecurity (identifying the caller) and of tenant resolution.
+func JobInterceptors(track func(fullMethod string) bool) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if track != nil && !track(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		tracking := &jobTracking{}
+		resp, err := handler(context.WithValue(ctx, jobTrackingKey{}, tracking), req)
+		tracking.finish(err, jobResult(resp))
+		return resp, err
+	}
+	stream := func(srv interface{}, serverStream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if track != nil && !track(info.FullMethod) {
+			return handler(srv, serverStream)
+		}
+		tracking := &jobTracking{}
+		ctx := context.WithValue(serverStream.Context(), jobTrackingKey{}, tracking)
+		err := handler(srv, &WrappedServerStream{ServerStream: serverStream, WrappedContext: ctx})
+		tracking.finish(err, "")
+		return err
+	}
+	return unary, stream
+}
+
+// InheritJobTracking returns a copy of ctx where the job registered is tracked as the one of parent,
+// for contexts detached from the context of the RPC
+func InheritJobTracking(ctx, parent context.Context) context.Context {
+	if tracking, ok := parent.Value(jobTrackingKey{}).(*jobTracking); ok {
+		return context.WithValue(ctx, jobTrackingKey{}, tracking)
+	}
+	return ctx
+}
+
+// finish saves the final status of the tracked job, if a job has been registered
+func (jt *jobTracking) finish(err error, result string) {
+	jt.mutex.Lock()
+	uuid, cancelled := jt.uuid, jt.cancelled
+	jt.mutex.Unlock()
+	if uuid == "" {
+		return
+	}
+
+	mutexJobManager.Lock()
+	store := jobStore
+	mutexJobManager.Unlock()
+	if store == nil {
+		return
+	}
+
+	record, gerr := store.Get(uuid)
+	if gerr != nil {
+		logrus.Warnf("failed to update job '%s': %v", uuid, gerr)
+		return
+	}
+	record.EndTime = time.Now()
+	record.Result = result
+	switch {
+	case err == nil:
+		record.Status = JobSucceeded
+	case cancelled || status.Code(err) == codes.Canceled:
+		record.Status = JobCancelled
+		record.Error = err.Error()
+	default:
+		record.Status = JobFailed
+		record.Error = err.Error()
+	}
+	if serr := store.Save(*record); serr != nil {
+		logrus.Warnf("failed to update job '%s': %v", uuid, serr)
+	}
+}
+
+// jobResult returns a summary of the response of a RPC, naming the resource returned if any
+func jobResult(resp interface{}) string {
+	v := reflect.ValueOf(resp)
+	if resp == nil || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return ""
+	}
+	kind := reflect.Indirect(v).Type().Name()
+	if kind == "Empty" {
+		return ""
+	}
+	name, id := "", ""
+	if r, ok := resp.(interface{ GetName() string }); ok {
+		name = r.GetName()
+	}
+	if r, ok := resp.(interface{ GetId() string }); ok {
+		id = r.GetId()
+	}
+	switch {
+	case name != "" && id != "":
+		return fmt.Sprintf("%s '%s' (%s)", kind, name, id)
+	case name != "":
+		return fmt.Sprintf("%s '%s'", kind, name)
+	case id != "":
+		return fmt.Sprintf("%s %s", kind, id)
+	}
+	return kind
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+)
+
+func init() {
+	metrics.SetRunningJobsFunc(func() int {
+		mutexJobManager.Lock()
+		defer mutexJobManager.Unlock()
+		return len(jobMap)
+	})
+}
+
+// MetricsInterceptors returns the interceptors counting the unary and streaming calls and measuring their duration.
+// They must be chained first, to measure the time spent in the other interceptors too.
+func MetricsInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		service, method := SplitMethod(info.FullMethod)
+		metrics.ObserveRPC(service, method, status.Code(err).String(), start)
+		return resp, err
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		service, method := SplitMethod(info.FullMethod)
+		metrics.ObserveRPC(service, method, status.Code(err).String(), start)
+		return err
+	}
+	return unary, stream
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
+)
+
+// Kinds of progress events
+const (
+	// ProgressPhaseStarted is sent when a phase of an operation starts
+	ProgressPhaseStarted = "phase-started"
+	// ProgressPhaseFinished is sent when a phase of an operation succeeds
+	ProgressPhaseFinished = "phase-finished"
+	// ProgressPhaseFailed is sent when a phase of an operation fails
+	ProgressPhaseFailed = "phase-failed"
+	// ProgressRetry is sent when an attempt inside a phase failed and is retried
+	ProgressRetry = "retry"
+	// ProgressSSHReady is sent when the SSH service of a host is ready
+	ProgressSSHReady = "ssh-ready"
+	// ProgressStepResult is sent with the result of a step of a feature on a host
+	ProgressStepResult = "step-result"
+)
+
+// ProgressReporter receives the progress events of an operation; it must be safe for concurrent use
+type ProgressReporter func(*pb.ProgressEvent)
+
+type progressKey struct{}
+
+// ContextWithProgress returns a copy of ctx whose progress events are sent to reporter
+func ContextWithProgress(ctx context.Context, reporter ProgressReporter) context.Context {
+	if reporter == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, reporter)
+}
+
+// ProgressFromContext returns the reporter of progress events of ctx, or nil if nobody follows the progress
+func ProgressFromContext(ctx context.Context) ProgressReporter {
+	if ctx == nil {
+		return nil
+	}
+	if reporter, ok := ctx.Value(progressKey{}).(ProgressReporter); ok {
+		return reporter
+	}
+	return nil
+}
+
+// ReportProgress sends a progress event to the reporter of ctx, if any
+func ReportProgress(ctx context.Context, kind, phase, target, message string) {
+	reporter := ProgressFromContext(ctx)
+	if reporter == nil {
+		return
+	}
+	reporter(&pb.ProgressEvent{
+		Time:    time.Now().Format(time.RFC3339Nano),
+		Kind:    kind,
+		Phase:   phase,
+		Target:  target,
+		Message: message,
+	})
+}
+
+// StartPhase reports the start of a phase of an operation on target, and returns the function
+// to call with the result of the phase to report its end
+func StartPhase(ctx context.Context, phase, target string) func(error) {
+	ReportProgress(ctx, ProgressPhaseStarted, phase, target, "")
+	return func(err error) {
+		if err != nil {
+			ReportProgress(ctx, ProgressPhaseFailed, phase, target, err.Error())
+			return
+		}
+		ReportProgress(ctx, ProgressPhaseFinished, phase, target, "")
+	}
+}
+
+// ProgressRetryNotifier returns a retry.Notify reporting the failed attempts of a phase
+func ProgressRetryNotifier(ctx context.Context, phase, target string) retry.Notify {
+	return func(t retry.Try, v verdict.Enum) {
+		if v != verdict.Retry {
+			return
+		}
+		reporter := ProgressFromContext(ctx)
+		if reporter == nil {
+			return
+		}
+		message := ""
+		if t.Err != nil {
+			message = t.Err.Error()
+		}
+		reporter(&pb.ProgressEvent{
+			Time:    time.Now().Format(time.RFC3339Nano),
+			Kind:    ProgressRetry,
+			Phase:   phase,
+			Target:  target,
+			Message: message,
+			Attempt: int32(t.Count),
+		})
+	}
+}
+
+// ProgressStream returns a ProgressReporter sending events with send, safe for concurrent use, and the function
+// returning the first error met while sending
+func ProgressStream(send func(*pb.ProgressEvent) error) (ProgressReporter, func() error) {
+	var (
+		mutex   sync.Mutex
+		sendErr error
+	)
+	reporter := func(event *pb.ProgressEvent) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if sendErr != nil {
+			return
+		}
+		if err := send(event); err != nil {
+			sendErr = fmt.Errorf("failed to send progress event: %v", err)
+		}
+	}
+	return reporter, func() error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return sendErr
+	}
+}
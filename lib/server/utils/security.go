@@ -0,0 +1,307 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationMetadataKey = "authorization"
+	apiKeyMetadataKey        = "x-api-key"
+	bearerPrefix             = "Bearer "
+)
+
+// ServerSecurity contains the settings securing the gRPC endpoint of safescaled
+type ServerSecurity struct {
+	// CertFile and KeyFile contain the certificate of safescaled; TLS is used if set
+	CertFile string
+	KeyFile  string
+	// ClientCAFile contains the CA certificates used to check client certificates; mutual TLS is required if set
+	ClientCAFile string
+	// Tokens contains the accepted bearer tokens or API keys; authentication is disabled if empty
+	Tokens []string
+	// Identities maps tokens to the identity (email) of their owner
+	Identities map[string]string
+
+	loopbackToken string
+}
+
+// Caller describes the author of a call to safescaled
+type Caller struct {
+	// Identity is the email of the caller, taken from its token or its client certificate; empty if unknown
+	Identity string
+	// Internal is true when safescaled calls itself
+	Internal bool
+}
+
+type callerKey struct{}
+
+// ContextWithCaller returns a copy of ctx carrying the caller
+func ContextWithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller identified by the interceptors of ServerSecurity
+func CallerFromContext(ctx context.Context) Caller {
+	if caller, ok := ctx.Value(callerKey{}).(Caller); ok {
+		return caller
+	}
+	return Caller{}
+}
+
+// TLSEnabled tells if safescaled has to use TLS
+func (ss ServerSecurity) TLSEnabled() bool {
+	return ss.CertFile != ""
+}
+
+// AuthEnabled tells if safescaled has to authenticate calls
+func (ss ServerSecurity) AuthEnabled() bool {
+	return len(ss.Tokens) > 0
+}
+
+// TLSConfig returns the TLS configuration of safescaled, or nil if TLS is not enabled
+func (ss ServerSecurity) TLSConfig() (*tls.Config, error) {
+	if !ss.TLSEnabled() {
+		if ss.ClientCAFile != "" {
+			return nil, fmt.Errorf("mutual TLS requires a server certificate")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(ss.CertFile, ss.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %v", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if ss.ClientCAFile != "" {
+		pool, err := loadCertPool(ss.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// TransportCredentials returns the TLS credentials of safescaled, or nil if TLS is not enabled
+func (ss ServerSecurity) TransportCredentials() (credentials.TransportCredentials, error) {
+	config, err := ss.TLSConfig()
+	if err != nil || config == nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// LoopbackClientSecurity returns the client settings safescaled has to use to call itself.
+// A token dedicated to these internal calls is generated, identifying them as internal, and added to the accepted ones.
+// With mutual TLS, safescaled presents its own certificate, which must then be signed by the client CA.
+func (ss *ServerSecurity) LoopbackClientSecurity() (ClientSecurity, error) {
+	cs := ClientSecurity{}
+
+	token, err := uuid.NewV4()
+	if err != nil {
+		return cs, err
+	}
+	cs.Token = token.String()
+	ss.loopbackToken = cs.Token
+	if ss.AuthEnabled() {
+		ss.Tokens = append(ss.Tokens, cs.Token)
+	}
+
+	if ss.TLSEnabled() {
+		cs.CAFiles = []string{ss.CertFile}
+		if ss.ClientCAFile != "" {
+			cs.CAFiles = append(cs.CAFiles, ss.ClientCAFile)
+			cs.CertFile = ss.CertFile
+			cs.KeyFile = ss.KeyFile
+		}
+		serverName, err := loopbackServerName(ss.CertFile, ss.KeyFile)
+		if err != nil {
+			return cs, err
+		}
+		cs.ServerName = serverName
+	}
+	return cs, nil
+}
+
+// Interceptors returns the interceptors authenticating unary and streaming calls if authentication is enabled,
+// and adding the Caller to their context
+func (ss ServerSecurity) Interceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	tokens := make([][]byte, 0, len(ss.Tokens))
+	for _, t := range ss.Tokens {
+		tokens = append(tokens, []byte(t))
+	}
+	identify := func(ctx context.Context) (context.Context, error) {
+		candidate := tokenFromContext(ctx)
+		if ss.AuthEnabled() {
+			if candidate == "" {
+				return nil, status.Errorf(codes.Unauthenticated, "missing authentication token")
+			}
+			accepted := false
+			for _, t := range tokens {
+				if subtle.ConstantTimeCompare([]byte(candidate), t) == 1 {
+					accepted = true
+					break
+				}
+			}
+			if !accepted {
+				return nil, status.Errorf(codes.Unauthenticated, "invalid authentication token")
+			}
+		}
+
+		caller := Caller{}
+		if candidate != "" {
+			if ss.loopbackToken != "" && subtle.ConstantTimeCompare([]byte(candidate), []byte(ss.loopbackToken)) == 1 {
+				caller.Internal = true
+			} else {
+				caller.Identity = ss.Identities[candidate]
+			}
+		}
+		if caller.Identity == "" && !caller.Internal {
+			caller.Identity = identityFromPeer(ctx)
+		}
+		return ContextWithCaller(ctx, caller), nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := identify(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, serverStream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := identify(serverStream.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &WrappedServerStream{ServerStream: serverStream, WrappedContext: ctx})
+	}
+	return unary, stream
+}
+
+// identityFromPeer returns the email, or else the common name, of the verified client certificate of the caller
+func identityFromPeer(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	if len(cert.EmailAddresses) > 0 {
+		return cert.EmailAddresses[0]
+	}
+	return cert.Subject.CommonName
+}
+
+// tokenFromContext returns the bearer token or API key found in the incoming metadata
+func tokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, v := range md.Get(authorizationMetadataKey) {
+		if len(v) > len(bearerPrefix) && strings.EqualFold(v[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(v[len(bearerPrefix):])
+		}
+	}
+	for _, v := range md.Get(apiKeyMetadataKey) {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// LoadTokens reads the tokens contained in a file, one per line, optionally followed by the identity (email) of their owner.
+// Empty lines and lines starting with '#' are ignored
+func LoadTokens(path string) ([]string, map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read tokens file '%s': %v", path, err)
+	}
+	var tokens []string
+	identities := map[string]string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		tokens = append(tokens, fields[0])
+		if len(fields) > 1 {
+			identities[fields[0]] = fields[1]
+		}
+	}
+	return tokens, identities, nil
+}
+
+// loadCertPool returns a certificate pool containing the PEM certificates of the files
+func loadCertPool(files ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file '%s': %v", f, err)
+		}
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("no valid certificate found in CA file '%s'", f)
+		}
+	}
+	return pool, nil
+}
+
+// loopbackServerName returns the name to check in the server certificate when safescaled calls itself on localhost
+func loopbackServerName(certFile, keyFile string) (string, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to load server certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse server certificate: %v", err)
+	}
+	if cert.VerifyHostname("localhost") == nil {
+		return "", nil
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0], nil
+	}
+	return cert.Subject.CommonName, nil
+}
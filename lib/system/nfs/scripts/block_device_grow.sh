@@ -0,0 +1,49 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# block_device_grow.sh
+# Grows the filesystem of a mounted block device to the size of the device
+
+{{.BashHeader}}
+
+function print_error {
+    read line file <<<$(caller)
+    echo "An error occurred in line $line of file $file:" "{"`sed "${line}q;d" "$file"`"}" >&2
+}
+trap print_error ERR
+
+DEVICE=$(readlink -f "/dev/disk/by-uuid/{{.UUID}}")
+MOUNTPOINT=$(findmnt -n -o TARGET --source "$DEVICE" | head -n 1)
+[ -z "$MOUNTPOINT" ] && echo "block device '{{.UUID}}' is not mounted" >&2 && exit 1
+
+# Some hypervisors do not notify the guest of the new size of a SCSI disk
+RESCAN=/sys/class/block/$(basename "$DEVICE")/device/rescan
+[ -w "$RESCAN" ] && echo 1 >"$RESCAN"
+
+FSTYPE=$(findmnt -n -o FSTYPE --source "$DEVICE" | head -n 1)
+case $FSTYPE in
+    xfs)
+        xfs_growfs "$MOUNTPOINT" >/dev/null || exit 1
+        ;;
+    ext2|ext3|ext4)
+        resize2fs "$DEVICE" >/dev/null 2>&1 || exit 1
+        ;;
+    *)
+        echo "growing filesystem '$FSTYPE' is not supported" >&2
+        exit 1
+        ;;
+esac
+exit 0
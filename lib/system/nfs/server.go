@@ -118,6 +118,15 @@ func (s *Server) MountBlockDevice(deviceName, mountPoint, format string, doNotFo
 	return stdout, err
 }
 
+// GrowBlockDevice grows the filesystem of a mounted local block device to the size of the device
+func (s *Server) GrowBlockDevice(volumeUUID string) error {
+	data := map[string]interface{}{
+		"UUID": volumeUUID,
+	}
+	retcode, stdout, stderr, err := executeScript(*s.SSHConfig, "block_device_grow.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to grow block device")
+}
+
 // UnmountBlockDevice unmounts a local block device on the remote system
 func (s *Server) UnmountBlockDevice(volumeUUID string) error {
 	data := map[string]interface{}{
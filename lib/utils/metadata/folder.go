@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 
@@ -37,8 +39,13 @@ type Folder struct {
 	service  iaas.Service
 	crypt    bool
 	cryptKey *crypt.Key
+	// historySize is the number of revisions kept in the history of each object (0 disables history)
+	historySize int
 }
 
+// optimisticWarning makes sure the warning about version checks without conditional writes is logged once
+var optimisticWarning sync.Once
+
 // FolderDecoderCallback is the prototype of the function that will decode data read from Metadata
 type FolderDecoderCallback func([]byte) error
 
@@ -50,9 +57,10 @@ func NewFolder(svc iaas.Service, path string) (*Folder, error) {
 	cryptKey := svc.GetMetadataKey()
 	crypto := cryptKey != nil && len(cryptKey) > 0
 	f := &Folder{
-		path:    strings.Trim(path, "/"),
-		service: svc,
-		crypt:   crypto,
+		path:        strings.Trim(path, "/"),
+		service:     svc,
+		crypt:       crypto,
+		historySize: svc.GetMetadataHistorySize(),
 	}
 	if crypto {
 		f.cryptKey = cryptKey
@@ -108,12 +116,30 @@ func (f *Folder) Search(path string, name string) error {
 	return scerr.NotFoundError(fmt.Sprintf("failed to find '%s'", fullPath))
 }
 
-// Delete removes metadata passed as parameter
+// Delete removes metadata passed as parameter, keeping its content in its history
 func (f *Folder) Delete(path string, name string) error {
-	err := f.service.GetMetadataBucket().DeleteObject(f.absolutePath(path, name))
+	return f.delete(path, name, nil)
+}
+
+// delete removes the object 'path/name', keeping in history the content 'previous' if it's known or the content read
+// just before
+func (f *Folder) delete(path string, name string, previous *storedObject) error {
+	absPath := f.absolutePath(path, name)
+	version := ""
+	if previous != nil {
+		version = previous.version
+	}
+	content, written, err := f.replaced(absPath, version, previous)
+	if err != nil {
+		// The content cannot be kept, but this must not prevent the deletion
+		log.Warnf("failed to read metadata '%s' before deleting it, no revision kept in history: %v", absPath, err)
+		content = nil
+	}
+	err = f.service.GetMetadataBucket().DeleteObject(absPath)
 	if err != nil {
 		return fmt.Errorf("failed to remove metadata in Object Storage: %s", err.Error())
 	}
+	f.archive(absPath, content, written)
 	return nil
 }
 
@@ -123,24 +149,52 @@ func (f *Folder) Delete(path string, name string) error {
 // returns true, nil if the object has been found
 // The callback function has to know how to decode it and where to store the result
 func (f *Folder) Read(path string, name string, callback FolderDecoderCallback) error {
-	err := f.Search(path, name)
+	_, err := f.ReadVersion(path, name, callback)
+	return err
+}
+
+// ReadVersion loads the content of the object stored in metadata bucket like Read, and returns the version of the object read,
+// to be passed to Write to detect concurrent changes
+func (f *Folder) ReadVersion(path string, name string, callback FolderDecoderCallback) (string, error) {
+	stored, err := f.read(path, name, callback)
 	if err != nil {
-		if _, ok := err.(scerr.ErrNotFound); ok {
-			return err
-		}
+		return "", err
+	}
+	return stored.version, nil
+}
 
-		return err
+// read loads the content of the object 'path/name' like ReadVersion, and returns the object as stored
+func (f *Folder) read(path string, name string, callback FolderDecoderCallback) (storedObject, error) {
+	err := f.Search(path, name)
+	if err != nil {
+		return storedObject{}, err
 	}
 
 	var buffer bytes.Buffer
-	_, err = f.service.GetMetadataBucket().ReadObject(f.absolutePath(path, name), &buffer, 0, 0)
+	o, err := f.service.GetMetadataBucket().ReadObject(f.absolutePath(path, name), &buffer, 0, 0)
 	if err != nil {
 		if _, ok := err.(scerr.ErrNotFound); ok {
-			return scerr.NotFoundError(fmt.Sprintf("failed to read '%s/%s' in Metadata Storage: %v", path, name, err))
+			return storedObject{}, scerr.NotFoundError(fmt.Sprintf("failed to read '%s/%s' in Metadata Storage: %v", path, name, err))
+		}
+		return storedObject{}, err
+	}
+	err = f.decode(buffer.Bytes(), path, name, callback)
+	if err != nil {
+		return storedObject{}, err
+	}
+	stored := storedObject{version: o.GetETag()}
+	if f.historySize > 0 {
+		stored.content = buffer.Bytes()
+		stored.written, err = o.GetLastUpdate()
+		if err != nil || stored.written.IsZero() {
+			stored.written = time.Now()
 		}
-		return err
 	}
-	data := buffer.Bytes()
+	return stored, nil
+}
+
+// decode decrypts if needed the content of the metadata 'path/name' read from the Object Storage, and passes it to callback
+func (f *Folder) decode(data []byte, path string, name string, callback FolderDecoderCallback) (err error) {
 	if f.crypt {
 		data, err = crypt.Decrypt(data, f.cryptKey)
 		if err != nil {
@@ -160,8 +214,56 @@ func (f *Folder) Read(path string, name string, callback FolderDecoderCallback)
 	return nil
 }
 
-// Write writes the content in Object Storage
-func (f *Folder) Write(path string, name string, content []byte) error {
+// Write writes the content in Object Storage and returns the new version of the object; the content replaced is kept in history
+// If 'version' isn't empty, the object is written only if it is still in this version (as returned by ReadVersion or
+// a previous Write); otherwise Write fails with scerr.ErrConflict. If 'version' is empty, the object is overwritten
+// whatever its content.
+// On Object Storages without conditional writes, the version is checked just before writing: a concurrent change
+// made in between is lost (a warning is logged once)
+func (f *Folder) Write(path string, name string, content []byte, version string) (string, error) {
+	stored, err := f.store(path, name, content, version, false, nil)
+	return stored.version, err
+}
+
+// Create writes the content in Object Storage only if the object doesn't exist yet, and returns the version of the
+// object; fails with scerr.ErrConflict if someone else created it
+// On Object Storages without conditional writes, the existence is checked just before writing, like in Write
+func (f *Folder) Create(path string, name string, content []byte) (string, error) {
+	stored, err := f.store(path, name, content, "", true, nil)
+	return stored.version, err
+}
+
+// store writes the content in the object 'path/name' with the preconditions of Write or Create, keeping in history
+// the content replaced ('previous' if it's known), and returns the object as stored
+func (f *Folder) store(path string, name string, content []byte, version string, create bool, previous *storedObject) (storedObject, error) {
+	var (
+		replaced []byte
+		written  time.Time
+		err      error
+	)
+	absPath := f.absolutePath(path, name)
+	if !create {
+		replaced, written, err = f.replaced(absPath, version, previous)
+		if err != nil {
+			return storedObject{}, err
+		}
+	}
+	newVersion, data, err := f.write(absPath, content, version, create)
+	if err != nil {
+		return storedObject{}, err
+	}
+	f.archive(absPath, replaced, written)
+	stored := storedObject{version: newVersion}
+	if f.historySize > 0 {
+		stored.content = data
+		stored.written = time.Now()
+	}
+	return stored, nil
+}
+
+// write encrypts the content if needed and writes it in the object 'absPath', provided it is still in version 'version'
+// or, if 'create' is true, provided it doesn't exist; returns the new version and the content as stored
+func (f *Folder) write(absPath string, content []byte, version string, create bool) (string, []byte, error) {
 	var (
 		data []byte
 		err  error
@@ -170,15 +272,73 @@ func (f *Folder) Write(path string, name string, content []byte) error {
 	if f.crypt {
 		data, err = crypt.Encrypt(content, f.cryptKey)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 	} else {
 		data = content
 	}
 
+	bucket := f.service.GetMetadataBucket()
 	source := bytes.NewBuffer(data)
-	_, err = f.service.GetMetadataBucket().WriteObject(f.absolutePath(path, name), source, int64(source.Len()), nil)
-	return err
+	conflict := scerr.ConflictError(fmt.Sprintf("metadata '%s' has been changed by someone else since it has been read", absPath))
+	if create {
+		conflict = scerr.ConflictError(fmt.Sprintf("metadata '%s' has been created by someone else", absPath))
+	}
+	if cb, ok := bucket.(objectstorage.ConditionalBucket); ok {
+		var o objectstorage.Object
+		if version == "" && !create {
+			o, err = bucket.WriteObject(absPath, source, int64(source.Len()), nil)
+		} else {
+			// an empty version makes the write conditional to the absence of the object
+			o, err = cb.WriteObjectIfMatch(absPath, source, int64(source.Len()), nil, version)
+		}
+		if err != nil {
+			if _, ok := err.(scerr.ErrDuplicate); ok {
+				return "", nil, conflict
+			}
+			return "", nil, err
+		}
+		return o.GetETag(), data, nil
+	}
+
+	// Without conditional writes, checks the version just before writing; someone else may still write in between
+	if version != "" || create {
+		optimisticWarning.Do(func() {
+			log.Warnf("the metadata bucket '%s' doesn't support conditional writes, concurrent changes of metadata may be lost",
+				bucket.GetName())
+		})
+		current, err := f.version(absPath)
+		if err != nil {
+			return "", nil, err
+		}
+		if current != version {
+			return "", nil, conflict
+		}
+	}
+	_, err = bucket.WriteObject(absPath, source, int64(source.Len()), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	newVersion, err := f.version(absPath)
+	return newVersion, data, err
+}
+
+// version returns the ETag of the object 'absPath' the same way ReadVersion does, or "" if it doesn't exist
+func (f *Folder) version(absPath string) (string, error) {
+	list, err := f.service.GetMetadataBucket().List(absPath[:strings.LastIndex(absPath, "/")], objectstorage.NoPrefix)
+	if err != nil {
+		return "", err
+	}
+	for _, item := range list {
+		if item == absPath {
+			o, err := f.service.GetMetadataBucket().GetObject(absPath)
+			if err != nil {
+				return "", err
+			}
+			return o.GetETag(), nil
+		}
+	}
+	return "", nil
 }
 
 // Browse browses the content of a specific path in Metadata and executes 'cb' on each entry
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+const (
+	// historyFolderName is the folder of the metadata bucket containing the past revisions of the metadata objects
+	historyFolderName = "history"
+	// revisionLayout is the layout of the time used as ID of a revision, sortable as a string
+	revisionLayout = "20060102T150405.000000000Z"
+)
+
+// Revision describes a past content of a metadata object, kept in its history
+type Revision struct {
+	ID   string    // ID identifies the revision in the history of the object
+	Time time.Time // Time is the time the revision had been written
+	Size int64     // Size is the size of the stored content (encrypted if metadata are encrypted)
+}
+
+// storedObject is an object as stored in the metadata bucket (encrypted if metadata are encrypted), with its version
+// and the time it has been written; the content is only kept if history is enabled
+type storedObject struct {
+	version string
+	content []byte
+	written time.Time
+}
+
+// historyPath returns the path of the folder containing the history of the object 'absPath'
+func historyPath(absPath string) string {
+	return historyFolderName + "/" + absPath
+}
+
+// replaced returns the content of the object 'absPath' about to be replaced and the time it has been written, or nil
+// if history is disabled or if the object doesn't exist
+// If 'previous' is the object in version 'version' (as read or written by an Item), its content is used; otherwise
+// the object is read from the metadata bucket
+func (f *Folder) replaced(absPath string, version string, previous *storedObject) ([]byte, time.Time, error) {
+	if f.historySize <= 0 {
+		return nil, time.Time{}, nil
+	}
+	if previous != nil && previous.content != nil && version != "" && previous.version == version {
+		return previous.content, previous.written, nil
+	}
+	return f.stored(absPath)
+}
+
+// stored returns the content of the object 'absPath' as stored (encrypted if needed) and the time it has been written,
+// or nil if the object doesn't exist
+func (f *Folder) stored(absPath string) ([]byte, time.Time, error) {
+	bucket := f.service.GetMetadataBucket()
+	list, err := bucket.List(absPath[:strings.LastIndex(absPath, "/")], objectstorage.NoPrefix)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	for _, item := range list {
+		if item == absPath {
+			var buffer bytes.Buffer
+			o, err := bucket.ReadObject(absPath, &buffer, 0, 0)
+			if err != nil {
+				return nil, time.Time{}, err
+			}
+			written, err := o.GetLastUpdate()
+			if err != nil || written.IsZero() {
+				written = time.Now()
+			}
+			return buffer.Bytes(), written, nil
+		}
+	}
+	return nil, time.Time{}, nil
+}
+
+// archive keeps 'content', written at 'written', in the history of the object 'absPath', then removes the oldest
+// revisions beyond the history size
+// Failures are only logged: losing a revision must not make fail the change of the metadata
+func (f *Folder) archive(absPath string, content []byte, written time.Time) {
+	if content == nil {
+		return
+	}
+	bucket := f.service.GetMetadataBucket()
+	revisionPath := historyPath(absPath) + "/" + written.UTC().Format(revisionLayout)
+	_, err := bucket.WriteObject(revisionPath, bytes.NewReader(content), int64(len(content)), nil)
+	if err != nil {
+		log.Errorf("failed to keep revision of metadata '%s' in history: %v", absPath, err)
+		return
+	}
+
+	names, err := f.revisionNames(absPath)
+	if err != nil {
+		log.Errorf("failed to list history of metadata '%s': %v", absPath, err)
+		return
+	}
+	for len(names) > f.historySize {
+		err = bucket.DeleteObject(names[0])
+		if err != nil {
+			log.Errorf("failed to remove revision '%s' from history: %v", names[0], err)
+		}
+		names = names[1:]
+	}
+}
+
+// revisionNames returns the names of the objects containing the revisions of the object 'absPath', from the oldest to
+// the most recent
+func (f *Folder) revisionNames(absPath string) ([]string, error) {
+	prefix := historyPath(absPath) + "/"
+	list, err := f.service.GetMetadataBucket().List(historyPath(absPath), objectstorage.NoPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range list {
+		if strings.HasPrefix(item, prefix) && !strings.Contains(item[len(prefix):], "/") {
+			names = append(names, item)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Revisions returns the revisions kept in the history of the metadata 'path/name', from the oldest to the most recent
+func (f *Folder) Revisions(path string, name string) ([]Revision, error) {
+	absPath := f.absolutePath(path, name)
+	names, err := f.revisionNames(absPath)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]Revision, 0, len(names))
+	for _, n := range names {
+		o, err := f.service.GetMetadataBucket().GetObject(n)
+		if err != nil {
+			return nil, err
+		}
+		id := n[strings.LastIndex(n, "/")+1:]
+		written, err := time.Parse(revisionLayout, id)
+		if err != nil {
+			log.Warnf("unexpected revision '%s' in history of metadata '%s'", id, absPath)
+			continue
+		}
+		revisions = append(revisions, Revision{ID: id, Time: written, Size: o.GetSize()})
+	}
+	return revisions, nil
+}
+
+// ReadRevision loads the content of the revision 'revision' of the metadata 'path/name', like Read does with
+// its current content
+func (f *Folder) ReadRevision(path string, name string, revision string, callback FolderDecoderCallback) error {
+	absPath := f.absolutePath(path, name)
+	names, err := f.revisionNames(absPath)
+	if err != nil {
+		return err
+	}
+	revisionPath := historyPath(absPath) + "/" + revision
+	found := false
+	for _, n := range names {
+		if n == revisionPath {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return scerr.NotFoundError(fmt.Sprintf("failed to find revision '%s' of metadata '%s'", revision, absPath))
+	}
+
+	var buffer bytes.Buffer
+	_, err = f.service.GetMetadataBucket().ReadObject(revisionPath, &buffer, 0, 0)
+	if err != nil {
+		return err
+	}
+	return f.decode(buffer.Bytes(), path, name, callback)
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+func readRevision(t *testing.T, folder *Folder, revision string) int {
+	c := &counter{}
+	err := folder.ReadRevision("byID", "id", revision, func(buf []byte) error {
+		return c.Deserialize(buf)
+	})
+	require.Nil(t, err)
+	return c.Value
+}
+
+func TestFolderHistory(t *testing.T) {
+	folder, err := NewFolder(bucketService{bucket: newTestBucket(t), history: 2}, "hosts")
+	require.Nil(t, err)
+
+	for value := 0; value < 3; value++ {
+		content, err := (&counter{Value: value}).Serialize()
+		require.Nil(t, err)
+		_, err = folder.Write("byID", "id", content, "")
+		require.Nil(t, err)
+	}
+
+	// Only the last 2 replaced contents are kept, from the oldest to the most recent
+	revisions, err := folder.Revisions("byID", "id")
+	require.Nil(t, err)
+	require.Len(t, revisions, 2)
+	assert.True(t, revisions[0].Time.Before(revisions[1].Time))
+	assert.Equal(t, 0, readRevision(t, folder, revisions[0].ID))
+	assert.Equal(t, 1, readRevision(t, folder, revisions[1].ID))
+
+	// The deleted content is kept too
+	require.Nil(t, folder.Delete("byID", "id"))
+	revisions, err = folder.Revisions("byID", "id")
+	require.Nil(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, readRevision(t, folder, revisions[1].ID))
+
+	// History is not seen as metadata
+	err = folder.Browse("byID", func(buf []byte) error {
+		t.Errorf("unexpected metadata '%s'", string(buf))
+		return nil
+	})
+	require.Nil(t, err)
+
+	err = folder.ReadRevision("byID", "id", "20000101T000000.000000000Z", func(buf []byte) error {
+		return nil
+	})
+	_, ok := err.(scerr.ErrNotFound)
+	assert.True(t, ok)
+}
+
+// readCountingBucket counts the objects read
+type readCountingBucket struct {
+	objectstorage.Bucket
+	reads int
+}
+
+func (b *readCountingBucket) ReadObject(name string, target io.Writer, from int64, to int64) (objectstorage.Object, error) {
+	b.reads++
+	return b.Bucket.ReadObject(name, target, from, to)
+}
+
+func TestItemHistory(t *testing.T) {
+	bucket := &readCountingBucket{Bucket: newTestBucket(t)}
+	item, err := NewItem(bucketService{bucket: bucket, history: 5}, "hosts")
+	require.Nil(t, err)
+	require.Nil(t, item.Carry(&counter{Value: 1}).WriteInto("byID", "id"))
+
+	// The content replaced is the one the Item wrote, it's kept without reading it again
+	item.Get().(*counter).Value = 2
+	require.Nil(t, item.WriteInto("byID", "id"))
+	assert.Equal(t, 0, bucket.reads)
+	revisions, err := item.folder.Revisions("byID", "id")
+	require.Nil(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, 1, readRevision(t, item.folder, revisions[0].ID))
+
+	require.Nil(t, item.DeleteFrom("byID", "id"))
+	revisions, err = item.folder.Revisions("byID", "id")
+	require.Nil(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, readRevision(t, item.folder, revisions[1].ID))
+}
+
+func TestFolderHistoryDisabled(t *testing.T) {
+	folder, err := NewFolder(bucketService{bucket: newTestBucket(t)}, "hosts")
+	require.Nil(t, err)
+
+	for value := 0; value < 2; value++ {
+		content, err := (&counter{Value: value}).Serialize()
+		require.Nil(t, err)
+		_, err = folder.Write("byID", "id", content, "")
+		require.Nil(t, err)
+	}
+	require.Nil(t, folder.Delete("byID", "id"))
+	revisions, err := folder.Revisions("byID", "id")
+	require.Nil(t, err)
+	assert.Empty(t, revisions)
+}
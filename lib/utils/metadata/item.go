@@ -31,7 +31,15 @@ type Item struct {
 	payload serialize.Serializable
 	folder  *Folder
 	written bool
-	lock    *sync.Mutex
+	// loaded tells if the item has been read from Object Storage; if not, writing an object not written yet creates it
+	loaded bool
+	lock   *sync.Mutex
+	lease  *lease
+	// versions contains the version of each object read or written, by path in the folder (with its content if
+	// history is enabled, to keep it in history when it's replaced without reading it again)
+	versions map[string]storedObject
+	// stateLock protects written, loaded and versions, the Item being possibly read and written by several goroutines
+	stateLock *sync.Mutex
 }
 
 // ItemDecoderCallback ...
@@ -45,9 +53,11 @@ func NewItem(svc iaas.Service, path string) (*Item, error) {
 	}
 
 	theItem := &Item{
-		folder:  fold,
-		payload: nil,
-		lock:    &sync.Mutex{},
+		folder:    fold,
+		payload:   nil,
+		lock:      &sync.Mutex{},
+		versions:  map[string]storedObject{},
+		stateLock: &sync.Mutex{},
 	}
 
 	return theItem, nil
@@ -70,6 +80,8 @@ func (i *Item) GetPath() string {
 
 // Written tells if the item has already been written in Object Storage
 func (i *Item) Written() bool {
+	i.stateLock.Lock()
+	defer i.stateLock.Unlock()
 	return i.written
 }
 
@@ -82,7 +94,11 @@ func (i *Item) Carry(data serialize.Serializable) *Item {
 // Reset ...
 func (i *Item) Reset() *Item {
 	i.payload = nil
+	i.stateLock.Lock()
+	defer i.stateLock.Unlock()
 	i.written = false
+	i.loaded = false
+	i.versions = map[string]storedObject{}
 	return i
 }
 
@@ -109,7 +125,14 @@ func (i *Item) DeleteFrom(path string, name string) error {
 		return err
 	}
 
-	err = i.folder.Delete(path, name)
+	i.stateLock.Lock()
+	previous, known := i.versions[i.folder.absolutePath(path, name)]
+	i.stateLock.Unlock()
+	if known {
+		err = i.folder.delete(path, name, &previous)
+	} else {
+		err = i.folder.delete(path, name, nil)
+	}
 	if err != nil {
 		return err
 	}
@@ -125,7 +148,7 @@ func (i *Item) Delete(name string) error {
 // ReadFrom reads metadata of item from Object Storage in a subfolder
 func (i *Item) ReadFrom(path string, name string, callback ItemDecoderCallback) error {
 	var data serialize.Serializable
-	err := i.folder.Read(path, name, func(buf []byte) error {
+	stored, err := i.folder.read(path, name, func(buf []byte) error {
 		var err error
 		data, err = callback(buf)
 		return err
@@ -134,7 +157,11 @@ func (i *Item) ReadFrom(path string, name string, callback ItemDecoderCallback)
 		return err
 	}
 	i.payload = data
+	i.stateLock.Lock()
+	defer i.stateLock.Unlock()
 	i.written = true
+	i.loaded = true
+	i.versions[i.folder.absolutePath(path, name)] = stored
 	return nil
 }
 
@@ -144,6 +171,8 @@ func (i *Item) Read(name string, callback ItemDecoderCallback) error {
 }
 
 // WriteInto saves the content of Item in a subfolder to the Object Storage
+// Fails with scerr.ErrConflict if the object has been changed by someone else since this Item read or wrote it, or,
+// if this Item has never been read, if the object has been created by someone else
 func (i *Item) WriteInto(path string, name string) error {
 	if i == nil {
 		return scerr.InvalidInstanceError()
@@ -155,11 +184,27 @@ func (i *Item) WriteInto(path string, name string) error {
 	if err != nil {
 		return err
 	}
-	err = i.folder.Write(path, name, data)
+	absPath := i.folder.absolutePath(path, name)
+	i.stateLock.Lock()
+	previous, known := i.versions[absPath]
+	loaded := i.loaded
+	i.stateLock.Unlock()
+	var stored storedObject
+	switch {
+	case known:
+		stored, err = i.folder.store(path, name, data, previous.version, false, &previous)
+	case loaded:
+		stored, err = i.folder.store(path, name, data, "", false, nil)
+	default:
+		stored, err = i.folder.store(path, name, data, "", true, nil)
+	}
 	if err != nil {
 		return err
 	}
+	i.stateLock.Lock()
+	defer i.stateLock.Unlock()
 	i.written = true
+	i.versions[absPath] = stored
 	return nil
 }
 
@@ -185,12 +230,31 @@ func (i *Item) Browse(callback func([]byte) error) error {
 	return i.BrowseInto(".", callback)
 }
 
-// Acquire waits until the lock is available, then locks the metadata
-func (i *Item) Acquire() {
+// Acquire waits until the lock of the metadata 'name' is available, then locks the metadata
+// The lock is shared with every process using the same Object Storage (see lease)
+func (i *Item) Acquire(name string) error {
+	if name == "" {
+		return scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
 	i.lock.Lock()
+	l, err := newLease(i.GetBucket(), i.GetPath(), name)
+	if err == nil {
+		err = l.acquire()
+	}
+	if err != nil {
+		i.lock.Unlock()
+		return err
+	}
+	i.lease = l
+	return nil
 }
 
 // Release unlocks the metadata
 func (i *Item) Release() {
+	if i.lease != nil {
+		i.lease.release()
+		i.lease = nil
+	}
 	i.lock.Unlock()
 }
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// bucketService is a Service providing only a metadata bucket
+type bucketService struct {
+	iaas.Service
+	bucket  objectstorage.Bucket
+	history int
+}
+
+func (s bucketService) GetMetadataKey() *crypt.Key {
+	return nil
+}
+
+func (s bucketService) GetMetadataHistorySize() int {
+	return s.history
+}
+
+func (s bucketService) GetMetadataBucket() objectstorage.Bucket {
+	return s.bucket
+}
+
+type counter struct {
+	Value int `json:"value"`
+}
+
+func (c *counter) Serialize() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *counter) Deserialize(buf []byte) error {
+	return json.Unmarshal(buf, c)
+}
+
+func readCounter(t *testing.T, item *Item) *counter {
+	err := item.Read("counter", func(buf []byte) (serialize.Serializable, error) {
+		c := &counter{}
+		return c, c.Deserialize(buf)
+	})
+	require.Nil(t, err)
+	return item.Get().(*counter)
+}
+
+func testItemConflict(t *testing.T, bucket objectstorage.Bucket) {
+	svc := bucketService{bucket: bucket}
+	first, err := NewItem(svc, "clusters")
+	require.Nil(t, err)
+	require.Nil(t, first.Carry(&counter{}).Write("counter"))
+
+	second, err := NewItem(svc, "clusters")
+	require.Nil(t, err)
+	readCounter(t, second).Value++
+	readCounter(t, first).Value += 10
+
+	require.Nil(t, second.Write("counter"))
+	err = first.Write("counter")
+	_, ok := err.(scerr.ErrConflict)
+	assert.True(t, ok)
+
+	// Once reloaded, the update is applied on the latest version
+	readCounter(t, first).Value += 10
+	require.Nil(t, first.Write("counter"))
+	// first wrote the latest version, it can write again
+	require.Nil(t, first.Write("counter"))
+	assert.Equal(t, 11, readCounter(t, second).Value)
+}
+
+func TestItem_Conflict(t *testing.T) {
+	testItemConflict(t, newTestBucket(t))
+}
+
+func TestItem_ConflictWithoutConditionalWrites(t *testing.T) {
+	testItemConflict(t, plainBucket{newTestBucket(t)})
+}
+
+func testItemCreateConflict(t *testing.T, bucket objectstorage.Bucket) {
+	svc := bucketService{bucket: bucket}
+	first, err := NewItem(svc, "clusters")
+	require.Nil(t, err)
+	require.Nil(t, first.Carry(&counter{Value: 1}).Write("counter"))
+
+	// An Item that has never been read creates the object, it doesn't overwrite the one created in the meantime
+	second, err := NewItem(svc, "clusters")
+	require.Nil(t, err)
+	err = second.Carry(&counter{Value: 2}).Write("counter")
+	_, ok := err.(scerr.ErrConflict)
+	assert.True(t, ok)
+	assert.Equal(t, 1, readCounter(t, second).Value)
+
+	// Once read, the object is replaced
+	second.Carry(&counter{Value: 2})
+	require.Nil(t, second.Write("counter"))
+	assert.Equal(t, 2, readCounter(t, first).Value)
+}
+
+func TestItem_CreateConflict(t *testing.T) {
+	testItemCreateConflict(t, newTestBucket(t))
+}
+
+func TestItem_CreateConflictWithoutConditionalWrites(t *testing.T) {
+	testItemCreateConflict(t, plainBucket{newTestBucket(t)})
+}
+
+// Run with -race to check the state of the Item is protected
+func TestItem_ConcurrentWrites(t *testing.T) {
+	item, err := NewItem(bucketService{bucket: newTestBucket(t)}, "hosts")
+	require.Nil(t, err)
+	item.Carry(&counter{Value: 1})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			errs <- item.WriteInto("byID", name)
+			_ = item.Written()
+		}(fmt.Sprintf("host-%d", i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+	assert.True(t, item.Written())
+	assert.Len(t, item.versions, 10)
+}
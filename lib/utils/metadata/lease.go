@@ -0,0 +1,333 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// locksFolderName is the folder of the metadata bucket containing the lock objects
+	locksFolderName = "locks"
+)
+
+var (
+	// leaseDuration is the time a lock stays valid without being renewed by its owner
+	leaseDuration = temporal.GetTimeoutFromEnv("SAFESCALE_METADATA_LEASE_DURATION", 30*time.Second)
+	// leaseTimeout is the maximum time spent waiting for a lock held by someone else
+	leaseTimeout = temporal.GetTimeoutFromEnv("SAFESCALE_METADATA_LOCK_TIMEOUT", 2*time.Minute)
+	// leaseRetryDelay is the delay between two attempts to take a lock
+	leaseRetryDelay = 500 * time.Millisecond
+	// leaseSettleDelay is the time left to concurrent writers before checking who got a lock, on backends without conditional writes
+	leaseSettleDelay = temporal.GetTimeoutFromEnv("SAFESCALE_METADATA_LOCK_SETTLE_DELAY", 500*time.Millisecond)
+)
+
+// clock gives the time to the leases and makes them wait
+type clock interface {
+	Now() time.Time
+	Sleep(time.Duration)
+}
+
+// systemClock is the clock of the system
+type systemClock struct{}
+
+// Now returns the current time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep waits for the duration d
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// bestEffortWarning makes sure the warning about locks without conditional writes is logged once
+var bestEffortWarning sync.Once
+
+// leaseRecord is the content of a lock object
+type leaseRecord struct {
+	Owner    string    `json:"owner"`
+	Acquired time.Time `json:"acquired"`
+	Renewed  time.Time `json:"renewed"`
+	Expires  time.Time `json:"expires"`
+}
+
+// lease is a lock on a metadata object, stored in the metadata bucket so it is shared by every safescaled and CLI using it
+type lease struct {
+	bucket   objectstorage.Bucket
+	path     string
+	owner    string
+	clock    clock
+	acquired time.Time
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// newLease returns a lease on the metadata object 'name' in folder 'path'
+func newLease(bucket objectstorage.Bucket, path, name string) (*lease, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lock owner: %v", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &lease{
+		bucket: bucket,
+		path:   strings.Join([]string{locksFolderName, strings.Trim(path, "/"), name}, "/"),
+		owner:  fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), id.String()),
+		clock:  systemClock{},
+	}, nil
+}
+
+// record returns the content of the lock object for a lease renewed at 'now'
+func (l *lease) record(now time.Time) leaseRecord {
+	return leaseRecord{
+		Owner:    l.owner,
+		Acquired: l.acquired,
+		Renewed:  now,
+		Expires:  now.Add(leaseDuration),
+	}
+}
+
+// read returns the current content of the lock object and its ETag, or nil if nobody holds the lock
+func (l *lease) read() (*leaseRecord, string, error) {
+	list, err := l.bucket.List(l.path[:strings.LastIndex(l.path, "/")], objectstorage.NoPrefix)
+	if err != nil {
+		return nil, "", err
+	}
+	found := false
+	for _, item := range list {
+		if item == l.path {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, "", nil
+	}
+
+	var buffer bytes.Buffer
+	o, err := l.bucket.ReadObject(l.path, &buffer, 0, 0)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			// released in the meantime
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	record := &leaseRecord{}
+	err = json.Unmarshal(buffer.Bytes(), record)
+	if err != nil {
+		// A lock object that cannot be decoded has no expiry, so it is handled as a stale lock
+		log.Warnf("metadata lock '%s' is unreadable: %v", l.path, err)
+		record = &leaseRecord{Owner: "<unreadable>"}
+	}
+	return record, o.GetETag(), nil
+}
+
+// write stores 'record' in the lock object if it is still in version 'etag' ("" meaning there was no lock);
+// returns false if someone else changed the lock object in the meantime
+func (l *lease) write(record leaseRecord, etag string) (bool, error) {
+	content, err := json.Marshal(record)
+	if err != nil {
+		return false, err
+	}
+
+	if cb, ok := l.bucket.(objectstorage.ConditionalBucket); ok {
+		_, err = cb.WriteObjectIfMatch(l.path, bytes.NewReader(content), int64(len(content)), nil, etag)
+		if err != nil {
+			if _, ok := err.(scerr.ErrDuplicate); ok {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	// Without conditional writes, the last writer wins: write, leave concurrent writers the time to do the same, then check;
+	// two writers slower than the settle delay may both believe they hold the lock
+	bestEffortWarning.Do(func() {
+		log.Warnf("the metadata bucket '%s' doesn't support conditional writes, metadata locks are best-effort and concurrent changes may be lost",
+			l.bucket.GetName())
+	})
+	_, err = l.bucket.WriteObject(l.path, bytes.NewReader(content), int64(len(content)), nil)
+	if err != nil {
+		return false, err
+	}
+	l.clock.Sleep(leaseSettleDelay)
+	current, _, err := l.read()
+	if err != nil {
+		return false, err
+	}
+	return current != nil && current.Owner == l.owner, nil
+}
+
+// attempt tries once to take the lock if it is free or stale; returns the record of the lock if someone else holds it
+func (l *lease) attempt() (bool, *leaseRecord, error) {
+	current, etag, err := l.read()
+	if err != nil {
+		return false, nil, err
+	}
+	now := l.clock.Now()
+	switch {
+	case current == nil:
+		l.acquired = now
+		taken, err := l.write(l.record(now), "")
+		return taken, nil, err
+	case now.After(current.Expires):
+		log.Warnf("metadata lock '%s' held by '%s' expired at %s without being released, taking it over",
+			l.path, current.Owner, current.Expires.Format(time.RFC3339))
+		l.acquired = now
+		taken, err := l.write(l.record(now), etag)
+		return taken, nil, err
+	}
+	return false, current, nil
+}
+
+// acquire waits until the lock is free or stale, takes it, then keeps it alive until release
+func (l *lease) acquire() error {
+	var (
+		holder  string
+		lastErr error
+	)
+	deadline := l.clock.Now().Add(leaseTimeout)
+	for {
+		taken, current, err := l.attempt()
+		if taken {
+			log.Debugf("metadata lock '%s' acquired by '%s'", l.path, l.owner)
+			l.stop = make(chan struct{})
+			l.done = make(chan struct{})
+			go l.keepAlive()
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+			log.Debugf("failed to take metadata lock '%s': %v", l.path, err)
+		}
+		if current != nil && current.Owner != holder {
+			holder = current.Owner
+			log.Infof("metadata lock '%s' is held by '%s' since %s (lease expires at %s), waiting",
+				l.path, current.Owner, current.Acquired.Format(time.RFC3339), current.Expires.Format(time.RFC3339))
+		}
+
+		if l.clock.Now().After(deadline) {
+			if lastErr == nil && holder != "" {
+				return scerr.TimeoutError(fmt.Sprintf("failed to acquire metadata lock '%s', still held by '%s'", l.path, holder), leaseTimeout, nil)
+			}
+			return scerr.TimeoutError(fmt.Sprintf("failed to acquire metadata lock '%s'", l.path), leaseTimeout, lastErr)
+		}
+		l.clock.Sleep(leaseRetryDelay)
+	}
+}
+
+// renew extends the lease if the lock is still owned; returns the record of the lock if it is not renewed
+func (l *lease) renew() (bool, *leaseRecord, error) {
+	current, etag, err := l.read()
+	if err != nil {
+		return false, nil, err
+	}
+	if current == nil || current.Owner != l.owner {
+		return false, current, nil
+	}
+	renewed, err := l.write(l.record(l.clock.Now()), etag)
+	if err != nil || renewed {
+		return renewed, nil, err
+	}
+	// Changed by someone else between the read and the write
+	return false, current, nil
+}
+
+// keepAlive renews the lease regularly until it is released or taken over
+func (l *lease) keepAlive() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			renewed, current, err := l.renew()
+			if err != nil {
+				// Keeps trying until the lease expires, the next renewal may succeed
+				log.Errorf("failed to renew metadata lock '%s': %v", l.path, err)
+				continue
+			}
+			if renewed {
+				continue
+			}
+			holder := "nobody"
+			if current != nil {
+				holder = current.Owner
+				if holder == l.owner {
+					holder = "someone else"
+				}
+			}
+			log.Errorf("metadata lock '%s' owned by '%s' has been taken over by '%s', concurrent changes of the metadata may be lost",
+				l.path, l.owner, holder)
+			return
+		}
+	}
+}
+
+// release stops the renewal of the lease and deletes the lock object if it is still owned
+func (l *lease) release() {
+	close(l.stop)
+	<-l.done
+
+	current, etag, err := l.read()
+	if err != nil {
+		log.Errorf("failed to release metadata lock '%s': %v", l.path, err)
+		return
+	}
+	if current == nil || current.Owner != l.owner {
+		holder := "nobody"
+		if current != nil {
+			holder = current.Owner
+		}
+		log.Warnf("metadata lock '%s' acquired by '%s' at %s is now held by '%s', not releasing it",
+			l.path, l.owner, l.acquired.Format(time.RFC3339), holder)
+		return
+	}
+
+	if cb, ok := l.bucket.(objectstorage.ConditionalBucket); ok {
+		err = cb.DeleteObjectIfMatch(l.path, etag)
+	} else {
+		err = l.bucket.DeleteObject(l.path)
+	}
+	if err != nil {
+		log.Errorf("failed to release metadata lock '%s': %v", l.path, err)
+		return
+	}
+	log.Debugf("metadata lock '%s' released by '%s' after %s", l.path, l.owner, l.clock.Now().Sub(l.acquired))
+}
@@ -0,0 +1,286 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// plainBucket hides the conditional writes of the memory bucket, like the Object Storages without conditional writes
+type plainBucket struct {
+	objectstorage.Bucket
+}
+
+// fakeClock is a clock whose time only moves when someone waits
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// leaseDelays sets the delays of the leases for a test, and returns the function restoring them
+func leaseDelays(duration, timeout time.Duration) func() {
+	previousDuration, previousTimeout := leaseDuration, leaseTimeout
+	leaseDuration, leaseTimeout = duration, timeout
+	return func() {
+		leaseDuration, leaseTimeout = previousDuration, previousTimeout
+	}
+}
+
+func newTestBucket(t *testing.T) objectstorage.Bucket {
+	bucket, err := objectstorage.NewMemoryLocation().CreateBucket("metadata")
+	require.Nil(t, err)
+	return bucket
+}
+
+func newTestLease(t *testing.T, bucket objectstorage.Bucket, clock clock, path, name string) *lease {
+	l, err := newLease(bucket, path, name)
+	require.Nil(t, err)
+	l.clock = clock
+	return l
+}
+
+// writeRecord writes the lock object of 'l' as someone else would
+func writeRecord(t *testing.T, bucket objectstorage.Bucket, l *lease, record leaseRecord) {
+	content, err := json.Marshal(record)
+	require.Nil(t, err)
+	_, err = bucket.WriteObject(l.path, bytes.NewReader(content), int64(len(content)), nil)
+	require.Nil(t, err)
+}
+
+func testLeaseContention(t *testing.T, bucket objectstorage.Bucket) {
+	// The lease of first is not renewed by the fake clock, so the timeout is shorter than the lease
+	defer leaseDelays(time.Minute, 10*time.Second)()
+	clock := newFakeClock()
+	first := newTestLease(t, bucket, clock, "hosts", "id")
+	second := newTestLease(t, bucket, clock, "hosts", "id")
+	require.NotEqual(t, first.owner, second.owner)
+
+	require.Nil(t, first.acquire())
+	record, _, err := first.read()
+	require.Nil(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, first.owner, record.Owner)
+
+	taken, current, err := second.attempt()
+	require.Nil(t, err)
+	assert.False(t, taken)
+	require.NotNil(t, current)
+	assert.Equal(t, first.owner, current.Owner)
+
+	start := clock.Now()
+	err = second.acquire()
+	_, ok := err.(scerr.ErrTimeout)
+	require.True(t, ok, fmt.Sprintf("unexpected error: %v", err))
+	assert.Contains(t, err.Error(), first.owner)
+	assert.True(t, clock.Now().Sub(start) >= leaseTimeout)
+
+	first.release()
+	require.Nil(t, second.acquire())
+	second.release()
+
+	names, err := bucket.List(locksFolderName, objectstorage.NoPrefix)
+	require.Nil(t, err)
+	assert.Empty(t, names)
+}
+
+func TestLease_Contention(t *testing.T) {
+	testLeaseContention(t, newTestBucket(t))
+}
+
+func TestLease_ContentionWithoutConditionalWrites(t *testing.T) {
+	testLeaseContention(t, plainBucket{newTestBucket(t)})
+}
+
+func TestLease_ExpiredWhileWaiting(t *testing.T) {
+	defer leaseDelays(time.Minute, 2*time.Minute)()
+	bucket := newTestBucket(t)
+	clock := newFakeClock()
+	first := newTestLease(t, bucket, clock, "volumes", "id")
+	second := newTestLease(t, bucket, clock, "volumes", "id")
+
+	// first stops renewing its lease (the fake clock doesn't move its renewal), second takes the lock over once it expired
+	require.Nil(t, first.acquire())
+	start := clock.Now()
+	require.Nil(t, second.acquire())
+	assert.True(t, clock.Now().Sub(start) > leaseDuration)
+
+	record, _, err := second.read()
+	require.Nil(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, second.owner, record.Owner)
+
+	// first must leave the lock of second in place
+	first.release()
+	record, _, err = second.read()
+	require.Nil(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, second.owner, record.Owner)
+	second.release()
+}
+
+func TestLease_StaleTakeover(t *testing.T) {
+	bucket := newTestBucket(t)
+	clock := newFakeClock()
+	l := newTestLease(t, bucket, clock, "clusters", "mycluster")
+
+	// Lock left by a process that died without releasing it
+	writeRecord(t, bucket, l, leaseRecord{
+		Owner:    "crashed",
+		Acquired: clock.Now().Add(-time.Hour),
+		Renewed:  clock.Now().Add(-time.Hour),
+		Expires:  clock.Now().Add(-time.Minute),
+	})
+	assert.Equal(t, "locks/clusters/mycluster", l.path)
+
+	require.Nil(t, l.acquire())
+	record, _, err := l.read()
+	require.Nil(t, err)
+	assert.Equal(t, l.owner, record.Owner)
+	l.release()
+
+	record, _, err = l.read()
+	require.Nil(t, err)
+	assert.Nil(t, record)
+}
+
+func TestLease_ConcurrentTakeover(t *testing.T) {
+	bucket := newTestBucket(t)
+	clock := newFakeClock()
+	first := newTestLease(t, bucket, clock, "shares", "id")
+	second := newTestLease(t, bucket, clock, "shares", "id")
+	writeRecord(t, bucket, first, leaseRecord{Owner: "crashed", Expires: clock.Now().Add(-time.Minute)})
+
+	// Both find the same stale lock; only the first conditional write succeeds
+	_, firstETag, err := first.read()
+	require.Nil(t, err)
+	_, secondETag, err := second.read()
+	require.Nil(t, err)
+	require.Equal(t, firstETag, secondETag)
+
+	written, err := first.write(first.record(clock.Now()), firstETag)
+	require.Nil(t, err)
+	assert.True(t, written)
+	written, err = second.write(second.record(clock.Now()), secondETag)
+	require.Nil(t, err)
+	assert.False(t, written)
+
+	record, _, err := second.read()
+	require.Nil(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, first.owner, record.Owner)
+
+	// Creating a lock that exists already fails the same way
+	written, err = second.write(second.record(clock.Now()), "")
+	require.Nil(t, err)
+	assert.False(t, written)
+}
+
+func TestLease_ConcurrentTakeoverWithoutConditionalWrites(t *testing.T) {
+	bucket := plainBucket{newTestBucket(t)}
+	clock := newFakeClock()
+	first := newTestLease(t, bucket, clock, "shares", "id")
+	second := newTestLease(t, bucket, clock, "shares", "id")
+	writeRecord(t, bucket, first, leaseRecord{Owner: "crashed", Expires: clock.Now().Add(-time.Minute)})
+
+	_, etag, err := first.read()
+	require.Nil(t, err)
+
+	// Without conditional writes the last writer wins; first finds out at its next renewal
+	start := clock.Now()
+	written, err := first.write(first.record(clock.Now()), etag)
+	require.Nil(t, err)
+	assert.True(t, written)
+	assert.Equal(t, leaseSettleDelay, clock.Now().Sub(start))
+	written, err = second.write(second.record(clock.Now()), etag)
+	require.Nil(t, err)
+	assert.True(t, written)
+
+	renewed, current, err := first.renew()
+	require.Nil(t, err)
+	assert.False(t, renewed)
+	require.NotNil(t, current)
+	assert.Equal(t, second.owner, current.Owner)
+}
+
+func TestLease_Renew(t *testing.T) {
+	bucket := newTestBucket(t)
+	clock := newFakeClock()
+	l := newTestLease(t, bucket, clock, "networks", "id")
+	require.Nil(t, l.acquire())
+	defer l.release()
+
+	clock.Sleep(10 * time.Second)
+	renewed, _, err := l.renew()
+	require.Nil(t, err)
+	assert.True(t, renewed)
+	record, _, err := l.read()
+	require.Nil(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, clock.Now(), record.Renewed.UTC())
+	assert.Equal(t, clock.Now().Add(leaseDuration), record.Expires.UTC())
+
+	// Someone else considered the lock stale and took it over: the lease is not renewed anymore
+	writeRecord(t, bucket, l, leaseRecord{Owner: "other", Expires: clock.Now().Add(time.Hour)})
+	renewed, current, err := l.renew()
+	require.Nil(t, err)
+	assert.False(t, renewed)
+	require.NotNil(t, current)
+	assert.Equal(t, "other", current.Owner)
+}
+
+func TestLease_TakenOver(t *testing.T) {
+	bucket := newTestBucket(t)
+	clock := newFakeClock()
+	l := newTestLease(t, bucket, clock, "networks", "id")
+	require.Nil(t, l.acquire())
+
+	// Someone else considered the lock stale and took it over: release must leave its lock in place
+	writeRecord(t, bucket, l, leaseRecord{Owner: "other", Expires: clock.Now().Add(time.Hour)})
+	l.release()
+
+	record, _, err := l.read()
+	require.Nil(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "other", record.Owner)
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics defines the Prometheus metrics of SafeScale and the helpers updating them
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "safescale"
+
+var (
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "requests_total",
+		Help:      "Number of RPCs handled by safescaled, by service, method and status code",
+	}, []string{"service", "method", "code"})
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "duration_seconds",
+		Help:      "Duration of the RPCs handled by safescaled, by service and method",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800},
+	}, []string{"service", "method"})
+
+	providerCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "calls_total",
+		Help:      "Number of calls to the providers, by provider, tenant and call",
+	}, []string{"provider", "tenant", "call"})
+	providerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "errors_total",
+		Help:      "Number of failed calls to the providers, by provider, tenant and call",
+	}, []string{"provider", "tenant", "call"})
+	providerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "call_duration_seconds",
+		Help:      "Duration of the calls to the providers, by provider, tenant and call",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"provider", "tenant", "call"})
+
+	providerCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "cache_hits_total",
+		Help:      "Number of calls to the providers answered by the cache, by provider, tenant and call",
+	}, []string{"provider", "tenant", "call"})
+	providerCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provider",
+		Name:      "cache_misses_total",
+		Help:      "Number of cacheable calls to the providers not found in the cache, by provider, tenant and call",
+	}, []string{"provider", "tenant", "call"})
+
+	retryAttempts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "retry",
+		Name:      "attempts_total",
+		Help:      "Number of new attempts decided by the retry loops",
+	})
+
+	sshDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ssh",
+		Name:      "command_duration_seconds",
+		Help:      "Duration of the commands run on hosts through SSH",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800},
+	})
+
+	managedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "managed_resources",
+		Help:      "Number of resources managed by SafeScale, by tenant and kind (host, network, cluster)",
+	}, []string{"tenant", "kind"})
+
+	runningJobsMutex sync.Mutex
+	runningJobsFunc  func() int
+	runningJobs      = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "running",
+		Help:      "Number of jobs running in safescaled",
+	}, func() float64 {
+		runningJobsMutex.Lock()
+		defer runningJobsMutex.Unlock()
+		if runningJobsFunc == nil {
+			return 0
+		}
+		return float64(runningJobsFunc())
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		rpcRequests, rpcDuration,
+		providerCalls, providerErrors, providerDuration,
+		providerCacheHits, providerCacheMisses,
+		retryAttempts,
+		sshDuration,
+		managedResources,
+		runningJobs,
+	)
+}
+
+// Handler returns the HTTP handler exposing the metrics to Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveRPC records an RPC handled by safescaled, started at start and ended with the status code
+func ObserveRPC(service, method, code string, start time.Time) {
+	rpcRequests.WithLabelValues(service, method, code).Inc()
+	rpcDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+}
+
+// ObserveProviderCall records a call to a provider started at start, failed if err is not nil
+func ObserveProviderCall(provider, tenant, call string, start time.Time, err error) {
+	providerCalls.WithLabelValues(provider, tenant, call).Inc()
+	providerDuration.WithLabelValues(provider, tenant, call).Observe(time.Since(start).Seconds())
+	if err != nil {
+		providerErrors.WithLabelValues(provider, tenant, call).Inc()
+	}
+}
+
+// ObserveProviderCache records a cacheable call to a provider, answered by the cache if hit
+func ObserveProviderCache(provider, tenant, call string, hit bool) {
+	if hit {
+		providerCacheHits.WithLabelValues(provider, tenant, call).Inc()
+	} else {
+		providerCacheMisses.WithLabelValues(provider, tenant, call).Inc()
+	}
+}
+
+// CountRetry records a new attempt decided by a retry loop
+func CountRetry() {
+	retryAttempts.Inc()
+}
+
+// ObserveSSHCommand records a command run through SSH started at start
+func ObserveSSHCommand(start time.Time) {
+	sshDuration.Observe(time.Since(start).Seconds())
+}
+
+// SetManagedResources sets the number of resources of a kind managed in a tenant
+func SetManagedResources(tenant, kind string, count int) {
+	managedResources.WithLabelValues(tenant, kind).Set(float64(count))
+}
+
+// SetRunningJobsFunc sets the function counting the running jobs
+func SetRunningJobsFunc(f func() int) {
+	runningJobsMutex.Lock()
+	defer runningJobsMutex.Unlock()
+	runningJobsFunc = f
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 )
@@ -370,6 +371,7 @@ func (a action) loop() error {
 			return retryErr
 		default:
 			// Retry is wanted, so blocks the loop the amount of time needed
+			metrics.CountRetry()
 			if a.Officer != nil {
 				a.Officer.Block(try)
 			}
@@ -450,6 +452,7 @@ func (a action) loopWithTimeout(timeout time.Duration) error {
 			return retryErr
 		default:
 			// Retry is wanted, so blocks the loop the amount of time needed
+			metrics.CountRetry()
 			if a.Officer != nil {
 				go func() {
 					a.Officer.Block(try)
@@ -488,6 +488,28 @@ func OverloadError(msg string) ErrOverload {
 	}
 }
 
+// ErrConflict is used when something has been changed by someone else since it has been read
+type ErrConflict struct {
+	ErrCore
+}
+
+// AddConsequence adds an error 'err' to the list of consequences
+func (e ErrConflict) AddConsequence(err error) error {
+	e.ErrCore = e.ErrCore.Reset(e.ErrCore.AddConsequence(err))
+	return e
+}
+
+// ConflictError creates a ErrConflict error
+func ConflictError(msg string) ErrConflict {
+	return ErrConflict{
+		ErrCore: ErrCore{
+			Message:      msg,
+			cause:        nil,
+			consequences: []error{},
+		},
+	}
+}
+
 // ErrNotImplemented ...
 type ErrNotImplemented struct {
 	ErrCore